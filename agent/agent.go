@@ -0,0 +1,527 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agent implements a plan–act–observe loop on top of the Gemini
+// Interactions API.
+//
+// An [Agent] sends the input to the model together with its [Tool]
+// declarations, runs the function calls the model requests, sends the results
+// back as the next turn of the interaction, and repeats until the model answers
+// without calling a tool or a stop condition is reached. [Agent.Run] returns a
+// [Trace] with every step of the loop.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/plar/genai"
+)
+
+// defaultMaxSteps is the step limit used when Config.MaxSteps is zero.
+const defaultMaxSteps = 10
+
+// Tool is a function that the model can call during a run.
+type Tool struct {
+	// Required. The name the model uses to call the tool.
+	Name string
+	// Optional. Describes what the tool does and when to use it.
+	Description string
+	// Optional. The JSON schema of the tool arguments, e.g. a map[string]any or
+	// a *genai.Schema.
+	Parameters any
+	// Required. Runs the tool with the arguments chosen by the model. The
+	// returned value is sent back to the model as the function result; it must
+	// be JSON serializable. A returned error is sent to the model as a failed
+	// result so that it can recover.
+	Func func(ctx context.Context, args map[string]any) (any, error)
+	// Optional. Restricts what the tool may do. Overrides Config.ToolPolicy.
+	Policy *Policy
+}
+
+func (t *Tool) declaration() *genai.InteractionTool {
+	return &genai.InteractionTool{
+		Type:        "function",
+		Name:        t.Name,
+		Description: t.Description,
+		Parameters:  t.Parameters,
+	}
+}
+
+// Budget limits the resources a run may use. Zero values mean no limit.
+type Budget struct {
+	// Optional. The maximum number of tokens, as reported by the interaction
+	// usage, summed over all steps.
+	MaxTotalTokens int
+	// Optional. The maximum number of tool calls over all steps.
+	MaxToolCalls int
+	// Optional. The maximum wall-clock duration of the run. The run is also
+	// bounded by the context deadline.
+	MaxDuration time.Duration
+}
+
+// Config configures an Agent.
+type Config struct {
+	// Optional. The name of the agent. Required for agents in a [Team], where
+	// it identifies the agent in handoffs and traces.
+	Name string
+	// Optional. Describes what the agent is good at. In a [Team], it tells the
+	// other agents when to hand off to this agent.
+	Description string
+	// The model that plans the steps, e.g. "gemini-2.5-flash". Required unless
+	// Agent is set or the client has a default model.
+	Model string
+	// Optional. The name of a server-side agent, e.g. "deep-research", that
+	// runs the steps instead of Model. See [genai.Interaction.Agent].
+	Agent string
+	// Optional. The configuration of the server-side agent.
+	AgentConfig any
+	// Optional. The system instruction sent with every step.
+	SystemInstruction string
+	// Optional. The tools the model can call.
+	Tools []*Tool
+	// Optional. The policy of the tools that have no Policy of their own.
+	ToolPolicy *Policy
+	// Optional. Generation parameters sent with every step.
+	GenerationConfig *genai.InteractionGenerationConfig
+	// Optional. The maximum number of model calls in a run. Defaults to 10.
+	MaxSteps int
+	// Optional. Resource limits of a run.
+	Budget Budget
+	// Optional. Called after each step; if it returns true, the run stops with
+	// StopReasonCondition.
+	StopWhen func(step *Step) bool
+	// Optional. The memory recalled at the start of a run and added to the
+	// system instruction. The input text and the final output of each run that
+	// does not fail are added to it as turn records. See [genai.Memory].
+	Memory genai.Memory
+}
+
+// StopReason describes why a run stopped.
+type StopReason string
+
+const (
+	// StopReasonCompleted means the model answered without calling a tool.
+	StopReasonCompleted StopReason = "completed"
+	// StopReasonMaxSteps means Config.MaxSteps model calls were made.
+	StopReasonMaxSteps StopReason = "max_steps"
+	// StopReasonBudget means a limit of Config.Budget was reached.
+	StopReasonBudget StopReason = "budget"
+	// StopReasonCondition means Config.StopWhen returned true.
+	StopReasonCondition StopReason = "condition"
+	// StopReasonError means the run failed; see the error returned by Run.
+	StopReasonError StopReason = "error"
+	// StopReasonHandoff means the model handed off to another agent of a
+	// [Team]. Team.Run continues with that agent, so it never returns it.
+	StopReasonHandoff StopReason = "handoff"
+	// StopReasonMaxHandoffs means a [Team] run reached TeamConfig.MaxHandoffs.
+	StopReasonMaxHandoffs StopReason = "max_handoffs"
+)
+
+// ToolCall is a function call requested by the model and its result.
+type ToolCall struct {
+	// ID is the call ID assigned by the model.
+	ID string `json:"id,omitempty"`
+	// Name is the name of the called tool.
+	Name string `json:"name"`
+	// Arguments are the arguments chosen by the model.
+	Arguments map[string]any `json:"arguments,omitempty"`
+	// Result is the value returned by the tool.
+	Result any `json:"result,omitempty"`
+	// Err is the error returned by the tool, or the reason it could not run.
+	// It is encoded as the "error" string in JSON.
+	Err error `json:"-"`
+	// Start is when the tool started.
+	Start time.Time `json:"start"`
+	// Duration is how long the tool ran.
+	Duration time.Duration `json:"duration"`
+}
+
+// Step is one plan–act–observe iteration: a model call and the tool calls it
+// requested.
+type Step struct {
+	// Number is the 1-based index of the step in the run.
+	Number int `json:"number"`
+	// Agent is the name of the agent that ran the step.
+	Agent string `json:"agent,omitempty"`
+	// Interaction is the model response of the step.
+	Interaction *genai.Interaction `json:"interaction,omitempty"`
+	// Text is the text output of the model in this step.
+	Text string `json:"text,omitempty"`
+	// ToolCalls are the tool calls requested in this step, with their results.
+	ToolCalls []*ToolCall `json:"toolCalls,omitempty"`
+	// TotalTokens is the token usage of the step.
+	TotalTokens int `json:"totalTokens,omitempty"`
+	// Start is when the step started.
+	Start time.Time `json:"start"`
+	// Duration is how long the step took, including the tool calls.
+	Duration time.Duration `json:"duration"`
+}
+
+// Trace is the record of a run. It can be encoded as JSON, e.g. with
+// [Trace.WriteJSON], or exported as OpenTelemetry spans with [Trace.Spans] and
+// [Trace.WriteOTLP].
+type Trace struct {
+	// ID is a random identifier of the run, a 32-digit hex string. It is used
+	// as the trace ID of the exported spans.
+	ID string `json:"id"`
+	// Steps are the steps of the run, in order.
+	Steps []*Step `json:"steps"`
+	// Output is the text output of the last step.
+	Output string `json:"output,omitempty"`
+	// StopReason is why the run stopped.
+	StopReason StopReason `json:"stopReason"`
+	// TotalTokens is the token usage summed over all steps.
+	TotalTokens int `json:"totalTokens,omitempty"`
+	// ToolCalls is the number of tool calls over all steps.
+	ToolCalls int `json:"toolCalls,omitempty"`
+	// Handoffs are the handoffs between the agents of a [Team] run, in order.
+	Handoffs []*Handoff `json:"handoffs,omitempty"`
+	// State is the state passed between the agents of a [Team] run, merged
+	// over all handoffs.
+	State map[string]any `json:"state,omitempty"`
+	// Start is when the run started.
+	Start time.Time `json:"start"`
+	// Duration is how long the run took.
+	Duration time.Duration `json:"duration"`
+}
+
+// LastInteraction returns the model response of the last step, or nil if no
+// step was made.
+func (t *Trace) LastInteraction() *genai.Interaction {
+	if t == nil || len(t.Steps) == 0 {
+		return nil
+	}
+	return t.Steps[len(t.Steps)-1].Interaction
+}
+
+// Agent runs a plan–act–observe loop with a model and a set of tools.
+// An Agent is safe for concurrent use; each Run is independent.
+type Agent struct {
+	interactions *genai.Interactions
+	config       Config
+	tools        map[string]*Tool
+}
+
+// New creates an Agent that calls the model through client.Interactions.
+func New(client *genai.Client, config *Config) (*Agent, error) {
+	if client == nil {
+		return nil, errors.New("agent.New: client is required")
+	}
+	if config == nil {
+		config = &Config{}
+	}
+	if config.Model != "" && config.Agent != "" {
+		return nil, errors.New("agent.New: model and agent are mutually exclusive")
+	}
+	if config.Model == "" && config.Agent == "" && client.ClientConfig().DefaultModel == "" {
+		return nil, errors.New("agent.New: model or agent is required")
+	}
+	tools := make(map[string]*Tool, len(config.Tools))
+	for _, t := range config.Tools {
+		if t == nil || t.Name == "" {
+			return nil, errors.New("agent.New: tool name is required")
+		}
+		if t.Func == nil {
+			return nil, fmt.Errorf("agent.New: tool %q has no Func", t.Name)
+		}
+		if _, ok := tools[t.Name]; ok {
+			return nil, fmt.Errorf("agent.New: duplicate tool %q", t.Name)
+		}
+		tools[t.Name] = t
+	}
+	return &Agent{interactions: client.Interactions, config: *config, tools: tools}, nil
+}
+
+// Run runs the loop for input, which can be any Interaction input such as a
+// string or []*genai.InteractionContent, and returns the trace of the run.
+//
+// A failing tool does not stop the run; its error is reported to the model.
+// If a model call fails, Run returns the trace up to that point together with
+// the error, and the trace's StopReason is StopReasonError.
+func (a *Agent) Run(ctx context.Context, input any) (*Trace, error) {
+	return a.run(ctx, input, &runState{})
+}
+
+// runState carries the context of a run, e.g. within a Team.
+type runState struct {
+	// recalled is the memory recalled for the input.
+	recalled string
+	// previousID is the interaction that the run continues, if any.
+	previousID string
+	// handoffTools are the handoff tools the model can call, by tool name.
+	handoffTools map[string]*Agent
+	// firstStep is the number of the first step, minus one.
+	firstStep int
+	// handoff is set when the model called a handoff tool.
+	handoff *Handoff
+}
+
+func (a *Agent) run(ctx context.Context, input any, rs *runState) (*Trace, error) {
+	start := time.Now()
+	trace := &Trace{ID: newTraceID(), Start: start}
+	defer func() { trace.Duration = time.Since(start) }()
+
+	maxSteps := a.config.MaxSteps
+	if maxSteps <= 0 {
+		maxSteps = defaultMaxSteps
+	}
+	if a.config.Budget.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.config.Budget.MaxDuration)
+		defer cancel()
+	}
+
+	query := inputText(input)
+	if a.config.Memory != nil {
+		recalled, err := genai.RecallMemory(ctx, a.config.Memory, query, genai.DefaultMemoryRecallLimit)
+		if err != nil {
+			trace.StopReason = StopReasonError
+			return trace, fmt.Errorf("agent: %w", err)
+		}
+		rs.recalled = recalled
+	}
+
+	previousID := rs.previousID
+	for n := 1; ; n++ {
+		step, err := a.step(ctx, rs.firstStep+n, input, previousID, rs)
+		if step != nil {
+			trace.Steps = append(trace.Steps, step)
+			trace.Output = step.Text
+			trace.TotalTokens += step.TotalTokens
+			trace.ToolCalls += len(step.ToolCalls)
+		}
+		if err != nil {
+			if a.config.Budget.MaxDuration > 0 && errors.Is(err, context.DeadlineExceeded) && time.Since(start) >= a.config.Budget.MaxDuration {
+				trace.StopReason = StopReasonBudget
+				return trace, nil
+			}
+			trace.StopReason = StopReasonError
+			return trace, err
+		}
+
+		switch {
+		case rs.handoff != nil:
+			trace.StopReason = StopReasonHandoff
+		case len(step.ToolCalls) == 0:
+			trace.StopReason = StopReasonCompleted
+		case a.config.StopWhen != nil && a.config.StopWhen(step):
+			trace.StopReason = StopReasonCondition
+		case a.overBudget(trace):
+			trace.StopReason = StopReasonBudget
+		case n >= maxSteps:
+			trace.StopReason = StopReasonMaxSteps
+		}
+		if trace.StopReason != "" {
+			a.remember(ctx, query, trace)
+			return trace, nil
+		}
+		input = functionResults(step.ToolCalls)
+		previousID = step.Interaction.ID
+	}
+}
+
+func (a *Agent) overBudget(trace *Trace) bool {
+	b := a.config.Budget
+	return (b.MaxTotalTokens > 0 && trace.TotalTokens >= b.MaxTotalTokens) ||
+		(b.MaxToolCalls > 0 && trace.ToolCalls >= b.MaxToolCalls)
+}
+
+// step makes one model call and runs the tools it requests. The first call
+// of a handoff tool is recorded in rs instead of being run; the other tools
+// of the step still run. Further handoff calls in the same step are not
+// followed and are reported as failed tool calls, so that every call of the
+// step has a result.
+func (a *Agent) step(ctx context.Context, n int, input any, previousID string, rs *runState) (*Step, error) {
+	start := time.Now()
+	resp, err := a.interactions.Create(ctx, a.request(input, previousID, rs), nil)
+	if err != nil {
+		return nil, fmt.Errorf("agent: step %d: %w", n, err)
+	}
+	step := &Step{Number: n, Agent: a.config.Name, Interaction: resp, Text: outputText(resp), Start: start}
+	if resp.Usage != nil {
+		step.TotalTokens = resp.Usage.TotalTokens
+	}
+	var handoffCall *genai.InteractionContent
+	for _, out := range resp.Outputs {
+		if out == nil || out.Type != "function_call" {
+			continue
+		}
+		if to, ok := rs.handoffTools[out.Name]; ok {
+			handoffCall = out
+			rs.handoff = newHandoff(a, to, out)
+			break
+		}
+	}
+	for _, out := range resp.Outputs {
+		if out == nil || out.Type != "function_call" || out == handoffCall {
+			continue
+		}
+		if _, ok := rs.handoffTools[out.Name]; ok {
+			step.ToolCalls = append(step.ToolCalls, &ToolCall{
+				ID:    out.ID,
+				Name:  out.Name,
+				Start: time.Now(),
+				Err:   fmt.Errorf("agent: not transferred, the step already hands off to %q", rs.handoff.To),
+			})
+			continue
+		}
+		step.ToolCalls = append(step.ToolCalls, a.callTool(ctx, out))
+	}
+	step.Duration = time.Since(start)
+	return step, nil
+}
+
+// remember adds the input and final output of a run to the memory.
+func (a *Agent) remember(ctx context.Context, query string, trace *Trace) {
+	if a.config.Memory == nil {
+		return
+	}
+	var records []*genai.MemoryRecord
+	if query != "" {
+		records = append(records, genai.NewTurnRecord(genai.RoleUser, query))
+	}
+	if trace.Output != "" {
+		records = append(records, genai.NewTurnRecord(genai.RoleModel, trace.Output))
+	}
+	if len(records) == 0 {
+		return
+	}
+	if err := a.config.Memory.Add(ctx, records...); err != nil {
+		log.Printf("Warning: agent: failed to add the run to memory: %v", err)
+	}
+}
+
+func (a *Agent) request(input any, previousID string, rs *runState) *genai.Interaction {
+	instruction := a.config.SystemInstruction
+	if rs.recalled != "" {
+		instruction = strings.TrimSpace(instruction + "\n\n" + rs.recalled)
+	}
+	req := &genai.Interaction{
+		Model:                 a.config.Model,
+		Agent:                 a.config.Agent,
+		AgentConfig:           a.config.AgentConfig,
+		Input:                 input,
+		SystemInstruction:     instruction,
+		GenerationConfig:      a.config.GenerationConfig,
+		PreviousInteractionID: previousID,
+	}
+	for _, t := range a.config.Tools {
+		req.Tools = append(req.Tools, t.declaration())
+	}
+	for _, name := range sortedKeys(rs.handoffTools) {
+		req.Tools = append(req.Tools, handoffDeclaration(name, rs.handoffTools[name]))
+	}
+	return req
+}
+
+// callTool runs the tool requested by a function_call output.
+func (a *Agent) callTool(ctx context.Context, call *genai.InteractionContent) *ToolCall {
+	tc := &ToolCall{ID: call.ID, Name: call.Name, Start: time.Now()}
+	args, err := toolArguments(call.Arguments)
+	if err != nil {
+		tc.Err = err
+		return tc
+	}
+	tc.Arguments = args
+	tool, ok := a.tools[call.Name]
+	if !ok {
+		tc.Err = fmt.Errorf("unknown tool %q", call.Name)
+		return tc
+	}
+	policy := tool.Policy
+	if policy == nil {
+		policy = a.config.ToolPolicy
+	}
+	tc.Result, tc.Err = runTool(ctx, tool, policy, args)
+	tc.Duration = time.Since(tc.Start)
+	return tc
+}
+
+// toolArguments converts function call arguments to a map.
+func toolArguments(arguments any) (map[string]any, error) {
+	switch args := arguments.(type) {
+	case nil:
+		return map[string]any{}, nil
+	case map[string]any:
+		return args, nil
+	case string:
+		// Some models return the arguments as a JSON string.
+		m := map[string]any{}
+		if err := json.Unmarshal([]byte(args), &m); err != nil {
+			return nil, fmt.Errorf("invalid tool arguments: %w", err)
+		}
+		return m, nil
+	default:
+		b, err := json.Marshal(args)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool arguments: %w", err)
+		}
+		m := map[string]any{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("invalid tool arguments: %w", err)
+		}
+		return m, nil
+	}
+}
+
+// functionResults returns the input of the next step for the tool calls.
+func functionResults(calls []*ToolCall) []*genai.InteractionContent {
+	results := make([]*genai.InteractionContent, 0, len(calls))
+	for _, tc := range calls {
+		result := &genai.InteractionContent{Type: "function_result", CallID: tc.ID, Name: tc.Name, Result: tc.Result}
+		if tc.Err != nil {
+			result.Result = map[string]any{"error": tc.Err.Error()}
+			result.IsError = true
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// inputText returns the text of a run input, which is a string or
+// Interaction contents.
+func inputText(input any) string {
+	switch in := input.(type) {
+	case string:
+		return in
+	case *genai.InteractionContent:
+		return inputText([]*genai.InteractionContent{in})
+	case []*genai.InteractionContent:
+		var texts []string
+		for _, c := range in {
+			if c != nil && c.Type == "text" && c.Text != "" {
+				texts = append(texts, c.Text)
+			}
+		}
+		return strings.Join(texts, "\n")
+	}
+	return ""
+}
+
+// outputText returns the concatenated text outputs of an interaction.
+func outputText(i *genai.Interaction) string {
+	var sb strings.Builder
+	for _, out := range i.Outputs {
+		if out != nil && out.Type == "text" {
+			sb.WriteString(out.Text)
+		}
+	}
+	return sb.String()
+}
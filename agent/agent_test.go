@@ -0,0 +1,262 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/plar/genai"
+)
+
+// fakeServer replies to Interactions.Create calls with the scripted responses
+// in order and records the requests.
+type fakeServer struct {
+	t         *testing.T
+	mu        sync.Mutex
+	responses []*genai.Interaction
+	requests  []map[string]any
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var req map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.t.Errorf("invalid request body: %v", err)
+	}
+	s.requests = append(s.requests, req)
+	if len(s.responses) == 0 {
+		http.Error(w, `{"error": {"code": 500, "message": "no more responses"}}`, http.StatusInternalServerError)
+		return
+	}
+	resp := s.responses[0]
+	s.responses = s.responses[1:]
+	json.NewEncoder(w).Encode(resp)
+}
+
+func newTestClient(t *testing.T, responses ...*genai.Interaction) (*genai.Client, *fakeServer) {
+	t.Helper()
+	fs := &fakeServer{t: t, responses: responses}
+	ts := httptest.NewServer(fs)
+	t.Cleanup(ts.Close)
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      "test-api-key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: ts.URL, APIVersion: "v1beta"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, fs
+}
+
+func functionCall(id, name string, args map[string]any) *genai.InteractionContent {
+	return &genai.InteractionContent{Type: "function_call", ID: id, Name: name, Arguments: args}
+}
+
+func textOutput(id, text string, tokens int) *genai.Interaction {
+	return &genai.Interaction{
+		ID:      id,
+		Status:  "completed",
+		Outputs: []*genai.InteractionContent{{Type: "text", Text: text}},
+		Usage:   &genai.InteractionUsage{TotalTokens: tokens},
+	}
+}
+
+var weatherTool = &Tool{
+	Name:        "get_weather",
+	Description: "Returns the weather for a city.",
+	Parameters: map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"city": map[string]any{"type": "string"}},
+	},
+	Func: func(ctx context.Context, args map[string]any) (any, error) {
+		if args["city"] == "Atlantis" {
+			return nil, errors.New("city not found")
+		}
+		return map[string]any{"forecast": "sunny in " + args["city"].(string)}, nil
+	},
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	client, fs := newTestClient(t,
+		&genai.Interaction{
+			ID:     "step-1",
+			Status: "requires_action",
+			Outputs: []*genai.InteractionContent{
+				functionCall("call-1", "get_weather", map[string]any{"city": "Paris"}),
+				functionCall("call-2", "get_weather", map[string]any{"city": "Atlantis"}),
+			},
+			Usage: &genai.InteractionUsage{TotalTokens: 10},
+		},
+		textOutput("step-2", "It is sunny in Paris.", 5),
+	)
+	a, err := New(client, &Config{Model: "gemini-2.5-flash", SystemInstruction: "Be brief.", Tools: []*Tool{weatherTool}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trace, err := a.Run(ctx, "What is the weather in Paris and Atlantis?")
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if trace.StopReason != StopReasonCompleted {
+		t.Errorf("StopReason = %q, want %q", trace.StopReason, StopReasonCompleted)
+	}
+	if trace.Output != "It is sunny in Paris." {
+		t.Errorf("Output = %q, want the final answer", trace.Output)
+	}
+	if len(trace.Steps) != 2 || trace.TotalTokens != 15 || trace.ToolCalls != 2 {
+		t.Fatalf("got %d steps, %d tokens and %d tool calls, want 2, 15 and 2", len(trace.Steps), trace.TotalTokens, trace.ToolCalls)
+	}
+	calls := trace.Steps[0].ToolCalls
+	if diff := cmp.Diff(map[string]any{"forecast": "sunny in Paris"}, calls[0].Result); diff != "" {
+		t.Errorf("tool result mismatch (-want +got):\n%s", diff)
+	}
+	if calls[1].Err == nil || calls[1].Err.Error() != "city not found" {
+		t.Errorf("tool error = %v, want city not found", calls[1].Err)
+	}
+
+	// The second request continues the interaction with the function results.
+	if len(fs.requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(fs.requests))
+	}
+	first, second := fs.requests[0], fs.requests[1]
+	if first["systemInstruction"] != "Be brief." || first["tools"] == nil {
+		t.Errorf("first request = %v, want system instruction and tools", first)
+	}
+	if second["previousInteractionId"] != "step-1" {
+		t.Errorf("previousInteractionId = %v, want step-1", second["previousInteractionId"])
+	}
+	wantInput := []any{
+		map[string]any{"type": "function_result", "callId": "call-1", "name": "get_weather", "result": map[string]any{"forecast": "sunny in Paris"}},
+		map[string]any{"type": "function_result", "callId": "call-2", "name": "get_weather", "result": map[string]any{"error": "city not found"}, "isError": true},
+	}
+	if diff := cmp.Diff(wantInput, second["input"]); diff != "" {
+		t.Errorf("second request input mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunStopConditions(t *testing.T) {
+	ctx := context.Background()
+	loop := func(id string) *genai.Interaction {
+		return &genai.Interaction{
+			ID:      id,
+			Outputs: []*genai.InteractionContent{functionCall("c", "get_weather", map[string]any{"city": "Paris"})},
+			Usage:   &genai.InteractionUsage{TotalTokens: 100},
+		}
+	}
+	tests := []struct {
+		name      string
+		config    Config
+		want      StopReason
+		wantSteps int
+	}{
+		{name: "max steps", config: Config{MaxSteps: 2}, want: StopReasonMaxSteps, wantSteps: 2},
+		{name: "token budget", config: Config{Budget: Budget{MaxTotalTokens: 250}}, want: StopReasonBudget, wantSteps: 3},
+		{name: "tool call budget", config: Config{Budget: Budget{MaxToolCalls: 1}}, want: StopReasonBudget, wantSteps: 1},
+		{
+			name:      "stop condition",
+			config:    Config{StopWhen: func(s *Step) bool { return s.Number == 2 }},
+			want:      StopReasonCondition,
+			wantSteps: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, loop("1"), loop("2"), loop("3"), loop("4"), loop("5"))
+			tt.config.Model = "gemini-2.5-flash"
+			tt.config.Tools = []*Tool{weatherTool}
+			a, err := New(client, &tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			trace, err := a.Run(ctx, "loop")
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if trace.StopReason != tt.want || len(trace.Steps) != tt.wantSteps {
+				t.Errorf("Run() stopped with %q after %d steps, want %q after %d", trace.StopReason, len(trace.Steps), tt.want, tt.wantSteps)
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newTestClient(t, &genai.Interaction{
+		ID:      "1",
+		Outputs: []*genai.InteractionContent{functionCall("c", "missing_tool", nil)},
+	})
+	a, err := New(client, &Config{Model: "gemini-2.5-flash"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace, err := a.Run(ctx, "hi")
+	if err == nil || !strings.Contains(err.Error(), "step 2") {
+		t.Fatalf("Run() error = %v, want a step 2 failure", err)
+	}
+	if trace.StopReason != StopReasonError || len(trace.Steps) != 1 {
+		t.Errorf("trace = %+v, want one step and StopReasonError", trace)
+	}
+	if tc := trace.Steps[0].ToolCalls[0]; tc.Err == nil || !strings.Contains(tc.Err.Error(), "unknown tool") {
+		t.Errorf("tool call error = %v, want unknown tool", tc.Err)
+	}
+
+	for _, config := range []*Config{
+		{},
+		{Model: "m", Tools: []*Tool{{Name: "t"}}},
+		{Model: "m", Tools: []*Tool{weatherTool, weatherTool}},
+	} {
+		if _, err := New(client, config); err == nil {
+			t.Errorf("New(%+v) succeeded, want error", config)
+		}
+	}
+}
+
+func TestRunMemory(t *testing.T) {
+	ctx := context.Background()
+	client, fs := newTestClient(t, textOutput("1", "Noted, your name is Ana.", 1), textOutput("2", "You are Ana.", 1))
+	memory := genai.NewTurnBuffer(10, nil)
+	a, err := New(client, &Config{Model: "gemini-2.5-flash", SystemInstruction: "Be brief.", Memory: memory})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range []string{"My name is Ana.", "Who am I?"} {
+		if _, err := a.Run(ctx, input); err != nil {
+			t.Fatalf("Run(%q) failed: %v", input, err)
+		}
+	}
+	if got := fs.requests[0]["systemInstruction"]; got != "Be brief." {
+		t.Errorf("first system instruction = %q, want no recalled memory", got)
+	}
+	want := "Be brief.\n\nRelevant memory from earlier conversations:\n- user: My name is Ana.\n- model: Noted, your name is Ana."
+	if got := fs.requests[1]["systemInstruction"]; got != want {
+		t.Errorf("second system instruction = %q, want %q", got, want)
+	}
+	records, _ := memory.Search(ctx, "", 0)
+	if len(records) != 4 {
+		t.Errorf("memory has %d records, want 4 turns", len(records))
+	}
+}
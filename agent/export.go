@@ -0,0 +1,285 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// instrumentationScope names the exported spans' instrumentation scope.
+const instrumentationScope = "github.com/plar/genai/agent"
+
+func newTraceID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// MarshalJSON encodes the ToolCall with its error as a string.
+func (tc *ToolCall) MarshalJSON() ([]byte, error) {
+	type Alias ToolCall
+	aux := struct {
+		*Alias
+		Error string `json:"error,omitempty"`
+	}{Alias: (*Alias)(tc)}
+	if tc.Err != nil {
+		aux.Error = tc.Err.Error()
+	}
+	return json.Marshal(aux)
+}
+
+// UnmarshalJSON decodes a ToolCall encoded by MarshalJSON. The error, if any,
+// is restored as a plain error with the same message.
+func (tc *ToolCall) UnmarshalJSON(data []byte) error {
+	type Alias ToolCall
+	aux := struct {
+		*Alias
+		Error string `json:"error,omitempty"`
+	}{Alias: (*Alias)(tc)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if aux.Error != "" {
+		tc.Err = errors.New(aux.Error)
+	}
+	return nil
+}
+
+// WriteJSON writes the trace as an indented JSON document, for debugging and
+// auditing runs after the fact. Use [ReadTrace] to read it back.
+func (t *Trace) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(t)
+}
+
+// ReadTrace reads a trace written by [Trace.WriteJSON].
+func ReadTrace(r io.Reader) (*Trace, error) {
+	t := &Trace{}
+	if err := json.NewDecoder(r).Decode(t); err != nil {
+		return nil, fmt.Errorf("agent: invalid trace: %w", err)
+	}
+	return t, nil
+}
+
+// SpanKind is the kind of an exported span, as defined by OpenTelemetry.
+type SpanKind int
+
+const (
+	// SpanKindInternal is an operation within the application, e.g. a
+	// tool call.
+	SpanKindInternal SpanKind = 1
+	// SpanKindClient is a call to a remote service, e.g. a model call.
+	SpanKindClient SpanKind = 3
+)
+
+// Span is a run, step or tool call of a trace in the OpenTelemetry span data
+// model. Attribute names follow the OpenTelemetry semantic conventions for
+// generative AI where they exist.
+type Span struct {
+	// TraceID is the ID of the trace, a 32-digit hex string.
+	TraceID string
+	// SpanID is the ID of the span, a 16-digit hex string.
+	SpanID string
+	// ParentSpanID is the ID of the parent span, or empty for the root span.
+	ParentSpanID string
+	// Name is the name of the span, e.g. "execute_tool get_weather".
+	Name string
+	// Kind is the kind of the span.
+	Kind SpanKind
+	// StartTime is when the operation started.
+	StartTime time.Time
+	// EndTime is when the operation ended.
+	EndTime time.Time
+	// Attributes describe the operation. Values are strings, ints or bools.
+	Attributes map[string]any
+	// Error is the error of the operation, or empty if it succeeded.
+	Error string
+}
+
+// Spans returns the trace as OpenTelemetry spans: one "invoke_agent" root span
+// for the run, a "chat" span for each step, and an "execute_tool" span for
+// each tool call, as a child of its step. Span IDs are derived from the trace
+// ID, so repeated calls return the same IDs.
+func (t *Trace) Spans() []*Span {
+	traceID := t.ID
+	if traceID == "" {
+		traceID = newTraceID()
+	}
+	root := &Span{
+		TraceID:   traceID,
+		SpanID:    spanID(traceID, "run"),
+		Name:      "invoke_agent",
+		Kind:      SpanKindInternal,
+		StartTime: t.Start,
+		EndTime:   t.Start.Add(t.Duration),
+		Attributes: map[string]any{
+			"gen_ai.operation.name":     "invoke_agent",
+			"gen_ai.usage.total_tokens": t.TotalTokens,
+			"agent.stop_reason":         string(t.StopReason),
+			"agent.tool_calls":          t.ToolCalls,
+			"agent.handoffs":            len(t.Handoffs),
+		},
+	}
+	if t.StopReason == StopReasonError {
+		root.Error = "the run failed"
+	}
+	spans := []*Span{root}
+	for _, step := range t.Steps {
+		s := &Span{
+			TraceID:      traceID,
+			SpanID:       spanID(traceID, "step", step.Number),
+			ParentSpanID: root.SpanID,
+			Name:         "chat",
+			Kind:         SpanKindClient,
+			StartTime:    step.Start,
+			EndTime:      step.Start.Add(step.Duration),
+			Attributes: map[string]any{
+				"gen_ai.operation.name":     "chat",
+				"gen_ai.usage.total_tokens": step.TotalTokens,
+				"agent.step":                step.Number,
+			},
+		}
+		if step.Agent != "" {
+			s.Attributes["gen_ai.agent.name"] = step.Agent
+		}
+		if i := step.Interaction; i != nil {
+			if i.ID != "" {
+				s.Attributes["gen_ai.response.id"] = i.ID
+			}
+			if i.Model != "" {
+				s.Attributes["gen_ai.response.model"] = i.Model
+				s.Name = "chat " + i.Model
+			}
+			if u := i.Usage; u != nil {
+				s.Attributes["gen_ai.usage.input_tokens"] = u.TotalInputTokens
+				s.Attributes["gen_ai.usage.output_tokens"] = u.TotalOutputTokens
+			}
+		}
+		spans = append(spans, s)
+		for i, tc := range step.ToolCalls {
+			spans = append(spans, toolSpan(traceID, s.SpanID, step.Number, i, tc))
+		}
+	}
+	for _, h := range t.Handoffs {
+		for _, s := range spans {
+			if s.Attributes["agent.step"] == h.Step {
+				s.Attributes["agent.handoff.to"] = h.To
+			}
+		}
+	}
+	return spans
+}
+
+func toolSpan(traceID, parentID string, step, index int, tc *ToolCall) *Span {
+	s := &Span{
+		TraceID:      traceID,
+		SpanID:       spanID(traceID, "tool", step, index),
+		ParentSpanID: parentID,
+		Name:         "execute_tool " + tc.Name,
+		Kind:         SpanKindInternal,
+		StartTime:    tc.Start,
+		EndTime:      tc.Start.Add(tc.Duration),
+		Attributes: map[string]any{
+			"gen_ai.operation.name": "execute_tool",
+			"gen_ai.tool.name":      tc.Name,
+		},
+	}
+	if tc.ID != "" {
+		s.Attributes["gen_ai.tool.call.id"] = tc.ID
+	}
+	if b, err := json.Marshal(tc.Arguments); err == nil && tc.Arguments != nil {
+		s.Attributes["gen_ai.tool.call.arguments"] = string(b)
+	}
+	if b, err := json.Marshal(tc.Result); err == nil && tc.Result != nil {
+		s.Attributes["gen_ai.tool.call.result"] = string(b)
+	}
+	if tc.Err != nil {
+		s.Error = tc.Err.Error()
+	}
+	return s
+}
+
+// spanID derives a span ID from the trace ID and the position of the span.
+func spanID(traceID string, parts ...any) string {
+	sum := sha256.Sum256(fmt.Append([]byte(traceID), parts...))
+	return hex.EncodeToString(sum[:8])
+}
+
+// WriteOTLP writes the spans of the trace as an OTLP/JSON ExportTraceServiceRequest,
+// the body accepted by the /v1/traces endpoint of OpenTelemetry collectors.
+// serviceName is set as the service.name resource attribute.
+func (t *Trace) WriteOTLP(w io.Writer, serviceName string) error {
+	spans := t.Spans()
+	otlpSpans := make([]map[string]any, len(spans))
+	for i, s := range spans {
+		span := map[string]any{
+			"traceId":           s.TraceID,
+			"spanId":            s.SpanID,
+			"name":              s.Name,
+			"kind":              int(s.Kind),
+			"startTimeUnixNano": strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+			"attributes":        otlpAttributes(s.Attributes),
+			"status":            map[string]any{"code": 1},
+		}
+		if s.ParentSpanID != "" {
+			span["parentSpanId"] = s.ParentSpanID
+		}
+		if s.Error != "" {
+			span["status"] = map[string]any{"code": 2, "message": s.Error}
+		}
+		otlpSpans[i] = span
+	}
+	req := map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource": map[string]any{
+				"attributes": otlpAttributes(map[string]any{"service.name": serviceName}),
+			},
+			"scopeSpans": []any{map[string]any{
+				"scope": map[string]any{"name": instrumentationScope},
+				"spans": otlpSpans,
+			}},
+		}},
+	}
+	return json.NewEncoder(w).Encode(req)
+}
+
+// otlpAttributes converts attributes to OTLP/JSON key-value pairs, sorted by
+// key.
+func otlpAttributes(attrs map[string]any) []any {
+	kvs := make([]any, 0, len(attrs))
+	for _, key := range sortedKeys(attrs) {
+		var value map[string]any
+		switch v := attrs[key].(type) {
+		case int:
+			value = map[string]any{"intValue": strconv.Itoa(v)}
+		case bool:
+			value = map[string]any{"boolValue": v}
+		default:
+			value = map[string]any{"stringValue": fmt.Sprint(v)}
+		}
+		kvs = append(kvs, map[string]any{"key": key, "value": value})
+	}
+	return kvs
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/plar/genai"
+)
+
+func exportTestTrace(t *testing.T) *Trace {
+	t.Helper()
+	client, _ := newTestClient(t,
+		&genai.Interaction{
+			ID:    "step-1",
+			Model: "gemini-2.5-flash",
+			Outputs: []*genai.InteractionContent{
+				functionCall("call-1", "get_weather", map[string]any{"city": "Paris"}),
+				functionCall("call-2", "get_weather", map[string]any{"city": "Atlantis"}),
+			},
+			Usage: &genai.InteractionUsage{TotalInputTokens: 7, TotalOutputTokens: 3, TotalTokens: 10},
+		},
+		textOutput("step-2", "It is sunny in Paris.", 5),
+	)
+	a, err := New(client, &Config{Name: "weather", Model: "gemini-2.5-flash", Tools: []*Tool{weatherTool}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace, err := a.Run(context.Background(), "Weather in Paris and Atlantis?")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trace
+}
+
+func TestTraceJSON(t *testing.T) {
+	trace := exportTestTrace(t)
+	var buf bytes.Buffer
+	if err := trace.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadTrace(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != trace.ID || len(got.Steps) != 2 || got.StopReason != StopReasonCompleted || !got.Start.Equal(trace.Start) {
+		t.Errorf("ReadTrace() = %+v, want the written trace", got)
+	}
+	if tc := got.Steps[0].ToolCalls[1]; tc.Err == nil || tc.Err.Error() != "city not found" {
+		t.Errorf("tool call error = %v, want the error to survive the round trip", tc.Err)
+	}
+}
+
+func TestTraceSpans(t *testing.T) {
+	trace := exportTestTrace(t)
+	spans := trace.Spans()
+	if len(spans) != 5 {
+		t.Fatalf("got %d spans, want a run, 2 steps and 2 tool calls", len(spans))
+	}
+	var names []string
+	for _, s := range spans {
+		names = append(names, s.Name)
+		if s.TraceID != trace.ID {
+			t.Errorf("span %q has trace ID %s, want %s", s.Name, s.TraceID, trace.ID)
+		}
+	}
+	want := []string{"invoke_agent", "chat gemini-2.5-flash", "execute_tool get_weather", "execute_tool get_weather", "chat"}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Errorf("span names mismatch (-want +got):\n%s", diff)
+	}
+	root, step, tool := spans[0], spans[1], spans[3]
+	if step.ParentSpanID != root.SpanID || tool.ParentSpanID != step.SpanID {
+		t.Errorf("unexpected span tree: %+v", spans)
+	}
+	if step.Attributes["gen_ai.usage.input_tokens"] != 7 || step.Attributes["gen_ai.agent.name"] != "weather" {
+		t.Errorf("step attributes = %v, want usage and agent name", step.Attributes)
+	}
+	if tool.Error != "city not found" || tool.Attributes["gen_ai.tool.call.arguments"] != `{"city":"Atlantis"}` {
+		t.Errorf("tool span = %+v, want the arguments and the error", tool)
+	}
+	if again := trace.Spans(); again[3].SpanID != tool.SpanID {
+		t.Error("Spans() returned different span IDs for the same trace")
+	}
+
+	var buf bytes.Buffer
+	if err := trace.WriteOTLP(&buf, "weather-bot"); err != nil {
+		t.Fatal(err)
+	}
+	var otlp struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []struct {
+					SpanID string `json:"spanId"`
+					Status struct {
+						Code    int    `json:"code"`
+						Message string `json:"message"`
+					} `json:"status"`
+				} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &otlp); err != nil {
+		t.Fatal(err)
+	}
+	otlpSpans := otlp.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(otlpSpans) != 5 || otlpSpans[3].Status.Code != 2 || otlpSpans[3].Status.Message != "city not found" {
+		t.Errorf("OTLP spans = %+v, want 5 spans with an error status for the failed tool call", otlpSpans)
+	}
+}
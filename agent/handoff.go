@@ -0,0 +1,257 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/plar/genai"
+)
+
+// defaultMaxHandoffs is the handoff limit used when TeamConfig.MaxHandoffs is
+// zero.
+const defaultMaxHandoffs = 5
+
+// handoffToolPrefix prefixes the names of the tools that hand off to another
+// agent.
+const handoffToolPrefix = "transfer_to_"
+
+var agentNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Handoff records one agent passing control to another in a [Team] run.
+type Handoff struct {
+	// From is the name of the agent that handed off.
+	From string `json:"from"`
+	// To is the name of the agent that continued.
+	To string `json:"to"`
+	// Message is the instruction or context the model passed to the next agent.
+	Message string `json:"message,omitempty"`
+	// State is the state the model passed to the next agent.
+	State map[string]any `json:"state,omitempty"`
+	// Step is the number of the step in which the handoff happened.
+	Step int `json:"step"`
+
+	callID string
+}
+
+func newHandoff(from, to *Agent, call *genai.InteractionContent) *Handoff {
+	h := &Handoff{From: from.config.Name, To: to.config.Name, callID: call.ID}
+	args, err := toolArguments(call.Arguments)
+	if err != nil {
+		return h
+	}
+	h.Message, _ = args["message"].(string)
+	h.State, _ = args["state"].(map[string]any)
+	return h
+}
+
+func handoffDeclaration(toolName string, to *Agent) *genai.InteractionTool {
+	description := fmt.Sprintf("Hand off the conversation to the %q agent.", to.config.Name)
+	if to.config.Description != "" {
+		description += " " + to.config.Description
+	}
+	return &genai.InteractionTool{
+		Type:        "function",
+		Name:        toolName,
+		Description: description,
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"message": map[string]any{
+					"type":        "string",
+					"description": "What the next agent should do, with any context it needs.",
+				},
+				"state": map[string]any{
+					"type":        "object",
+					"description": "Structured facts gathered so far that the next agent needs.",
+				},
+			},
+			"required": []string{"message"},
+		},
+	}
+}
+
+// TeamConfig configures a Team.
+type TeamConfig struct {
+	// Optional. The maximum number of handoffs in a run. Defaults to 5.
+	MaxHandoffs int
+	// Optional. If true, the agent that takes over starts a new interaction that
+	// only contains the handoff message and state. By default it continues the
+	// interaction of the previous agent and sees the whole conversation.
+	IsolateHistory bool
+}
+
+// Team routes a run between named agents, for supervisor/worker patterns.
+//
+// Every agent of the team can hand off to every other agent through a
+// generated "transfer_to_<name>" tool. When the model calls it, the current
+// agent stops and the named agent continues with the handoff message and the
+// state passed along, and the steps of all agents are combined into one
+// [Trace].
+//
+// Only the first handoff of a step is followed; further handoff calls in the
+// step are reported as failed tool calls. Other tools called in the same step
+// still run, and their results are passed to the next agent: as function
+// results when it continues the interaction, or in the handoff message with
+// TeamConfig.IsolateHistory.
+type Team struct {
+	entry  *Agent
+	agents map[string]*Agent
+	config TeamConfig
+}
+
+// NewTeam creates a Team whose runs start with entry. All agents must have
+// distinct names that only contain letters, digits, '_' and '-'.
+func NewTeam(config *TeamConfig, entry *Agent, others ...*Agent) (*Team, error) {
+	if entry == nil {
+		return nil, errors.New("agent.NewTeam: entry agent is required")
+	}
+	if config == nil {
+		config = &TeamConfig{}
+	}
+	t := &Team{entry: entry, agents: map[string]*Agent{}, config: *config}
+	for _, a := range append([]*Agent{entry}, others...) {
+		if a == nil {
+			return nil, errors.New("agent.NewTeam: agent is nil")
+		}
+		name := a.config.Name
+		if !agentNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("agent.NewTeam: invalid agent name %q", name)
+		}
+		if _, ok := t.agents[name]; ok {
+			return nil, fmt.Errorf("agent.NewTeam: duplicate agent %q", name)
+		}
+		t.agents[name] = a
+	}
+	return t, nil
+}
+
+// handoffTools returns the handoff tools offered to the agent a.
+func (t *Team) handoffTools(a *Agent) map[string]*Agent {
+	tools := map[string]*Agent{}
+	for name, other := range t.agents {
+		if other != a {
+			tools[handoffToolPrefix+name] = other
+		}
+	}
+	return tools
+}
+
+// Run runs the team for input, starting with the entry agent, and returns the
+// combined trace of all agents. The trace's StopReason is that of the last
+// agent, or StopReasonMaxHandoffs.
+func (t *Team) Run(ctx context.Context, input any) (*Trace, error) {
+	start := time.Now()
+	combined := &Trace{ID: newTraceID(), Start: start}
+	defer func() { combined.Duration = time.Since(start) }()
+
+	maxHandoffs := t.config.MaxHandoffs
+	if maxHandoffs <= 0 {
+		maxHandoffs = defaultMaxHandoffs
+	}
+	current := t.entry
+	rs := &runState{}
+	for {
+		rs.handoffTools = t.handoffTools(current)
+		rs.firstStep = len(combined.Steps)
+		trace, err := current.run(ctx, input, rs)
+		combined.Steps = append(combined.Steps, trace.Steps...)
+		combined.TotalTokens += trace.TotalTokens
+		combined.ToolCalls += trace.ToolCalls
+		combined.Output = trace.Output
+		combined.StopReason = trace.StopReason
+		if err != nil || trace.StopReason != StopReasonHandoff {
+			return combined, err
+		}
+
+		h := rs.handoff
+		h.Step = len(combined.Steps)
+		combined.Handoffs = append(combined.Handoffs, h)
+		if len(h.State) > 0 {
+			if combined.State == nil {
+				combined.State = map[string]any{}
+			}
+			maps.Copy(combined.State, h.State)
+		}
+		if len(combined.Handoffs) > maxHandoffs {
+			combined.StopReason = StopReasonMaxHandoffs
+			return combined, nil
+		}
+
+		last := trace.Steps[len(trace.Steps)-1]
+		next := &runState{}
+		if t.config.IsolateHistory {
+			input = handoffMessage(h, last.ToolCalls, combined.State)
+		} else {
+			input = handoffInput(h, last.ToolCalls, combined.State)
+			next.previousID = last.Interaction.ID
+		}
+		rs = next
+		current = t.agents[h.To]
+	}
+}
+
+// handoffInput returns the first input of the agent that takes over: the
+// results of the pending function calls, including the handoff call, and the
+// handoff message.
+func handoffInput(h *Handoff, calls []*ToolCall, state map[string]any) []*genai.InteractionContent {
+	input := functionResults(calls)
+	input = append(input,
+		&genai.InteractionContent{
+			Type:   "function_result",
+			CallID: h.callID,
+			Name:   handoffToolPrefix + h.To,
+			Result: map[string]any{"transferred_to": h.To},
+		},
+		&genai.InteractionContent{Type: "text", Text: handoffMessage(h, nil, state)},
+	)
+	return input
+}
+
+// handoffMessage returns the message for the agent that takes over. calls are
+// the other tool calls of the handoff step, whose results are included when
+// the next agent does not see them as function results.
+func handoffMessage(h *Handoff, calls []*ToolCall, state map[string]any) string {
+	msg := fmt.Sprintf("You are the %q agent and take over from the %q agent.", h.To, h.From)
+	if h.Message != "" {
+		msg += "\n" + h.Message
+	}
+	if len(calls) > 0 {
+		results := make([]map[string]any, 0, len(calls))
+		for _, r := range functionResults(calls) {
+			results = append(results, map[string]any{"name": r.Name, "result": r.Result})
+		}
+		if b, err := json.Marshal(results); err == nil {
+			msg += "\nTool results: " + string(b)
+		}
+	}
+	if len(state) > 0 {
+		if b, err := json.Marshal(state); err == nil {
+			msg += "\nState: " + string(b)
+		}
+	}
+	return msg
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/plar/genai"
+)
+
+func toolNames(req map[string]any) []string {
+	var names []string
+	tools, _ := req["tools"].([]any)
+	for _, t := range tools {
+		names = append(names, t.(map[string]any)["name"].(string))
+	}
+	return names
+}
+
+func TestTeamRun(t *testing.T) {
+	ctx := context.Background()
+	client, fs := newTestClient(t,
+		&genai.Interaction{
+			ID: "step-1",
+			Outputs: []*genai.InteractionContent{
+				functionCall("call-1", "get_weather", map[string]any{"city": "Paris"}),
+				functionCall("call-2", "transfer_to_writer", map[string]any{
+					"message": "Write a poem about the weather.",
+					"state":   map[string]any{"city": "Paris"},
+				}),
+			},
+			Usage: &genai.InteractionUsage{TotalTokens: 10},
+		},
+		textOutput("step-2", "Paris basks in sun.", 5),
+	)
+	supervisor, err := New(client, &Config{Name: "supervisor", Model: "gemini-2.5-flash", Tools: []*Tool{weatherTool}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer, err := New(client, &Config{Name: "writer", Description: "Writes poems.", Model: "gemini-2.5-pro"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	team, err := NewTeam(nil, supervisor, writer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trace, err := team.Run(ctx, "Write a poem about the weather in Paris.")
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if trace.StopReason != StopReasonCompleted || trace.Output != "Paris basks in sun." {
+		t.Errorf("Run() = %q, %q, want the writer's answer", trace.StopReason, trace.Output)
+	}
+	if len(trace.Steps) != 2 || trace.TotalTokens != 15 || trace.ToolCalls != 1 {
+		t.Fatalf("got %d steps, %d tokens and %d tool calls, want 2, 15 and 1", len(trace.Steps), trace.TotalTokens, trace.ToolCalls)
+	}
+	if s := trace.Steps[1]; s.Number != 2 || s.Agent != "writer" {
+		t.Errorf("second step = %d by %q, want 2 by writer", s.Number, s.Agent)
+	}
+	wantHandoffs := []*Handoff{{
+		From:    "supervisor",
+		To:      "writer",
+		Message: "Write a poem about the weather.",
+		State:   map[string]any{"city": "Paris"},
+		Step:    1,
+	}}
+	if diff := cmp.Diff(wantHandoffs, trace.Handoffs, cmpopts.IgnoreUnexported(Handoff{})); diff != "" {
+		t.Errorf("handoffs mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"city": "Paris"}, trace.State); diff != "" {
+		t.Errorf("state mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(fs.requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(fs.requests))
+	}
+	first, second := fs.requests[0], fs.requests[1]
+	if diff := cmp.Diff([]string{"get_weather", "transfer_to_writer"}, toolNames(first)); diff != "" {
+		t.Errorf("supervisor tools mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"transfer_to_supervisor"}, toolNames(second)); diff != "" {
+		t.Errorf("writer tools mismatch (-want +got):\n%s", diff)
+	}
+	if second["model"] != "gemini-2.5-pro" || second["previousInteractionId"] != "step-1" {
+		t.Errorf("writer request = %v, want the writer's model continuing step-1", second)
+	}
+	input := second["input"].([]any)
+	if len(input) != 3 {
+		t.Fatalf("writer input = %v, want the tool result, the handoff result and the message", input)
+	}
+	wantResults := []any{
+		map[string]any{"type": "function_result", "callId": "call-1", "name": "get_weather", "result": map[string]any{"forecast": "sunny in Paris"}},
+		map[string]any{"type": "function_result", "callId": "call-2", "name": "transfer_to_writer", "result": map[string]any{"transferred_to": "writer"}},
+	}
+	if diff := cmp.Diff(wantResults, input[:2]); diff != "" {
+		t.Errorf("writer input mismatch (-want +got):\n%s", diff)
+	}
+	msg := input[2].(map[string]any)["text"].(string)
+	for _, want := range []string{"Write a poem about the weather.", `State: {"city":"Paris"}`} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("handoff message %q does not contain %q", msg, want)
+		}
+	}
+}
+
+func TestTeamRunHandoffSiblings(t *testing.T) {
+	ctx := context.Background()
+	for _, isolate := range []bool{false, true} {
+		client, fs := newTestClient(t,
+			&genai.Interaction{
+				ID: "step-1",
+				Outputs: []*genai.InteractionContent{
+					functionCall("call-1", "transfer_to_writer", map[string]any{"message": "Write it."}),
+					functionCall("call-2", "get_weather", map[string]any{"city": "Paris"}),
+					functionCall("call-3", "transfer_to_critic", map[string]any{"message": "Review it."}),
+				},
+			},
+			textOutput("step-2", "Done.", 5),
+		)
+		var agents []*Agent
+		for _, name := range []string{"supervisor", "writer", "critic"} {
+			a, err := New(client, &Config{Name: name, Model: "m", Tools: []*Tool{weatherTool}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			agents = append(agents, a)
+		}
+		team, err := NewTeam(&TeamConfig{IsolateHistory: isolate}, agents[0], agents[1:]...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		trace, err := team.Run(ctx, "Write a poem.")
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if len(trace.Handoffs) != 1 || trace.Handoffs[0].To != "writer" || trace.Steps[1].Agent != "writer" {
+			t.Fatalf("Run() handed off %v, want only the first handoff to writer", trace.Handoffs)
+		}
+		calls := trace.Steps[0].ToolCalls
+		if len(calls) != 2 || calls[0].Name != "get_weather" || calls[0].Err != nil || calls[1].Name != "transfer_to_critic" || calls[1].Err == nil {
+			t.Fatalf("first step tool calls = %v, want get_weather to run and transfer_to_critic to fail", calls)
+		}
+
+		input := fs.requests[1]["input"]
+		if isolate {
+			msg, _ := input.(string)
+			for _, want := range []string{"Write it.", `"forecast":"sunny in Paris"`, "already hands off"} {
+				if !strings.Contains(msg, want) {
+					t.Errorf("handoff message %q does not contain %q", msg, want)
+				}
+			}
+			continue
+		}
+		var callIDs []any
+		for _, c := range input.([]any) {
+			if c := c.(map[string]any); c["type"] == "function_result" {
+				callIDs = append(callIDs, c["callId"])
+			}
+		}
+		if diff := cmp.Diff([]any{"call-2", "call-3", "call-1"}, callIDs); diff != "" {
+			t.Errorf("writer function results mismatch (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestTeamRunMaxHandoffs(t *testing.T) {
+	ctx := context.Background()
+	handoff := func(id, to string) *genai.Interaction {
+		return &genai.Interaction{ID: id, Outputs: []*genai.InteractionContent{functionCall("c-"+id, "transfer_to_"+to, nil)}}
+	}
+	client, fs := newTestClient(t, handoff("1", "b"), handoff("2", "a"), handoff("3", "b"))
+	a, err := New(client, &Config{Name: "a", Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := New(client, &Config{Name: "b", Model: "m"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	team, err := NewTeam(&TeamConfig{MaxHandoffs: 2, IsolateHistory: true}, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace, err := team.Run(ctx, "ping")
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if trace.StopReason != StopReasonMaxHandoffs || len(trace.Handoffs) != 3 || len(trace.Steps) != 3 {
+		t.Errorf("Run() stopped with %q after %d handoffs and %d steps, want %q after 3 and 3", trace.StopReason, len(trace.Handoffs), len(trace.Steps), StopReasonMaxHandoffs)
+	}
+	// With isolated history, every agent starts a new interaction.
+	for _, req := range fs.requests {
+		if req["previousInteractionId"] != nil {
+			t.Errorf("request continues %v, want a new interaction", req["previousInteractionId"])
+		}
+	}
+
+	for _, agents := range [][]*Agent{{nil}, {a, a}, {a, &Agent{config: Config{Name: "bad name"}}}} {
+		if _, err := NewTeam(nil, agents[0], agents[1:]...); err == nil {
+			t.Errorf("NewTeam(%v) succeeded, want error", agents)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+
+	"github.com/plar/genai"
+)
+
+// Policy restricts what a tool may do. The agent checks the arguments chosen
+// by the model before the tool runs and the result after it returns; a
+// violation is reported to the model as a failed tool call with a
+// *PolicyError. It is the [genai.ToolPolicy] that FunctionRegistry enforces,
+// so that a policy applies alike to the tools of an agent and to the
+// functions of a chat.
+type Policy = genai.ToolPolicy
+
+// PolicyError is the error of a tool call that violated a Policy.
+type PolicyError = genai.ToolPolicyError
+
+// PolicyFromContext returns the policy of the running tool, or nil if the
+// tool has no policy.
+func PolicyFromContext(ctx context.Context) *Policy {
+	return genai.ToolPolicyFromContext(ctx)
+}
+
+// runTool runs tool with args under the policy, if any.
+func runTool(ctx context.Context, tool *Tool, policy *Policy, args map[string]any) (any, error) {
+	return policy.Run(ctx, tool.Name, args, tool.Func)
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/plar/genai"
+)
+
+func TestRunToolPolicy(t *testing.T) {
+	ctx := context.Background()
+	client, fs := newTestClient(t,
+		&genai.Interaction{ID: "1", Outputs: []*genai.InteractionContent{functionCall("c", "read_file", map[string]any{"path": "/etc/passwd"})}},
+		textOutput("2", "I cannot read that file.", 1),
+	)
+	read := &Tool{Name: "read_file", Func: func(ctx context.Context, args map[string]any) (any, error) {
+		t.Error("the tool ran despite the policy")
+		return nil, nil
+	}}
+	a, err := New(client, &Config{Model: "m", Tools: []*Tool{read}, ToolPolicy: &Policy{DeniedPaths: []string{"/etc"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace, err := a.Run(ctx, "Read /etc/passwd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pe *PolicyError
+	if !errors.As(trace.Steps[0].ToolCalls[0].Err, &pe) {
+		t.Errorf("tool call error = %v, want a PolicyError", trace.Steps[0].ToolCalls[0].Err)
+	}
+	result := fs.requests[1]["input"].([]any)[0].(map[string]any)
+	if result["isError"] != true || !strings.Contains(result["result"].(map[string]any)["error"].(string), "violates the policy") {
+		t.Errorf("function result = %v, want the policy violation", result)
+	}
+}
@@ -16,10 +16,10 @@ package genai
 
 import (
 	"bufio"
-	"os"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"iter"
@@ -29,6 +29,7 @@ import (
 	"net/textproto"
 	"net/url"
 	"reflect"
+	"regexp"
 	"runtime"
 	"slices"
 	"strconv"
@@ -43,63 +44,166 @@ const delayMultiplier = 2
 
 type apiClient struct {
 	clientConfig *ClientConfig
+	// limiter enforces ClientConfig.RateLimit, or is nil.
+	limiter *rateLimiter
+	// transport is the transport configured by ClientConfig.HTTPClientOptions,
+	// or nil.
+	transport *http.Transport
+}
+
+// resolveModel returns model, or the client's default model if model is empty.
+func (ac *apiClient) resolveModel(model string) string {
+	if model == "" && ac.clientConfig != nil {
+		return ac.clientConfig.DefaultModel
+	}
+	return model
 }
 
 // sendStreamRequest issues an server streaming API request and returns a map of the response contents.
 func sendStreamRequest[T responseStream[R], R any](ctx context.Context, ac *apiClient, path string, method string, body any, httpOptions *HTTPOptions, output *responseStream[R]) error {
-	req, httpOptions, err := buildRequest(ctx, ac, path, body, method, httpOptions)
+	call, err := ac.hookCall(ctx, path, method, body)
 	if err != nil {
 		return err
 	}
-
-	// Handle context timeout.
-	// The request's context deadline is set using [HTTPOptions.Timeout].
-	// [ClientConfig.HTTPClient.Timeout] does not affect the context deadline for the request.
-	// [ClientConfig.HTTPClient.Timeout] is used along with `x-server-timeout` header in order to
-	// get the end-to-end timeout value for logging.
-	requestContext := ctx
-	timeout := httpOptions.Timeout
-	var cancel context.CancelFunc
-	if timeout != nil && *timeout > 0*time.Second && isTimeoutBeforeDeadline(ctx, *timeout) {
-		requestContext, cancel = context.WithTimeout(ctx, *timeout)
-		defer cancel()
-	}
-	req = req.WithContext(requestContext)
-
-	resp, err := doRequest(ac, req)
+	resp, httpOptions, timeouts, err := ac.send(ctx, path, method, body, httpOptions, true)
 	if err != nil {
-		return err
+		call.done()
+		return call.error(err)
 	}
-
-	// resp.Body will be closed by the iterator
-	return deserializeStreamResponse(resp, output)
+	ac.reportServerWarnings(resp)
+
+	// resp.Body, the timeouts and the call will be released by the iterator
+	output.timeouts = timeouts
+	output.call = call
+	output.raw = ac.requestOptions(ctx).IncludeRawResponse
+	output.backend = ac.clientConfig.Backend
+	output.strictDecoding = ac.clientConfig.StrictDecoding
+	output.opts = ac.requestOptions(ctx).StreamOptions
+	output.onEvent = settleStreamUsage(resp, ac.logStreamEvents(ctx, ac.requestOptions(ctx).OnSSEEvent))
+	if err := deserializeStreamResponse(resp, output); err != nil {
+		timeouts.release()
+		call.done()
+		return call.error(ac.withBackend(err))
+	}
+	return nil
 }
 
 // sendRequest issues an API request and returns a map of the response contents.
 func sendRequest(ctx context.Context, ac *apiClient, path string, method string, body any, httpOptions *HTTPOptions) (map[string]any, error) {
-
-	req, httpOptions, err := buildRequest(ctx, ac, path, body, method, httpOptions)
+	call, err := ac.hookCall(ctx, path, method, body)
 	if err != nil {
 		return nil, err
 	}
-
-	requestContext := ctx
-	timeout := httpOptions.Timeout
-	var cancel context.CancelFunc
-	if timeout != nil && *timeout > 0*time.Second && isTimeoutBeforeDeadline(ctx, *timeout) {
-		requestContext, cancel = context.WithTimeout(ctx, *timeout)
-		defer cancel()
-	}
-	req = req.WithContext(requestContext)
-
-	resp, err := doRequest(ac, req)
+	defer call.done()
+	resp, _, timeouts, err := ac.send(ctx, path, method, body, httpOptions, false)
 	if err != nil {
-		return nil, err
+		return nil, call.error(err)
 	}
+	defer timeouts.release()
+	ac.reportServerWarnings(resp)
 
 	defer resp.Body.Close()
 
-	return deserializeUnaryResponse(resp)
+	output, err := deserializeUnaryResponse(resp, ac.requestOptions(ctx).IncludeRawResponse)
+	err = timeouts.cause(err)
+	if err == nil {
+		settleUsage(resp, output)
+		ac.logBody(ctx, "genai response body", resp.Request, output)
+		err = call.response(output)
+	}
+	if err == nil {
+		markStrictDecoding(output, ac.clientConfig.StrictDecoding)
+	}
+	return output, call.error(ac.withBackend(err))
+}
+
+// send builds the request of an API call and sends it through the
+// interceptors of the client. It returns the response, the HTTP options of
+// the request patched with those of the client, and the timeouts of the
+// request, to be released once the response is consumed.
+func (ac *apiClient) send(ctx context.Context, path string, method string, body any, httpOptions *HTTPOptions, stream bool) (*http.Response, *HTTPOptions, *callTimeouts, error) {
+	var patchedHTTPOptions *HTTPOptions
+	var timeouts *callTimeouts
+	interceptors := ac.clientConfig.Interceptors
+	invoke := func(ctx context.Context, call *InterceptedCall) (*http.Response, error) {
+		requestBody := body
+		if len(interceptors) > 0 {
+			requestBody = nil
+			if call.Body != nil {
+				requestBody = call.Body
+			}
+		}
+		retryOptions := ac.retryOptions(ctx)
+		requestOptions := ac.requestOptions(ctx)
+		for attempt := 1; ; attempt++ {
+			req, options, err := buildRequest(ctx, ac, call.Path, requestBody, call.Method, httpOptions)
+			if err != nil {
+				return nil, err
+			}
+			for key, values := range call.Header {
+				req.Header[key] = values
+			}
+			patchedHTTPOptions = options
+
+			// Handle context timeout.
+			// The request's context deadline is set using [HTTPOptions.Timeout],
+			// or [RequestOptions.RequestTimeout] for unary calls.
+			// [ClientConfig.HTTPClient.Timeout] does not affect the context deadline for the request.
+			// [ClientConfig.HTTPClient.Timeout] is used along with `x-server-timeout` header in order to
+			// get the end-to-end timeout value for logging.
+			if !call.Stream && requestOptions.RequestTimeout != nil {
+				setServerTimeout(ctx, ac, req, requestOptions.RequestTimeout)
+			}
+			timeouts.release()
+			reservation, release, err := ac.limiter.acquire(ctx, call, requestBody)
+			if err != nil {
+				return nil, err
+			}
+			// The timeouts of the attempt start once the rate limiter lets
+			// it through, so that the wait for the limiter does not count
+			// against ConnectTimeout or RequestTimeout.
+			var requestContext context.Context
+			requestContext, timeouts = newCallTimeouts(ctx, options, requestOptions, call.Stream)
+			ac.logRequest(ctx, req, requestBody, attempt)
+			start := time.Now()
+			resp, err := doRequest(ac, req.WithContext(requestContext))
+			timeouts.responded()
+			err = timeouts.cause(err)
+			ac.logResponse(ctx, req, resp, err, start)
+			limitResponse(resp, reservation, release)
+			if attempt >= retryOptions.maxAttempts() || !retryOptions.shouldRetry(ctx, resp, err) {
+				return resp, err
+			}
+			delay := retryOptions.delay(attempt, resp)
+			discardResponse(resp)
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("request aborted while waiting to retry (attempt %d): %w", attempt, ctx.Err())
+			case <-time.After(delay):
+			}
+		}
+	}
+	call := &InterceptedCall{Method: method, Path: path, Header: http.Header{}, Stream: stream}
+	if len(interceptors) > 0 {
+		bodyMap, err := interceptedBody(body)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		call.Body = bodyMap
+	}
+	resp, err := chainInterceptors(interceptors, invoke)(ctx, call)
+	if err == nil && resp == nil {
+		err = fmt.Errorf("the interceptors of %s %s returned no response", method, path)
+	}
+	if err == nil && patchedHTTPOptions == nil {
+		// An interceptor answered the call without sending it.
+		patchedHTTPOptions, err = patchHTTPOptions(ac.clientConfig.HTTPOptions, *httpOptions)
+	}
+	if err != nil {
+		timeouts.release()
+		return nil, nil, nil, err
+	}
+	return resp, patchedHTTPOptions, timeouts, nil
 }
 
 func downloadFile(ctx context.Context, ac *apiClient, path string, httpOptions *HTTPOptions) ([]byte, error) {
@@ -119,7 +223,11 @@ func downloadFile(ctx context.Context, ac *apiClient, path string, httpOptions *
 	return io.ReadAll(resp.Body)
 }
 
+// mapToStruct decodes a response map into output. If the map carries the
+// StrictDecodingConfig of the client, see markStrictDecoding, the fields of the
+// map unknown to the type of output are surfaced.
 func mapToStruct[R any](input map[string]any, output *R) error {
+	sd := takeStrictDecoding(input)
 	b := new(bytes.Buffer)
 	err := json.NewEncoder(b).Encode(input)
 	if err != nil {
@@ -129,10 +237,13 @@ func mapToStruct[R any](input map[string]any, output *R) error {
 	if err != nil {
 		return fmt.Errorf("mapToStruct: error unmarshalling input %#v: %w", input, err)
 	}
+	if sd != nil {
+		return checkUnknownFields(sd, input, reflect.TypeOf(output).Elem())
+	}
 	return nil
 }
 
-func (ac *apiClient) createAPIURL(suffix, method string, httpOptions *HTTPOptions) (*url.URL, error) {
+func (ac *apiClient) createAPIURL(suffix, method string, httpOptions *HTTPOptions, options RequestOptions) (*url.URL, error) {
 	path, query, _ := strings.Cut(suffix, "?")
 
 	u, err := url.Parse(httpOptions.BaseURL)
@@ -142,12 +253,30 @@ func (ac *apiClient) createAPIURL(suffix, method string, httpOptions *HTTPOption
 
 	var finalURL *url.URL
 	if ac.clientConfig.Backend == BackendVertexAI {
-		queryVertexBaseModel := method == http.MethodGet && strings.HasPrefix(path, "publishers/google/models")
+		if ac.expressMode() && (options.Project != "" || options.Location != "") {
+			return nil, fmt.Errorf("RequestOptions.Project and RequestOptions.Location are not supported in Vertex AI express mode")
+		}
+		if options.Location != "" {
+			if err := validateLocation(options.Location); err != nil {
+				return nil, err
+			}
+		}
+		project, location := ac.projectLocation(options)
+		queryVertexBaseModel := method == http.MethodGet && strings.HasPrefix(path, "publishers/")
 		if ac.clientConfig.APIKey == "" && (!strings.HasPrefix(path, "projects/") && !queryVertexBaseModel) {
-			path = fmt.Sprintf("projects/%s/locations/%s/%s", ac.clientConfig.Project, ac.clientConfig.Location, path)
+			path = fmt.Sprintf("projects/%s/locations/%s/%s", project, location, path)
+		} else if ac.clientConfig.APIKey == "" {
+			path = rebaseResourceName(path, ac.clientConfig.Project, ac.clientConfig.Location, project, location)
+		} else if ac.expressMode() {
+			if err := checkExpressModePath(path); err != nil {
+				return nil, err
+			}
 		}
-		finalURL = u.JoinPath(httpOptions.APIVersion, path)
+		finalURL = ac.resourceRegionURL(u, path).JoinPath(httpOptions.APIVersion, path)
 	} else {
+		if options.Project != "" || options.Location != "" {
+			return nil, fmt.Errorf("RequestOptions.Project and RequestOptions.Location are only supported in the Vertex AI client. You can choose to use Vertex AI by setting ClientConfig.Backend to BackendVertexAI.")
+		}
 		if !strings.HasPrefix(path, httpOptions.APIVersion+"/") && !strings.Contains(path, "/"+httpOptions.APIVersion+"/") {
 			path = httpOptions.APIVersion + "/" + strings.TrimPrefix(path, "/")
 		}
@@ -161,6 +290,98 @@ func (ac *apiClient) createAPIURL(suffix, method string, httpOptions *HTTPOption
 	return finalURL, nil
 }
 
+// resourceRegionURL returns the base URL u for a Vertex AI request to path.
+// Resources such as deployed endpoints and tuned models can only be reached
+// through the host of their own region, so if path names a resource in
+// another location than the client and u is the default host of the client,
+// the host of the resource's location is used instead.
+func (ac *apiClient) resourceRegionURL(u *url.URL, path string) *url.URL {
+	parts := strings.SplitN(path, "/", 5)
+	if len(parts) < 4 || parts[0] != "projects" || parts[2] != "locations" {
+		return u
+	}
+	location := parts[3]
+	if location == ac.clientConfig.Location || ac.clientConfig.APIKey != "" {
+		return u
+	}
+	if u.Host != "aiplatform.googleapis.com" && u.Host != ac.clientConfig.Location+"-aiplatform.googleapis.com" {
+		return u
+	}
+	regional := *u
+	regional.Host = vertexAIHost(location)
+	return &regional
+}
+
+// vertexAIHost returns the host of the Vertex AI endpoint of location: the
+// global endpoint for "global", or the regional endpoint otherwise.
+func vertexAIHost(location string) string {
+	if location == "global" {
+		return "aiplatform.googleapis.com"
+	}
+	return location + "-aiplatform.googleapis.com"
+}
+
+// locationPattern matches the form of Vertex AI locations such as "global",
+// "us" or "europe-west4".
+var locationPattern = regexp.MustCompile(`^[a-z]+(-[a-z0-9]+)*$`)
+
+// validateLocation checks that location is a well-formed Vertex AI location,
+// so that a typo is reported instead of a request to a host that does not
+// exist. It does not check that the region exists or serves a model: that
+// list changes over time, so it is left to the API.
+func validateLocation(location string) error {
+	if !locationPattern.MatchString(location) {
+		return fmt.Errorf(`invalid Vertex AI location %q; use "global" or a region such as "us-central1", see https://cloud.google.com/vertex-ai/generative-ai/docs/learn/locations`, location)
+	}
+	return nil
+}
+
+// projectLocation returns the project and location of a Vertex AI request,
+// which RequestOptions.Project and RequestOptions.Location override.
+func (ac *apiClient) projectLocation(options RequestOptions) (project, location string) {
+	project, location = ac.clientConfig.Project, ac.clientConfig.Location
+	if options.Project != "" {
+		project = options.Project
+	}
+	if options.Location != "" {
+		location = options.Location
+	}
+	return project, location
+}
+
+// rebaseResourceName moves name from the project and location of the client
+// to those of the request, if they differ. Names in other projects or
+// locations are returned unchanged.
+func rebaseResourceName(name, fromProject, fromLocation, project, location string) string {
+	if fromProject == project && fromLocation == location {
+		return name
+	}
+	from := fmt.Sprintf("projects/%s/locations/%s/", fromProject, fromLocation)
+	if rest, ok := strings.CutPrefix(name, from); ok {
+		return fmt.Sprintf("projects/%s/locations/%s/%s", project, location, rest)
+	}
+	return name
+}
+
+// rebaseResourceNames applies rebaseResourceName to the strings of a request
+// body, such as the model names that the transformers expand with the
+// project and location of the client.
+func rebaseResourceNames(v any, fromProject, fromLocation, project, location string) any {
+	switch v := v.(type) {
+	case string:
+		return rebaseResourceName(v, fromProject, fromLocation, project, location)
+	case map[string]any:
+		for k, e := range v {
+			v[k] = rebaseResourceNames(e, fromProject, fromLocation, project, location)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = rebaseResourceNames(e, fromProject, fromLocation, project, location)
+		}
+	}
+	return v
+}
+
 // patchHTTPOptions merges two HttpOptions objects, creating a new one.
 // Fields from patchOptions will overwrite fields from options.
 func patchHTTPOptions(options, patchOptions HTTPOptions) (*HTTPOptions, error) {
@@ -234,28 +455,44 @@ func buildRequest(ctx context.Context, ac *apiClient, path string, body any, met
 	if err != nil {
 		return nil, nil, err
 	}
-	url, err := ac.createAPIURL(path, method, patchedHTTPOptions)
+	options := ac.requestOptions(ctx)
+	url, err := ac.createAPIURL(path, method, patchedHTTPOptions, options)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	if patchedHTTPOptions.ExtraBody != nil {
-		if bodyMap, ok := body.(map[string]any); ok {
-			recursiveMapMerge(bodyMap, patchedHTTPOptions.ExtraBody)
-		}
+	if project, location := ac.projectLocation(options); project != ac.clientConfig.Project || location != ac.clientConfig.Location {
+		rebaseResourceNames(body, ac.clientConfig.Project, ac.clientConfig.Location, project, location)
 	}
 
-	if patchedHTTPOptions.ExtrasRequestProvider != nil {
-		body = patchedHTTPOptions.ExtrasRequestProvider(body)
+	if body != nil && (patchedHTTPOptions.ExtraBody != nil || patchedHTTPOptions.ExtrasRequestProvider != nil) {
+		// Bodies such as an *Interaction are encoded to a map first, so that
+		// the extras apply to every request.
+		bodyMap, err := interceptedBody(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		if patchedHTTPOptions.ExtraBody != nil {
+			recursiveMapMerge(bodyMap, patchedHTTPOptions.ExtraBody)
+		}
+		if patchedHTTPOptions.ExtrasRequestProvider != nil {
+			bodyMap = patchedHTTPOptions.ExtrasRequestProvider(bodyMap)
+		}
+		body = bodyMap
 	}
 
 	b := new(bytes.Buffer)
 	var payload []byte
+	compressed := false
 	if body != nil {
 		payload, err = json.Marshal(body)
 		if err != nil {
 			return nil, nil, fmt.Errorf("buildRequest: error encoding body %#v: %w", body, err)
 		}
+		payload, compressed, err = compressBody(payload, options.Compression)
+		if err != nil {
+			return nil, nil, fmt.Errorf("buildRequest: error compressing body: %w", err)
+		}
 		b.Write(payload)
 	}
 
@@ -264,28 +501,16 @@ func buildRequest(ctx context.Context, ac *apiClient, path string, body any, met
 		return nil, nil, err
 	}
 	req.Header = patchedHTTPOptions.Headers
-	timeoutSeconds := inferTimeout(ctx, ac, patchedHTTPOptions.Timeout).Seconds()
-	if timeoutSeconds > 0 {
-		req.Header.Set("x-server-timeout", strconv.FormatInt(int64(math.Ceil(timeoutSeconds)), 10))
-	}
+	setServerTimeout(ctx, ac, req, patchedHTTPOptions.Timeout)
 
 	req.Header.Set("Content-Type", "application/json")
+	if compressed {
+		req.Header.Set("Content-Encoding", string(CompressionGzip))
+	}
 	if ac.clientConfig.APIKey != "" {
 		req.Header.Set("x-goog-api-key", ac.clientConfig.APIKey)
 	}
 
-	f, err := os.OpenFile("/tmp/debug.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err == nil {
-		_, _ = f.WriteString("\n--- DEBUG REQUEST ---\n")
-		_, _ = fmt.Fprintf(f, "URL: %s %s\n", method, url.String())
-		for k, v := range req.Header {
-			_, _ = fmt.Fprintf(f, "HEADER: %s: %v\n", k, v)
-		}
-		_, _ = f.WriteString("BODY: ")
-		_, _ = f.Write(payload)
-		_, _ = f.WriteString("\n----------------------\n")
-		f.Close()
-	}
 	return req, patchedHTTPOptions, nil
 }
 
@@ -320,6 +545,15 @@ func recursiveMapMerge(dest, src map[string]any) {
 }
 
 // TODO(b/428730853): HTTP Client timeout should be considered.
+// setServerTimeout sets the x-server-timeout header of req to the end-to-end
+// timeout of the request, if any.
+func setServerTimeout(ctx context.Context, ac *apiClient, req *http.Request, requestTimeout *time.Duration) {
+	timeoutSeconds := inferTimeout(ctx, ac, requestTimeout).Seconds()
+	if timeoutSeconds > 0 {
+		req.Header.Set("x-server-timeout", strconv.FormatInt(int64(math.Ceil(timeoutSeconds)), 10))
+	}
+}
+
 func isTimeoutBeforeDeadline(ctx context.Context, timeout time.Duration) bool {
 	deadline, ok := ctx.Deadline()
 	if !ok {
@@ -367,10 +601,13 @@ func doRequest(ac *apiClient, req *http.Request) (*http.Response, error) {
 	if err != nil {
 		return nil, fmt.Errorf("doRequest: error sending request: %w", err)
 	}
+	if err := decompressResponse(resp); err != nil {
+		return nil, err
+	}
 	return resp, nil
 }
 
-func deserializeUnaryResponse(resp *http.Response) (map[string]any, error) {
+func deserializeUnaryResponse(resp *http.Response, includeRaw bool) (map[string]any, error) {
 	if !httpStatusOk(resp) {
 		return nil, newAPIError(resp)
 	}
@@ -390,6 +627,9 @@ func deserializeUnaryResponse(resp *http.Response) (map[string]any, error) {
 	httpResponse := map[string]any{
 		"headers": resp.Header,
 	}
+	if includeRaw {
+		httpResponse["body"] = string(respBody)
+	}
 	output["sdkHttpResponse"] = httpResponse
 	return output, nil
 }
@@ -398,45 +638,86 @@ type responseStream[R any] struct {
 	r  *bufio.Scanner
 	rc io.ReadCloser
 	h  http.Header
+	// raw retains the JSON payload of each chunk in its SDKHTTPResponse.
+	raw bool
+	// opts controls how the body is closed if the caller stops early.
+	opts *StreamOptions
+	// onEvent is called with every raw event, see [RequestOptions.OnSSEEvent].
+	onEvent func(*SSEEvent)
+	// resp and backend are recorded in the errors of the stream.
+	resp    *http.Response
+	backend Backend
+	// timeouts are the timeouts of the request, released with the body.
+	timeouts *callTimeouts
+	// strictDecoding is the StrictDecodingConfig of the client.
+	strictDecoding *StrictDecodingConfig
+	// call runs the hooks of the call on each chunk, and is done with the
+	// stream.
+	call *hookedCall
+}
+
+func (rs *responseStream[R]) notifyEvent(event *SSEEvent) {
+	if rs.onEvent != nil {
+		rs.onEvent(event)
+	}
+}
+
+// blockError returns the error of a block of the stream without data, or nil
+// if the block is not an error.
+func (rs *responseStream[R]) blockError(block []byte) error {
+	var respWithError = new(responseWithError)
+	if err := json.Unmarshal(block, respWithError); err != nil || respWithError.ErrorInfo == nil {
+		return nil
+	}
+	apiErr := *respWithError.ErrorInfo
+	if rs.resp != nil {
+		apiErr.setResponseContext(rs.resp)
+	}
+	apiErr.Backend = rs.backend
+	return apiErr
 }
 
 func iterateResponseStream[R any](rs *responseStream[R], responseConverter func(responseMap map[string]any) (*R, error)) iter.Seq2[*R, error] {
 	return func(yield func(*R, error) bool) {
+		stopped := false
 		defer func() {
-			if err := rs.rc.Close(); err != nil {
-				log.Printf("Error closing response body: %v", err)
-			}
+			closeStream(rs.rc, stopped, rs.opts)
+			rs.timeouts.release()
+			rs.call.done()
 		}()
 		for rs.r.Scan() {
+			rs.timeouts.resetIdle()
 			block := rs.r.Bytes()
 			if len(block) == 0 {
 				continue
 			}
 
-			var dataPayload []byte
-			// Robustly find the data: part in the SSE block
-			lines_in_block := bytes.Split(block, []byte("\n"))
-			for _, line := range lines_in_block {
-				line = bytes.TrimSpace(line)
-				if bytes.HasPrefix(line, []byte("data:")) {
-					dataPayload = bytes.TrimPrefix(line, []byte("data:"))
-					dataPayload = bytes.TrimSpace(dataPayload)
-					break
-				}
-			}
+			event := parseSSEEvent(block)
+			dataPayload := []byte(event.Data)
 
 			if len(dataPayload) > 0 {
 				if string(dataPayload) == "[DONE]" {
+					rs.notifyEvent(event)
 					return
 				}
 				respRaw := make(map[string]any)
 				if err := json.Unmarshal(dataPayload, &respRaw); err != nil {
 					// Skip invalid JSON or comments
+					event.Err = err
+					rs.notifyEvent(event)
 					continue
 				}
+				rs.notifyEvent(event)
+				if err := rs.call.response(respRaw); err != nil {
+					yield(nil, err)
+					stopped = true
+					return
+				}
+				markStrictDecoding(respRaw, rs.strictDecoding)
 				resp, err := responseConverter(respRaw)
 				if err != nil {
 					if !yield(nil, err) {
+						stopped = true
 						return
 					}
 					continue
@@ -449,26 +730,37 @@ func iterateResponseStream[R any](rs *responseStream[R], responseConverter func(
 							field.Set(reflect.ValueOf(&HTTPResponse{}))
 						}
 						field.Interface().(*HTTPResponse).Headers = rs.h
+						if rs.raw {
+							field.Interface().(*HTTPResponse).Body = string(dataPayload)
+						}
 					}
 				}
 				if !yield(resp, nil) {
+					stopped = true
 					return
 				}
 				continue
 			}
 
-			// Check for error chunk in raw block if no data found
-			var respWithError = new(responseWithError)
-			if err := json.Unmarshal(block, respWithError); err == nil && respWithError.ErrorInfo != nil {
-				if !yield(nil, *respWithError.ErrorInfo) {
+			rs.notifyEvent(event)
+			if err := rs.blockError(block); err != nil {
+				if !yield(nil, err) {
+					stopped = true
 					return
 				}
 			}
 		}
+		// A connection that breaks mid-stream is reported rather than
+		// mistaken for the end of the stream.
+		if err := rs.r.Err(); err != nil {
+			yield(nil, fmt.Errorf("stream interrupted: %w", rs.timeouts.cause(err)))
+		}
 	}
 }
 
-// APIError contains an error response from the server.
+// APIError contains an error response from the server. Use [AsAPIError] to
+// get it from an error returned by any service, and predicates such as
+// [IsRateLimited] or [IsNotFound] to check the kind of error.
 type APIError struct {
 	// Code is the HTTP response status code.
 	Code int `json:"code,omitempty"`
@@ -478,6 +770,18 @@ type APIError struct {
 	Status string `json:"status,omitempty"`
 	// Details field provides more context to an error.
 	Details []map[string]any `json:"details,omitempty"`
+
+	// Backend is the backend the failed request was sent to.
+	Backend Backend `json:"-"`
+	// Endpoint is the HTTP method and URL (without query parameters) of the failed request.
+	Endpoint string `json:"-"`
+	// Model is the model or endpoint resource targeted by the failed request, if any.
+	Model string `json:"-"`
+	// RequestID is the server-assigned identifier of the failed request, if any.
+	RequestID string `json:"-"`
+	// RetryAfter is how long the server asked to wait before retrying, from the
+	// Retry-After response header, if any.
+	RetryAfter time.Duration `json:"-"`
 }
 
 type responseWithError struct {
@@ -491,29 +795,133 @@ func newAPIError(resp *http.Response) error {
 		return fmt.Errorf("newAPIError: error reading response body: %w. Response: %v", err, string(body))
 	}
 
+	var apiErr APIError
 	if len(body) > 0 {
 		if err := json.Unmarshal(body, respWithError); err != nil {
 			// Handle plain text error message. File upload backend doesn't return json error message.
-			return APIError{Code: resp.StatusCode, Status: resp.Status, Message: string(body)}
+			apiErr = APIError{Code: resp.StatusCode, Status: resp.Status, Message: string(body)}
+		} else if respWithError.ErrorInfo != nil {
+			// Check if we successfully parsed an error response
+			apiErr = *respWithError.ErrorInfo
+		} else {
+			// Valid JSON but no error field - treat as generic error with body content
+			apiErr = APIError{Code: resp.StatusCode, Status: resp.Status, Message: string(body)}
 		}
+	} else {
+		apiErr = APIError{Code: resp.StatusCode, Status: resp.Status}
+	}
+	apiErr.setResponseContext(resp)
+	return apiErr
+}
+
+// isTransientError reports whether a request that failed with err may succeed
+// if it is retried or resumed: a network error, a truncated response, a
+// *TimeoutError, or an APIError for a rate limit or a server error.
+func isTransientError(err error) bool {
+	var timeoutErr *TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return true
+	}
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// modelResourcePattern matches the model, tuned model or endpoint resource in a request path.
+var modelResourcePattern = regexp.MustCompile(`(?:^|/)((?:models|tunedModels|endpoints)/[^/:]+)`)
 
-		// Check if we successfully parsed an error response
-		if respWithError.ErrorInfo != nil {
-			return *respWithError.ErrorInfo
+// setResponseContext records which request failed, so that the error message
+// can point at the model and endpoint involved.
+func (e *APIError) setResponseContext(resp *http.Response) {
+	if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		e.RetryAfter = after
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		e.Endpoint = requestEndpoint(resp)
+		if m := modelResourcePattern.FindStringSubmatch(resp.Request.URL.Path); m != nil {
+			e.Model = strings.TrimPrefix(m[1], "models/")
 		}
+	}
+	for _, detail := range e.Details {
+		if t, _ := detail["@type"].(string); strings.HasSuffix(t, "google.rpc.RequestInfo") {
+			if id, ok := detail["requestId"].(string); ok && id != "" {
+				e.RequestID = id
+				return
+			}
+		}
+	}
+	e.RequestID = resp.Header.Get("X-Request-Id")
+}
+
+// requestEndpoint returns the HTTP method and URL, without query, of the
+// request that produced resp.
+func requestEndpoint(resp *http.Response) string {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return ""
+	}
+	u := *resp.Request.URL
+	u.RawQuery = ""
+	u.Fragment = ""
+	return fmt.Sprintf("%s %s", resp.Request.Method, u.String())
+}
 
-		// Valid JSON but no error field - treat as generic error with body content
-		return APIError{Code: resp.StatusCode, Status: resp.Status, Message: string(body)}
+// withBackend fills in the client backend on an APIError. Other errors are
+// returned unchanged.
+func (ac *apiClient) withBackend(err error) error {
+	if apiErr, ok := err.(APIError); ok {
+		apiErr.Backend = ac.clientConfig.Backend
+		return apiErr
 	}
-	return APIError{Code: resp.StatusCode, Status: resp.Status}
+	return err
+}
+
+// hint returns a suggestion for resolving common failures, or an empty string.
+func (e APIError) hint() string {
+	message := strings.ToLower(e.Message)
+	switch {
+	case strings.Contains(e.Endpoint, "/interactions") && (e.Code == http.StatusNotFound || e.Code == http.StatusBadRequest) &&
+		!strings.Contains(e.Endpoint, "/v1beta/"):
+		return `the Interactions API is only served under v1beta; set HTTPOptions.APIVersion to "v1beta"`
+	case strings.Contains(message, "not in an active state") || strings.Contains(message, "file is not active"):
+		return "uploaded files must finish processing before use; poll Files.Get until File.State is FileStateActive"
+	case e.Backend == BackendVertexAI && (e.Code == http.StatusNotFound || e.Code == http.StatusBadRequest) &&
+		(strings.Contains(message, "location") || strings.Contains(message, "region") || strings.Contains(message, "was not found or your project does not have access")):
+		return `the model may not be available in the configured location; check the model's supported regions or use Location "global"`
+	case e.Code == http.StatusTooManyRequests:
+		return "the request was rate limited; retry with backoff or request a quota increase"
+	}
+	return ""
 }
 
 // Error returns a string representation of the APIError.
+//
+// Besides the server response, the string includes the backend, model,
+// endpoint and request ID of the failed request when known, followed by a
+// hint for common failures.
 func (e APIError) Error() string {
-	return fmt.Sprintf(
-		"Error %d, Message: %s, Status: %s, Details: %v",
-		e.Code, e.Message, e.Status, e.Details,
-	)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Error %d, Message: %s, Status: %s, Details: %v", e.Code, e.Message, e.Status, e.Details)
+	if e.Backend != BackendUnspecified {
+		fmt.Fprintf(&sb, ", Backend: %s", e.Backend)
+	}
+	if e.Model != "" {
+		fmt.Fprintf(&sb, ", Model: %s", e.Model)
+	}
+	if e.Endpoint != "" {
+		fmt.Fprintf(&sb, ", Endpoint: %s", e.Endpoint)
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&sb, ", RequestID: %s", e.RequestID)
+	}
+	if hint := e.hint(); hint != "" {
+		fmt.Fprintf(&sb, ". Hint: %s", hint)
+	}
+	return sb.String()
 }
 
 func httpStatusOk(resp *http.Response) bool {
@@ -535,6 +943,7 @@ func deserializeStreamResponse[T responseStream[R], R any](resp *http.Response,
 	output.r.Split(scan)
 	output.rc = resp.Body
 	output.h = resp.Header
+	output.resp = resp
 	return nil
 }
 
@@ -627,9 +1036,9 @@ func (ac *apiClient) upload(ctx context.Context, r io.Reader, uploadURL string,
 		}
 		defer resp.Body.Close()
 
-		respBody, err = deserializeUnaryResponse(resp)
+		respBody, err = deserializeUnaryResponse(resp, ac.requestOptions(ctx).IncludeRawResponse)
 		if err != nil {
-			return nil, fmt.Errorf("response body is invalid for chunk at offset %d: %w", offset, err)
+			return nil, fmt.Errorf("response body is invalid for chunk at offset %d: %w", offset, ac.withBackend(err))
 		}
 
 		offset += int64(bytesRead)
@@ -729,6 +729,7 @@ func TestBuildRequest(t *testing.T) {
 		body            map[string]any
 		method          string
 		httpOptions     *HTTPOptions
+		requestOptions  *RequestOptions
 		contextTimeout  time.Duration
 		want            *http.Request
 		wantErr         bool
@@ -969,6 +970,183 @@ func TestBuildRequest(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Vertex AI endpoint in another location",
+			clientConfig: &ClientConfig{
+				Project:     "test-project",
+				Location:    "us-central1",
+				Backend:     BackendVertexAI,
+				HTTPClient:  &http.Client{},
+				Credentials: &auth.Credentials{},
+			},
+			path:   "projects/test-project/locations/europe-west4/endpoints/123:generateContent",
+			body:   map[string]any{},
+			method: "POST",
+			httpOptions: &HTTPOptions{
+				BaseURL:    "https://us-central1-aiplatform.googleapis.com",
+				APIVersion: "v1beta1",
+			},
+			want: &http.Request{
+				Method: "POST",
+				URL: &url.URL{
+					Scheme: "https",
+					Host:   "europe-west4-aiplatform.googleapis.com",
+					Path:   "/v1beta1/projects/test-project/locations/europe-west4/endpoints/123:generateContent",
+				},
+				Header: http.Header{
+					"Content-Type":      []string{"application/json"},
+					"User-Agent":        []string{fmt.Sprintf("google-genai-sdk/%s gl-go/%s", version, runtime.Version())},
+					"X-Goog-Api-Client": []string{fmt.Sprintf("google-genai-sdk/%s gl-go/%s", version, runtime.Version())},
+				},
+				Body: io.NopCloser(strings.NewReader(`{}`)),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Vertex AI endpoint in another location with a custom base URL",
+			clientConfig: &ClientConfig{
+				Project:     "test-project",
+				Location:    "us-central1",
+				Backend:     BackendVertexAI,
+				HTTPClient:  &http.Client{},
+				Credentials: &auth.Credentials{},
+			},
+			path:   "projects/test-project/locations/europe-west4/endpoints/123:generateContent",
+			body:   map[string]any{},
+			method: "POST",
+			httpOptions: &HTTPOptions{
+				BaseURL:    "https://proxy.example.com",
+				APIVersion: "v1beta1",
+			},
+			want: &http.Request{
+				Method: "POST",
+				URL: &url.URL{
+					Scheme: "https",
+					Host:   "proxy.example.com",
+					Path:   "/v1beta1/projects/test-project/locations/europe-west4/endpoints/123:generateContent",
+				},
+				Header: http.Header{
+					"Content-Type":      []string{"application/json"},
+					"User-Agent":        []string{fmt.Sprintf("google-genai-sdk/%s gl-go/%s", version, runtime.Version())},
+					"X-Goog-Api-Client": []string{fmt.Sprintf("google-genai-sdk/%s gl-go/%s", version, runtime.Version())},
+				},
+				Body: io.NopCloser(strings.NewReader(`{}`)),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Vertex AI request with project and location override",
+			clientConfig: &ClientConfig{
+				Project:     "test-project",
+				Location:    "us-central1",
+				Backend:     BackendVertexAI,
+				HTTPClient:  &http.Client{},
+				Credentials: &auth.Credentials{},
+			},
+			path:   "cachedContents",
+			body:   map[string]any{"model": "projects/test-project/locations/us-central1/publishers/google/models/gemini-2.5-flash"},
+			method: "POST",
+			httpOptions: &HTTPOptions{
+				BaseURL:    "https://us-central1-aiplatform.googleapis.com",
+				APIVersion: "v1beta1",
+			},
+			requestOptions: &RequestOptions{
+				Project:  "tenant-project",
+				Location: "europe-west4",
+			},
+			want: &http.Request{
+				Method: "POST",
+				URL: &url.URL{
+					Scheme: "https",
+					Host:   "europe-west4-aiplatform.googleapis.com",
+					Path:   "/v1beta1/projects/tenant-project/locations/europe-west4/cachedContents",
+				},
+				Header: http.Header{
+					"Content-Type":      []string{"application/json"},
+					"User-Agent":        []string{fmt.Sprintf("google-genai-sdk/%s gl-go/%s", version, runtime.Version())},
+					"X-Goog-Api-Client": []string{fmt.Sprintf("google-genai-sdk/%s gl-go/%s", version, runtime.Version())},
+				},
+				Body: io.NopCloser(strings.NewReader(`{"model":"projects/tenant-project/locations/europe-west4/publishers/google/models/gemini-2.5-flash"}`)),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Vertex AI request with global location override",
+			clientConfig: &ClientConfig{
+				Project:     "test-project",
+				Location:    "us-central1",
+				Backend:     BackendVertexAI,
+				HTTPClient:  &http.Client{},
+				Credentials: &auth.Credentials{},
+			},
+			path:   "publishers/google/models/gemini-2.5-flash:generateContent",
+			body:   map[string]any{},
+			method: "POST",
+			httpOptions: &HTTPOptions{
+				BaseURL:    "https://us-central1-aiplatform.googleapis.com",
+				APIVersion: "v1beta1",
+			},
+			requestOptions: &RequestOptions{
+				Location: "global",
+			},
+			want: &http.Request{
+				Method: "POST",
+				URL: &url.URL{
+					Scheme: "https",
+					Host:   "aiplatform.googleapis.com",
+					Path:   "/v1beta1/projects/test-project/locations/global/publishers/google/models/gemini-2.5-flash:generateContent",
+				},
+				Header: http.Header{
+					"Content-Type":      []string{"application/json"},
+					"User-Agent":        []string{fmt.Sprintf("google-genai-sdk/%s gl-go/%s", version, runtime.Version())},
+					"X-Goog-Api-Client": []string{fmt.Sprintf("google-genai-sdk/%s gl-go/%s", version, runtime.Version())},
+				},
+				Body: io.NopCloser(strings.NewReader(`{}`)),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Vertex AI request with invalid location override",
+			clientConfig: &ClientConfig{
+				Project:     "test-project",
+				Location:    "us-central1",
+				Backend:     BackendVertexAI,
+				HTTPClient:  &http.Client{},
+				Credentials: &auth.Credentials{},
+			},
+			path:   "cachedContents",
+			body:   map[string]any{},
+			method: "POST",
+			httpOptions: &HTTPOptions{
+				BaseURL:    "https://us-central1-aiplatform.googleapis.com",
+				APIVersion: "v1beta1",
+			},
+			requestOptions: &RequestOptions{
+				Location: "Europe West4",
+			},
+			wantErr:       true,
+			expectedError: "invalid Vertex AI location",
+		},
+		{
+			name: "Gemini API request with project override",
+			clientConfig: &ClientConfig{
+				APIKey:     "test-api-key",
+				Backend:    BackendGeminiAPI,
+				HTTPClient: &http.Client{},
+			},
+			path:   "models/gemini-2.5-flash:generateContent",
+			body:   map[string]any{},
+			method: "POST",
+			httpOptions: &HTTPOptions{
+				BaseURL:    "https://generativelanguage.googleapis.com",
+				APIVersion: "v1beta",
+			},
+			requestOptions: &RequestOptions{
+				Project: "tenant-project",
+			},
+			wantErr:       true,
+			expectedError: "only supported in the Vertex AI client",
+		},
 		{
 			name: "Invalid URL",
 			clientConfig: &ClientConfig{
@@ -1164,6 +1342,7 @@ func TestBuildRequest(t *testing.T) {
 				ctx, cancel = context.WithTimeout(ctx, tt.contextTimeout)
 				defer cancel()
 			}
+			ctx = WithRequestOptions(ctx, tt.requestOptions)
 
 			req, _, err := buildRequest(ctx, ac, tt.path, tt.body, tt.method, tt.httpOptions)
 
@@ -1204,6 +1383,28 @@ func TestBuildRequest(t *testing.T) {
 	}
 }
 
+func TestBuildRequestExtrasWithStructBody(t *testing.T) {
+	ac := &apiClient{clientConfig: &ClientConfig{APIKey: "test-api-key", Backend: BackendGeminiAPI, HTTPClient: &http.Client{}}}
+	httpOptions := &HTTPOptions{
+		BaseURL:    "https://generativelanguage.googleapis.com",
+		APIVersion: "v1beta",
+		ExtraBody:  map[string]any{"store": false},
+		ExtrasRequestProvider: func(body map[string]any) map[string]any {
+			body["model"] = "models/" + body["model"].(string)
+			return body
+		},
+	}
+	req, _, err := buildRequest(context.Background(), ac, "interactions", &Interaction{Model: "gemini-2.5-flash"}, http.MethodPost, httpOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(req.Body)
+	want := "{\"model\":\"models/gemini-2.5-flash\",\"store\":false}"
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("buildRequest() body mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestPatchHTTPOptions(t *testing.T) {
 	timeout1 := 10 * time.Second
 	timeout2 := 20 * time.Second
@@ -1780,3 +1981,122 @@ func TestRecursiveMapMerge(t *testing.T) {
 		})
 	}
 }
+
+func TestAPIErrorRequestContext(t *testing.T) {
+	tests := []struct {
+		name         string
+		backend      Backend
+		apiVersion   string
+		path         string
+		statusCode   int
+		responseBody string
+		header       http.Header
+		wantContains []string
+		wantAbsent   []string
+	}{
+		{
+			name:         "model and request ID from details",
+			backend:      BackendGeminiAPI,
+			apiVersion:   "v1beta",
+			path:         "models/gemini-2.5-flash:generateContent?key=secret",
+			statusCode:   http.StatusBadRequest,
+			responseBody: `{"error": {"code": 400, "message": "bad request", "status": "INVALID_ARGUMENT", "details": [{"@type": "type.googleapis.com/google.rpc.RequestInfo", "requestId": "req-123"}]}}`,
+			wantContains: []string{
+				"Error 400, Message: bad request, Status: INVALID_ARGUMENT",
+				"Backend: BackendGeminiAPI",
+				"Model: gemini-2.5-flash",
+				"Endpoint: POST ",
+				"/v1beta/models/gemini-2.5-flash:generateContent",
+				"RequestID: req-123",
+			},
+			wantAbsent: []string{"secret", "Hint"},
+		},
+		{
+			name:         "request ID from header",
+			backend:      BackendGeminiAPI,
+			apiVersion:   "v1beta",
+			path:         "tunedModels/my-model:generateContent",
+			statusCode:   http.StatusInternalServerError,
+			responseBody: `{"error": {"code": 500, "message": "internal", "status": "INTERNAL"}}`,
+			header:       http.Header{"X-Request-Id": []string{"hdr-456"}},
+			wantContains: []string{"Model: tunedModels/my-model", "RequestID: hdr-456"},
+		},
+		{
+			name:         "interactions on wrong API version",
+			backend:      BackendGeminiAPI,
+			apiVersion:   "v1",
+			path:         "interactions",
+			statusCode:   http.StatusNotFound,
+			responseBody: `{"error": {"code": 404, "message": "not found", "status": "NOT_FOUND"}}`,
+			wantContains: []string{`Hint: the Interactions API is only served under v1beta`},
+		},
+		{
+			name:         "file not active",
+			backend:      BackendGeminiAPI,
+			apiVersion:   "v1beta",
+			path:         "models/gemini-2.5-flash:generateContent",
+			statusCode:   http.StatusBadRequest,
+			responseBody: `{"error": {"code": 400, "message": "The File abc is not in an ACTIVE state and usage is not allowed.", "status": "FAILED_PRECONDITION"}}`,
+			wantContains: []string{"Hint: uploaded files must finish processing before use"},
+		},
+		{
+			name:         "region mismatch",
+			backend:      BackendVertexAI,
+			apiVersion:   "v1beta1",
+			path:         "projects/p/locations/europe-west9/publishers/google/models/gemini-2.5-flash:generateContent",
+			statusCode:   http.StatusNotFound,
+			responseBody: `{"error": {"code": 404, "message": "Publisher Model was not found or your project does not have access to it.", "status": "NOT_FOUND"}}`,
+			wantContains: []string{"Backend: BackendVertexAI", "Model: gemini-2.5-flash", "Hint: the model may not be available in the configured location"},
+		},
+		{
+			name:         "rate limited",
+			backend:      BackendGeminiAPI,
+			apiVersion:   "v1beta",
+			path:         "models/gemini-2.5-flash:generateContent",
+			statusCode:   http.StatusTooManyRequests,
+			responseBody: `{"error": {"code": 429, "message": "quota exceeded", "status": "RESOURCE_EXHAUSTED"}}`,
+			wantContains: []string{"Hint: the request was rate limited"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(tt.statusCode)
+				fmt.Fprintln(w, tt.responseBody)
+			}))
+			defer ts.Close()
+
+			ac := &apiClient{
+				clientConfig: &ClientConfig{
+					Backend:     tt.backend,
+					APIKey:      "test-api-key",
+					HTTPOptions: HTTPOptions{BaseURL: ts.URL, APIVersion: tt.apiVersion},
+					HTTPClient:  ts.Client(),
+				},
+			}
+
+			_, err := sendRequest(context.Background(), ac, tt.path, http.MethodPost, map[string]any{}, &HTTPOptions{})
+			apiErr, ok := err.(APIError)
+			if !ok {
+				t.Fatalf("sendRequest() error = %T(%v), want APIError", err, err)
+			}
+			if apiErr.Backend != tt.backend {
+				t.Errorf("APIError.Backend = %v, want %v", apiErr.Backend, tt.backend)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(apiErr.Error(), want) {
+					t.Errorf("APIError.Error() = %q, want it to contain %q", apiErr.Error(), want)
+				}
+			}
+			for _, absent := range tt.wantAbsent {
+				if strings.Contains(apiErr.Error(), absent) {
+					t.Errorf("APIError.Error() = %q, want it to not contain %q", apiErr.Error(), absent)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// AutoUploadOptions configures the upload of the inline data parts of the
+// contents of GenerateContent calls that are larger than a threshold, so that
+// large media does not exceed the request size limit. The parts are sent as
+// file data parts that refer to the uploads instead. Set them with
+// RequestOptions.AutoUpload.
+type AutoUploadOptions struct {
+	// The size in bytes above which inline data is uploaded. Zero sends all
+	// inline data as is.
+	Threshold int64
+	// Optional. Uploads the inline data. Defaults to the Files API on the
+	// Gemini API. Required on Vertex AI, e.g. to stage the data in Cloud
+	// Storage.
+	Stager MediaStager
+	// Optional. Whether to delete the uploaded data once the call, or its
+	// stream, has ended.
+	Cleanup bool
+}
+
+// MediaStager uploads inline data that is too large to be sent in a request,
+// see [AutoUploadOptions].
+type MediaStager interface {
+	// Stage uploads blob and returns the file data that refers to it in a
+	// request.
+	Stage(ctx context.Context, blob *Blob) (*FileData, error)
+	// Delete deletes data returned by Stage.
+	Delete(ctx context.Context, data *FileData) error
+}
+
+// NewFilesMediaStager returns a MediaStager that uploads the data with the
+// Files API and waits until it is processed. It is the default on the Gemini
+// API.
+func NewFilesMediaStager(files *Files) MediaStager {
+	return filesMediaStager{files: *files}
+}
+
+type filesMediaStager struct {
+	files Files
+}
+
+func (s filesMediaStager) Stage(ctx context.Context, blob *Blob) (*FileData, error) {
+	file, err := s.files.Upload(ctx, bytes.NewReader(blob.Data), &UploadFileConfig{MIMEType: blob.MIMEType, DisplayName: blob.DisplayName})
+	if err != nil {
+		return nil, err
+	}
+	if file.State == FileStateProcessing {
+		if file, err = s.files.WaitForActive(ctx, file.Name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return &FileData{FileURI: file.URI, MIMEType: blob.MIMEType}, nil
+}
+
+func (s filesMediaStager) Delete(ctx context.Context, data *FileData) error {
+	_, err := s.files.Delete(ctx, data.FileURI, nil)
+	return err
+}
+
+// autoUploadHook uploads the inline data parts of the contents of a
+// generateContent call that are larger than the AutoUpload threshold of the
+// RequestOptions, and replaces them with file data parts that refer to the
+// uploads. The uploads are deleted once the call has ended if
+// AutoUploadOptions.Cleanup is set.
+func (ac *apiClient) autoUploadHook(ctx context.Context, call *hookedCall) error {
+	options := ac.requestOptions(ctx).AutoUpload
+	if options == nil || options.Threshold <= 0 || call.body == nil || !call.isMethod("generateContent", "streamGenerateContent") {
+		return nil
+	}
+	stager := options.Stager
+	if stager == nil {
+		if ac.clientConfig.Backend == BackendVertexAI {
+			return errors.New("AutoUploadOptions.Threshold requires an AutoUploadOptions.Stager on Vertex AI, e.g. one that stages the data in Cloud Storage")
+		}
+		stager = filesMediaStager{files: Files{apiClient: ac}}
+	}
+	var staged []*FileData
+	if options.Cleanup {
+		call.onDone = append(call.onDone, func() {
+			for _, data := range staged {
+				if err := stager.Delete(context.WithoutCancel(ctx), data); err != nil {
+					log.Printf("Warning: failed to delete the uploaded inline data %s: %v", data.FileURI, err)
+				}
+			}
+		})
+	}
+	// Data that occurs more than once, e.g. in the history of a chat, is
+	// uploaded once.
+	uploads := map[string]*FileData{}
+	for _, content := range objectsOf(call.body["contents"]) {
+		for _, part := range objectsOf(content["parts"]) {
+			inlineData, ok := part["inlineData"].(map[string]any)
+			if !ok {
+				continue
+			}
+			encoded, _ := inlineData["data"].(string)
+			if int64(base64.StdEncoding.DecodedLen(len(encoded))) <= options.Threshold {
+				continue
+			}
+			data, ok := uploads[encoded]
+			if !ok {
+				blob := &Blob{}
+				if err := mapToStruct(inlineData, blob); err != nil {
+					return err
+				}
+				if int64(len(blob.Data)) <= options.Threshold {
+					continue
+				}
+				var err error
+				if data, err = stager.Stage(ctx, blob); err != nil {
+					return fmt.Errorf("failed to upload inline data of %d bytes: %w", len(blob.Data), err)
+				}
+				uploads[encoded] = data
+				staged = append(staged, data)
+			}
+			delete(part, "inlineData")
+			part["fileData"] = map[string]any{"fileUri": data.FileURI, "mimeType": data.MIMEType}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type recordingStager struct {
+	staged  []*Blob
+	deleted []string
+}
+
+func (s *recordingStager) Stage(ctx context.Context, blob *Blob) (*FileData, error) {
+	s.staged = append(s.staged, blob)
+	return &FileData{FileURI: fmt.Sprintf("gs://bucket/%d", len(s.staged)), MIMEType: blob.MIMEType}, nil
+}
+
+func (s *recordingStager) Delete(ctx context.Context, data *FileData) error {
+	s.deleted = append(s.deleted, data.FileURI)
+	return nil
+}
+
+func TestGenerateContentAutoUpload(t *testing.T) {
+	ctx := context.Background()
+	var requests []map[string]any
+	var uploaded []byte
+	var deleted []string
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/upload/v1beta/files":
+			w.Header().Set("X-Goog-Upload-URL", "http://"+r.Host+"/upload-session/1")
+		case r.URL.Path == "/upload-session/1":
+			uploaded, _ = io.ReadAll(r.Body)
+			w.Header().Set("X-Goog-Upload-Status", "final")
+			fmt.Fprint(w, `{"file": {"name": "files/big", "uri": "https://generativelanguage.googleapis.com/v1beta/files/big", "state": "ACTIVE"}}`)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			fmt.Fprint(w, `{}`)
+		default:
+			var req map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Error(err)
+			}
+			requests = append(requests, req)
+			response := `{"candidates": [{"content": {"role": "model", "parts": [{"text": "a cat"}]}, "finishReason": "STOP"}]}`
+			if strings.Contains(r.URL.Path, ":streamGenerateContent") {
+				response = "data: " + response + "\n\n"
+			}
+			fmt.Fprint(w, response)
+		}
+	})
+	video := bytes.Repeat([]byte{1}, 100)
+	contents := []*Content{NewContentFromParts([]*Part{
+		NewPartFromText("What is in this video and this image?"),
+		NewPartFromBytes(video, "video/mp4"),
+		NewPartFromBytes([]byte{2, 3}, "image/png"),
+	}, RoleUser)}
+	original := cloneContents(contents)
+
+	t.Run("Files API", func(t *testing.T) {
+		ctx := WithRequestOptions(ctx, &RequestOptions{AutoUpload: &AutoUploadOptions{Threshold: 10, Cleanup: true}})
+		if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", contents, nil); err != nil {
+			t.Fatalf("GenerateContent() failed: %v", err)
+		}
+		want := []*Content{NewContentFromParts([]*Part{
+			NewPartFromText("What is in this video and this image?"),
+			NewPartFromURI("https://generativelanguage.googleapis.com/v1beta/files/big", "video/mp4"),
+			NewPartFromBytes([]byte{2, 3}, "image/png"),
+		}, RoleUser)}
+		if diff := cmp.Diff(want, requestContents(t, requests[len(requests)-1])); diff != "" {
+			t.Errorf("sent contents mismatch (-want +got):\n%s", diff)
+		}
+		if !bytes.Equal(uploaded, video) {
+			t.Errorf("uploaded %v, want the video", uploaded)
+		}
+		if diff := cmp.Diff([]string{"/v1beta/files/big"}, deleted); diff != "" {
+			t.Errorf("deleted files mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(original, contents); diff != "" {
+			t.Errorf("GenerateContent() modified the contents (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("custom stager", func(t *testing.T) {
+		stager := &recordingStager{}
+		ctx := WithRequestOptions(ctx, &RequestOptions{AutoUpload: &AutoUploadOptions{Threshold: 1, Stager: stager, Cleanup: true}})
+		// The same blob twice, e.g. in the history of a chat, is staged once.
+		twice := []*Content{contents[0], contents[0]}
+		for _, err := range client.Models.GenerateContentStream(ctx, "gemini-2.5-flash", twice, nil) {
+			if err != nil {
+				t.Fatalf("GenerateContentStream() failed: %v", err)
+			}
+		}
+		if len(stager.staged) != 2 {
+			t.Errorf("staged %d blobs, want the 2 blobs above the threshold", len(stager.staged))
+		}
+		if diff := cmp.Diff([]string{"gs://bucket/1", "gs://bucket/2"}, stager.deleted); diff != "" {
+			t.Errorf("deleted mismatch (-want +got):\n%s", diff)
+		}
+		sent := requestContents(t, requests[len(requests)-1])
+		if len(sent) != 2 || sent[1].Parts[2].FileData == nil || sent[1].Parts[2].FileData.FileURI != "gs://bucket/2" {
+			t.Errorf("sent contents = %+v, want the staged blobs", sent)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// EmbeddingResults returns the embeddings of the completed embeddings batch
+// job created with CreateEmbeddings. They are read from the inlined responses
+// of the job, in the order of its contents, or by downloading its result file
+// with the Files service, in the order of the file, which need not be that of
+// the contents; the Key of a *BatchResponseError then identifies the request.
+//
+// The embedding of a request that failed is nil, and the returned error joins
+// a *BatchResponseError for every failed request. An error that prevents
+// reading the results is returned with nil embeddings.
+func (b Batches) EmbeddingResults(ctx context.Context, job *BatchJob) ([]*ContentEmbedding, error) {
+	var responses []*InlinedEmbedContentResponse
+	var keys []string
+	switch dest := job.Dest; {
+	case dest != nil && len(dest.InlinedEmbedContentResponses) > 0:
+		responses = dest.InlinedEmbedContentResponses
+	case dest != nil && dest.FileName != "":
+		for data, err := range b.resultFileLines(ctx, dest.FileName) {
+			if err != nil {
+				return nil, err
+			}
+			var line struct {
+				Key string `json:"key"`
+				InlinedEmbedContentResponse
+			}
+			if err := json.Unmarshal(data, &line); err != nil {
+				return nil, fmt.Errorf("invalid result line %d of %s: %w", len(responses), dest.FileName, err)
+			}
+			responses = append(responses, &line.InlinedEmbedContentResponse)
+			keys = append(keys, line.Key)
+		}
+	default:
+		return nil, fmt.Errorf("batch job %s has no embedding results, its state is %s", job.Name, job.State)
+	}
+
+	embeddings := make([]*ContentEmbedding, len(responses))
+	var errs []error
+	for i, response := range responses {
+		var key string
+		if keys != nil {
+			key = keys[i]
+		}
+		switch {
+		case response.Error != nil:
+			errs = append(errs, &BatchResponseError{Index: i, Key: key, Err: response.Error})
+		case response.Response == nil || response.Response.Embedding == nil:
+			errs = append(errs, &BatchResponseError{Index: i, Key: key, Err: &JobError{Message: "no embedding"}})
+		default:
+			embeddings[i] = response.Response.Embedding
+		}
+	}
+	return embeddings, errors.Join(errs...)
+}
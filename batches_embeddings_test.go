@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBatchesEmbeddingResults(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1beta/batches/inline":
+			fmt.Fprint(w, `{"name": "batches/inline", "metadata": {"state": "BATCH_STATE_SUCCEEDED", "output": {"inlinedEmbedContentResponses": {"inlinedResponses": [
+				{"response": {"embedding": {"values": [1, 2]}, "tokenCount": "3"}},
+				{"error": {"code": 3, "message": "empty content"}}
+			]}}}}`)
+		case "/v1beta/files/embeddings:download":
+			fmt.Fprintln(w, `{"key": "a", "response": {"embedding": {"values": [3, 4]}}}`)
+			fmt.Fprintln(w, `{"key": "b", "response": {"embedding": {"values": [5, 6]}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	job, err := client.Batches.Get(ctx, "batches/inline", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	embeddings, err := client.Batches.EmbeddingResults(ctx, job)
+	var respErr *BatchResponseError
+	if !errors.As(err, &respErr) || respErr.Index != 1 || respErr.Err.Message != "empty content" {
+		t.Errorf("EmbeddingResults() error = %v, want a *BatchResponseError for the second request", err)
+	}
+	if diff := cmp.Diff([]*ContentEmbedding{{Values: []float32{1, 2}}, nil}, embeddings); diff != "" {
+		t.Errorf("EmbeddingResults() mismatch (-want +got):\n%s", diff)
+	}
+
+	file := &BatchJob{Name: "batches/file", Dest: &BatchJobDestination{FileName: "files/embeddings"}}
+	embeddings, err = client.Batches.EmbeddingResults(ctx, file)
+	if err != nil {
+		t.Fatalf("EmbeddingResults() failed: %v", err)
+	}
+	if diff := cmp.Diff([]*ContentEmbedding{{Values: []float32{3, 4}}, {Values: []float32{5, 6}}}, embeddings); diff != "" {
+		t.Errorf("EmbeddingResults() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := client.Batches.EmbeddingResults(ctx, &BatchJob{Name: "batches/running", State: JobStateRunning}); err == nil {
+		t.Error("EmbeddingResults() of a job without results succeeded, want an error")
+	}
+}
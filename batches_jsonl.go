@@ -0,0 +1,154 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// The input file of a batch job of the Gemini API has a JSON object per line
+// with the key of a request and the request in the format of the REST API,
+// e.g. {"key": "request-1", "request": {"contents": [...], "generationConfig":
+// {...}}}. The result file has a line per request with its key and its
+// response or error.
+
+// batchFileAPIClient converts the requests written by a BatchRequestWriter.
+// File-based batch jobs are only supported by the Gemini API.
+var batchFileAPIClient = &apiClient{clientConfig: &ClientConfig{Backend: BackendGeminiAPI}}
+
+// BatchRequestWriter writes the requests of a batch job in the JSONL format
+// of the input file of a batch job of the Gemini API. Submit the written file
+// with Batches.CreateFromJSONL and read its results with ReadBatchResults.
+type BatchRequestWriter struct {
+	w    io.Writer
+	keys map[string]bool
+}
+
+// NewBatchRequestWriter returns a BatchRequestWriter that writes to w.
+func NewBatchRequestWriter(w io.Writer) *BatchRequestWriter {
+	return &BatchRequestWriter{w: w, keys: map[string]bool{}}
+}
+
+// Write writes request as a line that has key, which identifies the response
+// of the request in the result file. Keys must be unique. An empty key is
+// replaced by "request-N", where N is the number of requests written before.
+// request.Metadata is not supported in files; use the key instead.
+func (bw *BatchRequestWriter) Write(key string, request *GenerateContentRequest) error {
+	if key == "" {
+		key = fmt.Sprintf("request-%d", len(bw.keys))
+	}
+	switch {
+	case bw.keys[key]:
+		return fmt.Errorf("duplicate request key %q", key)
+	case request == nil || len(request.Contents) == 0:
+		return fmt.Errorf("request %q has no contents", key)
+	case len(request.Metadata) > 0:
+		return fmt.Errorf("request %q has metadata, which is not supported in batch files; use the key instead", key)
+	}
+	fromObject := make(map[string]any)
+	if err := deepMarshal(&InlinedRequest{Contents: request.Contents, Config: request.Config}, &fromObject); err != nil {
+		return err
+	}
+	toObject, err := inlinedRequestToMldev(batchFileAPIClient, fromObject, nil, fromObject)
+	if err != nil {
+		return fmt.Errorf("failed to convert request %q: %w", key, err)
+	}
+	line, err := json.Marshal(map[string]any{"key": key, "request": toObject["request"]})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request %q: %w", key, err)
+	}
+	if _, err := bw.w.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	bw.keys[key] = true
+	return nil
+}
+
+// Count returns the number of written requests.
+func (bw *BatchRequestWriter) Count() int {
+	return len(bw.keys)
+}
+
+// CreateFromJSONL uploads the batch input file read from r, e.g. one written
+// by a BatchRequestWriter, with the Files service and creates a batch job of
+// model that reads it. The file is uploaded with config.DisplayName.
+func (b Batches) CreateFromJSONL(ctx context.Context, model string, r io.Reader, config *CreateBatchJobConfig) (*BatchJob, error) {
+	if b.apiClient.clientConfig.Backend == BackendVertexAI {
+		return nil, errors.New("method CreateFromJSONL is only supported in the Gemini Developer client. On Vertex AI, write the file to Cloud Storage and call Create.")
+	}
+	upload := &UploadFileConfig{MIMEType: "application/jsonl"}
+	if config != nil {
+		upload.DisplayName = config.DisplayName
+	}
+	file, err := Files{apiClient: b.apiClient}.Upload(ctx, r, upload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload the batch input file: %w", err)
+	}
+	return b.Create(ctx, model, &BatchJobSource{FileName: file.Name}, config)
+}
+
+// BatchResult is the result of a request of a batch job read from its result
+// file.
+type BatchResult struct {
+	// The key of the request.
+	Key string
+	// The response of the request, if it succeeded.
+	Response *GenerateContentResponse
+	// The *BatchResponseError of the request, if it failed.
+	Err error
+}
+
+// ReadBatchResults returns an iterator over the results read from r, the
+// result file of a batch job, in the order of the file. It yields an error
+// and stops if the file cannot be read or parsed.
+func ReadBatchResults(r io.Reader) iter.Seq2[*BatchResult, error] {
+	return keyedBatchResults(readBatchResults(r))
+}
+
+// ResultsByKey downloads the result file of the completed batch job and
+// returns its results by the key of their request.
+func (b Batches) ResultsByKey(ctx context.Context, job *BatchJob) (map[string]*BatchResult, error) {
+	if job.Dest == nil || job.Dest.FileName == "" {
+		return nil, fmt.Errorf("batch job %s has no result file, its state is %s", job.Name, job.State)
+	}
+	results := map[string]*BatchResult{}
+	for result, err := range keyedBatchResults(b.resultFile(ctx, job.Dest.FileName)) {
+		if err != nil {
+			return nil, err
+		}
+		results[result.Key] = result
+	}
+	return results, nil
+}
+
+func keyedBatchResults(lines iter.Seq2[int, batchResultLine]) iter.Seq2[*BatchResult, error] {
+	return func(yield func(*BatchResult, error) bool) {
+		for i, line := range lines {
+			if line.err != nil {
+				yield(nil, line.err)
+				return
+			}
+			resp, err := batchResult(i, line.key, line.response)
+			if !yield(&BatchResult{Key: line.key, Response: resp, Err: err}, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBatchRequestWriter(t *testing.T) {
+	var sb strings.Builder
+	bw := NewBatchRequestWriter(&sb)
+	if err := bw.Write("greeting", &GenerateContentRequest{
+		Contents: Text("Hello"),
+		Config:   &GenerateContentConfig{Temperature: Float32(0), SystemInstruction: NewContentFromText("Be brief.", RoleUser)},
+	}); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if err := bw.Write("", &GenerateContentRequest{Contents: Text("World")}); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if err := bw.Write("greeting", &GenerateContentRequest{Contents: Text("Again")}); err == nil {
+		t.Error("Write() with a duplicate key succeeded, want an error")
+	}
+	if err := bw.Write("meta", &GenerateContentRequest{Contents: Text("Hi"), Metadata: map[string]string{"a": "b"}}); err == nil {
+		t.Error("Write() with metadata succeeded, want an error")
+	}
+	if bw.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", bw.Count())
+	}
+
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(sb.String()), "\n") {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+		lines = append(lines, m)
+	}
+	want := []map[string]any{
+		{"key": "greeting", "request": map[string]any{
+			"contents":          []any{map[string]any{"role": "user", "parts": []any{map[string]any{"text": "Hello"}}}},
+			"generationConfig":  map[string]any{"temperature": float64(0)},
+			"systemInstruction": map[string]any{"role": "user", "parts": []any{map[string]any{"text": "Be brief."}}},
+		}},
+		{"key": "request-1", "request": map[string]any{
+			"contents": []any{map[string]any{"role": "user", "parts": []any{map[string]any{"text": "World"}}}},
+		}},
+	}
+	if diff := cmp.Diff(want, lines); diff != "" {
+		t.Errorf("written lines mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBatchesCreateFromJSONL(t *testing.T) {
+	var uploaded, source string
+	var client *Client
+	client = newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/upload/v1beta/files":
+			w.Header().Set("X-Goog-Upload-URL", client.clientConfig.HTTPOptions.BaseURL+"/upload-session")
+		case r.URL.Path == "/upload-session":
+			data, _ := io.ReadAll(r.Body)
+			uploaded = string(data)
+			w.Header().Set("X-Goog-Upload-Status", "final")
+			fmt.Fprint(w, `{"file": {"name": "files/input"}}`)
+		case strings.HasSuffix(r.URL.Path, ":batchGenerateContent"):
+			data, _ := io.ReadAll(r.Body)
+			source = string(data)
+			fmt.Fprint(w, `{"name": "batches/1"}`)
+		case r.URL.Path == "/v1beta/files/results:download":
+			fmt.Fprintln(w, `{"key": "a", "response": {"candidates": [{"content": {"parts": [{"text": "one"}]}}]}}`)
+			fmt.Fprintln(w, `{"key": "b", "error": {"code": 3, "message": "invalid"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	input := `{"key": "a", "request": {"contents": [{"parts": [{"text": "Hi"}]}]}}` + "\n"
+	job, err := client.Batches.CreateFromJSONL(context.Background(), "gemini-2.5-flash", strings.NewReader(input), nil)
+	if err != nil {
+		t.Fatalf("CreateFromJSONL() failed: %v", err)
+	}
+	if job.Name != "batches/1" || uploaded != input || !strings.Contains(source, `"files/input"`) {
+		t.Errorf("CreateFromJSONL() = %+v after uploading %q and sending %s", job, uploaded, source)
+	}
+
+	results, err := client.Batches.ResultsByKey(context.Background(), &BatchJob{Dest: &BatchJobDestination{FileName: "files/results"}})
+	if err != nil {
+		t.Fatalf("ResultsByKey() failed: %v", err)
+	}
+	if len(results) != 2 || results["a"].Response.Text() != "one" {
+		t.Errorf("ResultsByKey() = %+v, want 2 results", results)
+	}
+	var respErr *BatchResponseError
+	if !errors.As(results["b"].Err, &respErr) || respErr.Key != "b" || respErr.Err.Message != "invalid" {
+		t.Errorf("result b error = %v, want a *BatchResponseError", results["b"].Err)
+	}
+}
+
+func TestReadBatchResults(t *testing.T) {
+	file := `{"key": "a", "response": {"candidates": [{"content": {"parts": [{"text": "one"}]}}]}}
+
+{"key": "b", "response": {"candidates": [{"content": {"parts": [{"text": "two"}]}}]}}
+not json
+`
+	var keys []string
+	var err error
+	for result, e := range ReadBatchResults(strings.NewReader(file)) {
+		if e != nil {
+			err = e
+			break
+		}
+		keys = append(keys, result.Key+"="+result.Response.Text())
+	}
+	if diff := cmp.Diff([]string{"a=one", "b=two"}, keys); diff != "" {
+		t.Errorf("ReadBatchResults() mismatch (-want +got):\n%s", diff)
+	}
+	if err == nil {
+		t.Error("ReadBatchResults() of an invalid line succeeded, want an error")
+	}
+}
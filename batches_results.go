@@ -0,0 +1,236 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+)
+
+// GenerateContentRequest is a request of a batch job, with the arguments of
+// Models.GenerateContent other than the model, which is the model of the
+// batch job.
+type GenerateContentRequest struct {
+	// Required. The contents of the request.
+	Contents []*Content `json:"contents,omitempty"`
+	// Optional. The config of the request.
+	Config *GenerateContentConfig `json:"config,omitempty"`
+	// Optional. The metadata of the request, which is returned with its
+	// response.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// BatchResponseError is the error of a request of a batch job that failed.
+type BatchResponseError struct {
+	// The index of the response: the position of the request in the batch
+	// for inlined responses, or the line of the response in the result file,
+	// whose order need not be that of the requests.
+	Index int
+	// The key of the request, which identifies it for the results read from a
+	// file.
+	Key string
+	// The metadata of the request, if any.
+	Metadata map[string]string
+	// The error returned for the request.
+	Err *JobError
+}
+
+// Error returns a string representation of the BatchResponseError.
+func (e *BatchResponseError) Error() string {
+	code := int32(0)
+	if e.Err.Code != nil {
+		code = *e.Err.Code
+	}
+	if e.Key != "" {
+		return fmt.Sprintf("request %q of the batch failed with code %d: %s", e.Key, code, e.Err.Message)
+	}
+	return fmt.Sprintf("request %d of the batch failed with code %d: %s", e.Index, code, e.Err.Message)
+}
+
+// CreateFromRequests creates a batch job of model with requests inlined in the
+// batch. Inlined requests are only supported by the Gemini API; on Vertex AI,
+// write the requests to Cloud Storage or BigQuery and call Create.
+func (b Batches) CreateFromRequests(ctx context.Context, model string, requests []*GenerateContentRequest, config *CreateBatchJobConfig) (*BatchJob, error) {
+	src := &BatchJobSource{InlinedRequests: make([]*InlinedRequest, len(requests))}
+	for i, request := range requests {
+		if request == nil || len(request.Contents) == 0 {
+			return nil, fmt.Errorf("request %d has no contents", i)
+		}
+		src.InlinedRequests[i] = &InlinedRequest{Contents: request.Contents, Config: request.Config, Metadata: request.Metadata}
+	}
+	return b.Create(ctx, model, src, config)
+}
+
+// Results returns an iterator over the responses of the requests of the
+// completed batch job. The responses are read from job.Dest: from the inlined
+// responses, in the order of the requests, or by downloading the result file
+// with the Files service, in the order of the file, which need not be that of
+// the requests; use ResultsByKey to match them with their requests. A request
+// that failed yields its *BatchResponseError, whose Key identifies the request
+// of a result file, and the iteration continues with the next request.
+//
+// The responses of batch jobs whose destination is in Cloud Storage or
+// BigQuery cannot be read by Results, which yields an error.
+func (b Batches) Results(ctx context.Context, job *BatchJob) iter.Seq2[*GenerateContentResponse, error] {
+	return func(yield func(*GenerateContentResponse, error) bool) {
+		dest := job.Dest
+		switch {
+		case dest == nil:
+			yield(nil, fmt.Errorf("batch job %s has no results, its state is %s", job.Name, job.State))
+		case len(dest.InlinedResponses) > 0:
+			for i, response := range dest.InlinedResponses {
+				if !yield(batchResult(i, "", response)) {
+					return
+				}
+			}
+		case dest.FileName != "":
+			for i, line := range b.resultFile(ctx, dest.FileName) {
+				if line.err != nil {
+					yield(nil, line.err)
+					return
+				}
+				if !yield(batchResult(i, line.key, line.response)) {
+					return
+				}
+			}
+		case dest.GCSURI != "" || dest.BigqueryURI != "":
+			yield(nil, fmt.Errorf("the results of batch job %s are in %s%s, which cannot be read by the SDK", job.Name, dest.GCSURI, dest.BigqueryURI))
+		default:
+			yield(nil, fmt.Errorf("batch job %s has no results, its state is %s", job.Name, job.State))
+		}
+	}
+}
+
+// batchResult returns the response or the error of the i-th response of a
+// batch job, the i-th inlined response or the i-th line of its result file,
+// whose request has key if it was read from a file.
+func batchResult(i int, key string, response *InlinedResponse) (*GenerateContentResponse, error) {
+	switch {
+	case response == nil:
+		return nil, &BatchResponseError{Index: i, Key: key, Err: &JobError{Message: "no response"}}
+	case response.Error != nil:
+		return nil, &BatchResponseError{Index: i, Key: key, Metadata: response.Metadata, Err: response.Error}
+	case response.Response == nil:
+		return nil, &BatchResponseError{Index: i, Key: key, Metadata: response.Metadata, Err: &JobError{Message: "no response"}}
+	}
+	return response.Response, nil
+}
+
+// batchResultLine is a line of the result file of a batch job.
+type batchResultLine struct {
+	key      string
+	response *InlinedResponse
+	err      error
+}
+
+// resultFile returns an iterator over the parsed lines of the result file
+// name of a batch job, which is downloaded as it is read.
+func (b Batches) resultFile(ctx context.Context, name string) iter.Seq2[int, batchResultLine] {
+	return batchResultLines(b.resultFileLines(ctx, name))
+}
+
+// readBatchResults returns an iterator over the JSONL result lines read from
+// r. Each line has the key of the request and its response or error, in the
+// format of the Gemini API.
+func readBatchResults(r io.Reader) iter.Seq2[int, batchResultLine] {
+	return batchResultLines(readJSONLines(r))
+}
+
+// batchResultLines parses lines as result lines and yields them with their
+// index. The iteration stops at the first error.
+func batchResultLines(lines iter.Seq2[[]byte, error]) iter.Seq2[int, batchResultLine] {
+	return func(yield func(int, batchResultLine) bool) {
+		i := 0
+		for data, err := range lines {
+			line := batchResultLine{err: err}
+			if err == nil {
+				line = parseBatchResultLine(data)
+			}
+			if !yield(i, line) || line.err != nil {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// resultFileLines returns an iterator over the lines of the result file name
+// of a batch job, which is downloaded as it is read.
+func (b Batches) resultFileLines(ctx context.Context, name string) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		r, w := io.Pipe()
+		defer r.Close()
+		go func() {
+			files := Files{apiClient: b.apiClient}
+			_, err := files.DownloadTo(ctx, &File{DownloadURI: name}, w, nil)
+			w.CloseWithError(err)
+		}()
+		for line, err := range readJSONLines(r) {
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to read the result file %s: %w", name, err))
+				return
+			}
+			if !yield(line, nil) {
+				return
+			}
+		}
+	}
+}
+
+// readJSONLines returns an iterator over the non-blank lines read from r. It
+// yields an error and stops if r cannot be read.
+func readJSONLines(r io.Reader) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		br := bufio.NewReader(r)
+		for {
+			data, err := br.ReadBytes('\n')
+			if len(bytes.TrimSpace(data)) > 0 && !yield(data, nil) {
+				return
+			}
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}
+}
+
+func parseBatchResultLine(data []byte) batchResultLine {
+	var fromObject map[string]any
+	if err := json.Unmarshal(data, &fromObject); err != nil {
+		return batchResultLine{err: fmt.Errorf("invalid result line: %w", err)}
+	}
+	toObject, err := inlinedResponseFromMldev(fromObject, nil, nil)
+	if err != nil {
+		return batchResultLine{err: err}
+	}
+	line := batchResultLine{response: &InlinedResponse{}}
+	if err := mapToStruct(toObject, line.response); err != nil {
+		return batchResultLine{err: err}
+	}
+	line.key, _ = fromObject["key"].(string)
+	return line
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// collectBatchResults returns the texts of the responses of job, with "error"
+// and the key for the requests that failed.
+func collectBatchResults(t *testing.T, client *Client, job *BatchJob) []string {
+	t.Helper()
+	var got []string
+	for resp, err := range client.Batches.Results(context.Background(), job) {
+		var respErr *BatchResponseError
+		switch {
+		case errors.As(err, &respErr):
+			got = append(got, fmt.Sprintf("error %d %s: %s", respErr.Index, respErr.Key, respErr.Err.Message))
+		case err != nil:
+			t.Fatalf("Results() failed: %v", err)
+		default:
+			got = append(got, resp.Text())
+		}
+	}
+	return got
+}
+
+func TestBatchesCreateFromRequests(t *testing.T) {
+	var body map[string]any
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"name": "batches/1"}`)
+	})
+	job, err := client.Batches.CreateFromRequests(context.Background(), "gemini-2.5-flash", []*GenerateContentRequest{
+		{Contents: Text("Hello")},
+		{Contents: Text("World"), Config: &GenerateContentConfig{Temperature: Float32(0)}, Metadata: map[string]string{"id": "2"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("CreateFromRequests() failed: %v", err)
+	}
+	if job.Name != "batches/1" {
+		t.Errorf("CreateFromRequests() = %+v, want batches/1", job)
+	}
+	data, _ := json.Marshal(body)
+	if !strings.Contains(string(data), `"World"`) || !strings.Contains(string(data), `"temperature":0`) || !strings.Contains(string(data), `"id":"2"`) {
+		t.Errorf("request = %s, want the inlined requests", data)
+	}
+
+	if _, err := client.Batches.CreateFromRequests(context.Background(), "gemini-2.5-flash", []*GenerateContentRequest{{}}, nil); err == nil {
+		t.Error("CreateFromRequests() of an empty request succeeded, want an error")
+	}
+}
+
+func TestBatchesResults(t *testing.T) {
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/files/results:download" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, `{"key": "a", "response": {"candidates": [{"content": {"parts": [{"text": "one"}]}}]}}`)
+		fmt.Fprintln(w, `{"key": "b", "error": {"code": 3, "message": "invalid"}}`)
+		fmt.Fprint(w, `{"key": "c", "response": {"candidates": [{"content": {"parts": [{"text": "three"}]}}]}}`)
+	})
+
+	inline := &BatchJob{Name: "batches/1", Dest: &BatchJobDestination{InlinedResponses: []*InlinedResponse{
+		{Response: &GenerateContentResponse{Candidates: []*Candidate{{Content: NewContentFromText("one", RoleModel)}}}},
+		{Error: &JobError{Code: Ptr[int32](3), Message: "invalid"}},
+	}}}
+	want := []string{"one", "error 1 : invalid"}
+	if got := collectBatchResults(t, client, inline); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("inline Results() = %q, want %q", got, want)
+	}
+
+	file := &BatchJob{Name: "batches/2", Dest: &BatchJobDestination{FileName: "files/results"}}
+	want = []string{"one", "error 1 b: invalid", "three"}
+	if got := collectBatchResults(t, client, file); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("file Results() = %q, want %q", got, want)
+	}
+
+	for _, job := range []*BatchJob{
+		{Name: "batches/3", State: JobStateRunning},
+		{Name: "batches/4", Dest: &BatchJobDestination{BigqueryURI: "bq://p.d.t"}},
+		{Name: "batches/5", Dest: &BatchJobDestination{FileName: "files/missing"}},
+	} {
+		var err error
+		for _, err = range client.Batches.Results(context.Background(), job) {
+		}
+		if err == nil {
+			t.Errorf("Results(%s) succeeded, want an error", job.Name)
+		}
+	}
+}
+
+func TestBatchResponseErrorError(t *testing.T) {
+	inlined := &BatchResponseError{Index: 1, Err: &JobError{Code: Ptr(int32(400)), Message: "bad request"}}
+	if got, want := inlined.Error(), "request 1 of the batch failed with code 400: bad request"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	fromFile := &BatchResponseError{Index: 0, Key: "request-7", Err: &JobError{Code: Ptr(int32(400)), Message: "bad request"}}
+	if got, want := fromFile.Error(), `request "request-7" of the batch failed with code 400: bad request`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrBatchJobFailed is matched by errors.Is for a *BatchJobError of a
+	// batch job that failed.
+	ErrBatchJobFailed = errors.New("batch job failed")
+	// ErrBatchJobExpired is matched by errors.Is for a *BatchJobError of a
+	// batch job that expired before it completed.
+	ErrBatchJobExpired = errors.New("batch job expired")
+	// ErrBatchJobCancelled is matched by errors.Is for a *BatchJobError of a
+	// batch job that was cancelled.
+	ErrBatchJobCancelled = errors.New("batch job cancelled")
+)
+
+// WaitBatchConfig configures Batches.Wait.
+type WaitBatchConfig struct {
+	// Optional. The delay before the second poll. Defaults to 1 second.
+	InitialInterval time.Duration
+	// Optional. The maximum delay between two polls. Defaults to 30 seconds.
+	MaxInterval time.Duration
+	// Optional. The factor the delay grows by after every poll. Defaults to 2.
+	Multiplier float64
+	// Optional. How long to wait for the batch job to finish. If it is
+	// exceeded, Wait returns a *BatchWaitTimeoutError and the job keeps
+	// running. If zero, Wait waits until ctx is done.
+	Timeout time.Duration
+	// Optional. Called with every polled batch job and its progress.
+	OnPoll func(job *BatchJob, progress BatchProgress)
+	// Optional. Used for every call to Batches.Get.
+	HTTPOptions *HTTPOptions
+}
+
+// BatchJobError is returned by Batches.Wait for a batch job that finished
+// without succeeding. It matches ErrBatchJobFailed, ErrBatchJobExpired or
+// ErrBatchJobCancelled with errors.Is.
+type BatchJobError struct {
+	// Job is the finished batch job.
+	Job *BatchJob
+}
+
+// Error returns a string representation of the BatchJobError.
+func (e *BatchJobError) Error() string {
+	if e.Job.Error != nil && e.Job.Error.Message != "" {
+		return fmt.Sprintf("batch job %s finished with state %s: %s", e.Job.Name, e.Job.State, e.Job.Error.Message)
+	}
+	return fmt.Sprintf("batch job %s finished with state %s", e.Job.Name, e.Job.State)
+}
+
+// Is reports whether target is the error of the state of the job.
+func (e *BatchJobError) Is(target error) bool {
+	switch e.Job.State {
+	case JobStateFailed:
+		return target == ErrBatchJobFailed
+	case JobStateExpired:
+		return target == ErrBatchJobExpired
+	case JobStateCancelled:
+		return target == ErrBatchJobCancelled
+	}
+	return false
+}
+
+// BatchWaitTimeoutError is returned by Batches.Wait when the batch job did not
+// finish within WaitBatchConfig.Timeout. It wraps context.DeadlineExceeded.
+type BatchWaitTimeoutError struct {
+	// Name is the name of the batch job waited for.
+	Name string
+	// Timeout is the exceeded WaitBatchConfig.Timeout.
+	Timeout time.Duration
+	// Last is the last polled batch job, or nil if none was polled.
+	Last *BatchJob
+}
+
+// Error returns a string representation of the BatchWaitTimeoutError.
+func (e *BatchWaitTimeoutError) Error() string {
+	state := JobState("unknown")
+	if e.Last != nil {
+		state = e.Last.State
+	}
+	return fmt.Sprintf("batch job %s did not finish within %v, last state %q", e.Name, e.Timeout, state)
+}
+
+// Unwrap returns context.DeadlineExceeded.
+func (e *BatchWaitTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// BatchProgress counts the requests of a batch job by their status.
+type BatchProgress struct {
+	// The number of requests in the batch, or 0 if it is not known yet.
+	Total int64
+	// The number of requests that were processed successfully.
+	Succeeded int64
+	// The number of requests that failed.
+	Failed int64
+	// The number of requests that are not processed yet.
+	Pending int64
+}
+
+// batchStats are the request counts of a batch job of the Gemini API, which
+// are in the metadata of the operation of the job.
+type batchStats struct {
+	RequestCount           int64 `json:"requestCount,omitempty,string"`
+	SuccessfulRequestCount int64 `json:"successfulRequestCount,omitempty,string"`
+	FailedRequestCount     int64 `json:"failedRequestCount,omitempty,string"`
+	PendingRequestCount    int64 `json:"pendingRequestCount,omitempty,string"`
+}
+
+type batchStatsKey struct{}
+
+// batchStatsHook decodes the batch stats of the batch job got by a call made
+// with a context of Batches.Wait into the *batchStats of the context.
+func (ac *apiClient) batchStatsHook(ctx context.Context, call *hookedCall) error {
+	stats, ok := ctx.Value(batchStatsKey{}).(*batchStats)
+	if !ok || call.method != http.MethodGet {
+		return nil
+	}
+	call.onResponse = append(call.onResponse, func(response map[string]any) error {
+		*stats = batchStats{}
+		metadata, _ := response["metadata"].(map[string]any)
+		if s, ok := metadata["batchStats"].(map[string]any); ok {
+			return mapToStruct(s, stats)
+		}
+		return nil
+	})
+	return nil
+}
+
+// batchProgress returns the request counts of the job, from stats on the
+// Gemini API or CompletionStats on Vertex AI.
+func batchProgress(job *BatchJob, stats *batchStats) BatchProgress {
+	switch {
+	case stats != nil && *stats != (batchStats{}):
+		return BatchProgress{Total: stats.RequestCount, Succeeded: stats.SuccessfulRequestCount, Failed: stats.FailedRequestCount, Pending: stats.PendingRequestCount}
+	case job.CompletionStats != nil:
+		s := job.CompletionStats
+		pending := max(s.IncompleteCount, 0)
+		return BatchProgress{Total: s.SuccessfulCount + s.FailedCount + pending, Succeeded: s.SuccessfulCount, Failed: s.FailedCount, Pending: pending}
+	}
+	return BatchProgress{}
+}
+
+// isTerminal reports whether a batch job in the state has finished.
+func (s JobState) isTerminal() bool {
+	switch s {
+	case JobStateSucceeded, JobStatePartiallySucceeded, JobStateFailed, JobStateCancelled, JobStateExpired:
+		return true
+	}
+	return false
+}
+
+// Wait polls the batch job name until it finishes and returns it. The delay
+// between polls starts at config.InitialInterval and grows by
+// config.Multiplier up to config.MaxInterval. A job that succeeded, or
+// partially succeeded, is returned without an error. A job that failed,
+// expired or was cancelled is returned with a *BatchJobError.
+//
+// If ctx is done first, Wait returns ctx.Err(). If config.Timeout is exceeded
+// first, it returns a *BatchWaitTimeoutError.
+func (b Batches) Wait(ctx context.Context, name string, config *WaitBatchConfig) (*BatchJob, error) {
+	if config == nil {
+		config = &WaitBatchConfig{}
+	}
+	get := func(ctx context.Context) (*BatchJob, error) {
+		var stats batchStats
+		job, err := b.Get(context.WithValue(ctx, batchStatsKey{}, &stats), name, &GetBatchJobConfig{HTTPOptions: config.HTTPOptions})
+		if err == nil && config.OnPoll != nil {
+			config.OnPoll(job, batchProgress(job, &stats))
+		}
+		return job, err
+	}
+	waitConfig := &WaitConfig{
+		InitialInterval: config.InitialInterval,
+		MaxInterval:     config.MaxInterval,
+		Multiplier:      config.Multiplier,
+		Timeout:         config.Timeout,
+	}
+	job, timedOut, err := poll(ctx, waitConfig, get, func(job *BatchJob) bool { return job.State.isTerminal() })
+	if timedOut {
+		return nil, &BatchWaitTimeoutError{Name: name, Timeout: config.Timeout, Last: job}
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch job.State {
+	case JobStateFailed, JobStateExpired, JobStateCancelled:
+		return job, &BatchJobError{Job: job}
+	}
+	return job, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBatchesWait(t *testing.T) {
+	ctx := context.Background()
+	polls := map[string]int{}
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/v1beta/")
+		polls[name]++
+		state := "BATCH_STATE_RUNNING"
+		switch {
+		case name == "batches/slow" || polls[name] < 3:
+		case name == "batches/expired":
+			state = "BATCH_STATE_EXPIRED"
+		default:
+			state = "BATCH_STATE_SUCCEEDED"
+		}
+		done := polls[name] - 1
+		fmt.Fprintf(w, `{"name": %q, "metadata": {"state": %q, "batchStats": {"requestCount": "4", "successfulRequestCount": "%d", "failedRequestCount": "1", "pendingRequestCount": "%d"}}}`, name, state, done, 3-done)
+	})
+
+	var progress []BatchProgress
+	job, err := client.Batches.Wait(ctx, "batches/1", &WaitBatchConfig{
+		InitialInterval: time.Millisecond,
+		OnPoll:          func(job *BatchJob, p BatchProgress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatalf("Wait() failed: %v", err)
+	}
+	if job.State != JobStateSucceeded {
+		t.Errorf("Wait() = %+v, want a succeeded job", job)
+	}
+	want := []BatchProgress{
+		{Total: 4, Succeeded: 0, Failed: 1, Pending: 3},
+		{Total: 4, Succeeded: 1, Failed: 1, Pending: 2},
+		{Total: 4, Succeeded: 2, Failed: 1, Pending: 1},
+	}
+	if diff := cmp.Diff(want, progress); diff != "" {
+		t.Errorf("progress mismatch (-want +got):\n%s", diff)
+	}
+
+	job, err = client.Batches.Wait(ctx, "batches/expired", &WaitBatchConfig{InitialInterval: time.Millisecond})
+	var jobErr *BatchJobError
+	if !errors.Is(err, ErrBatchJobExpired) || !errors.As(err, &jobErr) || job == nil || job.State != JobStateExpired {
+		t.Errorf("Wait() = %+v, %v, want the expired job with ErrBatchJobExpired", job, err)
+	}
+	if errors.Is(err, ErrBatchJobFailed) {
+		t.Errorf("Wait() error %v matches ErrBatchJobFailed", err)
+	}
+
+	_, err = client.Batches.Wait(ctx, "batches/slow", &WaitBatchConfig{InitialInterval: time.Millisecond, Timeout: 20 * time.Millisecond})
+	var timeoutErr *BatchWaitTimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) || timeoutErr.Last == nil {
+		t.Errorf("Wait() = %v, want a *BatchWaitTimeoutError with the last job", err)
+	}
+}
+
+func TestBatchProgress(t *testing.T) {
+	vertex := &BatchJob{CompletionStats: &CompletionStats{SuccessfulCount: 5, FailedCount: 2, IncompleteCount: 3}}
+	if diff := cmp.Diff(BatchProgress{Total: 10, Succeeded: 5, Failed: 2, Pending: 3}, batchProgress(vertex, nil)); diff != "" {
+		t.Errorf("batchProgress() mismatch (-want +got):\n%s", diff)
+	}
+	if got := batchProgress(&BatchJob{}, &batchStats{}); got != (BatchProgress{}) {
+		t.Errorf("batchProgress() of a job without stats = %+v, want zero", got)
+	}
+}
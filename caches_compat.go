@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The Gemini API and Vertex AI differ in how they name cache models, which
+// TTL combinations they accept and how they report errors. The functions in
+// this file normalize these differences so that Caches behaves the same on
+// both backends: Caches accepts the model names of either backend, errors
+// for contents below the minimum cache size match ErrCacheTooSmall and
+// errors for missing caches match ErrCacheNotFound.
+
+var (
+	// ErrCacheTooSmall is matched by errors.Is when the contents of a cache
+	// have fewer tokens than the minimum of the model. Use errors.As with a
+	// *CacheError to get the token counts.
+	ErrCacheTooSmall = errors.New("cached content is too small")
+	// ErrCacheNotFound is matched by errors.Is when a cache does not exist,
+	// e.g. because it expired. The Gemini API reports this as a permission
+	// error and Vertex AI as a not found error.
+	ErrCacheNotFound = errors.New("cached content not found")
+)
+
+// CacheError is an error of the Caches service that has the same meaning on
+// both backends. It matches ErrCacheTooSmall or ErrCacheNotFound with
+// errors.Is and unwraps to the APIError returned by the service.
+type CacheError struct {
+	// Err is the APIError returned by the service.
+	Err error
+	// TokenCount is the number of tokens of the contents, for ErrCacheTooSmall.
+	TokenCount int
+	// MinTokenCount is the minimum number of tokens of a cache of the model,
+	// for ErrCacheTooSmall.
+	MinTokenCount int
+
+	kind error
+}
+
+func (e *CacheError) Error() string {
+	if e.kind == ErrCacheTooSmall && e.MinTokenCount > 0 {
+		return fmt.Sprintf("%v: the contents have %d tokens, the model requires at least %d: %v", e.kind, e.TokenCount, e.MinTokenCount, e.Err)
+	}
+	return fmt.Sprintf("%v: %v", e.kind, e.Err)
+}
+
+// Is reports whether target is the kind of the error.
+func (e *CacheError) Is(target error) bool { return target == e.kind }
+
+// Unwrap returns the APIError returned by the service.
+func (e *CacheError) Unwrap() error { return e.Err }
+
+var (
+	// Gemini API: "Cached content is too small. total_token_count=12, min_total_token_count=1024".
+	geminiCacheTooSmall = regexp.MustCompile(`(?i)cached content is too small.*total_token_count=(\d+).*min_total_token_count=(\d+)`)
+	// Vertex AI: "The cached content is of 12 tokens. The minimum token count to start caching is 1024."
+	vertexCacheTooSmall = regexp.MustCompile(`(?i)cached content is of (\d+) tokens.*minimum token count to start caching is (\d+)`)
+)
+
+// normalizeCacheError converts the backend specific errors of the Caches
+// service to a *CacheError and returns other errors unchanged.
+func normalizeCacheError(err error) error {
+	var apiErr APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	for _, re := range []*regexp.Regexp{geminiCacheTooSmall, vertexCacheTooSmall} {
+		if m := re.FindStringSubmatch(apiErr.Message); m != nil {
+			tokens, _ := strconv.Atoi(m[1])
+			minTokens, _ := strconv.Atoi(m[2])
+			return &CacheError{Err: err, TokenCount: tokens, MinTokenCount: minTokens, kind: ErrCacheTooSmall}
+		}
+	}
+	if strings.Contains(strings.ToLower(apiErr.Message), "too small") {
+		return &CacheError{Err: err, kind: ErrCacheTooSmall}
+	}
+	msg := strings.ToLower(apiErr.Message)
+	if apiErr.Code == http.StatusNotFound || (apiErr.Code == http.StatusForbidden && strings.Contains(msg, "not found")) {
+		return &CacheError{Err: err, kind: ErrCacheNotFound}
+	}
+	return err
+}
+
+// cachesHook normalizes the calls of the Caches service: it checks the
+// expiration of the caches that are created or updated, since an update must
+// set one of TTL and ExpireTime, and converts the errors of the calls with
+// normalizeCacheError.
+func (ac *apiClient) cachesHook(ctx context.Context, call *hookedCall) error {
+	if !slices.Contains(strings.Split(call.path, "/"), "cachedContents") {
+		return nil
+	}
+	call.onError = append(call.onError, normalizeCacheError)
+	if call.body == nil || (call.method != http.MethodPost && call.method != http.MethodPatch) {
+		return nil
+	}
+	ttlString, _ := call.body["ttl"].(string)
+	expireTimeString, _ := call.body["expireTime"].(string)
+	if call.method == http.MethodPatch && ttlString == "" && expireTimeString == "" {
+		return fmt.Errorf("one of TTL and ExpireTime must be set to update a cache")
+	}
+	var ttl time.Duration
+	var expireTime time.Time
+	var err error
+	if ttlString != "" {
+		if ttl, err = time.ParseDuration(ttlString); err != nil {
+			return fmt.Errorf("invalid TTL %q: %w", ttlString, err)
+		}
+	}
+	if expireTimeString != "" {
+		if expireTime, err = time.Parse(time.RFC3339, expireTimeString); err != nil {
+			return fmt.Errorf("invalid ExpireTime %q: %w", expireTimeString, err)
+		}
+	}
+	return checkCacheExpiration(ttl, expireTime)
+}
+
+// checkCacheExpiration returns an error if both ttl and expireTime are set or
+// if they are invalid. The backends reject these requests with different
+// errors, or, for a past expireTime, create a cache that is already expired.
+func checkCacheExpiration(ttl time.Duration, expireTime time.Time) error {
+	switch {
+	case ttl != 0 && !expireTime.IsZero():
+		return fmt.Errorf("only one of TTL and ExpireTime can be set")
+	case ttl < 0:
+		return fmt.Errorf("TTL must not be negative, got %v", ttl)
+	case !expireTime.IsZero() && !expireTime.After(time.Now()):
+		return fmt.Errorf("ExpireTime %v is in the past", expireTime)
+	}
+	return nil
+}
+
+// cacheModelName normalizes the model of a cache for the Gemini API, which
+// only accepts "models/..." names, so that callers can pass the Vertex AI
+// names returned by CachedContent.Model on either backend. Vertex AI already
+// accepts all forms.
+func cacheModelName(ac *apiClient, model any) any {
+	name, ok := model.(string)
+	if !ok || ac.clientConfig.Backend == BackendVertexAI {
+		return model
+	}
+	if strings.HasPrefix(name, "projects/") || strings.HasPrefix(name, "publishers/") {
+		if i := strings.LastIndex(name, "/models/"); i >= 0 {
+			return name[i+1:]
+		}
+	}
+	return model
+}
+
+// ModelID returns the ID of the model of the cache, e.g. "gemini-2.5-flash",
+// without the backend specific prefix of Model, e.g. "models/" for the Gemini
+// API or "projects/.../publishers/google/models/" for Vertex AI.
+func (c *CachedContent) ModelID() string {
+	return modelID(c.Model)
+}
+
+// modelID returns the ID of the model name without the "models/" prefix of
+// the Gemini API or the resource path of Vertex AI.
+func modelID(name string) string {
+	if i := strings.LastIndex(name, "models/"); i >= 0 {
+		return name[i+len("models/"):]
+	}
+	return name
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name              string
+		backend           Backend
+		code              int
+		status            string
+		message           string
+		want              error
+		tokens, minTokens int
+	}{
+		{"GeminiTooSmall", BackendGeminiAPI, 400, "INVALID_ARGUMENT", "Cached content is too small. total_token_count=12, min_total_token_count=1024", ErrCacheTooSmall, 12, 1024},
+		{"VertexTooSmall", BackendVertexAI, 400, "INVALID_ARGUMENT", "The cached content is of 12 tokens. The minimum token count to start caching is 2048.", ErrCacheTooSmall, 12, 2048},
+		{"GeminiNotFound", BackendGeminiAPI, 403, "PERMISSION_DENIED", "CachedContent not found (or permission denied)", ErrCacheNotFound, 0, 0},
+		{"VertexNotFound", BackendVertexAI, 404, "NOT_FOUND", "Not found: cached content metadata for 123.", ErrCacheNotFound, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, &ClientConfig{Backend: tt.backend}, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.code)
+				json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"code": tt.code, "status": tt.status, "message": tt.message}})
+			})
+			var err error
+			if tt.want == ErrCacheTooSmall {
+				_, err = client.Caches.Create(ctx, "gemini-2.5-flash", &CreateCachedContentConfig{Contents: Text("Hi")})
+			} else {
+				_, err = client.Caches.Get(ctx, "123", nil)
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			}
+			var ce *CacheError
+			if !errors.As(err, &ce) || ce.TokenCount != tt.tokens || ce.MinTokenCount != tt.minTokens {
+				t.Errorf("CacheError = %+v, want %d of %d tokens", ce, tt.tokens, tt.minTokens)
+			}
+			var apiErr APIError
+			if !errors.As(err, &apiErr) || apiErr.Code != tt.code {
+				t.Errorf("error %v does not unwrap to the APIError", err)
+			}
+		})
+	}
+}
+
+func TestCacheRequests(t *testing.T) {
+	ctx := context.Background()
+	var model string
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		model, _ = body["model"].(string)
+		w.Write([]byte(`{"name": "cachedContents/1", "model": "models/gemini-2.5-flash"}`))
+	})
+
+	for _, name := range []string{"gemini-2.5-flash", "publishers/google/models/gemini-2.5-flash", "projects/p/locations/l/publishers/google/models/gemini-2.5-flash"} {
+		cache, err := client.Caches.Create(ctx, name, nil)
+		if err != nil {
+			t.Fatalf("Create(%q) failed: %v", name, err)
+		}
+		if model != "models/gemini-2.5-flash" {
+			t.Errorf("Create(%q) sent model %q, want models/gemini-2.5-flash", name, model)
+		}
+		if got := cache.ModelID(); got != "gemini-2.5-flash" {
+			t.Errorf("ModelID() = %q, want gemini-2.5-flash", got)
+		}
+	}
+	vertex := &CachedContent{Model: "projects/p/locations/l/publishers/google/models/gemini-2.5-flash"}
+	if got := vertex.ModelID(); got != "gemini-2.5-flash" {
+		t.Errorf("ModelID() = %q, want gemini-2.5-flash", got)
+	}
+
+	invalid := []*CreateCachedContentConfig{
+		{TTL: time.Hour, ExpireTime: time.Now().Add(time.Hour)},
+		{TTL: -time.Hour},
+		{ExpireTime: time.Now().Add(-time.Hour)},
+	}
+	for _, config := range invalid {
+		if _, err := client.Caches.Create(ctx, "gemini-2.5-flash", config); err == nil {
+			t.Errorf("Create() with TTL %v and ExpireTime %v succeeded, want an error", config.TTL, config.ExpireTime)
+		}
+	}
+	if _, err := client.Caches.Update(ctx, "cachedContents/1", &UpdateCachedContentConfig{}); err == nil {
+		t.Error("Update() without an expiration succeeded, want an error")
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Implicit caching reuses the tokens of a request that starts with the same
+// tokens as an earlier request to the same model. Only a prefix that is
+// byte-identical is reused, so the stable parts of a prompt, such as the
+// system instruction, tools and large documents, should come first and the
+// parts that change, such as the question or the current time, last. The
+// functions in this file help to check that a prompt is structured this way.
+
+// ContentHash returns a hash of the JSON encoding of systemInstruction and
+// contents. Requests with the same hash send the same system instruction and
+// contents, so the hashes of the stable parts of a prompt can be compared
+// across requests to check that they do not change.
+func ContentHash(contents []*Content, systemInstruction *Content) (string, error) {
+	h := sha256.New()
+	enc := json.NewEncoder(h)
+	if err := enc.Encode(systemInstruction); err != nil {
+		return "", fmt.Errorf("failed to encode the system instruction: %w", err)
+	}
+	for i, content := range contents {
+		if err := enc.Encode(content); err != nil {
+			return "", fmt.Errorf("failed to encode content %d: %w", i, err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CachePrefixReport describes how much of a request can be served from the
+// implicit cache of an earlier request.
+type CachePrefixReport struct {
+	// The ContentHash of the contents and system instruction of the request.
+	Hash string
+	// Whether the model, system instruction, tools and tool config of the
+	// request are the same as those of the earlier request. If not, no part of
+	// the request can be served from the implicit cache.
+	SameConfig bool
+	// The number of leading contents of the request that are identical to the
+	// earlier request, which form the prefix that can be served from the
+	// implicit cache. It is 0 if SameConfig is false.
+	PrefixContents int
+	// The number of contents of the request.
+	TotalContents int
+}
+
+// CachePrefixTracker compares every request with the previous request to the
+// same model, so that an application can check that its prompts keep a stable
+// prefix for implicit caching, e.g. in tests or logs. The
+// GenerateContentResponseUsageMetadata.CachedTokenRatio of the response then
+// tells whether the prefix was served from the cache.
+//
+// A CachePrefixTracker is safe for concurrent use.
+type CachePrefixTracker struct {
+	mu   sync.Mutex
+	last map[string]trackedPrefix
+}
+
+type trackedPrefix struct {
+	key          string
+	fingerprints []string
+}
+
+// NewCachePrefixTracker returns an empty CachePrefixTracker.
+func NewCachePrefixTracker() *CachePrefixTracker {
+	return &CachePrefixTracker{last: map[string]trackedPrefix{}}
+}
+
+// Observe reports how much of the request of model with contents and config
+// is identical to the previous request to model, and records the request for
+// the next call. config may be nil.
+func (t *CachePrefixTracker) Observe(model string, contents []*Content, config *GenerateContentConfig) (*CachePrefixReport, error) {
+	if config == nil {
+		config = &GenerateContentConfig{}
+	}
+	hash, err := ContentHash(contents, config.SystemInstruction)
+	if err != nil {
+		return nil, err
+	}
+	key, err := cacheKey(model, config.SystemInstruction, config.Tools, config.ToolConfig)
+	if err != nil {
+		return nil, err
+	}
+	fingerprints, err := contentFingerprints(contents)
+	if err != nil {
+		return nil, err
+	}
+	report := &CachePrefixReport{Hash: hash, TotalContents: len(contents)}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if last, ok := t.last[modelID(model)]; ok && last.key == key {
+		report.SameConfig = true
+		for report.PrefixContents < min(len(last.fingerprints), len(fingerprints)) && last.fingerprints[report.PrefixContents] == fingerprints[report.PrefixContents] {
+			report.PrefixContents++
+		}
+	}
+	t.last[modelID(model)] = trackedPrefix{key: key, fingerprints: fingerprints}
+	return report, nil
+}
+
+// CachedTokenRatio returns the fraction of the prompt tokens of the response
+// that were served from a cache, implicit or explicit, or 0 if the usage
+// metadata does not report prompt tokens.
+func (u *GenerateContentResponseUsageMetadata) CachedTokenRatio() float64 {
+	if u == nil || u.PromptTokenCount == 0 {
+		return 0
+	}
+	return float64(u.CachedContentTokenCount) / float64(u.PromptTokenCount)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestContentHash(t *testing.T) {
+	document := NewContentFromText("A very long document.", RoleUser)
+	instruction := NewContentFromText("Answer questions about the document.", RoleUser)
+	hash := func(contents []*Content, instruction *Content) string {
+		t.Helper()
+		h, err := ContentHash(contents, instruction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+	base := hash([]*Content{document}, instruction)
+	if got := hash([]*Content{NewContentFromText("A very long document.", RoleUser)}, instruction); got != base {
+		t.Errorf("ContentHash() of equal contents = %s, want %s", got, base)
+	}
+	for name, got := range map[string]string{
+		"no system instruction":  hash([]*Content{document}, nil),
+		"instruction as content": hash([]*Content{instruction, document}, nil),
+		"more contents":          hash([]*Content{document, document}, instruction),
+	} {
+		if got == base {
+			t.Errorf("ContentHash() with %s = the hash of the original request", name)
+		}
+	}
+}
+
+func TestCachePrefixTracker(t *testing.T) {
+	tracker := NewCachePrefixTracker()
+	document := NewContentFromText("A very long document.", RoleUser)
+	config := &GenerateContentConfig{SystemInstruction: NewContentFromText("Be brief.", RoleUser)}
+	observe := func(model string, contents []*Content, config *GenerateContentConfig) *CachePrefixReport {
+		t.Helper()
+		report, err := tracker.Observe(model, contents, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report.Hash = ""
+		return report
+	}
+
+	if diff := cmp.Diff(&CachePrefixReport{TotalContents: 2}, observe("gemini-2.5-flash", []*Content{document, Text("Q1")[0]}, config)); diff != "" {
+		t.Errorf("first Observe() mismatch (-want +got):\n%s", diff)
+	}
+	want := &CachePrefixReport{SameConfig: true, PrefixContents: 1, TotalContents: 2}
+	if diff := cmp.Diff(want, observe("models/gemini-2.5-flash", []*Content{document, Text("Q2")[0]}, config)); diff != "" {
+		t.Errorf("Observe() with a new question mismatch (-want +got):\n%s", diff)
+	}
+	// A changed system instruction invalidates the whole prefix.
+	changed := &GenerateContentConfig{SystemInstruction: NewContentFromText("It is 10:42.", RoleUser)}
+	if diff := cmp.Diff(&CachePrefixReport{TotalContents: 2}, observe("gemini-2.5-flash", []*Content{document, Text("Q3")[0]}, changed)); diff != "" {
+		t.Errorf("Observe() with a changed instruction mismatch (-want +got):\n%s", diff)
+	}
+	// Requests to other models are tracked separately.
+	if diff := cmp.Diff(&CachePrefixReport{TotalContents: 1}, observe("gemini-2.5-pro", []*Content{document}, changed)); diff != "" {
+		t.Errorf("Observe() of another model mismatch (-want +got):\n%s", diff)
+	}
+
+	usage := &GenerateContentResponseUsageMetadata{PromptTokenCount: 2000, CachedContentTokenCount: 1500}
+	if got := usage.CachedTokenRatio(); got != 0.75 {
+		t.Errorf("CachedTokenRatio() = %v, want 0.75", got)
+	}
+}
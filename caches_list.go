@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// CacheFilter selects the caches listed by Caches.AllMatching. The service
+// cannot filter caches, so the caches are filtered as they are listed. The
+// zero value matches every cache.
+type CacheFilter struct {
+	// Optional. The model of the caches, in the form used by either backend,
+	// e.g. "gemini-2.5-flash" or "models/gemini-2.5-flash".
+	Model string
+	// Optional. The display name of the caches.
+	DisplayName string
+	// Optional. Matches the caches that expire before this time, e.g. to
+	// refresh them.
+	ExpiresBefore time.Time
+	// Optional. Matches the caches that expire after this time, e.g.
+	// time.Now() for the caches that have not expired yet.
+	ExpiresAfter time.Time
+}
+
+// Matches reports whether cache matches the filter. A nil filter matches
+// every cache.
+func (f *CacheFilter) Matches(cache *CachedContent) bool {
+	switch {
+	case f == nil:
+		return true
+	case f.Model != "" && modelID(f.Model) != cache.ModelID():
+		return false
+	case f.DisplayName != "" && f.DisplayName != cache.DisplayName:
+		return false
+	case !f.ExpiresBefore.IsZero() && !cache.ExpireTime.Before(f.ExpiresBefore):
+		return false
+	case !f.ExpiresAfter.IsZero() && !cache.ExpireTime.After(f.ExpiresAfter):
+		return false
+	}
+	return true
+}
+
+// AllMatching returns an iterator over the caches that match filter. It
+// fetches the pages of config, starting at config.PageToken, as they are
+// iterated.
+func (m Caches) AllMatching(ctx context.Context, config *ListCachedContentsConfig, filter *CacheFilter) iter.Seq2[*CachedContent, error] {
+	return func(yield func(*CachedContent, error) bool) {
+		page, err := m.List(ctx, config)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for cache, err := range page.all(ctx) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if filter.Matches(cache) && !yield(cache, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ListAll returns all the caches that match filter, fetching every page.
+func (m Caches) ListAll(ctx context.Context, filter *CacheFilter) ([]*CachedContent, error) {
+	var caches []*CachedContent
+	for cache, err := range m.AllMatching(ctx, nil, filter) {
+		if err != nil {
+			return nil, err
+		}
+		caches = append(caches, cache)
+	}
+	return caches, nil
+}
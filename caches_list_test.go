@@ -0,0 +1,85 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCachesAllMatching(t *testing.T) {
+	ctx := context.Background()
+	soon := time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+	later := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	pages := map[string]string{
+		"": fmt.Sprintf(`{"cachedContents": [
+			{"name": "cachedContents/1", "model": "models/gemini-2.5-flash", "displayName": "docs", "expireTime": %q},
+			{"name": "cachedContents/2", "model": "models/gemini-2.5-pro", "displayName": "docs", "expireTime": %q}
+		], "nextPageToken": "2"}`, soon, later),
+		"2": fmt.Sprintf(`{"cachedContents": [
+			{"name": "cachedContents/3", "model": "models/gemini-2.5-flash", "displayName": "code", "expireTime": %q}
+		]}`, later),
+	}
+	var requests int
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(pages[r.URL.Query().Get("pageToken")]))
+	})
+
+	tests := []struct {
+		name   string
+		filter *CacheFilter
+		want   []string
+	}{
+		{"all", nil, []string{"cachedContents/1", "cachedContents/2", "cachedContents/3"}},
+		{"model", &CacheFilter{Model: "publishers/google/models/gemini-2.5-flash"}, []string{"cachedContents/1", "cachedContents/3"}},
+		{"display name", &CacheFilter{DisplayName: "docs"}, []string{"cachedContents/1", "cachedContents/2"}},
+		{"expires before", &CacheFilter{ExpiresBefore: time.Now().Add(10 * time.Minute)}, []string{"cachedContents/1"}},
+		{"expires after", &CacheFilter{Model: "gemini-2.5-flash", ExpiresAfter: time.Now().Add(10 * time.Minute)}, []string{"cachedContents/3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			caches, err := client.Caches.ListAll(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("ListAll() failed: %v", err)
+			}
+			var got []string
+			for _, cache := range caches {
+				got = append(got, cache.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListAll() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Stopping the iteration does not fetch the next page.
+	requests = 0
+	for cache, err := range client.Caches.AllMatching(ctx, &ListCachedContentsConfig{PageSize: 2}, &CacheFilter{DisplayName: "docs"}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cache.Name == "cachedContents/2" {
+			break
+		}
+	}
+	if requests != 1 {
+		t.Errorf("AllMatching() sent %d requests, want 1", requests)
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCacheTTL is the TTL the service gives a cache created without a TTL
+// or an expire time.
+const defaultCacheTTL = time.Hour
+
+// CacheStats are the cache usage statistics of the responses recorded by a
+// CacheManager.
+type CacheStats struct {
+	// The number of recorded responses.
+	Requests int64
+	// The number of recorded responses that used cached tokens, explicitly or
+	// implicitly.
+	Hits int64
+	// The total number of prompt tokens, including the cached tokens.
+	PromptTokens int64
+	// The total number of cached tokens.
+	CachedTokens int64
+}
+
+// HitRate returns the fraction of the requests that used cached tokens.
+func (s CacheStats) HitRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Requests)
+}
+
+// CachedTokenRatio returns the fraction of the prompt tokens that were cached.
+func (s CacheStats) CachedTokenRatio() float64 {
+	if s.PromptTokens == 0 {
+		return 0
+	}
+	return float64(s.CachedTokens) / float64(s.PromptTokens)
+}
+
+// CacheManager keeps track of the caches it creates, so that it can refresh
+// their TTL before they expire, use them for the requests that start with
+// their contents and report how often the responses used cached tokens.
+//
+// A CacheManager is safe for concurrent use.
+type CacheManager struct {
+	caches Caches
+	models Models
+
+	mu      sync.Mutex
+	entries map[string]*managedCache
+	stats   CacheStats
+}
+
+// managedCache is a cache tracked by a CacheManager.
+type managedCache struct {
+	cache *CachedContent
+	// ttl is the TTL set when the cache is refreshed.
+	ttl time.Duration
+	// key is the fingerprint of the model, system instruction and tools of the
+	// cache, which a request must have to use the cache.
+	key string
+	// prefix are the fingerprints of the cached contents, which a request must
+	// start with to use the cache.
+	prefix []string
+}
+
+// NewCacheManager returns a CacheManager that uses the Caches and Models of
+// client.
+func NewCacheManager(client *Client) *CacheManager {
+	return &CacheManager{caches: *client.Caches, models: *client.Models, entries: map[string]*managedCache{}}
+}
+
+// Create creates a cache as Caches.Create does and tracks it.
+func (m *CacheManager) Create(ctx context.Context, model string, config *CreateCachedContentConfig) (*CachedContent, error) {
+	cache, err := m.caches.Create(ctx, model, config)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &CreateCachedContentConfig{}
+	}
+	key, err := cacheKey(model, config.SystemInstruction, config.Tools, config.ToolConfig)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := contentFingerprints(config.Contents)
+	if err != nil {
+		return nil, err
+	}
+	entry := &managedCache{cache: cache, ttl: config.TTL, key: key, prefix: prefix}
+	if entry.ttl == 0 && config.ExpireTime.IsZero() {
+		entry.ttl = defaultCacheTTL
+	}
+	m.mu.Lock()
+	m.entries[cache.Name] = entry
+	m.mu.Unlock()
+	return cache, nil
+}
+
+// Caches returns the tracked caches, as they were last created or refreshed.
+func (m *CacheManager) Caches() []*CachedContent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	caches := make([]*CachedContent, 0, len(m.entries))
+	for _, name := range sortedKeys(m.entries) {
+		caches = append(caches, m.entries[name].cache)
+	}
+	return caches
+}
+
+// Delete deletes the cache name and stops tracking it.
+func (m *CacheManager) Delete(ctx context.Context, name string) error {
+	m.untrack(name)
+	_, err := m.caches.Delete(ctx, name, nil)
+	return err
+}
+
+// KeepAlive refreshes the TTL of the tracked cache name every interval until
+// ctx is done, the cache is deleted with Delete or it no longer exists. It
+// blocks, so it is usually run in its own goroutine.
+//
+// Each refresh sets the TTL the cache was created with, or 1h if it was
+// created with neither a TTL nor an expire time. If this TTL is not longer
+// than interval, twice interval is set instead so that the cache does not
+// expire between refreshes. An interval of 0 refreshes the cache when half of
+// its TTL has elapsed.
+//
+// A failed refresh is logged and retried at the next interval. KeepAlive
+// returns ctx.Err() when ctx is done, nil when the cache was deleted with
+// Delete, and an error that matches ErrCacheNotFound when the cache no longer
+// exists.
+func (m *CacheManager) KeepAlive(ctx context.Context, name string, interval time.Duration) error {
+	m.mu.Lock()
+	entry, ok := m.entries[name]
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("cache %s is not tracked by the CacheManager", name)
+	}
+	ttl := entry.ttl
+	switch {
+	case interval < 0:
+		return fmt.Errorf("interval must not be negative, got %v", interval)
+	case interval == 0 && ttl == 0:
+		return fmt.Errorf("an interval is required for cache %s, which was created with an expire time", name)
+	case interval == 0:
+		interval = ttl / 2
+	}
+	if ttl <= interval {
+		ttl = 2 * interval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if !m.tracked(name) {
+			return nil
+		}
+		cache, err := m.caches.Update(ctx, name, &UpdateCachedContentConfig{TTL: ttl})
+		switch {
+		case errors.Is(err, ErrCacheNotFound):
+			m.untrack(name)
+			return err
+		case ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil:
+			log.Printf("Warning: failed to refresh the TTL of cache %s: %v", name, err)
+		default:
+			m.mu.Lock()
+			entry.cache = cache
+			m.mu.Unlock()
+		}
+	}
+}
+
+// GenerateContent calls Models.GenerateContent and records the usage of the
+// response. If config does not set CachedContent and a tracked cache has the
+// model, system instruction, tools and tool config of the request, and
+// contents start with the contents of the cache, the request uses the cache:
+// it sets CachedContent and only sends the remaining contents. If several
+// caches match, the one with the most contents is used.
+//
+// If the cache turns out to no longer exist, it is no longer tracked and the
+// request is sent again without it. Neither contents nor config is modified.
+func (m *CacheManager) GenerateContent(ctx context.Context, model string, contents []*Content, config *GenerateContentConfig) (*GenerateContentResponse, error) {
+	name, cachedContents, cachedConfig := m.attach(model, contents, config)
+	if name == "" {
+		return m.generateContent(ctx, model, contents, config)
+	}
+	resp, err := m.generateContent(ctx, model, cachedContents, cachedConfig)
+	if isCacheNotFound(err) {
+		m.untrack(name)
+		return m.generateContent(ctx, model, contents, config)
+	}
+	return resp, err
+}
+
+func (m *CacheManager) generateContent(ctx context.Context, model string, contents []*Content, config *GenerateContentConfig) (*GenerateContentResponse, error) {
+	resp, err := m.models.GenerateContent(ctx, model, contents, config)
+	if err != nil {
+		return nil, err
+	}
+	m.Record(resp)
+	return resp, nil
+}
+
+// Record adds the usage metadata of resp to the statistics, e.g. for the
+// last response of a stream. Responses without usage metadata are ignored.
+func (m *CacheManager) Record(resp *GenerateContentResponse) {
+	if resp == nil || resp.UsageMetadata == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats.Requests++
+	if resp.UsageMetadata.CachedContentTokenCount > 0 {
+		m.stats.Hits++
+	}
+	m.stats.PromptTokens += int64(resp.UsageMetadata.PromptTokenCount)
+	m.stats.CachedTokens += int64(resp.UsageMetadata.CachedContentTokenCount)
+}
+
+// Stats returns the statistics of the recorded responses.
+func (m *CacheManager) Stats() CacheStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
+// attach returns the name of the tracked cache for the request, with the
+// contents and config of the request that uses it, or an empty name if no
+// cache matches.
+func (m *CacheManager) attach(model string, contents []*Content, config *GenerateContentConfig) (string, []*Content, *GenerateContentConfig) {
+	request := GenerateContentConfig{}
+	if config != nil {
+		request = *config
+	}
+	if request.CachedContent != "" {
+		return "", nil, nil
+	}
+	key, err := cacheKey(model, request.SystemInstruction, request.Tools, request.ToolConfig)
+	if err != nil {
+		return "", nil, nil
+	}
+	fingerprints, err := contentFingerprints(contents)
+	if err != nil {
+		return "", nil, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var best *managedCache
+	for name, entry := range m.entries {
+		if !entry.cache.ExpireTime.IsZero() && time.Now().After(entry.cache.ExpireTime) {
+			delete(m.entries, name)
+			continue
+		}
+		// The request must have contents after the cached ones.
+		if entry.key != key || len(entry.prefix) >= len(fingerprints) || !slices.Equal(entry.prefix, fingerprints[:len(entry.prefix)]) {
+			continue
+		}
+		if best == nil || len(entry.prefix) > len(best.prefix) {
+			best = entry
+		}
+	}
+	if best == nil {
+		return "", nil, nil
+	}
+	// The cache has the system instruction and tools, which cannot be set in
+	// a request that uses a cache.
+	request.SystemInstruction, request.Tools, request.ToolConfig = nil, nil, nil
+	request.CachedContent = best.cache.Name
+	return best.cache.Name, contents[len(best.prefix):], &request
+}
+
+func (m *CacheManager) tracked(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.entries[name]
+	return ok
+}
+
+func (m *CacheManager) untrack(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, name)
+}
+
+// isCacheNotFound reports whether err is the error of a request whose cached
+// content does not exist.
+func isCacheNotFound(err error) bool {
+	return err != nil && errors.Is(normalizeCacheError(err), ErrCacheNotFound) && strings.Contains(strings.ToLower(err.Error()), "cache")
+}
+
+// cacheKey returns the fingerprint of the parts of a request that a cache
+// fixes besides its contents.
+func cacheKey(model string, systemInstruction *Content, tools []*Tool, toolConfig *ToolConfig) (string, error) {
+	return fingerprint(struct {
+		Model             string      `json:"model"`
+		SystemInstruction *Content    `json:"systemInstruction,omitempty"`
+		Tools             []*Tool     `json:"tools,omitempty"`
+		ToolConfig        *ToolConfig `json:"toolConfig,omitempty"`
+	}{modelID(model), systemInstruction, tools, toolConfig})
+}
+
+// contentFingerprints returns the fingerprint of every content.
+func contentFingerprints(contents []*Content) ([]string, error) {
+	fingerprints := make([]string, len(contents))
+	for i, content := range contents {
+		var err error
+		if fingerprints[i], err = fingerprint(content); err != nil {
+			return nil, err
+		}
+	}
+	return fingerprints, nil
+}
+
+// fingerprint returns the SHA-256 hash of the JSON encoding of v.
+func fingerprint(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the request to compute its fingerprint: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
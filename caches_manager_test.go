@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCacheManager(t *testing.T) {
+	ctx := context.Background()
+	var requests []map[string]any
+	var refreshes []string
+	expired := false
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case r.Method == http.MethodPatch:
+			refreshes = append(refreshes, body["ttl"].(string))
+			w.Write([]byte(`{"name": "cachedContents/1", "model": "models/gemini-2.5-flash"}`))
+		case strings.HasSuffix(r.URL.Path, "/cachedContents"):
+			w.Write([]byte(`{"name": "cachedContents/1", "model": "models/gemini-2.5-flash"}`))
+		default:
+			requests = append(requests, body)
+			if expired && body["cachedContent"] != nil {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error": {"code": 403, "status": "PERMISSION_DENIED", "message": "CachedContent not found (or permission denied)"}}`))
+				return
+			}
+			cached := 0
+			if body["cachedContent"] != nil {
+				cached = 1000
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"candidates":    []any{map[string]any{"content": map[string]any{"role": "model", "parts": []any{map[string]any{"text": "42"}}}}},
+				"usageMetadata": map[string]any{"promptTokenCount": 1010, "cachedContentTokenCount": cached},
+			})
+		}
+	})
+	manager := NewCacheManager(client)
+
+	document := NewContentFromText("A very long document.", RoleUser)
+	instruction := NewContentFromText("Answer questions about the document.", RoleUser)
+	if _, err := manager.Create(ctx, "gemini-2.5-flash", &CreateCachedContentConfig{
+		Contents:          []*Content{document},
+		SystemInstruction: instruction,
+	}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	question := NewContentFromText("What is the answer?", RoleUser)
+	config := &GenerateContentConfig{SystemInstruction: instruction, Temperature: Float32(0)}
+	if _, err := manager.GenerateContent(ctx, "models/gemini-2.5-flash", []*Content{document, question}, config); err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	got := requests[len(requests)-1]
+	if got["cachedContent"] != "cachedContents/1" || got["systemInstruction"] != nil || len(got["contents"].([]any)) != 1 {
+		t.Errorf("request = %v, want the cache with only the question", got)
+	}
+	if config.CachedContent != "" || config.SystemInstruction == nil {
+		t.Errorf("GenerateContent() modified the config: %+v", config)
+	}
+
+	// A different system instruction does not match the cache.
+	other := &GenerateContentConfig{SystemInstruction: NewContentFromText("Be brief.", RoleUser)}
+	if _, err := manager.GenerateContent(ctx, "gemini-2.5-flash", []*Content{document, question}, other); err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	if got := requests[len(requests)-1]; got["cachedContent"] != nil {
+		t.Errorf("request = %v, want no cache", got)
+	}
+	want := CacheStats{Requests: 2, Hits: 1, PromptTokens: 2020, CachedTokens: 1000}
+	if diff := cmp.Diff(want, manager.Stats()); diff != "" {
+		t.Errorf("Stats() mismatch (-want +got):\n%s", diff)
+	}
+	if rate := manager.Stats().HitRate(); rate != 0.5 {
+		t.Errorf("HitRate() = %v, want 0.5", rate)
+	}
+
+	keepAliveCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := manager.KeepAlive(keepAliveCtx, "cachedContents/1", 10*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("KeepAlive() = %v, want context.DeadlineExceeded", err)
+	}
+	if len(refreshes) == 0 || refreshes[0] != "3600s" {
+		t.Errorf("refreshed TTLs = %v, want the default TTL", refreshes)
+	}
+
+	// An expired cache is dropped and the request is sent without it.
+	expired = true
+	n := len(requests)
+	if _, err := manager.GenerateContent(ctx, "gemini-2.5-flash", []*Content{document, question}, config); err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	if len(requests) != n+2 || requests[n+1]["cachedContent"] != nil || requests[n+1]["systemInstruction"] == nil {
+		t.Errorf("requests = %v, want a retry without the cache", requests[n:])
+	}
+	if caches := manager.Caches(); len(caches) != 0 {
+		t.Errorf("Caches() = %v, want the expired cache dropped", caches)
+	}
+	if err := manager.KeepAlive(ctx, "cachedContents/1", time.Millisecond); err == nil {
+		t.Error("KeepAlive() of an untracked cache succeeded, want an error")
+	}
+}
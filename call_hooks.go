@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"strings"
+)
+
+// The generated methods of the services convert their arguments to a request
+// body, send it with sendRequest or sendStreamRequest and convert the response
+// map. The behaviors that the SDK adds to these calls, such as guardrails, are
+// hooks run on the request body and the response map, chosen by the method
+// and path of the call.
+
+// hookedCall is an API call to which the hooks apply.
+type hookedCall struct {
+	method string
+	// path is the path of the call without the query, e.g.
+	// "models/gemini-2.5-flash:generateContent".
+	path string
+	// body is the request body that the hooks may edit, or nil if the body is
+	// not a map.
+	body map[string]any
+
+	// onResponse edit the response map of the call, or the map of each chunk
+	// of a stream, before it is converted.
+	onResponse []func(response map[string]any) error
+	// onError convert the error of the call, in order.
+	onError []func(err error) error
+	// onDone are called once the call, or its stream, has ended.
+	onDone []func()
+}
+
+// hookCall runs the hooks on a call to path and returns the call, whose
+// response and done methods must be called.
+func (ac *apiClient) hookCall(ctx context.Context, path, method string, body any) (*hookedCall, error) {
+	path, _, _ = strings.Cut(path, "?")
+	call := &hookedCall{method: method, path: path}
+	call.body, _ = body.(map[string]any)
+	hooks := []func(context.Context, *hookedCall) error{
+		ac.defaultConfigHook,
+		ac.guardrailsHook,
+		ac.cachesHook,
+		ac.labelsHook,
+		ac.autoUploadHook,
+		ac.batchStatsHook,
+		ac.tuningSnapshotsHook,
+	}
+	for _, hook := range hooks {
+		if err := hook(ctx, call); err != nil {
+			call.done()
+			return nil, err
+		}
+	}
+	return call, nil
+}
+
+// isMethod reports whether the call is to one of the custom methods of the
+// API, such as "generateContent".
+func (c *hookedCall) isMethod(names ...string) bool {
+	_, method, ok := strings.Cut(c.path, ":")
+	if !ok {
+		return false
+	}
+	for _, name := range names {
+		if method == name {
+			return true
+		}
+	}
+	return false
+}
+
+// response runs the response hooks on the response map of the call.
+func (c *hookedCall) response(response map[string]any) error {
+	if c == nil {
+		return nil
+	}
+	for _, f := range c.onResponse {
+		if err := f(response); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// error converts err, an error of the call, with the error hooks.
+func (c *hookedCall) error(err error) error {
+	if c == nil || err == nil {
+		return err
+	}
+	for _, f := range c.onError {
+		err = f(err)
+	}
+	return err
+}
+
+// done runs the done hooks of the call, once.
+func (c *hookedCall) done() {
+	if c == nil {
+		return
+	}
+	onDone := c.onDone
+	c.onDone = nil
+	for _, f := range onDone {
+		f()
+	}
+}
+
+// objectsOf returns the objects of a list of a request body or response map,
+// which the converters build as []map[string]any and JSON decodes as []any.
+func objectsOf(v any) []map[string]any {
+	switch v := v.(type) {
+	case []map[string]any:
+		return v
+	case []any:
+		objects := make([]map[string]any, 0, len(v))
+		for _, e := range v {
+			if object, ok := e.(map[string]any); ok {
+				objects = append(objects, object)
+			}
+		}
+		return objects
+	}
+	return nil
+}
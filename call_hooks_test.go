@@ -0,0 +1,48 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHookedCall(t *testing.T) {
+	ac := &apiClient{clientConfig: &ClientConfig{}}
+	call, err := ac.hookCall(context.Background(), "models/gemini-2.5-flash:streamGenerateContent?alt=sse", "POST", map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !call.isMethod("generateContent", "streamGenerateContent") {
+		t.Errorf("isMethod(generateContent, streamGenerateContent) = false for %q, want true", call.path)
+	}
+	if call.isMethod("generateContent") {
+		t.Errorf("isMethod(generateContent) = true for %q, want false", call.path)
+	}
+
+	done := 0
+	call.onDone = append(call.onDone, func() { done++ })
+	call.done()
+	call.done()
+	if done != 1 {
+		t.Errorf("done hooks ran %d times, want 1", done)
+	}
+
+	var nilCall *hookedCall
+	if err := nilCall.response(map[string]any{}); err != nil {
+		t.Errorf("response() of a nil call = %v, want nil", err)
+	}
+	nilCall.done()
+}
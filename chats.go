@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"iter"
+	"log"
+	"strings"
 )
 
 // Chats provides util functions for creating a new chat session.
@@ -44,6 +46,17 @@ type Chat struct {
 	comprehensiveHistory []*Content
 	// Curated history is the set of valid turns that will be used in the subsequent send requests.
 	curatedHistory []*Content
+	// memory is consulted before and written after each turn, if set.
+	memory Memory
+	// historyPolicy shapes the curated history before each turn, if set.
+	historyPolicy HistoryPolicy
+	// automaticFunctionCalling calls the functions requested by the model in
+	// Send, if set.
+	automaticFunctionCalling *AutomaticFunctionCallingConfig
+	// store saves the comprehensive history as the chat id after each turn, if
+	// set by Chats.Resume.
+	store ChatStore
+	id    string
 }
 
 func validateContent(content *Content) bool {
@@ -134,7 +147,7 @@ func (c *Chats) Create(ctx context.Context, model string, config *GenerateConten
 	}
 	chat := &Chat{
 		apiClient:            c.apiClient,
-		model:                model,
+		model:                c.apiClient.resolveModel(model),
 		config:               config,
 		comprehensiveHistory: compHistory,
 		curatedHistory:       curatedHistory,
@@ -143,7 +156,10 @@ func (c *Chats) Create(ctx context.Context, model string, config *GenerateConten
 	return chat, nil
 }
 
-func (c *Chat) recordHistory(ctx context.Context, inputContent *Content, outputContents []*Content, isValid bool) {
+// recordHistory adds a turn to the history. The comprehensive history records
+// outputContents, and the curated history curatedOutputContents, which differ
+// for streamed responses.
+func (c *Chat) recordHistory(ctx context.Context, inputContent *Content, outputContents, curatedOutputContents []*Content, isValid bool) {
 	c.comprehensiveHistory = append(c.comprehensiveHistory, inputContent)
 	if len(outputContents) == 0 {
 		c.comprehensiveHistory = append(c.comprehensiveHistory, &Content{Role: RoleModel, Parts: []*Part{}})
@@ -152,15 +168,67 @@ func (c *Chat) recordHistory(ctx context.Context, inputContent *Content, outputC
 	}
 
 	if isValid {
+		c.remember(ctx, inputContent, curatedOutputContents)
 		c.curatedHistory = append(c.curatedHistory, inputContent)
-		if len(outputContents) == 0 {
+		if len(curatedOutputContents) == 0 {
 			c.curatedHistory = append(c.curatedHistory, &Content{Role: RoleModel, Parts: []*Part{}})
 		} else {
-			c.curatedHistory = append(c.curatedHistory, outputContents...)
+			c.curatedHistory = append(c.curatedHistory, curatedOutputContents...)
 		}
 	}
 }
 
+// SetMemory sets the memory of the chat. Before each message, the records
+// relevant to the message text are recalled and added to the system
+// instruction of the request; after each valid turn, the user message and the
+// model response are added to the memory as turn records. A nil memory turns
+// this off.
+func (c *Chat) SetMemory(m Memory) {
+	c.memory = m
+}
+
+// memoryConfig returns the config of a request for inputContent, with the
+// memory recalled for it added to the system instruction of base.
+func (c *Chat) memoryConfig(ctx context.Context, base *GenerateContentConfig, inputContent *Content) (*GenerateContentConfig, error) {
+	if c.memory == nil {
+		return base, nil
+	}
+	recalled, err := RecallMemory(ctx, c.memory, contentText(inputContent), DefaultMemoryRecallLimit)
+	if err != nil || recalled == "" {
+		return base, err
+	}
+	config := &GenerateContentConfig{}
+	if base != nil {
+		*config = *base
+	}
+	instruction := &Content{Role: RoleUser}
+	if config.SystemInstruction != nil {
+		instruction.Role = config.SystemInstruction.Role
+		instruction.Parts = append(instruction.Parts, config.SystemInstruction.Parts...)
+	}
+	instruction.Parts = append(instruction.Parts, NewPartFromText(recalled))
+	config.SystemInstruction = instruction
+	return config, nil
+}
+
+// remember adds a valid turn to the memory of the chat.
+func (c *Chat) remember(ctx context.Context, inputContent *Content, outputContents []*Content) {
+	if c.memory == nil {
+		return
+	}
+	var output strings.Builder
+	for _, content := range outputContents {
+		output.WriteString(contentText(content))
+	}
+	records := []*MemoryRecord{NewTurnRecord(RoleUser, contentText(inputContent))}
+	if output.Len() > 0 {
+		records = append(records, NewTurnRecord(RoleModel, output.String()))
+	}
+	if err := c.memory.Add(ctx, records...); err != nil {
+		log.Printf("Warning: failed to add the chat turn to memory: %v", err)
+	}
+}
+
 // History returns the chat history. Returns the curated history if
 // curated is true, otherwise returns the comprehensive history.
 func (c *Chat) History(curated bool) []*Content {
@@ -182,25 +250,44 @@ func (c *Chat) SendMessage(ctx context.Context, parts ...Part) (*GenerateContent
 
 // Send function sends the conversation history with the additional user's message and returns the model's response.
 func (c *Chat) Send(ctx context.Context, parts ...*Part) (*GenerateContentResponse, error) {
-	inputContent := &Content{Parts: parts, Role: RoleUser}
+	return c.send(ctx, nil, parts)
+}
+
+func (c *Chat) send(ctx context.Context, overrides *GenerateContentConfig, parts []*Part) (*GenerateContentResponse, error) {
+	// Apply the input guardrails here rather than in GenerateContent, so that
+	// the history keeps the checked message.
+	inputContent, err := c.apiClient.guardContent(ctx, GuardrailStageInput, &Content{Parts: parts, Role: RoleUser})
+	if err != nil {
+		return nil, err
+	}
+	ctx = withoutInputGuardrails(ctx)
+
+	if err := c.applyHistoryPolicy(ctx, inputContent); err != nil {
+		return nil, err
+	}
 
 	// Combine history with input content to send to model
 	contents := append(c.curatedHistory, inputContent)
 
-	// Generate Content
-	modelOutput, err := c.GenerateContent(ctx, c.model, contents, c.config)
+	config, err := c.memoryConfig(ctx, c.requestConfig(overrides), inputContent)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate Content, calling the functions requested by the model if
+	// automatic function calling is enabled.
+	modelOutput, outputContents, err := c.generateContent(ctx, contents, config)
 	if err != nil {
 		return nil, err
 	}
 
 	// Record history. By default, use the first candidate for history.
-	var outputContents []*Content
 	if len(modelOutput.Candidates) > 0 && modelOutput.Candidates[0].Content != nil {
 		outputContents = append(outputContents, modelOutput.Candidates[0].Content)
 	}
-	c.recordHistory(ctx, inputContent, outputContents, validateResponse(modelOutput))
+	c.recordHistory(ctx, inputContent, outputContents, outputContents, validateResponse(modelOutput))
 
-	return modelOutput, err
+	return modelOutput, c.save(ctx)
 }
 
 // SendMessageStream is a wrapper around SendStream.
@@ -215,16 +302,34 @@ func (c *Chat) SendMessageStream(ctx context.Context, parts ...Part) iter.Seq2[*
 
 // SendStream function sends the conversation history with the additional user's message and returns the model's response.
 func (c *Chat) SendStream(ctx context.Context, parts ...*Part) iter.Seq2[*GenerateContentResponse, error] {
-	inputContent := &Content{Parts: parts, Role: RoleUser}
-
-	// Combine history with input content to send to model
-	contents := append(c.curatedHistory, inputContent)
+	return c.sendStream(ctx, nil, parts)
+}
 
-	// Generate Content
-	response := c.GenerateContentStream(ctx, c.model, contents, c.config)
+func (c *Chat) sendStream(ctx context.Context, overrides *GenerateContentConfig, parts []*Part) iter.Seq2[*GenerateContentResponse, error] {
+	inputContent, err := c.apiClient.guardContent(ctx, GuardrailStageInput, &Content{Parts: parts, Role: RoleUser})
+	if err != nil {
+		return yieldErrorAndEndIterator[GenerateContentResponse](err)
+	}
+	ctx = withoutInputGuardrails(ctx)
 
 	// Return a new iterator that will yield the responses and record history with merged response.
 	return func(yield func(*GenerateContentResponse, error) bool) {
+		if err := c.applyHistoryPolicy(ctx, inputContent); err != nil {
+			yield(nil, err)
+			return
+		}
+
+		// Combine history with input content to send to model
+		contents := append(c.curatedHistory, inputContent)
+
+		config, err := c.memoryConfig(ctx, c.requestConfig(overrides), inputContent)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		// Generate Content
+		response := c.GenerateContentStream(ctx, c.model, contents, config)
 		var outputContents []*Content
 		isValid := true
 		finishReason := FinishReasonUnspecified
@@ -251,8 +356,17 @@ func (c *Chat) SendStream(ctx context.Context, parts ...*Part) iter.Seq2[*Genera
 				return
 			}
 		}
-		// Record history. By default, use the first candidate for history.
+		// Record history. By default, use the first candidate for history. The
+		// comprehensive history keeps the content of every chunk, the curated
+		// history the merged model turn, as returned without streaming.
 		finalIsValid := isValid && finishReason != FinishReasonUnspecified
-		c.recordHistory(ctx, inputContent, outputContents, finalIsValid)
+		var curatedOutputContents []*Content
+		if merged := mergeStreamContents(outputContents); merged != nil {
+			curatedOutputContents = []*Content{merged}
+		}
+		c.recordHistory(ctx, inputContent, outputContents, curatedOutputContents, finalIsValid)
+		if err := c.save(ctx); err != nil {
+			yield(nil, err)
+		}
 	}
 }
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"iter"
+)
+
+// Config returns the config that the chat sends with each message. It must not
+// be modified; use SetConfig instead.
+func (c *Chat) Config() *GenerateContentConfig {
+	return c.config
+}
+
+// SetConfig replaces the config that the chat sends with the following
+// messages, e.g. to change the temperature or the tools in the middle of a
+// conversation. The history of the chat is kept. A nil config sends the
+// messages without config.
+func (c *Chat) SetConfig(config *GenerateContentConfig) {
+	c.config = config
+}
+
+// SetSystemInstruction replaces the system instruction of the config of the
+// chat for the following messages. The rest of the config is kept; the config
+// passed to Chats.Create or SetConfig is not modified. A nil instruction
+// removes the system instruction.
+func (c *Chat) SetSystemInstruction(instruction *Content) {
+	config := c.config.Clone()
+	if config == nil {
+		config = &GenerateContentConfig{}
+	}
+	config.SystemInstruction = instruction
+	c.config = config
+}
+
+// SendWithConfig is like Send, but sends the message with overrides layered on
+// top of the config of the chat, as with GenerateContentConfig.Merge. The
+// config of the chat is not changed for the following messages.
+func (c *Chat) SendWithConfig(ctx context.Context, overrides *GenerateContentConfig, parts ...*Part) (*GenerateContentResponse, error) {
+	return c.send(ctx, overrides, parts)
+}
+
+// SendMessageWithConfig is a wrapper around SendWithConfig.
+func (c *Chat) SendMessageWithConfig(ctx context.Context, overrides *GenerateContentConfig, parts ...Part) (*GenerateContentResponse, error) {
+	p := make([]*Part, len(parts))
+	for i, part := range parts {
+		p[i] = &part
+	}
+	return c.send(ctx, overrides, p)
+}
+
+// SendStreamWithConfig is like SendStream, but sends the message with
+// overrides layered on top of the config of the chat, as with
+// GenerateContentConfig.Merge. The config of the chat is not changed for the
+// following messages.
+func (c *Chat) SendStreamWithConfig(ctx context.Context, overrides *GenerateContentConfig, parts ...*Part) iter.Seq2[*GenerateContentResponse, error] {
+	return c.sendStream(ctx, overrides, parts)
+}
+
+// SendMessageStreamWithConfig is a wrapper around SendStreamWithConfig.
+func (c *Chat) SendMessageStreamWithConfig(ctx context.Context, overrides *GenerateContentConfig, parts ...Part) iter.Seq2[*GenerateContentResponse, error] {
+	p := make([]*Part, len(parts))
+	for i, part := range parts {
+		p[i] = &part
+	}
+	return c.sendStream(ctx, overrides, p)
+}
+
+// requestConfig returns the config of the chat with the overrides of a
+// message merged in.
+func (c *Chat) requestConfig(overrides *GenerateContentConfig) *GenerateContentConfig {
+	if overrides == nil {
+		return c.config
+	}
+	return c.config.Merge(overrides)
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestChatSetConfig(t *testing.T) {
+	ctx := context.Background()
+	var requests []map[string]any
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		requests = append(requests, req)
+		response := `{"candidates": [{"content": {"role": "model", "parts": [{"text": "ok"}]}, "finishReason": "STOP"}]}`
+		if strings.Contains(r.URL.Path, ":streamGenerateContent") {
+			response = "data: " + response + "\n\n"
+		}
+		fmt.Fprint(w, response)
+	})
+	config := &GenerateContentConfig{
+		Temperature:       Float32(0.5),
+		SystemInstruction: NewContentFromText("Be brief.", RoleUser),
+	}
+	chat, err := client.Chats.Create(ctx, "gemini-2.5-flash", config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chat.SetSystemInstruction(NewContentFromText("Be formal.", RoleUser))
+	if _, err := chat.SendMessage(ctx, Part{Text: "1"}); err != nil {
+		t.Fatalf("SendMessage() failed: %v", err)
+	}
+	if _, err := chat.SendMessageWithConfig(ctx, &GenerateContentConfig{Temperature: Float32(1)}, Part{Text: "2"}); err != nil {
+		t.Fatalf("SendMessageWithConfig() failed: %v", err)
+	}
+	for _, err := range chat.SendMessageStreamWithConfig(ctx, &GenerateContentConfig{CandidateCount: 1}, Part{Text: "3"}) {
+		if err != nil {
+			t.Fatalf("SendMessageStreamWithConfig() failed: %v", err)
+		}
+	}
+	chat.SetConfig(nil)
+	if _, err := chat.SendMessage(ctx, Part{Text: "4"}); err != nil {
+		t.Fatalf("SendMessage() failed: %v", err)
+	}
+
+	formal := map[string]any{"role": "user", "parts": []any{map[string]any{"text": "Be formal."}}}
+	want := []struct {
+		generationConfig  any
+		systemInstruction any
+		contents          int
+	}{
+		{map[string]any{"temperature": 0.5}, formal, 1},
+		{map[string]any{"temperature": 1.0}, formal, 3},
+		{map[string]any{"temperature": 0.5, "candidateCount": 1.0}, formal, 5},
+		{nil, nil, 7},
+	}
+	if len(requests) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(requests), len(want))
+	}
+	for i, w := range want {
+		if diff := cmp.Diff(w.generationConfig, requests[i]["generationConfig"]); diff != "" {
+			t.Errorf("request %d generationConfig mismatch (-want +got):\n%s", i, diff)
+		}
+		if diff := cmp.Diff(w.systemInstruction, requests[i]["systemInstruction"]); diff != "" {
+			t.Errorf("request %d systemInstruction mismatch (-want +got):\n%s", i, diff)
+		}
+		if got := len(requestContents(t, requests[i])); got != w.contents {
+			t.Errorf("request %d has %d contents, want %d", i, got, w.contents)
+		}
+	}
+	if got := config.SystemInstruction.Parts[0].Text; got != "Be brief." {
+		t.Errorf("SetSystemInstruction() modified the config passed to Create, got instruction %q", got)
+	}
+	if chat.Config() != nil {
+		t.Errorf("Config() = %v, want nil", chat.Config())
+	}
+}
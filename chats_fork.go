@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Fork returns an independent chat that continues from the current history
+// of c, e.g. to explore several continuations of a conversation. Both chats
+// share the contents of the history until then, which must not be modified;
+// later turns of one chat are not seen by the other. The fork uses the same
+// model, config, memory, history policy and automatic function calling, but is not saved to the store of
+// a chat resumed with Chats.Resume.
+func (c *Chat) Fork() *Chat {
+	fork := *c
+	fork.comprehensiveHistory = slices.Clip(c.comprehensiveHistory)
+	fork.curatedHistory = slices.Clip(c.curatedHistory)
+	fork.store = nil
+	fork.id = ""
+	return &fork
+}
+
+// RewindTo returns a fork of c, as returned by Fork, whose history is the
+// first turnIndex turns of the comprehensive history of c, so that the
+// conversation continues from that earlier point. A turn is a user message with
+// the model responses to it, including the function calls and responses in
+// between. c itself is not modified. turnIndex must be between 0 and the number
+// of turns.
+func (c *Chat) RewindTo(turnIndex int) (*Chat, error) {
+	turns := historyTurns(c.comprehensiveHistory)
+	if turnIndex < 0 || turnIndex > len(turns) {
+		return nil, fmt.Errorf("turn index %d is out of range, the chat has %d turns", turnIndex, len(turns))
+	}
+	n := 0
+	for _, turn := range turns[:turnIndex] {
+		n += len(turn)
+	}
+	curatedHistory, err := extractCuratedHistory(c.comprehensiveHistory[:n])
+	if err != nil {
+		return nil, err
+	}
+	fork := c.Fork()
+	fork.comprehensiveHistory = slices.Clip(c.comprehensiveHistory[:n])
+	fork.curatedHistory = curatedHistory
+	return fork, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestChatForkAndRewindTo(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		contents := requestContents(t, req)
+		message := contentText(contents[len(contents)-1])
+		fmt.Fprintf(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "re: %s"}]}, "finishReason": "STOP"}]}`, message)
+	})
+	history := testHistory()
+	chat, err := client.Chats.Create(ctx, "gemini-2.5-flash", nil, history)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fork := chat.Fork()
+	if _, err := chat.SendMessage(ctx, Part{Text: "original"}); err != nil {
+		t.Fatalf("SendMessage() failed: %v", err)
+	}
+	if _, err := fork.SendMessage(ctx, Part{Text: "fork"}); err != nil {
+		t.Fatalf("SendMessage() on the fork failed: %v", err)
+	}
+	wantOriginal := append(testHistory(), NewContentFromText("original", RoleUser), NewContentFromText("re: original", RoleModel))
+	if diff := cmp.Diff(wantOriginal, chat.History(false)); diff != "" {
+		t.Errorf("History(false) mismatch (-want +got):\n%s", diff)
+	}
+	wantFork := append(testHistory(), NewContentFromText("fork", RoleUser), NewContentFromText("re: fork", RoleModel))
+	if diff := cmp.Diff(wantFork, fork.History(true)); diff != "" {
+		t.Errorf("fork History(true) mismatch (-want +got):\n%s", diff)
+	}
+
+	rewound, err := chat.RewindTo(1)
+	if err != nil {
+		t.Fatalf("RewindTo() failed: %v", err)
+	}
+	if diff := cmp.Diff(history[:2], rewound.History(true)); diff != "" {
+		t.Errorf("rewound History(true) mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := rewound.SendMessage(ctx, Part{Text: "again"}); err != nil {
+		t.Fatalf("SendMessage() on the rewound chat failed: %v", err)
+	}
+	wantRewound := append(testHistory()[:2], NewContentFromText("again", RoleUser), NewContentFromText("re: again", RoleModel))
+	if diff := cmp.Diff(wantRewound, rewound.History(false)); diff != "" {
+		t.Errorf("rewound History(false) mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantOriginal, chat.History(false)); diff != "" {
+		t.Errorf("History(false) changed by the rewound chat (-want +got):\n%s", diff)
+	}
+
+	for _, turnIndex := range []int{-1, 5} {
+		if _, err := chat.RewindTo(turnIndex); err == nil {
+			t.Errorf("RewindTo(%d) succeeded, want error", turnIndex)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import "context"
+
+const defaultMaximumRemoteCalls = 10
+
+// AutomaticFunctionCallingConfig configures the automatic function calling of
+// a chat. When the model calls registered functions, Chat.Send calls them,
+// sends their responses back to the model, and repeats until the model answers
+// without function calls. The function calls and responses are kept in the
+// history of the chat before the final answer.
+//
+//	chat, err := client.Chats.Create(ctx, "gemini-2.5-flash", nil, nil)
+//	chat.SetAutomaticFunctionCalling(&genai.AutomaticFunctionCallingConfig{Functions: functions})
+//	result, err := chat.SendMessage(ctx, genai.Part{Text: "What is the weather in Paris?"})
+type AutomaticFunctionCallingConfig struct {
+	// Optional. Turns automatic function calling off while keeping the rest
+	// of the config.
+	Disable bool
+	// Required. The functions that are called automatically. Their declarations
+	// are added to the Tools of the requests; do not add them to the Tools
+	// yourself. If the model calls a function that is not registered, none of
+	// the calls of that response are made and the response is returned as is.
+	// The calls are made under the Policy of the registry, if any.
+	Functions *FunctionRegistry
+	// Optional. The maximum number of requests that a single Send sends to the
+	// model. When it is reached, the last response is returned with its
+	// function calls unanswered. Defaults to 10.
+	MaximumRemoteCalls int
+}
+
+// enabled reports whether functions are called automatically.
+func (c *AutomaticFunctionCallingConfig) enabled() bool {
+	return c != nil && !c.Disable && c.Functions != nil
+}
+
+func (c *AutomaticFunctionCallingConfig) maximumRemoteCalls() int {
+	if c.MaximumRemoteCalls <= 0 {
+		return defaultMaximumRemoteCalls
+	}
+	return c.MaximumRemoteCalls
+}
+
+// SetAutomaticFunctionCalling sets the automatic function calling of the chat,
+// with which Send and SendMessage call the functions that the model requests.
+// It is not applied to streamed messages. A nil config turns this off.
+func (c *Chat) SetAutomaticFunctionCalling(config *AutomaticFunctionCallingConfig) {
+	c.automaticFunctionCalling = config
+}
+
+// generateContent generates the response to contents. If automatic function
+// calling is enabled for the chat, it answers the function calls of the model
+// and also returns the intermediate model and function response contents,
+// to be recorded in the history before the final response.
+func (c *Chat) generateContent(ctx context.Context, contents []*Content, config *GenerateContentConfig) (*GenerateContentResponse, []*Content, error) {
+	afc := c.automaticFunctionCalling
+	if !afc.enabled() {
+		response, err := c.GenerateContent(ctx, c.model, contents, config)
+		return response, nil, err
+	}
+	withTools := GenerateContentConfig{}
+	if config != nil {
+		withTools = *config
+	}
+	withTools.Tools = append(append([]*Tool(nil), withTools.Tools...), afc.Functions.Tool())
+	contents = append([]*Content(nil), contents...)
+	var intermediate []*Content
+	for calls := 1; ; calls++ {
+		response, err := c.GenerateContent(ctx, c.model, contents, &withTools)
+		if err != nil {
+			return nil, nil, err
+		}
+		functionCalls := response.FunctionCalls()
+		if len(functionCalls) == 0 || !afc.Functions.hasAll(functionCalls) || calls >= afc.maximumRemoteCalls() {
+			return response, intermediate, nil
+		}
+		parts, err := afc.Functions.DispatchAll(ctx, functionCalls)
+		if err != nil {
+			return nil, nil, err
+		}
+		turn := []*Content{response.Candidates[0].Content, {Role: RoleUser, Parts: parts}}
+		intermediate = append(intermediate, turn...)
+		contents = append(contents, turn...)
+	}
+}
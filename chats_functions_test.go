@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testForecastCall = `{"candidates": [{"content": {"role": "model", "parts": [{"functionCall": {"name": "get_forecast", "args": {"city": "Paris"}}}]}}]}`
+
+// newFunctionCallingChat returns a chat with afc whose server answers the
+// first calls requests with a call of get_forecast and the next ones with a
+// text, and the contents of every request the server receives.
+func newFunctionCallingChat(t *testing.T, calls int, afc *AutomaticFunctionCallingConfig) (*Chat, *[][]any) {
+	t.Helper()
+	var contents [][]any
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		got, _ := req["contents"].([]any)
+		contents = append(contents, got)
+		if len(contents) <= calls {
+			fmt.Fprint(w, testForecastCall)
+			return
+		}
+		fmt.Fprint(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "21.5 degrees."}]}}]}`)
+	})
+	chat, err := client.Chats.Create(context.Background(), "gemini-2.5-flash", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat.SetAutomaticFunctionCalling(afc)
+	return chat, &contents
+}
+
+func TestChatAutomaticFunctionCalling(t *testing.T) {
+	ctx := context.Background()
+	chat, requests := newFunctionCallingChat(t, 2, &AutomaticFunctionCallingConfig{Functions: newTestFunctionRegistry(t)})
+
+	got, err := chat.SendMessage(ctx, Part{Text: "Weather in Paris?"})
+	if err != nil {
+		t.Fatalf("SendMessage() failed: %v", err)
+	}
+	if got.Text() != "21.5 degrees." {
+		t.Errorf("SendMessage() text = %q, want the final answer", got.Text())
+	}
+	if len(*requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(*requests))
+	}
+	if n := len((*requests)[2]); n != 5 {
+		t.Errorf("the last request has %d contents, want 5", n)
+	}
+
+	call := &Part{FunctionCall: &FunctionCall{Name: "get_forecast", Args: map[string]any{"city": "Paris"}}}
+	response := &Part{FunctionResponse: &FunctionResponse{Name: "get_forecast", Response: map[string]any{"output": &testForecast{City: "Paris", Celsius: 21.5}}}}
+	want := []*Content{
+		{Role: RoleUser, Parts: []*Part{{Text: "Weather in Paris?"}}},
+		{Role: RoleModel, Parts: []*Part{call}},
+		{Role: RoleUser, Parts: []*Part{response}},
+		{Role: RoleModel, Parts: []*Part{call}},
+		{Role: RoleUser, Parts: []*Part{response}},
+		{Role: RoleModel, Parts: []*Part{{Text: "21.5 degrees."}}},
+	}
+	if diff := cmp.Diff(want, chat.History(true)); diff != "" {
+		t.Errorf("History(true) mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want, chat.History(false)); diff != "" {
+		t.Errorf("History(false) mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestChatAutomaticFunctionCallingLimits(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name         string
+		afc          *AutomaticFunctionCallingConfig
+		wantRequests int
+	}{
+		{name: "maximum remote calls", afc: &AutomaticFunctionCallingConfig{Functions: newTestFunctionRegistry(t), MaximumRemoteCalls: 2}, wantRequests: 2},
+		{name: "disabled", afc: &AutomaticFunctionCallingConfig{Functions: newTestFunctionRegistry(t), Disable: true}, wantRequests: 1},
+		{name: "unregistered function", afc: &AutomaticFunctionCallingConfig{Functions: NewFunctionRegistry()}, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chat, requests := newFunctionCallingChat(t, 5, tt.afc)
+			got, err := chat.SendMessage(ctx, Part{Text: "Weather in Paris?"})
+			if err != nil {
+				t.Fatalf("SendMessage() failed: %v", err)
+			}
+			if len(got.FunctionCalls()) != 1 {
+				t.Errorf("SendMessage() = %+v, want the unanswered function call", got)
+			}
+			if len(*requests) != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", len(*requests), tt.wantRequests)
+			}
+			if n := len(chat.History(true)); n != 2*tt.wantRequests {
+				t.Errorf("History(true) has %d contents, want %d", n, 2*tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestChatAutomaticFunctionCallingPolicy(t *testing.T) {
+	ctx := context.Background()
+	functions := newTestFunctionRegistry(t)
+	functions.Policy = &ToolPolicy{Check: func(ctx context.Context, name string, args map[string]any) error {
+		if args["city"] == "Paris" {
+			return errors.New("Paris is off limits")
+		}
+		return nil
+	}}
+	chat, requests := newFunctionCallingChat(t, 1, &AutomaticFunctionCallingConfig{Functions: functions})
+
+	if _, err := chat.SendMessage(ctx, Part{Text: "Weather in Paris?"}); err != nil {
+		t.Fatalf("SendMessage() failed: %v", err)
+	}
+	if len(*requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(*requests))
+	}
+	turn := (*requests)[1][2].(map[string]any)
+	response := turn["parts"].([]any)[0].(map[string]any)["functionResponse"].(map[string]any)["response"].(map[string]any)
+	if got, _ := response["error"].(string); !strings.Contains(got, "violates the policy: Paris is off limits") {
+		t.Errorf("function response = %v, want the policy violation", response)
+	}
+	if _, ok := response["output"]; ok {
+		t.Errorf("function response = %v, want no output of the function", response)
+	}
+}
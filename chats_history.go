@@ -0,0 +1,271 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	defaultSummaryKeepTurns = 2
+	defaultSummaryPrompt    = "Summarize the following conversation in a few sentences. Keep the facts, names, decisions and open questions that later messages may refer to.\n\n"
+	summaryPrefix           = "Summary of the earlier conversation:\n"
+)
+
+// TokenCounter counts the tokens of contents, e.g. for a HistoryPolicy. A
+// local tokenizer avoids the API call of Models.CountTokens:
+//
+//	tok, err := tokenizer.NewLocalTokenizer("gemini-2.5-flash")
+//	counter := genai.TokenCounterFunc(func(ctx context.Context, contents []*genai.Content) (int, error) {
+//		result, err := tok.CountTokens(contents, nil)
+//		if err != nil {
+//			return 0, err
+//		}
+//		return int(result.TotalTokens), nil
+//	})
+type TokenCounter interface {
+	CountTokens(ctx context.Context, contents []*Content) (int, error)
+}
+
+// TokenCounterFunc is a function that implements TokenCounter.
+type TokenCounterFunc func(ctx context.Context, contents []*Content) (int, error)
+
+// CountTokens calls f.
+func (f TokenCounterFunc) CountTokens(ctx context.Context, contents []*Content) (int, error) {
+	return f(ctx, contents)
+}
+
+// HistoryPolicy keeps the history of a chat within the context window of the
+// model. Before each message is sent, the chat replaces its curated history
+// with the history returned by Apply; the comprehensive history keeps every
+// turn. See Chat.SetHistoryPolicy.
+type HistoryPolicy interface {
+	// Apply returns the history to send with message, either history itself or
+	// a shortened or compressed copy of it. It must not modify history.
+	Apply(ctx context.Context, chat *Chat, history []*Content, message *Content) ([]*Content, error)
+}
+
+// HistoryEviction describes the contents that a HistoryPolicy removed from the
+// history of a chat.
+type HistoryEviction struct {
+	// The removed contents, oldest first. They are whole turns: a user message
+	// with the model responses, function calls and function responses that
+	// followed it.
+	Evicted []*Content
+	// The content that replaced the removed contents, if they were summarized.
+	Summary *Content
+}
+
+// DropOldestTurns is a HistoryPolicy that keeps the last MaxTurns turns of the
+// history.
+type DropOldestTurns struct {
+	// Required. The maximum number of turns of the history sent with a message.
+	MaxTurns int
+	// Optional. Called when turns are removed from the history.
+	OnEvict func(ctx context.Context, eviction *HistoryEviction)
+}
+
+// Apply implements HistoryPolicy.
+func (p *DropOldestTurns) Apply(ctx context.Context, chat *Chat, history []*Content, message *Content) ([]*Content, error) {
+	turns := historyTurns(history)
+	if len(turns) <= p.MaxTurns {
+		return history, nil
+	}
+	return evictTurns(ctx, turns, len(turns)-max(p.MaxTurns, 0), nil, p.OnEvict), nil
+}
+
+// SlidingTokenWindow is a HistoryPolicy that removes the oldest turns of the
+// history until the history and the message fit in MaxTokens tokens.
+type SlidingTokenWindow struct {
+	// Required. The maximum number of tokens of the history and the message.
+	MaxTokens int
+	// Optional. Counts the tokens of each turn. Defaults to Models.CountTokens
+	// with the model of the chat, which sends a request for every turn.
+	Counter TokenCounter
+	// Optional. Called when turns are removed from the history.
+	OnEvict func(ctx context.Context, eviction *HistoryEviction)
+}
+
+// Apply implements HistoryPolicy.
+func (p *SlidingTokenWindow) Apply(ctx context.Context, chat *Chat, history []*Content, message *Content) ([]*Content, error) {
+	turns := historyTurns(history)
+	counts, total, err := countTurnTokens(ctx, chat, p.Counter, turns, message)
+	if err != nil {
+		return nil, err
+	}
+	evict := 0
+	for ; evict < len(turns) && total > p.MaxTokens; evict++ {
+		total -= counts[evict]
+	}
+	if evict == 0 {
+		return history, nil
+	}
+	return evictTurns(ctx, turns, evict, nil, p.OnEvict), nil
+}
+
+// SummarizeOldestTurns is a HistoryPolicy that, once the history and the
+// message no longer fit in MaxTokens tokens, replaces all but the last
+// KeepTurns turns of the history with a summary generated by the model.
+type SummarizeOldestTurns struct {
+	// Required. The maximum number of tokens of the history and the message.
+	MaxTokens int
+	// Optional. The number of recent turns that are kept as is. Defaults to 2.
+	KeepTurns int
+	// Optional. The model that summarizes the turns. Defaults to the model of
+	// the chat.
+	Model string
+	// Optional. The instruction that precedes the transcript of the turns to
+	// summarize.
+	Prompt string
+	// Optional. Counts the tokens of each turn. Defaults to Models.CountTokens
+	// with the model of the chat, which sends a request for every turn.
+	Counter TokenCounter
+	// Optional. Called when turns are replaced by a summary.
+	OnEvict func(ctx context.Context, eviction *HistoryEviction)
+}
+
+// Apply implements HistoryPolicy.
+func (p *SummarizeOldestTurns) Apply(ctx context.Context, chat *Chat, history []*Content, message *Content) ([]*Content, error) {
+	turns := historyTurns(history)
+	_, total, err := countTurnTokens(ctx, chat, p.Counter, turns, message)
+	if err != nil {
+		return nil, err
+	}
+	keep := p.KeepTurns
+	if keep <= 0 {
+		keep = defaultSummaryKeepTurns
+	}
+	if total <= p.MaxTokens || len(turns) <= keep {
+		return history, nil
+	}
+	evict := len(turns) - keep
+	prompt := p.Prompt
+	if prompt == "" {
+		prompt = defaultSummaryPrompt
+	}
+	var transcript strings.Builder
+	transcript.WriteString(prompt)
+	for _, turn := range turns[:evict] {
+		for _, content := range turn {
+			if text := contentText(content); text != "" {
+				fmt.Fprintf(&transcript, "%s: %s\n", content.Role, text)
+			}
+		}
+	}
+	model := p.Model
+	if model == "" {
+		model = chat.model
+	}
+	response, err := chat.Models.GenerateContent(ctx, model, Text(transcript.String()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize the chat history: %w", err)
+	}
+	summary := NewContentFromText(summaryPrefix+response.Text(), RoleUser)
+	return evictTurns(ctx, turns, evict, summary, p.OnEvict), nil
+}
+
+// SetHistoryPolicy sets the policy that keeps the history of the chat within
+// the context window of the model, e.g. a SlidingTokenWindow. It is applied
+// before each message is sent. A nil policy turns this off.
+func (c *Chat) SetHistoryPolicy(p HistoryPolicy) {
+	c.historyPolicy = p
+}
+
+// applyHistoryPolicy replaces the curated history with the history returned by
+// the history policy for message.
+func (c *Chat) applyHistoryPolicy(ctx context.Context, message *Content) error {
+	if c.historyPolicy == nil {
+		return nil
+	}
+	history, err := c.historyPolicy.Apply(ctx, c, c.curatedHistory, message)
+	if err != nil {
+		return err
+	}
+	c.curatedHistory = history
+	return nil
+}
+
+// historyTurns splits history into turns. A turn starts with a user content
+// that is not only function responses.
+func historyTurns(history []*Content) [][]*Content {
+	var turns [][]*Content
+	for _, content := range history {
+		if len(turns) == 0 || content.Role == RoleUser && !isFunctionResponseContent(content) {
+			turns = append(turns, nil)
+		}
+		turns[len(turns)-1] = append(turns[len(turns)-1], content)
+	}
+	return turns
+}
+
+func isFunctionResponseContent(content *Content) bool {
+	for _, part := range content.Parts {
+		if part == nil || part.FunctionResponse == nil {
+			return false
+		}
+	}
+	return len(content.Parts) > 0
+}
+
+// countTurnTokens returns the tokens of every turn, and their total together
+// with the tokens of message.
+func countTurnTokens(ctx context.Context, chat *Chat, counter TokenCounter, turns [][]*Content, message *Content) ([]int, int, error) {
+	if counter == nil {
+		counter = TokenCounterFunc(func(ctx context.Context, contents []*Content) (int, error) {
+			response, err := chat.Models.CountTokens(ctx, chat.model, contents, nil)
+			if err != nil {
+				return 0, err
+			}
+			return int(response.TotalTokens), nil
+		})
+	}
+	total, err := counter.CountTokens(ctx, []*Content{message})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count the tokens of the message: %w", err)
+	}
+	counts := make([]int, len(turns))
+	for i, turn := range turns {
+		if counts[i], err = counter.CountTokens(ctx, turn); err != nil {
+			return nil, 0, fmt.Errorf("failed to count the tokens of the chat history: %w", err)
+		}
+		total += counts[i]
+	}
+	return counts, total, nil
+}
+
+// evictTurns returns the history without its first evict turns, preceded by
+// summary if it is set, and reports the eviction to onEvict.
+func evictTurns(ctx context.Context, turns [][]*Content, evict int, summary *Content, onEvict func(context.Context, *HistoryEviction)) []*Content {
+	eviction := &HistoryEviction{Summary: summary}
+	for _, turn := range turns[:evict] {
+		eviction.Evicted = append(eviction.Evicted, turn...)
+	}
+	var history []*Content
+	if summary != nil {
+		history = append(history, summary)
+	}
+	for _, turn := range turns[evict:] {
+		history = append(history, turn...)
+	}
+	if history == nil {
+		history = []*Content{}
+	}
+	if onEvict != nil {
+		onEvict(ctx, eviction)
+	}
+	return history
+}
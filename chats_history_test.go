@@ -0,0 +1,186 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// wordCounter counts a token for every word of the text of contents.
+var wordCounter = TokenCounterFunc(func(ctx context.Context, contents []*Content) (int, error) {
+	n := 0
+	for _, content := range contents {
+		n += len(strings.Fields(contentText(content)))
+	}
+	return n, nil
+})
+
+func testHistory() []*Content {
+	return []*Content{
+		NewContentFromText("one two", RoleUser),
+		NewContentFromText("three", RoleModel),
+		NewContentFromText("four five six", RoleUser),
+		{Role: RoleModel, Parts: []*Part{NewPartFromFunctionCall("f", nil)}},
+		{Role: RoleUser, Parts: []*Part{NewPartFromFunctionResponse("f", map[string]any{"output": "ok"})}},
+		NewContentFromText("seven", RoleModel),
+		NewContentFromText("eight", RoleUser),
+		NewContentFromText("nine ten", RoleModel),
+	}
+}
+
+func TestHistoryPolicies(t *testing.T) {
+	ctx := context.Background()
+	history := testHistory()
+	message := NewContentFromText("eleven", RoleUser)
+	tests := []struct {
+		name        string
+		policy      func(onEvict func(context.Context, *HistoryEviction)) HistoryPolicy
+		want        []*Content
+		wantEvicted []*Content
+	}{
+		{
+			name: "drop oldest turns",
+			policy: func(onEvict func(context.Context, *HistoryEviction)) HistoryPolicy {
+				return &DropOldestTurns{MaxTurns: 1, OnEvict: onEvict}
+			},
+			want:        history[6:],
+			wantEvicted: history[:6],
+		},
+		{
+			name: "drop oldest turns within limit",
+			policy: func(onEvict func(context.Context, *HistoryEviction)) HistoryPolicy {
+				return &DropOldestTurns{MaxTurns: 3, OnEvict: onEvict}
+			},
+			want: history,
+		},
+		{
+			name: "sliding token window",
+			policy: func(onEvict func(context.Context, *HistoryEviction)) HistoryPolicy {
+				return &SlidingTokenWindow{MaxTokens: 9, Counter: wordCounter, OnEvict: onEvict}
+			},
+			want:        history[2:],
+			wantEvicted: history[:2],
+		},
+		{
+			name: "sliding token window within limit",
+			policy: func(onEvict func(context.Context, *HistoryEviction)) HistoryPolicy {
+				return &SlidingTokenWindow{MaxTokens: 11, Counter: wordCounter, OnEvict: onEvict}
+			},
+			want: history,
+		},
+		{
+			name: "sliding token window too small for the message",
+			policy: func(onEvict func(context.Context, *HistoryEviction)) HistoryPolicy {
+				return &SlidingTokenWindow{MaxTokens: 0, Counter: wordCounter, OnEvict: onEvict}
+			},
+			want:        []*Content{},
+			wantEvicted: history,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var evictions []*HistoryEviction
+			policy := tt.policy(func(ctx context.Context, e *HistoryEviction) { evictions = append(evictions, e) })
+			got, err := policy.Apply(ctx, &Chat{}, history, message)
+			if err != nil {
+				t.Fatalf("Apply() failed: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Apply() mismatch (-want +got):\n%s", diff)
+			}
+			var wantEvictions []*HistoryEviction
+			if tt.wantEvicted != nil {
+				wantEvictions = []*HistoryEviction{{Evicted: tt.wantEvicted}}
+			}
+			if diff := cmp.Diff(wantEvictions, evictions); diff != "" {
+				t.Errorf("evictions mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChatSummarizeOldestTurns(t *testing.T) {
+	ctx := context.Background()
+	var requests []map[string]any
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		requests = append(requests, req)
+		if strings.Contains(r.URL.Path, "gemini-2.5-flash-lite") {
+			fmt.Fprint(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "Counted to six."}]}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "twelve"}]}, "finishReason": "STOP"}]}`)
+	})
+	history := testHistory()
+	chat, err := client.Chats.Create(ctx, "gemini-2.5-flash", nil, history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var evictions []*HistoryEviction
+	chat.SetHistoryPolicy(&SummarizeOldestTurns{
+		MaxTokens: 9,
+		KeepTurns: 1,
+		Model:     "gemini-2.5-flash-lite",
+		Counter:   wordCounter,
+		OnEvict:   func(ctx context.Context, e *HistoryEviction) { evictions = append(evictions, e) },
+	})
+
+	if _, err := chat.SendMessage(ctx, Part{Text: "eleven"}); err != nil {
+		t.Fatalf("SendMessage() failed: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	wantTranscript := defaultSummaryPrompt + "user: one two\nmodel: three\nuser: four five six\nmodel: seven\n"
+	if diff := cmp.Diff(Text(wantTranscript), requestContents(t, requests[0])); diff != "" {
+		t.Errorf("summary request mismatch (-want +got):\n%s", diff)
+	}
+	summary := NewContentFromText(summaryPrefix+"Counted to six.", RoleUser)
+	wantSent := append([]*Content{summary}, history[6:]...)
+	wantSent = append(wantSent, NewContentFromText("eleven", RoleUser))
+	if diff := cmp.Diff(wantSent, requestContents(t, requests[1])); diff != "" {
+		t.Errorf("sent contents mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]*HistoryEviction{{Evicted: history[:6], Summary: summary}}, evictions); diff != "" {
+		t.Errorf("evictions mismatch (-want +got):\n%s", diff)
+	}
+	if n := len(chat.History(false)); n != len(history)+2 {
+		t.Errorf("History(false) has %d contents, want the whole conversation of %d", n, len(history)+2)
+	}
+}
+
+// requestContents returns the contents of a generate content request.
+func requestContents(t *testing.T, request map[string]any) []*Content {
+	t.Helper()
+	data, err := json.Marshal(request["contents"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []*Content
+	if err := json.Unmarshal(data, &contents); err != nil {
+		t.Fatal(err)
+	}
+	return contents
+}
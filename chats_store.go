@@ -0,0 +1,217 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrChatNotFound is returned by ChatStore.Load for a chat that is not
+// stored.
+var ErrChatNotFound = errors.New("chat not found")
+
+// ChatStore persists the histories of chats by ID, so that conversations
+// survive restarts and can be continued by another process with
+// Chats.Resume.
+//
+// Implementations must be safe for concurrent use.
+type ChatStore interface {
+	// Save replaces the history of the chat id with a copy of history.
+	Save(ctx context.Context, id string, history []*Content) error
+	// Load returns the history of the chat id, or an error wrapping
+	// ErrChatNotFound if it is not stored.
+	Load(ctx context.Context, id string) ([]*Content, error)
+	// List returns the IDs of the stored chats, sorted.
+	List(ctx context.Context) ([]string, error)
+	// Delete removes the chat id. Deleting a chat that is not stored is not an
+	// error.
+	Delete(ctx context.Context, id string) error
+}
+
+// Resume continues the chat id of store, or starts it if it is not stored
+// yet. The history of the chat is saved to store after every turn, so that the
+// conversation can be resumed again, e.g. after a restart or by another
+// replica that shares the store.
+func (c *Chats) Resume(ctx context.Context, model string, config *GenerateContentConfig, id string, store ChatStore) (*Chat, error) {
+	if id == "" {
+		return nil, errors.New("chat id is required")
+	}
+	history, err := store.Load(ctx, id)
+	if err != nil && !errors.Is(err, ErrChatNotFound) {
+		return nil, fmt.Errorf("failed to load chat %s: %w", id, err)
+	}
+	chat, err := c.Create(ctx, model, config, history)
+	if err != nil {
+		return nil, err
+	}
+	chat.id = id
+	chat.store = store
+	return chat, nil
+}
+
+// save saves the comprehensive history of the chat to its store, if it was
+// resumed from one.
+func (c *Chat) save(ctx context.Context) error {
+	if c.store == nil {
+		return nil
+	}
+	if err := c.store.Save(ctx, c.id, c.comprehensiveHistory); err != nil {
+		return fmt.Errorf("failed to save chat %s: %w", c.id, err)
+	}
+	return nil
+}
+
+type inMemoryChatStore struct {
+	mu    sync.Mutex
+	chats map[string][]*Content
+}
+
+// NewInMemoryChatStore returns a ChatStore that keeps the histories in memory.
+func NewInMemoryChatStore() ChatStore {
+	return &inMemoryChatStore{chats: map[string][]*Content{}}
+}
+
+func (s *inMemoryChatStore) Save(ctx context.Context, id string, history []*Content) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats[id] = cloneContents(history)
+	return nil
+}
+
+func (s *inMemoryChatStore) Load(ctx context.Context, id string) ([]*Content, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	history, ok := s.chats[id]
+	if !ok {
+		return nil, fmt.Errorf("chat %s: %w", id, ErrChatNotFound)
+	}
+	return cloneContents(history), nil
+}
+
+func (s *inMemoryChatStore) List(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedKeys(s.chats), nil
+}
+
+func (s *inMemoryChatStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.chats, id)
+	return nil
+}
+
+func cloneContents(contents []*Content) []*Content {
+	clone := make([]*Content, len(contents))
+	for i, content := range contents {
+		clone[i] = cloneOf(content)
+	}
+	return clone
+}
+
+const chatFileSuffix = ".json"
+
+type fileChatStore struct {
+	dir string
+}
+
+// NewFileChatStore returns a ChatStore that keeps the history of every chat in
+// a JSON file in dir, which is created if it does not exist. The files are
+// replaced as a whole, so that processes that share dir never read a
+// partially written history.
+func NewFileChatStore(dir string) (ChatStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create chat store directory: %w", err)
+	}
+	return &fileChatStore{dir: dir}, nil
+}
+
+func (s *fileChatStore) path(id string) string {
+	return filepath.Join(s.dir, url.PathEscape(id)+chatFileSuffix)
+}
+
+func (s *fileChatStore) Save(ctx context.Context, id string, history []*Content) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat history: %w", err)
+	}
+	path := s.path(id)
+	f, err := os.CreateTemp(s.dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write chat file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write chat file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write chat file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write chat file: %w", err)
+	}
+	return nil
+}
+
+func (s *fileChatStore) Load(ctx context.Context, id string) ([]*Content, error) {
+	data, err := os.ReadFile(s.path(id))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("chat %s: %w", id, ErrChatNotFound)
+	case err != nil:
+		return nil, fmt.Errorf("failed to read chat file: %w", err)
+	}
+	var history []*Content
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse chat file %s: %w", s.path(id), err)
+	}
+	return history, nil
+}
+
+func (s *fileChatStore) List(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat files: %w", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), chatFileSuffix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if id, err := url.PathUnescape(name); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (s *fileChatStore) Delete(ctx context.Context, id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete chat file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestChatStores(t *testing.T) {
+	ctx := context.Background()
+	fileStore, err := NewFileChatStore(filepath.Join(t.TempDir(), "chats"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores := map[string]ChatStore{
+		"in memory": NewInMemoryChatStore(),
+		"file":      fileStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			if _, err := store.Load(ctx, "missing"); !errors.Is(err, ErrChatNotFound) {
+				t.Errorf("Load() of a missing chat = %v, want ErrChatNotFound", err)
+			}
+			history := []*Content{NewContentFromText("Hi", RoleUser), NewContentFromText("Hello!", RoleModel)}
+			for _, id := range []string{"users/b/chat", "a"} {
+				if err := store.Save(ctx, id, history); err != nil {
+					t.Fatalf("Save(%q) failed: %v", id, err)
+				}
+			}
+			history[0].Parts[0].Text = "changed"
+			got, err := store.Load(ctx, "users/b/chat")
+			if err != nil {
+				t.Fatalf("Load() failed: %v", err)
+			}
+			want := []*Content{NewContentFromText("Hi", RoleUser), NewContentFromText("Hello!", RoleModel)}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+			}
+			ids, err := store.List(ctx)
+			if err != nil {
+				t.Fatalf("List() failed: %v", err)
+			}
+			if diff := cmp.Diff([]string{"a", "users/b/chat"}, ids); diff != "" {
+				t.Errorf("List() mismatch (-want +got):\n%s", diff)
+			}
+			if err := store.Delete(ctx, "a"); err != nil {
+				t.Fatalf("Delete() failed: %v", err)
+			}
+			if err := store.Delete(ctx, "a"); err != nil {
+				t.Errorf("Delete() of a deleted chat failed: %v", err)
+			}
+			if _, err := store.Load(ctx, "a"); !errors.Is(err, ErrChatNotFound) {
+				t.Errorf("Load() of a deleted chat = %v, want ErrChatNotFound", err)
+			}
+		})
+	}
+}
+
+func TestChatsResume(t *testing.T) {
+	ctx := context.Background()
+	turn := 0
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		turn++
+		response := fmt.Sprintf(`{"candidates": [{"content": {"role": "model", "parts": [{"text": "answer %d"}]}, "finishReason": "STOP"}]}`, turn)
+		if strings.Contains(r.URL.Path, ":streamGenerateContent") {
+			response = "data: " + response + "\n\n"
+		}
+		fmt.Fprint(w, response)
+	})
+	dir := t.TempDir()
+	store, err := NewFileChatStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chat, err := client.Chats.Resume(ctx, "gemini-2.5-flash", nil, "support-42", store)
+	if err != nil {
+		t.Fatalf("Resume() of a new chat failed: %v", err)
+	}
+	if _, err := chat.SendMessage(ctx, Part{Text: "question 1"}); err != nil {
+		t.Fatalf("SendMessage() failed: %v", err)
+	}
+
+	// Another process resumes the chat from the same directory.
+	store, err = NewFileChatStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := client.Chats.Resume(ctx, "gemini-2.5-flash", nil, "support-42", store)
+	if err != nil {
+		t.Fatalf("Resume() failed: %v", err)
+	}
+	for _, err := range resumed.SendMessageStream(ctx, Part{Text: "question 2"}) {
+		if err != nil {
+			t.Fatalf("SendMessageStream() failed: %v", err)
+		}
+	}
+	want := []*Content{
+		NewContentFromText("question 1", RoleUser),
+		NewContentFromText("answer 1", RoleModel),
+		NewContentFromText("question 2", RoleUser),
+		NewContentFromText("answer 2", RoleModel),
+	}
+	got, err := store.Load(ctx, "support-42")
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("saved history mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Chats.Resume(ctx, "gemini-2.5-flash", nil, "broken", store); err == nil {
+		t.Errorf("Resume() of a corrupt chat succeeded, want error")
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// streamContentMerger merges the contents of the chunks of a streamed response
+// into the single content that the response would have had if it was not
+// streamed: consecutive text parts are joined, and function calls streamed
+// over several chunks, with WillContinue and PartialArgs, are assembled into
+// one function call with its complete Args.
+type streamContentMerger struct {
+	content *Content
+	// The function call that is continued by the next chunks, if any.
+	pending *FunctionCall
+	// The JSON paths of the pending function call whose string values are
+	// continued by the next partial args.
+	continuing map[string]bool
+}
+
+// mergeStreamContents returns the merged contents of the chunks of a streamed
+// response, or nil if there are none.
+func mergeStreamContents(contents []*Content) *Content {
+	var m streamContentMerger
+	for _, content := range contents {
+		m.add(content)
+	}
+	return m.content
+}
+
+// add merges the content of the next chunk.
+func (m *streamContentMerger) add(content *Content) {
+	if content == nil {
+		return
+	}
+	if m.content == nil {
+		m.content = &Content{Role: content.Role, Parts: []*Part{}}
+	}
+	if m.content.Role == "" {
+		m.content.Role = content.Role
+	}
+	for _, part := range content.Parts {
+		if part != nil {
+			m.addPart(part)
+		}
+	}
+}
+
+func (m *streamContentMerger) addPart(part *Part) {
+	if part.FunctionCall != nil {
+		m.addFunctionCall(part)
+		return
+	}
+	m.pending = nil
+	if last := m.lastPart(); isTextPart(part) && last != nil && isTextPart(last) && last.Thought == part.Thought && last.ThoughtSignature == nil {
+		last.Text += part.Text
+		last.ThoughtSignature = part.ThoughtSignature
+		return
+	}
+	clone := *part
+	m.content.Parts = append(m.content.Parts, &clone)
+}
+
+func (m *streamContentMerger) addFunctionCall(part *Part) {
+	fc := part.FunctionCall
+	call := m.pending
+	if call == nil {
+		call = &FunctionCall{ID: fc.ID, Name: fc.Name}
+		clone := *part
+		clone.FunctionCall = call
+		m.content.Parts = append(m.content.Parts, &clone)
+		m.continuing = map[string]bool{}
+	} else {
+		if call.ID == "" {
+			call.ID = fc.ID
+		}
+		if call.Name == "" {
+			call.Name = fc.Name
+		}
+		if part.ThoughtSignature != nil {
+			m.lastPart().ThoughtSignature = part.ThoughtSignature
+		}
+	}
+	for k, v := range fc.Args {
+		if call.Args == nil {
+			call.Args = map[string]any{}
+		}
+		call.Args[k] = v
+	}
+	for _, arg := range fc.PartialArgs {
+		if arg != nil {
+			m.applyPartialArg(call, arg)
+		}
+	}
+	m.pending = nil
+	if fc.WillContinue != nil && *fc.WillContinue {
+		m.pending = call
+	}
+}
+
+// applyPartialArg sets the value of arg in the args of call. Partial args
+// whose JSON path is not supported are kept in the PartialArgs of call.
+func (m *streamContentMerger) applyPartialArg(call *FunctionCall, arg *PartialArg) {
+	path, err := parseJSONPath(arg.JsonPath)
+	if err != nil || len(path) == 0 {
+		call.PartialArgs = append(call.PartialArgs, arg)
+		return
+	}
+	if _, ok := path[0].(string); !ok {
+		call.PartialArgs = append(call.PartialArgs, arg)
+		return
+	}
+	var value any
+	switch {
+	case arg.NULLValue != "":
+		value = nil
+	case arg.NumberValue != nil:
+		value = *arg.NumberValue
+	case arg.BoolValue != nil:
+		value = *arg.BoolValue
+	default:
+		value = arg.StringValue
+	}
+	if call.Args == nil {
+		call.Args = map[string]any{}
+	}
+	call.Args = setJSONPath(call.Args, path, value, m.continuing[arg.JsonPath]).(map[string]any)
+	m.continuing[arg.JsonPath] = arg.WillContinue != nil && *arg.WillContinue
+}
+
+func (m *streamContentMerger) lastPart() *Part {
+	if len(m.content.Parts) == 0 {
+		return nil
+	}
+	return m.content.Parts[len(m.content.Parts)-1]
+}
+
+// isTextPart reports whether part only has text, possibly a thought.
+func isTextPart(part *Part) bool {
+	return part.MediaResolution == nil && part.CodeExecutionResult == nil && part.ExecutableCode == nil &&
+		part.FileData == nil && part.FunctionCall == nil && part.FunctionResponse == nil &&
+		part.InlineData == nil && part.VideoMetadata == nil
+}
+
+// parseJSONPath parses a JSON path of the form $.a.b[0]['c'] into its
+// segments, strings for member names and ints for array indexes.
+func parseJSONPath(path string) ([]any, error) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, fmt.Errorf("JSON path %q does not start with $", path)
+	}
+	var segments []any
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("JSON path %q has an empty member name", path)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		case strings.HasPrefix(rest, "['") || strings.HasPrefix(rest, `["`):
+			quote := rest[1]
+			end := strings.IndexByte(rest[2:], quote)
+			if end < 0 || !strings.HasPrefix(rest[2+end+1:], "]") {
+				return nil, fmt.Errorf("JSON path %q has an unterminated member name", path)
+			}
+			segments = append(segments, rest[2:2+end])
+			rest = rest[2+end+2:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSON path %q has an unterminated index", path)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("JSON path %q has an invalid index %q", path, rest[1:end])
+			}
+			segments = append(segments, index)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSON path %q is not supported", path)
+		}
+	}
+	return segments, nil
+}
+
+// setJSONPath returns container with value set at path, creating the objects
+// and arrays on the way. If appendString is true and both the existing value
+// and value are strings, value is appended to the existing value.
+func setJSONPath(container any, path []any, value any, appendString bool) any {
+	if len(path) == 0 {
+		if s, ok := container.(string); ok && appendString {
+			if v, ok := value.(string); ok {
+				return s + v
+			}
+		}
+		return value
+	}
+	switch segment := path[0].(type) {
+	case int:
+		array, _ := container.([]any)
+		for len(array) <= segment {
+			array = append(array, nil)
+		}
+		array[segment] = setJSONPath(array[segment], path[1:], value, appendString)
+		return array
+	default:
+		object, ok := container.(map[string]any)
+		if !ok {
+			object = map[string]any{}
+		}
+		name := segment.(string)
+		object[name] = setJSONPath(object[name], path[1:], value, appendString)
+		return object
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMergeStreamContents(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents []*Content
+		want     *Content
+	}{
+		{
+			name: "no contents",
+		},
+		{
+			name: "text",
+			contents: []*Content{
+				{Role: RoleModel, Parts: []*Part{{Text: "Let me ", Thought: true}, {Text: "think.", Thought: true}}},
+				{Role: RoleModel, Parts: []*Part{{Text: "1 + "}}},
+				{Role: RoleModel, Parts: []*Part{{Text: "2 = 3", ThoughtSignature: []byte("sig")}}},
+				{Role: RoleModel, Parts: []*Part{{Text: "."}}},
+			},
+			want: &Content{Role: RoleModel, Parts: []*Part{
+				{Text: "Let me think.", Thought: true},
+				{Text: "1 + 2 = 3", ThoughtSignature: []byte("sig")},
+				{Text: "."},
+			}},
+		},
+		{
+			name: "function calls with partial args",
+			contents: []*Content{
+				{Role: RoleModel, Parts: []*Part{{Text: "Checking."}, {FunctionCall: &FunctionCall{Name: "get_weather", WillContinue: Ptr(true)}}}},
+				{Role: RoleModel, Parts: []*Part{{FunctionCall: &FunctionCall{WillContinue: Ptr(true), PartialArgs: []*PartialArg{
+					{JsonPath: "$.location.city", StringValue: "San ", WillContinue: Ptr(true)},
+				}}}}},
+				{Role: RoleModel, Parts: []*Part{{FunctionCall: &FunctionCall{WillContinue: Ptr(true), PartialArgs: []*PartialArg{
+					{JsonPath: "$.location.city", StringValue: "Francisco"},
+					{JsonPath: "$.days[1]", NumberValue: Ptr(2.0)},
+					{JsonPath: "$['metric']", BoolValue: Ptr(true)},
+					{JsonPath: "$[0]", StringValue: "kept"},
+				}}}}},
+				{Role: RoleModel, Parts: []*Part{{FunctionCall: &FunctionCall{}, ThoughtSignature: []byte("sig")}}},
+				{Role: RoleModel, Parts: []*Part{{FunctionCall: &FunctionCall{Name: "get_time", Args: map[string]any{"zone": "PST"}}}}},
+			},
+			want: &Content{Role: RoleModel, Parts: []*Part{
+				{Text: "Checking."},
+				{
+					FunctionCall: &FunctionCall{
+						Name: "get_weather",
+						Args: map[string]any{
+							"location": map[string]any{"city": "San Francisco"},
+							"days":     []any{nil, 2.0},
+							"metric":   true,
+						},
+						PartialArgs: []*PartialArg{{JsonPath: "$[0]", StringValue: "kept"}},
+					},
+					ThoughtSignature: []byte("sig"),
+				},
+				{FunctionCall: &FunctionCall{Name: "get_time", Args: map[string]any{"zone": "PST"}}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeStreamContents(tt.contents)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("mergeStreamContents() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChatSendStreamMergesHistory(t *testing.T) {
+	ctx := context.Background()
+	chunks := []string{
+		`{"candidates": [{"content": {"role": "model", "parts": [{"text": "Checking "}]}}]}`,
+		`{"candidates": [{"content": {"role": "model", "parts": [{"text": "the weather."}, {"functionCall": {"name": "get_weather", "willContinue": true}}]}}]}`,
+		`{"candidates": [{"content": {"role": "model", "parts": [{"functionCall": {"partialArgs": [{"jsonPath": "$.city", "stringValue": "Par", "willContinue": true}], "willContinue": true}}]}}]}`,
+		`{"candidates": [{"content": {"role": "model", "parts": [{"functionCall": {"partialArgs": [{"jsonPath": "$.city", "stringValue": "is"}]}}]}, "finishReason": "STOP"}]}`,
+	}
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		for _, chunk := range chunks {
+			fmt.Fprint(w, "data: "+chunk+"\n\n")
+		}
+	})
+	chat, err := client.Chats.Create(ctx, "gemini-2.5-flash", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, err := range chat.SendMessageStream(ctx, Part{Text: "Weather in Paris?"}) {
+		if err != nil {
+			t.Fatalf("SendMessageStream() failed: %v", err)
+		}
+		n++
+	}
+	if n != len(chunks) {
+		t.Errorf("SendMessageStream() yielded %d chunks, want %d", n, len(chunks))
+	}
+	want := []*Content{
+		NewContentFromText("Weather in Paris?", RoleUser),
+		{Role: RoleModel, Parts: []*Part{
+			{Text: "Checking the weather."},
+			NewPartFromFunctionCall("get_weather", map[string]any{"city": "Paris"}),
+		}},
+	}
+	if diff := cmp.Diff(want, chat.History(true)); diff != "" {
+		t.Errorf("History(true) mismatch (-want +got):\n%s", diff)
+	}
+	if got := len(chat.History(false)); got != len(chunks)+1 {
+		t.Errorf("History(false) has %d contents, want the message and every chunk", got)
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -52,6 +53,8 @@ type Client struct {
 	AuthTokens *Tokens
 	// Interactions provides access to the Interactions service.
 	Interactions *Interactions
+	// RAGCorpora provides access to the Vertex AI RAG corpora.
+	RAGCorpora *RAGCorpora
 }
 
 // Backend is the GenAI backend to use for the client.
@@ -94,30 +97,157 @@ type ClientConfig struct {
 	Backend Backend
 
 	// Optional. GCP Project ID for Vertex AI. Required for BackendVertexAI.
+	// For BackendGeminiAPI, it is the quota project of Credentials that have
+	// none.
 	// Can also be set via the GOOGLE_CLOUD_PROJECT environment variable.
 	// Find your Project ID: https://cloud.google.com/resource-manager/docs/creating-managing-projects#identifying_projects
 	Project string
 
-	// Optional. GCP Location/Region for Vertex AI. Required for BackendVertexAI.
+	// Optional. GCP Location/Region for Vertex AI, e.g. "us-central1", or
+	// "global" for the global endpoint. Defaults to "global" for
+	// BackendVertexAI. Requests are sent to the endpoint of the location
+	// unless HTTPOptions.BaseURL is set, and RequestOptions.Location overrides
+	// it per request. Only the form of the location is checked when the client is
+	// created; whether a region exists and serves a given model is reported
+	// by the API when a request is sent. The model of a request can also be
+	// a deployed endpoint or a tuned model, e.g.
+	// "projects/my-project/locations/europe-west4/endpoints/123", or
+	// "endpoints/123" for an endpoint in Location; requests for resources in
+	// another location are sent to the endpoint of that location.
 	// Can also be set via the GOOGLE_CLOUD_LOCATION or GOOGLE_CLOUD_REGION environment variable.
 	// Generative AI locations: https://cloud.google.com/vertex-ai/generative-ai/docs/learn/locations.
 	Location string
 
 	// Optional. Google credentials.  If not specified, [Application Default Credentials] will be used.
 	//
+	// For BackendGeminiAPI, credentials such as OAuth user credentials can be
+	// used instead of an API key, e.g. for tools acting on behalf of end users.
+	// They need the [GeminiAPIScopes] and a quota project, either their own or
+	// Project, which is sent in the X-Goog-User-Project header. Tokens are
+	// refreshed by the token provider of the credentials.
+	//
 	// [Application Default Credentials]: https://developers.google.com/accounts/docs/application-default-credentials
 	Credentials *auth.Credentials
 
+	// Optional. Source of the OAuth access tokens of the client, e.g. a
+	// workload identity broker, for both backends. Tokens are cached and
+	// refreshed shortly before they expire. Project is the quota project of
+	// the requests. Mutually exclusive with Credentials and APIKey.
+	TokenProvider TokenProvider
+
+	// Optional. Private Service Connect endpoint for Vertex AI, e.g.
+	// "us-central1-aiplatform-myendpoint.p.googleapis.com" or "10.128.0.2".
+	// If set, requests are sent to this endpoint instead of the public Vertex
+	// AI endpoint. Ignored if HTTPOptions.BaseURL is set.
+	PSCEndpoint string
+
+	// Optional. Audience of the tokens of the default credentials for Vertex
+	// AI, e.g. "https://aiplatform.googleapis.com/". Set it when the tokens
+	// must name the public service although requests go to a PSCEndpoint or
+	// a proxy. Only applicable to service account credentials and unused if
+	// Credentials or HTTPClient is set.
+	CredentialsAudience string
+
 	// Optional HTTP client to use. If nil, a default client will be created.
 	// For Vertex AI, this client must handle authentication appropriately.
 	// Otherwise, call [UseDefaultCredentials] convenience method to add default credentials to the
 	// client.
 	HTTPClient *http.Client
 
+	// Optional. Configures the transport of the HTTP client created by
+	// NewClient, e.g. a proxy, a custom dialer, TLS certificates or the size
+	// of the connection pool. Ignored if HTTPClient is set. See
+	// [HTTPClientOptions].
+	HTTPClientOptions *HTTPClientOptions
+
 	// Optional HTTP options to override.
 	HTTPOptions HTTPOptions
 
+	// Optional. Options of the SDK for all the API calls of the client, e.g.
+	// to retain the raw responses. [WithRequestOptions] overrides them per
+	// call. See [RequestOptions].
+	RequestOptions RequestOptions
+
+	// Optional. Labels attached to the Vertex AI requests that support
+	// labels, i.e. to generate content, image, batch and tuning requests, e.g.
+	// to break down the spend per feature or team in billing exports. Labels
+	// of RequestOptions and of the config of a request take precedence. Not
+	// supported in Gemini API.
+	Labels map[string]string
+
+	// Optional. Model used when an empty model name is passed to Models,
+	// Chats or Interactions methods, e.g. "gemini-2.5-flash". On Vertex AI,
+	// Models.EmbedContent chooses between the embedContent and predict APIs
+	// by the model argument, so pass Gemini embedding models explicitly.
+	// Can also be set via the GOOGLE_GENAI_DEFAULT_MODEL environment variable.
+	DefaultModel string
+
+	// Optional. Config that every GenerateContent and GenerateContentStream
+	// call, including the calls of Chats, is merged on top of, e.g. for
+	// default safety settings or a maximum number of output tokens. The
+	// config of a call takes precedence, see [GenerateContentConfig.Merge].
+	// Its HTTPOptions are not used, set ClientConfig.HTTPOptions instead.
+	DefaultGenerateContentConfig *GenerateContentConfig
+
+	// Optional. Generation config that the GenerationConfig of every
+	// interaction created by Interactions is merged on top of. The config of
+	// the interaction takes precedence, see
+	// [InteractionGenerationConfig.Merge].
+	DefaultInteractionGenerationConfig *InteractionGenerationConfig
+
+	// Optional. Called for each warning or deprecation notice found in the
+	// response headers of an API call, so that sunsetting models and fields are
+	// noticed before they break. If nil, the warnings are logged. Warnings are
+	// also available from [HTTPResponse.Warnings].
+	OnServerWarning func(w *ServerWarning)
+
+	// Optional. Surfaces fields in API responses that the SDK types do not
+	// model, either through a warning callback or as an error. See
+	// [StrictDecodingConfig]. If nil, unknown fields are ignored.
+	StrictDecoding *StrictDecodingConfig
+
+	// Optional. Validators applied to the user content sent to the model and
+	// the text it generates, for Models, Chats and Interactions. See
+	// [Guardrails].
+	Guardrails *Guardrails
+
+	// Optional. Retries API calls that fail with a retryable status code or
+	// a network error, with exponential backoff. RequestOptions.RetryOptions
+	// of a call take precedence. If nil, calls are not retried. See
+	// [RetryOptions].
+	RetryOptions *RetryOptions
+
+	// Optional. Limits the rate and concurrency of the API calls of all the
+	// services of the client, e.g. to stay within the quotas of the project.
+	// See [RateLimit].
+	RateLimit *RateLimit
+
+	// Optional. Logger of the API calls of the client. Requests and
+	// responses are logged at slog.LevelDebug with their method, URL, status
+	// and duration, failed calls at slog.LevelWarn. API keys and
+	// authorization headers are redacted. If nil, nothing is logged.
+	Logger *slog.Logger
+
+	// Optional. Level at which Logger logs the bodies of requests and
+	// responses and every chunk of streamed responses, with inline media
+	// bytes redacted and long values truncated. Defaults to slog.LevelDebug;
+	// use e.g. slog.LevelDebug-4 to only log bodies with verbose handlers.
+	LogBodyLevel *slog.Level
+
+	// Optional. Interceptors of every unary and streaming API call, the
+	// first one outermost, e.g. to rotate authorization headers or audit
+	// requests. See [Interceptor].
+	Interceptors []Interceptor
+
+	// Optional. Records the HTTP traffic of the client to a cassette file, or
+	// replays it without sending requests, e.g. for hermetic integration
+	// tests. See [ReplayOptions].
+	ReplayOptions *ReplayOptions
+
 	envVarProvider func() map[string]string
+	// tokenCredentials are the Credentials that NewClient created from
+	// TokenProvider.
+	tokenCredentials *auth.Credentials
 }
 
 func defaultEnvVarProvider() map[string]string {
@@ -146,6 +276,9 @@ func defaultEnvVarProvider() map[string]string {
 	if v, ok := os.LookupEnv("GOOGLE_VERTEX_BASE_URL"); ok {
 		vars["GOOGLE_VERTEX_BASE_URL"] = v
 	}
+	if v, ok := os.LookupEnv("GOOGLE_GENAI_DEFAULT_MODEL"); ok {
+		vars["GOOGLE_GENAI_DEFAULT_MODEL"] = v
+	}
 	return vars
 }
 
@@ -185,6 +318,11 @@ func getAPIKeyFromEnv(envVars map[string]string) string {
 //   - GOOGLE_CLOUD_LOCATION or GOOGLE_CLOUD_REGION: Required. Specifies the GCP
 //     location/region.
 //
+//   - Environment Variables for both backends:
+//
+//   - GOOGLE_GENAI_DEFAULT_MODEL: Optional. Specifies the model used when an
+//     empty model name is passed, see [ClientConfig.DefaultModel].
+//
 // If using the Vertex AI backend and no credentials are provided in the
 // ClientConfig, the client will attempt to use application default credentials.
 func NewClient(ctx context.Context, cc *ClientConfig) (*Client, error) {
@@ -198,13 +336,26 @@ func NewClient(ctx context.Context, cc *ClientConfig) (*Client, error) {
 	envVars := cc.envVarProvider()
 
 	if cc.Project != "" && cc.APIKey != "" {
-		return nil, fmt.Errorf("project and API key are mutually exclusive in the client initializer. ClientConfig: %#v", cc)
+		return nil, fmt.Errorf("project and API key are mutually exclusive in the client initializer. ClientConfig: %v", cc)
 	}
 	if cc.Location != "" && cc.APIKey != "" {
-		return nil, fmt.Errorf("location and API key are mutually exclusive in the client initializer. ClientConfig: %#v", cc)
+		return nil, fmt.Errorf("location and API key are mutually exclusive in the client initializer. ClientConfig: %v", cc)
 	}
 	if cc.Credentials != nil && cc.APIKey != "" {
-		return nil, fmt.Errorf("credentials and API key are mutually exclusive in the client initializer. ClientConfig: %#v", cc)
+		return nil, fmt.Errorf("credentials and API key are mutually exclusive in the client initializer. ClientConfig: %v", cc)
+	}
+
+	if cc.TokenProvider != nil {
+		if cc.APIKey != "" {
+			return nil, fmt.Errorf("token provider and API key are mutually exclusive in the client initializer. ClientConfig: %v", cc)
+		}
+		if cc.Credentials != nil && cc.Credentials != cc.tokenCredentials {
+			return nil, fmt.Errorf("token provider and credentials are mutually exclusive in the client initializer. ClientConfig: %v", cc)
+		}
+	}
+
+	if cc.Credentials != nil && cc.CredentialsAudience != "" {
+		return nil, fmt.Errorf("credentials and credentials audience are mutually exclusive in the client initializer. ClientConfig: %v", cc)
 	}
 
 	if cc.Backend == BackendUnspecified {
@@ -241,6 +392,14 @@ func NewClient(ctx context.Context, cc *ClientConfig) (*Client, error) {
 	if cc.Location == "" {
 		cc.Location = envLocation
 	}
+	if cc.DefaultModel == "" {
+		cc.DefaultModel = envVars["GOOGLE_GENAI_DEFAULT_MODEL"]
+	}
+
+	if cc.TokenProvider != nil {
+		cc.tokenCredentials = tokenProviderCredentials(cc.TokenProvider, cc.Project)
+		cc.Credentials = cc.tokenCredentials
+	}
 
 	if cc.Backend == BackendVertexAI {
 		// Handle when to use Vertex AI in express mode (api key).
@@ -266,36 +425,55 @@ func NewClient(ctx context.Context, cc *ClientConfig) (*Client, error) {
 		if cc.Location == "" && cc.APIKey == "" {
 			cc.Location = "global"
 		}
+		if cc.Location != "" {
+			if err := validateLocation(cc.Location); err != nil {
+				return nil, err
+			}
+		}
 
 		if (cc.Project == "" || cc.Location == "") && cc.APIKey == "" {
-			return nil, fmt.Errorf("project/location or API key must be set when using Vertex AI backend. ClientConfig: %#v", cc)
+			return nil, fmt.Errorf("project/location or API key must be set when using Vertex AI backend. ClientConfig: %v", cc)
 		}
 	} else {
 		// Mldev API
-		if cc.APIKey == "" {
-			return nil, fmt.Errorf("api key is required for Google AI backend. ClientConfig: %#v.\nYou can get the API key from https://ai.google.dev/gemini-api/docs/api-key", cc)
+		if cc.Credentials != nil && envAPIKey != "" {
+			log.Println("Warning: The user provided Google credentials will take precedence over the API key from the environment variable.")
+			cc.APIKey = ""
+		}
+		if cc.APIKey == "" && cc.Credentials == nil && !cc.ReplayOptions.replaying() {
+			return nil, fmt.Errorf("api key or credentials are required for Google AI backend. ClientConfig: %v.\nYou can get the API key from https://ai.google.dev/gemini-api/docs/api-key", cc)
+		}
+		if cc.PSCEndpoint != "" || cc.CredentialsAudience != "" {
+			return nil, fmt.Errorf("PSC endpoint and credentials audience are only supported in the Vertex AI client. ClientConfig: %v", cc)
+		}
+		if len(cc.Labels) > 0 {
+			return nil, fmt.Errorf("labels are only supported in the Vertex AI client. ClientConfig: %v", cc)
 		}
 	}
+	if err := validateLabels(cc.Labels); err != nil {
+		return nil, err
+	}
 
-	if cc.Backend == BackendVertexAI && cc.Credentials == nil && cc.APIKey == "" && cc.HTTPClient == nil {
-		cred, err := credentials.DetectDefault(&credentials.DetectOptions{
-			Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
-		})
+	if cc.Backend == BackendVertexAI && cc.Credentials == nil && cc.APIKey == "" && cc.HTTPClient == nil && !cc.ReplayOptions.replaying() {
+		cred, err := detectDefaultCredentials(cc.CredentialsAudience)
 		if err != nil {
-			return nil, fmt.Errorf("failed to find default credentials: %w", err)
+			return nil, err
 		}
 		cc.Credentials = cred
 	}
 
+	if cc.PSCEndpoint != "" && cc.HTTPOptions.BaseURL == "" {
+		cc.HTTPOptions.BaseURL = pscBaseURL(cc.PSCEndpoint)
+	}
 	baseURL := getBaseURL(cc.Backend, &cc.HTTPOptions, envVars)
 	if baseURL != "" {
 		cc.HTTPOptions.BaseURL = baseURL
 	}
 	if cc.HTTPOptions.BaseURL == "" && cc.Backend == BackendVertexAI {
-		if cc.Location == "global" || cc.APIKey != "" {
+		if cc.APIKey != "" {
 			cc.HTTPOptions.BaseURL = "https://aiplatform.googleapis.com/"
 		} else {
-			cc.HTTPOptions.BaseURL = fmt.Sprintf("https://%s-aiplatform.googleapis.com/", cc.Location)
+			cc.HTTPOptions.BaseURL = fmt.Sprintf("https://%s/", vertexAIHost(cc.Location))
 		}
 	} else if cc.HTTPOptions.BaseURL == "" {
 		cc.HTTPOptions.BaseURL = "https://generativelanguage.googleapis.com/"
@@ -307,9 +485,26 @@ func NewClient(ctx context.Context, cc *ClientConfig) (*Client, error) {
 		cc.HTTPOptions.APIVersion = "v1beta"
 	}
 
+	var transport *http.Transport
+	if cc.HTTPClient == nil && cc.HTTPClientOptions != nil {
+		t, err := cc.HTTPClientOptions.transport()
+		if err != nil {
+			return nil, fmt.Errorf("invalid HTTP client options: %w", err)
+		}
+		transport = t
+	}
+	// base is nil rather than a nil *http.Transport if there is no transport.
+	var base http.RoundTripper
+	if transport != nil {
+		base = transport
+	}
+
 	if cc.HTTPClient == nil {
 		// x-goog-api-key header is set for Express mode in api_client.go
-		if cc.Backend == BackendVertexAI && cc.APIKey == "" {
+		if cc.ReplayOptions.replaying() {
+			// Replayed requests are not sent, so they need no credentials.
+			cc.HTTPClient = &http.Client{Transport: base}
+		} else if cc.Backend == BackendVertexAI && cc.APIKey == "" {
 			quotaProjectID, err := cc.Credentials.QuotaProjectID(ctx)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get quota project ID: %w", err)
@@ -319,21 +514,40 @@ func NewClient(ctx context.Context, cc *ClientConfig) (*Client, error) {
 				Headers: http.Header{
 					"X-Goog-User-Project": []string{quotaProjectID},
 				},
+				BaseRoundTripper: base,
 			})
 			if err != nil {
 				return nil, fmt.Errorf("failed to create HTTP client: %w", err)
 			}
 			cc.HTTPClient = client
+		} else if cc.Credentials != nil && cc.APIKey == "" {
+			client, err := geminiAPIHTTPClient(ctx, cc.Credentials, cc.Project, base)
+			if err != nil {
+				return nil, err
+			}
+			cc.HTTPClient = client
 		} else {
-			cc.HTTPClient = &http.Client{}
+			cc.HTTPClient = &http.Client{Transport: base}
 		}
 	}
 
-	ac := &apiClient{clientConfig: cc}
+	if cc.ReplayOptions != nil && cc.ReplayOptions.Mode != ReplayModeOff {
+		if _, ok := cc.HTTPClient.Transport.(*replayTransport); !ok {
+			rt, err := newReplayTransport(cc.ReplayOptions, cc.HTTPClient.Transport)
+			if err != nil {
+				return nil, fmt.Errorf("invalid replay options: %w", err)
+			}
+			client := *cc.HTTPClient
+			client.Transport = rt
+			cc.HTTPClient = &client
+		}
+	}
+
+	ac := &apiClient{clientConfig: cc, limiter: newRateLimiter(cc.RateLimit), transport: transport}
 	c := &Client{
 		clientConfig:     *cc,
 		Models:           &Models{apiClient: ac},
-		Live:             &Live{apiClient: ac},
+		Live:             &Live{apiClient: ac, Music: &LiveMusic{apiClient: ac}},
 		Caches:           &Caches{apiClient: ac},
 		Chats:            &Chats{apiClient: ac},
 		Operations:       &Operations{apiClient: ac},
@@ -343,6 +557,7 @@ func NewClient(ctx context.Context, cc *ClientConfig) (*Client, error) {
 		Tunings:          &Tunings{apiClient: ac},
 		AuthTokens:       &Tokens{apiClient: ac},
 		Interactions:     &Interactions{apiClient: ac},
+		RAGCorpora:       &RAGCorpora{apiClient: ac},
 	}
 	return c, nil
 }
@@ -365,11 +580,9 @@ func (cc *ClientConfig) UseDefaultCredentials() error {
 		return fmt.Errorf("Credentials are already set")
 	}
 	if cc.Credentials == nil {
-		cred, err := credentials.DetectDefault(&credentials.DetectOptions{
-			Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
-		})
+		cred, err := detectDefaultCredentials(cc.CredentialsAudience)
 		if err != nil {
-			return fmt.Errorf("failed to find default credentials: %w", err)
+			return err
 		}
 		cc.Credentials = cred
 	}
@@ -381,3 +594,29 @@ func (cc *ClientConfig) UseDefaultCredentials() error {
 	}
 	return nil
 }
+
+// detectDefaultCredentials returns the application default credentials with
+// the cloud-platform scope, or with audience if it is set.
+func detectDefaultCredentials(audience string) (*auth.Credentials, error) {
+	opts := &credentials.DetectOptions{Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"}}
+	if audience != "" {
+		opts = &credentials.DetectOptions{Audience: audience}
+	}
+	cred, err := credentials.DetectDefault(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find default credentials: %w", err)
+	}
+	return cred, nil
+}
+
+// pscBaseURL returns the base URL of a Private Service Connect endpoint,
+// which may be given as a host name, an IP address or a URL.
+func pscBaseURL(endpoint string) string {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+	return endpoint
+}
@@ -16,8 +16,12 @@ package genai
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,6 +30,49 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
+// newTestClient returns a client whose requests are served by handler on a
+// test server that is closed when the test ends. The fields of config that are
+// not set default to the test server and to test credentials of its backend,
+// the Gemini API if config is nil, and the environment variables are ignored
+// unless config.envVarProvider is set.
+func newTestClient(t *testing.T, config *ClientConfig, handler http.HandlerFunc) *Client {
+	t.Helper()
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	cc := ClientConfig{}
+	if config != nil {
+		cc = *config
+	}
+	if cc.HTTPOptions.BaseURL == "" {
+		cc.HTTPOptions.BaseURL = ts.URL
+	}
+	switch {
+	case cc.Credentials != nil || cc.TokenProvider != nil || cc.APIKey != "":
+	case cc.Backend == BackendVertexAI:
+		if cc.Project == "" {
+			cc.Project = "test-project"
+		}
+		if cc.Location == "" {
+			cc.Location = "us-central1"
+		}
+		// Without an HTTP client, the client of Vertex AI would look up
+		// the default credentials.
+		if cc.HTTPClient == nil {
+			cc.HTTPClient = ts.Client()
+		}
+	default:
+		cc.APIKey = "test-api-key"
+	}
+	if cc.envVarProvider == nil {
+		cc.envVarProvider = func() map[string]string { return map[string]string{} }
+	}
+	client, err := NewClient(context.Background(), &cc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
 // TestNewClient only runs in replay mode.
 func TestNewClient(t *testing.T) {
 
@@ -438,6 +485,38 @@ func TestNewClient(t *testing.T) {
 			}
 		})
 
+		t.Run("Base URL from PSC endpoint", func(t *testing.T) {
+			for endpoint, want := range map[string]string{
+				"us-central1-aiplatform-myendpoint.p.googleapis.com": "https://us-central1-aiplatform-myendpoint.p.googleapis.com/",
+				"http://10.128.0.2": "http://10.128.0.2/",
+			} {
+				client, err := NewClient(ctx, &ClientConfig{Project: "test-project", Location: "test-location", Backend: BackendVertexAI,
+					PSCEndpoint: endpoint, CredentialsAudience: "https://aiplatform.googleapis.com/"})
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if client.clientConfig.HTTPOptions.BaseURL != want {
+					t.Errorf("Expected base URL %q, got %q", want, client.clientConfig.HTTPOptions.BaseURL)
+				}
+			}
+			client, err := NewClient(ctx, &ClientConfig{Project: "test-project", Location: "test-location", Backend: BackendVertexAI,
+				PSCEndpoint: "10.128.0.2", HTTPOptions: HTTPOptions{BaseURL: "https://test-base-url.com/"}})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if client.clientConfig.HTTPOptions.BaseURL != "https://test-base-url.com/" {
+				t.Errorf("Expected the base URL to take precedence over the PSC endpoint, got %q", client.clientConfig.HTTPOptions.BaseURL)
+			}
+		})
+
+		t.Run("Credentials and credentials audience are mutually exclusive", func(t *testing.T) {
+			_, err := NewClient(ctx, &ClientConfig{Project: "test-project", Location: "test-location", Backend: BackendVertexAI,
+				Credentials: &auth.Credentials{}, CredentialsAudience: "https://aiplatform.googleapis.com/"})
+			if err == nil {
+				t.Errorf("Expected error, got nil")
+			}
+		})
+
 		t.Run("Default location to global when only project is provided", func(t *testing.T) {
 			client, err := NewClient(ctx, &ClientConfig{Backend: BackendVertexAI, Project: "fake-project-id",
 				envVarProvider: func() map[string]string {
@@ -557,6 +636,31 @@ func TestNewClient(t *testing.T) {
 			}
 		})
 
+		t.Run("Invalid location", func(t *testing.T) {
+			for _, location := range []string{"US-CENTRAL1", "us central1", "us-central1.", "https://us-central1-aiplatform.googleapis.com"} {
+				_, err := NewClient(ctx, &ClientConfig{Backend: BackendVertexAI, Project: "fake-project-id", Location: location,
+					envVarProvider: func() map[string]string { return map[string]string{} },
+				})
+				if err == nil || !strings.Contains(err.Error(), "invalid Vertex AI location") {
+					t.Errorf("NewClient() with location %q: error = %v, want an invalid location error", location, err)
+				}
+			}
+		})
+
+		t.Run("Base URL from location", func(t *testing.T) {
+			for location, want := range map[string]string{"global": "https://aiplatform.googleapis.com/", "europe-west4": "https://europe-west4-aiplatform.googleapis.com/"} {
+				client, err := NewClient(ctx, &ClientConfig{Backend: BackendVertexAI, Project: "fake-project-id", Location: location,
+					envVarProvider: func() map[string]string { return map[string]string{} },
+				})
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if got := client.clientConfig.HTTPOptions.BaseURL; got != want {
+					t.Errorf("BaseURL for location %q = %q, want %q", location, got, want)
+				}
+			}
+		})
+
 		t.Run("No default location to global when env location is set", func(t *testing.T) {
 			client, err := NewClient(ctx, &ClientConfig{Backend: BackendVertexAI,
 				envVarProvider: func() map[string]string {
@@ -790,6 +894,13 @@ func TestNewClient(t *testing.T) {
 		})
 	})
 
+	t.Run("PSC endpoint with the Gemini API", func(t *testing.T) {
+		_, err := NewClient(ctx, &ClientConfig{APIKey: "test-api-key", Backend: BackendGeminiAPI, PSCEndpoint: "10.128.0.2"})
+		if err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
+
 	t.Run("Project conflicts with APIKey", func(t *testing.T) {
 		_, err := NewClient(ctx, &ClientConfig{Project: "test-project", APIKey: "test-api-key", envVarProvider: func() map[string]string { return map[string]string{} }})
 		if err == nil {
@@ -864,6 +975,122 @@ func TestNewClient(t *testing.T) {
 		}
 	})
 
+	t.Run("DefaultModel", func(t *testing.T) {
+		envVarProvider := func() map[string]string {
+			return map[string]string{"GOOGLE_GENAI_DEFAULT_MODEL": "env-model"}
+		}
+		client, err := NewClient(ctx, &ClientConfig{APIKey: "test-api-key", envVarProvider: envVarProvider})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if client.clientConfig.DefaultModel != "env-model" {
+			t.Errorf("Expected default model %q, got %q", "env-model", client.clientConfig.DefaultModel)
+		}
+
+		client, err = NewClient(ctx, &ClientConfig{APIKey: "test-api-key", DefaultModel: "config-model", envVarProvider: envVarProvider})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if client.clientConfig.DefaultModel != "config-model" {
+			t.Errorf("Expected default model %q, got %q", "config-model", client.clientConfig.DefaultModel)
+		}
+	})
+}
+
+func TestDefaultModel(t *testing.T) {
+	ctx := context.Background()
+	var gotPath, gotBody string
+	client := newTestClient(t, &ClientConfig{DefaultModel: "gemini-default"}, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		fmt.Fprint(w, `{}`)
+	})
+
+	if _, err := client.Models.GenerateContent(ctx, "", Text("hello"), nil); err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	if want := "/v1beta/models/gemini-default:generateContent"; gotPath != want {
+		t.Errorf("GenerateContent() path = %q, want %q", gotPath, want)
+	}
+
+	if _, err := client.Models.GenerateContent(ctx, "gemini-explicit", Text("hello"), nil); err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	if want := "/v1beta/models/gemini-explicit:generateContent"; gotPath != want {
+		t.Errorf("GenerateContent() path = %q, want %q", gotPath, want)
+	}
+
+	chat, err := client.Chats.Create(ctx, "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chat.SendMessage(ctx, Part{Text: "hello"}); err != nil {
+		t.Fatalf("SendMessage() failed: %v", err)
+	}
+	if want := "/v1beta/models/gemini-default:generateContent"; gotPath != want {
+		t.Errorf("SendMessage() path = %q, want %q", gotPath, want)
+	}
+
+	interaction := &Interaction{Input: "hello"}
+	if _, err := client.Interactions.Create(ctx, interaction, nil); err != nil {
+		t.Fatalf("Interactions.Create() failed: %v", err)
+	}
+	if !strings.Contains(gotBody, `"model":"gemini-default"`) {
+		t.Errorf("Interactions.Create() body = %s, want model gemini-default", gotBody)
+	}
+	if interaction.Model != "" {
+		t.Errorf("Interactions.Create() modified the input interaction: Model = %q", interaction.Model)
+	}
+}
+
+func TestDefaultGenerationConfigs(t *testing.T) {
+	ctx := context.Background()
+	var gotBody string
+	client := newTestClient(t, &ClientConfig{
+		DefaultGenerateContentConfig: &GenerateContentConfig{
+			MaxOutputTokens: 100,
+			SafetySettings:  []*SafetySetting{{Category: HarmCategoryHarassment, Threshold: HarmBlockThresholdBlockOnlyHigh}},
+		},
+		DefaultInteractionGenerationConfig: &InteractionGenerationConfig{MaxOutputTokens: 100, Seed: Ptr[int32](7)},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		fmt.Fprint(w, `{}`)
+	})
+
+	config := &GenerateContentConfig{MaxOutputTokens: 10}
+	if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("hello"), config); err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	if !strings.Contains(gotBody, `"maxOutputTokens":10`) || !strings.Contains(gotBody, `"category":"HARM_CATEGORY_HARASSMENT"`) {
+		t.Errorf("GenerateContent() body = %s, want the call's maxOutputTokens and the default safety settings", gotBody)
+	}
+	if config.SafetySettings != nil {
+		t.Errorf("GenerateContent() modified the config: SafetySettings = %v", config.SafetySettings)
+	}
+
+	chat, err := client.Chats.Create(ctx, "gemini-2.5-flash", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chat.SendMessage(ctx, Part{Text: "hello"}); err != nil {
+		t.Fatalf("SendMessage() failed: %v", err)
+	}
+	if !strings.Contains(gotBody, `"maxOutputTokens":100`) {
+		t.Errorf("SendMessage() body = %s, want the default maxOutputTokens", gotBody)
+	}
+
+	interaction := &Interaction{Model: "gemini-2.5-flash", Input: "hello", GenerationConfig: &InteractionGenerationConfig{MaxOutputTokens: 10}}
+	if _, err := client.Interactions.Create(ctx, interaction, nil); err != nil {
+		t.Fatalf("Interactions.Create() failed: %v", err)
+	}
+	if !strings.Contains(gotBody, `"maxOutputTokens":10`) || !strings.Contains(gotBody, `"seed":7`) {
+		t.Errorf("Interactions.Create() body = %s, want the interaction's maxOutputTokens and the default seed", gotBody)
+	}
+	if interaction.GenerationConfig.Seed != nil {
+		t.Errorf("Interactions.Create() modified the input interaction: Seed = %v", *interaction.GenerationConfig.Seed)
+	}
 }
 
 func TestClientConfigHTTPOptions(t *testing.T) {
@@ -16,6 +16,7 @@ package genai
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -27,14 +28,41 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // Ptr returns a pointer to its argument.
 // It can be used to initialize pointer fields:
 //
-//	genai.GenerateContentConfig{Temperature: genai.Ptr(0.5)}
+//	genai.GenerateContentConfig{Temperature: genai.Float32(0.5)}
+//
+// Untyped constants default to int or float64, so use [Float32] or [Int32] for
+// the common optional numeric fields.
 func Ptr[T any](t T) *T { return &t }
 
+// Float32 returns a pointer to v. It is a shorthand for optional float32 fields
+// such as Temperature and TopP:
+//
+//	genai.GenerateContentConfig{Temperature: genai.Float32(0.5)}
+func Float32(v float32) *float32 { return &v }
+
+// Int32 returns a pointer to v. It is a shorthand for optional int32 fields
+// such as Seed and ThinkingBudget:
+//
+//	genai.GenerateContentConfig{Seed: genai.Int32(42)}
+func Int32(v int32) *int32 { return &v }
+
+// ValueOr returns *p, or def if p is nil. It can be used to read optional
+// fields:
+//
+//	temperature := genai.ValueOr(config.Temperature, 1.0)
+func ValueOr[T any](p *T, def T) T {
+	if p == nil {
+		return def
+	}
+	return *p
+}
+
 //nolint:unused
 type converterFuncWithClientWithRoot func(*apiClient, map[string]any, map[string]any, map[string]any) (map[string]any, error)
 
@@ -392,6 +420,34 @@ func createURLQuery(query map[string]any) (string, error) {
 	return v.Encode(), nil
 }
 
+// forEachConcurrently calls f for 0 to n-1 with up to workers calls at a time.
+// It stops starting calls when ctx is done, and returns when the started calls
+// have returned.
+func forEachConcurrently(ctx context.Context, n, workers int, f func(i int)) {
+	workers = min(max(workers, 1), n)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i)
+			}
+		}()
+	}
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+}
+
 func yieldErrorAndEndIterator[T any](err error) iter.Seq2[*T, error] {
 	return func(yield func(*T, error) bool) {
 		if !yield(nil, err) {
@@ -591,3 +591,16 @@ func TestMoveValueByPath(t *testing.T) {
 		})
 	}
 }
+
+func TestOptionalValueHelpers(t *testing.T) {
+	config := &GenerateContentConfig{Temperature: Float32(0.5), Seed: Int32(42)}
+	if got := ValueOr(config.Temperature, 1); got != 0.5 {
+		t.Errorf("ValueOr(Temperature) = %v, want 0.5", got)
+	}
+	if got := ValueOr(config.TopP, 0.95); got != 0.95 {
+		t.Errorf("ValueOr(TopP) = %v, want 0.95", got)
+	}
+	if got := ValueOr(config.Seed, 0); got != 42 {
+		t.Errorf("ValueOr(Seed) = %v, want 42", got)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Compression is the content encoding of request bodies.
+type Compression string
+
+const (
+	// CompressionNone sends request bodies uncompressed.
+	CompressionNone Compression = ""
+	// CompressionGzip compresses request bodies with gzip.
+	CompressionGzip Compression = "gzip"
+)
+
+// minCompressedBodySize is the size under which request bodies are sent
+// uncompressed, as compressing them would not save anything.
+const minCompressedBodySize = 1024
+
+// compressBody returns payload encoded with compression, and whether it was
+// compressed. Bodies smaller than minCompressedBodySize are not compressed.
+func compressBody(payload []byte, compression Compression) ([]byte, bool, error) {
+	switch compression {
+	case CompressionNone:
+		return payload, false, nil
+	case CompressionGzip:
+		if len(payload) < minCompressedBodySize {
+			return payload, false, nil
+		}
+		var b bytes.Buffer
+		w := gzip.NewWriter(&b)
+		if _, err := w.Write(payload); err != nil {
+			return nil, false, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, false, err
+		}
+		return b.Bytes(), true, nil
+	}
+	return nil, false, fmt.Errorf("unsupported compression %q, only %q is supported", compression, CompressionGzip)
+}
+
+// decompressResponse replaces the body of a gzip-encoded response with its
+// decompressed content. The transports of net/http already do so unless the
+// Accept-Encoding header of the request was set explicitly, e.g. through
+// HTTPOptions.Headers, or a custom transport is used.
+func decompressResponse(resp *http.Response) error {
+	if resp.Uncompressed || !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	r, err := gzip.NewReader(resp.Body)
+	if err == io.EOF {
+		// An empty body.
+		return nil
+	}
+	if err != nil {
+		resp.Body.Close()
+		return fmt.Errorf("decompressResponse: error reading gzip response: %w", err)
+	}
+	resp.Body = &gzipBody{Reader: r, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody is a decompressed response body.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	ctx := context.Background()
+	var gotEncoding string
+	var gotBody map[string]any
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+		var body io.Reader = r.Body
+		if gotEncoding == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("request body is not gzip: %v", err)
+				return
+			}
+			body = zr
+		}
+		gotBody = nil
+		if err := json.NewDecoder(body).Decode(&gotBody); err != nil {
+			t.Errorf("request body is not JSON: %v", err)
+		}
+		response := `{"candidates":[{"content":{"parts":[{"text":"compressed answer"}]}}]}`
+		if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			io.WriteString(zw, response)
+			zw.Close()
+			return
+		}
+		io.WriteString(w, response)
+	})
+
+	tests := []struct {
+		name           string
+		httpOptions    *HTTPOptions
+		requestOptions *RequestOptions
+		text           string
+		wantEncoding   string
+	}{
+		{name: "LargeBody", requestOptions: &RequestOptions{Compression: CompressionGzip}, text: strings.Repeat("long context ", 200), wantEncoding: "gzip"},
+		{name: "SmallBody", requestOptions: &RequestOptions{Compression: CompressionGzip}, text: "Hi", wantEncoding: ""},
+		{name: "Disabled", httpOptions: &HTTPOptions{}, text: strings.Repeat("long context ", 200), wantEncoding: ""},
+		// An explicit Accept-Encoding header disables the transparent
+		// decompression of net/http.
+		{name: "GzipResponse", httpOptions: &HTTPOptions{Headers: http.Header{"Accept-Encoding": []string{"gzip"}}}, text: "Hi", wantEncoding: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Models.GenerateContent(WithRequestOptions(ctx, tt.requestOptions), "gemini-2.5-flash", Text(tt.text), &GenerateContentConfig{HTTPOptions: tt.httpOptions})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gotEncoding != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", gotEncoding, tt.wantEncoding)
+			}
+			if gotBody["contents"] == nil {
+				t.Errorf("request body = %v, want the contents", gotBody)
+			}
+			if resp.Text() != "compressed answer" {
+				t.Errorf("Text() = %q, want the decompressed response", resp.Text())
+			}
+		})
+	}
+}
+
+func TestCompressBodyUnsupported(t *testing.T) {
+	if _, _, err := compressBody([]byte("{}"), "br"); err == nil {
+		t.Error("compressBody() with an unsupported compression succeeded, want error")
+	}
+}
+
+func TestCompressionUpload(t *testing.T) {
+	ctx := WithRequestOptions(context.Background(), &RequestOptions{Compression: CompressionGzip})
+	var content bytes.Buffer
+	s := newFailingUploadServer(t, &content, -1, 0)
+	var encodings []string
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Goog-Upload-Command") != "" {
+			encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		}
+		s.ServeHTTP(w, r)
+	})
+	s.baseURL = client.clientConfig.HTTPOptions.BaseURL
+
+	data := testUploadData()
+	config := &UploadFromReaderConfig{MIMEType: "video/mp4", ChunkSize: uploadChunkGranularity}
+	if _, err := client.Files.UploadFromReader(ctx, bytes.NewReader(data), int64(len(data)), config); err != nil {
+		t.Fatalf("UploadFromReader() failed: %v", err)
+	}
+	for i, encoding := range encodings {
+		if encoding != "" {
+			t.Errorf("Content-Encoding of upload request %d = %q, want the content uncompressed", i, encoding)
+		}
+	}
+	if !bytes.Equal(content.Bytes(), data) {
+		t.Errorf("the server received %d bytes, want the %d bytes of the content", content.Len(), len(data))
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"reflect"
+)
+
+// SafetySettings is a list of safety settings, as used by
+// [GenerateContentConfig.SafetySettings].
+type SafetySettings []*SafetySetting
+
+// Clone returns a deep copy of the SafetySetting.
+func (s *SafetySetting) Clone() *SafetySetting {
+	return cloneOf(s)
+}
+
+// Clone returns a deep copy of the SafetySettings.
+func (s SafetySettings) Clone() SafetySettings {
+	return *cloneOf(&s)
+}
+
+// Merge returns a new SafetySettings with overrides layered on top of s.
+//
+// Settings are matched by harm category: an override replaces the setting of
+// the same category, and settings for new categories are appended. Neither s
+// nor overrides is modified.
+func (s SafetySettings) Merge(overrides SafetySettings) SafetySettings {
+	if s == nil && overrides == nil {
+		return nil
+	}
+	merged := s.Clone()
+	for _, o := range overrides {
+		if o == nil {
+			continue
+		}
+		replaced := false
+		for i, m := range merged {
+			if m != nil && m.Category == o.Category {
+				merged[i] = o.Clone()
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, o.Clone())
+		}
+	}
+	return merged
+}
+
+// Clone returns a deep copy of the GenerateContentConfig. Cloning a nil config
+// returns nil.
+func (c *GenerateContentConfig) Clone() *GenerateContentConfig {
+	return cloneOf(c)
+}
+
+// Merge returns a new GenerateContentConfig with overrides layered on top of c,
+// so that global defaults, per-feature and per-call configs can be composed
+// without mutating shared values. Neither c nor overrides is modified.
+//
+// Fields set in overrides take precedence. Nested config structs (such as
+// ThinkingConfig or ToolConfig) are merged field by field, maps such as Labels
+// are merged key by key, and SafetySettings are merged by category (see
+// [SafetySettings.Merge]). Other slices, SystemInstruction, ResponseSchema and
+// ResponseJsonSchema are replaced as a whole. Zero values in overrides, such as
+// false or an empty string, cannot unset a value from c.
+func (c *GenerateContentConfig) Merge(overrides *GenerateContentConfig) *GenerateContentConfig {
+	merged := mergeOf(c, overrides)
+	if merged != nil && c != nil && overrides != nil {
+		merged.SafetySettings = SafetySettings(c.SafetySettings).Merge(overrides.SafetySettings)
+	}
+	return merged
+}
+
+// Clone returns a deep copy of the InteractionGenerationConfig. Cloning a nil
+// config returns nil.
+func (c *InteractionGenerationConfig) Clone() *InteractionGenerationConfig {
+	return cloneOf(c)
+}
+
+// Merge returns a new InteractionGenerationConfig with overrides layered on top
+// of c. Neither c nor overrides is modified.
+//
+// Fields set in overrides take precedence, nested config structs are merged
+// field by field, and slices and ToolChoice are replaced as a whole. Zero values
+// in overrides cannot unset a value from c.
+func (c *InteractionGenerationConfig) Merge(overrides *InteractionGenerationConfig) *InteractionGenerationConfig {
+	return mergeOf(c, overrides)
+}
+
+// atomicMergeTypes are struct types that are replaced as a whole rather than
+// merged field by field, because a partial merge would change their meaning.
+var atomicMergeTypes = map[reflect.Type]bool{
+	reflect.TypeOf(Content{}): true,
+	reflect.TypeOf(Schema{}):  true,
+}
+
+// cloneOf returns a deep copy of *v, or nil if v is nil.
+func cloneOf[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	return deepCopyValue(reflect.ValueOf(v)).Interface().(*T)
+}
+
+// mergeOf returns a deep copy of base with the set fields of overrides merged
+// in. It is the struct counterpart of recursiveMapMerge.
+func mergeOf[T any](base, overrides *T) *T {
+	if overrides == nil {
+		return cloneOf(base)
+	}
+	if base == nil {
+		return cloneOf(overrides)
+	}
+	merged := cloneOf(base)
+	mergeValue(reflect.ValueOf(merged).Elem(), reflect.ValueOf(overrides).Elem())
+	return merged
+}
+
+// deepCopyValue returns a deep copy of v. Functions and channels are copied by
+// reference.
+func deepCopyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(deepCopyValue(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopyValue(v.Elem()))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), deepCopyValue(iter.Value()))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		// Copy unexported fields, e.g. of time.Time, as is.
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				c.Field(i).Set(deepCopyValue(v.Field(i)))
+			}
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// mergeValue merges the non-zero values of src into dst, which must be
+// settable and of the same type.
+func mergeValue(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Struct:
+		if atomicMergeTypes[src.Type()] {
+			dst.Set(deepCopyValue(src))
+			return
+		}
+		for i := 0; i < src.NumField(); i++ {
+			if src.Type().Field(i).IsExported() {
+				mergeValue(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() || src.Elem().Kind() != reflect.Struct {
+			dst.Set(deepCopyValue(src))
+			return
+		}
+		mergeValue(dst.Elem(), src.Elem())
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(dst.Type(), src.Len()))
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), deepCopyValue(iter.Value()))
+		}
+	default:
+		if !src.IsZero() {
+			dst.Set(deepCopyValue(src))
+		}
+	}
+}
+
+// defaultConfigHook merges ClientConfig.DefaultGenerateContentConfig beneath
+// the config of the generate content calls. The default config is converted
+// to a request body like the config of a call, and merged with the body as
+// GenerateContentConfig.Merge merges the configs, see mergeBodyBeneath.
+func (ac *apiClient) defaultConfigHook(ctx context.Context, call *hookedCall) error {
+	defaults := ac.clientConfig.DefaultGenerateContentConfig
+	if defaults == nil || call.body == nil || !call.isMethod("generateContent", "streamGenerateContent") {
+		return nil
+	}
+	defaults = defaults.Clone()
+	defaults.setDefaults()
+	parameterMap := make(map[string]any)
+	if err := deepMarshal(map[string]any{"config": defaults}, &parameterMap); err != nil {
+		return err
+	}
+	toConverter := generateContentParametersToMldev
+	if ac.clientConfig.Backend == BackendVertexAI {
+		toConverter = generateContentParametersToVertex
+	}
+	defaultBody, err := toConverter(ac, parameterMap, nil, parameterMap)
+	if err != nil {
+		return err
+	}
+	mergeBodyBeneath(call.body, defaultBody)
+	return nil
+}
+
+// atomicBodyKeys are the keys of a generate content body that are replaced
+// as a whole, like the atomicMergeTypes of the config.
+var atomicBodyKeys = map[string]bool{
+	"systemInstruction":  true,
+	"responseSchema":     true,
+	"responseJsonSchema": true,
+}
+
+// mergeBodyBeneath merges the values of defaults that body does not set into
+// body. Objects are merged key by key and safety settings by category, other
+// values, such as lists, are kept from body as a whole.
+func mergeBodyBeneath(body, defaults map[string]any) {
+	for key, value := range defaults {
+		bodyValue, ok := body[key]
+		switch {
+		case !ok || bodyValue == nil:
+			body[key] = value
+		case atomicBodyKeys[key]:
+		case key == "safetySettings":
+			body[key] = mergeSafetySettingsBeneath(objectsOf(bodyValue), objectsOf(value))
+		default:
+			bodyMap, isBodyMap := bodyValue.(map[string]any)
+			defaultMap, isDefaultMap := value.(map[string]any)
+			if isBodyMap && isDefaultMap {
+				mergeBodyBeneath(bodyMap, defaultMap)
+			}
+		}
+	}
+}
+
+// mergeSafetySettingsBeneath returns the safety settings of a request body
+// merged on top of defaults by category, see [SafetySettings.Merge].
+func mergeSafetySettingsBeneath(settings, defaults []map[string]any) []any {
+	merged := make([]any, 0, len(defaults)+len(settings))
+	for _, d := range defaults {
+		merged = append(merged, d)
+	}
+	for _, s := range settings {
+		replaced := false
+		for i, m := range merged {
+			if m.(map[string]any)["category"] == s["category"] {
+				merged[i] = s
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
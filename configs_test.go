@@ -0,0 +1,205 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestGenerateContentConfigClone(t *testing.T) {
+	if got := (*GenerateContentConfig)(nil).Clone(); got != nil {
+		t.Errorf("Clone() of nil = %v, want nil", got)
+	}
+
+	original := &GenerateContentConfig{
+		HTTPOptions:       &HTTPOptions{Headers: http.Header{"X-Test": []string{"a"}}, Timeout: Ptr(time.Second)},
+		SystemInstruction: &Content{Role: RoleUser, Parts: []*Part{{Text: "Be brief."}}},
+		Temperature:       Float32(0.5),
+		StopSequences:     []string{"STOP"},
+		SafetySettings:    []*SafetySetting{{Category: HarmCategoryHarassment, Threshold: HarmBlockThresholdBlockNone}},
+		Labels:            map[string]string{"team": "a"},
+		ThinkingConfig:    &ThinkingConfig{IncludeThoughts: true},
+	}
+	clone := original.Clone()
+	if diff := cmp.Diff(original, clone, cmpopts.IgnoreFields(HTTPOptions{}, "ExtrasRequestProvider")); diff != "" {
+		t.Fatalf("Clone() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Mutating the clone must not affect the original.
+	*clone.Temperature = 1
+	clone.SystemInstruction.Parts[0].Text = "changed"
+	clone.StopSequences[0] = "changed"
+	clone.SafetySettings[0].Threshold = HarmBlockThresholdBlockLowAndAbove
+	clone.Labels["team"] = "b"
+	clone.ThinkingConfig.IncludeThoughts = false
+	clone.HTTPOptions.Headers.Set("X-Test", "b")
+
+	if *original.Temperature != 0.5 ||
+		original.SystemInstruction.Parts[0].Text != "Be brief." ||
+		original.StopSequences[0] != "STOP" ||
+		original.SafetySettings[0].Threshold != HarmBlockThresholdBlockNone ||
+		original.Labels["team"] != "a" ||
+		!original.ThinkingConfig.IncludeThoughts ||
+		original.HTTPOptions.Headers.Get("X-Test") != "a" {
+		t.Errorf("mutating the clone changed the original: %+v", original)
+	}
+}
+
+func TestGenerateContentConfigMerge(t *testing.T) {
+	defaults := &GenerateContentConfig{
+		SystemInstruction: &Content{Role: RoleUser, Parts: []*Part{{Text: "default"}, {Text: "instruction"}}},
+		Temperature:       Float32(0.2),
+		MaxOutputTokens:   1024,
+		StopSequences:     []string{"A", "B"},
+		SafetySettings: []*SafetySetting{
+			{Category: HarmCategoryHarassment, Threshold: HarmBlockThresholdBlockNone},
+			{Category: HarmCategoryHateSpeech, Threshold: HarmBlockThresholdBlockNone},
+		},
+		Labels:         map[string]string{"team": "search", "env": "prod"},
+		ThinkingConfig: &ThinkingConfig{IncludeThoughts: true},
+		ResponseSchema: &Schema{Type: TypeObject, Properties: map[string]*Schema{"a": {Type: TypeString}}},
+	}
+	overrides := &GenerateContentConfig{
+		SystemInstruction: &Content{Parts: []*Part{{Text: "override"}}},
+		Temperature:       Float32(0.9),
+		StopSequences:     []string{"C"},
+		SafetySettings: []*SafetySetting{
+			{Category: HarmCategoryHateSpeech, Threshold: HarmBlockThresholdBlockLowAndAbove},
+			{Category: HarmCategoryDangerousContent, Threshold: HarmBlockThresholdBlockOnlyHigh},
+		},
+		Labels:         map[string]string{"team": "ads"},
+		ThinkingConfig: &ThinkingConfig{ThinkingBudget: Int32(128)},
+		ResponseSchema: &Schema{Type: TypeObject, Properties: map[string]*Schema{"b": {Type: TypeInteger}}},
+	}
+	defaultsBefore := defaults.Clone()
+	overridesBefore := overrides.Clone()
+
+	got := defaults.Merge(overrides)
+	want := &GenerateContentConfig{
+		SystemInstruction: &Content{Parts: []*Part{{Text: "override"}}},
+		Temperature:       Float32(0.9),
+		MaxOutputTokens:   1024,
+		StopSequences:     []string{"C"},
+		SafetySettings: []*SafetySetting{
+			{Category: HarmCategoryHarassment, Threshold: HarmBlockThresholdBlockNone},
+			{Category: HarmCategoryHateSpeech, Threshold: HarmBlockThresholdBlockLowAndAbove},
+			{Category: HarmCategoryDangerousContent, Threshold: HarmBlockThresholdBlockOnlyHigh},
+		},
+		Labels:         map[string]string{"team": "ads", "env": "prod"},
+		ThinkingConfig: &ThinkingConfig{IncludeThoughts: true, ThinkingBudget: Int32(128)},
+		ResponseSchema: &Schema{Type: TypeObject, Properties: map[string]*Schema{"b": {Type: TypeInteger}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Merge() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(defaultsBefore, defaults); diff != "" {
+		t.Errorf("Merge() modified the receiver (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(overridesBefore, overrides); diff != "" {
+		t.Errorf("Merge() modified the overrides (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(defaults, defaults.Merge(nil)); diff != "" {
+		t.Errorf("Merge(nil) mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(overrides, (*GenerateContentConfig)(nil).Merge(overrides)); diff != "" {
+		t.Errorf("nil.Merge() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestInteractionGenerationConfigMerge(t *testing.T) {
+	defaults := &InteractionGenerationConfig{
+		Temperature:     Float32(0.2),
+		MaxOutputTokens: 512,
+		ImageConfig:     &InteractionImageConfig{AspectRatio: "16:9"},
+	}
+	overrides := &InteractionGenerationConfig{
+		Seed:        Int32(7),
+		ImageConfig: &InteractionImageConfig{ImageSize: "1K"},
+		ToolChoice:  "auto",
+	}
+	want := &InteractionGenerationConfig{
+		Temperature:     Float32(0.2),
+		Seed:            Int32(7),
+		MaxOutputTokens: 512,
+		ImageConfig:     &InteractionImageConfig{AspectRatio: "16:9", ImageSize: "1K"},
+		ToolChoice:      "auto",
+	}
+	if diff := cmp.Diff(want, defaults.Merge(overrides)); diff != "" {
+		t.Errorf("Merge() mismatch (-want +got):\n%s", diff)
+	}
+	if defaults.ImageConfig.ImageSize != "" {
+		t.Errorf("Merge() modified the receiver: %+v", defaults.ImageConfig)
+	}
+
+	clone := defaults.Clone()
+	*clone.Temperature = 1
+	if *defaults.Temperature != 0.2 {
+		t.Errorf("mutating the clone changed the original")
+	}
+}
+
+func TestSafetySettingsMerge(t *testing.T) {
+	base := SafetySettings{{Category: HarmCategoryHarassment, Threshold: HarmBlockThresholdBlockNone}}
+	got := base.Merge(SafetySettings{{Category: HarmCategoryHarassment, Threshold: HarmBlockThresholdOff}})
+	want := SafetySettings{{Category: HarmCategoryHarassment, Threshold: HarmBlockThresholdOff}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Merge() mismatch (-want +got):\n%s", diff)
+	}
+	if base[0].Threshold != HarmBlockThresholdBlockNone {
+		t.Errorf("Merge() modified the receiver")
+	}
+	if got := SafetySettings(nil).Merge(nil); got != nil {
+		t.Errorf("Merge() of nil settings = %v, want nil", got)
+	}
+}
+
+func TestMergeBodyBeneath(t *testing.T) {
+	body := map[string]any{
+		"generationConfig":  map[string]any{"maxOutputTokens": 10, "responseSchema": map[string]any{"type": "STRING"}},
+		"safetySettings":    []map[string]any{{"category": "HARM_CATEGORY_HARASSMENT", "threshold": "OFF"}},
+		"stopSequences":     []any{"end"},
+		"systemInstruction": map[string]any{"parts": []any{map[string]any{"text": "call"}}},
+	}
+	defaults := map[string]any{
+		"generationConfig": map[string]any{"maxOutputTokens": 100, "temperature": 0.5, "responseSchema": map[string]any{"type": "OBJECT", "nullable": true}},
+		"safetySettings": []map[string]any{
+			{"category": "HARM_CATEGORY_HARASSMENT", "threshold": "BLOCK_NONE"},
+			{"category": "HARM_CATEGORY_HATE_SPEECH", "threshold": "BLOCK_NONE"},
+		},
+		"stopSequences":     []any{"stop"},
+		"systemInstruction": map[string]any{"role": "user", "parts": []any{map[string]any{"text": "default"}}},
+		"tools":             []any{map[string]any{"googleSearch": map[string]any{}}},
+	}
+	mergeBodyBeneath(body, defaults)
+	want := map[string]any{
+		"generationConfig": map[string]any{"maxOutputTokens": 10, "temperature": 0.5, "responseSchema": map[string]any{"type": "STRING"}},
+		"safetySettings": []any{
+			map[string]any{"category": "HARM_CATEGORY_HARASSMENT", "threshold": "OFF"},
+			map[string]any{"category": "HARM_CATEGORY_HATE_SPEECH", "threshold": "BLOCK_NONE"},
+		},
+		"stopSequences":     []any{"end"},
+		"systemInstruction": map[string]any{"parts": []any{map[string]any{"text": "call"}}},
+		"tools":             []any{map[string]any{"googleSearch": map[string]any{}}},
+	}
+	if diff := cmp.Diff(want, body); diff != "" {
+		t.Errorf("mergeBodyBeneath() mismatch (-want +got):\n%s", diff)
+	}
+}
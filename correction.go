@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// defaultCorrectionAttempts is the number of attempts used when
+// CorrectionConfig.MaxAttempts is zero.
+const defaultCorrectionAttempts = 3
+
+// CorrectionConfig configures GenerateContentWithCorrection.
+type CorrectionConfig struct {
+	// Optional. The maximum number of model calls, including the first one.
+	// Defaults to 3.
+	MaxAttempts int
+	// Optional. Checks a response. A returned error is sent back to the model
+	// as feedback and the request is retried. If nil and the request config
+	// has a ResponseSchema, the response text must be JSON matching the schema.
+	Validate func(resp *GenerateContentResponse) error
+	// Optional. Returns the feedback message for a failed attempt. Defaults to
+	// a message that quotes the error and asks for a corrected response.
+	Feedback func(err error) string
+}
+
+// CorrectionAttempt is one model call of GenerateContentWithCorrection.
+type CorrectionAttempt struct {
+	// Response is the response of the model, or nil if an output guardrail
+	// blocked it.
+	Response *GenerateContentResponse
+	// Err is why the attempt was rejected, or nil for the accepted attempt.
+	Err error
+}
+
+// CorrectionResult is the result of GenerateContentWithCorrection.
+type CorrectionResult struct {
+	// Response is the accepted response, or nil if every attempt failed.
+	Response *GenerateContentResponse
+	// Attempts are all attempts, in order.
+	Attempts []*CorrectionAttempt
+}
+
+// GenerateContentWithCorrection calls GenerateContent and validates the
+// response. If the validation fails, or an output guardrail blocks the
+// response, the model is asked again with the rejected response and the error
+// as feedback, up to correction.MaxAttempts calls in total.
+//
+// It returns the accepted response together with the history of attempts. If
+// no attempt is accepted, it returns the history and an error wrapping the
+// last validation error. Errors of the API calls themselves are returned
+// immediately.
+func (m Models) GenerateContentWithCorrection(ctx context.Context, model string, contents []*Content, config *GenerateContentConfig, correction *CorrectionConfig) (*CorrectionResult, error) {
+	if correction == nil {
+		correction = &CorrectionConfig{}
+	}
+	maxAttempts := correction.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultCorrectionAttempts
+	}
+	validate := correction.Validate
+	if validate == nil {
+		validate = schemaValidator(config)
+	}
+	feedback := correction.Feedback
+	if feedback == nil {
+		feedback = defaultCorrectionFeedback
+	}
+
+	result := &CorrectionResult{}
+	contents = slices.Clone(contents)
+	var lastErr error
+	for range maxAttempts {
+		resp, err := m.GenerateContent(ctx, model, contents, config)
+		var ge *GuardrailError
+		switch {
+		case errors.As(err, &ge) && ge.Stage == GuardrailStageOutput:
+			lastErr = err
+		case err != nil:
+			return result, err
+		default:
+			lastErr = validate(resp)
+		}
+		result.Attempts = append(result.Attempts, &CorrectionAttempt{Response: resp, Err: lastErr})
+		if lastErr == nil {
+			result.Response = resp
+			return result, nil
+		}
+		if resp != nil && len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
+			contents = append(contents, resp.Candidates[0].Content)
+		}
+		contents = append(contents, NewContentFromText(feedback(lastErr), RoleUser))
+	}
+	return result, fmt.Errorf("no valid response after %d attempts: %w", len(result.Attempts), lastErr)
+}
+
+func defaultCorrectionFeedback(err error) string {
+	return fmt.Sprintf("Your previous response was rejected: %v\nPlease answer again and fix this problem.", err)
+}
+
+// schemaValidator returns a validator that checks the response text against
+// the response schema of config, if any.
+func schemaValidator(config *GenerateContentConfig) func(resp *GenerateContentResponse) error {
+	return func(resp *GenerateContentResponse) error {
+		if config == nil || config.ResponseSchema == nil {
+			return nil
+		}
+		var v any
+		if err := json.Unmarshal([]byte(resp.Text()), &v); err != nil {
+			return fmt.Errorf("the response is not valid JSON: %w", err)
+		}
+		return checkSchema(v, config.ResponseSchema, "$")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateContentWithCorrection(t *testing.T) {
+	ctx := context.Background()
+	replies := []string{`{"age": "ten"}`, `The SECRET age`, `{"age": 10}`}
+	client, prompts := guardrailServer(t, &Guardrails{Output: []Guardrail{NewDenyListGuardrail(GuardrailBlock, "secret")}}, func(prompt string) string {
+		reply := replies[0]
+		replies = replies[1:]
+		return reply
+	})
+	config := &GenerateContentConfig{
+		ResponseMIMEType: "application/json",
+		ResponseSchema:   &Schema{Type: TypeObject, Properties: map[string]*Schema{"age": {Type: TypeInteger}}},
+	}
+
+	result, err := client.Models.GenerateContentWithCorrection(ctx, "gemini-2.5-flash", Text("How old is Tom?"), config, nil)
+	if err != nil {
+		t.Fatalf("GenerateContentWithCorrection() failed: %v", err)
+	}
+	if result.Response.Text() != `{"age": 10}` || len(result.Attempts) != 3 {
+		t.Fatalf("got %q after %d attempts, want the third reply", result.Response.Text(), len(result.Attempts))
+	}
+	if err := result.Attempts[0].Err; err == nil || !strings.Contains(err.Error(), "must be an integer") {
+		t.Errorf("first attempt error = %v, want a schema error", err)
+	}
+	var ge *GuardrailError
+	if err := result.Attempts[1].Err; !errors.As(err, &ge) || result.Attempts[1].Response != nil {
+		t.Errorf("second attempt = %+v, want a blocked response", result.Attempts[1])
+	}
+	// Each retry ends with the feedback for the previous attempt.
+	if !strings.Contains((*prompts)[1], "must be an integer") || !strings.Contains((*prompts)[2], "blocked by guardrail") {
+		t.Errorf("feedback prompts = %q, want the errors of the previous attempts", (*prompts)[1:])
+	}
+
+	replies = []string{"a", "b"}
+	result, err = client.Models.GenerateContentWithCorrection(ctx, "gemini-2.5-flash", Text("Say c"), nil, &CorrectionConfig{
+		MaxAttempts: 2,
+		Validate: func(resp *GenerateContentResponse) error {
+			if resp.Text() != "c" {
+				return errors.New("not c")
+			}
+			return nil
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts: not c") || len(result.Attempts) != 2 || result.Response != nil {
+		t.Errorf("GenerateContentWithCorrection() = %+v, %v, want two failed attempts", result, err)
+	}
+}
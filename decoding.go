@@ -0,0 +1,215 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// StrictDecodingMode controls how fields in API responses that have no
+// corresponding field in the SDK types are surfaced.
+type StrictDecodingMode int
+
+const (
+	// StrictDecodingOff silently ignores unknown response fields. This is the default.
+	StrictDecodingOff StrictDecodingMode = iota
+	// StrictDecodingWarn reports unknown response fields to
+	// [StrictDecodingConfig.OnUnknownFields], or logs them if no callback is set.
+	// The response is still returned.
+	StrictDecodingWarn
+	// StrictDecodingError fails the call with an [*UnknownFieldsError] when the
+	// response contains unknown fields.
+	StrictDecodingError
+)
+
+// StrictDecodingConfig configures how the client handles response fields that
+// the SDK does not model yet.
+//
+// Unknown fields are detected when the backend response is decoded into the
+// SDK types, so users discover new server fields and SDK gaps instead of losing
+// data silently. Responses that the SDK converts between the backend and SDK
+// representations, e.g. those of Models, only keep the top-level fields that
+// the SDK knows, so for them only unknown fields nested in known ones are
+// reported.
+type StrictDecodingConfig struct {
+	// Optional. How unknown fields are surfaced. Defaults to StrictDecodingOff.
+	Mode StrictDecodingMode
+	// Optional. Called with the decoded type name and the JSON paths of the
+	// unknown fields, e.g. "candidates[0].content.newField". Only used with
+	// StrictDecodingWarn.
+	OnUnknownFields func(typeName string, fields []string)
+}
+
+// UnknownFieldsError is returned in StrictDecodingError mode when an API
+// response contains fields that the SDK types do not model.
+type UnknownFieldsError struct {
+	// TypeName is the name of the SDK type the response was decoded into.
+	TypeName string
+	// Fields are the JSON paths of the unknown fields, sorted.
+	Fields []string
+}
+
+// Error returns a string representation of the UnknownFieldsError.
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("response for %s contains fields unknown to the SDK: %s", e.TypeName, strings.Join(e.Fields, ", "))
+}
+
+// strictDecodingKey is the key of the sdkHttpResponse map of a response under
+// which the client's StrictDecodingConfig is passed to mapToStruct. The
+// converters copy sdkHttpResponse unchanged, so the setting reaches the
+// decoding of the response into its SDK type.
+const strictDecodingKey = "strictDecoding"
+
+// strictDecoding holds the StrictDecodingConfig of a response. It has no
+// exported fields, so a response map that carries it still encodes to JSON.
+type strictDecoding struct {
+	config *StrictDecodingConfig
+}
+
+// markStrictDecoding records sd in the response map, unless unknown fields are
+// ignored.
+func markStrictDecoding(response map[string]any, sd *StrictDecodingConfig) {
+	if response == nil || sd == nil || sd.Mode == StrictDecodingOff {
+		return
+	}
+	httpResponse, ok := response["sdkHttpResponse"].(map[string]any)
+	if !ok {
+		httpResponse = map[string]any{}
+		response["sdkHttpResponse"] = httpResponse
+	}
+	httpResponse[strictDecodingKey] = strictDecoding{config: sd}
+}
+
+// takeStrictDecoding removes the StrictDecodingConfig recorded by
+// markStrictDecoding from input and returns it, or nil if there is none.
+func takeStrictDecoding(input map[string]any) *StrictDecodingConfig {
+	httpResponse, ok := input["sdkHttpResponse"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	sd, ok := httpResponse[strictDecodingKey].(strictDecoding)
+	if !ok {
+		return nil
+	}
+	delete(httpResponse, strictDecodingKey)
+	return sd.config
+}
+
+// checkUnknownFields surfaces the fields of input that have no corresponding
+// field in t according to sd.
+func checkUnknownFields(sd *StrictDecodingConfig, input map[string]any, t reflect.Type) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fields := unknownFields(input, t, "", true)
+	if len(fields) == 0 {
+		return nil
+	}
+	sort.Strings(fields)
+	switch sd.Mode {
+	case StrictDecodingError:
+		return &UnknownFieldsError{TypeName: t.Name(), Fields: fields}
+	case StrictDecodingWarn:
+		if sd.OnUnknownFields != nil {
+			sd.OnUnknownFields(t.Name(), fields)
+		} else {
+			log.Printf("Warning: response for %s contains fields unknown to the SDK: %s", t.Name(), strings.Join(fields, ", "))
+		}
+	}
+	return nil
+}
+
+var sdkPackagePath = reflect.TypeOf(Client{}).PkgPath()
+
+// unknownFields returns the paths of the keys in value that have no
+// corresponding JSON field in t.
+func unknownFields(value any, t reflect.Type, path string, root bool) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch v := value.(type) {
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return nil
+		}
+		var fields []string
+		for i, item := range v {
+			fields = append(fields, unknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), false)...)
+		}
+		return fields
+	case map[string]any:
+		// Only SDK structs are checked; maps, any and third-party types accept
+		// arbitrary keys.
+		if t.Kind() != reflect.Struct || t.PkgPath() != sdkPackagePath {
+			return nil
+		}
+		known := jsonFields(t)
+		var fields []string
+		for key, item := range v {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			ft, ok := known[key]
+			if !ok {
+				// sdkHttpResponse is added by the SDK to every response.
+				if !(root && key == "sdkHttpResponse") {
+					fields = append(fields, fieldPath)
+				}
+				continue
+			}
+			fields = append(fields, unknownFields(item, ft, fieldPath, false)...)
+		}
+		return fields
+	}
+	return nil
+}
+
+// jsonFields returns the JSON field names of a struct type and their types.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range jsonFields(ft) {
+					fields[k] = v
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnknownFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		input map[string]any
+		want  []string
+	}{
+		{
+			name: "all fields known",
+			input: map[string]any{
+				"sdkHttpResponse": map[string]any{"headers": map[string]any{}},
+				"candidates":      []any{map[string]any{"content": map[string]any{"parts": []any{map[string]any{"text": "hi"}}}}},
+				"modelVersion":    "v1",
+			},
+		},
+		{
+			name: "unknown top level and nested fields",
+			input: map[string]any{
+				"brandNewField": true,
+				"candidates": []any{
+					map[string]any{"content": map[string]any{"parts": []any{
+						map[string]any{"text": "hi"},
+						map[string]any{"text": "there", "newPartField": 1},
+					}}},
+				},
+			},
+			want: []string{"brandNewField", "candidates[0].content.parts[1].newPartField"},
+		},
+		{
+			name: "free-form maps accept any key",
+			input: map[string]any{
+				"candidates": []any{map[string]any{"content": map[string]any{"parts": []any{
+					map[string]any{"functionCall": map[string]any{"name": "f", "args": map[string]any{"anything": 1}}},
+				}}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markStrictDecoding(tt.input, &StrictDecodingConfig{Mode: StrictDecodingError})
+			err := mapToStruct(tt.input, new(GenerateContentResponse))
+			var got []string
+			var unknownErr *UnknownFieldsError
+			if errors.As(err, &unknownErr) {
+				got = unknownErr.Fields
+			} else if err != nil {
+				t.Fatalf("mapToStruct() failed: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("unknown fields mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStrictDecodingModes(t *testing.T) {
+	ctx := context.Background()
+	newClient := func(sd *StrictDecodingConfig) *Client {
+		return newTestClient(t, &ClientConfig{StrictDecoding: sd}, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"id": "test-id", "status": "completed", "futureField": {"a": 1}}`)
+		})
+	}
+
+	t.Run("Off", func(t *testing.T) {
+		resp, err := newClient(nil).Interactions.Get(ctx, "test-id", nil)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if resp.ID != "test-id" {
+			t.Errorf("ID = %q, want test-id", resp.ID)
+		}
+	})
+
+	t.Run("Warn", func(t *testing.T) {
+		var gotType string
+		var gotFields []string
+		client := newClient(&StrictDecodingConfig{
+			Mode: StrictDecodingWarn,
+			OnUnknownFields: func(typeName string, fields []string) {
+				gotType = typeName
+				gotFields = fields
+			},
+		})
+		resp, err := client.Interactions.Get(ctx, "test-id", nil)
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if resp.ID != "test-id" {
+			t.Errorf("ID = %q, want test-id", resp.ID)
+		}
+		if gotType != "Interaction" {
+			t.Errorf("OnUnknownFields typeName = %q, want Interaction", gotType)
+		}
+		if diff := cmp.Diff([]string{"futureField"}, gotFields); diff != "" {
+			t.Errorf("OnUnknownFields fields mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := newClient(&StrictDecodingConfig{Mode: StrictDecodingError}).Interactions.Get(ctx, "test-id", nil)
+		var unknownErr *UnknownFieldsError
+		if !errors.As(err, &unknownErr) {
+			t.Fatalf("Get() error = %v, want *UnknownFieldsError", err)
+		}
+		want := "response for Interaction contains fields unknown to the SDK: futureField"
+		if unknownErr.Error() != want {
+			t.Errorf("Error() = %q, want %q", unknownErr.Error(), want)
+		}
+	})
+}
+
+func TestStrictDecodingGenerateContent(t *testing.T) {
+	ctx := context.Background()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		// The converters of the generated methods only copy known top-level
+		// fields, so the unknown field is nested in one they copy as is.
+		body := `{"candidates": [{"content": {"parts": [{"text": "hi"}]}}], "usageMetadata": {"futureCount": 1}}`
+		if r.URL.Query().Get("alt") == "sse" {
+			fmt.Fprintf(w, "data: %s\n\n", body)
+			return
+		}
+		fmt.Fprint(w, body)
+	}
+	for _, backend := range []Backend{BackendGeminiAPI, BackendVertexAI} {
+		client := newTestClient(t, &ClientConfig{
+			APIKey:         "test-api-key",
+			Backend:        backend,
+			StrictDecoding: &StrictDecodingConfig{Mode: StrictDecodingError},
+		}, handler)
+		var unknownErr *UnknownFieldsError
+		if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Hi"), nil); !errors.As(err, &unknownErr) {
+			t.Errorf("%s: GenerateContent() error = %v, want *UnknownFieldsError", backend, err)
+		}
+		for _, err := range client.Models.GenerateContentStream(ctx, "gemini-2.5-flash", Text("Hi"), nil) {
+			if !errors.As(err, &unknownErr) || unknownErr.TypeName != "GenerateContentResponse" || unknownErr.Fields[0] != "usageMetadata.futureCount" {
+				t.Errorf("%s: GenerateContentStream() error = %v, want *UnknownFieldsError", backend, err)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package embeddings provides vector math for the embeddings returned by
+// Models.EmbedContent, such as their similarity and the nearest embeddings of
+// a query.
+package embeddings
+
+import (
+	"math"
+	"sort"
+)
+
+// DotProduct returns the dot product of a and b, or 0 if their lengths
+// differ.
+func DotProduct(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+// CosineSimilarity returns the cosine similarity of a and b, or 0 if their
+// lengths differ or either is zero. For normalized embeddings, it is their
+// dot product.
+func CosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	na, nb := norm(a), norm(b)
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return DotProduct(a, b) / (na * nb)
+}
+
+// Normalize returns a copy of v scaled to unit length, or a copy of v if it is
+// zero.
+func Normalize(v []float32) []float32 {
+	n := norm(v)
+	result := make([]float32, len(v))
+	for i, x := range v {
+		if n == 0 {
+			result[i] = x
+		} else {
+			result[i] = float32(float64(x) / n)
+		}
+	}
+	return result
+}
+
+// MatryoshkaTruncate returns the first dims values of embedding, normalized.
+//
+// Embedding models trained with Matryoshka representation learning, such as
+// gemini-embedding-001, put the most information in the first values, so a
+// prefix of an embedding is an embedding of reduced dimensionality. Only the
+// full embedding is normalized by the model; the prefix must be normalized
+// again to compare it with dot products. If dims is not less than the length
+// of embedding, the whole embedding is normalized.
+func MatryoshkaTruncate(embedding []float32, dims int) []float32 {
+	if dims < 0 {
+		dims = 0
+	}
+	return Normalize(embedding[:min(dims, len(embedding))])
+}
+
+// Match is an embedding of a corpus returned by TopK.
+type Match struct {
+	// The index of the embedding in the corpus.
+	Index int
+	// The cosine similarity of the embedding and the query.
+	Score float64
+}
+
+// TopK returns the k embeddings of corpus most similar to query, by decreasing
+// cosine similarity. Embeddings with the same similarity are in the order of
+// corpus. If k is not positive, all embeddings are returned.
+func TopK(query []float32, corpus [][]float32, k int) []Match {
+	matches := make([]Match, len(corpus))
+	for i, embedding := range corpus {
+		matches[i] = Match{Index: i, Score: CosineSimilarity(query, embedding)}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if k > 0 && len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+func norm(v []float32) float64 {
+	return math.Sqrt(DotProduct(v, v))
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embeddings
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name        string
+		a, b        []float32
+		dot, cosine float64
+	}{
+		{"same direction", []float32{1, 2}, []float32{2, 4}, 10, 1},
+		{"orthogonal", []float32{1, 0}, []float32{0, 3}, 0, 0},
+		{"opposite", []float32{1, 1}, []float32{-1, -1}, -2, -1},
+		{"zero", []float32{0, 0}, []float32{1, 1}, 0, 0},
+		{"different lengths", []float32{1}, []float32{1, 1}, 0, 0},
+		{"empty", nil, nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DotProduct(tt.a, tt.b); math.Abs(got-tt.dot) > 1e-9 {
+				t.Errorf("DotProduct() = %v, want %v", got, tt.dot)
+			}
+			if got := CosineSimilarity(tt.a, tt.b); math.Abs(got-tt.cosine) > 1e-6 {
+				t.Errorf("CosineSimilarity() = %v, want %v", got, tt.cosine)
+			}
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	approx := cmpopts.EquateApprox(0, 1e-6)
+	v := []float32{3, 4}
+	if diff := cmp.Diff([]float32{0.6, 0.8}, Normalize(v), approx); diff != "" {
+		t.Errorf("Normalize() mismatch (-want +got):\n%s", diff)
+	}
+	if v[0] != 3 {
+		t.Errorf("Normalize() modified its argument: %v", v)
+	}
+	if diff := cmp.Diff([]float32{0, 0}, Normalize([]float32{0, 0})); diff != "" {
+		t.Errorf("Normalize() of zero mismatch (-want +got):\n%s", diff)
+	}
+
+	embedding := Normalize([]float32{3, 4, 12})
+	if diff := cmp.Diff([]float32{0.6, 0.8}, MatryoshkaTruncate(embedding, 2), approx); diff != "" {
+		t.Errorf("MatryoshkaTruncate() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(embedding, MatryoshkaTruncate(embedding, 10), approx); diff != "" {
+		t.Errorf("MatryoshkaTruncate() over the length mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTopK(t *testing.T) {
+	corpus := [][]float32{{0, 1}, {1, 0}, {1, 1}, {2, 0}}
+	got := TopK([]float32{1, 0}, corpus, 3)
+	want := []Match{{Index: 1, Score: 1}, {Index: 3, Score: 1}, {Index: 2, Score: math.Sqrt2 / 2}}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-6)); diff != "" {
+		t.Errorf("TopK() mismatch (-want +got):\n%s", diff)
+	}
+	if got := TopK([]float32{1, 0}, corpus, 0); len(got) != 4 {
+		t.Errorf("TopK() with k 0 = %v, want all embeddings", got)
+	}
+}
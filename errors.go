@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Canonical statuses of API errors, see [APIError.CanonicalStatus].
+const (
+	StatusInvalidArgument    = "INVALID_ARGUMENT"
+	StatusFailedPrecondition = "FAILED_PRECONDITION"
+	StatusUnauthenticated    = "UNAUTHENTICATED"
+	StatusPermissionDenied   = "PERMISSION_DENIED"
+	StatusNotFound           = "NOT_FOUND"
+	StatusAborted            = "ABORTED"
+	StatusResourceExhausted  = "RESOURCE_EXHAUSTED"
+	StatusCancelled          = "CANCELLED"
+	StatusInternal           = "INTERNAL"
+	StatusUnimplemented      = "UNIMPLEMENTED"
+	StatusUnavailable        = "UNAVAILABLE"
+	StatusDeadlineExceeded   = "DEADLINE_EXCEEDED"
+)
+
+// canonicalStatuses maps HTTP status codes to the canonical status of
+// errors that have none.
+var canonicalStatuses = map[int]string{
+	http.StatusBadRequest:          StatusInvalidArgument,
+	http.StatusUnauthorized:        StatusUnauthenticated,
+	http.StatusForbidden:           StatusPermissionDenied,
+	http.StatusNotFound:            StatusNotFound,
+	http.StatusConflict:            StatusAborted,
+	http.StatusPreconditionFailed:  StatusFailedPrecondition,
+	http.StatusTooManyRequests:     StatusResourceExhausted,
+	499:                            StatusCancelled,
+	http.StatusInternalServerError: StatusInternal,
+	http.StatusNotImplemented:      StatusUnimplemented,
+	http.StatusServiceUnavailable:  StatusUnavailable,
+	http.StatusGatewayTimeout:      StatusDeadlineExceeded,
+}
+
+// AsAPIError returns the first APIError in the chain of err, returned as an
+// APIError or a *APIError by any service, including the errors yielded by
+// streaming iterators.
+func AsAPIError(err error) (APIError, bool) {
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	var ptr *APIError
+	if errors.As(err, &ptr) && ptr != nil {
+		return *ptr, true
+	}
+	return APIError{}, false
+}
+
+// CanonicalStatus returns the canonical status of the error, e.g.
+// "NOT_FOUND", as sent by the server or, for errors without a canonical
+// status such as plain-text error responses, derived from the HTTP status
+// code. It returns an empty string if neither is known.
+func (e APIError) CanonicalStatus() string {
+	if e.Status != "" && strings.ToUpper(e.Status) == e.Status && !strings.ContainsAny(e.Status, " 0123456789") {
+		return e.Status
+	}
+	return canonicalStatuses[e.Code]
+}
+
+// Reason returns the reason of the google.rpc.ErrorInfo detail of the error,
+// e.g. "API_KEY_INVALID", or an empty string if it has none.
+func (e APIError) Reason() string {
+	if detail := e.detail("google.rpc.ErrorInfo"); detail != nil {
+		reason, _ := detail["reason"].(string)
+		return reason
+	}
+	return ""
+}
+
+// RetryDelay returns the delay the server asks clients to wait before
+// retrying, from the google.rpc.RetryInfo detail of the error.
+func (e APIError) RetryDelay() (time.Duration, bool) {
+	detail := e.detail("google.rpc.RetryInfo")
+	if detail == nil {
+		return 0, false
+	}
+	delay, _ := detail["retryDelay"].(string)
+	d, err := time.ParseDuration(delay)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// FieldViolations returns the invalid fields of the request listed in the
+// google.rpc.BadRequest details of the error.
+func (e APIError) FieldViolations() []FieldViolation {
+	var violations []FieldViolation
+	for _, detail := range e.Details {
+		if t, _ := detail["@type"].(string); !strings.HasSuffix(t, "google.rpc.BadRequest") {
+			continue
+		}
+		items, _ := detail["fieldViolations"].([]any)
+		for _, item := range items {
+			v, _ := item.(map[string]any)
+			field, _ := v["field"].(string)
+			description, _ := v["description"].(string)
+			violations = append(violations, FieldViolation{Field: field, Description: description})
+		}
+	}
+	return violations
+}
+
+// detail returns the first detail of the error of the given type, e.g.
+// "google.rpc.ErrorInfo", or nil.
+func (e APIError) detail(typeName string) map[string]any {
+	for _, detail := range e.Details {
+		if t, _ := detail["@type"].(string); strings.HasSuffix(t, typeName) {
+			return detail
+		}
+	}
+	return nil
+}
+
+// hasStatus reports whether err is an APIError with the canonical status.
+func hasStatus(err error, status string) bool {
+	apiErr, ok := AsAPIError(err)
+	return ok && apiErr.CanonicalStatus() == status
+}
+
+// IsRateLimited reports whether err is an APIError for an exhausted quota or
+// rate limit, i.e. with status code 429 or status RESOURCE_EXHAUSTED.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, StatusResourceExhausted)
+}
+
+// IsInvalidArgument reports whether err is an APIError for an invalid
+// request, i.e. with status code 400 or status INVALID_ARGUMENT.
+func IsInvalidArgument(err error) bool {
+	return hasStatus(err, StatusInvalidArgument)
+}
+
+// IsNotFound reports whether err is an APIError for a resource that does not
+// exist, i.e. with status code 404 or status NOT_FOUND.
+func IsNotFound(err error) bool {
+	return hasStatus(err, StatusNotFound)
+}
+
+// IsPermissionDenied reports whether err is an APIError for a request that
+// the caller is not allowed to make, i.e. with status code 403 or status
+// PERMISSION_DENIED.
+func IsPermissionDenied(err error) bool {
+	return hasStatus(err, StatusPermissionDenied)
+}
+
+// IsUnauthenticated reports whether err is an APIError for missing or invalid
+// credentials, i.e. with status code 401 or status UNAUTHENTICATED.
+func IsUnauthenticated(err error) bool {
+	return hasStatus(err, StatusUnauthenticated)
+}
+
+// IsUnavailable reports whether err is an APIError for a service that is
+// temporarily unavailable, i.e. with status code 503 or status UNAVAILABLE.
+func IsUnavailable(err error) bool {
+	return hasStatus(err, StatusUnavailable)
+}
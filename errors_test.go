@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAPIErrorPredicates(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus string
+		predicate  func(error) bool
+	}{
+		{"RateLimitedByCode", APIError{Code: 429, Status: "429 Too Many Requests"}, StatusResourceExhausted, IsRateLimited},
+		{"RateLimitedByStatus", APIError{Code: 429, Status: "RESOURCE_EXHAUSTED"}, StatusResourceExhausted, IsRateLimited},
+		{"InvalidArgumentWrapped", fmt.Errorf("call failed: %w", APIError{Code: 400, Status: "INVALID_ARGUMENT"}), StatusInvalidArgument, IsInvalidArgument},
+		{"NotFoundPointer", &APIError{Code: 404}, StatusNotFound, IsNotFound},
+		{"PermissionDenied", APIError{Code: 403, Status: "PERMISSION_DENIED"}, StatusPermissionDenied, IsPermissionDenied},
+		{"Unauthenticated", APIError{Code: 401}, StatusUnauthenticated, IsUnauthenticated},
+		{"Unavailable", APIError{Code: 503, Status: "UNAVAILABLE"}, StatusUnavailable, IsUnavailable},
+		{"StatusOverCode", APIError{Code: 400, Status: "FAILED_PRECONDITION"}, StatusFailedPrecondition, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr, ok := AsAPIError(tt.err)
+			if !ok {
+				t.Fatalf("AsAPIError(%v) = false", tt.err)
+			}
+			if got := apiErr.CanonicalStatus(); got != tt.wantStatus {
+				t.Errorf("CanonicalStatus() = %q, want %q", got, tt.wantStatus)
+			}
+			if tt.predicate != nil && !tt.predicate(tt.err) {
+				t.Errorf("predicate(%v) = false, want true", tt.err)
+			}
+		})
+	}
+	if IsNotFound(errors.New("404 not found")) {
+		t.Error("IsNotFound() is true for an error that is not an APIError")
+	}
+	if _, ok := AsAPIError(nil); ok {
+		t.Error("AsAPIError(nil) = true")
+	}
+}
+
+func TestAPIErrorDetails(t *testing.T) {
+	apiErr := APIError{Code: 429, Details: []map[string]any{
+		{"@type": "type.googleapis.com/google.rpc.ErrorInfo", "reason": "RATE_LIMIT_EXCEEDED"},
+		{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "17s"},
+		{"@type": "type.googleapis.com/google.rpc.BadRequest", "fieldViolations": []any{
+			map[string]any{"field": "contents", "description": "must not be empty"},
+		}},
+	}}
+	if got := apiErr.Reason(); got != "RATE_LIMIT_EXCEEDED" {
+		t.Errorf("Reason() = %q", got)
+	}
+	if got, ok := apiErr.RetryDelay(); !ok || got != 17*time.Second {
+		t.Errorf("RetryDelay() = %v, %v, want 17s", got, ok)
+	}
+	want := []FieldViolation{{Field: "contents", Description: "must not be empty"}}
+	if diff := cmp.Diff(want, apiErr.FieldViolations()); diff != "" {
+		t.Errorf("FieldViolations() mismatch (-want +got):\n%s", diff)
+	}
+	if _, ok := (APIError{}).RetryDelay(); ok {
+		t.Error("RetryDelay() of an error without RetryInfo = true")
+	}
+}
+
+func TestStreamAPIError(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("X-Request-Id", "req-123")
+		fmt.Fprint(w, "{\"error\":{\"code\":429,\"message\":\"quota\",\"status\":\"RESOURCE_EXHAUSTED\"}}\n\n")
+	})
+	var gotErr error
+	for _, err := range client.Models.GenerateContentStream(ctx, "gemini-2.5-flash", Text("Hi"), nil) {
+		if err != nil {
+			gotErr = err
+		}
+	}
+	if !IsRateLimited(gotErr) {
+		t.Fatalf("IsRateLimited(%v) = false", gotErr)
+	}
+	apiErr, _ := AsAPIError(gotErr)
+	if apiErr.Backend != BackendGeminiAPI || apiErr.Model != "gemini-2.5-flash" || apiErr.RequestID != "req-123" {
+		t.Errorf("stream error = %+v, want the backend, model and request ID of the stream", apiErr)
+	}
+}
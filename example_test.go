@@ -629,16 +629,16 @@ func ExampleModels_GenerateContent_config_vertexai() {
 		"gemini-2.5-flash",
 		genai.Text("Tell me about New York?"),
 		&genai.GenerateContentConfig{
-			Temperature:      genai.Ptr[float32](0.5),
-			TopP:             genai.Ptr[float32](0.5),
-			TopK:             genai.Ptr[float32](2.0),
+			Temperature:      genai.Float32(0.5),
+			TopP:             genai.Float32(0.5),
+			TopK:             genai.Float32(2.0),
 			ResponseMIMEType: "application/json",
 			StopSequences:    []string{"\n"},
 			CandidateCount:   2,
-			Seed:             genai.Ptr[int32](42),
+			Seed:             genai.Int32(42),
 			MaxOutputTokens:  128,
-			PresencePenalty:  genai.Ptr[float32](0.5),
-			FrequencyPenalty: genai.Ptr[float32](0.5),
+			PresencePenalty:  genai.Float32(0.5),
+			FrequencyPenalty: genai.Float32(0.5),
 		},
 	)
 	if err != nil {
@@ -663,16 +663,16 @@ func ExampleModels_GenerateContent_config_geminiapi() {
 		"gemini-2.5-flash",
 		genai.Text("Tell me about New York?"),
 		&genai.GenerateContentConfig{
-			Temperature:      genai.Ptr[float32](0.5),
-			TopP:             genai.Ptr[float32](0.5),
-			TopK:             genai.Ptr[float32](2.0),
+			Temperature:      genai.Float32(0.5),
+			TopP:             genai.Float32(0.5),
+			TopK:             genai.Float32(2.0),
 			ResponseMIMEType: "application/json",
 			StopSequences:    []string{"\n"},
 			CandidateCount:   2,
-			Seed:             genai.Ptr[int32](42),
+			Seed:             genai.Int32(42),
 			MaxOutputTokens:  128,
-			PresencePenalty:  genai.Ptr[float32](0.5),
-			FrequencyPenalty: genai.Ptr[float32](0.5),
+			PresencePenalty:  genai.Float32(0.5),
+			FrequencyPenalty: genai.Float32(0.5),
 		},
 	)
 	if err != nil {
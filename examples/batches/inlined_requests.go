@@ -66,7 +66,7 @@ func run(ctx context.Context) {
 						},
 					},
 				},
-				Temperature: genai.Ptr[float32](0.5),
+				Temperature: genai.Float32(0.5),
 			},
 		},
 		{
@@ -88,7 +88,7 @@ func run(ctx context.Context) {
 						},
 					},
 				},
-				Temperature: genai.Ptr[float32](0.5),
+				Temperature: genai.Float32(0.5),
 			},
 		},
 	}
@@ -37,7 +37,7 @@ func chat(ctx context.Context) {
 	} else {
 		fmt.Println("Calling GeminiAPI Backend...")
 	}
-	var config *genai.GenerateContentConfig = &genai.GenerateContentConfig{Temperature: genai.Ptr[float32](0.5)}
+	var config *genai.GenerateContentConfig = &genai.GenerateContentConfig{Temperature: genai.Float32(0.5)}
 
 	// Create a new Chat.
 	chat, err := client.Chats.Create(ctx, *model, config, nil)
@@ -74,7 +74,7 @@ func run(ctx context.Context) {
 				StreamFunctionCallArguments: &streamingArgument,
 			},
 		},
-		Temperature: genai.Ptr[float32](0),
+		Temperature: genai.Float32(0),
 		Tools:       tools,
 	}
 
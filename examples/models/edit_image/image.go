@@ -70,7 +70,7 @@ func run(ctx context.Context) {
 		ReferenceID: 2,
 		Config: &genai.MaskReferenceConfig{
 			MaskMode:     "MASK_MODE_BACKGROUND",
-			MaskDilation: genai.Ptr[float32](0.0),
+			MaskDilation: genai.Float32(0.0),
 		},
 	}
 	response3, err := client.Models.EditImage(
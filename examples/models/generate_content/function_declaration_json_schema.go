@@ -64,7 +64,7 @@ func run(ctx context.Context) {
 	} else {
 		fmt.Println("Calling GeminiAPI Backend...")
 	}
-	var config *genai.GenerateContentConfig = &genai.GenerateContentConfig{Temperature: genai.Ptr[float32](0), Tools: tools}
+	var config *genai.GenerateContentConfig = &genai.GenerateContentConfig{Temperature: genai.Float32(0), Tools: tools}
 	// Call the GenerateContent method.
 	result, err := client.Models.GenerateContent(ctx, *model, genai.Text("Control the light in the living room to 50% brightness and warm white color."), config)
 	if err != nil {
@@ -37,7 +37,7 @@ func run(ctx context.Context) {
 	} else {
 		fmt.Println("Calling GeminiAPI Backend...")
 	}
-	var config *genai.GenerateContentConfig = &genai.GenerateContentConfig{Temperature: genai.Ptr[float32](0)}
+	var config *genai.GenerateContentConfig = &genai.GenerateContentConfig{Temperature: genai.Float32(0)}
 	// Call the GenerateContent method.
 	result, err := client.Models.GenerateContent(ctx, *model, genai.Text("What is your name?"), config)
 	if err != nil {
@@ -70,7 +70,7 @@ func run(ctx context.Context) {
 			IncludeRAIReason:        true,
 			OutputMIMEType:          "image/jpeg",
 			EnhanceInputImage:       true,
-			ImagePreservationFactor: genai.Ptr[float32](0.6),
+			ImagePreservationFactor: genai.Float32(0.6),
 		})
 	if err != nil {
 		log.Fatal(err)
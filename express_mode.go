@@ -0,0 +1,62 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"fmt"
+	"strings"
+)
+
+// expressModeUnsupported are the top-level resource collections that Vertex
+// AI express mode cannot serve because they belong to a project: deployed
+// endpoints, tuned models and RAG corpora.
+var expressModeUnsupported = map[string]string{
+	"endpoints":  "deployed endpoints",
+	"models":     "tuned models",
+	"ragCorpora": "RAG corpora",
+}
+
+// ExpressModeError is returned for requests that Vertex AI express mode does
+// not support, e.g. for deployed endpoints and tuned models. Such requests need
+// a client with a project, a location and Google Cloud credentials instead of
+// an API key.
+type ExpressModeError struct {
+	// Resource describes the unsupported resource, e.g. "deployed endpoints".
+	Resource string
+}
+
+func (e *ExpressModeError) Error() string {
+	return fmt.Sprintf("%s are not supported in Vertex AI express mode. You can use them by setting ClientConfig.Project and ClientConfig.Location and using Google Cloud credentials instead of an API key.", e.Resource)
+}
+
+// expressMode reports whether the client uses Vertex AI in express mode, i.e.
+// with an API key instead of a project and credentials.
+func (ac *apiClient) expressMode() bool {
+	return ac.clientConfig.Backend == BackendVertexAI && ac.clientConfig.APIKey != ""
+}
+
+// checkExpressModePath returns an *ExpressModeError if path is not served by
+// express mode.
+func checkExpressModePath(path string) error {
+	if strings.HasPrefix(path, "projects/") {
+		return nil
+	}
+	collection, _, _ := strings.Cut(path, "/")
+	collection, _, _ = strings.Cut(collection, ":")
+	if resource, ok := expressModeUnsupported[collection]; ok {
+		return &ExpressModeError{Resource: resource}
+	}
+	return nil
+}
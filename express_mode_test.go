@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExpressMode(t *testing.T) {
+	ctx := context.Background()
+	var requests []string
+	var models []any
+	client := newTestClient(t, &ClientConfig{
+		Backend: BackendVertexAI,
+		APIKey:  "test-api-key",
+	}, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("x-goog-api-key"); got != "test-api-key" {
+			t.Errorf("x-goog-api-key = %q, want test-api-key", got)
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		models = append(models, body["model"])
+		switch r.URL.Path {
+		case "/v1beta1/cachedContents", "/v1beta1/cachedContents/abc":
+			w.Write([]byte(`{"name": "cachedContents/abc", "model": "publishers/google/models/gemini-2.5-flash"}`))
+		default:
+			w.Write([]byte(`{"candidates": [{"content": {"role": "model", "parts": [{"text": "hi"}]}}]}`))
+		}
+	})
+
+	for _, model := range []string{"gemini-2.5-flash", "models/gemini-2.5-flash"} {
+		if _, err := client.Models.GenerateContent(ctx, model, Text("Hi"), nil); err != nil {
+			t.Errorf("GenerateContent(%q) failed: %v", model, err)
+		}
+	}
+	cache, err := client.Caches.Create(ctx, "models/gemini-2.5-flash", nil)
+	if err != nil {
+		t.Fatalf("Caches.Create() failed: %v", err)
+	}
+	if _, err := client.Caches.Get(ctx, "abc", nil); err != nil {
+		t.Errorf("Caches.Get() failed: %v", err)
+	}
+	if cache.ModelID() != "gemini-2.5-flash" {
+		t.Errorf("ModelID() = %q, want gemini-2.5-flash", cache.ModelID())
+	}
+	wantRequests := []string{
+		"POST /v1beta1/publishers/google/models/gemini-2.5-flash:generateContent",
+		"POST /v1beta1/publishers/google/models/gemini-2.5-flash:generateContent",
+		"POST /v1beta1/cachedContents",
+		"GET /v1beta1/cachedContents/abc",
+	}
+	if diff := cmp.Diff(wantRequests, requests); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+	if models[2] != "publishers/google/models/gemini-2.5-flash" {
+		t.Errorf("cache model = %v, want a model without a project", models[2])
+	}
+
+	var expressErr *ExpressModeError
+	if _, err := client.Models.GenerateContent(ctx, "endpoints/123", Text("Hi"), nil); !errors.As(err, &expressErr) || expressErr.Resource != "deployed endpoints" {
+		t.Errorf("GenerateContent() on an endpoint = %v, want an ExpressModeError", err)
+	}
+	if _, err := client.RAGCorpora.Get(ctx, "123", nil); !errors.As(err, &expressErr) {
+		t.Errorf("RAGCorpora.Get() = %v, want an ExpressModeError", err)
+	}
+	if len(requests) != len(wantRequests) {
+		t.Errorf("unsupported requests were sent: %q", requests[len(wantRequests):])
+	}
+}
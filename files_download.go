@@ -0,0 +1,307 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultDownloadMaxAttempts = 3
+	defaultDownloadRetryDelay  = time.Second
+)
+
+// DownloadToConfig configures Files.DownloadTo and Files.DownloadToFile.
+type DownloadToConfig struct {
+	// Optional. Used to override HTTP request options.
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+	// Optional. Called after every chunk written.
+	OnProgress func(DownloadProgress) `json:"-"`
+	// Optional. The maximum number of attempts for a download interrupted by
+	// a network error or a retryable status such as 429 or 503. Every retry
+	// resumes the download where it stopped. Defaults to 3.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// Optional. The delay before the first retry, doubled for every further
+	// retry. Defaults to 1s.
+	RetryDelay time.Duration `json:"retryDelay,omitempty"`
+	// Optional. Whether to verify the SHA-256 hash of the downloaded content
+	// against the Sha256Hash reported by the server. The hash is taken from the
+	// File that is downloaded, or from Files.Get if it is not set. A download
+	// that does not match fails with ErrChecksumMismatch. Videos that already
+	// have VideoBytes are not verified.
+	VerifyChecksum bool `json:"verifyChecksum,omitempty"`
+}
+
+// ErrChecksumMismatch is returned by Files.DownloadTo and Files.DownloadToFile
+// with DownloadToConfig.VerifyChecksum for content whose hash does not match
+// the hash reported by the server.
+var ErrChecksumMismatch = errors.New("the downloaded content does not match the checksum reported by the server")
+
+// DownloadProgress reports the progress of a download.
+type DownloadProgress struct {
+	// Written is the number of bytes written so far, including the bytes of
+	// a download that was resumed.
+	Written int64
+	// Total is the size of the content, or -1 if it is unknown.
+	Total int64
+}
+
+// DownloadTo downloads the content of a file or generated video to w without
+// buffering it in memory, which suits large artifacts such as videos. Unlike
+// Download, it does not set the VideoBytes of a video. Videos that already
+// have VideoBytes, e.g. videos generated by Vertex AI without a GCS output,
+// are written as is. It returns the number of bytes written.
+func (m Files) DownloadTo(ctx context.Context, uri DownloadURI, w io.Writer, config *DownloadToConfig) (int64, error) {
+	return m.downloadTo(ctx, uri, w, 0, config)
+}
+
+// DownloadToFile downloads the content of a file or generated video to the
+// file at path like DownloadTo. The content is first written to path with a
+// ".download" suffix, which is renamed to path once it is complete, so that
+// a download that failed is resumed by calling DownloadToFile again. A
+// download that fails the checksum verification is removed.
+func (m Files) DownloadToFile(ctx context.Context, uri DownloadURI, path string, config *DownloadToConfig) (int64, error) {
+	partial := path + ".download"
+	f, err := os.OpenFile(partial, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+	n, err := m.downloadTo(ctx, uri, f, info.Size(), config)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if errors.Is(err, ErrChecksumMismatch) {
+		os.Remove(partial)
+	}
+	if err != nil {
+		return n, err
+	}
+	return n, os.Rename(partial, path)
+}
+
+// videoBytes returns the bytes of a video that has already been downloaded or
+// that was returned inline.
+func videoBytes(uri DownloadURI) []byte {
+	switch v := uri.(type) {
+	case *Video:
+		return v.VideoBytes
+	case *GeneratedVideo:
+		if v.Video != nil {
+			return v.Video.VideoBytes
+		}
+	}
+	return nil
+}
+
+// downloadTo downloads uri to w, skipping the first offset bytes that w
+// already has. To verify the checksum of a resumed download, w must be an
+// io.ReaderAt from which the first offset bytes are read.
+func (m Files) downloadTo(ctx context.Context, uri DownloadURI, w io.Writer, offset int64, config *DownloadToConfig) (int64, error) {
+	if config == nil {
+		config = &DownloadToConfig{}
+	}
+	if data := videoBytes(uri); len(data) > 0 {
+		if offset > int64(len(data)) {
+			offset = int64(len(data))
+		}
+		n, err := io.Copy(w, bytes.NewReader(data[offset:]))
+		if config.OnProgress != nil {
+			config.OnProgress(DownloadProgress{Written: offset + n, Total: int64(len(data))})
+		}
+		return offset + n, err
+	}
+	if m.apiClient.clientConfig.Backend == BackendVertexAI {
+		return offset, fmt.Errorf("method DownloadTo is only supported in the Gemini Developer client. You can choose to use Gemini Developer client by setting ClientConfig.Backend to BackendGeminiAPI.")
+	}
+	if uri.uri() == "" {
+		return offset, fmt.Errorf("the resource doesn't support download")
+	}
+	fileName, err := tFileName(uri.uri())
+	if err != nil {
+		return offset, err
+	}
+	path := fmt.Sprintf("files/%s:download?alt=media", fileName)
+	httpOptions := mergeHTTPOptions(m.apiClient.clientConfig, config.HTTPOptions)
+
+	var checksum *downloadChecksum
+	if config.VerifyChecksum {
+		if checksum, err = m.newDownloadChecksum(ctx, uri, fileName, w, offset); err != nil {
+			return offset, err
+		}
+		w = io.MultiWriter(w, checksum.hash)
+	}
+
+	maxAttempts := config.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultDownloadMaxAttempts
+	}
+	delay := config.RetryDelay
+	if delay <= 0 {
+		delay = defaultDownloadRetryDelay
+	}
+	written := offset
+	for attempt := 1; ; attempt++ {
+		var done bool
+		done, err = m.downloadRange(ctx, path, httpOptions, w, &written, config.OnProgress)
+		if done && err == nil && checksum != nil {
+			return written, checksum.verify()
+		}
+		if done || !isTransientError(err) || attempt >= maxAttempts {
+			return written, err
+		}
+		select {
+		case <-ctx.Done():
+			return written, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// downloadRange requests the content from *written on and copies it to w. It
+// reports whether the download is complete.
+func (m Files) downloadRange(ctx context.Context, path string, httpOptions *HTTPOptions, w io.Writer, written *int64, onProgress func(DownloadProgress)) (bool, error) {
+	req, _, err := buildRequest(ctx, m.apiClient, path, nil, http.MethodGet, httpOptions)
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	if *written > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", *written))
+	}
+	resp, err := doRequest(m.apiClient, req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	total := int64(-1)
+	switch {
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && *written > 0:
+		// The content was already downloaded completely.
+		return true, nil
+	case resp.StatusCode == http.StatusPartialContent:
+		total = contentRangeTotal(resp.Header.Get("Content-Range"))
+	case resp.StatusCode == http.StatusOK:
+		total = resp.ContentLength
+		// The server ignored the range, skip the bytes that w already has.
+		if _, err := io.CopyN(io.Discard, resp.Body, *written); err != nil {
+			return false, err
+		}
+	default:
+		return false, m.apiClient.withBackend(newAPIError(resp))
+	}
+
+	buf := make([]byte, 256*1024)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return false, err
+			}
+			*written += int64(n)
+			if onProgress != nil {
+				onProgress(DownloadProgress{Written: *written, Total: total})
+			}
+		}
+		if readErr == io.EOF {
+			if total >= 0 && *written < total {
+				return false, io.ErrUnexpectedEOF
+			}
+			return true, nil
+		}
+		if readErr != nil {
+			return false, readErr
+		}
+	}
+}
+
+// contentRangeTotal returns the complete length of a "bytes a-b/total"
+// Content-Range header, or -1 if it is unknown.
+func contentRangeTotal(contentRange string) int64 {
+	_, total, ok := strings.Cut(contentRange, "/")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(total, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// downloadChecksum verifies the SHA-256 hash of a download.
+type downloadChecksum struct {
+	fileName string
+	// want is the hash reported by the server, hex or base64 encoded.
+	want string
+	hash hash.Hash
+}
+
+// newDownloadChecksum returns the checksum of the download of uri, with the
+// first offset bytes of w, which a resumed download already has, hashed.
+func (m Files) newDownloadChecksum(ctx context.Context, uri DownloadURI, fileName string, w io.Writer, offset int64) (*downloadChecksum, error) {
+	c := &downloadChecksum{fileName: fileName, hash: sha256.New()}
+	if f, ok := uri.(*File); ok {
+		c.want = f.Sha256Hash
+	}
+	if c.want == "" {
+		file, err := m.Get(ctx, fileName, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the checksum of file %s: %w", fileName, err)
+		}
+		c.want = file.Sha256Hash
+	}
+	if c.want == "" {
+		return nil, fmt.Errorf("the server did not report a checksum of file %s", fileName)
+	}
+	if offset > 0 {
+		r, ok := w.(io.ReaderAt)
+		if !ok {
+			return nil, errors.New("cannot verify the checksum of a resumed download to a writer that is not an io.ReaderAt")
+		}
+		if _, err := io.Copy(c.hash, io.NewSectionReader(r, 0, offset)); err != nil {
+			return nil, fmt.Errorf("failed to read the downloaded content: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (c *downloadChecksum) verify() error {
+	sum := c.hash.Sum(nil)
+	if strings.EqualFold(hex.EncodeToString(sum), c.want) ||
+		base64.StdEncoding.EncodeToString(sum) == c.want || base64.URLEncoding.EncodeToString(sum) == c.want {
+		return nil
+	}
+	return fmt.Errorf("file %s: %w: got SHA-256 %x, want %s", c.fileName, ErrChecksumMismatch, sum, c.want)
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilesDownloadTo(t *testing.T) {
+	ctx := context.Background()
+	content := []byte(strings.Repeat("0123456789", 100))
+	var ranges []string
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/files/abc:download" {
+			http.NotFound(w, r)
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) == 2 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		start := 0
+		if rng := r.Header.Get("Range"); rng != "" {
+			start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)-start))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(content[start:])
+			return
+		}
+		// Announce the whole content but stop after 300 bytes.
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Write(content[:300])
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	})
+
+	var progress []DownloadProgress
+	var buf bytes.Buffer
+	config := &DownloadToConfig{
+		RetryDelay: time.Millisecond,
+		OnProgress: func(p DownloadProgress) { progress = append(progress, p) },
+	}
+	video := &GeneratedVideo{Video: &Video{URI: "https://generativelanguage.googleapis.com/v1beta/files/abc:download?alt=media"}}
+	n, err := client.Files.DownloadTo(ctx, video, &buf, config)
+	if err != nil {
+		t.Fatalf("DownloadTo() failed: %v", err)
+	}
+	if n != int64(len(content)) || !bytes.Equal(buf.Bytes(), content) {
+		t.Errorf("DownloadTo() wrote %d bytes %q, want the content", n, buf.Bytes())
+	}
+	if want := []string{"", "bytes=300-", "bytes=300-"}; fmt.Sprint(ranges) != fmt.Sprint(want) {
+		t.Errorf("Range headers = %q, want %q", ranges, want)
+	}
+	if last := progress[len(progress)-1]; last.Written != int64(len(content)) || last.Total != int64(len(content)) {
+		t.Errorf("last progress = %+v, want the complete content", last)
+	}
+	if video.Video.VideoBytes != nil {
+		t.Errorf("DownloadTo() set VideoBytes")
+	}
+
+	t.Run("ResumeFile", func(t *testing.T) {
+		ranges = []string{"", ""}
+		path := filepath.Join(t.TempDir(), "video.mp4")
+		if err := os.WriteFile(path+".download", content[:700], 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Files.DownloadToFile(ctx, video, path, nil); err != nil {
+			t.Fatalf("DownloadToFile() failed: %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, content) {
+			t.Errorf("downloaded file = %q, want the content", got)
+		}
+		if ranges[2] != "bytes=700-" {
+			t.Errorf("Range header = %q, want bytes=700-", ranges[2])
+		}
+	})
+
+	t.Run("VideoBytes", func(t *testing.T) {
+		buf.Reset()
+		if _, err := client.Files.DownloadTo(ctx, &Video{VideoBytes: []byte("inline")}, &buf, nil); err != nil {
+			t.Fatalf("DownloadTo() failed: %v", err)
+		}
+		if buf.String() != "inline" {
+			t.Errorf("DownloadTo() wrote %q, want inline", buf.String())
+		}
+	})
+}
+
+func TestFilesDownloadToVerifyChecksum(t *testing.T) {
+	ctx := context.Background()
+	content := []byte(strings.Repeat("0123456789", 100))
+	sum := sha256.Sum256(content)
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1beta/files/abc":
+			fmt.Fprintf(w, `{"name": "files/abc", "sha256Hash": %q}`, base64.StdEncoding.EncodeToString(sum[:]))
+		case "/v1beta/files/abc:download":
+			start := 0
+			if rng := r.Header.Get("Range"); rng != "" {
+				start, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(rng, "bytes="), "-"))
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+				w.WriteHeader(http.StatusPartialContent)
+			}
+			w.Write(content[start:])
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	downloadURI := "https://generativelanguage.googleapis.com/v1beta/files/abc:download?alt=media"
+	config := &DownloadToConfig{VerifyChecksum: true}
+
+	t.Run("File", func(t *testing.T) {
+		var buf bytes.Buffer
+		file := &File{Name: "files/abc", DownloadURI: downloadURI, Sha256Hash: hex.EncodeToString(sum[:])}
+		if _, err := client.Files.DownloadTo(ctx, file, &buf, config); err != nil {
+			t.Fatalf("DownloadTo() failed: %v", err)
+		}
+		file.Sha256Hash = strings.Repeat("0", 64)
+		if _, err := client.Files.DownloadTo(ctx, file, &buf, config); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("DownloadTo() with a wrong hash = %v, want ErrChecksumMismatch", err)
+		}
+	})
+
+	t.Run("HashFromGet", func(t *testing.T) {
+		var buf bytes.Buffer
+		video := &Video{URI: downloadURI}
+		if _, err := client.Files.DownloadTo(ctx, video, &buf, config); err != nil {
+			t.Fatalf("DownloadTo() failed: %v", err)
+		}
+		if !bytes.Equal(buf.Bytes(), content) {
+			t.Errorf("DownloadTo() wrote %q, want the content", buf.Bytes())
+		}
+	})
+
+	t.Run("ResumeFile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "video.mp4")
+		if err := os.WriteFile(path+".download", content[:400], 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Files.DownloadToFile(ctx, &Video{URI: downloadURI}, path, config); err != nil {
+			t.Fatalf("DownloadToFile() failed: %v", err)
+		}
+
+		corrupt := bytes.Clone(content[:400])
+		corrupt[0] = 'x'
+		if err := os.WriteFile(path+".download", corrupt, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Files.DownloadToFile(ctx, &Video{URI: downloadURI}, path, config); !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("DownloadToFile() of a corrupt partial download = %v, want ErrChecksumMismatch", err)
+		}
+		if _, err := os.Stat(path + ".download"); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("DownloadToFile() kept the corrupt partial download: %v", err)
+		}
+	})
+}
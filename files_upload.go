@@ -0,0 +1,376 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// uploadChunkGranularity is the multiple of the size of every chunk of a
+	// resumable upload but the last.
+	uploadChunkGranularity   = 256 * 1024
+	defaultUploadMaxAttempts = 3
+	defaultUploadRetryDelay  = time.Second
+)
+
+// UploadFromReaderConfig configures Files.UploadFromReader.
+type UploadFromReaderConfig struct {
+	// Optional. Used to override HTTP request options.
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+	// Optional. The name of the file in the destination (e.g., 'files/sample-image'. If
+	// not provided one will be generated.
+	Name string `json:"name,omitempty"`
+	// Optional. The MIME type of the file.
+	MIMEType string `json:"mimeType,omitempty"`
+	// Optional. Optional display name of the file.
+	DisplayName string `json:"displayName,omitempty"`
+	// Optional. The size of the chunks sent in one request, rounded up to a
+	// multiple of 256 KiB. Larger chunks need fewer requests, smaller chunks
+	// repeat less content when a request fails. Defaults to 8 MiB.
+	ChunkSize int64 `json:"chunkSize,omitempty"`
+	// Optional. Called after every chunk uploaded.
+	OnProgress func(UploadProgress) `json:"-"`
+	// Optional. The maximum number of attempts for a chunk whose upload failed
+	// with a network error or a retryable status such as 429 or 503. Every
+	// retry sends the part of the chunk that the server did not receive.
+	// Overrides RetryOptions.MaxAttempts. Defaults to 3.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// Optional. The delay before the first retry of a chunk. Further retries
+	// back off as configured by RequestOptions.RetryOptions or
+	// ClientConfig.RetryOptions, with jitter, and wait at least as long as a
+	// Retry-After response header asks. Overrides RetryOptions.InitialInterval.
+	// Defaults to 1s.
+	RetryDelay time.Duration `json:"retryDelay,omitempty"`
+	// Optional. The upload URL of an interrupted upload to resume, see
+	// [UploadInterruptedError]. The reader must then read the content from its
+	// start; the content that the server already received is skipped. Name,
+	// DisplayName and the size of the content are those of the interrupted
+	// upload.
+	UploadURL string `json:"-"`
+}
+
+// UploadProgress reports the progress of an upload.
+type UploadProgress struct {
+	// Sent is the number of bytes received by the server so far, including the
+	// bytes of an upload that was resumed.
+	Sent int64
+	// Total is the size of the content, or -1 if it is unknown.
+	Total int64
+}
+
+// UploadInterruptedError is returned by Files.UploadFromReader for an upload
+// that failed after it started. Setting UploadFromReaderConfig.UploadURL to
+// UploadURL resumes the upload where it stopped, e.g. after a restart.
+type UploadInterruptedError struct {
+	// UploadURL is the URL of the resumable upload session.
+	UploadURL string
+	// Offset is the number of bytes that the server confirmed.
+	Offset int64
+	// Err is the error that interrupted the upload.
+	Err error
+}
+
+// Error returns a string representation of the UploadInterruptedError.
+func (e *UploadInterruptedError) Error() string {
+	return fmt.Sprintf("upload interrupted after %d bytes: %v", e.Offset, e.Err)
+}
+
+// Unwrap returns the error that interrupted the upload.
+func (e *UploadInterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// UploadFromReader uploads size bytes read from r in chunks and returns
+// information about the resulting file. Unlike Upload, it reports the
+// progress of the upload, retries the chunks whose upload failed, and returns
+// an *UploadInterruptedError with which a failed upload is resumed, which
+// suits large files such as videos. A negative size uploads r until io.EOF.
+func (m Files) UploadFromReader(ctx context.Context, r io.Reader, size int64, config *UploadFromReaderConfig) (*File, error) {
+	if m.apiClient.clientConfig.Backend == BackendVertexAI {
+		return nil, fmt.Errorf("method UploadFromReader is only supported in the Gemini Developer client. You can choose to use Gemini Developer client by setting ClientConfig.Backend to BackendGeminiAPI.")
+	}
+	if config == nil {
+		config = &UploadFromReaderConfig{}
+	}
+	chunkSize := config.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = maxChunkSize
+	}
+	chunkSize = (chunkSize + uploadChunkGranularity - 1) / uploadChunkGranularity * uploadChunkGranularity
+	u := &resumableUpload{
+		apiClient: m.apiClient,
+		url:       config.UploadURL,
+		retry:     m.apiClient.uploadRetryOptions(ctx, config.MaxAttempts, config.RetryDelay),
+	}
+	total := max(size, -1)
+	progress := func(sent int64) {
+		if config.OnProgress != nil {
+			config.OnProgress(UploadProgress{Sent: sent, Total: total})
+		}
+	}
+
+	var offset int64
+	if u.url == "" {
+		uploadConfig := &UploadFileConfig{
+			HTTPOptions: &HTTPOptions{Headers: http.Header{}},
+			Name:        config.Name,
+			MIMEType:    config.MIMEType,
+			DisplayName: config.DisplayName,
+		}
+		if config.HTTPOptions != nil {
+			deepCopy(*config.HTTPOptions, uploadConfig.HTTPOptions)
+		}
+		if uploadConfig.HTTPOptions.Headers == nil {
+			uploadConfig.HTTPOptions.Headers = http.Header{}
+		}
+		if size >= 0 {
+			uploadConfig.HTTPOptions.Headers.Set("X-Goog-Upload-Header-Content-Length", strconv.FormatInt(size, 10))
+		}
+		var err error
+		if u.url, u.httpOptions, err = m.startUpload(ctx, uploadConfig); err != nil {
+			return nil, err
+		}
+	} else {
+		u.httpOptions = &HTTPOptions{Headers: http.Header{}}
+		if config.HTTPOptions != nil {
+			deepCopy(*config.HTTPOptions, u.httpOptions)
+		}
+		if u.httpOptions.Headers == nil {
+			u.httpOptions.Headers = http.Header{}
+		}
+		u.httpOptions.APIVersion = ""
+		u.httpOptions.Headers.Set("X-Goog-Upload-Header-Content-Type", config.MIMEType)
+		status, err := u.query(ctx)
+		if err != nil {
+			return nil, u.interrupted(0, fmt.Errorf("failed to query the upload status: %w", err))
+		}
+		if status.file != nil {
+			progress(status.received)
+			return status.file, nil
+		}
+		offset = status.received
+		if err := skipUploaded(r, offset); err != nil {
+			return nil, u.interrupted(offset, fmt.Errorf("failed to skip the uploaded content: %w", err))
+		}
+		progress(offset)
+	}
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(r, buf)
+		final := err == io.EOF || err == io.ErrUnexpectedEOF || size >= 0 && offset+int64(n) >= size
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, u.interrupted(offset, fmt.Errorf("failed to read the content at offset %d: %w", offset, err))
+		}
+		status, err := u.sendChunk(ctx, buf[:n], offset, final)
+		if err != nil {
+			return nil, u.interrupted(offset, err)
+		}
+		offset += int64(n)
+		progress(offset)
+		if final {
+			if status.file == nil {
+				return nil, u.interrupted(offset, errors.New("upload completed but the response has no file"))
+			}
+			return status.file, nil
+		}
+		if status.state != "active" {
+			return nil, u.interrupted(offset, fmt.Errorf("unexpected upload status %q", status.state))
+		}
+	}
+}
+
+// skipUploaded skips the first n bytes of r.
+func skipUploaded(r io.Reader, n int64) error {
+	if n == 0 {
+		return nil
+	}
+	if s, ok := r.(io.Seeker); ok {
+		_, err := s.Seek(n, io.SeekCurrent)
+		return err
+	}
+	_, err := io.CopyN(io.Discard, r, n)
+	return err
+}
+
+// resumableUpload is a resumable upload session.
+type resumableUpload struct {
+	apiClient   *apiClient
+	url         string
+	httpOptions *HTTPOptions
+	retry       *RetryOptions
+}
+
+// uploadStatus is the status of a resumable upload returned by the server.
+type uploadStatus struct {
+	// state is "active" while the upload continues, "final" once it is
+	// complete.
+	state string
+	// received is the number of bytes received by the server, if it was
+	// reported.
+	received int64
+	// file is the uploaded file, once the upload is complete.
+	file *File
+}
+
+func (u *resumableUpload) interrupted(offset int64, err error) error {
+	return &UploadInterruptedError{UploadURL: u.url, Offset: offset, Err: err}
+}
+
+// sendChunk uploads chunk, which starts at offset of the content. Failed
+// requests are retried with the part of chunk that the server did not
+// receive.
+func (u *resumableUpload) sendChunk(ctx context.Context, chunk []byte, offset int64, final bool) (*uploadStatus, error) {
+	command := "upload"
+	if final {
+		command += ", finalize"
+	}
+	var sent int64
+	for attempt := 1; ; attempt++ {
+		status, err := u.do(ctx, command, offset+sent, chunk[sent:])
+		if err == nil {
+			return status, nil
+		}
+		if !isTransientError(err) || attempt >= u.retry.maxAttempts() {
+			return nil, fmt.Errorf("failed to upload the chunk at offset %d: %w", offset+sent, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(u.retry.errorDelay(attempt, err)):
+		}
+		// Ask the server which part of the chunk it received, so that only the
+		// rest is sent again.
+		if status, err := u.query(ctx); err == nil {
+			if status.file != nil && final {
+				return status, nil
+			}
+			if received := status.received - offset; received >= 0 && received <= int64(len(chunk)) {
+				sent = received
+			}
+		}
+	}
+}
+
+// query returns the status of the upload.
+func (u *resumableUpload) query(ctx context.Context) (*uploadStatus, error) {
+	return u.do(ctx, "query", -1, nil)
+}
+
+// do sends an upload command with body at offset, or without offset if offset
+// is negative.
+func (u *resumableUpload) do(ctx context.Context, command string, offset int64, body []byte) (*uploadStatus, error) {
+	ac := u.apiClient
+	patchedHTTPOptions, err := patchHTTPOptions(ac.clientConfig.HTTPOptions, *u.httpOptions)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upload request: %w", err)
+	}
+	req.Header = patchedHTTPOptions.Headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+	if ac.clientConfig.APIKey != "" {
+		req.Header.Set("x-goog-api-key", ac.clientConfig.APIKey)
+	}
+	req.Header.Set("X-Goog-Upload-Command", command)
+	// The body is never compressed with RequestOptions.Compression: the
+	// server would store it compressed, and the offsets count the bytes of
+	// the content.
+	if offset >= 0 {
+		req.Header.Set("X-Goog-Upload-Offset", strconv.FormatInt(offset, 10))
+	}
+	req.ContentLength = int64(len(body))
+	resp, err := doRequest(ac, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if !httpStatusOk(resp) {
+		return nil, ac.withBackend(newAPIError(resp))
+	}
+	status := &uploadStatus{state: resp.Header.Get("X-Goog-Upload-Status")}
+	if status.state == "" {
+		return nil, errors.New("the upload response has no upload status")
+	}
+	if received := resp.Header.Get("X-Goog-Upload-Size-Received"); received != "" {
+		if status.received, err = strconv.ParseInt(received, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid upload size received %q: %w", received, err)
+		}
+	}
+	if status.state == "final" {
+		var response struct {
+			File *File `json:"file"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to parse the upload response: %w", err)
+		}
+		status.file = response.File
+	}
+	return status, nil
+}
+
+// startUpload starts a resumable upload as Files.Upload does and returns its
+// upload URL and the HTTP options of the requests that upload the content.
+// Unlike Files.Upload, it wraps the error of the create request, so that
+// whether it is worth retrying can be told.
+func (m Files) startUpload(ctx context.Context, config *UploadFileConfig) (string, *HTTPOptions, error) {
+	var fileToUpload File
+	if config != nil {
+		fileToUpload.MIMEType = config.MIMEType
+		fileToUpload.Name = config.Name
+		fileToUpload.DisplayName = config.DisplayName
+	}
+	if fileToUpload.Name != "" && !strings.HasPrefix(fileToUpload.Name, "files/") {
+		fileToUpload.Name = "files/" + fileToUpload.Name
+	}
+
+	httpOptions := HTTPOptions{Headers: http.Header{}}
+	if config != nil && config.HTTPOptions != nil {
+		deepCopy(*config.HTTPOptions, &httpOptions)
+	}
+	if httpOptions.Headers == nil {
+		httpOptions.Headers = http.Header{}
+	}
+	httpOptions.APIVersion = ""
+	httpOptions.Headers.Add("Content-Type", "application/json")
+	httpOptions.Headers.Add("X-Goog-Upload-Protocol", "resumable")
+	httpOptions.Headers.Add("X-Goog-Upload-Command", "start")
+	httpOptions.Headers.Add("X-Goog-Upload-Header-Content-Type", fileToUpload.MIMEType)
+
+	resp, err := m.create(ctx, &fileToUpload, &CreateFileConfig{HTTPOptions: &httpOptions, ShouldReturnHTTPResponse: true})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create file: %w", err)
+	}
+	if resp.SDKHTTPResponse == nil || resp.SDKHTTPResponse.Headers == nil {
+		return "", nil, errors.New("failed to create file: upload URL was not returned from the create file request")
+	}
+	uploadURL := resp.SDKHTTPResponse.Headers.Get("X-Goog-Upload-Url")
+	if uploadURL == "" {
+		return "", nil, errors.New("failed to create file: upload URL was not returned from the create file request")
+	}
+	return uploadURL, &httpOptions, nil
+}
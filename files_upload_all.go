@@ -0,0 +1,209 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const defaultUploadAllConcurrency = 4
+
+// UploadSpec describes a file to upload with Files.UploadAll. Either Path or
+// Reader is required.
+type UploadSpec struct {
+	// The path of the file to upload, as with Files.UploadFromPath.
+	Path string
+	// The content to upload, as with Files.Upload. The upload of a reader that
+	// is not an io.Seeker is not retried, since its content cannot be read
+	// again.
+	Reader io.Reader
+	// Optional. The config of the upload.
+	Config *UploadFileConfig
+}
+
+// UploadAllConfig configures Files.UploadAll.
+type UploadAllConfig struct {
+	// Optional. The maximum number of files uploaded at a time. Defaults to 4.
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+	// Optional. The maximum number of attempts for a file whose upload failed
+	// with a network error or a retryable status such as 429 or 503. Overrides
+	// RetryOptions.MaxAttempts. Defaults to 3.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// Optional. The delay before the first retry of a file. Further retries
+	// back off as configured by RequestOptions.RetryOptions or
+	// ClientConfig.RetryOptions, with jitter, and wait at least as long as a
+	// Retry-After response header asks. Overrides RetryOptions.InitialInterval.
+	// Defaults to 1s.
+	RetryDelay time.Duration `json:"retryDelay,omitempty"`
+	// Optional. Called when the upload of a file succeeded or failed, e.g. to
+	// report the progress. It may be called concurrently.
+	OnResult func(UploadResult) `json:"-"`
+}
+
+// UploadResult is the result of the upload of a file by Files.UploadAll.
+type UploadResult struct {
+	// The index of the UploadSpec of the file.
+	Index int
+	// The uploaded file, if the upload succeeded.
+	File *File
+	// The error of the upload, if it failed.
+	Err error
+}
+
+// UploadAll uploads the files of specs concurrently and returns a result for
+// every spec, in the order of specs. A file that fails to upload does not stop
+// the others; its error is in its result. When ctx is done, no more uploads
+// are started, the files that were not uploaded get the error of ctx, and
+// UploadAll returns the results with the error of ctx.
+func (m Files) UploadAll(ctx context.Context, specs []UploadSpec, config *UploadAllConfig) ([]UploadResult, error) {
+	if m.apiClient.clientConfig.Backend == BackendVertexAI {
+		return nil, fmt.Errorf("method UploadAll is only supported in the Gemini Developer client. You can choose to use Gemini Developer client by setting ClientConfig.Backend to BackendGeminiAPI.")
+	}
+	if config == nil {
+		config = &UploadAllConfig{}
+	}
+	concurrency := config.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = defaultUploadAllConcurrency
+	}
+	results := make([]UploadResult, len(specs))
+	started := make([]bool, len(specs))
+	forEachConcurrently(ctx, len(specs), concurrency, func(i int) {
+		started[i] = true
+		file, err := m.uploadWithRetry(ctx, specs[i], config)
+		results[i] = UploadResult{Index: i, File: file, Err: err}
+		if config.OnResult != nil {
+			config.OnResult(results[i])
+		}
+	})
+	for i := range results {
+		if !started[i] {
+			results[i] = UploadResult{Index: i, Err: ctx.Err()}
+		}
+	}
+	return results, ctx.Err()
+}
+
+// uploadWithRetry uploads the file of spec, retrying transient errors.
+func (m Files) uploadWithRetry(ctx context.Context, spec UploadSpec, config *UploadAllConfig) (*File, error) {
+	retry := m.apiClient.uploadRetryOptions(ctx, config.MaxAttempts, config.RetryDelay)
+	maxAttempts := retry.maxAttempts()
+	var seeker io.Seeker
+	var start int64
+	if spec.Reader != nil {
+		var ok bool
+		if seeker, ok = spec.Reader.(io.Seeker); ok {
+			var err error
+			if start, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+				seeker = nil
+			}
+		}
+		if seeker == nil {
+			maxAttempts = 1
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		file, retryable, err := m.uploadSpec(ctx, spec)
+		if err == nil || !retryable || attempt >= maxAttempts {
+			return file, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retry.errorDelay(attempt, err)):
+		}
+		if seeker != nil {
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("failed to rewind the content to retry its upload: %w", err)
+			}
+		}
+	}
+}
+
+// uploadSpec uploads the file of spec once, and reports whether an error is
+// worth retrying.
+func (m Files) uploadSpec(ctx context.Context, spec UploadSpec) (*File, bool, error) {
+	switch {
+	case spec.Path != "" && spec.Reader != nil:
+		return nil, false, errors.New("only one of Path and Reader can be set")
+	case spec.Path != "":
+		f, config, err := openUploadFile(spec.Path, spec.Config)
+		if err != nil {
+			return nil, false, err
+		}
+		defer f.Close()
+		file, err := m.upload(ctx, f, config)
+		return file, isTransientError(err), err
+	case spec.Reader != nil:
+		file, err := m.upload(ctx, spec.Reader, spec.Config)
+		return file, isTransientError(err), err
+	default:
+		return nil, false, errors.New("either Path or Reader is required")
+	}
+}
+
+// upload uploads the content of r as Files.Upload does, but with the errors of
+// startUpload.
+func (m Files) upload(ctx context.Context, r io.Reader, config *UploadFileConfig) (*File, error) {
+	uploadURL, httpOptions, err := m.startUpload(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return m.apiClient.uploadFile(ctx, r, uploadURL, httpOptions)
+}
+
+// openUploadFile opens the file at path and returns it with the config of its
+// upload, which has the MIME type, size and name of the file as with
+// Files.UploadFromPath.
+func openUploadFile(path string, config *UploadFileConfig) (*os.File, *UploadFileConfig, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil || fileInfo.IsDir() {
+		return nil, nil, fmt.Errorf("%s is not a valid file path", path)
+	}
+	if config == nil {
+		config = &UploadFileConfig{}
+	}
+	var copiedCfg UploadFileConfig
+	deepCopy(*config, &copiedCfg)
+	if copiedCfg.MIMEType == "" {
+		copiedCfg.MIMEType = mime.TypeByExtension(filepath.Ext(path))
+		if copiedCfg.MIMEType == "" {
+			return nil, nil, fmt.Errorf("could not determine the MIME type of %s, set UploadFileConfig.MIMEType", path)
+		}
+	}
+	if copiedCfg.HTTPOptions == nil {
+		copiedCfg.HTTPOptions = &HTTPOptions{}
+	}
+	if copiedCfg.HTTPOptions.Headers == nil {
+		copiedCfg.HTTPOptions.Headers = http.Header{}
+	}
+	copiedCfg.HTTPOptions.Headers.Add("X-Goog-Upload-Header-Content-Length", strconv.FormatInt(fileInfo.Size(), 10))
+	copiedCfg.HTTPOptions.Headers.Add("X-Goog-Upload-File-Name", filepath.Base(path))
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, &copiedCfg, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFilesUploadAll(t *testing.T) {
+	ctx := context.Background()
+	s := NewMockUploadServer(t)
+	var mu sync.Mutex
+	// The number of times that the upload of a file with the display name
+	// fails before it succeeds.
+	failures := map[string]int{"retried": 1, "not seekable": 1}
+	s.createHandler = func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		var req struct {
+			File File `json:"file"`
+		}
+		json.Unmarshal(body, &req)
+		mu.Lock()
+		fail := failures[req.File.DisplayName] > 0
+		failures[req.File.DisplayName]--
+		mu.Unlock()
+		if fail {
+			http.Error(w, `{"error": {"code": 503, "message": "unavailable", "status": "UNAVAILABLE"}}`, http.StatusServiceUnavailable)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		s.handleCreate(w, r)
+	}
+	client := newTestClient(t, nil, s.ServeHTTP)
+	s.baseURL = client.clientConfig.HTTPOptions.BaseURL
+	path := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(path, []byte("notes"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	specs := []UploadSpec{
+		{Path: path, Config: &UploadFileConfig{Name: "notes", DisplayName: "path"}},
+		{Reader: strings.NewReader("retried"), Config: &UploadFileConfig{Name: "retried", DisplayName: "retried", MIMEType: "text/plain"}},
+		{Reader: io.MultiReader(strings.NewReader("once")), Config: &UploadFileConfig{DisplayName: "not seekable", MIMEType: "text/plain"}},
+		{},
+	}
+	var reported []int
+	results, err := client.Files.UploadAll(ctx, specs, &UploadAllConfig{
+		MaxConcurrency: 2,
+		RetryDelay:     time.Millisecond,
+		OnResult: func(r UploadResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, r.Index)
+		},
+	})
+	if err != nil {
+		t.Fatalf("UploadAll() failed: %v", err)
+	}
+	if len(results) != len(specs) || len(reported) != len(specs) {
+		t.Fatalf("UploadAll() returned %d results and reported %d, want %d", len(results), len(reported), len(specs))
+	}
+	for i, want := range []string{"files/notes", "files/retried"} {
+		if results[i].Err != nil || results[i].Index != i || results[i].File.Name != want {
+			t.Errorf("results[%d] = %+v, want file %s", i, results[i], want)
+		}
+	}
+	if got := *results[0].File.SizeBytes; got != int64(len("notes")) {
+		t.Errorf("uploaded %d bytes of the file, want %d", got, len("notes"))
+	}
+	if !IsUnavailable(results[2].Err) {
+		t.Errorf("results[2].Err = %v, want the unavailable error of a reader that is not retried", results[2].Err)
+	}
+	if results[3].Err == nil {
+		t.Errorf("results[3].Err = nil, want an error for a spec without Path and Reader")
+	}
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		results, err := client.Files.UploadAll(ctx, specs[:2], nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("UploadAll() = %v, want context.Canceled", err)
+		}
+		for _, r := range results {
+			if !errors.Is(r.Err, context.Canceled) {
+				t.Errorf("results[%d].Err = %v, want context.Canceled", r.Index, r.Err)
+			}
+		}
+	})
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newFailingUploadServer returns a MockUploadServer that records the uploaded
+// content and answers query commands. The upload of the chunk at failOffset
+// fails failures times with a 503 after half of the chunk was received.
+func newFailingUploadServer(t *testing.T, content *bytes.Buffer, failOffset int64, failures int) *MockUploadServer {
+	s := NewMockUploadServer(t)
+	s.uploadHandler = func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		session, ok := s.uploads[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		command := r.Header.Get("X-Goog-Upload-Command")
+		if command == "query" {
+			w.Header().Set("X-Goog-Upload-Status", "active")
+			w.Header().Set("X-Goog-Upload-Size-Received", strconv.FormatInt(session.receivedSize, 10))
+			return
+		}
+		offset, err := strconv.ParseInt(r.Header.Get("X-Goog-Upload-Offset"), 10, 64)
+		if err != nil || offset != session.receivedSize {
+			t.Errorf("upload at offset %q, want %d", r.Header.Get("X-Goog-Upload-Offset"), session.receivedSize)
+			http.Error(w, "Conflict", http.StatusConflict)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if offset == failOffset && failures > 0 {
+			failures--
+			content.Write(body[:len(body)/2])
+			session.receivedSize += int64(len(body) / 2)
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		content.Write(body)
+		session.receivedSize += int64(len(body))
+		if !strings.Contains(command, "finalize") {
+			w.Header().Set("X-Goog-Upload-Status", "active")
+			return
+		}
+		w.Header().Set("X-Goog-Upload-Status", "final")
+		fmt.Fprintf(w, `{"file": {"name": "files/video", "mimeType": %q, "sizeBytes": "%d", "state": "ACTIVE"}}`,
+			r.Header.Get("X-Goog-Upload-Header-Content-Type"), session.receivedSize)
+	}
+	return s
+}
+
+func testUploadData() []byte {
+	data := make([]byte, 600*1024)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestFilesUploadFromReaderRetriesChunks(t *testing.T) {
+	ctx := context.Background()
+	data := testUploadData()
+	var content bytes.Buffer
+	s := newFailingUploadServer(t, &content, uploadChunkGranularity, 2)
+	client := newTestClient(t, nil, s.ServeHTTP)
+	s.baseURL = client.clientConfig.HTTPOptions.BaseURL
+
+	var progress []UploadProgress
+	file, err := client.Files.UploadFromReader(ctx, bytes.NewReader(data), int64(len(data)), &UploadFromReaderConfig{
+		MIMEType:   "video/mp4",
+		ChunkSize:  1,
+		RetryDelay: time.Millisecond,
+		OnProgress: func(p UploadProgress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatalf("UploadFromReader() failed: %v", err)
+	}
+	want := &File{Name: "files/video", MIMEType: "video/mp4", SizeBytes: Ptr(int64(len(data))), State: FileStateActive}
+	if diff := cmp.Diff(want, file); diff != "" {
+		t.Errorf("UploadFromReader() mismatch (-want +got):\n%s", diff)
+	}
+	if !bytes.Equal(content.Bytes(), data) {
+		t.Errorf("uploaded content differs from the data")
+	}
+	total := int64(len(data))
+	wantProgress := []UploadProgress{{uploadChunkGranularity, total}, {2 * uploadChunkGranularity, total}, {total, total}}
+	if diff := cmp.Diff(wantProgress, progress); diff != "" {
+		t.Errorf("progress mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFilesUploadFromReaderResume(t *testing.T) {
+	ctx := context.Background()
+	data := testUploadData()
+	var content bytes.Buffer
+	s := newFailingUploadServer(t, &content, uploadChunkGranularity, 1)
+	client := newTestClient(t, nil, s.ServeHTTP)
+	s.baseURL = client.clientConfig.HTTPOptions.BaseURL
+
+	config := &UploadFromReaderConfig{MIMEType: "video/mp4", ChunkSize: uploadChunkGranularity, MaxAttempts: 1}
+	_, err := client.Files.UploadFromReader(ctx, bytes.NewReader(data), int64(len(data)), config)
+	var interrupted *UploadInterruptedError
+	if !errors.As(err, &interrupted) {
+		t.Fatalf("UploadFromReader() = %v, want an UploadInterruptedError", err)
+	}
+	if interrupted.Offset != uploadChunkGranularity || interrupted.UploadURL == "" {
+		t.Errorf("UploadInterruptedError = %+v, want offset %d and the upload URL", interrupted, uploadChunkGranularity)
+	}
+	if !IsUnavailable(err) {
+		t.Errorf("IsUnavailable(%v) = false, want true", err)
+	}
+
+	// Resume from a reader that cannot seek, e.g. after a restart.
+	config.UploadURL = interrupted.UploadURL
+	var progress []UploadProgress
+	config.OnProgress = func(p UploadProgress) { progress = append(progress, p) }
+	file, err := client.Files.UploadFromReader(ctx, io.MultiReader(bytes.NewReader(data)), int64(len(data)), config)
+	if err != nil {
+		t.Fatalf("UploadFromReader() resuming the upload failed: %v", err)
+	}
+	if file.Name != "files/video" {
+		t.Errorf("UploadFromReader() returned file %q, want files/video", file.Name)
+	}
+	if !bytes.Equal(content.Bytes(), data) {
+		t.Errorf("uploaded content differs from the data")
+	}
+	total := int64(len(data))
+	wantProgress := []UploadProgress{{uploadChunkGranularity * 3 / 2, total}, {total, total}}
+	if diff := cmp.Diff(wantProgress, progress); diff != "" {
+		t.Errorf("progress mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// FileProcessingError is returned by Files.WaitForActive for a file whose
+// processing failed.
+type FileProcessingError struct {
+	// File is the failed file.
+	File *File
+}
+
+// Error returns a string representation of the FileProcessingError.
+func (e *FileProcessingError) Error() string {
+	if e.File.Error == nil || e.File.Error.Message == "" {
+		return fmt.Sprintf("processing of file %s failed", e.File.Name)
+	}
+	return fmt.Sprintf("processing of file %s failed: %s", e.File.Name, e.File.Error.Message)
+}
+
+// FileWaitTimeoutError is returned by Files.WaitForActive when the file did
+// not become active within WaitConfig.Timeout. It wraps
+// context.DeadlineExceeded.
+type FileWaitTimeoutError struct {
+	// Name is the name of the file waited for.
+	Name string
+	// Timeout is the exceeded WaitConfig.Timeout.
+	Timeout time.Duration
+	// Last is the last polled file, or nil if none was polled.
+	Last *File
+}
+
+// Error returns a string representation of the FileWaitTimeoutError.
+func (e *FileWaitTimeoutError) Error() string {
+	state := FileState("unknown")
+	if e.Last != nil {
+		state = e.Last.State
+	}
+	return fmt.Sprintf("file %s did not become active within %v, last state %q", e.Name, e.Timeout, state)
+}
+
+// Unwrap returns context.DeadlineExceeded.
+func (e *FileWaitTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// WaitForActive polls the file name, e.g. an uploaded video, until it is
+// processed and returns it once its state is ACTIVE, so that it can be used in
+// a request. The delays between polls are those of Interactions.Wait. If the
+// processing failed, it returns the file with a *FileProcessingError that has
+// the reason.
+//
+// If ctx is done first, WaitForActive returns ctx.Err(). If config.Timeout is
+// exceeded first, it returns a *FileWaitTimeoutError.
+func (m Files) WaitForActive(ctx context.Context, name string, config *WaitConfig) (*File, error) {
+	if config == nil {
+		config = &WaitConfig{}
+	}
+	get := func(ctx context.Context) (*File, error) {
+		file, err := m.Get(ctx, name, &GetFileConfig{HTTPOptions: config.HTTPOptions})
+		if err == nil && config.OnFileUpdate != nil {
+			config.OnFileUpdate(file)
+		}
+		return file, err
+	}
+	done := func(file *File) bool {
+		return file.State == FileStateActive || file.State == FileStateFailed
+	}
+	file, timedOut, err := poll(ctx, config, get, done)
+	if timedOut {
+		return nil, &FileWaitTimeoutError{Name: name, Timeout: config.Timeout, Last: file}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if file.State == FileStateFailed {
+		return file, &FileProcessingError{File: file}
+	}
+	return file, nil
+}
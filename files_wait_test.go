@@ -0,0 +1,72 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFilesWaitForActive(t *testing.T) {
+	ctx := context.Background()
+	polls := map[string]int{}
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/v1beta/")
+		polls[name]++
+		switch {
+		case polls[name] < 3 || name == "files/slow":
+			fmt.Fprintf(w, `{"name": %q, "state": "PROCESSING"}`, name)
+		case name == "files/broken":
+			fmt.Fprintf(w, `{"name": %q, "state": "FAILED", "error": {"code": 3, "message": "unsupported codec"}}`, name)
+		default:
+			fmt.Fprintf(w, `{"name": %q, "state": "ACTIVE"}`, name)
+		}
+	})
+
+	var states []FileState
+	file, err := client.Files.WaitForActive(ctx, "files/video", &WaitConfig{
+		InitialInterval: time.Millisecond,
+		OnFileUpdate:    func(f *File) { states = append(states, f.State) },
+	})
+	if err != nil {
+		t.Fatalf("WaitForActive() failed: %v", err)
+	}
+	if file.State != FileStateActive || len(states) != 3 {
+		t.Errorf("WaitForActive() = %+v after %d polls, want ACTIVE after 3 polls", file, len(states))
+	}
+
+	file, err = client.Files.WaitForActive(ctx, "broken", &WaitConfig{InitialInterval: time.Millisecond})
+	var processingErr *FileProcessingError
+	if !errors.As(err, &processingErr) || file == nil || file.State != FileStateFailed {
+		t.Fatalf("WaitForActive() = %+v, %v, want the failed file with a *FileProcessingError", file, err)
+	}
+	if !strings.Contains(err.Error(), "unsupported codec") {
+		t.Errorf("WaitForActive() error = %q, want the failure reason", err)
+	}
+
+	_, err = client.Files.WaitForActive(ctx, "files/slow", &WaitConfig{InitialInterval: time.Millisecond, Timeout: 20 * time.Millisecond})
+	var timeoutErr *FileWaitTimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForActive() = %v, want a *FileWaitTimeoutError", err)
+	}
+	if timeoutErr.Last == nil || timeoutErr.Last.State != FileStateProcessing {
+		t.Errorf("FileWaitTimeoutError = %+v, want the last polled file", timeoutErr)
+	}
+}
@@ -0,0 +1,398 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const defaultFunctionMaxRounds = 10
+
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	errorType   = reflect.TypeFor[error]()
+)
+
+// FunctionRegistry holds Go functions that the model can call, and dispatches
+// the function calls of the model to them. The declarations of the functions
+// are derived from their signatures with SchemaFromType, so that the arguments
+// and results are typed Go values:
+//
+//	type WeatherArgs struct {
+//		City string `json:"city" jsonschema:"description=The city, e.g. Paris"`
+//	}
+//
+//	type Weather struct {
+//		Celsius float64 `json:"celsius"`
+//	}
+//
+//	functions := genai.NewFunctionRegistry()
+//	err := functions.Register("get_weather", "Returns the current weather of a city.",
+//		func(ctx context.Context, args WeatherArgs) (*Weather, error) { ... })
+//
+// The functions are declared to the model with Tool for Models and Chats, and
+// with InteractionTools for Interactions. GenerateContent and RunInteraction
+// answer the function calls of the model until it responds without calling a
+// registered function, running up to MaxConcurrency calls of a response
+// concurrently. Register must not be called concurrently with the other
+// methods.
+type FunctionRegistry struct {
+	functions map[string]*registeredFunction
+	// names are the names of the functions in the order they were registered.
+	names []string
+	// MaxRounds is the maximum number of requests that GenerateContent and
+	// RunInteraction send to answer function calls. Defaults to 10.
+	MaxRounds int
+	// MaxConcurrency is the maximum number of function calls that DispatchAll
+	// and DispatchInteraction run concurrently. Defaults to 1, which runs the
+	// calls one after the other.
+	MaxConcurrency int
+	// CallTimeout is the timeout of each function call, after which the call
+	// is reported to the model as failed. The context of the function is done
+	// then. Zero means no timeout.
+	CallTimeout time.Duration
+	// Policy restricts what the functions may do, e.g. the paths and hosts in
+	// their arguments, for every call that the registry dispatches, including
+	// the automatic function calling of a Chat. A call that violates it is
+	// reported to the model as failed with a *ToolPolicyError. Nil means no
+	// restrictions.
+	Policy *ToolPolicy
+}
+
+type registeredFunction struct {
+	declaration *FunctionDeclaration
+	fn          reflect.Value
+	args        reflect.Type
+}
+
+// NewFunctionRegistry returns an empty FunctionRegistry.
+func NewFunctionRegistry() *FunctionRegistry {
+	return &FunctionRegistry{functions: map[string]*registeredFunction{}}
+}
+
+// Register adds fn as the function name with the given description. fn must
+// be a func(context.Context, Args) (Result, error), where Args is a struct, or
+// a pointer to a struct, into which the arguments of the model are decoded, and
+// Result is sent back to the model as the output of the call. The Parameters of
+// the declaration are the Schema of Args, and its Response is the Schema of
+// Result unless Result has no Schema, e.g. if it is a map.
+func (r *FunctionRegistry) Register(name, description string, fn any) error {
+	if name == "" {
+		return errors.New("function name is required")
+	}
+	if _, ok := r.functions[name]; ok {
+		return fmt.Errorf("function %q is already registered", name)
+	}
+	v := reflect.ValueOf(fn)
+	t := v.Type()
+	if v.Kind() != reflect.Func || t.NumIn() != 2 || t.NumOut() != 2 || t.In(0) != contextType || t.Out(1) != errorType || t.IsVariadic() {
+		return fmt.Errorf("function %q must be a func(context.Context, Args) (Result, error), got %T", name, fn)
+	}
+	args := t.In(1)
+	if a := args; a.Kind() != reflect.Struct && (a.Kind() != reflect.Pointer || a.Elem().Kind() != reflect.Struct) {
+		return fmt.Errorf("function %q: the arguments must be a struct or a pointer to a struct, got %v", name, args)
+	}
+	parameters, err := SchemaFromType(args)
+	if err != nil {
+		return fmt.Errorf("function %q: %w", name, err)
+	}
+	declaration := &FunctionDeclaration{Name: name, Description: description, Parameters: parameters}
+	if response, err := SchemaFromType(t.Out(0)); err == nil {
+		declaration.Response = response
+	}
+	r.functions[name] = &registeredFunction{declaration: declaration, fn: v, args: args}
+	r.names = append(r.names, name)
+	return nil
+}
+
+// Has reports whether the function name is registered.
+func (r *FunctionRegistry) Has(name string) bool {
+	_, ok := r.functions[name]
+	return ok
+}
+
+// Declarations returns the declarations of the functions, in the order they
+// were registered.
+func (r *FunctionRegistry) Declarations() []*FunctionDeclaration {
+	declarations := make([]*FunctionDeclaration, len(r.names))
+	for i, name := range r.names {
+		declarations[i] = r.functions[name].declaration
+	}
+	return declarations
+}
+
+// Tool returns the Tool that declares the functions, to be added to the Tools
+// of a GenerateContentConfig.
+func (r *FunctionRegistry) Tool() *Tool {
+	return &Tool{FunctionDeclarations: r.Declarations()}
+}
+
+// Dispatch calls the function of fc with its arguments decoded into the
+// arguments type of the function, and returns the FunctionResponse for fc. The
+// response has an "output" key with the result of the function, or an "error"
+// key if the arguments cannot be decoded, the function fails or the call
+// violates the Policy, so that the model can recover. It returns an error if the function is not registered or
+// ctx is done.
+func (r *FunctionRegistry) Dispatch(ctx context.Context, fc *FunctionCall) (*FunctionResponse, error) {
+	if fc == nil {
+		return nil, errors.New("function call is required")
+	}
+	f, ok := r.functions[fc.Name]
+	if !ok {
+		return nil, fmt.Errorf("function %q is not registered", fc.Name)
+	}
+	result, err := r.Policy.Run(ctx, fc.Name, fc.Args, f.call)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	response := &FunctionResponse{ID: fc.ID, Name: fc.Name}
+	if err != nil {
+		response.Response = map[string]any{"error": err.Error()}
+	} else {
+		response.Response = map[string]any{"output": result}
+	}
+	return response, nil
+}
+
+// DispatchAll dispatches calls with Dispatch, running up to MaxConcurrency of
+// them concurrently, and returns the parts with their function responses in
+// the order of the calls, to be sent back to the model as the next user turn,
+// e.g. with Chat.Send. A call that fails, times out or is for a function that
+// is not registered gets a response with an "error" key, so that the model
+// can use the results of the other calls. It returns an error only if ctx is
+// done.
+func (r *FunctionRegistry) DispatchAll(ctx context.Context, calls []*FunctionCall) ([]*Part, error) {
+	parts := make([]*Part, len(calls))
+	r.forEach(ctx, len(calls), func(callCtx context.Context, i int) {
+		response, err := r.Dispatch(callCtx, calls[i])
+		if err != nil {
+			response = &FunctionResponse{Response: map[string]any{"error": err.Error()}}
+			if call := calls[i]; call != nil {
+				response.ID, response.Name = call.ID, call.Name
+			}
+		}
+		parts[i] = &Part{FunctionResponse: response}
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+// forEach calls f for 0 to n-1 with up to MaxConcurrency calls at a time, each
+// with its own context that is done after CallTimeout. It stops starting calls
+// when ctx is done, and returns when the started calls have returned.
+func (r *FunctionRegistry) forEach(ctx context.Context, n int, f func(ctx context.Context, i int)) {
+	forEachConcurrently(ctx, n, r.MaxConcurrency, func(i int) {
+		callCtx, cancel := context.WithCancel(ctx)
+		if r.CallTimeout > 0 {
+			callCtx, cancel = context.WithTimeout(ctx, r.CallTimeout)
+		}
+		defer cancel()
+		f(callCtx, i)
+	})
+}
+
+// call decodes args into the arguments type of the function and calls it.
+func (f *registeredFunction) call(ctx context.Context, args map[string]any) (any, error) {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	ptr := reflect.New(f.args)
+	if len(args) > 0 {
+		if err := json.Unmarshal(data, ptr.Interface()); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+	if f.args.Kind() == reflect.Pointer && ptr.Elem().IsNil() {
+		ptr.Elem().Set(reflect.New(f.args.Elem()))
+	}
+	out := f.fn.Call([]reflect.Value{reflect.ValueOf(ctx), ptr.Elem()})
+	if err, _ := out[1].Interface().(error); err != nil {
+		return nil, err
+	}
+	return out[0].Interface(), nil
+}
+
+// GenerateContent generates content with the functions added to the tools of
+// config, and answers the function calls of the model with DispatchAll until
+// the model responds without function calls, or calls a function that is not
+// registered. It returns that last response; the function calls and responses
+// before it are not part of contents.
+func (r *FunctionRegistry) GenerateContent(ctx context.Context, models *Models, model string, contents []*Content, config *GenerateContentConfig) (*GenerateContentResponse, error) {
+	var withTools GenerateContentConfig
+	if config != nil {
+		withTools = *config
+	}
+	withTools.Tools = append(append([]*Tool(nil), withTools.Tools...), r.Tool())
+	contents = append([]*Content(nil), contents...)
+	for round := 0; ; round++ {
+		response, err := models.GenerateContent(ctx, model, contents, &withTools)
+		if err != nil {
+			return nil, err
+		}
+		calls := response.FunctionCalls()
+		if len(calls) == 0 || !r.hasAll(calls) {
+			return response, nil
+		}
+		if round+1 >= r.maxRounds() {
+			return response, fmt.Errorf("the model still called functions after %d requests", r.maxRounds())
+		}
+		parts, err := r.DispatchAll(ctx, calls)
+		if err != nil {
+			return nil, err
+		}
+		contents = append(contents, response.Candidates[0].Content, &Content{Role: RoleUser, Parts: parts})
+	}
+}
+
+func (r *FunctionRegistry) hasAll(calls []*FunctionCall) bool {
+	for _, call := range calls {
+		if !r.Has(call.Name) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *FunctionRegistry) maxRounds() int {
+	if r.MaxRounds <= 0 {
+		return defaultFunctionMaxRounds
+	}
+	return r.MaxRounds
+}
+
+// InteractionTools returns the function tools of the functions, to be added to
+// the Tools of an interaction.
+func (r *FunctionRegistry) InteractionTools() []*InteractionTool {
+	tools := make([]*InteractionTool, len(r.names))
+	for i, name := range r.names {
+		d := r.functions[name].declaration
+		tools[i] = &InteractionTool{Type: "function", Name: d.Name, Description: d.Description, Parameters: d.Parameters.jsonSchema()}
+	}
+	return tools
+}
+
+// DispatchInteraction calls the function of every function call in outputs
+// that is for a registered function, running up to MaxConcurrency of them
+// concurrently, and returns the function results in the order of the calls,
+// like MCPToolset.Dispatch. Calls of other functions are skipped; calls that
+// fail, time out or violate the Policy are reported with NewInteractionFunctionError. It returns
+// an error only if ctx is done.
+func (r *FunctionRegistry) DispatchInteraction(ctx context.Context, outputs []*InteractionContent) ([]*InteractionContent, error) {
+	var calls []*InteractionFunctionCall
+	for _, out := range outputs {
+		if call, ok := out.AsFunctionCall(); ok && r.Has(call.Name) {
+			calls = append(calls, call)
+		}
+	}
+	results := make([]*InteractionContent, len(calls))
+	r.forEach(ctx, len(calls), func(callCtx context.Context, i int) {
+		call := calls[i]
+		result, err := r.Policy.Run(callCtx, call.Name, call.Args, r.functions[call.Name].call)
+		if err == nil {
+			err = callCtx.Err()
+		}
+		if err != nil {
+			results[i] = NewInteractionFunctionError(call.ID, call.Name, err)
+		} else {
+			results[i] = NewInteractionFunctionResult(call.ID, call.Name, result)
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// RunInteraction creates interaction with the functions added to its tools,
+// answers the function calls of the model with DispatchInteraction, and returns
+// the first interaction without calls of registered functions, like
+// MCPToolset.Run.
+func (r *FunctionRegistry) RunInteraction(ctx context.Context, interactions *Interactions, interaction *Interaction, config *CreateInteractionConfig) (*Interaction, error) {
+	if interaction == nil {
+		return nil, errors.New("interaction is required")
+	}
+	withTools := *interaction
+	withTools.Tools = append(append([]*InteractionTool(nil), interaction.Tools...), r.InteractionTools()...)
+	session := interactions.NewSession(&withTools, config)
+	var input any = interaction.Input
+	for round := 0; ; round++ {
+		response, err := session.Send(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		results, err := r.DispatchInteraction(ctx, response.Outputs)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			return response, nil
+		}
+		if round+1 >= r.maxRounds() {
+			return response, fmt.Errorf("the model still called functions after %d interactions", r.maxRounds())
+		}
+		input = results
+	}
+}
+
+// jsonSchema returns the JSON schema that s represents, e.g. for the
+// Parameters of an InteractionTool.
+func (s *Schema) jsonSchema() map[string]any {
+	if s == nil {
+		return nil
+	}
+	schema := map[string]any{}
+	if s.Type != "" && s.Type != TypeUnspecified {
+		schema["type"] = strings.ToLower(string(s.Type))
+	}
+	if s.Description != "" {
+		schema["description"] = s.Description
+	}
+	if s.Format != "" {
+		schema["format"] = s.Format
+	}
+	if len(s.Enum) > 0 {
+		schema["enum"] = s.Enum
+	}
+	if s.Items != nil {
+		schema["items"] = s.Items.jsonSchema()
+	}
+	if s.Properties != nil {
+		properties := map[string]any{}
+		for name, p := range s.Properties {
+			properties[name] = p.jsonSchema()
+		}
+		schema["properties"] = properties
+	}
+	if len(s.Required) > 0 {
+		schema["required"] = s.Required
+	}
+	if len(s.AnyOf) > 0 {
+		anyOf := make([]any, len(s.AnyOf))
+		for i, a := range s.AnyOf {
+			anyOf[i] = a.jsonSchema()
+		}
+		schema["anyOf"] = anyOf
+	}
+	return schema
+}
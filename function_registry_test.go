@@ -0,0 +1,311 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testForecastArgs struct {
+	City string `json:"city" jsonschema:"description=The city"`
+	Days int    `json:"days,omitempty"`
+}
+
+type testForecast struct {
+	City    string  `json:"city"`
+	Celsius float64 `json:"celsius"`
+}
+
+func newTestFunctionRegistry(t *testing.T) *FunctionRegistry {
+	t.Helper()
+	r := NewFunctionRegistry()
+	if err := r.Register("get_forecast", "Returns the forecast of a city.", func(ctx context.Context, args testForecastArgs) (*testForecast, error) {
+		if args.City == "" {
+			return nil, errors.New("unknown city")
+		}
+		return &testForecast{City: args.City, Celsius: 21.5}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register("list_cities", "", func(ctx context.Context, args *struct{}) (map[string]int, error) {
+		return map[string]int{"Paris": 1}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestFunctionRegistryRegister(t *testing.T) {
+	r := newTestFunctionRegistry(t)
+	want := []*FunctionDeclaration{
+		{
+			Name:        "get_forecast",
+			Description: "Returns the forecast of a city.",
+			Parameters: &Schema{
+				Type: TypeObject,
+				Properties: map[string]*Schema{
+					"city": {Type: TypeString, Description: "The city"},
+					"days": {Type: TypeInteger},
+				},
+				Required:         []string{"city"},
+				PropertyOrdering: []string{"city", "days"},
+			},
+			Response: &Schema{
+				Type:             TypeObject,
+				Properties:       map[string]*Schema{"city": {Type: TypeString}, "celsius": {Type: TypeNumber}},
+				Required:         []string{"city", "celsius"},
+				PropertyOrdering: []string{"city", "celsius"},
+			},
+		},
+		{Name: "list_cities", Parameters: &Schema{Type: TypeObject, Properties: map[string]*Schema{}}},
+	}
+	if diff := cmp.Diff(want, r.Declarations()); diff != "" {
+		t.Errorf("Declarations() mismatch (-want +got):\n%s", diff)
+	}
+
+	tests := []struct {
+		name string
+		fn   any
+	}{
+		{name: "not a function", fn: "get_forecast"},
+		{name: "no context", fn: func(args testForecastArgs) (string, error) { return "", nil }},
+		{name: "no error", fn: func(ctx context.Context, args testForecastArgs) string { return "" }},
+		{name: "scalar arguments", fn: func(ctx context.Context, city string) (string, error) { return "", nil }},
+		{name: "map arguments", fn: func(ctx context.Context, args map[string]any) (string, error) { return "", nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Register("f", "", tt.fn); err == nil {
+				t.Errorf("Register() succeeded, want error")
+			}
+		})
+	}
+	if err := r.Register("get_forecast", "", func(ctx context.Context, args testForecastArgs) (string, error) { return "", nil }); err == nil {
+		t.Errorf("Register() of a duplicate name succeeded, want error")
+	}
+}
+
+func TestFunctionRegistryDispatch(t *testing.T) {
+	ctx := context.Background()
+	r := newTestFunctionRegistry(t)
+	tests := []struct {
+		name string
+		call *FunctionCall
+		want *FunctionResponse
+	}{
+		{
+			name: "output",
+			call: &FunctionCall{ID: "c1", Name: "get_forecast", Args: map[string]any{"city": "Paris"}},
+			want: &FunctionResponse{ID: "c1", Name: "get_forecast", Response: map[string]any{"output": &testForecast{City: "Paris", Celsius: 21.5}}},
+		},
+		{
+			name: "function error",
+			call: &FunctionCall{Name: "get_forecast"},
+			want: &FunctionResponse{Name: "get_forecast", Response: map[string]any{"error": "unknown city"}},
+		},
+		{
+			name: "invalid arguments",
+			call: &FunctionCall{Name: "get_forecast", Args: map[string]any{"city": 1}},
+			want: &FunctionResponse{Name: "get_forecast", Response: map[string]any{"error": "invalid arguments: json: cannot unmarshal number into Go struct field testForecastArgs.city of type string"}},
+		},
+		{
+			name: "pointer arguments",
+			call: &FunctionCall{Name: "list_cities"},
+			want: &FunctionResponse{Name: "list_cities", Response: map[string]any{"output": map[string]int{"Paris": 1}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Dispatch(ctx, tt.call)
+			if err != nil {
+				t.Fatalf("Dispatch() failed: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Dispatch() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+	if _, err := r.Dispatch(ctx, &FunctionCall{Name: "unknown"}); err == nil {
+		t.Errorf("Dispatch() of an unknown function succeeded, want error")
+	}
+}
+
+func TestFunctionRegistryDispatchAllConcurrently(t *testing.T) {
+	ctx := context.Background()
+	var running, maxRunning atomic.Int32
+	r := NewFunctionRegistry()
+	r.MaxConcurrency = 2
+	r.CallTimeout = 50 * time.Millisecond
+	if err := r.Register("sleep", "", func(ctx context.Context, args struct {
+		Millis int `json:"millis"`
+	}) (int, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for m := maxRunning.Load(); n > m && !maxRunning.CompareAndSwap(m, n); m = maxRunning.Load() {
+		}
+		select {
+		case <-time.After(time.Duration(args.Millis) * time.Millisecond):
+			return args.Millis, nil
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := []*FunctionCall{
+		{ID: "c1", Name: "sleep", Args: map[string]any{"millis": 20}},
+		{ID: "c2", Name: "sleep", Args: map[string]any{"millis": 1}},
+		{ID: "c3", Name: "sleep", Args: map[string]any{"millis": 1000}},
+		{ID: "c4", Name: "unknown"},
+		{ID: "c5", Name: "sleep", Args: map[string]any{"millis": 5}},
+	}
+	got, err := r.DispatchAll(ctx, calls)
+	if err != nil {
+		t.Fatalf("DispatchAll() failed: %v", err)
+	}
+	want := []*Part{
+		{FunctionResponse: &FunctionResponse{ID: "c1", Name: "sleep", Response: map[string]any{"output": 20}}},
+		{FunctionResponse: &FunctionResponse{ID: "c2", Name: "sleep", Response: map[string]any{"output": 1}}},
+		{FunctionResponse: &FunctionResponse{ID: "c3", Name: "sleep", Response: map[string]any{"error": "context deadline exceeded"}}},
+		{FunctionResponse: &FunctionResponse{ID: "c4", Name: "unknown", Response: map[string]any{"error": `function "unknown" is not registered`}}},
+		{FunctionResponse: &FunctionResponse{ID: "c5", Name: "sleep", Response: map[string]any{"output": 5}}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DispatchAll() mismatch (-want +got):\n%s", diff)
+	}
+	if m := maxRunning.Load(); m != 2 {
+		t.Errorf("at most %d calls ran concurrently, want 2", m)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := r.DispatchAll(canceled, calls); !errors.Is(err, context.Canceled) {
+		t.Errorf("DispatchAll() with a canceled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestFunctionRegistryGenerateContent(t *testing.T) {
+	ctx := context.Background()
+	var requests []map[string]any
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, req)
+		if len(requests) == 1 {
+			fmt.Fprint(w, `{"candidates": [{"content": {"role": "model", "parts": [
+				{"functionCall": {"id": "c1", "name": "get_forecast", "args": {"city": "Paris"}}},
+				{"functionCall": {"id": "c2", "name": "get_forecast", "args": {}}}]}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "21.5 degrees in Paris."}]}}]}`)
+	})
+
+	r := newTestFunctionRegistry(t)
+	config := &GenerateContentConfig{Temperature: Float32(0)}
+	got, err := r.GenerateContent(ctx, client.Models, "gemini-2.5-flash", Text("Weather in Paris?"), config)
+	if err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	if got.Text() != "21.5 degrees in Paris." {
+		t.Errorf("GenerateContent() text = %q, want the final answer", got.Text())
+	}
+	if len(config.Tools) != 0 {
+		t.Errorf("GenerateContent() modified the tools of config: %v", config.Tools)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	tools, _ := requests[0]["tools"].([]any)
+	if len(tools) != 1 {
+		t.Errorf("advertised tools = %v, want the registry tool", requests[0]["tools"])
+	}
+	wantContents := []any{
+		map[string]any{"role": "user", "parts": []any{map[string]any{"text": "Weather in Paris?"}}},
+		map[string]any{"role": "model", "parts": []any{
+			map[string]any{"functionCall": map[string]any{"id": "c1", "name": "get_forecast", "args": map[string]any{"city": "Paris"}}},
+			map[string]any{"functionCall": map[string]any{"id": "c2", "name": "get_forecast"}},
+		}},
+		map[string]any{"role": "user", "parts": []any{
+			map[string]any{"functionResponse": map[string]any{"id": "c1", "name": "get_forecast", "response": map[string]any{"output": map[string]any{"city": "Paris", "celsius": 21.5}}}},
+			map[string]any{"functionResponse": map[string]any{"id": "c2", "name": "get_forecast", "response": map[string]any{"error": "unknown city"}}},
+		}},
+	}
+	if diff := cmp.Diff(wantContents, requests[1]["contents"]); diff != "" {
+		t.Errorf("second request contents mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFunctionRegistryRunInteraction(t *testing.T) {
+	ctx := context.Background()
+	var requests []map[string]any
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, req)
+		if len(requests) == 1 {
+			fmt.Fprint(w, `{"id": "turn-1", "status": "requires_action", "outputs": [
+				{"type": "function_call", "id": "c1", "name": "get_forecast", "arguments": {"city": "Paris"}},
+				{"type": "function_call", "id": "c2", "name": "remote_only", "arguments": {}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": "turn-2", "status": "completed", "outputs": [{"type": "text", "text": "21.5 degrees in Paris."}]}`)
+	})
+
+	r := newTestFunctionRegistry(t)
+	got, err := r.RunInteraction(ctx, client.Interactions, &Interaction{Model: "gemini-2.5-flash", Input: "Weather in Paris?"}, nil)
+	if err != nil {
+		t.Fatalf("RunInteraction() failed: %v", err)
+	}
+	if got.ID != "turn-2" {
+		t.Errorf("RunInteraction() = %+v, want the final interaction", got)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	wantTools := []any{
+		map[string]any{"type": "function", "name": "get_forecast", "description": "Returns the forecast of a city.", "parameters": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"city": map[string]any{"type": "string", "description": "The city"},
+				"days": map[string]any{"type": "integer"},
+			},
+			"required": []any{"city"},
+		}},
+		map[string]any{"type": "function", "name": "list_cities", "parameters": map[string]any{"type": "object", "properties": map[string]any{}}},
+	}
+	if diff := cmp.Diff(wantTools, requests[0]["tools"]); diff != "" {
+		t.Errorf("advertised tools mismatch (-want +got):\n%s", diff)
+	}
+	wantInput := []any{
+		map[string]any{"type": "function_result", "callId": "c1", "name": "get_forecast", "result": map[string]any{"city": "Paris", "celsius": 21.5}},
+	}
+	if diff := cmp.Diff(wantInput, requests[1]["input"]); diff != "" {
+		t.Errorf("second input mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,442 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package genaitest provides an in-memory fake of the Gemini API for the
+// tests of code that uses a *genai.Client, without an HTTP server.
+//
+// A [Fake] answers the requests of the clients it creates with scripted
+// responses, and keeps files, cached contents, batches and interactions in
+// memory so that they can be created, got, listed and deleted:
+//
+//	fake := genaitest.New()
+//	fake.OnGenerateContent("gemini-2.5-flash", genaitest.TextResponse("Hello!"))
+//	client, err := fake.NewClient(ctx, nil)
+//	...
+//	resp, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", genai.Text("Hi"), nil)
+//	...
+//	fake.AssertCalled(t, "POST models/gemini-2.5-flash:generateContent", 1)
+//
+// Requests are matched against the patterns of [Fake.Handle] and
+// [Fake.HandleFunc], e.g. "POST models/*:generateContent", where the path has
+// no API version and is matched with [path.Match]. Requests that match no
+// pattern fail with a 404 error.
+package genaitest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/plar/genai"
+)
+
+// BaseURL is the base URL of the clients of a Fake. Requests are never sent
+// to it.
+const BaseURL = "https://genaitest.invalid/"
+
+// Call is a request received by a Fake.
+type Call struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// Path is the path of the request without the API version, e.g.
+	// "models/gemini-2.5-flash:generateContent" or "upload/files".
+	Path string
+	// Query is the query of the request.
+	Query url.Values
+	// Header is the header of the request.
+	Header http.Header
+	// Body is the JSON body of the request, or nil if it has none.
+	Body map[string]any
+	// Data is the raw body of the request.
+	Data []byte
+	// Stream reports whether the request is for a streaming response.
+	Stream bool
+}
+
+// Response is a scripted response of a Fake.
+type Response struct {
+	// Status is the HTTP status code. Defaults to 200.
+	Status int
+	// Header is added to the header of the response.
+	Header http.Header
+	// Body is encoded as the JSON body of the response. It may be a genai
+	// response type, such as *genai.GenerateContentResponse, or a map of the
+	// JSON of the REST API.
+	Body any
+	// Stream are the events of a streaming response, each encoded as JSON in
+	// a server-sent event. If set, Body is ignored.
+	Stream []any
+	// ChunkDelay is the delay before every event of Stream.
+	ChunkDelay time.Duration
+	// Latency delays the response in addition to Fake.Latency.
+	Latency time.Duration
+	// Err, if set, is returned by the transport instead of a response, e.g. to
+	// simulate a network error.
+	Err error
+}
+
+// ErrorResponse returns the response of an API error with the HTTP status
+// code status and message.
+func ErrorResponse(status int, message string) *Response {
+	return &Response{Status: status, Body: map[string]any{"error": map[string]any{
+		"code":    status,
+		"message": message,
+		"status":  canonicalStatus(status),
+	}}}
+}
+
+// TextResponse returns a GenerateContentResponse with a single candidate that
+// answers text.
+func TextResponse(text string) *genai.GenerateContentResponse {
+	return &genai.GenerateContentResponse{Candidates: []*genai.Candidate{{
+		Content:      genai.NewContentFromText(text, genai.RoleModel),
+		FinishReason: genai.FinishReasonStop,
+	}}}
+}
+
+// Fake is an in-memory Gemini API backend. It is safe for concurrent use.
+type Fake struct {
+	// Latency delays every response, e.g. to test timeouts. A request whose
+	// context is done while it waits fails with the error of the context.
+	Latency time.Duration
+
+	mu    sync.Mutex
+	stubs []*stub
+	calls []*Call
+	store *store
+}
+
+// stub answers the requests that match its pattern.
+type stub struct {
+	method, path string
+	respond      func(*Call) *Response
+}
+
+// New returns a Fake with the built-in handlers of files, cached contents,
+// batches and interactions.
+func New() *Fake {
+	f := &Fake{}
+	f.store = newStore(f)
+	f.store.register()
+	return f
+}
+
+// NewClient returns a Gemini API client whose requests are answered by the
+// fake. config is copied, and may be nil; its APIKey defaults to a fake key,
+// and its HTTPClient and BaseURL are replaced.
+func (f *Fake) NewClient(ctx context.Context, config *genai.ClientConfig) (*genai.Client, error) {
+	cc := genai.ClientConfig{}
+	if config != nil {
+		cc = *config
+	}
+	if cc.Backend == genai.BackendVertexAI {
+		return nil, fmt.Errorf("genaitest: only the Gemini API backend is supported")
+	}
+	cc.Backend = genai.BackendGeminiAPI
+	if cc.APIKey == "" {
+		cc.APIKey = "genaitest-api-key"
+	}
+	cc.HTTPClient = &http.Client{Transport: f}
+	cc.HTTPOptions.BaseURL = BaseURL
+	return genai.NewClient(ctx, &cc)
+}
+
+// Handle answers the requests that match pattern with responses, in order.
+// The last response answers all the requests after it. Handlers registered
+// later take precedence.
+func (f *Fake) Handle(pattern string, responses ...*Response) {
+	next := 0
+	var mu sync.Mutex
+	f.HandleFunc(pattern, func(*Call) *Response {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(responses) == 0 {
+			return &Response{}
+		}
+		r := responses[min(next, len(responses)-1)]
+		next++
+		return r
+	})
+}
+
+// HandleFunc answers the requests that match pattern with respond. If
+// respond returns nil, the request is passed on to the handlers registered
+// before. Handlers registered later take precedence.
+func (f *Fake) HandleFunc(pattern string, respond func(*Call) *Response) {
+	method, p, ok := strings.Cut(pattern, " ")
+	if !ok {
+		method, p = "", pattern
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stubs = append(f.stubs, &stub{method: method, path: p, respond: respond})
+}
+
+// OnGenerateContent answers the GenerateContent calls of model, including
+// those of chats, with responses in order. An empty model or "*" matches
+// every model.
+func (f *Fake) OnGenerateContent(model string, responses ...*genai.GenerateContentResponse) {
+	scripted := make([]*Response, len(responses))
+	for i, r := range responses {
+		scripted[i] = &Response{Body: r}
+	}
+	f.Handle("POST "+modelPath(model)+":generateContent", scripted...)
+}
+
+// OnGenerateContentStream answers every GenerateContentStream call of model,
+// including those of chats, with a stream of chunks. An empty model or "*"
+// matches every model.
+func (f *Fake) OnGenerateContentStream(model string, chunks ...*genai.GenerateContentResponse) {
+	events := make([]any, len(chunks))
+	for i, c := range chunks {
+		events[i] = c
+	}
+	f.Handle("POST "+modelPath(model)+":streamGenerateContent", &Response{Stream: events})
+}
+
+// RoundTrip answers req. It implements http.RoundTripper, so that a Fake can
+// also be the transport of a client created with genai.NewClient.
+func (f *Fake) RoundTrip(req *http.Request) (*http.Response, error) {
+	call, err := newCall(req)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, call)
+	f.mu.Unlock()
+
+	r := f.respond(call)
+	if err := sleep(req.Context(), f.Latency+r.Latency); err != nil {
+		return nil, err
+	}
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	return f.httpResponse(req, r)
+}
+
+// respond returns the response of the latest handler of call.
+func (f *Fake) respond(call *Call) *Response {
+	f.mu.Lock()
+	stubs := append([]*stub(nil), f.stubs...)
+	f.mu.Unlock()
+	for i := len(stubs) - 1; i >= 0; i-- {
+		s := stubs[i]
+		if s.method != "" && s.method != call.Method {
+			continue
+		}
+		if ok, _ := path.Match(s.path, call.Path); !ok {
+			continue
+		}
+		if r := s.respond(call); r != nil {
+			return r
+		}
+	}
+	return ErrorResponse(http.StatusNotFound, fmt.Sprintf("genaitest: no handler for %s %s", call.Method, call.Path))
+}
+
+func (f *Fake) httpResponse(req *http.Request, r *Response) (*http.Response, error) {
+	status := r.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	resp := &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Request:    req,
+	}
+	for key, values := range r.Header {
+		resp.Header[key] = values
+	}
+	if r.Stream != nil {
+		resp.Header.Set("Content-Type", "text/event-stream")
+		resp.Body = streamBody(req.Context(), r.Stream, r.ChunkDelay)
+		return resp, nil
+	}
+	var data []byte
+	switch body := r.Body.(type) {
+	case nil:
+		data = []byte("{}")
+	case []byte:
+		data = body
+	default:
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("genaitest: failed to encode the response body: %w", err)
+		}
+	}
+	if resp.Header.Get("Content-Type") == "" {
+		resp.Header.Set("Content-Type", "application/json")
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	return resp, nil
+}
+
+// streamBody returns a body that sends events as server-sent events, waiting
+// for delay before each of them.
+func streamBody(ctx context.Context, events []any, delay time.Duration) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		for _, event := range events {
+			if err := sleep(ctx, delay); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				pw.CloseWithError(fmt.Errorf("genaitest: failed to encode a stream event: %w", err))
+				return
+			}
+			if _, err := fmt.Fprintf(pw, "data: %s\n\n", data); err != nil {
+				return
+			}
+		}
+		pw.Close()
+	}()
+	return pr
+}
+
+// Calls returns the requests received by the fake, in order.
+func (f *Fake) Calls() []*Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*Call(nil), f.calls...)
+}
+
+// CallsTo returns the requests received by the fake that match pattern, in
+// order.
+func (f *Fake) CallsTo(pattern string) []*Call {
+	method, p, ok := strings.Cut(pattern, " ")
+	if !ok {
+		method, p = "", pattern
+	}
+	var calls []*Call
+	for _, call := range f.Calls() {
+		if method != "" && method != call.Method {
+			continue
+		}
+		if ok, _ := path.Match(p, call.Path); ok {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// AssertCalled reports an error to t unless the fake received times requests
+// that match pattern.
+func (f *Fake) AssertCalled(t testing.TB, pattern string, times int) {
+	t.Helper()
+	if got := len(f.CallsTo(pattern)); got != times {
+		var received []string
+		for _, call := range f.Calls() {
+			received = append(received, call.Method+" "+call.Path)
+		}
+		t.Errorf("genaitest: got %d calls matching %q, want %d; received %q", got, pattern, times, received)
+	}
+}
+
+// versionPattern matches the API version segment of a request path.
+var versionPattern = regexp.MustCompile(`^v\d+[a-z0-9]*$`)
+
+func newCall(req *http.Request) (*Call, error) {
+	call := &Call{Method: req.Method, Query: req.URL.Query(), Header: req.Header.Clone()}
+	call.Stream = call.Query.Get("alt") == "sse"
+	var segments []string
+	for i, segment := range strings.Split(strings.Trim(req.URL.Path, "/"), "/") {
+		if i < 2 && versionPattern.MatchString(segment) {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	call.Path = strings.Join(segments, "/")
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		call.Data = data
+		if len(data) > 0 && strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+			// Chunks of uploads are sent with a JSON content type too.
+			_ = json.Unmarshal(data, &call.Body)
+		}
+	}
+	return call, nil
+}
+
+// modelPath returns the path of model in a request.
+func modelPath(model string) string {
+	switch {
+	case model == "" || model == "*":
+		return "models/*"
+	case strings.Contains(model, "/"):
+		return model
+	}
+	return "models/" + model
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// canonicalStatus returns the canonical status of an HTTP status code.
+func canonicalStatus(code int) string {
+	switch code {
+	case http.StatusBadRequest:
+		return genai.StatusInvalidArgument
+	case http.StatusUnauthorized:
+		return genai.StatusUnauthenticated
+	case http.StatusForbidden:
+		return genai.StatusPermissionDenied
+	case http.StatusNotFound:
+		return genai.StatusNotFound
+	case http.StatusTooManyRequests:
+		return genai.StatusResourceExhausted
+	case http.StatusNotImplemented:
+		return genai.StatusUnimplemented
+	case http.StatusServiceUnavailable:
+		return genai.StatusUnavailable
+	case http.StatusGatewayTimeout:
+		return genai.StatusDeadlineExceeded
+	}
+	if code >= 500 {
+		return genai.StatusInternal
+	}
+	return ""
+}
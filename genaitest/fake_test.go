@@ -0,0 +1,338 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genaitest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/plar/genai"
+)
+
+const model = "gemini-2.5-flash"
+
+func newClient(t *testing.T, f *Fake) *genai.Client {
+	t.Helper()
+	client, err := f.NewClient(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestModels(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+	f.OnGenerateContent(model, TextResponse("first"), TextResponse("second"))
+	client := newClient(t, f)
+
+	for _, want := range []string{"first", "second", "second"} {
+		resp, err := client.Models.GenerateContent(ctx, model, genai.Text("hi"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.Text(); got != want {
+			t.Errorf("GenerateContent().Text() = %q, want %q", got, want)
+		}
+	}
+	f.AssertCalled(t, "POST models/*:generateContent", 3)
+	calls := f.CallsTo("models/" + model + ":generateContent")
+	if got := calls[0].Body["contents"].([]any)[0].(map[string]any)["parts"].([]any)[0].(map[string]any)["text"]; got != "hi" {
+		t.Errorf("recorded prompt = %v, want hi", got)
+	}
+
+	if _, err := client.Models.GenerateContent(ctx, "other-model", genai.Text("hi"), nil); !genai.IsNotFound(err) {
+		t.Errorf("GenerateContent() of an unscripted model error = %v, want NOT_FOUND", err)
+	}
+}
+
+func TestStream(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+	f.OnGenerateContentStream("", TextResponse("Hello"), TextResponse(", world"))
+	client := newClient(t, f)
+
+	var sb strings.Builder
+	for resp, err := range client.Models.GenerateContentStream(ctx, model, genai.Text("hi"), nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb.WriteString(resp.Text())
+	}
+	if got := sb.String(); got != "Hello, world" {
+		t.Errorf("streamed text = %q, want %q", got, "Hello, world")
+	}
+}
+
+func TestChats(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+	f.OnGenerateContent(model, TextResponse("one"), TextResponse("two"))
+	client := newClient(t, f)
+
+	chat, err := client.Chats.Create(ctx, model, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range []string{"a", "b"} {
+		if _, err := chat.SendMessage(ctx, genai.Part{Text: message}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	calls := f.CallsTo("POST models/*:generateContent")
+	if got := len(calls[1].Body["contents"].([]any)); got != 3 {
+		t.Errorf("second chat request has %d contents, want the 3 of the history", got)
+	}
+}
+
+func TestResponses(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Errors", func(t *testing.T) {
+		f := New()
+		f.Handle("POST models/*:generateContent", ErrorResponse(http.StatusTooManyRequests, "slow down"), &Response{Body: TextResponse("ok")})
+		client := newClient(t, f)
+		if _, err := client.Models.GenerateContent(ctx, model, genai.Text("hi"), nil); !genai.IsRateLimited(err) {
+			t.Errorf("GenerateContent() error = %v, want a rate limit error", err)
+		}
+		if _, err := client.Models.GenerateContent(ctx, model, genai.Text("hi"), nil); err != nil {
+			t.Errorf("GenerateContent() after the error failed: %v", err)
+		}
+	})
+
+	t.Run("Transport error", func(t *testing.T) {
+		f := New()
+		wantErr := errors.New("connection reset")
+		f.Handle("models/*:generateContent", &Response{Err: wantErr})
+		client := newClient(t, f)
+		if _, err := client.Models.GenerateContent(ctx, model, genai.Text("hi"), nil); !errors.Is(err, wantErr) {
+			t.Errorf("GenerateContent() error = %v, want %v", err, wantErr)
+		}
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		f := New()
+		f.Latency = time.Second
+		f.OnGenerateContent(model, TextResponse("late"))
+		client := newClient(t, f)
+		ctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		if _, err := client.Models.GenerateContent(ctx, model, genai.Text("hi"), nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("GenerateContent() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("HandleFunc falls through", func(t *testing.T) {
+		f := New()
+		f.OnGenerateContent(model, TextResponse("default"))
+		f.HandleFunc("POST models/*:generateContent", func(call *Call) *Response {
+			if strings.Contains(string(call.Data), "special") {
+				return &Response{Body: TextResponse("special")}
+			}
+			return nil
+		})
+		client := newClient(t, f)
+		for prompt, want := range map[string]string{"special": "special", "plain": "default"} {
+			resp, err := client.Models.GenerateContent(ctx, model, genai.Text(prompt), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := resp.Text(); got != want {
+				t.Errorf("GenerateContent(%q).Text() = %q, want %q", prompt, got, want)
+			}
+		}
+	})
+}
+
+func TestFiles(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+	client := newClient(t, f)
+
+	data := []byte("some file content")
+	file, err := client.Files.Upload(ctx, bytes.NewReader(data), &genai.UploadFileConfig{MIMEType: "text/plain", DisplayName: "notes"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.State != genai.FileStateActive || file.MIMEType != "text/plain" || file.DisplayName != "notes" || *file.SizeBytes != int64(len(data)) {
+		t.Errorf("Upload() = %+v, want an active text/plain file named notes", file)
+	}
+	got, err := client.Files.Get(ctx, file.Name, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.URI != file.URI {
+		t.Errorf("Get().URI = %q, want %q", got.URI, file.URI)
+	}
+	downloaded, err := client.Files.Download(ctx, genai.NewDownloadURIFromFile(file), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(downloaded, data) {
+		t.Errorf("Download() = %q, want %q", downloaded, data)
+	}
+
+	f.AddFile("other", "image/png", []byte{1, 2, 3})
+	var names []string
+	for file, err := range client.Files.All(ctx) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, file.Name)
+	}
+	if len(names) != 2 || names[0] != "files/other" {
+		t.Errorf("All() = %v, want the 2 files, newest first", names)
+	}
+
+	if _, err := client.Files.Delete(ctx, file.Name, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Files.Get(ctx, file.Name, nil); !genai.IsNotFound(err) {
+		t.Errorf("Get() of a deleted file error = %v, want NOT_FOUND", err)
+	}
+}
+
+func TestCaches(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+	client := newClient(t, f)
+
+	cache, err := client.Caches.Create(ctx, model, &genai.CreateCachedContentConfig{
+		DisplayName: "context",
+		TTL:         10 * time.Minute,
+		Contents:    genai.Text("a long document"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache.Name == "" || cache.DisplayName != "context" || time.Until(cache.ExpireTime) > 10*time.Minute {
+		t.Errorf("Create() = %+v, want a named cache that expires in 10 minutes", cache)
+	}
+	updated, err := client.Caches.Update(ctx, cache.Name, &genai.UpdateCachedContentConfig{TTL: 2 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(updated.ExpireTime) < time.Hour {
+		t.Errorf("Update().ExpireTime = %v, want in 2 hours", updated.ExpireTime)
+	}
+	page, err := client.Caches.List(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Name != cache.Name {
+		t.Errorf("List() = %+v, want the cache", page.Items)
+	}
+	if _, err := client.Caches.Delete(ctx, cache.Name, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Caches.Get(ctx, cache.Name, nil); !genai.IsNotFound(err) {
+		t.Errorf("Get() of a deleted cache error = %v, want NOT_FOUND", err)
+	}
+}
+
+func TestBatches(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+	f.OnGenerateContent(model, TextResponse("answer"))
+	client := newClient(t, f)
+
+	job, err := client.Batches.Create(ctx, model, &genai.BatchJobSource{InlinedRequests: []*genai.InlinedRequest{
+		{Contents: genai.Text("one")},
+		{Contents: genai.Text("two")},
+	}}, &genai.CreateBatchJobConfig{DisplayName: "job"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.State != genai.JobStateSucceeded || job.DisplayName != "job" {
+		t.Errorf("Create() = %+v, want a succeeded job named job", job)
+	}
+	if got := len(job.Dest.InlinedResponses); got != 2 {
+		t.Fatalf("Create() has %d inlined responses, want 2", got)
+	}
+	if got := job.Dest.InlinedResponses[1].Response.Text(); got != "answer" {
+		t.Errorf("inlined response text = %q, want answer", got)
+	}
+
+	if err := f.SetBatchState(job.Name, genai.JobStateRunning); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Batches.Cancel(ctx, job.Name, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.Batches.Get(ctx, job.Name, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State != genai.JobStateCancelled {
+		t.Errorf("Get().State after Cancel() = %v, want %v", got.State, genai.JobStateCancelled)
+	}
+
+	t.Run("File input", func(t *testing.T) {
+		input := f.AddFile("", "application/jsonl", []byte(`{"key":"k1","request":{"contents":[{"parts":[{"text":"hi"}]}]}}`+"\n"))
+		job, err := client.Batches.Create(ctx, model, &genai.BatchJobSource{FileName: input.Name}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, ok := f.FileData(job.Dest.FileName)
+		if !ok || !strings.Contains(string(data), `"key":"k1"`) || !strings.Contains(string(data), "answer") {
+			t.Errorf("responses file = %q, want the response of k1", data)
+		}
+	})
+}
+
+func TestInteractions(t *testing.T) {
+	ctx := context.Background()
+	f := New()
+	f.OnInteraction(&genai.Interaction{Outputs: []*genai.InteractionContent{{Type: "text", Text: "hello"}}})
+	f.OnInteractionStream(
+		&genai.InteractionEvent{EventType: "content.delta", Delta: &genai.InteractionContent{Type: "text", Text: "hel"}},
+		&genai.InteractionEvent{EventType: "interaction.complete", Interaction: &genai.Interaction{ID: "streamed", Status: genai.InteractionStatusCompleted}},
+	)
+	client := newClient(t, f)
+
+	created, err := client.Interactions.Create(ctx, &genai.Interaction{Model: model, Input: "hi"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" || created.Model != model || created.Status != genai.InteractionStatusCompleted {
+		t.Errorf("Create() = %+v, want a completed interaction of %s with an ID", created, model)
+	}
+	got, err := client.Interactions.Get(ctx, created.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text, _ := got.Outputs[0].AsText(); text != "hello" {
+		t.Errorf("Get().Outputs = %v, want hello", got.Outputs)
+	}
+
+	events := 0
+	for _, err := range client.Interactions.CreateStream(ctx, &genai.Interaction{Model: model, Input: "hi"}, nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		events++
+	}
+	if events != 2 {
+		t.Errorf("CreateStream() yielded %d events, want 2", events)
+	}
+	if _, err := client.Interactions.Get(ctx, "streamed", nil); err != nil {
+		t.Errorf("Get() of the streamed interaction failed: %v", err)
+	}
+}
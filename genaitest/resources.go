@@ -0,0 +1,578 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genaitest
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/plar/genai"
+)
+
+const (
+	defaultCacheTTL     = time.Hour
+	fileExpiration      = 48 * time.Hour
+	defaultListPageSize = 100
+)
+
+// collection is an ordered set of resources, encoded as the JSON of the REST
+// API, by name.
+type collection struct {
+	items map[string]map[string]any
+	names []string
+}
+
+func (c *collection) put(name string, item map[string]any) {
+	if c.items == nil {
+		c.items = map[string]map[string]any{}
+	}
+	if _, ok := c.items[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.items[name] = item
+}
+
+func (c *collection) get(name string) (map[string]any, bool) {
+	item, ok := c.items[name]
+	return item, ok
+}
+
+func (c *collection) delete(name string) bool {
+	if _, ok := c.items[name]; !ok {
+		return false
+	}
+	delete(c.items, name)
+	for i, n := range c.names {
+		if n == name {
+			c.names = append(c.names[:i], c.names[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// list returns the page of the collection that call asks for as the JSON of
+// a list response with the items under key, newest first.
+func (c *collection) list(call *Call, key string) map[string]any {
+	pageSize, _ := strconv.Atoi(call.Query.Get("pageSize"))
+	if pageSize <= 0 {
+		pageSize = defaultListPageSize
+	}
+	start, _ := strconv.Atoi(call.Query.Get("pageToken"))
+	items := []any{}
+	for i := len(c.names) - 1 - start; i >= 0 && len(items) < pageSize; i-- {
+		items = append(items, c.items[c.names[i]])
+	}
+	resp := map[string]any{key: items}
+	if next := start + len(items); next < len(c.names) {
+		resp["nextPageToken"] = strconv.Itoa(next)
+	}
+	return resp
+}
+
+// upload is a resumable file upload in progress.
+type upload struct {
+	file map[string]any
+	data bytes.Buffer
+}
+
+// store keeps the resources of a Fake.
+type store struct {
+	f            *Fake
+	mu           sync.Mutex
+	next         int
+	uploads      map[string]*upload
+	files        collection
+	fileData     map[string][]byte
+	caches       collection
+	batches      collection
+	interactions collection
+}
+
+func newStore(f *Fake) *store {
+	return &store{f: f, uploads: map[string]*upload{}, fileData: map[string][]byte{}}
+}
+
+// register registers the built-in handlers of the resources.
+func (s *store) register() {
+	f := s.f
+	f.HandleFunc("POST upload/files", s.upload)
+	f.HandleFunc("GET files", s.locked(func(call *Call) *Response { return &Response{Body: s.files.list(call, "files")} }))
+	f.HandleFunc("GET files/*", s.locked(s.getFile))
+	f.HandleFunc("DELETE files/*", s.locked(func(call *Call) *Response { return deleted(&s.files, call.Path) }))
+
+	f.HandleFunc("POST cachedContents", s.locked(s.createCache))
+	f.HandleFunc("GET cachedContents", s.locked(func(call *Call) *Response { return &Response{Body: s.caches.list(call, "cachedContents")} }))
+	f.HandleFunc("GET cachedContents/*", s.locked(func(call *Call) *Response { return found(&s.caches, call.Path) }))
+	f.HandleFunc("PATCH cachedContents/*", s.locked(s.updateCache))
+	f.HandleFunc("DELETE cachedContents/*", s.locked(func(call *Call) *Response { return deleted(&s.caches, call.Path) }))
+
+	// Batches run the requests through the other handlers, so they are not
+	// locked while they do.
+	f.HandleFunc("POST models/*:batchGenerateContent", s.createBatch)
+	f.HandleFunc("GET batches", s.locked(func(call *Call) *Response { return &Response{Body: s.batches.list(call, "operations")} }))
+	f.HandleFunc("GET batches/*", s.locked(func(call *Call) *Response { return found(&s.batches, call.Path) }))
+	f.HandleFunc("POST batches/*", s.locked(s.cancelBatch))
+	f.HandleFunc("DELETE batches/*", s.locked(func(call *Call) *Response { return deleted(&s.batches, call.Path) }))
+
+	f.HandleFunc("GET interactions/*", s.locked(func(call *Call) *Response { return found(&s.interactions, call.Path) }))
+	f.HandleFunc("POST interactions/*/cancel", s.locked(s.cancelInteraction))
+	f.HandleFunc("DELETE interactions/*", s.locked(func(call *Call) *Response { return deleted(&s.interactions, call.Path) }))
+}
+
+// locked returns handler called with the store locked.
+func (s *store) locked(handler func(*Call) *Response) func(*Call) *Response {
+	return func(call *Call) *Response {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return handler(call)
+	}
+}
+
+// newID returns a new resource ID with prefix. IDs are lowercase alphanumeric,
+// like those of the service.
+func (s *store) newID(prefix string) string {
+	s.next++
+	return fmt.Sprintf("%s%d", prefix, s.next)
+}
+
+func found(c *collection, name string) *Response {
+	item, ok := c.get(name)
+	if !ok {
+		return notFound(name)
+	}
+	return &Response{Body: item}
+}
+
+func deleted(c *collection, name string) *Response {
+	if !c.delete(name) {
+		return notFound(name)
+	}
+	return &Response{Body: map[string]any{}}
+}
+
+func notFound(name string) *Response {
+	return ErrorResponse(http.StatusNotFound, fmt.Sprintf("%s is not found", name))
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// upload handles the start of a resumable upload and its chunks.
+func (s *store) upload(call *Call) *Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id := call.Query.Get("upload_id"); id != "" {
+		return s.uploadChunk(call, id)
+	}
+	file, _ := call.Body["file"].(map[string]any)
+	file = maps.Clone(file)
+	if file == nil {
+		file = map[string]any{}
+	}
+	name, _ := file["name"].(string)
+	if name == "" {
+		name = "files/" + s.newID("file")
+	}
+	if _, ok := s.files.get(name); ok {
+		return ErrorResponse(http.StatusConflict, fmt.Sprintf("%s already exists", name))
+	}
+	file["name"] = name
+	if mimeType := call.Header.Get("X-Goog-Upload-Header-Content-Type"); mimeType != "" {
+		file["mimeType"] = mimeType
+	}
+	id := strings.TrimPrefix(name, "files/")
+	s.uploads[id] = &upload{file: file}
+	return &Response{Header: http.Header{
+		"X-Goog-Upload-Url":    {BaseURL + "upload/v1beta/files?upload_id=" + id},
+		"X-Goog-Upload-Status": {"active"},
+	}}
+}
+
+func (s *store) uploadChunk(call *Call, id string) *Response {
+	u, ok := s.uploads[id]
+	if !ok {
+		return notFound("upload " + id)
+	}
+	u.data.Write(call.Data)
+	if !strings.Contains(call.Header.Get("X-Goog-Upload-Command"), "finalize") {
+		return &Response{Header: http.Header{"X-Goog-Upload-Status": {"active"}}}
+	}
+	delete(s.uploads, id)
+	now := time.Now()
+	data := u.data.Bytes()
+	sum := sha256.Sum256(data)
+	file := u.file
+	name := file["name"].(string)
+	file["sizeBytes"] = strconv.Itoa(len(data))
+	file["createTime"] = timestamp(now)
+	file["updateTime"] = timestamp(now)
+	file["expirationTime"] = timestamp(now.Add(fileExpiration))
+	file["sha256Hash"] = base64.StdEncoding.EncodeToString(sum[:])
+	file["uri"] = BaseURL + "v1beta/" + name
+	// Unlike the service, the fake lets uploaded files be downloaded too.
+	file["downloadUri"] = BaseURL + "v1beta/" + name + ":download?alt=media"
+	file["state"] = "ACTIVE"
+	file["source"] = "UPLOADED"
+	s.files.put(name, file)
+	s.fileData[name] = bytes.Clone(data)
+	return &Response{
+		Header: http.Header{"X-Goog-Upload-Status": {"final"}},
+		Body:   map[string]any{"file": file},
+	}
+}
+
+// getFile handles the requests of the metadata and the content of files.
+func (s *store) getFile(call *Call) *Response {
+	name, download := strings.CutSuffix(call.Path, ":download")
+	if !download {
+		return found(&s.files, name)
+	}
+	data, ok := s.fileData[name]
+	if !ok {
+		return notFound(name)
+	}
+	header := http.Header{"Content-Type": {"application/octet-stream"}}
+	var offset int
+	if r := call.Header.Get("Range"); r != "" {
+		if _, err := fmt.Sscanf(r, "bytes=%d-", &offset); err != nil || offset > len(data) {
+			return &Response{Status: http.StatusRequestedRangeNotSatisfiable}
+		}
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(data)-1, len(data)))
+		return &Response{Status: http.StatusPartialContent, Header: header, Body: data[offset:]}
+	}
+	return &Response{Header: header, Body: data}
+}
+
+// AddFile stores a file with data as if it had been uploaded, and returns it.
+func (f *Fake) AddFile(name, mimeType string, data []byte) *genai.File {
+	s := f.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if name == "" {
+		name = "files/" + s.newID("file")
+	} else if !strings.HasPrefix(name, "files/") {
+		name = "files/" + name
+	}
+	id := strings.TrimPrefix(name, "files/")
+	s.uploads[id] = &upload{file: map[string]any{"name": name, "mimeType": mimeType}}
+	s.uploadChunk(&Call{
+		Header: http.Header{"X-Goog-Upload-Command": {"upload, finalize"}},
+		Data:   data,
+	}, id)
+	file := new(genai.File)
+	decode(s.files.items[name], file)
+	return file
+}
+
+// FileData returns the content of the file name, e.g. "files/file1".
+func (f *Fake) FileData(name string) ([]byte, bool) {
+	f.store.mu.Lock()
+	defer f.store.mu.Unlock()
+	data, ok := f.store.fileData[name]
+	return bytes.Clone(data), ok
+}
+
+func (s *store) createCache(call *Call) *Response {
+	cache := maps.Clone(call.Body)
+	if cache == nil {
+		cache = map[string]any{}
+	}
+	name := "cachedContents/" + s.newID("cache")
+	now := time.Now()
+	cache["name"] = name
+	cache["createTime"] = timestamp(now)
+	setCacheExpiration(cache, now)
+	s.caches.put(name, cache)
+	return &Response{Body: cache}
+}
+
+func (s *store) updateCache(call *Call) *Response {
+	cache, ok := s.caches.get(call.Path)
+	if !ok {
+		return notFound(call.Path)
+	}
+	cache = maps.Clone(cache)
+	if _, ok := call.Body["ttl"]; ok {
+		delete(cache, "expireTime")
+	}
+	maps.Copy(cache, call.Body)
+	setCacheExpiration(cache, time.Now())
+	s.caches.put(call.Path, cache)
+	return &Response{Body: cache}
+}
+
+// setCacheExpiration sets the update and expire times of cache, the latter
+// from its TTL, replacing the TTL like the service does.
+func setCacheExpiration(cache map[string]any, now time.Time) {
+	cache["updateTime"] = timestamp(now)
+	ttl := defaultCacheTTL
+	if s, ok := cache["ttl"].(string); ok {
+		if d, err := time.ParseDuration(s); err == nil {
+			ttl = d
+		}
+	}
+	delete(cache, "ttl")
+	if _, ok := cache["expireTime"]; !ok {
+		cache["expireTime"] = timestamp(now.Add(ttl))
+	}
+}
+
+// createBatch runs the requests of a batch with the GenerateContent handlers
+// of their model, and stores the batch as succeeded.
+func (s *store) createBatch(call *Call) *Response {
+	model := strings.TrimSuffix(call.Path, ":batchGenerateContent")
+	batch, _ := call.Body["batch"].(map[string]any)
+	input, _ := batch["inputConfig"].(map[string]any)
+	output := map[string]any{}
+	if requests, ok := input["requests"].(map[string]any); ok {
+		items, _ := requests["requests"].([]any)
+		var responses []any
+		for _, item := range items {
+			item, _ := item.(map[string]any)
+			request, _ := item["request"].(map[string]any)
+			response := s.generate(model, request)
+			if metadata, ok := item["metadata"]; ok {
+				response["metadata"] = metadata
+			}
+			responses = append(responses, response)
+		}
+		output["inlinedResponses"] = map[string]any{"inlinedResponses": responses}
+	} else if fileName, ok := input["fileName"].(string); ok {
+		responsesFile, err := s.generateFile(model, fileName)
+		if err != nil {
+			return ErrorResponse(http.StatusBadRequest, err.Error())
+		}
+		output["responsesFile"] = responsesFile
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := "batches/" + s.newID("batch")
+	now := timestamp(time.Now())
+	metadata := map[string]any{
+		"@type":      "type.googleapis.com/google.ai.generativelanguage.v1main.GenerateContentBatch",
+		"name":       name,
+		"model":      model,
+		"state":      "BATCH_STATE_SUCCEEDED",
+		"createTime": now,
+		"updateTime": now,
+		"endTime":    now,
+		"output":     output,
+	}
+	if displayName, ok := batch["displayName"]; ok {
+		metadata["displayName"] = displayName
+	}
+	operation := map[string]any{"name": name, "metadata": metadata, "done": true}
+	s.batches.put(name, operation)
+	return &Response{Body: operation}
+}
+
+// generate answers request with the GenerateContent handlers of model, as an
+// inlined response of a batch.
+func (s *store) generate(model string, request map[string]any) map[string]any {
+	data, _ := json.Marshal(request)
+	r := s.f.respond(&Call{Method: http.MethodPost, Path: model + ":generateContent", Header: http.Header{}, Body: request, Data: data})
+	var body map[string]any
+	if encoded, err := json.Marshal(r.Body); err == nil {
+		_ = json.Unmarshal(encoded, &body)
+	}
+	if r.Err != nil {
+		return map[string]any{"error": map[string]any{"code": http.StatusInternalServerError, "message": r.Err.Error()}}
+	}
+	if r.Status >= http.StatusBadRequest {
+		return map[string]any{"error": body["error"]}
+	}
+	return map[string]any{"response": body}
+}
+
+// generateFile answers the JSONL requests of the file fileName, and returns
+// the name of a new file with the JSONL responses.
+func (s *store) generateFile(model, fileName string) (string, error) {
+	s.mu.Lock()
+	data, ok := s.fileData[fileName]
+	s.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("%s is not found", fileName)
+	}
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var item map[string]any
+		if err := json.Unmarshal(line, &item); err != nil {
+			return "", fmt.Errorf("invalid line in %s: %w", fileName, err)
+		}
+		request, _ := item["request"].(map[string]any)
+		response := s.generate(model, request)
+		if key, ok := item["key"]; ok {
+			response["key"] = key
+		}
+		encoded, _ := json.Marshal(response)
+		out.Write(append(encoded, '\n'))
+	}
+	return s.f.AddFile("", "application/jsonl", out.Bytes()).Name, nil
+}
+
+func (s *store) cancelBatch(call *Call) *Response {
+	name, ok := strings.CutSuffix(call.Path, ":cancel")
+	if !ok {
+		return nil
+	}
+	operation, ok := s.batches.get(name)
+	if !ok {
+		return notFound(name)
+	}
+	metadata := operation["metadata"].(map[string]any)
+	switch metadata["state"] {
+	case "BATCH_STATE_PENDING", "BATCH_STATE_RUNNING":
+		metadata["state"] = "BATCH_STATE_CANCELLED"
+		metadata["endTime"] = timestamp(time.Now())
+		operation["done"] = true
+	}
+	return &Response{Body: map[string]any{}}
+}
+
+// SetBatchState sets the state of the batch name, e.g. to test the polling of
+// a pending batch. Batches are created in JobStateSucceeded.
+func (f *Fake) SetBatchState(name string, state genai.JobState) error {
+	s := f.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	operation, ok := s.batches.get(name)
+	if !ok {
+		return fmt.Errorf("genaitest: %s is not found", name)
+	}
+	metadata := operation["metadata"].(map[string]any)
+	metadata["state"] = "BATCH_STATE_" + strings.TrimPrefix(string(state), "JOB_STATE_")
+	metadata["updateTime"] = timestamp(time.Now())
+	switch state {
+	case genai.JobStatePending, genai.JobStateRunning, genai.JobStateQueued:
+		delete(metadata, "endTime")
+		operation["done"] = false
+	default:
+		metadata["endTime"] = metadata["updateTime"]
+		operation["done"] = true
+	}
+	return nil
+}
+
+// OnInteraction answers the Interactions.Create calls with interactions in
+// order, the last one answering all the calls after it. The interactions are
+// stored, with a new ID unless they have one, so that Interactions.Get
+// returns them.
+func (f *Fake) OnInteraction(interactions ...*genai.Interaction) {
+	next := 0
+	f.HandleFunc("POST interactions", func(call *Call) *Response {
+		if call.Stream || len(interactions) == 0 {
+			return nil
+		}
+		s := f.store
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		interaction := encode(interactions[min(next, len(interactions)-1)])
+		next++
+		s.storeInteraction(call, interaction)
+		return &Response{Body: interaction}
+	})
+}
+
+// OnInteractionStream answers every Interactions.CreateStream call with a
+// stream of events. The interactions of the events are stored so that
+// Interactions.Get returns them.
+func (f *Fake) OnInteractionStream(events ...*genai.InteractionEvent) {
+	f.HandleFunc("POST interactions", func(call *Call) *Response {
+		if !call.Stream {
+			return nil
+		}
+		s := f.store
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		stream := make([]any, len(events))
+		for i, event := range events {
+			stream[i] = event
+			if event != nil && event.Interaction != nil && event.Interaction.ID != "" {
+				s.storeInteraction(call, encode(event.Interaction))
+			}
+		}
+		return &Response{Stream: stream}
+	})
+}
+
+// storeInteraction completes interaction with the fields of the request
+// call that the service echoes, and stores it.
+func (s *store) storeInteraction(call *Call, interaction map[string]any) {
+	if id, _ := interaction["id"].(string); id == "" {
+		interaction["id"] = s.newID("interaction")
+	}
+	for _, key := range []string{"model", "agent", "previousInteractionId"} {
+		if _, ok := interaction[key]; !ok && call.Body[key] != nil {
+			interaction[key] = call.Body[key]
+		}
+	}
+	if _, ok := interaction["status"]; !ok {
+		interaction["status"] = string(genai.InteractionStatusCompleted)
+	}
+	now := timestamp(time.Now())
+	if _, ok := interaction["created"]; !ok {
+		interaction["created"] = now
+	}
+	interaction["updated"] = now
+	s.interactions.put("interactions/"+interaction["id"].(string), interaction)
+}
+
+func (s *store) cancelInteraction(call *Call) *Response {
+	name := strings.TrimSuffix(call.Path, "/cancel")
+	interaction, ok := s.interactions.get(name)
+	if !ok {
+		return notFound(name)
+	}
+	if interaction["status"] == string(genai.InteractionStatusInProgress) {
+		interaction["status"] = string(genai.InteractionStatusCancelled)
+		interaction["updated"] = timestamp(time.Now())
+	}
+	return &Response{Body: interaction}
+}
+
+// encode returns the JSON object of v.
+func encode(v any) map[string]any {
+	m := map[string]any{}
+	if data, err := json.Marshal(v); err == nil {
+		_ = json.Unmarshal(data, &m)
+	}
+	return m
+}
+
+// decode decodes the JSON object m into v.
+func decode(m map[string]any, v any) {
+	if data, err := json.Marshal(m); err == nil {
+		_ = json.Unmarshal(data, v)
+	}
+}
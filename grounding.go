@@ -0,0 +1,235 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GroundingSource is a source that grounds a response, e.g. a web page found
+// by Google Search, a retrieved document or a place of Google Maps.
+type GroundingSource struct {
+	// Title is the title of the source.
+	Title string
+	// URI is the URI of the source.
+	URI string
+	// Domain is the domain of a web source, e.g. "wikipedia.org".
+	Domain string
+}
+
+// GroundingCitation is a segment of the text of a response with the sources
+// that support it.
+type GroundingCitation struct {
+	// Text is the text of the segment.
+	Text string
+	// PartIndex is the index of the part that has the segment.
+	PartIndex int
+	// StartIndex and EndIndex are the byte offsets of the segment in the text
+	// of its part.
+	StartIndex, EndIndex int
+	// SourceIndices are the indices of the sources of the segment in the
+	// sources of the response, e.g. in GroundingSources.
+	SourceIndices []int
+	// Sources are the sources of the segment.
+	Sources []*GroundingSource
+	// ConfidenceScores are the confidences of the sources, from 0 to 1, if
+	// the backend reports them.
+	ConfidenceScores []float32
+}
+
+// CitationMarker returns the marker of a citation, inserted after its
+// segment by RenderCitations.
+type CitationMarker func(citation *GroundingCitation) string
+
+// MarkdownCitationMarker renders the sources of a citation as numbered
+// Markdown links, e.g. "[1](https://a.example), [3](https://b.example)",
+// numbered by their indices starting at 1.
+func MarkdownCitationMarker(citation *GroundingCitation) string {
+	links := make([]string, 0, len(citation.Sources))
+	for i, source := range citation.Sources {
+		if source == nil || source.URI == "" {
+			continue
+		}
+		links = append(links, fmt.Sprintf("[%d](%s)", citation.SourceIndices[i]+1, source.URI))
+	}
+	return strings.Join(links, ", ")
+}
+
+// RenderCitations returns text with the markers of citations inserted at the
+// end of their segments, e.g. to show the sources of a grounded response
+// inline. The markers default to MarkdownCitationMarker. Citations whose end
+// is out of the bounds of text are ignored, as are the part indices of the
+// citations; text must be the text of their part.
+func RenderCitations(text string, citations []*GroundingCitation, marker CitationMarker) string {
+	if marker == nil {
+		marker = MarkdownCitationMarker
+	}
+	var sorted []*GroundingCitation
+	for _, c := range citations {
+		if c != nil && c.EndIndex >= 0 && c.EndIndex <= len(text) {
+			sorted = append(sorted, c)
+		}
+	}
+	slices.SortStableFunc(sorted, func(a, b *GroundingCitation) int { return a.EndIndex - b.EndIndex })
+	var sb strings.Builder
+	last := 0
+	for _, c := range sorted {
+		sb.WriteString(text[last:c.EndIndex])
+		sb.WriteString(marker(c))
+		last = c.EndIndex
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// GroundingSources returns the sources of the grounding metadata of the first
+// candidate, e.g. the web pages found by Google Search, in the order of their
+// indices.
+func (r *GenerateContentResponse) GroundingSources() []*GroundingSource {
+	metadata := r.groundingMetadata()
+	if metadata == nil {
+		return nil
+	}
+	sources := make([]*GroundingSource, len(metadata.GroundingChunks))
+	for i, chunk := range metadata.GroundingChunks {
+		sources[i] = groundingSource(chunk)
+	}
+	return sources
+}
+
+// GroundingCitations returns the segments of the first candidate that are
+// supported by grounding sources, with their sources, in the order of the
+// grounding metadata.
+func (r *GenerateContentResponse) GroundingCitations() []*GroundingCitation {
+	metadata := r.groundingMetadata()
+	if metadata == nil {
+		return nil
+	}
+	sources := r.GroundingSources()
+	var citations []*GroundingCitation
+	for _, support := range metadata.GroundingSupports {
+		if support == nil || support.Segment == nil {
+			continue
+		}
+		c := &GroundingCitation{
+			Text:             support.Segment.Text,
+			PartIndex:        int(support.Segment.PartIndex),
+			StartIndex:       int(support.Segment.StartIndex),
+			EndIndex:         int(support.Segment.EndIndex),
+			ConfidenceScores: support.ConfidenceScores,
+		}
+		for _, i := range support.GroundingChunkIndices {
+			if int(i) < 0 || int(i) >= len(sources) {
+				continue
+			}
+			c.SourceIndices = append(c.SourceIndices, int(i))
+			c.Sources = append(c.Sources, sources[i])
+		}
+		citations = append(citations, c)
+	}
+	return citations
+}
+
+// WebSearchQueries returns the Google Search queries of the grounding
+// metadata of the first candidate.
+func (r *GenerateContentResponse) WebSearchQueries() []string {
+	if metadata := r.groundingMetadata(); metadata != nil {
+		return metadata.WebSearchQueries
+	}
+	return nil
+}
+
+// TextWithCitations returns the text of the first candidate, as Text does,
+// with the markers of its grounding citations inserted, see RenderCitations.
+func (r *GenerateContentResponse) TextWithCitations(marker CitationMarker) string {
+	citations := r.GroundingCitations()
+	var sb strings.Builder
+	for i, part := range r.firstCandidateParts() {
+		if part == nil || part.Thought || part.Text == "" {
+			continue
+		}
+		var partCitations []*GroundingCitation
+		for _, c := range citations {
+			if c.PartIndex == i {
+				partCitations = append(partCitations, c)
+			}
+		}
+		sb.WriteString(RenderCitations(part.Text, partCitations, marker))
+	}
+	return sb.String()
+}
+
+func (r *GenerateContentResponse) groundingMetadata() *GroundingMetadata {
+	if r == nil || len(r.Candidates) == 0 || r.Candidates[0] == nil {
+		return nil
+	}
+	return r.Candidates[0].GroundingMetadata
+}
+
+func groundingSource(chunk *GroundingChunk) *GroundingSource {
+	switch {
+	case chunk == nil:
+		return &GroundingSource{}
+	case chunk.Web != nil:
+		return &GroundingSource{Title: chunk.Web.Title, URI: chunk.Web.URI, Domain: chunk.Web.Domain}
+	case chunk.RetrievedContext != nil:
+		return &GroundingSource{Title: chunk.RetrievedContext.Title, URI: chunk.RetrievedContext.URI}
+	case chunk.Maps != nil:
+		return &GroundingSource{Title: chunk.Maps.Title, URI: chunk.Maps.URI}
+	}
+	return &GroundingSource{}
+}
+
+// GroundingCitations returns the annotations of a text content as citations
+// whose sources are numbered by their first appearance in the annotations.
+func (c *InteractionContent) GroundingCitations() []*GroundingCitation {
+	if c == nil {
+		return nil
+	}
+	indices := map[string]int{}
+	var citations []*GroundingCitation
+	for _, annotation := range c.Annotations {
+		if annotation == nil {
+			continue
+		}
+		i, ok := indices[annotation.Source]
+		if !ok {
+			i = len(indices)
+			indices[annotation.Source] = i
+		}
+		citation := &GroundingCitation{
+			StartIndex:    annotation.StartIndex,
+			EndIndex:      annotation.EndIndex,
+			SourceIndices: []int{i},
+			Sources:       []*GroundingSource{{URI: annotation.Source}},
+		}
+		if 0 <= annotation.StartIndex && annotation.StartIndex <= annotation.EndIndex && annotation.EndIndex <= len(c.Text) {
+			citation.Text = c.Text[annotation.StartIndex:annotation.EndIndex]
+		}
+		citations = append(citations, citation)
+	}
+	return citations
+}
+
+// TextWithCitations returns the text of a text content with the markers of
+// its annotations inserted, see RenderCitations.
+func (c *InteractionContent) TextWithCitations(marker CitationMarker) string {
+	if c == nil {
+		return ""
+	}
+	return RenderCitations(c.Text, c.GroundingCitations(), marker)
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGroundingCitations(t *testing.T) {
+	resp := &GenerateContentResponse{Candidates: []*Candidate{{
+		Content: &Content{Parts: []*Part{{Text: "Spain won Euro 2024. The final was in Berlin."}}},
+		GroundingMetadata: &GroundingMetadata{
+			WebSearchQueries: []string{"euro 2024 winner"},
+			GroundingChunks: []*GroundingChunk{
+				{Web: &GroundingChunkWeb{Title: "uefa.com", URI: "https://a.example", Domain: "uefa.com"}},
+				{Web: &GroundingChunkWeb{Title: "wikipedia.org", URI: "https://b.example"}},
+			},
+			GroundingSupports: []*GroundingSupport{
+				{Segment: &Segment{EndIndex: 20, Text: "Spain won Euro 2024."}, GroundingChunkIndices: []int32{0, 1}, ConfidenceScores: []float32{0.9, 0.8}},
+				{Segment: &Segment{StartIndex: 21, EndIndex: 45, Text: "The final was in Berlin."}, GroundingChunkIndices: []int32{1, 7}},
+			},
+		},
+	}}}
+
+	if diff := cmp.Diff([]string{"euro 2024 winner"}, resp.WebSearchQueries()); diff != "" {
+		t.Errorf("WebSearchQueries() mismatch (-want +got):\n%s", diff)
+	}
+	a := &GroundingSource{Title: "uefa.com", URI: "https://a.example", Domain: "uefa.com"}
+	b := &GroundingSource{Title: "wikipedia.org", URI: "https://b.example"}
+	if diff := cmp.Diff([]*GroundingSource{a, b}, resp.GroundingSources()); diff != "" {
+		t.Errorf("GroundingSources() mismatch (-want +got):\n%s", diff)
+	}
+	want := []*GroundingCitation{
+		{Text: "Spain won Euro 2024.", EndIndex: 20, SourceIndices: []int{0, 1}, Sources: []*GroundingSource{a, b}, ConfidenceScores: []float32{0.9, 0.8}},
+		{Text: "The final was in Berlin.", StartIndex: 21, EndIndex: 45, SourceIndices: []int{1}, Sources: []*GroundingSource{b}},
+	}
+	if diff := cmp.Diff(want, resp.GroundingCitations()); diff != "" {
+		t.Errorf("GroundingCitations() mismatch (-want +got):\n%s", diff)
+	}
+	wantText := "Spain won Euro 2024.[1](https://a.example), [2](https://b.example) The final was in Berlin.[2](https://b.example)"
+	if got := resp.TextWithCitations(nil); got != wantText {
+		t.Errorf("TextWithCitations() = %q, want %q", got, wantText)
+	}
+
+	var empty *GenerateContentResponse
+	if empty.GroundingCitations() != nil || empty.TextWithCitations(nil) != "" {
+		t.Error("accessors of a nil response returned values, want none")
+	}
+}
+
+func TestInteractionContentCitations(t *testing.T) {
+	content := &InteractionContent{
+		Type: InteractionContentTypeText,
+		Text: "Go is fast. Go is simple.",
+		Annotations: []*InteractionAnnotation{
+			{EndIndex: 11, Source: "https://go.dev"},
+			{StartIndex: 12, EndIndex: 25, Source: "https://go.dev"},
+			{StartIndex: 12, EndIndex: 25, Source: "https://example.com"},
+		},
+	}
+	marker := func(c *GroundingCitation) string { return c.Sources[0].URI[8:10] }
+	if got, want := content.TextWithCitations(marker), "Go is fast.go Go is simple.goex"; got != want {
+		t.Errorf("TextWithCitations() = %q, want %q", got, want)
+	}
+	citations := content.GroundingCitations()
+	var got [][]int
+	for _, c := range citations {
+		got = append(got, c.SourceIndices)
+	}
+	if diff := cmp.Diff([][]int{{0}, {0}, {1}}, got); diff != "" {
+		t.Errorf("source indices mismatch (-want +got):\n%s", diff)
+	}
+	if citations[1].Text != "Go is simple." {
+		t.Errorf("Text = %q, want %q", citations[1].Text, "Go is simple.")
+	}
+}
@@ -0,0 +1,643 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// GuardrailAction is what a guardrail does with the text it checks.
+type GuardrailAction string
+
+const (
+	// GuardrailAllow lets the text pass unchanged.
+	GuardrailAllow GuardrailAction = ""
+	// GuardrailBlock rejects the text; the call returns a *GuardrailError.
+	GuardrailBlock GuardrailAction = "block"
+	// GuardrailRewrite replaces the text with GuardrailVerdict.Rewrite.
+	GuardrailRewrite GuardrailAction = "rewrite"
+	// GuardrailAnnotate lets the text pass unchanged and reports the verdict to
+	// Guardrails.OnEvent.
+	GuardrailAnnotate GuardrailAction = "annotate"
+)
+
+// GuardrailStage is where a guardrail runs.
+type GuardrailStage string
+
+const (
+	// GuardrailStageInput checks the user content sent to the model.
+	GuardrailStageInput GuardrailStage = "input"
+	// GuardrailStageOutput checks the text generated by the model.
+	GuardrailStageOutput GuardrailStage = "output"
+)
+
+// GuardrailVerdict is the result of a guardrail check.
+type GuardrailVerdict struct {
+	// The action to take. GuardrailAllow lets the text pass.
+	Action GuardrailAction
+	// Optional. Why the guardrail took the action.
+	Reason string
+	// The replacement text if Action is GuardrailRewrite.
+	Rewrite string
+}
+
+// Guardrail checks a piece of text sent to or generated by the model.
+//
+// Implementations must be safe for concurrent use.
+type Guardrail interface {
+	// Check returns the verdict for text. A nil verdict lets the text pass. An
+	// error fails the call that is being checked.
+	Check(ctx context.Context, text string) (*GuardrailVerdict, error)
+}
+
+// GuardrailFunc adapts a function to the Guardrail interface.
+type GuardrailFunc func(ctx context.Context, text string) (*GuardrailVerdict, error)
+
+// Check calls f.
+func (f GuardrailFunc) Check(ctx context.Context, text string) (*GuardrailVerdict, error) {
+	return f(ctx, text)
+}
+
+// GuardrailEvent reports a verdict other than GuardrailAllow.
+type GuardrailEvent struct {
+	// Stage is where the guardrail ran.
+	Stage GuardrailStage
+	// Verdict is the verdict of the guardrail.
+	Verdict *GuardrailVerdict
+	// Text is the checked text, before any rewrite.
+	Text string
+}
+
+// Guardrails are the validators applied to the calls of a client.
+//
+// Input guardrails check the text parts of the last user content of
+// Models.GenerateContent and GenerateContentStream calls (which includes the
+// messages sent by Chats; the chat history keeps the rewritten message), and
+// the text of the Interaction input of Interactions.Create and CreateStream.
+// Output guardrails check the text parts of each response candidate, and the
+// text outputs of interactions.
+//
+// For streams, output guardrails check the text streamed so far by each
+// candidate or interaction output whenever a chunk or text delta arrives, so
+// that a match that spans chunks is caught. A block fails the stream at the
+// chunk that completes the match. A rewrite applies to the text that was not
+// streamed yet; a rewrite that would change text already streamed, e.g. of a
+// deny-listed word split across chunks, fails the stream with a
+// *GuardrailError instead. Guardrails that can only check complete text, such
+// as NewSchemaGuardrail, fail streams before they are sent, and guardrails
+// that call a model, such as NewJudgeGuardrail, are called for every chunk, so
+// both are better used on non-streaming calls.
+//
+// Guardrails run in order; each sees the text as rewritten by the previous
+// ones. The first block stops the call with a *GuardrailError.
+type Guardrails struct {
+	// Optional. The guardrails applied to the user content sent to the model.
+	Input []Guardrail
+	// Optional. The guardrails applied to the text generated by the model.
+	Output []Guardrail
+	// Optional. Called for every verdict other than GuardrailAllow, e.g. for
+	// auditing or to read the annotations of GuardrailAnnotate.
+	OnEvent func(e *GuardrailEvent)
+}
+
+// GuardrailError is returned by a call that a guardrail blocked.
+type GuardrailError struct {
+	// Stage is where the guardrail ran.
+	Stage GuardrailStage
+	// Reason is the reason of the verdict.
+	Reason string
+}
+
+// Error returns a string representation of the GuardrailError.
+func (e *GuardrailError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%s blocked by guardrail", e.Stage)
+	}
+	return fmt.Sprintf("%s blocked by guardrail: %s", e.Stage, e.Reason)
+}
+
+type skipInputGuardrailsKey struct{}
+
+type skipGuardrailsKey struct{}
+
+// withoutGuardrails returns a context in which the client's guardrails are not
+// applied, e.g. for the calls made by a judge guardrail.
+func withoutGuardrails(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipGuardrailsKey{}, true)
+}
+
+// withoutInputGuardrails returns a context in which input guardrails are not
+// applied because the caller already applied them.
+func withoutInputGuardrails(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipInputGuardrailsKey{}, true)
+}
+
+// guardrails returns the guardrails of the stage that apply in ctx.
+func (ac *apiClient) guardrails(ctx context.Context, stage GuardrailStage) []Guardrail {
+	g := ac.clientConfig.Guardrails
+	if g == nil || ctx.Value(skipGuardrailsKey{}) != nil {
+		return nil
+	}
+	if stage == GuardrailStageInput {
+		if ctx.Value(skipInputGuardrailsKey{}) != nil {
+			return nil
+		}
+		return g.Input
+	}
+	return g.Output
+}
+
+// guardText runs the guardrails of the stage on text and returns the text to
+// use.
+func (ac *apiClient) guardText(ctx context.Context, stage GuardrailStage, text string) (string, error) {
+	return ac.checkText(ctx, stage, text, func(int, *GuardrailVerdict) bool { return true })
+}
+
+// checkText runs the guardrails of the stage on text and returns the text to
+// use, reporting the verdicts of the i-th guardrail for which report returns
+// true to Guardrails.OnEvent.
+func (ac *apiClient) checkText(ctx context.Context, stage GuardrailStage, text string, report func(i int, verdict *GuardrailVerdict) bool) (string, error) {
+	guardrails := ac.guardrails(ctx, stage)
+	if text == "" || len(guardrails) == 0 {
+		return text, nil
+	}
+	for i, g := range guardrails {
+		verdict, err := g.Check(withoutGuardrails(ctx), text)
+		if err != nil {
+			return "", fmt.Errorf("%s guardrail failed: %w", stage, err)
+		}
+		if verdict == nil || verdict.Action == GuardrailAllow {
+			continue
+		}
+		if onEvent := ac.clientConfig.Guardrails.OnEvent; onEvent != nil && report(i, verdict) {
+			onEvent(&GuardrailEvent{Stage: stage, Verdict: verdict, Text: text})
+		}
+		switch verdict.Action {
+		case GuardrailBlock:
+			return "", &GuardrailError{Stage: stage, Reason: verdict.Reason}
+		case GuardrailRewrite:
+			text = verdict.Rewrite
+		}
+	}
+	return text, nil
+}
+
+// guardContent runs the guardrails of the stage on the text parts of content.
+// It returns content unchanged if no part is rewritten, and a copy otherwise.
+func (ac *apiClient) guardContent(ctx context.Context, stage GuardrailStage, content *Content) (*Content, error) {
+	if content == nil || len(ac.guardrails(ctx, stage)) == 0 {
+		return content, nil
+	}
+	var guarded *Content
+	for i, p := range content.Parts {
+		if p == nil || p.Text == "" || p.Thought {
+			continue
+		}
+		text, err := ac.guardText(ctx, stage, p.Text)
+		if err != nil {
+			return nil, err
+		}
+		if text == p.Text {
+			continue
+		}
+		if guarded == nil {
+			guarded = &Content{Role: content.Role, Parts: slices.Clone(content.Parts)}
+		}
+		part := *p
+		part.Text = text
+		guarded.Parts[i] = &part
+	}
+	if guarded == nil {
+		return content, nil
+	}
+	return guarded, nil
+}
+
+// guardrailsHook runs the guardrails on the generateContent and
+// streamGenerateContent calls of Models: the input guardrails on the last
+// user content of the request, and the output guardrails on the candidates of
+// the response, or on the text streamed by the candidates of a stream.
+func (ac *apiClient) guardrailsHook(ctx context.Context, call *hookedCall) error {
+	if call.body == nil || !call.isMethod("generateContent", "streamGenerateContent") {
+		return nil
+	}
+	var guard *streamGuard
+	if call.isMethod("streamGenerateContent") {
+		var err error
+		if guard, err = ac.newStreamGuard(ctx); err != nil {
+			return err
+		}
+	}
+	contents := objectsOf(call.body["contents"])
+	for i := len(contents) - 1; i >= 0; i-- {
+		if role, _ := contents[i]["role"].(string); role == RoleUser || role == "" {
+			if err := ac.guardContentMap(ctx, GuardrailStageInput, contents[i]); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	switch {
+	case guard != nil:
+		call.onResponse = append(call.onResponse, guard.guardCandidates)
+	case len(ac.guardrails(ctx, GuardrailStageOutput)) > 0:
+		call.onResponse = append(call.onResponse, func(response map[string]any) error {
+			for _, candidate := range objectsOf(response["candidates"]) {
+				content, _ := candidate["content"].(map[string]any)
+				if err := ac.guardContentMap(ctx, GuardrailStageOutput, content); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+	return nil
+}
+
+// guardContentMap runs the guardrails of the stage on the text parts of
+// content, a content of a request body or response map, in place.
+func (ac *apiClient) guardContentMap(ctx context.Context, stage GuardrailStage, content map[string]any) error {
+	if len(ac.guardrails(ctx, stage)) == 0 {
+		return nil
+	}
+	for _, part := range objectsOf(content["parts"]) {
+		text, _ := part["text"].(string)
+		if thought, _ := part["thought"].(bool); text == "" || thought {
+			continue
+		}
+		text, err := ac.guardText(ctx, stage, text)
+		if err != nil {
+			return err
+		}
+		part["text"] = text
+	}
+	return nil
+}
+
+// streamGuard runs the output guardrails on the text of a stream. Each piece
+// of text is checked together with the text streamed before it at the same
+// index, that of a candidate or of an interaction output, so that guardrails
+// see the matches that span chunks.
+type streamGuard struct {
+	ac  *apiClient
+	ctx context.Context
+	// received is the text received at each index, and streamed the text
+	// passed on after the guardrails.
+	received map[int]string
+	streamed map[int]string
+	// reported are the actions and reasons of the verdicts already reported
+	// to Guardrails.OnEvent, by index and guardrail, which are not reported
+	// again for every chunk.
+	reported map[[2]int][2]string
+}
+
+// newStreamGuard returns the streamGuard of a stream, or nil if no output
+// guardrails apply in ctx. It returns an error if a guardrail can only check
+// complete text.
+func (ac *apiClient) newStreamGuard(ctx context.Context) (*streamGuard, error) {
+	guardrails := ac.guardrails(ctx, GuardrailStageOutput)
+	if len(guardrails) == 0 {
+		return nil, nil
+	}
+	for _, g := range guardrails {
+		if g, ok := g.(*completeTextGuardrail); ok {
+			return nil, fmt.Errorf("the output guardrail %s can only check complete text and does not apply to streams; use a non-streaming call", g.name)
+		}
+	}
+	return &streamGuard{
+		ac:       ac,
+		ctx:      ctx,
+		received: map[int]string{},
+		streamed: map[int]string{},
+		reported: map[[2]int][2]string{},
+	}, nil
+}
+
+// guard runs the output guardrails on the text streamed at index followed by
+// text, and returns the part of the guarded text to stream in place of text.
+func (g *streamGuard) guard(index int, text string) (string, error) {
+	if text == "" {
+		return text, nil
+	}
+	g.received[index] += text
+	guarded, err := g.ac.checkText(g.ctx, GuardrailStageOutput, g.received[index], func(i int, verdict *GuardrailVerdict) bool {
+		key, value := [2]int{index, i}, [2]string{string(verdict.Action), verdict.Reason}
+		if last, ok := g.reported[key]; ok && last == value {
+			return false
+		}
+		g.reported[key] = value
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	streamed := g.streamed[index]
+	rest, ok := strings.CutPrefix(guarded, streamed)
+	if !ok {
+		return "", &GuardrailError{Stage: GuardrailStageOutput, Reason: "a guardrail rewrote text that was already streamed"}
+	}
+	g.streamed[index] = guarded
+	return rest, nil
+}
+
+// guardCandidates runs the output guardrails on the text parts of the
+// candidates of a chunk of a stream of Models, in place.
+func (g *streamGuard) guardCandidates(response map[string]any) error {
+	for _, candidate := range objectsOf(response["candidates"]) {
+		index, _ := candidate["index"].(float64)
+		content, _ := candidate["content"].(map[string]any)
+		for _, part := range objectsOf(content["parts"]) {
+			text, _ := part["text"].(string)
+			if thought, _ := part["thought"].(bool); text == "" || thought {
+				continue
+			}
+			text, err := g.guard(int(index), text)
+			if err != nil {
+				return err
+			}
+			part["text"] = text
+		}
+	}
+	return nil
+}
+
+// guardEvent runs the output guardrails on the text delta or the
+// interaction of a stream event of Interactions, in place.
+func (g *streamGuard) guardEvent(event *InteractionEvent) error {
+	if g == nil || event == nil {
+		return nil
+	}
+	if event.Delta != nil && event.Delta.Type == "text" {
+		text, err := g.guard(event.Index, event.Delta.Text)
+		if err != nil {
+			return err
+		}
+		event.Delta.Text = text
+	}
+	return g.ac.guardInteraction(g.ctx, event.Interaction)
+}
+
+// guardInteractionRequest runs the input guardrails on the input of
+// interaction and returns the interaction to send, a copy if guardrails apply.
+func (ac *apiClient) guardInteractionRequest(ctx context.Context, interaction *Interaction) (*Interaction, error) {
+	if interaction == nil || len(ac.guardrails(ctx, GuardrailStageInput)) == 0 {
+		return interaction, nil
+	}
+	input, err := ac.guardInteractionInput(ctx, interaction.Input)
+	if err != nil {
+		return nil, err
+	}
+	guarded := *interaction
+	guarded.Input = input
+	return &guarded, nil
+}
+
+// guardInteractionInput runs the input guardrails on the text of an
+// Interaction input and returns the input to send.
+func (ac *apiClient) guardInteractionInput(ctx context.Context, input any) (any, error) {
+	switch in := input.(type) {
+	case string:
+		return ac.guardText(ctx, GuardrailStageInput, in)
+	case *InteractionContent:
+		guarded, err := ac.guardInteractionContents(ctx, GuardrailStageInput, []*InteractionContent{in})
+		if err != nil {
+			return nil, err
+		}
+		return guarded[0], nil
+	case []*InteractionContent:
+		return ac.guardInteractionContents(ctx, GuardrailStageInput, in)
+	}
+	return input, nil
+}
+
+// guardInteractionContents runs the guardrails of the stage on the text
+// contents, returning a copy of contents if any is rewritten.
+func (ac *apiClient) guardInteractionContents(ctx context.Context, stage GuardrailStage, contents []*InteractionContent) ([]*InteractionContent, error) {
+	if len(ac.guardrails(ctx, stage)) == 0 {
+		return contents, nil
+	}
+	guarded, copied := contents, false
+	for i, c := range contents {
+		if c == nil || c.Type != "text" || c.Text == "" {
+			continue
+		}
+		text, err := ac.guardText(ctx, stage, c.Text)
+		if err != nil {
+			return nil, err
+		}
+		if text == c.Text {
+			continue
+		}
+		if !copied {
+			guarded, copied = slices.Clone(contents), true
+		}
+		content := *c
+		content.Text = text
+		guarded[i] = &content
+	}
+	return guarded, nil
+}
+
+// guardInteraction runs the output guardrails on the outputs of interaction,
+// in place.
+func (ac *apiClient) guardInteraction(ctx context.Context, interaction *Interaction) error {
+	if interaction == nil {
+		return nil
+	}
+	outputs, err := ac.guardInteractionContents(ctx, GuardrailStageOutput, interaction.Outputs)
+	if err != nil {
+		return err
+	}
+	interaction.Outputs = outputs
+	return nil
+}
+
+// NewRegexGuardrail returns a Guardrail that takes action on text matching any
+// of patterns. With GuardrailRewrite, every match is replaced with replacement,
+// e.g. to redact email addresses.
+func NewRegexGuardrail(action GuardrailAction, replacement string, patterns ...*regexp.Regexp) Guardrail {
+	return GuardrailFunc(func(ctx context.Context, text string) (*GuardrailVerdict, error) {
+		var matched []string
+		rewritten := text
+		for _, re := range patterns {
+			if re.MatchString(rewritten) {
+				matched = append(matched, re.String())
+				rewritten = re.ReplaceAllString(rewritten, replacement)
+			}
+		}
+		if len(matched) == 0 {
+			return nil, nil
+		}
+		return &GuardrailVerdict{
+			Action:  action,
+			Reason:  "matches " + strings.Join(matched, ", "),
+			Rewrite: rewritten,
+		}, nil
+	})
+}
+
+// NewDenyListGuardrail returns a Guardrail that takes action on text that
+// contains any of words, ignoring case. With GuardrailRewrite, the words are
+// replaced with "[redacted]".
+func NewDenyListGuardrail(action GuardrailAction, words ...string) Guardrail {
+	patterns := make([]*regexp.Regexp, len(words))
+	for i, w := range words {
+		patterns[i] = regexp.MustCompile(`(?i)` + regexp.QuoteMeta(w))
+	}
+	return NewRegexGuardrail(action, "[redacted]", patterns...)
+}
+
+// completeTextGuardrail is a guardrail that can only check complete text,
+// which streams do not apply.
+type completeTextGuardrail struct {
+	GuardrailFunc
+	// name describes the guardrail in the errors of streams.
+	name string
+}
+
+// NewSchemaGuardrail returns a Guardrail that blocks text that is not a JSON
+// value matching schema. Only type, enum, nullable, required, properties,
+// items and anyOf are checked. A nil schema only checks that the text is
+// valid JSON. It is meant for the output of non-streaming calls with a JSON
+// response MIME type; streams that it checks fail, as their partial text is
+// not valid JSON.
+func NewSchemaGuardrail(schema *Schema) Guardrail {
+	return &completeTextGuardrail{name: "NewSchemaGuardrail", GuardrailFunc: func(ctx context.Context, text string) (*GuardrailVerdict, error) {
+		var v any
+		if err := json.Unmarshal([]byte(text), &v); err != nil {
+			return &GuardrailVerdict{Action: GuardrailBlock, Reason: fmt.Sprintf("invalid JSON: %v", err)}, nil
+		}
+		if err := checkSchema(v, schema, "$"); err != nil {
+			return &GuardrailVerdict{Action: GuardrailBlock, Reason: err.Error()}, nil
+		}
+		return nil, nil
+	}}
+}
+
+func checkSchema(v any, s *Schema, path string) error {
+	if s == nil {
+		return nil
+	}
+	if v == nil {
+		if (s.Nullable != nil && *s.Nullable) || s.Type == TypeNULL || s.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s: must not be null", path)
+	}
+	if len(s.AnyOf) > 0 {
+		for _, alt := range s.AnyOf {
+			if checkSchema(v, alt, path) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: matches none of anyOf", path)
+	}
+	switch s.Type {
+	case TypeString:
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: must be a string", path)
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, str) {
+			return fmt.Errorf("%s: must be one of %s", path, strings.Join(s.Enum, ", "))
+		}
+	case TypeNumber:
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s: must be a number", path)
+		}
+	case TypeInteger:
+		if f, ok := v.(float64); !ok || f != float64(int64(f)) {
+			return fmt.Errorf("%s: must be an integer", path)
+		}
+	case TypeBoolean:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: must be a boolean", path)
+		}
+	case TypeArray:
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: must be an array", path)
+		}
+		for i, item := range items {
+			if err := checkSchema(item, s.Items, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case TypeObject:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: must be an object", path)
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s.%s: is required", path, name)
+			}
+		}
+		for _, name := range sortedKeys(obj) {
+			if err := checkSchema(obj[name], s.Properties[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NewJudgeGuardrail returns a Guardrail that asks model whether the text
+// violates policy, a description of what is not allowed, and takes action if
+// it does. With GuardrailRewrite, the judge is asked for a compliant rewrite.
+// The calls of the judge are not checked by the client's guardrails.
+func NewJudgeGuardrail(client *Client, model, policy string, action GuardrailAction) Guardrail {
+	return GuardrailFunc(func(ctx context.Context, text string) (*GuardrailVerdict, error) {
+		prompt := fmt.Sprintf("Policy:\n%s\n\nText:\n%s\n\nDoes the text violate the policy? "+
+			`Answer with a JSON object {"violates": boolean, "reason": string, "rewrite": string}, where rewrite `+
+			"is the text changed as little as possible to comply with the policy.", policy, text)
+		resp, err := client.Models.GenerateContent(withoutGuardrails(ctx), model, Text(prompt), &GenerateContentConfig{
+			ResponseMIMEType: "application/json",
+			ResponseSchema: &Schema{
+				Type: TypeObject,
+				Properties: map[string]*Schema{
+					"violates": {Type: TypeBoolean},
+					"reason":   {Type: TypeString},
+					"rewrite":  {Type: TypeString},
+				},
+				Required: []string{"violates"},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		var judgement struct {
+			Violates bool   `json:"violates"`
+			Reason   string `json:"reason"`
+			Rewrite  string `json:"rewrite"`
+		}
+		if err := json.Unmarshal([]byte(resp.Text()), &judgement); err != nil {
+			return nil, fmt.Errorf("invalid judge response: %w", err)
+		}
+		if !judgement.Violates {
+			return nil, nil
+		}
+		if action == GuardrailRewrite && judgement.Rewrite == "" {
+			return nil, errors.New("the judge did not return a rewrite")
+		}
+		return &GuardrailVerdict{Action: action, Reason: judgement.Reason, Rewrite: judgement.Rewrite}, nil
+	})
+}
@@ -0,0 +1,295 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// guardrailServer answers generateContent calls with reply(prompt), interaction
+// calls with an interaction echoing the input, and records the prompts.
+func guardrailServer(t *testing.T, guardrails *Guardrails, reply func(prompt string) string) (*Client, *[]string) {
+	t.Helper()
+	var prompts []string
+	client := newTestClient(t, &ClientConfig{Guardrails: guardrails}, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/interactions") {
+			var req struct {
+				Input string `json:"input"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			prompts = append(prompts, req.Input)
+			fmt.Fprintf(w, `{"id": "1", "status": "completed", "outputs": [{"type": "text", "text": %q}]}`, reply(req.Input))
+			return
+		}
+		var req struct {
+			Contents []*Content `json:"contents"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		prompt := contentText(req.Contents[len(req.Contents)-1])
+		prompts = append(prompts, prompt)
+		fmt.Fprintf(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": %q}]}, "finishReason": "STOP"}]}`, reply(prompt))
+	})
+	return client, &prompts
+}
+
+func TestGuardrails(t *testing.T) {
+	ctx := context.Background()
+	var events []*GuardrailEvent
+	email := regexp.MustCompile(`[\w.]+@[\w.]+`)
+	guardrails := &Guardrails{
+		Input:   []Guardrail{NewRegexGuardrail(GuardrailRewrite, "[email]", email)},
+		Output:  []Guardrail{NewDenyListGuardrail(GuardrailBlock, "secret"), NewDenyListGuardrail(GuardrailAnnotate, "maybe")},
+		OnEvent: func(e *GuardrailEvent) { events = append(events, e) },
+	}
+	client, prompts := guardrailServer(t, guardrails, func(prompt string) string {
+		if strings.Contains(prompt, "password") {
+			return "The SECRET is 42."
+		}
+		return "Maybe. You wrote: " + prompt
+	})
+
+	resp, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Mail ana@example.com"), nil)
+	if err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	if got := (*prompts)[0]; got != "Mail [email]" {
+		t.Errorf("sent prompt = %q, want the rewritten prompt", got)
+	}
+	if resp.Text() != "Maybe. You wrote: Mail [email]" {
+		t.Errorf("response = %q, want the annotated response unchanged", resp.Text())
+	}
+	if len(events) != 2 || events[0].Stage != GuardrailStageInput || events[1].Verdict.Action != GuardrailAnnotate {
+		t.Errorf("events = %+v, want a rewrite and an annotation", events)
+	}
+
+	_, err = client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("What is the password?"), nil)
+	var ge *GuardrailError
+	if !errors.As(err, &ge) || ge.Stage != GuardrailStageOutput {
+		t.Errorf("GenerateContent() error = %v, want an output GuardrailError", err)
+	}
+	for _, err := range client.Models.GenerateContentStream(ctx, "gemini-2.5-flash", Text("What is the password?"), nil) {
+		if !errors.As(err, &ge) {
+			t.Errorf("GenerateContentStream() error = %v, want a GuardrailError", err)
+		}
+	}
+
+	chat, err := client.Chats.Create(ctx, "gemini-2.5-flash", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chat.SendMessage(ctx, Part{Text: "I am bob@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := contentText(chat.History(true)[0]); got != "I am [email]" {
+		t.Errorf("chat history message = %q, want the rewritten message", got)
+	}
+
+	interaction, err := client.Interactions.Create(ctx, &Interaction{Model: "gemini-2.5-flash", Input: "Hi, I am eve@example.com"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := interaction.Outputs[0].Text; got != "Maybe. You wrote: Hi, I am [email]" {
+		t.Errorf("interaction output = %q, want the rewritten input echoed", got)
+	}
+	_, err = client.Interactions.Create(ctx, &Interaction{Model: "gemini-2.5-flash", Input: "password"}, nil)
+	if !errors.As(err, &ge) {
+		t.Errorf("Interactions.Create() error = %v, want a GuardrailError", err)
+	}
+}
+
+func TestJudgeGuardrail(t *testing.T) {
+	ctx := context.Background()
+	judge := &Guardrails{}
+	client, prompts := guardrailServer(t, judge, func(prompt string) string {
+		if strings.HasPrefix(prompt, "Policy:") {
+			if strings.Contains(prompt, "stupid") {
+				return `{"violates": true, "reason": "insult", "rewrite": "You are wrong."}`
+			}
+			return `{"violates": false}`
+		}
+		return "You are stupid."
+	})
+	judge.Output = []Guardrail{NewJudgeGuardrail(client, "gemini-2.5-flash", "No insults.", GuardrailRewrite)}
+
+	resp, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Am I right?"), nil)
+	if err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	if resp.Text() != "You are wrong." {
+		t.Errorf("response = %q, want the judge's rewrite", resp.Text())
+	}
+	// The judge call itself is not checked by the judge.
+	if len(*prompts) != 2 {
+		t.Errorf("got %d model calls, want 2", len(*prompts))
+	}
+}
+
+func TestSchemaGuardrail(t *testing.T) {
+	ctx := context.Background()
+	g := NewSchemaGuardrail(&Schema{
+		Type:     TypeObject,
+		Required: []string{"name"},
+		Properties: map[string]*Schema{
+			"name": {Type: TypeString},
+			"tags": {Type: TypeArray, Items: &Schema{Type: TypeString, Enum: []string{"a", "b"}}},
+		},
+	})
+	tests := []struct {
+		text       string
+		wantReason string
+	}{
+		{text: `{"name": "x", "tags": ["a"]}`},
+		{text: `not json`, wantReason: "invalid JSON"},
+		{text: `{"tags": []}`, wantReason: "$.name: is required"},
+		{text: `{"name": 1}`, wantReason: "$.name: must be a string"},
+		{text: `{"name": "x", "tags": ["c"]}`, wantReason: "$.tags[0]: must be one of a, b"},
+	}
+	for _, tt := range tests {
+		verdict, err := g.Check(ctx, tt.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case tt.wantReason == "" && verdict != nil:
+			t.Errorf("Check(%s) = %+v, want no verdict", tt.text, verdict)
+		case tt.wantReason != "" && (verdict == nil || verdict.Action != GuardrailBlock || !strings.Contains(verdict.Reason, tt.wantReason)):
+			t.Errorf("Check(%s) = %+v, want a block with %q", tt.text, verdict, tt.wantReason)
+		}
+	}
+}
+
+func TestGuardrailsStream(t *testing.T) {
+	ctx := context.Background()
+	email := regexp.MustCompile(`[\w.]+@[\w.]+`)
+	tests := []struct {
+		name      string
+		output    []Guardrail
+		chunks    []string
+		want      string
+		wantErr   string
+		wantSent  bool
+		wantEvent int
+	}{
+		{
+			name:      "block across chunks",
+			output:    []Guardrail{NewDenyListGuardrail(GuardrailBlock, "secret")},
+			chunks:    []string{"The sec", "ret is 42."},
+			want:      "The sec",
+			wantErr:   "output blocked by guardrail",
+			wantSent:  true,
+			wantEvent: 1,
+		},
+		{
+			name:      "rewrite across chunks",
+			output:    []Guardrail{NewRegexGuardrail(GuardrailRewrite, "[email]", email)},
+			chunks:    []string{"Mail ana@exa", "mple.com now"},
+			want:      "Mail [email] now",
+			wantSent:  true,
+			wantEvent: 1,
+		},
+		{
+			name:      "rewrite of streamed text",
+			output:    []Guardrail{NewDenyListGuardrail(GuardrailRewrite, "secret")},
+			chunks:    []string{"The sec", "ret is 42."},
+			want:      "The sec",
+			wantErr:   "rewrote text that was already streamed",
+			wantSent:  true,
+			wantEvent: 1,
+		},
+		{
+			name:      "annotation reported once",
+			output:    []Guardrail{NewDenyListGuardrail(GuardrailAnnotate, "maybe")},
+			chunks:    []string{"Maybe", " so", "."},
+			want:      "Maybe so.",
+			wantSent:  true,
+			wantEvent: 1,
+		},
+		{
+			name:    "complete text only",
+			output:  []Guardrail{NewSchemaGuardrail(nil)},
+			chunks:  []string{`{"a":`, ` 1}`},
+			wantErr: "NewSchemaGuardrail can only check complete text",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []*GuardrailEvent
+			sent := false
+			client := newTestClient(t, &ClientConfig{Guardrails: &Guardrails{
+				Output:  tt.output,
+				OnEvent: func(e *GuardrailEvent) { events = append(events, e) },
+			}}, func(w http.ResponseWriter, r *http.Request) {
+				sent = true
+				w.Header().Set("Content-Type", "text/event-stream")
+				if strings.HasSuffix(r.URL.Path, "/interactions") {
+					for _, chunk := range tt.chunks {
+						fmt.Fprintf(w, "data: {\"event_type\": \"content.delta\", \"index\": 1, \"delta\": {\"type\": \"text\", \"text\": %q}}\n\n", chunk)
+					}
+					return
+				}
+				for _, chunk := range tt.chunks {
+					fmt.Fprintf(w, "data: {\"candidates\": [{\"content\": {\"role\": \"model\", \"parts\": [{\"text\": %q}]}}]}\n\n", chunk)
+				}
+			})
+			check := func(method, got string, err error) {
+				t.Helper()
+				if got != tt.want {
+					t.Errorf("%s() streamed %q, want %q", method, got, tt.want)
+				}
+				switch {
+				case tt.wantErr == "" && err != nil:
+					t.Errorf("%s() error = %v, want nil", method, err)
+				case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+					t.Errorf("%s() error = %v, want an error with %q", method, err, tt.wantErr)
+				}
+				if sent != tt.wantSent {
+					t.Errorf("%s() sent the request = %v, want %v", method, sent, tt.wantSent)
+				}
+				if len(events) != tt.wantEvent {
+					t.Errorf("%s() reported %d events, want %d", method, len(events), tt.wantEvent)
+				}
+				sent, events = false, nil
+			}
+
+			var got string
+			var err error
+			for resp, e := range client.Models.GenerateContentStream(ctx, "gemini-2.5-flash", Text("Hi"), nil) {
+				if err = e; err != nil {
+					break
+				}
+				got += resp.Text()
+			}
+			check("GenerateContentStream", got, err)
+
+			got, err = "", nil
+			for event, e := range client.Interactions.CreateStream(ctx, &Interaction{Model: "gemini-2.5-flash", Input: "Hi"}, nil) {
+				if err = e; err != nil {
+					break
+				}
+				if event.Delta != nil {
+					got += event.Delta.Text
+				}
+			}
+			check("Interactions.CreateStream", got, err)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// HTTPClientOptions configures the transport of the HTTP client that
+// NewClient creates, e.g. to go through a corporate proxy or to trust a
+// private certificate authority. The transport is also used to connect Live
+// sessions. Unset fields keep the defaults of http.DefaultTransport.
+type HTTPClientOptions struct {
+	// Optional. The URL of the proxy of all requests, e.g.
+	// "http://proxy.example.com:3128". If empty, the proxy is taken from the
+	// HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+	ProxyURL string
+	// Optional. Dials the connections of the client, e.g. through a tunnel.
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	// Optional. The TLS config of the connections. CAFile and
+	// ClientCertFile are added to a copy of it.
+	TLSConfig *tls.Config
+	// Optional. The path of a PEM bundle of the certificate authorities
+	// trusted in addition to the system ones.
+	CAFile string
+	// Optional. The paths of the PEM certificate and key that the client
+	// presents to the server for mutual TLS. Both must be set.
+	ClientCertFile string
+	ClientKeyFile  string
+	// Optional. The maximum number of idle connections, in total and per
+	// host, and the maximum number of connections per host. Zero keeps the
+	// default.
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	// Optional. How long idle connections are kept open. Zero keeps the
+	// default.
+	IdleConnTimeout time.Duration
+}
+
+// transport returns the HTTP transport configured by o.
+func (o *HTTPClientOptions) transport() (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if o.ProxyURL != "" {
+		proxy, err := url.Parse(o.ProxyURL)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: %v", o.ProxyURL, err)
+		}
+		t.Proxy = http.ProxyURL(proxy)
+	}
+	if o.DialContext != nil {
+		t.DialContext = o.DialContext
+	}
+	if o.TLSConfig != nil || o.CAFile != "" || o.ClientCertFile != "" || o.ClientKeyFile != "" {
+		tlsConfig, err := o.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		t.TLSClientConfig = tlsConfig
+	}
+	if o.MaxIdleConns > 0 {
+		t.MaxIdleConns = o.MaxIdleConns
+	}
+	if o.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+	}
+	if o.MaxConnsPerHost > 0 {
+		t.MaxConnsPerHost = o.MaxConnsPerHost
+	}
+	if o.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = o.IdleConnTimeout
+	}
+	return t, nil
+}
+
+func (o *HTTPClientOptions) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if o.TLSConfig != nil {
+		tlsConfig = o.TLSConfig.Clone()
+	}
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA file: %w", err)
+		}
+		pool := tlsConfig.RootCAs
+		if pool == nil {
+			if pool, err = x509.SystemCertPool(); err != nil {
+				pool = x509.NewCertPool()
+			}
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("the CA file %s has no PEM certificates", o.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if o.ClientCertFile != "" || o.ClientKeyFile != "" {
+		if o.ClientCertFile == "" || o.ClientKeyFile == "" {
+			return nil, fmt.Errorf("both the client certificate and key files are required for mutual TLS")
+		}
+		cert, err := tls.LoadX509KeyPair(o.ClientCertFile, o.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, cert)
+	}
+	return tlsConfig, nil
+}
+
+// websocketDialer returns the dialer of Live sessions, which uses the proxy,
+// dialer and TLS config of the transport of the client if it has one.
+func (ac *apiClient) websocketDialer() *websocket.Dialer {
+	if ac.transport == nil {
+		return websocket.DefaultDialer
+	}
+	dialer := *websocket.DefaultDialer
+	dialer.Proxy = ac.transport.Proxy
+	dialer.NetDialContext = ac.transport.DialContext
+	dialer.TLSClientConfig = ac.transport.TLSClientConfig
+	return &dialer
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientOptions(t *testing.T) {
+	ctx := context.Background()
+	newClient := func(t *testing.T, baseURL string, opts *HTTPClientOptions) (*Client, error) {
+		t.Helper()
+		return NewClient(ctx, &ClientConfig{
+			APIKey:            "test-api-key",
+			Backend:           BackendGeminiAPI,
+			HTTPOptions:       HTTPOptions{BaseURL: baseURL, APIVersion: "v1beta"},
+			HTTPClientOptions: opts,
+			envVarProvider:    func() map[string]string { return map[string]string{} },
+		})
+	}
+	okHandler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"ok"}]}}]}`))
+	}
+
+	t.Run("Proxy", func(t *testing.T) {
+		var proxied atomic.Value
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied.Store(r.URL.String())
+			okHandler(w, r)
+		}))
+		defer proxy.Close()
+
+		client, err := newClient(t, "http://generativelanguage.example.com/", &HTTPClientOptions{ProxyURL: proxy.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash", Text("hi"), nil); err != nil {
+			t.Fatal(err)
+		}
+		want := "http://generativelanguage.example.com/v1beta/models/gemini-2.0-flash:generateContent"
+		if got, _ := proxied.Load().(string); got != want {
+			t.Errorf("proxy got request for %q, want %q", got, want)
+		}
+	})
+
+	t.Run("DialContext and pool", func(t *testing.T) {
+		var dials atomic.Int32
+		opts := &HTTPClientOptions{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dials.Add(1)
+				return (&net.Dialer{}).DialContext(ctx, network, addr)
+			},
+			MaxIdleConnsPerHost: 7,
+			IdleConnTimeout:     time.Minute,
+		}
+		client := newTestClient(t, &ClientConfig{HTTPClientOptions: opts}, okHandler)
+		if _, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash", Text("hi"), nil); err != nil {
+			t.Fatal(err)
+		}
+		if dials.Load() == 0 {
+			t.Error("DialContext was not called")
+		}
+		transport := client.Models.apiClient.transport
+		if transport.MaxIdleConnsPerHost != 7 || transport.IdleConnTimeout != time.Minute {
+			t.Errorf("transport pool = (%d, %v), want (7, 1m0s)", transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+		}
+	})
+
+	t.Run("CA file", func(t *testing.T) {
+		ts := httptest.NewTLSServer(http.HandlerFunc(okHandler))
+		defer ts.Close()
+
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		block := &pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}
+		if err := os.WriteFile(caFile, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Without the CA file, the certificate of the server is not trusted.
+		client, err := newClient(t, ts.URL, &HTTPClientOptions{MaxIdleConns: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash", Text("hi"), nil); err == nil {
+			t.Error("GenerateContent() without the CA file succeeded, want a certificate error")
+		}
+
+		client, err = newClient(t, ts.URL, &HTTPClientOptions{CAFile: caFile})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash", Text("hi"), nil); err != nil {
+			t.Errorf("GenerateContent() with the CA file failed: %v", err)
+		}
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		emptyFile := filepath.Join(t.TempDir(), "empty.pem")
+		if err := os.WriteFile(emptyFile, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		for name, opts := range map[string]*HTTPClientOptions{
+			"proxy URL":        {ProxyURL: "://proxy"},
+			"missing CA file":  {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+			"empty CA file":    {CAFile: emptyFile},
+			"key without cert": {ClientKeyFile: emptyFile},
+		} {
+			if _, err := newClient(t, "https://example.com", opts); err == nil {
+				t.Errorf("NewClient() with an invalid %s succeeded, want an error", name)
+			}
+		}
+	})
+
+	t.Run("Ignored with HTTPClient", func(t *testing.T) {
+		client, err := NewClient(ctx, &ClientConfig{
+			APIKey:            "test-api-key",
+			Backend:           BackendGeminiAPI,
+			HTTPClient:        &http.Client{},
+			HTTPClientOptions: &HTTPClientOptions{MaxConnsPerHost: 2},
+			envVarProvider:    func() map[string]string { return map[string]string{} },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if client.clientConfig.HTTPClient.Transport != nil || client.Models.apiClient.transport != nil {
+			t.Error("HTTPClientOptions were applied to the given HTTPClient, want them ignored")
+		}
+	})
+}
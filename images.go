@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Upscale factors of Models.UpscaleImage.
+const (
+	UpscaleFactorX2 = "x2"
+	UpscaleFactorX4 = "x4"
+)
+
+// ImageFromFile returns the image at path as an Image with its bytes. Its
+// MIME type is detected from the extension of path, e.g. image/png for ".png".
+func ImageFromFile(path string) (*Image, error) {
+	mimeType, _, _ := strings.Cut(mime.TypeByExtension(filepath.Ext(path)), ";")
+	if !strings.HasPrefix(mimeType, "image/") {
+		return nil, fmt.Errorf("failed to detect the image type of %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	return &Image{ImageBytes: data, MIMEType: mimeType}, nil
+}
+
+// Save writes the bytes of the image to the file at path. It fails if the
+// image has no bytes, e.g. if it was written to Cloud Storage.
+func (i *Image) Save(path string) error {
+	if i == nil || len(i.ImageBytes) == 0 {
+		return errors.New("the image has no bytes")
+	}
+	if err := os.WriteFile(path, i.ImageBytes, 0o644); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
+	}
+	return nil
+}
+
+// Images returns the generated images that were not filtered out, in the
+// order of the response.
+func (r *GenerateImagesResponse) Images() []*Image {
+	return generatedImages(r.GeneratedImages)
+}
+
+// FilteredReasons returns the Responsible AI reasons of the images that were
+// filtered out, which are only set if GenerateImagesConfig.IncludeRAIReason
+// is set.
+func (r *GenerateImagesResponse) FilteredReasons() []string {
+	return filteredReasons(r.GeneratedImages)
+}
+
+// Images returns the edited images that were not filtered out.
+func (r *EditImageResponse) Images() []*Image {
+	return generatedImages(r.GeneratedImages)
+}
+
+// FilteredReasons returns the Responsible AI reasons of the images that were
+// filtered out, which are only set if EditImageConfig.IncludeRAIReason is
+// set.
+func (r *EditImageResponse) FilteredReasons() []string {
+	return filteredReasons(r.GeneratedImages)
+}
+
+// Images returns the upscaled images that were not filtered out.
+func (r *UpscaleImageResponse) Images() []*Image {
+	return generatedImages(r.GeneratedImages)
+}
+
+// FilteredReasons returns the Responsible AI reasons of the images that were
+// filtered out, which are only set if UpscaleImageConfig.IncludeRAIReason is
+// set.
+func (r *UpscaleImageResponse) FilteredReasons() []string {
+	return filteredReasons(r.GeneratedImages)
+}
+
+func generatedImages(images []*GeneratedImage) []*Image {
+	var result []*Image
+	for _, image := range images {
+		if image != nil && image.Image != nil && (len(image.Image.ImageBytes) > 0 || image.Image.GCSURI != "") {
+			result = append(result, image.Image)
+		}
+	}
+	return result
+}
+
+func filteredReasons(images []*GeneratedImage) []string {
+	var reasons []string
+	for _, image := range images {
+		if image != nil && image.RAIFilteredReason != "" {
+			reasons = append(reasons, image.RAIFilteredReason)
+		}
+	}
+	return reasons
+}
+
+// EditImageWithMask edits the area of image that is white in mask as the
+// prompt describes, with EditImage. The edit mode of config defaults to
+// EditModeInpaintInsertion; set it to EditModeInpaintRemoval to remove the
+// masked content, or to EditModeOutpaint to extend the image.
+func (m Models) EditImageWithMask(ctx context.Context, model, prompt string, image, mask *Image, config *EditImageConfig) (*EditImageResponse, error) {
+	if image == nil || mask == nil {
+		return nil, errors.New("EditImageWithMask requires an image and a mask")
+	}
+	config = cloneOf(config)
+	if config == nil {
+		config = &EditImageConfig{}
+	}
+	if config.EditMode == "" {
+		config.EditMode = EditModeInpaintInsertion
+	}
+	references := []ReferenceImage{
+		NewRawReferenceImage(image, 1),
+		NewMaskReferenceImage(mask, 2, &MaskReferenceConfig{MaskMode: MaskReferenceModeMaskModeUserProvided}),
+	}
+	return m.EditImage(ctx, model, prompt, references, config)
+}
+
+// EditImageWithInstruction edits image as the instruction says, e.g.
+// "make the sky cloudy", without a mask, with EditImage in EditModeDefault.
+func (m Models) EditImageWithInstruction(ctx context.Context, model, instruction string, image *Image, config *EditImageConfig) (*EditImageResponse, error) {
+	if image == nil {
+		return nil, errors.New("EditImageWithInstruction requires an image")
+	}
+	config = cloneOf(config)
+	if config == nil {
+		config = &EditImageConfig{}
+	}
+	config.EditMode = EditModeDefault
+	return m.EditImage(ctx, model, instruction, []ReferenceImage{NewRawReferenceImage(image, 1)}, config)
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestImageFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cat.png")
+	if err := os.WriteFile(path, []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	image, err := ImageFromFile(path)
+	if err != nil {
+		t.Fatalf("ImageFromFile() failed: %v", err)
+	}
+	if diff := cmp.Diff(&Image{ImageBytes: []byte("png"), MIMEType: "image/png"}, image); diff != "" {
+		t.Errorf("ImageFromFile() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := ImageFromFile(filepath.Join(dir, "notes.txt")); err == nil {
+		t.Error("ImageFromFile() of a text file succeeded, want an error")
+	}
+
+	out := filepath.Join(dir, "out.png")
+	if err := image.Save(out); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "png" {
+		t.Errorf("Save() wrote %q, want png", data)
+	}
+	if err := (&Image{GCSURI: "gs://bucket/cat.png"}).Save(out); err == nil {
+		t.Error("Save() of an image without bytes succeeded, want an error")
+	}
+}
+
+func TestEditImageWithMask(t *testing.T) {
+	ctx := context.Background()
+	var instances []any
+	var parameters map[string]any
+	client := newTestClient(t, &ClientConfig{Backend: BackendVertexAI}, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Instances  []any          `json:"instances"`
+			Parameters map[string]any `json:"parameters"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		instances, parameters = body.Instances, body.Parameters
+		fmt.Fprint(w, `{"predictions": [{"bytesBase64Encoded": "ZWRpdGVk", "mimeType": "image/png"}, {"raiFilteredReason": "filtered"}]}`)
+	})
+	image := &Image{ImageBytes: []byte("image"), MIMEType: "image/png"}
+	mask := &Image{ImageBytes: []byte("mask"), MIMEType: "image/png"}
+	resp, err := client.Models.EditImageWithMask(ctx, "imagen-3.0-capability-001", "a hat", image, mask, nil)
+	if err != nil {
+		t.Fatalf("EditImageWithMask() failed: %v", err)
+	}
+	if diff := cmp.Diff([]*Image{{ImageBytes: []byte("edited"), MIMEType: "image/png"}}, resp.Images()); diff != "" {
+		t.Errorf("Images() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"filtered"}, resp.FilteredReasons()); diff != "" {
+		t.Errorf("FilteredReasons() mismatch (-want +got):\n%s", diff)
+	}
+	wantInstances := []any{map[string]any{
+		"prompt": "a hat",
+		"referenceImages": []any{
+			map[string]any{"referenceId": float64(1), "referenceType": "REFERENCE_TYPE_RAW", "referenceImage": map[string]any{"bytesBase64Encoded": "aW1hZ2U=", "mimeType": "image/png"}},
+			map[string]any{"referenceId": float64(2), "referenceType": "REFERENCE_TYPE_MASK", "referenceImage": map[string]any{"bytesBase64Encoded": "bWFzaw==", "mimeType": "image/png"}, "maskImageConfig": map[string]any{"maskMode": "MASK_MODE_USER_PROVIDED"}},
+		},
+	}}
+	if diff := cmp.Diff(wantInstances, instances); diff != "" {
+		t.Errorf("instances mismatch (-want +got):\n%s", diff)
+	}
+	if got := parameters["editMode"]; got != "EDIT_MODE_INPAINT_INSERTION" {
+		t.Errorf("editMode = %v, want EDIT_MODE_INPAINT_INSERTION", got)
+	}
+
+	if _, err := client.Models.EditImageWithInstruction(ctx, "imagen-3.0-capability-001", "make it blue", image, &EditImageConfig{EditMode: EditModeOutpaint}); err != nil {
+		t.Fatalf("EditImageWithInstruction() failed: %v", err)
+	}
+	if len(instances) != 1 || len(instances[0].(map[string]any)["referenceImages"].([]any)) != 1 {
+		t.Errorf("instances = %v, want one reference image", instances)
+	}
+	if got := parameters["editMode"]; got != "EDIT_MODE_DEFAULT" {
+		t.Errorf("editMode = %v, want EDIT_MODE_DEFAULT", got)
+	}
+}
@@ -29,7 +29,7 @@ type Interactions struct {
 // Interaction represents a generative AI interaction.
 type Interaction struct {
 	ID                    string                       `json:"id,omitempty"`
-	Status                string                       `json:"status,omitempty"`
+	Status                InteractionStatus            `json:"status,omitempty"`
 	Model                 string                       `json:"model,omitempty"`
 	Agent                 string                       `json:"agent,omitempty"`
 	Created               string                       `json:"created,omitempty"`
@@ -47,17 +47,56 @@ type Interaction struct {
 	GenerationConfig      *InteractionGenerationConfig `json:"generationConfig,omitempty"`
 	AgentConfig           any                          `json:"agentConfig,omitempty"`
 	Stream                bool                         `json:"stream,omitempty"`
+	Background            bool                         `json:"background,omitempty"` // Run asynchronously; poll with Get or follow with GetStream.
+	Webhook               *InteractionWebhook          `json:"webhook,omitempty"`    // Notified when a background interaction finishes.
 	SDKHTTPResponse       *HTTPResponse                `json:"sdkHttpResponse,omitempty"`
 }
 
 // InteractionEvent represents an event in a streaming interaction.
 type InteractionEvent struct {
 	EventType   string              `json:"event_type"`
+	EventID     string              `json:"event_id,omitempty"` // Resumes a stream after this event with GetInteractionConfig.LastEventID.
 	Interaction *Interaction        `json:"interaction,omitempty"`
 	Delta       *InteractionContent `json:"delta,omitempty"`
 	Index       int                 `json:"index,omitempty"`
 }
 
+// InteractionStatus is the status of an interaction. Statuses unknown to this
+// version of the SDK are kept as is, see IsKnown.
+type InteractionStatus string
+
+const (
+	InteractionStatusInProgress     InteractionStatus = "in_progress"
+	InteractionStatusRequiresAction InteractionStatus = "requires_action"
+	InteractionStatusCompleted      InteractionStatus = "completed"
+	InteractionStatusFailed         InteractionStatus = "failed"
+	InteractionStatusCancelled      InteractionStatus = "cancelled"
+	InteractionStatusIncomplete     InteractionStatus = "incomplete"
+)
+
+// IsKnown reports whether s is one of the InteractionStatus constants.
+func (s InteractionStatus) IsKnown() bool {
+	switch s {
+	case InteractionStatusInProgress, InteractionStatusRequiresAction, InteractionStatusCompleted,
+		InteractionStatusFailed, InteractionStatusCancelled, InteractionStatusIncomplete:
+		return true
+	}
+	return false
+}
+
+// IsTerminal reports whether the interaction stopped running, i.e. it
+// completed, failed, was cancelled, is incomplete or requires action such as
+// the result of a function call. Unknown statuses are not terminal, so that
+// polling continues until a known terminal status is reached.
+func (s InteractionStatus) IsTerminal() bool {
+	return s.IsKnown() && s != InteractionStatusInProgress
+}
+
+// Succeeded reports whether the interaction completed.
+func (s InteractionStatus) Succeeded() bool {
+	return s == InteractionStatusCompleted
+}
+
 // ResponseModality represents the requested modality of the response.
 type ResponseModality string
 
@@ -166,6 +205,38 @@ type InteractionImageConfig struct {
 // CreateInteractionConfig configuration for CreateInteraction.
 type CreateInteractionConfig struct {
 	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+	// Optional. Resumes the stream of CreateStream with GetStream from the
+	// last received event if the connection fails, instead of returning the
+	// error. If nil, streams are not resumed.
+	ResumePolicy *StreamResumePolicy `json:"-"`
+	// Optional. Sends the interaction as is, without checking it with
+	// Interaction.Validate first, e.g. to use fields that the service
+	// supports before this SDK does.
+	SkipValidation bool `json:"skipValidation,omitempty"`
+}
+
+// withDefaults returns a copy of interaction that uses the client's default
+// model if neither a model nor an agent is set, and whose GenerationConfig is
+// merged on top of the client's default InteractionGenerationConfig.
+func (i *Interactions) withDefaults(interaction *Interaction) *Interaction {
+	if interaction == nil {
+		return interaction
+	}
+	if defaults := i.apiClient.clientConfig.DefaultInteractionGenerationConfig; defaults != nil {
+		withConfig := *interaction
+		withConfig.GenerationConfig = defaults.Merge(interaction.GenerationConfig)
+		interaction = &withConfig
+	}
+	if interaction.Model != "" || interaction.Agent != "" {
+		return interaction
+	}
+	model := i.apiClient.resolveModel("")
+	if model == "" {
+		return interaction
+	}
+	withModel := *interaction
+	withModel.Model = model
+	return &withModel
 }
 
 // Create initiates a new generation.
@@ -176,6 +247,16 @@ func (i *Interactions) Create(ctx context.Context, interaction *Interaction, con
 	} else {
 		httpOptions = config.HTTPOptions
 	}
+	interaction = i.withDefaults(interaction)
+	if config == nil || !config.SkipValidation {
+		if err := interaction.validate(false); err != nil {
+			return nil, err
+		}
+	}
+	interaction, err := i.apiClient.guardInteractionRequest(ctx, interaction)
+	if err != nil {
+		return nil, err
+	}
 
 	path := "interactions"
 	responseMap, err := sendRequest(ctx, i.apiClient, path, http.MethodPost, interaction, httpOptions)
@@ -188,6 +269,9 @@ func (i *Interactions) Create(ctx context.Context, interaction *Interaction, con
 	if err != nil {
 		return nil, err
 	}
+	if err := i.apiClient.guardInteraction(ctx, response); err != nil {
+		return nil, err
+	}
 
 	return response, nil
 }
@@ -200,24 +284,47 @@ func (i *Interactions) CreateStream(ctx context.Context, interaction *Interactio
 	} else {
 		httpOptions = config.HTTPOptions
 	}
+	interaction = i.withDefaults(interaction)
+	if config == nil || !config.SkipValidation {
+		if err := interaction.validate(true); err != nil {
+			return yieldErrorAndEndIterator[InteractionEvent](err)
+		}
+	}
+	guard, err := i.apiClient.newStreamGuard(ctx)
+	if err != nil {
+		return yieldErrorAndEndIterator[InteractionEvent](err)
+	}
+	interaction, err = i.apiClient.guardInteractionRequest(ctx, interaction)
+	if err != nil {
+		return yieldErrorAndEndIterator[InteractionEvent](err)
+	}
 
 	interaction.Stream = true
 	path := "interactions?alt=sse"
 	var rs responseStream[InteractionEvent]
 
-	err := sendStreamRequest(ctx, i.apiClient, path, http.MethodPost, interaction, httpOptions, &rs)
+	streamCtx, stampEventID := i.apiClient.withEventIDs(ctx)
+	err = sendStreamRequest(streamCtx, i.apiClient, path, http.MethodPost, interaction, httpOptions, &rs)
 	if err != nil {
 		return yieldErrorAndEndIterator[InteractionEvent](err)
 	}
 
-	return iterateResponseStream(&rs, func(responseMap map[string]any) (*InteractionEvent, error) {
+	events := iterateResponseStream(&rs, func(responseMap map[string]any) (*InteractionEvent, error) {
 		var response = new(InteractionEvent)
 		err = mapToStruct(responseMap, response)
 		if err != nil {
 			return nil, err
 		}
+		stampEventID(response)
+		if err := guard.guardEvent(response); err != nil {
+			return nil, err
+		}
 		return response, nil
 	})
+	if config != nil && config.ResumePolicy != nil {
+		events = i.resumeOnError(ctx, events, httpOptions, config.ResumePolicy, guard)
+	}
+	return i.cancelInteractionOnBreak(ctx, events, "")
 }
 
 // Get fetches the full state of an interaction.
@@ -246,6 +353,12 @@ func (i *Interactions) Get(ctx context.Context, id string, config *GetInteractio
 
 // GetStream streams a previously created background interaction or resumes a stream.
 func (i *Interactions) GetStream(ctx context.Context, id string, config *GetInteractionConfig) iter.Seq2[*InteractionEvent, error] {
+	return i.cancelInteractionOnBreak(ctx, i.getStream(ctx, id, config), id)
+}
+
+// getStream is GetStream without cancelling the interaction when the caller
+// stops iterating.
+func (i *Interactions) getStream(ctx context.Context, id string, config *GetInteractionConfig) iter.Seq2[*InteractionEvent, error] {
 	var httpOptions *HTTPOptions
 	if config == nil || config.HTTPOptions == nil {
 		httpOptions = &HTTPOptions{}
@@ -259,7 +372,8 @@ func (i *Interactions) GetStream(ctx context.Context, id string, config *GetInte
 	}
 
 	var rs responseStream[InteractionEvent]
-	err := sendStreamRequest(ctx, i.apiClient, path, http.MethodGet, nil, httpOptions, &rs)
+	streamCtx, stampEventID := i.apiClient.withEventIDs(ctx)
+	err := sendStreamRequest(streamCtx, i.apiClient, path, http.MethodGet, nil, httpOptions, &rs)
 	if err != nil {
 		return yieldErrorAndEndIterator[InteractionEvent](err)
 	}
@@ -270,6 +384,7 @@ func (i *Interactions) GetStream(ctx context.Context, id string, config *GetInte
 		if err != nil {
 			return nil, err
 		}
+		stampEventID(response)
 		return response, nil
 	})
 }
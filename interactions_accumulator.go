@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
+// InteractionAccumulator rebuilds an interaction from the events of its
+// stream, e.g. of Interactions.CreateStream or Interactions.GetStream.
+//
+//	var acc genai.InteractionAccumulator
+//	for event, err := range client.Interactions.CreateStream(ctx, interaction, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		acc.Add(event)
+//	}
+//	result := acc.Interaction()
+//
+// The zero value is ready to use. An InteractionAccumulator is not safe for
+// concurrent use.
+type InteractionAccumulator struct {
+	interaction *Interaction
+	deltas      map[int]*InteractionContent
+}
+
+// Add merges event into the interaction. The fields of the interactions of
+// the events are merged field by field, the later events taking precedence.
+// Deltas of the same index are merged into one output content: the text of
+// text deltas and the summaries of thought deltas are concatenated, other
+// deltas replace the content of their index.
+func (a *InteractionAccumulator) Add(event *InteractionEvent) {
+	if event == nil {
+		return
+	}
+	if event.Interaction != nil {
+		a.interaction = mergeOf(a.interaction, event.Interaction)
+	}
+	d := event.Delta
+	if d == nil {
+		return
+	}
+	if a.deltas == nil {
+		a.deltas = map[int]*InteractionContent{}
+	}
+	existing, ok := a.deltas[event.Index]
+	if !ok || existing.Type != d.Type {
+		a.deltas[event.Index] = cloneOf(d)
+		return
+	}
+	switch d.Type {
+	case InteractionContentTypeText:
+		existing.Text += d.Text
+		existing.Annotations = append(existing.Annotations, *cloneOf(&d.Annotations)...)
+	case InteractionContentTypeThought:
+		for _, s := range d.Summary {
+			if s == nil {
+				continue
+			}
+			if n := len(existing.Summary); n > 0 && existing.Summary[n-1].Type == InteractionContentTypeText && s.Type == InteractionContentTypeText {
+				existing.Summary[n-1].Text += s.Text
+			} else {
+				existing.Summary = append(existing.Summary, cloneOf(s))
+			}
+		}
+		if len(d.Signature) > 0 {
+			existing.Signature = d.Signature
+		}
+	default:
+		a.deltas[event.Index] = cloneOf(d)
+	}
+}
+
+// Interaction returns the interaction merged from the events so far. Its
+// Outputs are the outputs of the last event that had any, usually the final
+// event of the stream, or else the outputs merged from the deltas in the order
+// of their index. The result is a copy, later events do not modify it.
+func (a *InteractionAccumulator) Interaction() *Interaction {
+	interaction := cloneOf(a.interaction)
+	if interaction == nil {
+		interaction = &Interaction{}
+	}
+	if len(interaction.Outputs) == 0 && len(a.deltas) > 0 {
+		for _, i := range slices.Sorted(maps.Keys(a.deltas)) {
+			interaction.Outputs = append(interaction.Outputs, cloneOf(a.deltas[i]))
+		}
+	}
+	return interaction
+}
+
+// CollectStream consumes the events of an interaction stream and returns the
+// interaction rebuilt by an InteractionAccumulator with all events. If the
+// stream fails, the interaction and events so far are returned with the error.
+func CollectStream(events iter.Seq2[*InteractionEvent, error]) (*Interaction, []*InteractionEvent, error) {
+	var acc InteractionAccumulator
+	var collected []*InteractionEvent
+	for event, err := range events {
+		if err != nil {
+			return acc.Interaction(), collected, err
+		}
+		acc.Add(event)
+		collected = append(collected, event)
+	}
+	return acc.Interaction(), collected, nil
+}
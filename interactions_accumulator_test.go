@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func interactionEvents(events []*InteractionEvent, err error) iter.Seq2[*InteractionEvent, error] {
+	return func(yield func(*InteractionEvent, error) bool) {
+		for _, e := range events {
+			if !yield(e, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func TestInteractionAccumulator(t *testing.T) {
+	events := []*InteractionEvent{
+		{EventType: "interaction.start", Interaction: &Interaction{ID: "abc", Status: "in_progress", Model: "gemini-2.5-flash"}},
+		{EventType: "content.delta", Index: 0, Delta: &InteractionContent{Type: "thought", Summary: []*InteractionContent{NewInteractionText("Adding ")}}},
+		{EventType: "content.delta", Index: 0, Delta: &InteractionContent{Type: "thought", Summary: []*InteractionContent{NewInteractionText("numbers.")}, Signature: []byte("sig")}},
+		{EventType: "content.delta", Index: 1, Delta: NewInteractionText("1 + 2 ")},
+		{EventType: "content.delta", Index: 1, Delta: NewInteractionText("= 3")},
+		{EventType: "content.delta", Index: 2, Delta: NewInteractionFunctionCall("c1", "save", map[string]any{"n": 3.0})},
+		{EventType: "interaction.complete", Interaction: &Interaction{ID: "abc", Status: "completed", Usage: &InteractionUsage{TotalTokens: 12}}},
+	}
+	want := &Interaction{
+		ID:     "abc",
+		Status: "completed",
+		Model:  "gemini-2.5-flash",
+		Usage:  &InteractionUsage{TotalTokens: 12},
+		Outputs: []*InteractionContent{
+			{Type: "thought", Summary: []*InteractionContent{NewInteractionText("Adding numbers.")}, Signature: []byte("sig")},
+			NewInteractionText("1 + 2 = 3"),
+			NewInteractionFunctionCall("c1", "save", map[string]any{"n": 3.0}),
+		},
+	}
+
+	var acc InteractionAccumulator
+	for _, e := range events[:4] {
+		acc.Add(e)
+	}
+	partial := acc.Interaction()
+	if partial.Status != "in_progress" || len(partial.Outputs) != 2 || partial.Outputs[1].Text != "1 + 2 " {
+		t.Errorf("Interaction() after 4 events = %+v, want the partial interaction", partial)
+	}
+
+	got, collected, err := CollectStream(interactionEvents(events, nil))
+	if err != nil {
+		t.Fatalf("CollectStream() failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("CollectStream() interaction mismatch (-want +got):\n%s", diff)
+	}
+	if len(collected) != len(events) {
+		t.Errorf("CollectStream() returned %d events, want %d", len(collected), len(events))
+	}
+	if partial.Status != "in_progress" || events[3].Delta.Text != "1 + 2 " {
+		t.Errorf("later events modified an earlier result or the events")
+	}
+
+	// Outputs of the final interaction take precedence over the deltas.
+	final := append(events[:len(events):len(events)], &InteractionEvent{Interaction: &Interaction{Outputs: []*InteractionContent{NewInteractionText("3")}}})
+	if got, _, _ := CollectStream(interactionEvents(final, nil)); len(got.Outputs) != 1 || got.Outputs[0].Text != "3" {
+		t.Errorf("CollectStream() outputs = %+v, want the outputs of the final interaction", got.Outputs)
+	}
+
+	streamErr := errors.New("stream failed")
+	got, collected, err = CollectStream(interactionEvents(events[:5], streamErr))
+	if !errors.Is(err, streamErr) || len(collected) != 5 || got.Outputs[1].Text != "1 + 2 = 3" {
+		t.Errorf("CollectStream() = %+v, %d events, %v, want the partial interaction and the error", got, len(collected), err)
+	}
+}
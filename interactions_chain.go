@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// InteractionChainVersion is the version of the InteractionChain format.
+const InteractionChainVersion = 1
+
+// InteractionChain is a portable document of the interactions of a
+// conversation, linked by their PreviousInteractionID, e.g. for auditing or
+// to seed a new InteractionSession with ImportChain. It is encoded as JSON
+// with encoding/json.
+type InteractionChain struct {
+	// Version is the version of the format, InteractionChainVersion.
+	Version int `json:"version"`
+	// Interactions are the interactions of the chain, oldest first.
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// ExportChain gets the interaction id and its previous interactions, and
+// returns them as an InteractionChain.
+func (i *Interactions) ExportChain(ctx context.Context, id string, config *GetInteractionConfig) (*InteractionChain, error) {
+	var interactions []*Interaction
+	seen := map[string]bool{}
+	for next := id; next != ""; {
+		if seen[next] {
+			return nil, fmt.Errorf("interaction %s is its own predecessor", next)
+		}
+		seen[next] = true
+		got, err := i.Get(ctx, next, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get interaction %s of the chain: %w", next, err)
+		}
+		got.SDKHTTPResponse = nil
+		interactions = append(interactions, got)
+		next = got.PreviousInteractionID
+	}
+	for l, r := 0, len(interactions)-1; l < r; l, r = l+1, r-1 {
+		interactions[l], interactions[r] = interactions[r], interactions[l]
+	}
+	return &InteractionChain{Version: InteractionChainVersion, Interactions: interactions}, nil
+}
+
+// Turns returns the transcript of the chain: the input turns of every
+// interaction followed by its outputs with the "model" role, like
+// InteractionSession.Transcript. Untyped inputs, e.g. of a chain decoded from
+// JSON, are converted to a string, []*InteractionContent or
+// []*InteractionTurn.
+func (c *InteractionChain) Turns() []*InteractionTurn {
+	var turns []*InteractionTurn
+	for _, interaction := range c.Interactions {
+		if interaction == nil {
+			continue
+		}
+		turns = append(turns, inputTurns(interaction.Input)...)
+		turns = append(turns, &InteractionTurn{Role: RoleModel, Content: interaction.Outputs})
+	}
+	return turns
+}
+
+// ImportChain seeds the session with the transcript of chain and continues
+// the conversation of its last interaction. The session must not have any
+// turns yet. The next turn fails if the service no longer stores the last
+// interaction of chain; to start over then, send chain.Turns() as the input of
+// a new session.
+func (s *InteractionSession) ImportChain(chain *InteractionChain) error {
+	if chain == nil || len(chain.Interactions) == 0 {
+		return errors.New("the chain has no interactions")
+	}
+	if chain.Version != InteractionChainVersion {
+		return fmt.Errorf("unsupported chain version %d, want %d", chain.Version, InteractionChainVersion)
+	}
+	if len(s.transcript) > 0 {
+		return errors.New("the session already has turns")
+	}
+	last := chain.Interactions[len(chain.Interactions)-1]
+	if last == nil || last.ID == "" {
+		return errors.New("the last interaction of the chain has no ID")
+	}
+	s.transcript = chain.Turns()
+	s.previousID = last.ID
+	return nil
+}
+
+// inputTurns returns the turns of the input of an interaction. Multi-turn
+// inputs are returned as is.
+func inputTurns(input any) []*InteractionTurn {
+	switch in := normalizeInteractionInput(input).(type) {
+	case nil:
+		return nil
+	case []*InteractionTurn:
+		return in
+	case *InteractionContent:
+		return []*InteractionTurn{{Role: RoleUser, Content: []*InteractionContent{in}}}
+	default:
+		return []*InteractionTurn{{Role: RoleUser, Content: in}}
+	}
+}
+
+// normalizeInteractionInput converts an input decoded from JSON into an any
+// to the type it was encoded from.
+func normalizeInteractionInput(input any) any {
+	switch input.(type) {
+	case map[string]any, []any:
+	default:
+		return input
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return input
+	}
+	if _, ok := input.(map[string]any); ok {
+		content := new(InteractionContent)
+		if json.Unmarshal(data, content) != nil {
+			return input
+		}
+		return []*InteractionContent{content}
+	}
+	var items []map[string]any
+	if json.Unmarshal(data, &items) != nil {
+		return input
+	}
+	if len(items) > 0 && items[0]["type"] == nil && (items[0]["role"] != nil || items[0]["content"] != nil) {
+		var turns []*InteractionTurn
+		if json.Unmarshal(data, &turns) != nil {
+			return input
+		}
+		for _, turn := range turns {
+			if turn != nil {
+				turn.Content = normalizeInteractionInput(turn.Content)
+			}
+		}
+		return turns
+	}
+	var contents []*InteractionContent
+	if json.Unmarshal(data, &contents) != nil {
+		return input
+	}
+	return contents
+}
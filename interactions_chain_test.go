@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInteractionsExportImportChain(t *testing.T) {
+	ctx := context.Background()
+	stored := map[string]string{
+		"turn-1": `{"id": "turn-1", "status": "completed", "input": "What is 1 + 2?", "outputs": [{"type": "text", "text": "3"}]}`,
+		"turn-2": `{"id": "turn-2", "status": "completed", "previousInteractionId": "turn-1", "input": [{"type": "text", "text": "Times 3?"}], "outputs": [{"type": "text", "text": "9"}]}`,
+	}
+	var created *Interaction
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			created = new(Interaction)
+			json.NewDecoder(r.Body).Decode(created)
+			fmt.Fprint(w, `{"id": "turn-3", "status": "completed", "outputs": [{"type": "text", "text": "You're welcome."}]}`)
+			return
+		}
+		body, ok := stored[strings.TrimPrefix(r.URL.Path, "/v1beta/interactions/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	})
+
+	chain, err := client.Interactions.ExportChain(ctx, "turn-2", nil)
+	if err != nil {
+		t.Fatalf("ExportChain() failed: %v", err)
+	}
+	if len(chain.Interactions) != 2 || chain.Interactions[0].ID != "turn-1" || chain.Interactions[1].ID != "turn-2" {
+		t.Fatalf("ExportChain() = %+v, want turn-1 and turn-2", chain.Interactions)
+	}
+
+	// Round trip the chain through JSON, as if it was stored for an audit.
+	data, err := json.Marshal(chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := new(InteractionChain)
+	if err := json.Unmarshal(data, imported); err != nil {
+		t.Fatal(err)
+	}
+	session := client.Interactions.NewSession(&Interaction{Model: "gemini-2.5-flash"}, nil)
+	if err := session.ImportChain(imported); err != nil {
+		t.Fatalf("ImportChain() failed: %v", err)
+	}
+	if _, err := session.Send(ctx, "Thanks"); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+	if created.PreviousInteractionID != "turn-2" {
+		t.Errorf("PreviousInteractionID = %q, want turn-2", created.PreviousInteractionID)
+	}
+	want := []*InteractionTurn{
+		{Role: RoleUser, Content: "What is 1 + 2?"},
+		{Role: RoleModel, Content: []*InteractionContent{NewInteractionText("3")}},
+		{Role: RoleUser, Content: []*InteractionContent{NewInteractionText("Times 3?")}},
+		{Role: RoleModel, Content: []*InteractionContent{NewInteractionText("9")}},
+		{Role: RoleUser, Content: "Thanks"},
+		{Role: RoleModel, Content: []*InteractionContent{NewInteractionText("You're welcome.")}},
+	}
+	if diff := cmp.Diff(want, session.Transcript()); diff != "" {
+		t.Errorf("Transcript() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := session.ImportChain(imported); err == nil {
+		t.Errorf("ImportChain() into a session with turns succeeded, want error")
+	}
+	if _, err := client.Interactions.ExportChain(ctx, "missing", nil); err == nil {
+		t.Errorf("ExportChain() of a missing interaction succeeded, want error")
+	}
+}
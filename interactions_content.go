@@ -0,0 +1,221 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import "encoding/json"
+
+// The types of an InteractionContent.
+const (
+	InteractionContentTypeText           = "text"
+	InteractionContentTypeImage          = "image"
+	InteractionContentTypeAudio          = "audio"
+	InteractionContentTypeVideo          = "video"
+	InteractionContentTypeDocument       = "document"
+	InteractionContentTypeThought        = "thought"
+	InteractionContentTypeFunctionCall   = "function_call"
+	InteractionContentTypeFunctionResult = "function_result"
+)
+
+// NewInteractionText builds a text InteractionContent.
+func NewInteractionText(text string) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeText, Text: text}
+}
+
+// NewInteractionImageBytes builds an image InteractionContent from the given
+// bytes and mime type.
+func NewInteractionImageBytes(data []byte, mimeType string) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeImage, Data: data, MIMEType: mimeType}
+}
+
+// NewInteractionImageURI builds an image InteractionContent from the given URI
+// and mime type.
+func NewInteractionImageURI(uri, mimeType string) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeImage, URI: uri, MIMEType: mimeType}
+}
+
+// NewInteractionAudioBytes builds an audio InteractionContent from the given
+// bytes and mime type.
+func NewInteractionAudioBytes(data []byte, mimeType string) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeAudio, Data: data, MIMEType: mimeType}
+}
+
+// NewInteractionAudioURI builds an audio InteractionContent from the given URI
+// and mime type.
+func NewInteractionAudioURI(uri, mimeType string) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeAudio, URI: uri, MIMEType: mimeType}
+}
+
+// NewInteractionVideoBytes builds a video InteractionContent from the given
+// bytes and mime type.
+func NewInteractionVideoBytes(data []byte, mimeType string) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeVideo, Data: data, MIMEType: mimeType}
+}
+
+// NewInteractionVideoURI builds a video InteractionContent from the given URI
+// and mime type.
+func NewInteractionVideoURI(uri, mimeType string) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeVideo, URI: uri, MIMEType: mimeType}
+}
+
+// NewInteractionDocumentBytes builds a document InteractionContent, e.g. a
+// PDF, from the given bytes and mime type.
+func NewInteractionDocumentBytes(data []byte, mimeType string) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeDocument, Data: data, MIMEType: mimeType}
+}
+
+// NewInteractionDocumentURI builds a document InteractionContent from the
+// given URI and mime type.
+func NewInteractionDocumentURI(uri, mimeType string) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeDocument, URI: uri, MIMEType: mimeType}
+}
+
+// NewInteractionFunctionCall builds a function call InteractionContent, e.g.
+// to replay a call of the model in an InteractionTurn.
+func NewInteractionFunctionCall(id, name string, args map[string]any) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeFunctionCall, ID: id, Name: name, Arguments: args}
+}
+
+// NewInteractionFunctionResult builds the InteractionContent with the result
+// of the function call callID.
+func NewInteractionFunctionResult(callID, name string, result any) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeFunctionResult, CallID: callID, Name: name, Result: result}
+}
+
+// NewInteractionFunctionError builds the InteractionContent that reports to
+// the model that the function call callID failed with err.
+func NewInteractionFunctionError(callID, name string, err error) *InteractionContent {
+	return &InteractionContent{Type: InteractionContentTypeFunctionResult, CallID: callID, Name: name, Result: err.Error(), IsError: true}
+}
+
+// InteractionMedia is the typed view of an image, audio, video or document
+// InteractionContent.
+type InteractionMedia struct {
+	// The content type, e.g. "image".
+	Type string
+	// The inline bytes of the media, if any.
+	Data []byte
+	// The URI of the media, if it is not inline.
+	URI string
+	// The mime type of the media.
+	MIMEType string
+	// The resolution the media is processed at, if set.
+	Resolution MediaResolution
+}
+
+// InteractionFunctionCall is the typed view of a function call
+// InteractionContent.
+type InteractionFunctionCall struct {
+	// The ID of the call, to be used as the CallID of its result.
+	ID string
+	// The name of the function.
+	Name string
+	// The arguments of the call.
+	Args map[string]any
+}
+
+// InteractionFunctionResult is the typed view of a function result
+// InteractionContent.
+type InteractionFunctionResult struct {
+	// The ID of the call the result is for.
+	CallID string
+	// The name of the function.
+	Name string
+	// The result of the call.
+	Result any
+	// Whether the call failed.
+	IsError bool
+}
+
+// InteractionThought is the typed view of a thought InteractionContent.
+type InteractionThought struct {
+	// The text of the thought summary.
+	Summary string
+	// The signature of the thought, which must be sent back unchanged.
+	Signature []byte
+}
+
+// AsText returns the text of a text content. It reports false if c is not a
+// text content.
+func (c *InteractionContent) AsText() (string, bool) {
+	if c == nil || c.Type != InteractionContentTypeText {
+		return "", false
+	}
+	return c.Text, true
+}
+
+// AsMedia returns the view of an image, audio, video or document content. It
+// reports false if c is none of them.
+func (c *InteractionContent) AsMedia() (*InteractionMedia, bool) {
+	if c == nil {
+		return nil, false
+	}
+	switch c.Type {
+	case InteractionContentTypeImage, InteractionContentTypeAudio, InteractionContentTypeVideo, InteractionContentTypeDocument:
+		return &InteractionMedia{Type: c.Type, Data: c.Data, URI: c.URI, MIMEType: c.MIMEType, Resolution: c.Resolution}, true
+	}
+	return nil, false
+}
+
+// AsFunctionCall returns the view of a function call content. It reports
+// false if c is not a function call, or if its arguments are not a JSON
+// object.
+func (c *InteractionContent) AsFunctionCall() (*InteractionFunctionCall, bool) {
+	if c == nil || c.Type != InteractionContentTypeFunctionCall {
+		return nil, false
+	}
+	args, ok := interactionArguments(c.Arguments)
+	if !ok {
+		return nil, false
+	}
+	return &InteractionFunctionCall{ID: c.ID, Name: c.Name, Args: args}, true
+}
+
+// AsFunctionResult returns the view of a function result content. It reports
+// false if c is not a function result.
+func (c *InteractionContent) AsFunctionResult() (*InteractionFunctionResult, bool) {
+	if c == nil || c.Type != InteractionContentTypeFunctionResult {
+		return nil, false
+	}
+	return &InteractionFunctionResult{CallID: c.CallID, Name: c.Name, Result: c.Result, IsError: c.IsError}, true
+}
+
+// AsThought returns the view of a thought content. It reports false if c is
+// not a thought.
+func (c *InteractionContent) AsThought() (*InteractionThought, bool) {
+	if c == nil || c.Type != InteractionContentTypeThought {
+		return nil, false
+	}
+	return &InteractionThought{Summary: interactionText(c.Summary), Signature: c.Signature}, true
+}
+
+// interactionArguments returns the arguments of a function call as a map.
+// Arguments that were set to a struct are converted through JSON.
+func interactionArguments(arguments any) (map[string]any, bool) {
+	switch args := arguments.(type) {
+	case nil:
+		return nil, true
+	case map[string]any:
+		return args, true
+	}
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, false
+	}
+	var args map[string]any
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, false
+	}
+	return args, true
+}
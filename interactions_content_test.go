@@ -0,0 +1,67 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInteractionContentAccessors(t *testing.T) {
+	if got, ok := NewInteractionText("hi").AsText(); !ok || got != "hi" {
+		t.Errorf("AsText() = %q, %v, want hi, true", got, ok)
+	}
+	if _, ok := NewInteractionText("hi").AsFunctionCall(); ok {
+		t.Errorf("AsFunctionCall() of a text content reported true")
+	}
+	if _, ok := (*InteractionContent)(nil).AsText(); ok {
+		t.Errorf("AsText() of nil reported true")
+	}
+
+	media, ok := NewInteractionImageURI("gs://bucket/cat.png", "image/png").AsMedia()
+	if diff := cmp.Diff(&InteractionMedia{Type: "image", URI: "gs://bucket/cat.png", MIMEType: "image/png"}, media); !ok || diff != "" {
+		t.Errorf("AsMedia() mismatch (-want +got):\n%s", diff)
+	}
+
+	// Contents decoded from JSON have map arguments, contents built in code
+	// may have struct arguments.
+	var decoded InteractionContent
+	if err := json.Unmarshal([]byte(`{"type": "function_call", "id": "c1", "name": "get_weather", "arguments": {"city": "Paris"}}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	built := &InteractionContent{Type: "function_call", ID: "c1", Name: "get_weather", Arguments: struct {
+		City string `json:"city"`
+	}{"Paris"}}
+	want := &InteractionFunctionCall{ID: "c1", Name: "get_weather", Args: map[string]any{"city": "Paris"}}
+	for _, c := range []*InteractionContent{&decoded, built, NewInteractionFunctionCall("c1", "get_weather", map[string]any{"city": "Paris"})} {
+		call, ok := c.AsFunctionCall()
+		if diff := cmp.Diff(want, call); !ok || diff != "" {
+			t.Errorf("AsFunctionCall() mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	result, ok := NewInteractionFunctionError("c1", "get_weather", errors.New("unavailable")).AsFunctionResult()
+	if diff := cmp.Diff(&InteractionFunctionResult{CallID: "c1", Name: "get_weather", Result: "unavailable", IsError: true}, result); !ok || diff != "" {
+		t.Errorf("AsFunctionResult() mismatch (-want +got):\n%s", diff)
+	}
+
+	thought := &InteractionContent{Type: "thought", Signature: []byte("sig"), Summary: []*InteractionContent{NewInteractionText("Thinking.")}}
+	if got, ok := thought.AsThought(); !ok || got.Summary != "Thinking." || string(got.Signature) != "sig" {
+		t.Errorf("AsThought() = %+v, %v, want the summary and signature", got, ok)
+	}
+}
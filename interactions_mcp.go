@@ -0,0 +1,178 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// InteractionToolTypeMCPServer is the type of an InteractionTool for a remote
+// MCP server that the service connects to itself.
+const InteractionToolTypeMCPServer = "mcp_server"
+
+const defaultMCPMaxRounds = 10
+
+// NewInteractionMCPServerTool builds the InteractionTool of the remote MCP
+// server name at url. The service calls the server with headers, e.g. for
+// authorization, and only lets the model use allowedTools, or every tool of
+// the server if allowedTools is empty. The server must be reachable from the
+// service; for a local MCP client session, use an MCPToolset instead.
+func NewInteractionMCPServerTool(name, url string, headers map[string]string, allowedTools []string) *InteractionTool {
+	tool := &InteractionTool{Type: InteractionToolTypeMCPServer, Name: name, URL: url, Headers: headers}
+	if len(allowedTools) > 0 {
+		tool.AllowedTools = &InteractionAllowedTools{Tools: allowedTools}
+	}
+	return tool
+}
+
+// MCPTool describes a tool of an MCP server.
+type MCPTool struct {
+	// The name of the tool.
+	Name string
+	// The description of the tool.
+	Description string
+	// The JSON schema of the arguments of the tool.
+	InputSchema any
+}
+
+// MCPSession is the client session of an MCP server, such as an
+// mcp.ClientSession of github.com/modelcontextprotocol/go-sdk wrapped as
+// follows:
+//
+//	type goSDKSession struct{ *mcp.ClientSession }
+//
+//	func (s goSDKSession) ListTools(ctx context.Context) ([]*genai.MCPTool, error) {
+//		var tools []*genai.MCPTool
+//		for tool, err := range s.Tools(ctx, nil) {
+//			if err != nil {
+//				return nil, err
+//			}
+//			tools = append(tools, &genai.MCPTool{Name: tool.Name, Description: tool.Description, InputSchema: tool.InputSchema})
+//		}
+//		return tools, nil
+//	}
+//
+//	func (s goSDKSession) CallTool(ctx context.Context, name string, args map[string]any) (any, error) {
+//		res, err := s.ClientSession.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+//		if err != nil {
+//			return nil, err
+//		}
+//		if res.IsError {
+//			return nil, fmt.Errorf("tool %s failed: %v", name, res.Content)
+//		}
+//		return res.Content, nil
+//	}
+type MCPSession interface {
+	// ListTools returns the tools of the server.
+	ListTools(ctx context.Context) ([]*MCPTool, error)
+	// CallTool calls the tool name with args and returns its result. An error
+	// is reported to the model as the result of the call.
+	CallTool(ctx context.Context, name string, args map[string]any) (any, error)
+}
+
+// MCPToolset exposes the tools of a local MCP session to interactions as
+// function tools, and dispatches the calls of the model to the session.
+type MCPToolset struct {
+	session MCPSession
+	tools   []*InteractionTool
+	names   map[string]bool
+	// MaxRounds is the maximum number of interactions that Run creates to
+	// answer tool calls. Defaults to 10.
+	MaxRounds int
+}
+
+// NewMCPToolset lists the tools of session and returns a toolset of them.
+func NewMCPToolset(ctx context.Context, session MCPSession) (*MCPToolset, error) {
+	tools, err := session.ListTools(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the MCP tools: %w", err)
+	}
+	s := &MCPToolset{session: session, names: map[string]bool{}}
+	for _, t := range tools {
+		if t == nil {
+			continue
+		}
+		s.names[t.Name] = true
+		s.tools = append(s.tools, &InteractionTool{Type: "function", Name: t.Name, Description: t.Description, Parameters: t.InputSchema})
+	}
+	return s, nil
+}
+
+// Tools returns the function tools of the MCP tools, to be added to the Tools
+// of an interaction.
+func (s *MCPToolset) Tools() []*InteractionTool {
+	return append([]*InteractionTool(nil), s.tools...)
+}
+
+// Dispatch calls the session for every function call in outputs that is for
+// one of the MCP tools, and returns the function results in the order of the
+// calls. Calls of other functions are skipped. It returns an error only if
+// ctx is done.
+func (s *MCPToolset) Dispatch(ctx context.Context, outputs []*InteractionContent) ([]*InteractionContent, error) {
+	var results []*InteractionContent
+	for _, out := range outputs {
+		call, ok := out.AsFunctionCall()
+		if !ok || !s.names[call.Name] {
+			continue
+		}
+		result, err := s.session.CallTool(ctx, call.Name, call.Args)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if err != nil {
+			results = append(results, NewInteractionFunctionError(call.ID, call.Name, err))
+		} else {
+			results = append(results, NewInteractionFunctionResult(call.ID, call.Name, result))
+		}
+	}
+	return results, nil
+}
+
+// Run creates interaction with the MCP tools added to its tools, answers the
+// MCP tool calls of the model with Dispatch, and returns the first
+// interaction without MCP tool calls. The interactions are chained with
+// PreviousInteractionID like in an InteractionSession.
+func (s *MCPToolset) Run(ctx context.Context, interactions *Interactions, interaction *Interaction, config *CreateInteractionConfig) (*Interaction, error) {
+	if interaction == nil {
+		return nil, errors.New("interaction is required")
+	}
+	withTools := *interaction
+	withTools.Tools = append(append([]*InteractionTool(nil), interaction.Tools...), s.tools...)
+	session := interactions.NewSession(&withTools, config)
+	maxRounds := s.MaxRounds
+	if maxRounds <= 0 {
+		maxRounds = defaultMCPMaxRounds
+	}
+	var input any = interaction.Input
+	for round := 0; ; round++ {
+		response, err := session.Send(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		results, err := s.Dispatch(ctx, response.Outputs)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			return response, nil
+		}
+		if round+1 >= maxRounds {
+			return response, fmt.Errorf("the model still called MCP tools after %d interactions", maxRounds)
+		}
+		input = results
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeMCPSession struct {
+	calls []string
+}
+
+func (s *fakeMCPSession) ListTools(ctx context.Context) ([]*MCPTool, error) {
+	return []*MCPTool{
+		{Name: "get_weather", Description: "Gets the weather.", InputSchema: map[string]any{"type": "object"}},
+		{Name: "get_time"},
+	}, nil
+}
+
+func (s *fakeMCPSession) CallTool(ctx context.Context, name string, args map[string]any) (any, error) {
+	s.calls = append(s.calls, fmt.Sprintf("%s(%v)", name, args["city"]))
+	if name == "get_time" {
+		return nil, errors.New("clock unavailable")
+	}
+	return "sunny", nil
+}
+
+func TestMCPToolsetRun(t *testing.T) {
+	ctx := context.Background()
+	var requests []map[string]any
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, req)
+		if len(requests) == 1 {
+			fmt.Fprint(w, `{"id": "turn-1", "status": "requires_action", "outputs": [
+				{"type": "function_call", "id": "c1", "name": "get_weather", "arguments": {"city": "Paris"}},
+				{"type": "function_call", "id": "c2", "name": "get_time", "arguments": {"city": "Paris"}},
+				{"type": "function_call", "id": "c3", "name": "local_only", "arguments": {}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": "turn-2", "status": "completed", "outputs": [{"type": "text", "text": "Sunny in Paris."}]}`)
+	})
+
+	session := &fakeMCPSession{}
+	toolset, err := NewMCPToolset(ctx, session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := toolset.Run(ctx, client.Interactions, &Interaction{Model: "gemini-2.5-flash", Input: "Weather in Paris?"}, nil)
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if got.ID != "turn-2" {
+		t.Errorf("Run() = %+v, want the final interaction", got)
+	}
+	if diff := cmp.Diff([]string{"get_weather(Paris)", "get_time(Paris)"}, session.calls); diff != "" {
+		t.Errorf("MCP calls mismatch (-want +got):\n%s", diff)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	wantTools := []any{
+		map[string]any{"type": "function", "name": "get_weather", "description": "Gets the weather.", "parameters": map[string]any{"type": "object"}},
+		map[string]any{"type": "function", "name": "get_time"},
+	}
+	if diff := cmp.Diff(wantTools, requests[0]["tools"]); diff != "" {
+		t.Errorf("advertised tools mismatch (-want +got):\n%s", diff)
+	}
+	wantInput := []any{
+		map[string]any{"type": "function_result", "callId": "c1", "name": "get_weather", "result": "sunny"},
+		map[string]any{"type": "function_result", "callId": "c2", "name": "get_time", "result": "clock unavailable", "isError": true},
+	}
+	if diff := cmp.Diff(wantInput, requests[1]["input"]); diff != "" {
+		t.Errorf("second input mismatch (-want +got):\n%s", diff)
+	}
+	if requests[1]["previousInteractionId"] != "turn-1" {
+		t.Errorf("previousInteractionId = %v, want turn-1", requests[1]["previousInteractionId"])
+	}
+}
+
+func TestNewInteractionMCPServerTool(t *testing.T) {
+	got := NewInteractionMCPServerTool("weather", "https://example.com/mcp", map[string]string{"Authorization": "Bearer t"}, []string{"get_weather"})
+	want := &InteractionTool{
+		Type:         "mcp_server",
+		Name:         "weather",
+		URL:          "https://example.com/mcp",
+		Headers:      map[string]string{"Authorization": "Bearer t"},
+		AllowedTools: &InteractionAllowedTools{Tools: []string{"get_weather"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NewInteractionMCPServerTool() mismatch (-want +got):\n%s", diff)
+	}
+}
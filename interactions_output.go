@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// InteractionResponseFormatFor returns the JSON schema of the JSON encoding of
+// T, to be used as the ResponseFormat of an Interaction together with the
+// "application/json" ResponseMIMEType. Struct fields, their jsonschema tags
+// and pointers follow the rules of SchemaFromType; maps are also supported.
+//
+//	format, err := genai.InteractionResponseFormatFor[Recipe]()
+//	interaction := &genai.Interaction{
+//		Model:            "gemini-2.5-flash",
+//		Input:            "A cookie recipe",
+//		ResponseFormat:   format,
+//		ResponseMIMEType: "application/json",
+//	}
+func InteractionResponseFormatFor[T any]() (map[string]any, error) {
+	return jsonSchemaOf(reflect.TypeFor[T]())
+}
+
+// OutputInto unmarshals the first text output of the interaction into v, which
+// must be a non-nil pointer. The output, optionally wrapped in a Markdown code
+// block, is first validated against the schema of the type of v as returned
+// by InteractionResponseFormatFor, so that a missing required field or a
+// value of the wrong type is reported as a *ValidationError rather than
+// decoded into a zero value.
+func (i *Interaction) OutputInto(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("OutputInto requires a non-nil pointer, got %T", v)
+	}
+	text, ok := firstInteractionText(i)
+	if !ok {
+		return errors.New("the interaction has no text output")
+	}
+	return unmarshalValidated(text, v)
+}
+
+// unmarshalValidated unmarshals text, optionally wrapped in a Markdown code
+// block, into v after validating it against the schema of the type of v.
+func unmarshalValidated(text string, v any) error {
+	data := []byte(trimCodeBlock(text))
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("the output is not valid JSON: %w", err)
+	}
+	t := reflect.TypeOf(v).Elem()
+	schema, err := jsonSchemaOf(t)
+	if err != nil {
+		return err
+	}
+	verr := &validator{}
+	validateJSONValue(verr, "", schema, value)
+	if err := verr.err(t.String()); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// firstInteractionText returns the text of the first text output of
+// interaction.
+func firstInteractionText(interaction *Interaction) (string, bool) {
+	if interaction == nil {
+		return "", false
+	}
+	for _, out := range interaction.Outputs {
+		if text, ok := out.AsText(); ok {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// trimCodeBlock returns the content of text if it is a Markdown code block,
+// e.g. "```json\n{...}\n```", or text itself otherwise.
+func trimCodeBlock(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") || !strings.HasSuffix(text, "```") || len(text) < 6 {
+		return text
+	}
+	body := strings.TrimSuffix(text[3:], "```")
+	// Drop the language of the code block.
+	if _, rest, ok := strings.Cut(body, "\n"); ok {
+		body = rest
+	}
+	return strings.TrimSpace(body)
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testIngredient struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity,omitempty"`
+}
+
+type testRecipe struct {
+	Title       string            `json:"title" jsonschema:"description=The name, as shown,enum=Cookies|Cake"`
+	Servings    int               `json:"servings"`
+	Vegan       *bool             `json:"vegan"`
+	Ingredients []testIngredient  `json:"ingredients"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Internal    string            `json:"-"`
+}
+
+func TestInteractionResponseFormatFor(t *testing.T) {
+	got, err := InteractionResponseFormatFor[testRecipe]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"title":    map[string]any{"type": "string", "description": "The name, as shown", "enum": []string{"Cookies", "Cake"}},
+			"servings": map[string]any{"type": "integer"},
+			"vegan":    map[string]any{"type": []string{"boolean", "null"}},
+			"ingredients": map[string]any{"type": "array", "items": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"name":     map[string]any{"type": "string"},
+					"quantity": map[string]any{"type": "number"},
+				},
+				"required":         []string{"name"},
+				"propertyOrdering": []string{"name", "quantity"},
+			}},
+			"tags": map[string]any{"type": "object", "additionalProperties": map[string]any{"type": "string"}},
+		},
+		"required":         []string{"title", "servings", "ingredients"},
+		"propertyOrdering": []string{"title", "servings", "vegan", "ingredients", "tags"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("InteractionResponseFormatFor() mismatch (-want +got):\n%s", diff)
+	}
+
+	type node struct {
+		Children []node `json:"children"`
+	}
+	if _, err := InteractionResponseFormatFor[node](); err == nil {
+		t.Errorf("InteractionResponseFormatFor() of a recursive type succeeded, want error")
+	}
+}
+
+func TestInteractionOutputInto(t *testing.T) {
+	interaction := &Interaction{Outputs: []*InteractionContent{
+		{Type: "thought", Summary: []*InteractionContent{NewInteractionText("Planning.")}},
+		NewInteractionText("```json\n{\"title\": \"Cookies\", \"servings\": 12, \"ingredients\": [{\"name\": \"flour\", \"quantity\": 2.5}]}\n```"),
+	}}
+	var got testRecipe
+	if err := interaction.OutputInto(&got); err != nil {
+		t.Fatalf("OutputInto() failed: %v", err)
+	}
+	want := testRecipe{Title: "Cookies", Servings: 12, Ingredients: []testIngredient{{Name: "flour", Quantity: 2.5}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("OutputInto() mismatch (-want +got):\n%s", diff)
+	}
+
+	invalid := &Interaction{Outputs: []*InteractionContent{NewInteractionText(`{"title": 1, "servings": 1.5, "ingredients": [{}]}`)}}
+	var verr *ValidationError
+	if err := invalid.OutputInto(&got); !errors.As(err, &verr) {
+		t.Fatalf("OutputInto() = %v, want a *ValidationError", err)
+	}
+	wantViolations := []FieldViolation{
+		{Field: "ingredients[0].name", Description: "is required"},
+		{Field: "servings", Description: "must be an integer, got a number"},
+		{Field: "title", Description: "must be a string, got a number"},
+	}
+	if diff := cmp.Diff(wantViolations, verr.Violations); diff != "" {
+		t.Errorf("violations mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := (&Interaction{}).OutputInto(&got); err == nil {
+		t.Errorf("OutputInto() without text output succeeded, want error")
+	}
+	if err := interaction.OutputInto(got); err == nil {
+		t.Errorf("OutputInto() with a non-pointer succeeded, want error")
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+const (
+	defaultResumeMaxAttempts  = 3
+	defaultResumeInitialDelay = 500 * time.Millisecond
+)
+
+// StreamResumePolicy configures how CreateStream resumes an interaction stream
+// that fails with a network error, a rate limit or a server error. The stream
+// is resumed with GetStream after the last event received, so that the caller
+// sees every event exactly once. A stream can only be resumed once the
+// interaction ID has been received; earlier errors are returned as is.
+type StreamResumePolicy struct {
+	// MaxAttempts is the maximum number of consecutive attempts to resume the
+	// stream. The count is reset by every event received. Defaults to 3.
+	MaxAttempts int
+	// InitialDelay is the delay before the first attempt, doubled for every
+	// following attempt. Defaults to 500ms.
+	InitialDelay time.Duration
+}
+
+// resumeOnError wraps events, the stream of a created interaction, so that
+// transient errors resume the stream according to policy instead of being
+// returned. The resumed events are checked by guard, the guard of events.
+func (i *Interactions) resumeOnError(ctx context.Context, events iter.Seq2[*InteractionEvent, error], httpOptions *HTTPOptions, policy *StreamResumePolicy, guard *streamGuard) iter.Seq2[*InteractionEvent, error] {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultResumeMaxAttempts
+	}
+	initialDelay := policy.InitialDelay
+	if initialDelay <= 0 {
+		initialDelay = defaultResumeInitialDelay
+	}
+	return func(yield func(*InteractionEvent, error) bool) {
+		var id, lastEventID string
+		attempts := 0
+		for {
+			var interrupted error
+			for event, err := range events {
+				if err != nil {
+					if id != "" && attempts < maxAttempts && isTransientError(err) {
+						interrupted = err
+						break
+					}
+					if !yield(nil, err) {
+						return
+					}
+					continue
+				}
+				attempts = 0
+				if event.Interaction != nil && event.Interaction.ID != "" {
+					id = event.Interaction.ID
+				}
+				if event.EventID != "" {
+					lastEventID = event.EventID
+				}
+				if !yield(event, nil) {
+					return
+				}
+			}
+			if interrupted == nil {
+				return
+			}
+			timer := time.NewTimer(initialDelay << attempts)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				yield(nil, interrupted)
+				return
+			case <-timer.C:
+			}
+			attempts++
+			resumed := i.getStream(ctx, id, &GetInteractionConfig{HTTPOptions: httpOptions, LastEventID: lastEventID})
+			events = guardInteractionEvents(guard, resumed)
+		}
+	}
+}
+
+// guardInteractionEvents runs the output guardrails of guard on every event of
+// events, like CreateStream does for the events it receives.
+func guardInteractionEvents(guard *streamGuard, events iter.Seq2[*InteractionEvent, error]) iter.Seq2[*InteractionEvent, error] {
+	return func(yield func(*InteractionEvent, error) bool) {
+		for event, err := range events {
+			if err == nil {
+				err = guard.guardEvent(event)
+				if err != nil {
+					event = nil
+				}
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+// withEventIDs returns a copy of ctx whose calls record the ID of every
+// server-sent event, still calling the OnSSEEvent of the request or the client
+// if set, and a function that sets that ID as the EventID of the
+// InteractionEvent decoded from the event.
+func (ac *apiClient) withEventIDs(ctx context.Context) (context.Context, func(*InteractionEvent)) {
+	var lastID string
+	onEvent := ac.requestOptions(ctx).OnSSEEvent
+	ctx = WithRequestOptions(ctx, &RequestOptions{OnSSEEvent: func(event *SSEEvent) {
+		lastID = event.ID
+		if onEvent != nil {
+			onEvent(event)
+		}
+	}})
+	return ctx, func(event *InteractionEvent) {
+		if event.EventID == "" {
+			event.EventID = lastID
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInteractionsCreateStreamResume(t *testing.T) {
+	ctx := context.Background()
+	var lastEventIDs []string
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch r.Method {
+		case http.MethodPost:
+			fmt.Fprint(w, "id: e1\ndata: {\"event_type\":\"interaction.start\",\"interaction\":{\"id\":\"int-1\",\"status\":\"in_progress\"}}\n\n")
+			fmt.Fprint(w, "id: e2\ndata: {\"event_type\":\"content.delta\",\"delta\":{\"type\":\"text\",\"text\":\"Hel\"}}\n\n")
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		case http.MethodGet:
+			if !strings.HasSuffix(r.URL.Path, "/interactions/int-1") {
+				t.Errorf("unexpected resume path %s", r.URL.Path)
+			}
+			lastEventIDs = append(lastEventIDs, r.URL.Query().Get("last_event_id"))
+			fmt.Fprint(w, "id: e3\ndata: {\"event_type\":\"content.delta\",\"delta\":{\"type\":\"text\",\"text\":\"lo\"}}\n\n")
+			fmt.Fprint(w, "id: e4\ndata: {\"event_type\":\"interaction.complete\",\"interaction\":{\"id\":\"int-1\",\"status\":\"completed\"}}\n\n")
+		}
+	})
+	interaction := &Interaction{Model: "gemini-3-flash-preview", Input: "Hi"}
+
+	t.Run("Resumed", func(t *testing.T) {
+		lastEventIDs = nil
+		config := &CreateInteractionConfig{ResumePolicy: &StreamResumePolicy{InitialDelay: time.Millisecond}}
+		var ids []string
+		var text string
+		for event, err := range client.Interactions.CreateStream(ctx, interaction, config) {
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, event.EventID)
+			if event.Delta != nil {
+				text += event.Delta.Text
+			}
+		}
+		if got, want := strings.Join(ids, ","), "e1,e2,e3,e4"; got != want {
+			t.Errorf("event IDs = %s, want %s", got, want)
+		}
+		if text != "Hello" {
+			t.Errorf("text = %q, want %q", text, "Hello")
+		}
+		if len(lastEventIDs) != 1 || lastEventIDs[0] != "e2" {
+			t.Errorf("resumed with last_event_id %v, want [e2]", lastEventIDs)
+		}
+	})
+
+	t.Run("NoPolicy", func(t *testing.T) {
+		lastEventIDs = nil
+		var gotErr error
+		for _, err := range client.Interactions.CreateStream(ctx, interaction, nil) {
+			if err != nil {
+				gotErr = err
+			}
+		}
+		if gotErr == nil {
+			t.Error("expected the interrupted stream to return an error")
+		}
+		if len(lastEventIDs) != 0 {
+			t.Errorf("expected no resume request, got %d", len(lastEventIDs))
+		}
+	})
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{context.Canceled, false},
+		{fmt.Errorf("wrapped: %w", context.DeadlineExceeded), false},
+		{APIError{Code: http.StatusBadRequest}, false},
+		{APIError{Code: http.StatusTooManyRequests}, true},
+		{APIError{Code: http.StatusServiceUnavailable}, true},
+		{fmt.Errorf("stream interrupted: %w", fmt.Errorf("unexpected EOF")), true},
+	}
+	for _, tt := range tests {
+		if got := isTransientError(tt.err); got != tt.want {
+			t.Errorf("isTransientError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
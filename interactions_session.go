@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"iter"
+	"slices"
+)
+
+// InteractionSession is a multi-turn conversation over the Interactions API.
+// Every turn is created with the PreviousInteractionID of the turn before, so
+// the service keeps the conversation state, and the session keeps a local
+// transcript of the turns.
+//
+//	session := client.Interactions.NewSession(&genai.Interaction{Model: "gemini-2.5-flash"}, nil)
+//	result, err := session.Send(ctx, "What is 1 + 2?")
+//	result, err = session.Send(ctx, "And times 3?")
+//
+// An InteractionSession is not safe for concurrent use.
+type InteractionSession struct {
+	interactions *Interactions
+	template     Interaction
+	config       *CreateInteractionConfig
+	previousID   string
+	transcript   []*InteractionTurn
+}
+
+// NewSession starts a conversation whose turns are created from interaction,
+// e.g. with its Model or Agent, SystemInstruction, Tools and
+// GenerationConfig, and with config. The Input of interaction is ignored. If
+// interaction has a PreviousInteractionID, the session continues that
+// conversation.
+func (i *Interactions) NewSession(interaction *Interaction, config *CreateInteractionConfig) *InteractionSession {
+	s := &InteractionSession{interactions: i, config: config}
+	if interaction != nil {
+		s.template = *interaction
+	}
+	s.previousID = s.template.PreviousInteractionID
+	s.template.Input, s.template.PreviousInteractionID = nil, ""
+	s.template.ID, s.template.Status, s.template.Outputs, s.template.Usage, s.template.SDKHTTPResponse = "", "", nil, nil, nil
+	return s
+}
+
+// LastInteractionID returns the ID of the last interaction of the session,
+// which the next turn continues, or an empty string before the first turn.
+func (s *InteractionSession) LastInteractionID() string {
+	return s.previousID
+}
+
+// Transcript returns the turns of the session so far: the input of every turn
+// with the "user" role, or the turns of a multi-turn input, followed by the
+// outputs of the interaction with the "model" role.
+func (s *InteractionSession) Transcript() []*InteractionTurn {
+	return slices.Clone(s.transcript)
+}
+
+// Send creates the next turn of the conversation with input, which can be a
+// string, an *InteractionContent or []*InteractionContent like
+// Interaction.Input, and returns the interaction.
+func (s *InteractionSession) Send(ctx context.Context, input any) (*Interaction, error) {
+	response, err := s.interactions.Create(ctx, s.next(input), s.config)
+	if err != nil {
+		return nil, err
+	}
+	s.record(input, response.ID, response.Outputs)
+	return response, nil
+}
+
+// SendStream creates the next turn of the conversation with input like Send
+// and streams its events. The turn is recorded once the stream has been
+// consumed completely.
+func (s *InteractionSession) SendStream(ctx context.Context, input any) iter.Seq2[*InteractionEvent, error] {
+	return func(yield func(*InteractionEvent, error) bool) {
+		var acc InteractionAccumulator
+		for event, err := range s.interactions.CreateStream(ctx, s.next(input), s.config) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			acc.Add(event)
+			if !yield(event, nil) {
+				return
+			}
+		}
+		interaction := acc.Interaction()
+		s.record(input, interaction.ID, interaction.Outputs)
+	}
+}
+
+// next returns the interaction of the next turn with input.
+func (s *InteractionSession) next(input any) *Interaction {
+	interaction := s.template
+	interaction.Input = input
+	interaction.PreviousInteractionID = s.previousID
+	return &interaction
+}
+
+func (s *InteractionSession) record(input any, id string, outputs []*InteractionContent) {
+	if id != "" {
+		s.previousID = id
+	}
+	s.transcript = append(s.transcript, inputTurns(input)...)
+	s.transcript = append(s.transcript, &InteractionTurn{Role: RoleModel, Content: outputs})
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInteractionSession(t *testing.T) {
+	ctx := context.Background()
+	var requests []*Interaction
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		req := new(Interaction)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		requests = append(requests, req)
+		id := fmt.Sprintf("turn-%d", len(requests))
+		if r.URL.Query().Get("alt") == "sse" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "data: {\"event_type\": \"interaction.start\", \"interaction\": {\"id\": %q}}\n\n", id)
+			fmt.Fprint(w, "data: {\"event_type\": \"content.delta\", \"delta\": {\"type\": \"text\", \"text\": \"Nine\"}}\n\n")
+			fmt.Fprint(w, "data: {\"event_type\": \"content.delta\", \"delta\": {\"type\": \"text\", \"text\": \".\"}}\n\n")
+			fmt.Fprintf(w, "data: {\"event_type\": \"interaction.complete\", \"interaction\": {\"id\": %q, \"status\": \"completed\"}}\n\n", id)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %q, "status": "completed", "outputs": [{"type": "text", "text": "Three."}]}`, id)
+	})
+
+	session := client.Interactions.NewSession(&Interaction{Model: "gemini-2.5-flash", SystemInstruction: "Be brief.", Input: "ignored"}, nil)
+	if _, err := session.Send(ctx, "What is 1 + 2?"); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+	for _, err := range session.SendStream(ctx, &InteractionContent{Type: "text", Text: "And times 3?"}) {
+		if err != nil {
+			t.Fatalf("SendStream() failed: %v", err)
+		}
+	}
+	if _, err := session.Send(ctx, "Thanks"); err != nil {
+		t.Fatalf("Send() failed: %v", err)
+	}
+
+	var previousIDs []string
+	for _, req := range requests {
+		previousIDs = append(previousIDs, req.PreviousInteractionID)
+		if req.Model != "gemini-2.5-flash" || req.SystemInstruction != "Be brief." {
+			t.Errorf("request = %+v, want the model and system instruction of the session", req)
+		}
+	}
+	if diff := cmp.Diff([]string{"", "turn-1", "turn-2"}, previousIDs); diff != "" {
+		t.Errorf("PreviousInteractionID mismatch (-want +got):\n%s", diff)
+	}
+	if got := session.LastInteractionID(); got != "turn-3" {
+		t.Errorf("LastInteractionID() = %q, want turn-3", got)
+	}
+
+	want := []*InteractionTurn{
+		{Role: RoleUser, Content: "What is 1 + 2?"},
+		{Role: RoleModel, Content: []*InteractionContent{{Type: "text", Text: "Three."}}},
+		{Role: RoleUser, Content: []*InteractionContent{{Type: "text", Text: "And times 3?"}}},
+		{Role: RoleModel, Content: []*InteractionContent{{Type: "text", Text: "Nine."}}},
+		{Role: RoleUser, Content: "Thanks"},
+		{Role: RoleModel, Content: []*InteractionContent{{Type: "text", Text: "Three."}}},
+	}
+	if diff := cmp.Diff(want, session.Transcript()); diff != "" {
+		t.Errorf("Transcript() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -134,24 +134,20 @@ func TestInteractionsCreateStream(t *testing.T) {
 		w.Header().Set("Cache-Control", "no-cache")
 		w.Header().Set("Connection", "keep-alive")
 
-		resp1 := Interaction{
-			ID:     "test-id",
-			Status: "in_progress",
-			Outputs: []*InteractionContent{
-				{
-					Type: "text",
-					Text: "Part 1",
-				},
+		resp1 := InteractionEvent{
+			EventType: "content.delta",
+			Index:     0,
+			Delta: &InteractionContent{
+				Type: "text",
+				Text: "Part 1",
 			},
 		}
-		resp2 := Interaction{
-			ID:     "test-id",
-			Status: "completed",
-			Outputs: []*InteractionContent{
-				{
-					Type: "text",
-					Text: "Part 2",
-				},
+		resp2 := InteractionEvent{
+			EventType: "content.delta",
+			Index:     0,
+			Delta: &InteractionContent{
+				Type: "text",
+				Text: "Part 2",
 			},
 		}
 
@@ -186,8 +182,8 @@ func TestInteractionsCreateStream(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		if len(resp.Outputs) > 0 {
-			texts = append(texts, resp.Outputs[0].Text)
+		if resp.Delta != nil {
+			texts = append(texts, resp.Delta.Text)
 		}
 	}
 
@@ -276,3 +272,38 @@ func TestInteractionsCancel(t *testing.T) {
 		t.Errorf("expected status cancelled, got %s", resp.Status)
 	}
 }
+
+func TestInteractionStatus(t *testing.T) {
+	tests := []struct {
+		status                     InteractionStatus
+		known, terminal, succeeded bool
+	}{
+		{InteractionStatusInProgress, true, false, false},
+		{InteractionStatusRequiresAction, true, true, false},
+		{InteractionStatusCompleted, true, true, true},
+		{InteractionStatusFailed, true, true, false},
+		{InteractionStatusCancelled, true, true, false},
+		{InteractionStatusIncomplete, true, true, false},
+		{"paused", false, false, false},
+		{"", false, false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.status.IsKnown(); got != tt.known {
+			t.Errorf("%q.IsKnown() = %v, want %v", tt.status, got, tt.known)
+		}
+		if got := tt.status.IsTerminal(); got != tt.terminal {
+			t.Errorf("%q.IsTerminal() = %v, want %v", tt.status, got, tt.terminal)
+		}
+		if got := tt.status.Succeeded(); got != tt.succeeded {
+			t.Errorf("%q.Succeeded() = %v, want %v", tt.status, got, tt.succeeded)
+		}
+	}
+
+	var interaction Interaction
+	if err := json.Unmarshal([]byte(`{"id": "abc", "status": "paused"}`), &interaction); err != nil {
+		t.Fatalf("Unmarshal() of an unknown status failed: %v", err)
+	}
+	if interaction.Status != "paused" {
+		t.Errorf("Status = %q, want the unknown status kept as is", interaction.Status)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CountInteractionTokensConfig configuration for Interactions.CountTokens.
+type CountInteractionTokensConfig struct {
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+}
+
+// CountTokens counts the tokens of the input of an interaction together with
+// its system instruction and tools, as the model of the interaction would
+// count them for Create. The tokens of a previous interaction referenced by
+// PreviousInteractionID and of built-in tools other than Google Search, code
+// execution and URL context are not counted. Interactions with an agent are
+// not supported.
+func (i *Interactions) CountTokens(ctx context.Context, interaction *Interaction, config *CountInteractionTokensConfig) (*CountTokensResponse, error) {
+	var httpOptions *HTTPOptions
+	if config == nil || config.HTTPOptions == nil {
+		httpOptions = &HTTPOptions{}
+	} else {
+		httpOptions = config.HTTPOptions
+	}
+	interaction = i.withDefaults(interaction)
+	if interaction == nil || interaction.Model == "" {
+		return nil, fmt.Errorf("CountTokens: a model is required")
+	}
+	contents, err := interactionInputContents(interaction.Input)
+	if err != nil {
+		return nil, fmt.Errorf("CountTokens: %w", err)
+	}
+	var systemInstruction *Content
+	if interaction.SystemInstruction != "" {
+		systemInstruction = NewContentFromText(interaction.SystemInstruction, RoleUser)
+	}
+	tools := interactionTools(interaction.Tools)
+
+	if i.apiClient.clientConfig.Backend == BackendVertexAI {
+		models := Models{apiClient: i.apiClient}
+		return models.CountTokens(ctx, interaction.Model, contents, &CountTokensConfig{
+			HTTPOptions:       httpOptions,
+			SystemInstruction: systemInstruction,
+			Tools:             tools,
+		})
+	}
+
+	// The Gemini API counts system instructions and tools only as part of a
+	// full generate content request.
+	model, err := tModel(i.apiClient, interaction.Model)
+	if err != nil {
+		return nil, err
+	}
+	request := map[string]any{"model": model, "contents": contents}
+	if systemInstruction != nil {
+		request["systemInstruction"] = systemInstruction
+	}
+	if len(tools) > 0 {
+		request["tools"] = tools
+	}
+	body := make(map[string]any)
+	deepMarshal(map[string]any{"generateContentRequest": request}, &body)
+	responseMap, err := sendRequest(ctx, i.apiClient, model+":countTokens", http.MethodPost, body, httpOptions)
+	if err != nil {
+		return nil, err
+	}
+	var response = new(CountTokensResponse)
+	if err := mapToStruct(responseMap, response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// interactionInputContents converts the input of an Interaction to the
+// contents of a generate content request.
+func interactionInputContents(input any) ([]*Content, error) {
+	switch in := input.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return Text(in), nil
+	case *InteractionContent:
+		return []*Content{NewContentFromParts(interactionParts([]*InteractionContent{in}), RoleUser)}, nil
+	case []*InteractionContent:
+		return []*Content{NewContentFromParts(interactionParts(in), RoleUser)}, nil
+	case []*InteractionTurn:
+		contents := make([]*Content, 0, len(in))
+		for _, turn := range in {
+			if turn == nil {
+				continue
+			}
+			role := Role(RoleUser)
+			if turn.Role == RoleModel {
+				role = RoleModel
+			}
+			var parts []*Part
+			switch c := turn.Content.(type) {
+			case string:
+				parts = []*Part{NewPartFromText(c)}
+			case []*InteractionContent:
+				parts = interactionParts(c)
+			default:
+				return nil, fmt.Errorf("unsupported turn content of type %T", turn.Content)
+			}
+			contents = append(contents, NewContentFromParts(parts, role))
+		}
+		return contents, nil
+	}
+	return nil, fmt.Errorf("unsupported input of type %T", input)
+}
+
+// interactionParts converts interaction contents to parts. Contents without
+// an equivalent part, such as thoughts and built-in tool calls, are skipped.
+func interactionParts(contents []*InteractionContent) []*Part {
+	var parts []*Part
+	for _, c := range contents {
+		if c == nil {
+			continue
+		}
+		switch c.Type {
+		case "text":
+			parts = append(parts, NewPartFromText(c.Text))
+		case "image", "audio", "video", "document":
+			if c.URI != "" {
+				parts = append(parts, NewPartFromURI(c.URI, c.MIMEType))
+			} else {
+				parts = append(parts, NewPartFromBytes(c.Data, c.MIMEType))
+			}
+		case "function_call":
+			args, _ := interactionArguments(c.Arguments)
+			parts = append(parts, &Part{FunctionCall: &FunctionCall{ID: c.ID, Name: c.Name, Args: args}})
+		case "function_result":
+			parts = append(parts, &Part{FunctionResponse: &FunctionResponse{ID: c.CallID, Name: c.Name, Response: map[string]any{"output": c.Result}}})
+		}
+	}
+	return parts
+}
+
+// interactionTools converts interaction tools to the tools of a generate
+// content request. Function tools are combined into a single tool.
+func interactionTools(tools []*InteractionTool) []*Tool {
+	var result []*Tool
+	var functions *Tool
+	for _, t := range tools {
+		if t == nil {
+			continue
+		}
+		switch t.Type {
+		case "function":
+			if functions == nil {
+				functions = &Tool{}
+				result = append(result, functions)
+			}
+			functions.FunctionDeclarations = append(functions.FunctionDeclarations, &FunctionDeclaration{
+				Name:                 t.Name,
+				Description:          t.Description,
+				ParametersJsonSchema: t.Parameters,
+			})
+		case "google_search":
+			result = append(result, &Tool{GoogleSearch: &GoogleSearch{}})
+		case "code_execution":
+			result = append(result, &Tool{CodeExecution: &ToolCodeExecution{}})
+		case "url_context":
+			result = append(result, &Tool{URLContext: &URLContext{}})
+		}
+	}
+	return result
+}
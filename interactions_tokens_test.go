@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestInteractionsCountTokens(t *testing.T) {
+	ctx := context.Background()
+	var path string
+	var body map[string]any
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"totalTokens": 42}`))
+	})
+
+	resp, err := client.Interactions.CountTokens(ctx, &Interaction{
+		Model:             "gemini-2.5-flash",
+		SystemInstruction: "Be brief.",
+		Input: []*InteractionTurn{
+			{Role: "user", Content: "What is the weather in Paris?"},
+			{Role: "model", Content: []*InteractionContent{{Type: "function_call", ID: "1", Name: "get_weather", Arguments: map[string]any{"city": "Paris"}}}},
+			{Role: "user", Content: []*InteractionContent{{Type: "function_result", CallID: "1", Name: "get_weather", Result: "sunny"}}},
+		},
+		Tools: []*InteractionTool{
+			{Type: "function", Name: "get_weather", Parameters: map[string]any{"type": "object"}},
+			{Type: "google_search"},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("CountTokens() failed: %v", err)
+	}
+	if resp.TotalTokens != 42 {
+		t.Errorf("TotalTokens = %d, want 42", resp.TotalTokens)
+	}
+	if path != "/v1beta/models/gemini-2.5-flash:countTokens" {
+		t.Errorf("path = %q, want the countTokens method of the model", path)
+	}
+	want := map[string]any{"generateContentRequest": map[string]any{
+		"model": "models/gemini-2.5-flash",
+		"contents": []any{
+			map[string]any{"role": "user", "parts": []any{map[string]any{"text": "What is the weather in Paris?"}}},
+			map[string]any{"role": "model", "parts": []any{map[string]any{"functionCall": map[string]any{"id": "1", "name": "get_weather", "args": map[string]any{"city": "Paris"}}}}},
+			map[string]any{"role": "user", "parts": []any{map[string]any{"functionResponse": map[string]any{"id": "1", "name": "get_weather", "response": map[string]any{"output": "sunny"}}}}},
+		},
+		"systemInstruction": map[string]any{"role": "user", "parts": []any{map[string]any{"text": "Be brief."}}},
+		"tools": []any{
+			map[string]any{"functionDeclarations": []any{map[string]any{"name": "get_weather", "parametersJsonSchema": map[string]any{"type": "object"}}}},
+			map[string]any{"googleSearch": map[string]any{}},
+		},
+	}}
+	if diff := cmp.Diff(want, body); diff != "" {
+		t.Errorf("request body mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := client.Interactions.CountTokens(ctx, &Interaction{Agent: DefaultResearchAgent, Input: "Hi"}, nil); err == nil {
+		t.Error("CountTokens() with an agent succeeded, want an error")
+	}
+	if _, err := client.Interactions.CountTokens(ctx, &Interaction{Model: "gemini-2.5-flash", Input: 42}, nil); err == nil {
+		t.Error("CountTokens() with an unsupported input succeeded, want an error")
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultWaitInitialInterval = time.Second
+	defaultWaitMaxInterval     = 30 * time.Second
+	defaultWaitMultiplier      = 2
+)
+
+// WaitConfig configures Interactions.Wait, Files.WaitForActive,
+// Operations.WaitVideos and Tunings.Watch.
+type WaitConfig struct {
+	// Optional. The delay before the second poll. Defaults to 1 second.
+	InitialInterval time.Duration
+	// Optional. The maximum delay between two polls. Defaults to 30 seconds.
+	MaxInterval time.Duration
+	// Optional. The factor the delay grows by after every poll, e.g. 1 to poll
+	// at a constant interval. Defaults to 2.
+	Multiplier float64
+	// Optional. How long to wait for the interaction to finish or the file to
+	// become active. If it is exceeded, Wait returns a *WaitTimeoutError and
+	// WaitForActive a *FileWaitTimeoutError, and Watch yields and WaitVideos
+	// returns an error that wraps context.DeadlineExceeded. The interaction
+	// keeps running. If zero, they wait until ctx is done.
+	Timeout time.Duration
+	// Optional. Called with every polled interaction, e.g. to report its
+	// status.
+	OnUpdate func(*Interaction)
+	// Optional. Called with every polled file of Files.WaitForActive.
+	OnFileUpdate func(*File)
+	// Optional. Called with every polled operation of Operations.WaitVideos,
+	// e.g. to report its progress from its metadata.
+	OnVideosOperationUpdate func(*GenerateVideosOperation)
+	// Optional. Used for every call to Interactions.Get, Files.Get,
+	// Operations.GetVideosOperation or Tunings.Get.
+	HTTPOptions *HTTPOptions
+}
+
+// WaitTimeoutError is returned by Interactions.Wait when the interaction did
+// not finish within WaitConfig.Timeout. It wraps context.DeadlineExceeded.
+type WaitTimeoutError struct {
+	// InteractionID is the ID of the interaction waited for.
+	InteractionID string
+	// Timeout is the exceeded WaitConfig.Timeout.
+	Timeout time.Duration
+	// Last is the last polled interaction, or nil if none was polled.
+	Last *Interaction
+}
+
+// Error returns a string representation of the WaitTimeoutError.
+func (e *WaitTimeoutError) Error() string {
+	status := InteractionStatus("unknown")
+	if e.Last != nil {
+		status = e.Last.Status
+	}
+	return fmt.Sprintf("interaction %s did not finish within %v, last status %q", e.InteractionID, e.Timeout, status)
+}
+
+// Unwrap returns context.DeadlineExceeded.
+func (e *WaitTimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+// Wait polls the background interaction id until it reaches a terminal
+// status, i.e. completed, failed, cancelled, incomplete or requires_action,
+// and returns it. The delay between polls starts at config.InitialInterval
+// and grows by config.Multiplier up to config.MaxInterval. An interaction that
+// failed is returned without an error, check its Status.
+//
+// If ctx is done first, Wait returns ctx.Err(). If config.Timeout is exceeded
+// first, it returns a *WaitTimeoutError.
+func (i *Interactions) Wait(ctx context.Context, id string, config *WaitConfig) (*Interaction, error) {
+	if config == nil {
+		config = &WaitConfig{}
+	}
+	get := func(ctx context.Context) (*Interaction, error) {
+		got, err := i.Get(ctx, id, &GetInteractionConfig{HTTPOptions: config.HTTPOptions})
+		if err == nil && config.OnUpdate != nil {
+			config.OnUpdate(got)
+		}
+		return got, err
+	}
+	got, timedOut, err := poll(ctx, config, get, func(got *Interaction) bool { return got.Status.IsTerminal() })
+	if timedOut {
+		return nil, &WaitTimeoutError{InteractionID: id, Timeout: config.Timeout, Last: got}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return got, nil
+}
+
+// poll calls get until done reports true for its result, with the delays and
+// the timeout of config, and returns the last result that get returned. It
+// reports whether config.Timeout was exceeded, in which case the error is
+// context.DeadlineExceeded.
+func poll[T any](ctx context.Context, config *WaitConfig, get func(ctx context.Context) (T, error), done func(T) bool) (T, bool, error) {
+	interval := config.InitialInterval
+	if interval <= 0 {
+		interval = defaultWaitInitialInterval
+	}
+	maxInterval := config.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaultWaitMaxInterval
+	}
+	multiplier := config.Multiplier
+	if multiplier <= 0 {
+		multiplier = defaultWaitMultiplier
+	}
+	waitCtx := ctx
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	var last T
+	timedOut := func() bool {
+		return ctx.Err() == nil && waitCtx.Err() != nil
+	}
+	for {
+		got, err := get(waitCtx)
+		if err != nil {
+			return last, timedOut(), err
+		}
+		last = got
+		if done(got) {
+			return got, false, nil
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-waitCtx.Done():
+			timer.Stop()
+			return last, timedOut(), waitCtx.Err()
+		case <-timer.C:
+		}
+		interval = min(time.Duration(float64(interval)*multiplier), maxInterval)
+	}
+}
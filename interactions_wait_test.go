@@ -0,0 +1,73 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestInteractionsWait(t *testing.T) {
+	ctx := context.Background()
+	var polls []time.Time
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		polls = append(polls, time.Now())
+		status := "in_progress"
+		if r.URL.Path == "/v1beta/interactions/done" && len(polls) >= 4 {
+			status = "completed"
+		}
+		fmt.Fprintf(w, `{"id": "done", "status": %q}`, status)
+	})
+
+	var statuses []InteractionStatus
+	got, err := client.Interactions.Wait(ctx, "done", &WaitConfig{
+		InitialInterval: 5 * time.Millisecond,
+		MaxInterval:     20 * time.Millisecond,
+		Multiplier:      3,
+		OnUpdate:        func(i *Interaction) { statuses = append(statuses, i.Status) },
+	})
+	if err != nil {
+		t.Fatalf("Wait() failed: %v", err)
+	}
+	if got.Status != "completed" || len(statuses) != 4 {
+		t.Errorf("Wait() = %+v after %d polls, want completed after 4 polls", got, len(statuses))
+	}
+	// The delays are 5ms, 15ms and then capped at 20ms.
+	for i, want := range []time.Duration{5 * time.Millisecond, 15 * time.Millisecond, 20 * time.Millisecond} {
+		if d := polls[i+1].Sub(polls[i]); d < want {
+			t.Errorf("delay before poll %d = %v, want at least %v", i+2, d, want)
+		}
+	}
+
+	polls = nil
+	_, err = client.Interactions.Wait(ctx, "running", &WaitConfig{InitialInterval: 5 * time.Millisecond, Timeout: 30 * time.Millisecond})
+	var timeoutErr *WaitTimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() = %v, want a *WaitTimeoutError", err)
+	}
+	if timeoutErr.InteractionID != "running" || timeoutErr.Last == nil || timeoutErr.Last.Status != "in_progress" {
+		t.Errorf("WaitTimeoutError = %+v, want the last polled interaction", timeoutErr)
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := client.Interactions.Wait(cancelCtx, "running", &WaitConfig{Timeout: time.Minute}); !errors.Is(err, context.Canceled) || errors.As(err, &timeoutErr) {
+		t.Errorf("Wait() with a cancelled context = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// InterceptedCall is an API call as seen by the Interceptors of a client.
+// Interceptors may modify it before sending it.
+type InterceptedCall struct {
+	// Method is the HTTP method of the call, e.g. "POST".
+	Method string
+	// Path is the path of the call relative to the API version, including
+	// its query, e.g. "models/gemini-2.5-flash:generateContent".
+	Path string
+	// Body is the JSON request body of the call, or nil if it has none.
+	Body map[string]any
+	// Header holds the headers set on the request in addition to, or in
+	// place of, the headers of the HTTPOptions of the call.
+	Header http.Header
+	// Stream reports whether the response is a stream of server-sent events.
+	Stream bool
+}
+
+// Invoker sends an API call and returns its HTTP response.
+type Invoker func(ctx context.Context, call *InterceptedCall) (*http.Response, error)
+
+// Interceptor intercepts the API calls of a client, such as to rotate
+// authorization headers, audit or rewrite requests. It sends call by calling
+// next, possibly more than once or with a modified ctx or call, and returns
+// the response, which it may inspect or replace. Responses with an error
+// status are returned as is and converted to an APIError after the
+// interceptors return. The body of the response, a stream of server-sent
+// events for streaming calls, is read by the SDK: an interceptor that reads it
+// must replace it.
+//
+//	audit := func(ctx context.Context, call *genai.InterceptedCall, next genai.Invoker) (*http.Response, error) {
+//		resp, err := next(ctx, call)
+//		if err == nil {
+//			log.Printf("%s %s: %s", call.Method, call.Path, resp.Status)
+//		}
+//		return resp, err
+//	}
+type Interceptor func(ctx context.Context, call *InterceptedCall, next Invoker) (*http.Response, error)
+
+// chainInterceptors returns an Invoker that calls interceptors in order, the
+// first one outermost, and invoke last.
+func chainInterceptors(interceptors []Interceptor, invoke Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(ctx context.Context, call *InterceptedCall) (*http.Response, error) {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return invoke
+}
+
+// interceptedBody returns the request body as the map of its JSON encoding.
+// Numbers are kept as json.Number so that they are encoded unchanged.
+func interceptedBody(body any) (map[string]any, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return b, nil
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("interceptedBody: error encoding body %#v: %w", body, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var bodyMap map[string]any
+	if err := dec.Decode(&bodyMap); err != nil {
+		return nil, fmt.Errorf("interceptedBody: body %T is not a JSON object: %w", body, err)
+	}
+	return bodyMap, nil
+}
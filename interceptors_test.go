@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestInterceptors(t *testing.T) {
+	ctx := context.Background()
+	var gotAuth, gotBody []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = append(gotAuth, r.Header.Get("X-Rotated-Token"))
+		body, _ := io.ReadAll(r.Body)
+		gotBody = append(gotBody, string(body))
+		if strings.Contains(r.URL.RawQuery, "alt=sse") {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"role\":\"model\",\"parts\":[{\"text\":\"streamed\"}]}}]}\n\n")
+			return
+		}
+		fmt.Fprint(w, `{"candidates":[{"content":{"role":"model","parts":[{"text":"unary"}]}}]}`)
+	}
+
+	var calls []string
+	token := 0
+	client := newTestClient(t, &ClientConfig{
+		Interceptors: []Interceptor{
+			func(ctx context.Context, call *InterceptedCall, next Invoker) (*http.Response, error) {
+				calls = append(calls, fmt.Sprintf("outer %s %s stream=%v", call.Method, call.Path, call.Stream))
+				resp, err := next(ctx, call)
+				if err == nil {
+					calls = append(calls, fmt.Sprintf("outer %d", resp.StatusCode))
+				}
+				return resp, err
+			},
+			func(ctx context.Context, call *InterceptedCall, next Invoker) (*http.Response, error) {
+				calls = append(calls, "inner")
+				token++
+				call.Header.Set("X-Rotated-Token", fmt.Sprintf("token-%d", token))
+				call.Body["labels"] = map[string]any{"audited": "true"}
+				return next(ctx, call)
+			},
+		},
+	}, handler)
+
+	resp, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Hi"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "unary" {
+		t.Errorf("Text() = %q, want unary", resp.Text())
+	}
+	for resp, err := range client.Models.GenerateContentStream(ctx, "gemini-2.5-flash", Text("Hi"), nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Text() != "streamed" {
+			t.Errorf("Text() = %q, want streamed", resp.Text())
+		}
+	}
+
+	wantCalls := []string{
+		"outer POST models/gemini-2.5-flash:generateContent stream=false", "inner", "outer 200",
+		"outer POST models/gemini-2.5-flash:streamGenerateContent?alt=sse stream=true", "inner", "outer 200",
+	}
+	if strings.Join(calls, "\n") != strings.Join(wantCalls, "\n") {
+		t.Errorf("calls = %q, want %q", calls, wantCalls)
+	}
+	if strings.Join(gotAuth, ",") != "token-1,token-2" {
+		t.Errorf("rotated tokens = %v, want [token-1 token-2]", gotAuth)
+	}
+	for _, body := range gotBody {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(body), &m); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := m["labels"]; !ok {
+			t.Errorf("body %s was not modified by the interceptor", body)
+		}
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	ctx := context.Background()
+	client, err := NewClient(ctx, &ClientConfig{
+		APIKey:      "test-api-key",
+		Backend:     BackendGeminiAPI,
+		HTTPOptions: HTTPOptions{BaseURL: "http://unused.invalid", APIVersion: "v1beta"},
+		Interceptors: []Interceptor{
+			func(ctx context.Context, call *InterceptedCall, next Invoker) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader(`{"candidates":[{"content":{"parts":[{"text":"cached"}]}}]}`)),
+				}, nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Hi"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Text() != "cached" {
+		t.Errorf("Text() = %q, want cached", resp.Text())
+	}
+}
+
+func TestInterceptedBody(t *testing.T) {
+	body, err := interceptedBody(struct {
+		Seed int64 `json:"seed"`
+	}{Seed: 9007199254740993})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "9007199254740993") {
+		t.Errorf("interceptedBody changed a large number: %s", data)
+	}
+	if body, err := interceptedBody(nil); err != nil || body != nil {
+		t.Errorf("interceptedBody(nil) = %v, %v, want nil, nil", body, err)
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	timeType           = reflect.TypeOf(time.Time{})
+	jsonRawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// jsonSchemaOf returns the JSON schema of the JSON encoding of values of type
+// t, as encoded by encoding/json, following the rules of SchemaFromType: the
+// properties of structs are listed in "propertyOrdering" in the order they
+// are declared, pointer fields are nullable, and jsonschema tags set the
+// description and enum of fields. Maps are objects with
+// "additionalProperties". Recursive types and types without a JSON encoding,
+// such as channels and functions, are an error.
+func jsonSchemaOf(t reflect.Type) (map[string]any, error) {
+	return jsonSchemaOfType(t, map[reflect.Type]bool{})
+}
+
+func jsonSchemaOfType(t reflect.Type, visiting map[reflect.Type]bool) (map[string]any, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}, nil
+	case jsonRawMessageType:
+		return map[string]any{}, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}, nil
+	case reflect.String:
+		return map[string]any{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]any{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return map[string]any{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := jsonSchemaOfType(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "array", "items": items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key type %v is not supported, only string keys are", t.Key())
+		}
+		values, err := jsonSchemaOfType(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case reflect.Struct:
+		if visiting[t] {
+			return nil, fmt.Errorf("recursive type %v is not supported", t)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		schema := map[string]any{"type": "object", "properties": map[string]any{}}
+		if err := addStructProperties(t, visiting, schema); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("type %v has no JSON schema", t)
+}
+
+// addStructProperties adds the properties of the exported fields of struct
+// type t to schema, with their order and the names of the required ones. The
+// fields of embedded structs without a json name are promoted.
+func addStructProperties(t reflect.Type, visiting map[reflect.Type]bool, schema map[string]any) error {
+	properties := schema["properties"].(map[string]any)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := addStructProperties(ft, visiting, schema); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		property, err := jsonSchemaOfType(ft, visiting)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		if err := applySchemaTag(property, f.Tag.Get("jsonschema")); err != nil {
+			return fmt.Errorf("field %s: %w", f.Name, err)
+		}
+		if ft.Kind() == reflect.Pointer {
+			if typ, ok := property["type"].(string); ok {
+				property["type"] = []string{typ, "null"}
+			}
+		} else if !strings.Contains(","+opts+",", ",omitempty,") && !strings.Contains(","+opts+",", ",omitzero,") {
+			required, _ := schema["required"].([]string)
+			schema["required"] = append(required, name)
+		}
+		properties[name] = property
+		ordering, _ := schema["propertyOrdering"].([]string)
+		schema["propertyOrdering"] = append(ordering, name)
+	}
+	return nil
+}
+
+// schemaTagOption matches the start of an option of a jsonschema tag.
+var schemaTagOption = regexp.MustCompile(`^[a-z]+=`)
+
+// applySchemaTag applies the options of a jsonschema struct tag to the schema
+// of the field. The options are separated by commas; a part that does not
+// start with an option name continues the description before it, so that a
+// description may contain commas.
+func applySchemaTag(schema map[string]any, tag string) error {
+	if tag == "" {
+		return nil
+	}
+	var options []string
+	for _, part := range strings.Split(tag, ",") {
+		if n := len(options); n > 0 && strings.HasPrefix(options[n-1], "description=") && !schemaTagOption.MatchString(part) {
+			options[n-1] += "," + part
+			continue
+		}
+		options = append(options, part)
+	}
+	for _, option := range options {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "description":
+			schema["description"] = value
+		case "enum":
+			target := schema
+			if target["type"] == "array" {
+				target = target["items"].(map[string]any)
+			}
+			if target["type"] != "string" {
+				return fmt.Errorf("enum requires a string type, got %v", target["type"])
+			}
+			target["enum"] = strings.Split(value, "|")
+		default:
+			return fmt.Errorf("unknown jsonschema tag option %q", key)
+		}
+	}
+	return nil
+}
+
+// jsonSchemaType returns the type of a JSON schema, without the "null" of a
+// nullable type.
+func jsonSchemaType(schema map[string]any) (typ string, nullable bool) {
+	switch t := schema["type"].(type) {
+	case string:
+		return t, false
+	case []string:
+		for _, name := range t {
+			if name == "null" {
+				nullable = true
+			} else {
+				typ = name
+			}
+		}
+	}
+	return typ, nullable
+}
+
+// validateJSONValue reports the violations of value, a JSON value decoded into
+// an any, against schema, a schema returned by jsonSchemaOf.
+func validateJSONValue(v *validator, field string, schema map[string]any, value any) {
+	typ, _ := jsonSchemaType(schema)
+	if typ == "" || value == nil {
+		// Null decodes into the zero value of every type.
+		return
+	}
+	at := field
+	if at == "" {
+		at = "(root)"
+	}
+	switch typ {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			v.addf(at, "must be an object, got %s", jsonTypeName(value))
+			return
+		}
+		required, _ := schema["required"].([]string)
+		for _, name := range required {
+			if _, ok := obj[name]; !ok {
+				v.addf(joinJSONPath(field, name), "is required")
+			}
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		additional, _ := schema["additionalProperties"].(map[string]any)
+		for _, name := range sortedKeys(obj) {
+			if p, ok := properties[name].(map[string]any); ok {
+				validateJSONValue(v, joinJSONPath(field, name), p, obj[name])
+			} else if additional != nil {
+				validateJSONValue(v, joinJSONPath(field, name), additional, obj[name])
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			v.addf(at, "must be an array, got %s", jsonTypeName(value))
+			return
+		}
+		items, _ := schema["items"].(map[string]any)
+		for i, item := range arr {
+			validateJSONValue(v, fmt.Sprintf("%s[%d]", field, i), items, item)
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != float64(int64(n)) {
+			v.addf(at, "must be an integer, got %s", jsonTypeName(value))
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			v.addf(at, "must be a number, got %s", jsonTypeName(value))
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			v.addf(at, "must be a string, got %s", jsonTypeName(value))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.addf(at, "must be a boolean, got %s", jsonTypeName(value))
+		}
+	}
+}
+
+func joinJSONPath(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "an array"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	}
+	return "null"
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Vertex AI attaches the labels of generate, batch and tuning requests to the
+// billing export, where they break down the spend. See
+// https://cloud.google.com/vertex-ai/generative-ai/docs/multimodal/add-labels-to-api-calls.
+
+const maxLabels = 64
+
+var (
+	labelKeyPattern   = regexp.MustCompile(`^\p{Ll}[\p{Ll}\p{Lo}\p{N}_-]{0,62}$`)
+	labelValuePattern = regexp.MustCompile(`^[\p{Ll}\p{Lo}\p{N}_-]{0,63}$`)
+)
+
+// validateLabels returns an error if labels do not meet the requirements of
+// Google Cloud labels: at most 64 labels, with keys that start with a
+// lowercase letter, and keys and values of at most 63 lowercase letters,
+// digits, underscores and dashes.
+func validateLabels(labels map[string]string) error {
+	if len(labels) > maxLabels {
+		return fmt.Errorf("at most %d labels are supported, got %d", maxLabels, len(labels))
+	}
+	for _, k := range sortedKeys(labels) {
+		if !labelKeyPattern.MatchString(k) {
+			return fmt.Errorf("invalid label key %q: keys must start with a lowercase letter and have at most 63 lowercase letters, digits, underscores and dashes", k)
+		}
+		if !labelValuePattern.MatchString(labels[k]) {
+			return fmt.Errorf("invalid value %q of label %q: values must have at most 63 lowercase letters, digits, underscores and dashes", labels[k], k)
+		}
+	}
+	return nil
+}
+
+// labelsHook merges ClientConfig.Labels and RequestOptions.Labels into the
+// labels of the body of the Vertex AI calls that support labels, and
+// validates them. Labels of the body, set in the config of the call, take
+// precedence.
+func (ac *apiClient) labelsHook(ctx context.Context, call *hookedCall) error {
+	if ac.clientConfig.Backend != BackendVertexAI || call.body == nil || !call.supportsLabels() {
+		return nil
+	}
+	labels := maps.Clone(ac.clientConfig.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	maps.Copy(labels, ac.requestOptions(ctx).Labels)
+	switch bodyLabels := call.body["labels"].(type) {
+	case map[string]any:
+		for k, v := range bodyLabels {
+			labels[k] = fmt.Sprint(v)
+		}
+	case map[string]string:
+		maps.Copy(labels, bodyLabels)
+	}
+	if len(labels) == 0 {
+		return nil
+	}
+	if err := validateLabels(labels); err != nil {
+		return err
+	}
+	call.body["labels"] = labels
+	return nil
+}
+
+// supportsLabels reports whether the call is a Vertex AI request that accepts
+// labels: a generate content, image, batch or tuning request.
+func (c *hookedCall) supportsLabels() bool {
+	switch {
+	case c.isMethod("generateContent", "streamGenerateContent"):
+		return true
+	case c.isMethod("predict"):
+		// Embedding models are also called with predict, but do not accept
+		// labels.
+		return !strings.Contains(c.path, "embedding")
+	case c.method == http.MethodPost:
+		collection := c.path[strings.LastIndex(c.path, "/")+1:]
+		return collection == "batchPredictionJobs" || collection == "tuningJobs"
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLabels(t *testing.T) {
+	ctx := context.Background()
+	var labels []any
+	client := newTestClient(t, &ClientConfig{
+		Backend: BackendVertexAI,
+		Labels:  map[string]string{"team": "search", "feature": "default"},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		labels = append(labels, body["labels"])
+		if strings.HasSuffix(r.URL.Path, "batchPredictionJobs") {
+			w.Write([]byte(`{"name": "projects/test-project/locations/us-central1/batchPredictionJobs/1"}`))
+			return
+		}
+		w.Write([]byte(`{"candidates": [{"content": {"role": "model", "parts": [{"text": "hi"}]}}]}`))
+	})
+
+	if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Hi"), nil); err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	config := &GenerateContentConfig{Labels: map[string]string{"feature": "summary"}}
+	if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Hi"), config); err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{"feature": "summary"}, config.Labels); diff != "" {
+		t.Errorf("GenerateContent() modified the labels of the config (-want +got):\n%s", diff)
+	}
+	batchCtx := WithRequestOptions(ctx, &RequestOptions{Labels: map[string]string{"feature": "batch"}})
+	if _, err := client.Batches.Create(batchCtx, "gemini-2.5-flash", &BatchJobSource{GCSURI: []string{"gs://bucket/in.jsonl"}}, nil); err != nil {
+		t.Fatalf("Batches.Create() failed: %v", err)
+	}
+
+	want := []any{
+		map[string]any{"team": "search", "feature": "default"},
+		map[string]any{"team": "search", "feature": "summary"},
+		map[string]any{"team": "search", "feature": "batch"},
+	}
+	if diff := cmp.Diff(want, labels); diff != "" {
+		t.Errorf("labels mismatch (-want +got):\n%s", diff)
+	}
+
+	invalid := &GenerateContentConfig{Labels: map[string]string{"Team": "search"}}
+	if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Hi"), invalid); err == nil {
+		t.Error("GenerateContent() with an invalid label key succeeded, want an error")
+	}
+	if _, err := NewClient(ctx, &ClientConfig{APIKey: "test-api-key", Backend: BackendGeminiAPI, Labels: map[string]string{"team": "search"}}); err == nil {
+		t.Error("NewClient() with labels for the Gemini API succeeded, want an error")
+	}
+}
@@ -38,6 +38,8 @@ import (
 //	session, _ := client.Live.Connect(ctx, model, &genai.LiveConnectConfig{}).
 type Live struct {
 	apiClient *apiClient
+	// Music provides access to realtime music generation.
+	Music *LiveMusic
 }
 
 // Preview. Session represents an active, real-time WebSocket connection to the
@@ -46,6 +48,10 @@ type Live struct {
 type Session struct {
 	conn      *websocket.Conn
 	apiClient *apiClient
+	// model and config are the arguments of Connect, used by Resume.
+	model      string
+	config     *LiveConnectConfig
+	resumption liveResumption
 }
 
 // Preview. Connect establishes a WebSocket connection to the specified
@@ -105,16 +111,39 @@ func (r *Live) Connect(context context.Context, model string, config *LiveConnec
 				Host:   baseURL.Host,
 				Path:   path.Join(baseURL.Path, fmt.Sprintf("ws/google.ai.generativelanguage.%s.GenerativeService.%s", httpOptions.APIVersion, method)),
 			}
+		} else if creds := r.apiClient.clientConfig.Credentials; creds != nil {
+			token, err := creds.Token(context)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get token: %w", err)
+			}
+			header.Set("Authorization", fmt.Sprintf("Bearer %s", token.Value))
+			quotaProjectID, err := creds.QuotaProjectID(context)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get quota project ID: %w", err)
+			}
+			if quotaProjectID == "" {
+				quotaProjectID = r.apiClient.clientConfig.Project
+			}
+			if quotaProjectID != "" {
+				header.Set("X-Goog-User-Project", quotaProjectID)
+			}
+			u = url.URL{
+				Scheme: scheme,
+				Host:   baseURL.Host,
+				Path:   path.Join(baseURL.Path, fmt.Sprintf("ws/google.ai.generativelanguage.%s.GenerativeService.BidiGenerateContent", httpOptions.APIVersion)),
+			}
 		}
 	}
 
-	conn, _, err := websocket.DefaultDialer.Dial(u.String(), header)
+	conn, _, err := r.apiClient.websocketDialer().DialContext(context, u.String(), header)
 	if err != nil {
 		return nil, fmt.Errorf("Connect to %s failed: %w", u.String(), err)
 	}
 	s := &Session{
 		conn:      conn,
 		apiClient: r.apiClient,
+		model:     model,
+		config:    cloneOf(config),
 	}
 	modelFullName, err := tModelFullName(r.apiClient, model)
 	if err != nil {
@@ -311,6 +340,7 @@ func (s *Session) Receive() (*LiveServerMessage, error) {
 	if err != nil {
 		return nil, err
 	}
+	s.resumption.update(message)
 	return message, err
 }
 
@@ -0,0 +1,274 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"mime"
+	"strconv"
+	"time"
+)
+
+// Sample rates of the audio of the Live API, which is 16-bit little-endian
+// mono PCM.
+const (
+	// LiveInputSampleRate is the sample rate the Live API expects for the
+	// audio sent with SendRealtimeInput. Audio at other rates is resampled
+	// by the server.
+	LiveInputSampleRate = 16000
+	// LiveOutputSampleRate is the sample rate of the audio the Live API
+	// responds with.
+	LiveOutputSampleRate = 24000
+)
+
+const defaultAudioChunkDuration = 100 * time.Millisecond
+
+// LiveAudioConfig configures how audio is sliced into realtime input chunks.
+type LiveAudioConfig struct {
+	// Optional. The sample rate of the audio, in Hz. Defaults to
+	// LiveInputSampleRate.
+	SampleRate int `json:"-"`
+	// Optional. The duration of the audio of a chunk. Defaults to 100ms.
+	ChunkDuration time.Duration `json:"-"`
+	// Optional. If true, SendAudio sends the chunks at the pace of the
+	// audio, as a microphone would, e.g. to stream a file. Otherwise they are
+	// sent as fast as they are read.
+	Paced bool `json:"-"`
+}
+
+func (c *LiveAudioConfig) sampleRate() int {
+	if c == nil || c.SampleRate <= 0 {
+		return LiveInputSampleRate
+	}
+	return c.SampleRate
+}
+
+func (c *LiveAudioConfig) chunkDuration() time.Duration {
+	if c == nil || c.ChunkDuration <= 0 {
+		return defaultAudioChunkDuration
+	}
+	return c.ChunkDuration
+}
+
+// PCMChunks returns an iterator over the 16-bit PCM audio of r sliced into
+// blobs of the duration of config, with the MIME type of their sample rate,
+// e.g. "audio/pcm;rate=16000", to send as the Audio of a LiveRealtimeInput.
+// The last chunk may be shorter. An error is yielded if r fails or ends in
+// the middle of a sample.
+func PCMChunks(r io.Reader, config *LiveAudioConfig) iter.Seq2[*Blob, error] {
+	rate := config.sampleRate()
+	size := int(int64(rate) * 2 * int64(config.chunkDuration()) / int64(time.Second))
+	size = max(2, size&^1)
+	mimeType := "audio/pcm;rate=" + strconv.Itoa(rate)
+	return func(yield func(*Blob, error) bool) {
+		for {
+			chunk := make([]byte, size)
+			n, err := io.ReadFull(r, chunk)
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+				yield(nil, fmt.Errorf("failed to read audio: %w", err))
+				return
+			}
+			if n%2 != 0 {
+				yield(nil, errors.New("the audio ends in the middle of a 16-bit sample"))
+				return
+			}
+			if !yield(&Blob{Data: chunk[:n], MIMEType: mimeType}, nil) {
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Preview. SendAudio sends the 16-bit PCM audio of r to the model in realtime
+// input chunks, see PCMChunks, until r ends or ctx is done. Then it sends
+// the end of the audio stream, so that the server flushes the audio it
+// buffered.
+func (s *Session) SendAudio(ctx context.Context, r io.Reader, config *LiveAudioConfig) error {
+	start := time.Now()
+	var sent time.Duration
+	for chunk, err := range PCMChunks(r, config) {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.SendRealtimeInput(LiveRealtimeInput{Audio: chunk}); err != nil {
+			return fmt.Errorf("failed to send audio: %w", err)
+		}
+		sent += time.Duration(len(chunk.Data)/2) * time.Second / time.Duration(config.sampleRate())
+		if config != nil && config.Paced {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Until(start.Add(sent))):
+			}
+		}
+	}
+	if err := s.SendRealtimeInput(LiveRealtimeInput{AudioStreamEnd: true}); err != nil {
+		return fmt.Errorf("failed to send the end of the audio stream: %w", err)
+	}
+	return nil
+}
+
+// LiveAudioBuffer reassembles the audio streamed by the model in the server
+// messages of a Live session into contiguous 16-bit PCM, e.g. to play it or
+// to save it as a WAV file.
+//
+//	var audio genai.LiveAudioBuffer
+//	for message, err := range session.Messages(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		audio.Add(message)
+//		if message.ServerContent != nil && message.ServerContent.TurnComplete {
+//			break
+//		}
+//	}
+//	err := audio.WriteWAV(f)
+//
+// When the model is interrupted, the audio of its turn that was not played
+// yet should be dropped, see Reset. The zero value is ready to use. A
+// LiveAudioBuffer is not safe for concurrent use.
+type LiveAudioBuffer struct {
+	data       bytes.Buffer
+	sampleRate int
+}
+
+// Add appends the PCM audio of the model turn of message to the buffer and
+// returns the number of bytes appended. Parts with other data are ignored.
+func (b *LiveAudioBuffer) Add(message *LiveServerMessage) int {
+	if message == nil || message.ServerContent == nil || message.ServerContent.ModelTurn == nil {
+		return 0
+	}
+	n := 0
+	for _, part := range message.ServerContent.ModelTurn.Parts {
+		if part == nil || part.InlineData == nil {
+			continue
+		}
+		rate, ok := pcmSampleRate(part.InlineData.MIMEType)
+		if !ok {
+			continue
+		}
+		if b.sampleRate == 0 {
+			b.sampleRate = rate
+		}
+		b.data.Write(part.InlineData.Data)
+		n += len(part.InlineData.Data)
+	}
+	return n
+}
+
+// Bytes returns the audio in the buffer. It is only valid until the next
+// call of Add or Reset.
+func (b *LiveAudioBuffer) Bytes() []byte {
+	return b.data.Bytes()
+}
+
+// Duration returns the duration of the audio in the buffer.
+func (b *LiveAudioBuffer) Duration() time.Duration {
+	return time.Duration(b.data.Len()/2) * time.Second / time.Duration(b.SampleRate())
+}
+
+// SampleRate returns the sample rate of the audio in the buffer, from the MIME
+// type of its first part, or LiveOutputSampleRate if it has none.
+func (b *LiveAudioBuffer) SampleRate() int {
+	if b.sampleRate == 0 {
+		return LiveOutputSampleRate
+	}
+	return b.sampleRate
+}
+
+// Reset empties the buffer.
+func (b *LiveAudioBuffer) Reset() {
+	b.data.Reset()
+	b.sampleRate = 0
+}
+
+// WriteWAV writes the audio in the buffer to w as a WAV file.
+func (b *LiveAudioBuffer) WriteWAV(w io.Writer) error {
+	return WriteWAV(w, b.data.Bytes(), b.SampleRate())
+}
+
+// WriteWAV writes the 16-bit little-endian mono PCM audio pcm of sampleRate
+// to w as a WAV file.
+func WriteWAV(w io.Writer, pcm []byte, sampleRate int) error {
+	if sampleRate <= 0 {
+		return fmt.Errorf("invalid sample rate %d", sampleRate)
+	}
+	const channels, bitsPerSample = 1, 16
+	header := struct {
+		RIFF          [4]byte
+		Size          uint32
+		WAVE          [4]byte
+		Fmt           [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}{
+		RIFF:          [4]byte{'R', 'I', 'F', 'F'},
+		Size:          uint32(36 + len(pcm)),
+		WAVE:          [4]byte{'W', 'A', 'V', 'E'},
+		Fmt:           [4]byte{'f', 'm', 't', ' '},
+		FmtSize:       16,
+		Format:        1,
+		Channels:      channels,
+		SampleRate:    uint32(sampleRate),
+		ByteRate:      uint32(sampleRate * channels * bitsPerSample / 8),
+		BlockAlign:    channels * bitsPerSample / 8,
+		BitsPerSample: bitsPerSample,
+		Data:          [4]byte{'d', 'a', 't', 'a'},
+		DataSize:      uint32(len(pcm)),
+	}
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return fmt.Errorf("failed to write WAV header: %w", err)
+	}
+	if _, err := w.Write(pcm); err != nil {
+		return fmt.Errorf("failed to write WAV data: %w", err)
+	}
+	return nil
+}
+
+// pcmSampleRate returns the sample rate of a PCM MIME type, e.g. 24000 for
+// "audio/pcm;rate=24000", or LiveOutputSampleRate if it has no rate.
+func pcmSampleRate(mimeType string) (int, bool) {
+	mediaType, params, err := mime.ParseMediaType(mimeType)
+	if err != nil || (mediaType != "audio/pcm" && mediaType != "audio/l16") {
+		return 0, false
+	}
+	rate, err := strconv.Atoi(params["rate"])
+	if err != nil || rate <= 0 {
+		return LiveOutputSampleRate, true
+	}
+	return rate, true
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/websocket"
+)
+
+func TestPCMChunks(t *testing.T) {
+	// 250ms of audio at 8kHz, in chunks of 100ms.
+	audio := bytes.Repeat([]byte{1, 2}, 2000)
+	var sizes []int
+	for chunk, err := range PCMChunks(bytes.NewReader(audio), &LiveAudioConfig{SampleRate: 8000}) {
+		if err != nil {
+			t.Fatalf("PCMChunks() failed: %v", err)
+		}
+		if chunk.MIMEType != "audio/pcm;rate=8000" {
+			t.Errorf("MIMEType = %q, want audio/pcm;rate=8000", chunk.MIMEType)
+		}
+		sizes = append(sizes, len(chunk.Data))
+	}
+	if diff := cmp.Diff([]int{1600, 1600, 800}, sizes); diff != "" {
+		t.Errorf("chunk sizes mismatch (-want +got):\n%s", diff)
+	}
+
+	var err error
+	for _, err = range PCMChunks(bytes.NewReader(make([]byte, 3)), nil) {
+	}
+	if err == nil {
+		t.Error("PCMChunks() of a partial sample succeeded, want an error")
+	}
+}
+
+func TestSessionSendAudio(t *testing.T) {
+	received := make(chan []string, 1)
+	session := liveTestSession(t, BackendGeminiAPI, nil, func(conn *websocket.Conn, setup string) {
+		var messages []string
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				break
+			}
+			messages = append(messages, string(message))
+			if strings.Contains(string(message), "audioStreamEnd") {
+				break
+			}
+		}
+		received <- messages
+	})
+	audio := make([]byte, LiveInputSampleRate*2/10+2)
+	if err := session.SendAudio(context.Background(), bytes.NewReader(audio), nil); err != nil {
+		t.Fatalf("SendAudio() failed: %v", err)
+	}
+	var got []int
+	for _, message := range <-received {
+		var m struct {
+			RealtimeInput struct {
+				Audio *Blob `json:"audio"`
+			} `json:"realtimeInput"`
+		}
+		if err := json.Unmarshal([]byte(message), &m); err != nil {
+			t.Fatal(err)
+		}
+		if m.RealtimeInput.Audio == nil {
+			got = append(got, -1)
+			continue
+		}
+		got = append(got, len(m.RealtimeInput.Audio.Data))
+	}
+	if diff := cmp.Diff([]int{3200, 2, -1}, got); diff != "" {
+		t.Errorf("sent chunks mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLiveAudioBuffer(t *testing.T) {
+	var b LiveAudioBuffer
+	messages := []*LiveServerMessage{
+		{SetupComplete: &LiveServerSetupComplete{}},
+		{ServerContent: &LiveServerContent{ModelTurn: &Content{Parts: []*Part{
+			{InlineData: &Blob{Data: []byte{1, 2}, MIMEType: "audio/pcm;rate=24000"}},
+			{Text: "ignored"},
+			{InlineData: &Blob{Data: []byte{9}, MIMEType: "image/png"}},
+		}}}},
+		{ServerContent: &LiveServerContent{ModelTurn: &Content{Parts: []*Part{
+			{InlineData: &Blob{Data: []byte{3, 4, 5, 6}, MIMEType: "audio/pcm"}},
+		}}}},
+	}
+	for _, message := range messages {
+		b.Add(message)
+	}
+	if diff := cmp.Diff([]byte{1, 2, 3, 4, 5, 6}, b.Bytes()); diff != "" {
+		t.Errorf("Bytes() mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := b.Duration(), 3*time.Second/24000; got != want {
+		t.Errorf("Duration() = %v, want %v", got, want)
+	}
+
+	var wav bytes.Buffer
+	if err := b.WriteWAV(&wav); err != nil {
+		t.Fatalf("WriteWAV() failed: %v", err)
+	}
+	data := wav.Bytes()
+	if len(data) != 50 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WAVE" || string(data[36:40]) != "data" {
+		t.Fatalf("WriteWAV() wrote %q, want a WAV file", data)
+	}
+	if got := binary.LittleEndian.Uint32(data[24:28]); got != 24000 {
+		t.Errorf("sample rate = %d, want 24000", got)
+	}
+	if got := binary.LittleEndian.Uint32(data[40:44]); got != 6 {
+		t.Errorf("data size = %d, want 6", got)
+	}
+
+	b.Reset()
+	if len(b.Bytes()) != 0 {
+		t.Errorf("Bytes() after Reset() = %v, want empty", b.Bytes())
+	}
+}
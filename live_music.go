@@ -0,0 +1,347 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"path"
+
+	"github.com/gorilla/websocket"
+)
+
+// Format of the audio of realtime music generation, which is 16-bit
+// little-endian PCM.
+const (
+	// LiveMusicSampleRate is the sample rate of the generated music.
+	LiveMusicSampleRate = 48000
+	// LiveMusicChannels is the number of interleaved channels of the
+	// generated music.
+	LiveMusicChannels = 2
+)
+
+// Scale is the musical scale of the generated music.
+type Scale string
+
+const (
+	// Default value. The model picks the scale.
+	ScaleUnspecified Scale = "SCALE_UNSPECIFIED"
+	// C major or A minor.
+	ScaleCMajorAMinor Scale = "C_MAJOR_A_MINOR"
+	// D flat major or B flat minor.
+	ScaleDFlatMajorBFlatMinor Scale = "D_FLAT_MAJOR_B_FLAT_MINOR"
+	// D major or B minor.
+	ScaleDMajorBMinor Scale = "D_MAJOR_B_MINOR"
+	// E flat major or C minor.
+	ScaleEFlatMajorCMinor Scale = "E_FLAT_MAJOR_C_MINOR"
+	// E major or D flat minor.
+	ScaleEMajorDFlatMinor Scale = "E_MAJOR_D_FLAT_MINOR"
+	// F major or D minor.
+	ScaleFMajorDMinor Scale = "F_MAJOR_D_MINOR"
+	// G flat major or E flat minor.
+	ScaleGFlatMajorEFlatMinor Scale = "G_FLAT_MAJOR_E_FLAT_MINOR"
+	// G major or E minor.
+	ScaleGMajorEMinor Scale = "G_MAJOR_E_MINOR"
+	// A flat major or F minor.
+	ScaleAFlatMajorFMinor Scale = "A_FLAT_MAJOR_F_MINOR"
+	// A major or G flat minor.
+	ScaleAMajorGFlatMinor Scale = "A_MAJOR_G_FLAT_MINOR"
+	// B flat major or G minor.
+	ScaleBFlatMajorGMinor Scale = "B_FLAT_MAJOR_G_MINOR"
+	// B major or A flat minor.
+	ScaleBMajorAFlatMinor Scale = "B_MAJOR_A_FLAT_MINOR"
+)
+
+// MusicGenerationMode is what the model emphasizes when generating music.
+type MusicGenerationMode string
+
+const (
+	// Default value. The model picks the mode.
+	MusicGenerationModeUnspecified MusicGenerationMode = "MUSIC_GENERATION_MODE_UNSPECIFIED"
+	// Steer the generation towards the quality of the music.
+	MusicGenerationModeQuality MusicGenerationMode = "QUALITY"
+	// Steer the generation towards more diverse music.
+	MusicGenerationModeDiversity MusicGenerationMode = "DIVERSITY"
+	// Let the model generate vocalizations.
+	MusicGenerationModeVocalization MusicGenerationMode = "VOCALIZATION"
+)
+
+// LiveMusicPlaybackControl is a command that controls the playback of a music
+// session.
+type LiveMusicPlaybackControl string
+
+const (
+	// Start or resume the generation of music.
+	LiveMusicPlaybackControlPlay LiveMusicPlaybackControl = "PLAY"
+	// Pause the generation of music.
+	LiveMusicPlaybackControlPause LiveMusicPlaybackControl = "PAUSE"
+	// Stop the generation of music and reset its context, keeping the prompts
+	// and the config.
+	LiveMusicPlaybackControlStop LiveMusicPlaybackControl = "STOP"
+	// Reset the context of the music generation, keeping the prompts and the
+	// config, e.g. to apply a new BPM or scale.
+	LiveMusicPlaybackControlResetContext LiveMusicPlaybackControl = "RESET_CONTEXT"
+)
+
+// WeightedPrompt is a prompt of the music, e.g. a genre, an instrument or a
+// mood, with its weight relative to the other prompts.
+type WeightedPrompt struct {
+	// Required. The text of the prompt.
+	Text string `json:"text,omitempty"`
+	// Required. The weight of the prompt. It must not be 0.
+	Weight float32 `json:"weight,omitempty"`
+}
+
+// LiveMusicClientContent is the prompts of a music session.
+type LiveMusicClientContent struct {
+	// Optional. The prompts of the music.
+	WeightedPrompts []*WeightedPrompt `json:"weightedPrompts,omitempty"`
+}
+
+// LiveMusicGenerationConfig configures the generation of music.
+type LiveMusicGenerationConfig struct {
+	// Optional. Controls the randomness of the music, from 0 to 3.
+	Temperature *float32 `json:"temperature,omitempty"`
+	// Optional. Limits the sampling to the k most likely tokens, from 1 to
+	// 1000.
+	TopK *int32 `json:"topK,omitempty"`
+	// Optional. The seed of the generation.
+	Seed *int32 `json:"seed,omitempty"`
+	// Optional. How strictly the model follows the prompts, from 0 to 6.
+	Guidance *float32 `json:"guidance,omitempty"`
+	// Optional. The beats per minute, from 60 to 200. A change is only
+	// applied after ResetContext.
+	BPM *int32 `json:"bpm,omitempty"`
+	// Optional. The density of the notes, from 0 to 1.
+	Density *float32 `json:"density,omitempty"`
+	// Optional. The brightness of the tone, from 0 to 1.
+	Brightness *float32 `json:"brightness,omitempty"`
+	// Optional. The scale of the music. A change is only applied after
+	// ResetContext.
+	Scale Scale `json:"scale,omitempty"`
+	// Optional. Whether to reduce the bass.
+	MuteBass *bool `json:"muteBass,omitempty"`
+	// Optional. Whether to reduce the drums.
+	MuteDrums *bool `json:"muteDrums,omitempty"`
+	// Optional. Whether to only generate bass and drums.
+	OnlyBassAndDrums *bool `json:"onlyBassAndDrums,omitempty"`
+	// Optional. What the model emphasizes.
+	MusicGenerationMode MusicGenerationMode `json:"musicGenerationMode,omitempty"`
+}
+
+// LiveMusicSourceMetadata is the prompts and the config a chunk of music was
+// generated from.
+type LiveMusicSourceMetadata struct {
+	// Optional. The prompts of the chunk.
+	ClientContent *LiveMusicClientContent `json:"clientContent,omitempty"`
+	// Optional. The config of the chunk.
+	MusicGenerationConfig *LiveMusicGenerationConfig `json:"musicGenerationConfig,omitempty"`
+}
+
+// AudioChunk is a chunk of generated music.
+type AudioChunk struct {
+	// Optional. The PCM audio of the chunk.
+	Data []byte `json:"data,omitempty"`
+	// Optional. The MIME type of the audio, e.g. "audio/l16;rate=48000;channels=2".
+	MIMEType string `json:"mimeType,omitempty"`
+	// Optional. The prompts and the config of the chunk.
+	SourceMetadata *LiveMusicSourceMetadata `json:"sourceMetadata,omitempty"`
+}
+
+// LiveMusicServerSetupComplete is sent when the setup of a music session is
+// complete.
+type LiveMusicServerSetupComplete struct{}
+
+// LiveMusicServerContent is the music generated by the server.
+type LiveMusicServerContent struct {
+	// Optional. The chunks of audio.
+	AudioChunks []*AudioChunk `json:"audioChunks,omitempty"`
+}
+
+// LiveMusicFilteredPrompt is a prompt that was filtered out.
+type LiveMusicFilteredPrompt struct {
+	// Optional. The text of the prompt.
+	Text string `json:"text,omitempty"`
+	// Optional. The reason the prompt was filtered out.
+	FilteredReason string `json:"filteredReason,omitempty"`
+}
+
+// LiveMusicServerMessage is a message of the server of a music session.
+type LiveMusicServerMessage struct {
+	// Optional. Sent in response to the setup of the session.
+	SetupComplete *LiveMusicServerSetupComplete `json:"setupComplete,omitempty"`
+	// Optional. The music generated by the server.
+	ServerContent *LiveMusicServerContent `json:"serverContent,omitempty"`
+	// Optional. A prompt that was filtered out.
+	FilteredPrompt *LiveMusicFilteredPrompt `json:"filteredPrompt,omitempty"`
+}
+
+// Preview. LiveMusic serves as the entry point for realtime music generation
+// with Lyria RealTime over WebSocket connections. It is only supported by the
+// Gemini API, with the v1alpha API version. Access it through the
+// `Live.Music` field of a `Client` instance.
+//
+//	session, _ := client.Live.Music.Connect(ctx, "models/lyria-realtime-exp")
+//	session.SetWeightedPrompts([]*genai.WeightedPrompt{{Text: "minimal techno", Weight: 1}})
+//	session.Play()
+//	for message, err := range session.Messages(ctx) {
+//		...
+//	}
+type LiveMusic struct {
+	apiClient *apiClient
+}
+
+// Preview. LiveMusicSession is a realtime music generation session, see
+// [LiveMusic.Connect].
+type LiveMusicSession struct {
+	conn *websocket.Conn
+}
+
+// Preview. Connect establishes a WebSocket connection to the music generation
+// model and sends the setup message of the session. The server answers with
+// a setupComplete message, the first message received on the session.
+func (m *LiveMusic) Connect(ctx context.Context, model string) (*LiveMusicSession, error) {
+	if m.apiClient.clientConfig.Backend == BackendVertexAI {
+		return nil, errors.New("live music is only supported in the Gemini Developer client. You can choose to use Gemini Developer client by setting ClientConfig.Backend to BackendGeminiAPI")
+	}
+	httpOptions := m.apiClient.clientConfig.HTTPOptions
+	if httpOptions.APIVersion == "" {
+		return nil, errors.New("live music requires APIVersion to be set. You can set APIVersion to v1alpha")
+	}
+	apiKey := m.apiClient.clientConfig.APIKey
+	if apiKey == "" {
+		return nil, errors.New("live music requires an API key")
+	}
+	baseURL, err := url.Parse(httpOptions.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base URL: %w", err)
+	}
+	scheme := baseURL.Scheme
+	if scheme != "wss" && scheme != "ws" {
+		scheme = "wss"
+	}
+	u := url.URL{
+		Scheme: scheme,
+		Host:   baseURL.Host,
+		Path:   path.Join(baseURL.Path, fmt.Sprintf("ws/google.ai.generativelanguage.%s.GenerativeService.BidiGenerateMusic", httpOptions.APIVersion)),
+	}
+	header := mergeHeaders(&httpOptions, nil)
+	header.Set("x-goog-api-key", apiKey)
+	conn, _, err := m.apiClient.websocketDialer().DialContext(ctx, u.String(), header)
+	if err != nil {
+		return nil, fmt.Errorf("Connect to %s failed: %w", u.String(), err)
+	}
+	modelFullName, err := tModelFullName(m.apiClient, model)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	s := &LiveMusicSession{conn: conn}
+	if err := s.send(map[string]any{"setup": map[string]any{"model": modelFullName}}); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to write the setup of the music session: %w", err)
+	}
+	return s, nil
+}
+
+// Preview. SetWeightedPrompts sets the prompts that steer the music. They
+// replace the prompts set before, and the music transitions to them.
+func (s *LiveMusicSession) SetWeightedPrompts(prompts []*WeightedPrompt) error {
+	if len(prompts) == 0 {
+		return errors.New("SetWeightedPrompts requires at least one prompt")
+	}
+	return s.send(map[string]any{"clientContent": &LiveMusicClientContent{WeightedPrompts: prompts}})
+}
+
+// Preview. SetMusicGenerationConfig sets the config of the generation. It
+// replaces the config set before; its unset fields get their defaults.
+func (s *LiveMusicSession) SetMusicGenerationConfig(config *LiveMusicGenerationConfig) error {
+	if config == nil {
+		config = &LiveMusicGenerationConfig{}
+	}
+	return s.send(map[string]any{"musicGenerationConfig": config})
+}
+
+// Preview. Play starts or resumes the generation of music.
+func (s *LiveMusicSession) Play() error {
+	return s.sendPlaybackControl(LiveMusicPlaybackControlPlay)
+}
+
+// Preview. Pause pauses the generation of music.
+func (s *LiveMusicSession) Pause() error {
+	return s.sendPlaybackControl(LiveMusicPlaybackControlPause)
+}
+
+// Preview. Stop stops the generation of music.
+func (s *LiveMusicSession) Stop() error {
+	return s.sendPlaybackControl(LiveMusicPlaybackControlStop)
+}
+
+// Preview. ResetContext resets the context of the generation, e.g. to apply a
+// change of the BPM or the scale of the config, keeping the prompts and the
+// config.
+func (s *LiveMusicSession) ResetContext() error {
+	return s.sendPlaybackControl(LiveMusicPlaybackControlResetContext)
+}
+
+func (s *LiveMusicSession) sendPlaybackControl(control LiveMusicPlaybackControl) error {
+	return s.send(map[string]any{"playbackControl": control})
+}
+
+func (s *LiveMusicSession) send(message map[string]any) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("marshal client message error: %w", err)
+	}
+	return s.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// Preview. Receive reads a LiveMusicServerMessage from the connection. It
+// blocks until a message is received.
+func (s *LiveMusicSession) Receive() (*LiveMusicServerMessage, error) {
+	messageType, msgBytes, err := s.conn.ReadMessage()
+	if err != nil {
+		return nil, err
+	}
+	var message struct {
+		LiveMusicServerMessage
+		Error json.RawMessage `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal(msgBytes, &message); err != nil {
+		return nil, fmt.Errorf("invalid message format. Error %w. messageType: %d, message: %s", err, messageType, msgBytes)
+	}
+	if message.Error != nil {
+		return nil, fmt.Errorf("received error in response: %v", string(msgBytes))
+	}
+	return &message.LiveMusicServerMessage, nil
+}
+
+// Preview. Messages returns an iterator over the messages the server sends
+// on the session, with the semantics of Session.Messages.
+func (s *LiveMusicSession) Messages(ctx context.Context) iter.Seq2[*LiveMusicServerMessage, error] {
+	return receiveMessages(ctx, s.conn, s.Receive)
+}
+
+// Preview. Close terminates the connection.
+func (s *LiveMusicSession) Close() error {
+	if s != nil && s.conn != nil {
+		return s.conn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/websocket"
+)
+
+func TestLiveMusicSession(t *testing.T) {
+	ctx := context.Background()
+	received := make(chan []string, 1)
+	var path, apiKey string
+	var upgrader websocket.Upgrader
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, apiKey = r.URL.Path, r.Header.Get("x-goog-api-key")
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		var messages []string
+		for len(messages) < 4 {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				break
+			}
+			messages = append(messages, string(message))
+		}
+		received <- messages
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"setupComplete":{}}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"serverContent":{"audioChunks":[{"data":"AQID","mimeType":"audio/l16;rate=48000;channels=2"}]}}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"filteredPrompt":{"text":"bad","filteredReason":"unsafe"}}`))
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		conn.ReadMessage()
+	}))
+	defer ts.Close()
+
+	client, err := NewClient(ctx, &ClientConfig{Backend: BackendGeminiAPI, APIKey: "test-api-key", HTTPOptions: HTTPOptions{APIVersion: "v1alpha", BaseURL: strings.Replace(ts.URL, "http", "ws", 1)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Live.Music.Connect(ctx, "lyria-realtime-exp")
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer session.Close()
+	if err := session.SetWeightedPrompts([]*WeightedPrompt{{Text: "minimal techno", Weight: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	bpm := int32(120)
+	if err := session.SetMusicGenerationConfig(&LiveMusicGenerationConfig{BPM: &bpm, Scale: ScaleDMajorBMinor}); err != nil {
+		t.Fatal(err)
+	}
+	if err := session.Play(); err != nil {
+		t.Fatal(err)
+	}
+	var got []*LiveMusicServerMessage
+	for message, err := range session.Messages(ctx) {
+		if err != nil {
+			t.Fatalf("Messages() failed: %v", err)
+		}
+		got = append(got, message)
+	}
+
+	wantSent := []string{
+		`{"setup":{"model":"models/lyria-realtime-exp"}}`,
+		`{"clientContent":{"weightedPrompts":[{"text":"minimal techno","weight":1}]}}`,
+		`{"musicGenerationConfig":{"bpm":120,"scale":"D_MAJOR_B_MINOR"}}`,
+		`{"playbackControl":"PLAY"}`,
+	}
+	if diff := cmp.Diff(wantSent, <-received); diff != "" {
+		t.Errorf("sent messages mismatch (-want +got):\n%s", diff)
+	}
+	want := []*LiveMusicServerMessage{
+		{SetupComplete: &LiveMusicServerSetupComplete{}},
+		{ServerContent: &LiveMusicServerContent{AudioChunks: []*AudioChunk{{Data: []byte{1, 2, 3}, MIMEType: "audio/l16;rate=48000;channels=2"}}}},
+		{FilteredPrompt: &LiveMusicFilteredPrompt{Text: "bad", FilteredReason: "unsafe"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Messages() mismatch (-want +got):\n%s", diff)
+	}
+	if want := "/ws/google.ai.generativelanguage.v1alpha.GenerativeService.BidiGenerateMusic"; path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if apiKey != "test-api-key" {
+		t.Errorf("x-goog-api-key = %q, want test-api-key", apiKey)
+	}
+
+	vertex := newTestClient(t, &ClientConfig{Backend: BackendVertexAI}, func(w http.ResponseWriter, r *http.Request) {})
+	if _, err := vertex.Live.Music.Connect(ctx, "lyria-realtime-exp"); err == nil {
+		t.Error("Connect() with a Vertex AI client succeeded, want an error")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Preview. Messages returns an iterator over the messages the server sends
+// on the session, decoded as Receive decodes them for the Gemini API and
+// Vertex AI backends:
+//
+//	for message, err := range session.Messages(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		if message.ServerContent != nil && message.ServerContent.TurnComplete {
+//			break
+//		}
+//	}
+//
+// The iterator ends without an error when the server closes the connection
+// normally. It yields the first error of Receive, e.g. an error message of the
+// server, and ends. If ctx is done, the pending read is interrupted and
+// ctx.Err() is yielded; the connection cannot be read anymore and the session
+// should be closed.
+//
+// Breaking out of the loop leaves the session open, so Messages can be called
+// again to receive the next messages, e.g. of the next turn.
+func (s *Session) Messages(ctx context.Context) iter.Seq2[*LiveServerMessage, error] {
+	return receiveMessages(ctx, s.conn, s.Receive)
+}
+
+// receiveMessages returns an iterator over the messages that receive reads
+// from conn, as Session.Messages describes.
+func receiveMessages[T any](ctx context.Context, conn *websocket.Conn, receive func() (T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		stop := context.AfterFunc(ctx, func() {
+			conn.SetReadDeadline(time.Now())
+		})
+		defer stop()
+		for {
+			message, err := receive()
+			if err != nil {
+				if ctx.Err() != nil {
+					yield(zero, ctx.Err())
+					return
+				}
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					return
+				}
+				yield(zero, err)
+				return
+			}
+			if !yield(message, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/auth"
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/websocket"
+)
+
+// liveTestSession returns a session connected to a test server on which serve
+// runs with the setup message of each connection.
+func liveTestSession(t *testing.T, backend Backend, config *LiveConnectConfig, serve func(conn *websocket.Conn, setup string)) *Session {
+	t.Helper()
+	ctx := context.Background()
+	var upgrader websocket.Upgrader
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("Upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		_, setup, err := conn.ReadMessage()
+		if err != nil {
+			t.Errorf("failed to read setup: %v", err)
+			return
+		}
+		serve(conn, string(setup))
+	}))
+	t.Cleanup(ts.Close)
+	clientConfig := &ClientConfig{Backend: backend, APIKey: "test-api-key"}
+	if backend == BackendVertexAI {
+		clientConfig = &ClientConfig{
+			Backend:     backend,
+			Project:     "test-project",
+			Location:    "test-location",
+			Credentials: auth.NewCredentials(&auth.CredentialsOptions{TokenProvider: mockCredentials{MockToken: &auth.Token{Value: "fake_access_token"}}}),
+		}
+	}
+	client, err := NewClient(ctx, clientConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Live.apiClient.clientConfig.HTTPOptions.BaseURL = strings.Replace(ts.URL, "http", "ws", 1)
+	client.Live.apiClient.clientConfig.HTTPClient = ts.Client()
+	session, err := client.Live.Connect(ctx, "test-model", config)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestSessionMessages(t *testing.T) {
+	ctx := context.Background()
+	for _, backend := range []Backend{BackendGeminiAPI, BackendVertexAI} {
+		t.Run(backend.String(), func(t *testing.T) {
+			session := liveTestSession(t, backend, nil, func(conn *websocket.Conn, setup string) {
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"setupComplete":{}}`))
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"serverContent":{"modelTurn":{"parts":[{"text":"hello"}]}}}`))
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"serverContent":{"turnComplete":true}}`))
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				conn.ReadMessage()
+			})
+			var got []*LiveServerMessage
+			for message, err := range session.Messages(ctx) {
+				if err != nil {
+					t.Fatalf("Messages() failed: %v", err)
+				}
+				got = append(got, message)
+			}
+			want := []*LiveServerMessage{
+				{SetupComplete: &LiveServerSetupComplete{}},
+				{ServerContent: &LiveServerContent{ModelTurn: &Content{Parts: []*Part{{Text: "hello"}}}}},
+				{ServerContent: &LiveServerContent{TurnComplete: true}},
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Messages() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		session := liveTestSession(t, BackendGeminiAPI, nil, func(conn *websocket.Conn, setup string) {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"error":{"code":400,"message":"bad request"}}`))
+			conn.ReadMessage()
+		})
+		var errs int
+		for _, err := range session.Messages(ctx) {
+			if err == nil || !strings.Contains(err.Error(), "bad request") {
+				t.Errorf("Messages() yielded %v, want the error of the server", err)
+			}
+			errs++
+		}
+		if errs != 1 {
+			t.Errorf("Messages() yielded %d errors, want 1", errs)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		done := make(chan struct{})
+		session := liveTestSession(t, BackendGeminiAPI, nil, func(conn *websocket.Conn, setup string) {
+			conn.WriteMessage(websocket.TextMessage, []byte(`{"setupComplete":{}}`))
+			<-done
+		})
+		defer close(done)
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		for message, err := range session.Messages(ctx) {
+			if err != nil {
+				if !errors.Is(err, context.Canceled) {
+					t.Errorf("Messages() failed with %v, want context.Canceled", err)
+				}
+				break
+			}
+			if message.SetupComplete == nil {
+				t.Errorf("Messages() yielded %+v, want setupComplete", message)
+			}
+			cancel()
+		}
+	})
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// liveResumption stores the latest resumption handle the server sent on a
+// session.
+type liveResumption struct {
+	mu     sync.Mutex
+	handle string
+}
+
+func (r *liveResumption) update(message *LiveServerMessage) {
+	update := message.SessionResumptionUpdate
+	if update == nil || !update.Resumable || update.NewHandle == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handle = update.NewHandle
+}
+
+func (r *liveResumption) get() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.handle
+}
+
+// NewSlidingWindowCompression returns a ContextWindowCompressionConfig that
+// truncates the context window of a Live session to its last targetTokens
+// tokens once it exceeds triggerTokens. A zero targetTokens lets the server
+// keep half of triggerTokens.
+func NewSlidingWindowCompression(triggerTokens, targetTokens int64) *ContextWindowCompressionConfig {
+	config := &ContextWindowCompressionConfig{TriggerTokens: &triggerTokens, SlidingWindow: &SlidingWindow{}}
+	if targetTokens > 0 {
+		config.SlidingWindow.TargetTokens = &targetTokens
+	}
+	return config
+}
+
+// Preview. ResumptionHandle returns the latest resumption handle received on
+// the session, or "" if none was received. The server only sends handles if
+// LiveConnectConfig.SessionResumption is set, and only at points where the
+// session can be resumed. The handle can be set as the
+// SessionResumptionConfig.Handle of a later Connect to resume the session.
+func (s *Session) ResumptionHandle() string {
+	return s.resumption.get()
+}
+
+// Preview. Resume reconnects the session with its latest resumption handle,
+// e.g. after the connection dropped or the server sent a GoAway message, so
+// the conversation continues with its state on the server. The current
+// connection is closed, and the next messages are sent and received on the
+// new one. Resume must not be called concurrently with the other methods of
+// the session.
+func (s *Session) Resume(ctx context.Context) error {
+	handle := s.ResumptionHandle()
+	if handle == "" {
+		return errors.New("the session has no resumption handle, set LiveConnectConfig.SessionResumption to receive them")
+	}
+	config := cloneOf(s.config)
+	if config == nil {
+		config = &LiveConnectConfig{}
+	}
+	if config.SessionResumption == nil {
+		config.SessionResumption = &SessionResumptionConfig{}
+	}
+	config.SessionResumption.Handle = handle
+	s.conn.Close()
+	live := Live{apiClient: s.apiClient}
+	next, err := live.Connect(ctx, s.model, config)
+	if err != nil {
+		return err
+	}
+	s.conn = next.conn
+	s.config = next.config
+	return nil
+}
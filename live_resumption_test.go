@@ -0,0 +1,70 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/websocket"
+)
+
+func TestSessionResume(t *testing.T) {
+	ctx := context.Background()
+	setups := make(chan string, 2)
+	config := &LiveConnectConfig{
+		SessionResumption:        &SessionResumptionConfig{},
+		ContextWindowCompression: NewSlidingWindowCompression(1000, 500),
+	}
+	session := liveTestSession(t, BackendGeminiAPI, config, func(conn *websocket.Conn, setup string) {
+		setups <- setup
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"sessionResumptionUpdate":{"newHandle":"handle-1","resumable":true}}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"sessionResumptionUpdate":{"resumable":false}}`))
+		conn.WriteMessage(websocket.TextMessage, []byte(`{"goAway":{"timeLeft":"1s"}}`))
+		conn.ReadMessage()
+	})
+	if err := session.Resume(ctx); err == nil {
+		t.Error("Resume() without a resumption handle succeeded, want an error")
+	}
+	for message, err := range session.Messages(ctx) {
+		if err != nil {
+			t.Fatalf("Messages() failed: %v", err)
+		}
+		if message.GoAway != nil {
+			break
+		}
+	}
+	if got := session.ResumptionHandle(); got != "handle-1" {
+		t.Fatalf("ResumptionHandle() = %q, want handle-1", got)
+	}
+	if err := session.Resume(ctx); err != nil {
+		t.Fatalf("Resume() failed: %v", err)
+	}
+	if _, err := session.Receive(); err != nil {
+		t.Fatalf("Receive() after Resume() failed: %v", err)
+	}
+	want := []string{
+		`{"setup":{"contextWindowCompression":{"slidingWindow":{"targetTokens":"500"},"triggerTokens":"1000"},"model":"models/test-model","sessionResumption":{}}}`,
+		`{"setup":{"contextWindowCompression":{"slidingWindow":{"targetTokens":"500"},"triggerTokens":"1000"},"model":"models/test-model","sessionResumption":{"handle":"handle-1"}}}`,
+	}
+	got := []string{<-setups, <-setups}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("setup messages mismatch (-want +got):\n%s", diff)
+	}
+	if config.SessionResumption.Handle != "" {
+		t.Errorf("Resume() modified the config of Connect: %+v", config.SessionResumption)
+	}
+}
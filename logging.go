@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// logBodyLevel returns the level of the log records of request and response
+// bodies.
+func (ac *apiClient) logBodyLevel() slog.Level {
+	if ac.clientConfig.LogBodyLevel != nil {
+		return *ac.clientConfig.LogBodyLevel
+	}
+	return slog.LevelDebug
+}
+
+// logRequest logs an attempt to send req with body.
+func (ac *apiClient) logRequest(ctx context.Context, req *http.Request, body any, attempt int) {
+	logger := ac.clientConfig.Logger
+	if logger == nil {
+		return
+	}
+	if logger.Enabled(ctx, slog.LevelDebug) {
+		logger.LogAttrs(ctx, slog.LevelDebug, "genai request",
+			slog.String("method", req.Method),
+			slog.String("url", redactURL(req.URL)),
+			slog.Int("attempt", attempt),
+			slog.Any("headers", redactedLogValue(req.Header)))
+	}
+	if body != nil {
+		ac.logBody(ctx, "genai request body", req, body)
+	}
+}
+
+// logResponse logs the response or error of req, sent at start.
+func (ac *apiClient) logResponse(ctx context.Context, req *http.Request, resp *http.Response, err error, start time.Time) {
+	logger := ac.clientConfig.Logger
+	if logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", redactURL(req.URL)),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelWarn, "genai request failed", append(attrs, slog.String("error", err.Error()))...)
+		return
+	}
+	level := slog.LevelDebug
+	if !httpStatusOk(resp) {
+		level = slog.LevelWarn
+	}
+	logger.LogAttrs(ctx, level, "genai response", append(attrs, slog.Int("status", resp.StatusCode))...)
+}
+
+// logBody logs the request or response body of req at the body level. Media
+// bytes and credentials are redacted, and long values are truncated.
+func (ac *apiClient) logBody(ctx context.Context, msg string, req *http.Request, body any) {
+	logger := ac.clientConfig.Logger
+	if logger == nil || !logger.Enabled(ctx, ac.logBodyLevel()) {
+		return
+	}
+	attrs := []slog.Attr{slog.Any("body", redactedLogValue(body))}
+	if req != nil {
+		attrs = append([]slog.Attr{slog.String("method", req.Method), slog.String("url", redactURL(req.URL))}, attrs...)
+	}
+	logger.LogAttrs(ctx, ac.logBodyLevel(), msg, attrs...)
+}
+
+// logStreamEvents returns onEvent wrapped to also log each chunk of a
+// streamed response at the body level.
+func (ac *apiClient) logStreamEvents(ctx context.Context, onEvent func(*SSEEvent)) func(*SSEEvent) {
+	logger := ac.clientConfig.Logger
+	if logger == nil || !logger.Enabled(ctx, ac.logBodyLevel()) {
+		return onEvent
+	}
+	return func(event *SSEEvent) {
+		var chunk any = event.Data
+		var data map[string]any
+		if json.Unmarshal([]byte(event.Data), &data) == nil {
+			chunk = data
+		}
+		logger.LogAttrs(ctx, ac.logBodyLevel(), "genai stream chunk",
+			slog.String("event", event.Event),
+			slog.String("id", event.ID),
+			slog.Any("body", redactedLogValue(chunk)))
+		if onEvent != nil {
+			onEvent(event)
+		}
+	}
+}
+
+// redactURL returns u without the values of API key query parameters.
+func redactURL(u *url.URL) string {
+	query := u.Query()
+	if !query.Has("key") {
+		return u.String()
+	}
+	redacted := *u
+	query.Set("key", redactedPlaceholder)
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
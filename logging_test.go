@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	ctx := context.Background()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.RawQuery, "alt=sse") {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"chunk one\"}]}}]}\n\n")
+			fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"chunk two\"}]}}]}\n\n")
+			return
+		}
+		fmt.Fprint(w, `{"candidates":[{"content":{"parts":[{"text":"unary answer"}]}}]}`)
+	}
+
+	newClient := func(level slog.Level, bodyLevel *slog.Level) (*Client, *bytes.Buffer) {
+		var buf bytes.Buffer
+		client := newTestClient(t, &ClientConfig{
+			APIKey:       "secret-api-key",
+			Logger:       slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: level})),
+			LogBodyLevel: bodyLevel,
+		}, handler)
+		return client, &buf
+	}
+	contents := []*Content{NewContentFromParts([]*Part{NewPartFromText("describe"), NewPartFromBytes([]byte("raw image bytes"), "image/png")}, RoleUser)}
+
+	t.Run("Bodies", func(t *testing.T) {
+		client, buf := newClient(slog.LevelDebug, nil)
+		if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", contents, nil); err != nil {
+			t.Fatal(err)
+		}
+		for _, err := range client.Models.GenerateContentStream(ctx, "gemini-2.5-flash", contents, nil) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		got := buf.String()
+		for _, want := range []string{"genai request", "genai response", "status=200", "genai request body", "describe", "unary answer", "genai stream chunk", "chunk one", "chunk two", "base64 chars"} {
+			if !strings.Contains(got, want) {
+				t.Errorf("log does not contain %q:\n%s", want, got)
+			}
+		}
+		for _, forbidden := range []string{"secret-api-key", "cmF3IGltYWdlIGJ5dGVz"} {
+			if strings.Contains(got, forbidden) {
+				t.Errorf("log contains %q:\n%s", forbidden, got)
+			}
+		}
+	})
+
+	t.Run("BodyLevel", func(t *testing.T) {
+		trace := slog.LevelDebug - 4
+		client, buf := newClient(slog.LevelDebug, &trace)
+		if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", contents, nil); err != nil {
+			t.Fatal(err)
+		}
+		got := buf.String()
+		if !strings.Contains(got, "genai response") {
+			t.Errorf("log does not contain the response:\n%s", got)
+		}
+		if strings.Contains(got, "body") {
+			t.Errorf("log contains bodies below their level:\n%s", got)
+		}
+	})
+}
+
+func TestRedactURL(t *testing.T) {
+	u, _ := url.Parse("wss://example.com/ws?key=secret&alt=sse")
+	if got := redactURL(u); strings.Contains(got, "secret") || !strings.Contains(got, "alt=sse") {
+		t.Errorf("redactURL() = %s", got)
+	}
+}
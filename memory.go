@@ -0,0 +1,594 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/plar/genai/embeddings"
+)
+
+// DefaultMemoryRecallLimit is the number of memory records recalled for each
+// message when a Memory is used by a Chat or an agent.
+const DefaultMemoryRecallLimit = 5
+
+// MemoryKind is the kind of a MemoryRecord.
+type MemoryKind string
+
+const (
+	// MemoryKindTurn is a message of a conversation.
+	MemoryKindTurn MemoryKind = "turn"
+	// MemoryKindFact is a fact identified by a key, e.g. a user preference.
+	MemoryKindFact MemoryKind = "fact"
+	// MemoryKindNote is any other piece of text.
+	MemoryKindNote MemoryKind = "note"
+)
+
+// MemoryRecord is a piece of information stored in a Memory.
+type MemoryRecord struct {
+	// Optional. The unique ID of the record. A MemoryStore generates one for
+	// the records it stores if it is empty.
+	ID string `json:"id"`
+	// Optional. The kind of the record. Defaults to MemoryKindNote.
+	Kind MemoryKind `json:"kind,omitempty"`
+	// Optional. For turns, the role of the author, e.g. "user" or "model".
+	Role string `json:"role,omitempty"`
+	// Optional. For facts, the key of the fact, e.g. "favorite_color". A fact
+	// replaces the earlier fact with the same key.
+	Key string `json:"key,omitempty"`
+	// Required. The text of the record.
+	Text string `json:"text"`
+	// Optional. The embedding of Text, set by VectorMemory.
+	Embedding []float32 `json:"embedding,omitempty"`
+	// Optional. When the record was created. A MemoryStore sets it for the
+	// records it stores if it is zero.
+	CreateTime time.Time `json:"createTime"`
+}
+
+// NewTurnRecord returns a MemoryRecord for a message of a conversation.
+func NewTurnRecord(role Role, text string) *MemoryRecord {
+	return &MemoryRecord{Kind: MemoryKindTurn, Role: string(role), Text: text}
+}
+
+// NewFactRecord returns a MemoryRecord for a fact identified by key.
+func NewFactRecord(key, text string) *MemoryRecord {
+	return &MemoryRecord{Kind: MemoryKindFact, Key: key, Text: text}
+}
+
+// Memory stores information that a Chat or an agent recalls in later turns
+// or sessions.
+//
+// Implementations must be safe for concurrent use.
+type Memory interface {
+	// Add stores records in the memory. A memory may ignore records of kinds
+	// it does not keep.
+	Add(ctx context.Context, records ...*MemoryRecord) error
+	// Search returns up to limit records relevant to query, most relevant
+	// first.
+	Search(ctx context.Context, query string, limit int) ([]*MemoryRecord, error)
+}
+
+// RecallMemory searches m for query and formats the results as a block of
+// text that can be added to a system instruction. It returns an empty string
+// if nothing was found.
+func RecallMemory(ctx context.Context, m Memory, query string, limit int) (string, error) {
+	records, err := m.Search(ctx, query, limit)
+	if err != nil {
+		return "", fmt.Errorf("memory search failed: %w", err)
+	}
+	if len(records) == 0 {
+		return "", nil
+	}
+	var sb strings.Builder
+	sb.WriteString("Relevant memory from earlier conversations:")
+	for _, r := range records {
+		sb.WriteString("\n- ")
+		switch {
+		case r.Kind == MemoryKindTurn && r.Role != "":
+			sb.WriteString(r.Role + ": ")
+		case r.Key != "":
+			sb.WriteString(r.Key + ": ")
+		}
+		sb.WriteString(r.Text)
+	}
+	return sb.String(), nil
+}
+
+// MemoryStore persists the records of a Memory.
+//
+// Implementations must be safe for concurrent use.
+type MemoryStore interface {
+	// Put adds copies of records, replacing the records with the same IDs.
+	// Copies without an ID or a creation time get one.
+	Put(ctx context.Context, records ...*MemoryRecord) error
+	// List returns all records, oldest first.
+	List(ctx context.Context) ([]*MemoryRecord, error)
+	// Delete removes the records with the given IDs.
+	Delete(ctx context.Context, ids ...string) error
+}
+
+type inMemoryStore struct {
+	mu      sync.Mutex
+	records []*MemoryRecord
+}
+
+// NewInMemoryStore returns a MemoryStore that keeps the records in memory.
+func NewInMemoryStore() MemoryStore {
+	return &inMemoryStore{}
+}
+
+func (s *inMemoryStore) Put(ctx context.Context, records ...*MemoryRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = putRecords(s.records, records)
+	return nil
+}
+
+func (s *inMemoryStore) List(ctx context.Context) ([]*MemoryRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.records), nil
+}
+
+func (s *inMemoryStore) Delete(ctx context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = deleteRecords(s.records, ids)
+	return nil
+}
+
+type fileMemoryStore struct {
+	mu      sync.Mutex
+	path    string
+	records []*MemoryRecord
+}
+
+// NewFileMemoryStore returns a MemoryStore that keeps the records in a JSON
+// file at path, so that they survive restarts. Existing records are loaded
+// from the file; the file is created on the first write. The file is
+// rewritten on every change, so the store is meant for small memories.
+func NewFileMemoryStore(path string) (MemoryStore, error) {
+	s := &fileMemoryStore{path: path}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return s, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read memory file: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.records); err != nil {
+		return nil, fmt.Errorf("failed to parse memory file %s: %w", path, err)
+	}
+	return s, nil
+}
+
+func (s *fileMemoryStore) Put(ctx context.Context, records ...*MemoryRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(putRecords(slices.Clone(s.records), records))
+}
+
+func (s *fileMemoryStore) List(ctx context.Context) ([]*MemoryRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.records), nil
+}
+
+func (s *fileMemoryStore) Delete(ctx context.Context, ids ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(deleteRecords(slices.Clone(s.records), ids))
+}
+
+// save writes records to a temporary file and renames it over the memory
+// file, so that a failed write does not corrupt it.
+func (s *fileMemoryStore) save(records []*MemoryRecord) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal memory records: %w", err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write memory file: %w", err)
+	}
+	s.records = records
+	return nil
+}
+
+func putRecords(existing, records []*MemoryRecord) []*MemoryRecord {
+	for _, r := range records {
+		r := *r
+		if r.ID == "" {
+			r.ID = newRecordID()
+		}
+		if r.CreateTime.IsZero() {
+			r.CreateTime = time.Now()
+		}
+		if i := slices.IndexFunc(existing, func(e *MemoryRecord) bool { return e.ID == r.ID }); i >= 0 {
+			existing[i] = &r
+		} else {
+			existing = append(existing, &r)
+		}
+	}
+	return existing
+}
+
+func deleteRecords(existing []*MemoryRecord, ids []string) []*MemoryRecord {
+	return slices.DeleteFunc(existing, func(r *MemoryRecord) bool { return slices.Contains(ids, r.ID) })
+}
+
+func newRecordID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// TurnBuffer is a short-term Memory that keeps the most recent conversation
+// turns. Search ignores the query and returns the latest turns in
+// chronological order.
+type TurnBuffer struct {
+	store MemoryStore
+	size  int
+}
+
+// NewTurnBuffer returns a TurnBuffer that keeps the last size turns in store.
+// If store is nil, the turns are kept in memory.
+func NewTurnBuffer(size int, store MemoryStore) *TurnBuffer {
+	if store == nil {
+		store = NewInMemoryStore()
+	}
+	return &TurnBuffer{store: store, size: size}
+}
+
+// Add stores the turn records and drops the oldest turns beyond the buffer
+// size. Records of other kinds are ignored.
+func (b *TurnBuffer) Add(ctx context.Context, records ...*MemoryRecord) error {
+	var turns []*MemoryRecord
+	for _, r := range records {
+		if r.Kind == MemoryKindTurn {
+			turns = append(turns, r)
+		}
+	}
+	if len(turns) == 0 {
+		return nil
+	}
+	if err := b.store.Put(ctx, turns...); err != nil {
+		return err
+	}
+	all, err := b.store.List(ctx)
+	if err != nil || len(all) <= b.size {
+		return err
+	}
+	var drop []string
+	for _, r := range all[:len(all)-b.size] {
+		drop = append(drop, r.ID)
+	}
+	return b.store.Delete(ctx, drop...)
+}
+
+// Search returns the last limit turns, oldest first.
+func (b *TurnBuffer) Search(ctx context.Context, query string, limit int) ([]*MemoryRecord, error) {
+	all, err := b.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(all) > limit {
+		all = all[len(all)-limit:]
+	}
+	return all, nil
+}
+
+// FactMemory is a long-term Memory of facts identified by keys, such as user
+// preferences. Adding a fact replaces the earlier fact with the same key.
+// Search ranks the facts by the words they share with the query.
+type FactMemory struct {
+	mu    sync.Mutex
+	store MemoryStore
+}
+
+// NewFactMemory returns a FactMemory that keeps the facts in store. If store
+// is nil, the facts are kept in memory.
+func NewFactMemory(store MemoryStore) *FactMemory {
+	if store == nil {
+		store = NewInMemoryStore()
+	}
+	return &FactMemory{store: store}
+}
+
+// Add stores the fact records. Records of other kinds and facts without a key
+// are ignored.
+func (m *FactMemory) Add(ctx context.Context, records ...*MemoryRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	all, err := m.store.List(ctx)
+	if err != nil {
+		return err
+	}
+	var facts []*MemoryRecord
+	for _, r := range records {
+		if r.Kind != MemoryKindFact || r.Key == "" {
+			continue
+		}
+		r := *r
+		// Reuse the ID of the fact with the same key so that it is replaced.
+		for _, e := range all {
+			if e.Key == r.Key {
+				r.ID = e.ID
+			}
+		}
+		facts = append(facts, &r)
+	}
+	if len(facts) == 0 {
+		return nil
+	}
+	return m.store.Put(ctx, facts...)
+}
+
+// Fact returns the text of the fact with the given key.
+func (m *FactMemory) Fact(ctx context.Context, key string) (string, bool, error) {
+	all, err := m.store.List(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	for _, r := range all {
+		if r.Key == key {
+			return r.Text, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// Search returns up to limit facts that share words with the query, the best
+// matches first. If the query is empty, it returns the most recent facts.
+func (m *FactMemory) Search(ctx context.Context, query string, limit int) ([]*MemoryRecord, error) {
+	all, err := m.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	terms := memoryTerms(query)
+	if len(terms) == 0 {
+		slices.Reverse(all)
+		return limitRecords(all, limit), nil
+	}
+	type scored struct {
+		r     *MemoryRecord
+		score int
+	}
+	var matches []scored
+	for _, r := range all {
+		words := memoryTerms(r.Key + " " + r.Text)
+		score := 0
+		for t := range terms {
+			if words[t] {
+				score++
+			}
+		}
+		if score > 0 {
+			matches = append(matches, scored{r, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	results := make([]*MemoryRecord, len(matches))
+	for i, m := range matches {
+		results[i] = m.r
+	}
+	return limitRecords(results, limit), nil
+}
+
+// memoryTerms returns the lower-cased words of s, splitting keys such as
+// "favorite_color" into their words.
+func memoryTerms(s string) map[string]bool {
+	terms := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		terms[w] = true
+	}
+	return terms
+}
+
+func limitRecords(records []*MemoryRecord, limit int) []*MemoryRecord {
+	if limit > 0 && len(records) > limit {
+		return records[:limit]
+	}
+	return records
+}
+
+// VectorMemoryConfig configures a VectorMemory.
+type VectorMemoryConfig struct {
+	// Required. The embedding model, e.g. "gemini-embedding-001".
+	Model string
+	// Optional. Where the records and their embeddings are kept. Defaults to an
+	// in-memory store.
+	Store MemoryStore
+	// Optional. The minimum cosine similarity of a search result. Defaults to 0.
+	MinScore float64
+	// Optional. The configuration of the embedding requests. TaskType is set to
+	// RETRIEVAL_DOCUMENT for added records and RETRIEVAL_QUERY for queries
+	// unless it is set here.
+	EmbedConfig *EmbedContentConfig
+}
+
+// VectorMemory is a long-term Memory that embeds records with
+// [Models.EmbedContent] and returns the records most similar to the query.
+type VectorMemory struct {
+	models Models
+	config VectorMemoryConfig
+}
+
+// NewVectorMemory returns a VectorMemory that embeds records with
+// client.Models.
+func NewVectorMemory(client *Client, config *VectorMemoryConfig) (*VectorMemory, error) {
+	if client == nil {
+		return nil, errors.New("NewVectorMemory: client is required")
+	}
+	if config == nil || config.Model == "" {
+		return nil, errors.New("NewVectorMemory: model is required")
+	}
+	m := &VectorMemory{models: *client.Models, config: *config}
+	if m.config.Store == nil {
+		m.config.Store = NewInMemoryStore()
+	}
+	return m, nil
+}
+
+// Add embeds the records that have no embedding yet and stores them.
+func (m *VectorMemory) Add(ctx context.Context, records ...*MemoryRecord) error {
+	var stored []*MemoryRecord
+	for _, r := range records {
+		if r.Text == "" {
+			continue
+		}
+		r := *r
+		if len(r.Embedding) == 0 {
+			embedding, err := m.embed(ctx, r.Text, "RETRIEVAL_DOCUMENT")
+			if err != nil {
+				return err
+			}
+			r.Embedding = embedding
+		}
+		stored = append(stored, &r)
+	}
+	if len(stored) == 0 {
+		return nil
+	}
+	return m.config.Store.Put(ctx, stored...)
+}
+
+// Search embeds the query and returns up to limit records by decreasing
+// cosine similarity.
+func (m *VectorMemory) Search(ctx context.Context, query string, limit int) ([]*MemoryRecord, error) {
+	if query == "" {
+		return nil, nil
+	}
+	all, err := m.config.Store.List(ctx)
+	if err != nil || len(all) == 0 {
+		return nil, err
+	}
+	q, err := m.embed(ctx, query, "RETRIEVAL_QUERY")
+	if err != nil {
+		return nil, err
+	}
+	type scored struct {
+		r     *MemoryRecord
+		score float64
+	}
+	var matches []scored
+	for _, r := range all {
+		if score := embeddings.CosineSimilarity(q, r.Embedding); score >= m.config.MinScore && len(r.Embedding) > 0 {
+			matches = append(matches, scored{r, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	results := make([]*MemoryRecord, len(matches))
+	for i, m := range matches {
+		results[i] = m.r
+	}
+	return limitRecords(results, limit), nil
+}
+
+func (m *VectorMemory) embed(ctx context.Context, text, taskType string) ([]float32, error) {
+	config := &EmbedContentConfig{}
+	if m.config.EmbedConfig != nil {
+		*config = *m.config.EmbedConfig
+	}
+	if config.TaskType == "" {
+		config.TaskType = taskType
+	}
+	resp, err := m.models.EmbedContent(ctx, m.config.Model, Text(text), config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed memory: %w", err)
+	}
+	if len(resp.Embeddings) == 0 || resp.Embeddings[0] == nil {
+		return nil, errors.New("failed to embed memory: the response has no embedding")
+	}
+	return resp.Embeddings[0].Values, nil
+}
+
+type combinedMemory []Memory
+
+// CombineMemories returns a Memory that adds records to all memories and
+// returns the search results of each memory in turn, e.g. recent turns from a
+// TurnBuffer followed by facts from a FactMemory. Each memory returns up to
+// limit results.
+func CombineMemories(memories ...Memory) Memory {
+	return combinedMemory(memories)
+}
+
+func (c combinedMemory) Add(ctx context.Context, records ...*MemoryRecord) error {
+	for _, m := range c {
+		if err := m.Add(ctx, records...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c combinedMemory) Search(ctx context.Context, query string, limit int) ([]*MemoryRecord, error) {
+	var results []*MemoryRecord
+	// The same turn may be kept by several memories under different IDs.
+	seen := map[[4]string]bool{}
+	for _, m := range c {
+		records, err := m.Search(ctx, query, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range records {
+			key := [4]string{string(r.Kind), r.Role, r.Key, r.Text}
+			if !seen[key] {
+				seen[key] = true
+				results = append(results, r)
+			}
+		}
+	}
+	return results, nil
+}
+
+// contentText returns the concatenated text parts of content, skipping
+// thoughts.
+func contentText(content *Content) string {
+	if content == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, p := range content.Parts {
+		if p != nil && !p.Thought {
+			sb.WriteString(p.Text)
+		}
+	}
+	return sb.String()
+}
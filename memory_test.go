@@ -0,0 +1,210 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func recordTexts(records []*MemoryRecord) []string {
+	texts := make([]string, len(records))
+	for i, r := range records {
+		texts[i] = r.Text
+	}
+	return texts
+}
+
+func TestFileMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "memory.json")
+	store, err := NewFileMemoryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, NewFactRecord("city", "Lives in Paris"), NewTurnRecord(RoleUser, "hi")); err != nil {
+		t.Fatal(err)
+	}
+	records, err := store.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, records[1].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new store for the same file sees the records written before.
+	reopened, err := NewFileMemoryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err = reopened.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Text != "Lives in Paris" || records[0].ID == "" || records[0].CreateTime.IsZero() {
+		t.Errorf("reopened store records = %+v, want the fact with an ID and a creation time", records)
+	}
+}
+
+func TestTurnBuffer(t *testing.T) {
+	ctx := context.Background()
+	b := NewTurnBuffer(3, nil)
+	for i := range 5 {
+		if err := b.Add(ctx, NewTurnRecord(RoleUser, fmt.Sprint(i)), NewFactRecord("ignored", "fact")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := b.Search(ctx, "anything", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"3", "4"}, recordTexts(got)); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+	got, _ = b.Search(ctx, "", 0)
+	if diff := cmp.Diff([]string{"2", "3", "4"}, recordTexts(got)); diff != "" {
+		t.Errorf("Search() with no limit mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFactMemory(t *testing.T) {
+	ctx := context.Background()
+	m := NewFactMemory(nil)
+	if err := m.Add(ctx,
+		NewFactRecord("favorite_color", "blue"),
+		NewFactRecord("home_city", "Paris"),
+		NewTurnRecord(RoleUser, "ignored"),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(ctx, NewFactRecord("favorite_color", "green")); err != nil {
+		t.Fatal(err)
+	}
+	if text, ok, err := m.Fact(ctx, "favorite_color"); err != nil || !ok || text != "green" {
+		t.Errorf("Fact() = %q, %v, %v, want the replaced fact", text, ok, err)
+	}
+	got, err := m.Search(ctx, "What is my favorite color?", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"green"}, recordTexts(got)); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+	got, _ = m.Search(ctx, "", 5)
+	if len(got) != 2 {
+		t.Errorf("Search() with an empty query returned %d facts, want 2", len(got))
+	}
+}
+
+// embeddingServer returns word-count embeddings over a small vocabulary.
+func embeddingServer(t *testing.T) *Client {
+	t.Helper()
+	vocabulary := []string{"paris", "weather", "cat", "dog"}
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Requests []struct {
+				Content  *Content `json:"content"`
+				TaskType string   `json:"taskType"`
+			} `json:"requests"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+		var embeddings []*ContentEmbedding
+		for _, r := range req.Requests {
+			text := strings.ToLower(contentText(r.Content))
+			values := make([]float32, len(vocabulary))
+			for i, word := range vocabulary {
+				values[i] = float32(strings.Count(text, word))
+			}
+			embeddings = append(embeddings, &ContentEmbedding{Values: values})
+		}
+		json.NewEncoder(w).Encode(&EmbedContentResponse{Embeddings: embeddings})
+	})
+	return client
+}
+
+func TestVectorMemory(t *testing.T) {
+	ctx := context.Background()
+	client := embeddingServer(t)
+	if _, err := NewVectorMemory(client, &VectorMemoryConfig{}); err == nil {
+		t.Error("NewVectorMemory() without a model succeeded, want error")
+	}
+	m, err := NewVectorMemory(client, &VectorMemoryConfig{Model: "gemini-embedding-001", MinScore: 0.1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add(ctx,
+		&MemoryRecord{Text: "My cat sleeps all day."},
+		&MemoryRecord{Text: "The weather in Paris is mild."},
+		&MemoryRecord{Text: "Paris has many cat cafes."},
+	); err != nil {
+		t.Fatal(err)
+	}
+	got, err := m.Search(ctx, "What is the weather like in Paris?", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"The weather in Paris is mild.", "Paris has many cat cafes."}, recordTexts(got)); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestChatMemory(t *testing.T) {
+	ctx := context.Background()
+	var instructions []string
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			SystemInstruction *Content `json:"systemInstruction"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+		instructions = append(instructions, contentText(req.SystemInstruction))
+		fmt.Fprint(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "Nice to meet you, Ana."}]}, "finishReason": "STOP"}]}`)
+	})
+
+	// The memory outlives the chat, like a memory shared by sessions.
+	memory := NewTurnBuffer(10, nil)
+	config := &GenerateContentConfig{SystemInstruction: NewContentFromText("Be brief.", RoleUser)}
+	for range 2 {
+		chat, err := client.Chats.Create(ctx, "gemini-2.5-flash", config, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		chat.SetMemory(memory)
+		if _, err := chat.SendMessage(ctx, Part{Text: "My name is Ana."}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if instructions[0] != "Be brief." {
+		t.Errorf("first system instruction = %q, want no recalled memory", instructions[0])
+	}
+	want := "Be brief.Relevant memory from earlier conversations:\n- user: My name is Ana.\n- model: Nice to meet you, Ana."
+	if instructions[1] != want {
+		t.Errorf("second system instruction = %q, want %q", instructions[1], want)
+	}
+	if len(config.SystemInstruction.Parts) != 1 {
+		t.Errorf("the chat config was modified: %+v", config.SystemInstruction)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const (
+	// geminiEmbedBatchSize is the maximum number of contents of a
+	// batchEmbedContents request of the Gemini API.
+	geminiEmbedBatchSize = 100
+	// vertexEmbedBatchSize is the maximum number of instances of a predict
+	// request of the embedding models of Vertex AI.
+	vertexEmbedBatchSize = 250
+	// defaultEmbedConcurrency is the default number of requests that
+	// EmbedContentBatched sends at a time.
+	defaultEmbedConcurrency = 4
+)
+
+// EmbedContentBatchedConfig configures Models.EmbedContentBatched.
+type EmbedContentBatchedConfig struct {
+	// Optional. The config of every EmbedContent request.
+	EmbedContentConfig
+	// Optional. The maximum number of contents that EmbedContentBatched sends
+	// in a request. Defaults to the limit of the API for the model.
+	BatchSize int
+	// Optional. The maximum number of requests that EmbedContentBatched sends
+	// at a time. Defaults to 4.
+	MaxConcurrency int
+}
+
+// EmbedContentBatched embeds contents as EmbedContent does, splitting them
+// into requests of up to config.BatchSize contents, which defaults to the
+// limit of the API for model: 100 on the Gemini API, 250 on Vertex AI, and 1
+// for the models of Vertex AI that embed one content at a time. Up to
+// config.MaxConcurrency requests are sent at a time.
+//
+// The embeddings of the response are in the order of contents, and its
+// metadata sums the billable characters of the requests. If a request fails,
+// the requests that were not sent are cancelled and its error is returned.
+func (m Models) EmbedContentBatched(ctx context.Context, model string, contents []*Content, config *EmbedContentBatchedConfig) (*EmbedContentResponse, error) {
+	if len(contents) == 0 {
+		return nil, fmt.Errorf("contents are required")
+	}
+	batchSize := m.embedBatchSize(model)
+	concurrency := defaultEmbedConcurrency
+	var embedConfig *EmbedContentConfig
+	if config != nil {
+		embedConfig = &config.EmbedContentConfig
+		if config.BatchSize > 0 {
+			batchSize = config.BatchSize
+		}
+		if config.MaxConcurrency > 0 {
+			concurrency = config.MaxConcurrency
+		}
+	}
+	n := (len(contents) + batchSize - 1) / batchSize
+	responses := make([]*EmbedContentResponse, n)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var mu sync.Mutex
+	var firstErr error
+	forEachConcurrently(ctx, n, concurrency, func(i int) {
+		start, end := i*batchSize, min((i+1)*batchSize, len(contents))
+		resp, err := m.EmbedContent(ctx, model, contents[start:end], embedConfig)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to embed contents %d to %d: %w", start, end-1, err)
+			cancel()
+		}
+		responses[i] = resp
+	})
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	// ctx may be done before every request was sent.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result := &EmbedContentResponse{SDKHTTPResponse: responses[0].SDKHTTPResponse}
+	for _, resp := range responses {
+		result.Embeddings = append(result.Embeddings, resp.Embeddings...)
+		if resp.Metadata != nil {
+			if result.Metadata == nil {
+				result.Metadata = &EmbedContentMetadata{}
+			}
+			result.Metadata.BillableCharacterCount += resp.Metadata.BillableCharacterCount
+		}
+	}
+	if len(result.Embeddings) != len(contents) {
+		return nil, fmt.Errorf("got %d embeddings for %d contents", len(result.Embeddings), len(contents))
+	}
+	return result, nil
+}
+
+// embedBatchSize returns the maximum number of contents of an EmbedContent
+// request for model.
+func (m Models) embedBatchSize(model string) int {
+	if m.apiClient.clientConfig.Backend != BackendVertexAI {
+		return geminiEmbedBatchSize
+	}
+	// gemini-embedding-001 takes a single instance per predict request.
+	if tIsVertexEmbedContentModel(m.apiClient.resolveModel(model)) || modelID(model) == "gemini-embedding-001" {
+		return 1
+	}
+	return vertexEmbedBatchSize
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// embedTexts returns a content for each of n texts "0", "1", ...
+func embedTexts(n int) []*Content {
+	contents := make([]*Content, n)
+	for i := range contents {
+		contents[i] = NewContentFromText(strconv.Itoa(i), RoleUser)
+	}
+	return contents
+}
+
+func TestEmbedContentBatched(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var sizes []int
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []struct {
+				Content *Content `json:"content"`
+			} `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		sizes = append(sizes, len(body.Requests))
+		mu.Unlock()
+		var embeddings []any
+		for _, request := range body.Requests {
+			if request.Content.Parts[0].Text == "bad" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": {"code": 400, "status": "INVALID_ARGUMENT", "message": "bad content"}}`))
+				return
+			}
+			v, _ := strconv.Atoi(request.Content.Parts[0].Text)
+			embeddings = append(embeddings, map[string]any{"values": []float32{float32(v)}})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings})
+	})
+
+	resp, err := client.Models.EmbedContentBatched(ctx, "text-embedding-004", embedTexts(5), &EmbedContentBatchedConfig{BatchSize: 2, MaxConcurrency: 2})
+	if err != nil {
+		t.Fatalf("EmbedContentBatched() failed: %v", err)
+	}
+	var got []float32
+	for _, embedding := range resp.Embeddings {
+		got = append(got, embedding.Values...)
+	}
+	if diff := cmp.Diff([]float32{0, 1, 2, 3, 4}, got); diff != "" {
+		t.Errorf("embeddings mismatch (-want +got):\n%s", diff)
+	}
+	if len(sizes) != 3 {
+		t.Errorf("sent requests of %v contents, want 3 requests", sizes)
+	}
+
+	sizes = nil
+	if _, err := client.Models.EmbedContentBatched(ctx, "text-embedding-004", embedTexts(250), nil); err != nil {
+		t.Fatalf("EmbedContentBatched() failed: %v", err)
+	}
+	if len(sizes) != 3 || max(sizes[0], sizes[1], sizes[2]) != 100 {
+		t.Errorf("sent requests of %v contents, want requests of up to 100", sizes)
+	}
+
+	contents := embedTexts(20)
+	contents[13] = NewContentFromText("bad", RoleUser)
+	_, err = client.Models.EmbedContentBatched(ctx, "text-embedding-004", contents, &EmbedContentBatchedConfig{BatchSize: 5})
+	if err == nil || !strings.Contains(err.Error(), "contents 10 to 14") {
+		t.Errorf("EmbedContentBatched() = %v, want the error of contents 10 to 14", err)
+	}
+}
+
+func TestEmbedContentBatchedVertex(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, &ClientConfig{Backend: BackendVertexAI}, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Instances []any `json:"instances"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var predictions []any
+		for range body.Instances {
+			predictions = append(predictions, map[string]any{"embeddings": map[string]any{"values": []float32{1}}})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"predictions": predictions, "metadata": map[string]any{"billableCharacterCount": 10 * len(body.Instances)}})
+	})
+	resp, err := client.Models.EmbedContentBatched(ctx, "text-embedding-005", embedTexts(300), nil)
+	if err != nil {
+		t.Fatalf("EmbedContentBatched() failed: %v", err)
+	}
+	if len(resp.Embeddings) != 300 || resp.Metadata.BillableCharacterCount != 3000 {
+		t.Errorf("EmbedContentBatched() = %d embeddings with metadata %+v, want 300 embeddings of 3000 characters", len(resp.Embeddings), resp.Metadata)
+	}
+}
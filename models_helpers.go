@@ -14,6 +14,12 @@
 
 package genai
 
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
 // Text returns a slice of Content with a single Part with the given text.
 func Text(text string) []*Content {
 	return []*Content{{
@@ -39,3 +45,90 @@ func (c *Content) setDefaults() {
 		c.Role = RoleUser
 	}
 }
+
+// inlineParts returns the non-thought parts with inline data from the first
+// candidate of the response, optionally filtered by a MIME type prefix.
+func (r *GenerateContentResponse) inlineParts(accessor, mimePrefix string) ([]*Part, error) {
+	if r == nil || len(r.Candidates) == 0 {
+		return nil, fmt.Errorf("%s: response has no candidates", accessor)
+	}
+	if r.Candidates[0].Content == nil || len(r.Candidates[0].Content.Parts) == 0 {
+		return nil, fmt.Errorf("%s: first candidate has no content parts (finish reason: %q)", accessor, r.Candidates[0].FinishReason)
+	}
+
+	if len(r.Candidates) > 1 {
+		log.Printf("Warning: there are multiple candidates in the response, returning %s from the first one.", accessor)
+	}
+
+	var parts []*Part
+	for _, part := range r.Candidates[0].Content.Parts {
+		if part == nil || part.Thought || part.InlineData == nil {
+			continue
+		}
+		if mimePrefix != "" && !strings.HasPrefix(part.InlineData.MIMEType, mimePrefix) {
+			continue
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// Image returns the i-th inline image (zero-based) in the first candidate of
+// the GenerateContentResponse.
+//
+// An error is returned if the response has no candidates, or if it contains
+// fewer than i+1 images.
+func (r *GenerateContentResponse) Image(i int) (*Image, error) {
+	parts, err := r.inlineParts("image", "image/")
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("image: response contains no inline image parts")
+	}
+	if i < 0 || i >= len(parts) {
+		return nil, fmt.Errorf("image: index %d out of range, response contains %d inline image part(s)", i, len(parts))
+	}
+	blob := parts[i].InlineData
+	return &Image{ImageBytes: blob.Data, MIMEType: blob.MIMEType}, nil
+}
+
+// AudioBytes returns the concatenation of all inline audio parts in the first
+// candidate of the GenerateContentResponse.
+//
+// The model may split audio output across several parts, so the bytes of all
+// audio parts are joined in order. An error is returned if the response has no
+// audio parts.
+func (r *GenerateContentResponse) AudioBytes() ([]byte, error) {
+	parts, err := r.inlineParts("audio", "audio/")
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("audio: response contains no inline audio parts")
+	}
+	var audio []byte
+	for _, part := range parts {
+		audio = append(audio, part.InlineData.Data...)
+	}
+	return audio, nil
+}
+
+// InlineFiles returns all inline data blobs (images, audio, documents, etc.) in
+// the first candidate of the GenerateContentResponse.
+//
+// An error is returned if the response has no inline data parts.
+func (r *GenerateContentResponse) InlineFiles() ([]*Blob, error) {
+	parts, err := r.inlineParts("inline files", "")
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("inline files: response contains no inline data parts")
+	}
+	blobs := make([]*Blob, len(parts))
+	for i, part := range parts {
+		blobs[i] = part.InlineData
+	}
+	return blobs, nil
+}
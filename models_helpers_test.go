@@ -15,6 +15,7 @@
 package genai
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -50,3 +51,71 @@ func TestContentHelpers(t *testing.T) {
 		}
 	})
 }
+
+func TestModalityAccessors(t *testing.T) {
+	pngPart := &Part{InlineData: &Blob{Data: []byte("png"), MIMEType: "image/png"}}
+	jpegPart := &Part{InlineData: &Blob{Data: []byte("jpeg"), MIMEType: "image/jpeg"}}
+	audioPart1 := &Part{InlineData: &Blob{Data: []byte("pcm1"), MIMEType: "audio/pcm"}}
+	audioPart2 := &Part{InlineData: &Blob{Data: []byte("pcm2"), MIMEType: "audio/pcm"}}
+	pdfPart := &Part{InlineData: &Blob{Data: []byte("pdf"), MIMEType: "application/pdf"}}
+	thoughtPart := &Part{Thought: true, InlineData: &Blob{Data: []byte("draft"), MIMEType: "image/png"}}
+	newResponse := func(parts ...*Part) *GenerateContentResponse {
+		return &GenerateContentResponse{Candidates: []*Candidate{{Content: &Content{Parts: parts}}}}
+	}
+
+	t.Run("Image", func(t *testing.T) {
+		resp := newResponse(&Part{Text: "Here you go"}, thoughtPart, pngPart, audioPart1, jpegPart)
+		got, err := resp.Image(1)
+		if err != nil {
+			t.Fatalf("Image() failed: %v", err)
+		}
+		want := &Image{ImageBytes: []byte("jpeg"), MIMEType: "image/jpeg"}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Image() mismatch (-want +got):\n%s", diff)
+		}
+		if _, err := resp.Image(2); err == nil || !strings.Contains(err.Error(), "out of range") {
+			t.Errorf("Image(2) error = %v, want out of range error", err)
+		}
+		if _, err := newResponse(&Part{Text: "no images"}).Image(0); err == nil || !strings.Contains(err.Error(), "no inline image parts") {
+			t.Errorf("Image(0) error = %v, want no inline image parts error", err)
+		}
+	})
+
+	t.Run("AudioBytes", func(t *testing.T) {
+		got, err := newResponse(audioPart1, pngPart, audioPart2).AudioBytes()
+		if err != nil {
+			t.Fatalf("AudioBytes() failed: %v", err)
+		}
+		if diff := cmp.Diff([]byte("pcm1pcm2"), got); diff != "" {
+			t.Errorf("AudioBytes() mismatch (-want +got):\n%s", diff)
+		}
+		if _, err := newResponse(pngPart).AudioBytes(); err == nil || !strings.Contains(err.Error(), "no inline audio parts") {
+			t.Errorf("AudioBytes() error = %v, want no inline audio parts error", err)
+		}
+	})
+
+	t.Run("InlineFiles", func(t *testing.T) {
+		got, err := newResponse(&Part{Text: "files"}, thoughtPart, pngPart, pdfPart).InlineFiles()
+		if err != nil {
+			t.Fatalf("InlineFiles() failed: %v", err)
+		}
+		want := []*Blob{pngPart.InlineData, pdfPart.InlineData}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("InlineFiles() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("NoCandidates", func(t *testing.T) {
+		resp := &GenerateContentResponse{}
+		if _, err := resp.Image(0); err == nil || !strings.Contains(err.Error(), "no candidates") {
+			t.Errorf("Image(0) error = %v, want no candidates error", err)
+		}
+		if _, err := resp.AudioBytes(); err == nil {
+			t.Errorf("AudioBytes() expected error, got nil")
+		}
+		empty := &GenerateContentResponse{Candidates: []*Candidate{{FinishReason: FinishReasonSafety}}}
+		if _, err := empty.InlineFiles(); err == nil || !strings.Contains(err.Error(), string(FinishReasonSafety)) {
+			t.Errorf("InlineFiles() error = %v, want error mentioning finish reason", err)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// The launch stage of a Vertex AI publisher model.
+type LaunchStage string
+
+const (
+	// The model launch stage is unspecified.
+	LaunchStageUnspecified LaunchStage = "LAUNCH_STAGE_UNSPECIFIED"
+	// Used to indicate the model is in an experimental launch stage, available
+	// to a small set of customers.
+	LaunchStageExperimental LaunchStage = "EXPERIMENTAL"
+	// Used to indicate the model is in a private preview launch stage, available
+	// to a small set of customers.
+	LaunchStagePrivatePreview LaunchStage = "PRIVATE_PREVIEW"
+	// Used to indicate the model is in a public preview launch stage, available
+	// to all customers, although not supported for production workloads.
+	LaunchStagePublicPreview LaunchStage = "PUBLIC_PREVIEW"
+	// Used to indicate the model is generally available to all customers and
+	// supported for production workloads.
+	LaunchStageGA LaunchStage = "GA"
+)
+
+// PublisherModel is a base model of a publisher on Vertex AI, such as a model
+// of Google or of the Model Garden.
+type PublisherModel struct {
+	// The model, whose SupportedActions are the actions of the Model Garden
+	// that it supports, e.g. "deploy" or "openNotebook".
+	Model
+	// Optional. The launch stage of the model.
+	LaunchStage LaunchStage `json:"launchStage,omitempty"`
+	// Optional. Whether the model is open source, e.g.
+	// "GOOGLE_OWNED_OSS_WITH_GOOGLE_CHECKPOINT" or "PROPRIETARY".
+	OpenSourceCategory string `json:"openSourceCategory,omitempty"`
+}
+
+// ListPublisherModelsConfig configures Models.ListPublisherModels.
+type ListPublisherModelsConfig struct {
+	// Optional. Used to override HTTP request options.
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+	// Optional. The maximum number of models per page.
+	PageSize int32 `json:"pageSize,omitempty"`
+	// Optional. The token of the page to retrieve.
+	PageToken string `json:"pageToken,omitempty"`
+	// Optional. A filter of the models, e.g. "launch_stage=GA".
+	Filter string `json:"filter,omitempty"`
+	// Optional. The publisher of the models to list. If empty, defaults to
+	// "google". Use "*" to list the Model Garden models of all publishers.
+	Publisher string `json:"publisher,omitempty"`
+	// Optional. Whether to list all versions of the models instead of only
+	// their default version.
+	ListAllVersions bool `json:"listAllVersions,omitempty"`
+}
+
+type listPublisherModelsResponse struct {
+	PublisherModels []*PublisherModel `json:"publisherModels,omitempty"`
+	NextPageToken   string            `json:"nextPageToken,omitempty"`
+	SDKHTTPResponse *HTTPResponse     `json:"sdkHttpResponse,omitempty"`
+}
+
+func (m Models) listPublisherModels(ctx context.Context, config *ListPublisherModelsConfig) (*listPublisherModelsResponse, error) {
+	if m.apiClient.clientConfig.Backend != BackendVertexAI {
+		return nil, fmt.Errorf("method ListPublisherModels is only supported in the Vertex AI client. You can choose to use Vertex AI by setting ClientConfig.Backend to BackendVertexAI.")
+	}
+	publisher := config.Publisher
+	if publisher == "" {
+		publisher = "google"
+	}
+	query := map[string]any{}
+	if config.PageSize > 0 {
+		query["pageSize"] = int(config.PageSize)
+	}
+	if config.PageToken != "" {
+		query["pageToken"] = config.PageToken
+	}
+	if config.Filter != "" {
+		query["filter"] = config.Filter
+	}
+	if config.ListAllVersions {
+		query["listAllVersions"] = true
+	}
+	path := fmt.Sprintf("publishers/%s/models", publisher)
+	if len(query) > 0 {
+		q, err := createURLQuery(query)
+		if err != nil {
+			return nil, err
+		}
+		path += "?" + q
+	}
+	httpOptions := config.HTTPOptions
+	if httpOptions == nil {
+		httpOptions = &HTTPOptions{}
+	}
+	responseMap, err := sendRequest(ctx, m.apiClient, path, http.MethodGet, nil, httpOptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, model := range objectsOf(responseMap["publisherModels"]) {
+		if err := publisherModelFromVertex(model); err != nil {
+			return nil, err
+		}
+	}
+	resp := new(listPublisherModelsResponse)
+	if err := mapToStruct(responseMap, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// publisherModelFromVertex converts a publisher model of the API in place to
+// a PublisherModel: the fields of its Model as converted by modelFromVertex,
+// and its supported actions, an object with one field per action, to the
+// sorted names of the actions.
+func publisherModelFromVertex(model map[string]any) error {
+	converted, err := modelFromVertex(model, nil, model)
+	if err != nil {
+		return err
+	}
+	switch actions := model["supportedActions"].(type) {
+	case map[string]any:
+		names := make([]any, 0, len(actions))
+		for _, name := range sortedKeys(actions) {
+			names = append(names, name)
+		}
+		converted["supportedActions"] = names
+	case []any:
+		converted["supportedActions"] = actions
+	case nil:
+	default:
+		return fmt.Errorf("publisherModelFromVertex: unexpected supported actions %v", actions)
+	}
+	for _, field := range []string{"launchStage", "openSourceCategory"} {
+		if value, ok := model[field]; ok {
+			converted[field] = value
+		}
+	}
+	clear(model)
+	for k, v := range converted {
+		model[k] = v
+	}
+	return nil
+}
+
+// ListPublisherModels retrieves a paginated list of the base models of a
+// publisher on Vertex AI, with their launch stage and supported actions, e.g.
+// to offer a choice of models without hardcoding their names. Set
+// [ListPublisherModelsConfig.Publisher] to "*" to list all Model Garden
+// models. It is only supported in the Vertex AI client.
+func (m Models) ListPublisherModels(ctx context.Context, config *ListPublisherModelsConfig) (Page[PublisherModel], error) {
+	listFunc := func(ctx context.Context, config map[string]any) ([]*PublisherModel, string, *HTTPResponse, error) {
+		var c ListPublisherModelsConfig
+		if err := mapToStruct(config, &c); err != nil {
+			return nil, "", nil, err
+		}
+		resp, err := m.listPublisherModels(ctx, &c)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return resp.PublisherModels, resp.NextPageToken, resp.SDKHTTPResponse, nil
+	}
+	c := make(map[string]any)
+	deepMarshal(config, &c)
+	return newPage(ctx, "publisherModels", c, listFunc)
+}
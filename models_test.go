@@ -1025,3 +1025,43 @@ func TestModelsAllEmptyResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestModelsListPublisherModels(t *testing.T) {
+	ctx := context.Background()
+	var requests []string
+	client := newTestClient(t, &ClientConfig{Backend: BackendVertexAI}, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		w.Write([]byte(`{"publisherModels": [{
+			"name": "publishers/google/models/gemini-2.5-flash",
+			"versionId": "001",
+			"launchStage": "GA",
+			"openSourceCategory": "PROPRIETARY",
+			"supportedActions": {"openGenerationAiStudio": {}, "deploy": {}}
+		}]}`))
+	})
+
+	page, err := client.Models.ListPublisherModels(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPublisherModels() failed: %v", err)
+	}
+	want := []*PublisherModel{{
+		Model: Model{
+			Name:             "publishers/google/models/gemini-2.5-flash",
+			Version:          "001",
+			SupportedActions: []string{"deploy", "openGenerationAiStudio"},
+			TunedModelInfo:   &TunedModelInfo{},
+		},
+		LaunchStage:        LaunchStageGA,
+		OpenSourceCategory: "PROPRIETARY",
+	}}
+	if diff := cmp.Diff(want, page.Items); diff != "" {
+		t.Errorf("ListPublisherModels() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := client.Models.ListPublisherModels(ctx, &ListPublisherModelsConfig{Publisher: "*", ListAllVersions: true}); err != nil {
+		t.Fatalf("ListPublisherModels() with a publisher failed: %v", err)
+	}
+	wantRequests := []string{"/v1beta1/publishers/google/models", "/v1beta1/publishers/*/models?listAllVersions=true"}
+	if diff := cmp.Diff(wantRequests, requests); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/auth"
+	"cloud.google.com/go/auth/httptransport"
+)
+
+// GeminiAPIScopes are the OAuth scopes that credentials need to call the
+// Gemini API instead of an API key. See
+// https://ai.google.dev/gemini-api/docs/oauth.
+var GeminiAPIScopes = []string{
+	"https://www.googleapis.com/auth/cloud-platform",
+	"https://www.googleapis.com/auth/generative-language.retriever",
+}
+
+// geminiAPIHTTPClient returns an HTTP client that authorizes Gemini API
+// requests with the tokens of creds. The Gemini API bills requests with user
+// credentials to the quota project in the X-Goog-User-Project header, which is
+// the quota project of creds or else project. Requests are sent with base, or
+// the default transport if it is nil.
+func geminiAPIHTTPClient(ctx context.Context, creds *auth.Credentials, project string, base http.RoundTripper) (*http.Client, error) {
+	quotaProjectID, err := creds.QuotaProjectID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quota project ID: %w", err)
+	}
+	if quotaProjectID == "" {
+		quotaProjectID = project
+	}
+	headers := http.Header{}
+	if quotaProjectID != "" {
+		headers.Set("X-Goog-User-Project", quotaProjectID)
+	}
+	client, err := httptransport.NewClient(&httptransport.Options{
+		Credentials:      creds,
+		Headers:          headers,
+		BaseRoundTripper: base,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	return client, nil
+}
+
+// TokenProvider provides the OAuth access tokens that authorize the requests
+// of a client, e.g. tokens issued by a workload identity broker. See
+// ClientConfig.TokenProvider.
+type TokenProvider interface {
+	// Token returns an access token and the time it expires. A zero expiry
+	// means that the token does not expire.
+	Token(ctx context.Context) (string, time.Time, error)
+}
+
+// tokenProviderAdapter adapts a TokenProvider to an auth.TokenProvider.
+type tokenProviderAdapter struct {
+	provider TokenProvider
+}
+
+func (a tokenProviderAdapter) Token(ctx context.Context) (*auth.Token, error) {
+	value, expiry, err := a.provider.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a token from the token provider: %w", err)
+	}
+	return &auth.Token{Value: value, Type: "Bearer", Expiry: expiry}, nil
+}
+
+// tokenProviderCredentials returns credentials whose tokens come from
+// provider. Tokens are cached and refreshed in the background shortly before
+// they expire. The quota project of the credentials is project.
+func tokenProviderCredentials(provider TokenProvider, project string) *auth.Credentials {
+	return auth.NewCredentials(&auth.CredentialsOptions{
+		TokenProvider: auth.NewCachedTokenProvider(tokenProviderAdapter{provider: provider}, nil),
+		QuotaProjectIDProvider: auth.CredentialsPropertyFunc(func(context.Context) (string, error) {
+			return project, nil
+		}),
+	})
+}
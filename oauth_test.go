@@ -0,0 +1,144 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/auth"
+)
+
+func TestGeminiAPICredentials(t *testing.T) {
+	ctx := context.Background()
+	var gotHeader http.Header
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		fmt.Fprint(w, `{}`)
+	}
+
+	tests := []struct {
+		name             string
+		quotaProject     string
+		project          string
+		wantQuotaProject string
+	}{
+		{"CredentialsQuotaProject", "creds-project", "config-project", "creds-project"},
+		{"ConfigProject", "", "config-project", "config-project"},
+		{"NoQuotaProject", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, &ClientConfig{
+				Project: tt.project,
+				Credentials: auth.NewCredentials(&auth.CredentialsOptions{
+					TokenProvider: mockCredentials{MockToken: &auth.Token{Value: "user-access-token"}},
+					QuotaProjectIDProvider: auth.CredentialsPropertyFunc(func(context.Context) (string, error) {
+						return tt.quotaProject, nil
+					}),
+				}),
+				envVarProvider: func() map[string]string { return map[string]string{"GEMINI_API_KEY": "env-api-key"} },
+			}, handler)
+			if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("hello"), nil); err != nil {
+				t.Fatalf("GenerateContent() failed: %v", err)
+			}
+			if got := gotHeader.Get("Authorization"); got != "Bearer user-access-token" {
+				t.Errorf("Authorization = %q, want the bearer token of the credentials", got)
+			}
+			if got := gotHeader.Get("X-Goog-User-Project"); got != tt.wantQuotaProject {
+				t.Errorf("X-Goog-User-Project = %q, want %q", got, tt.wantQuotaProject)
+			}
+			if got := gotHeader.Get("X-Goog-Api-Key"); got != "" {
+				t.Errorf("x-goog-api-key = %q, want no API key", got)
+			}
+		})
+	}
+
+	if _, err := NewClient(ctx, &ClientConfig{Backend: BackendGeminiAPI, envVarProvider: func() map[string]string { return map[string]string{} }}); err == nil {
+		t.Errorf("NewClient() without API key and credentials succeeded, want error")
+	}
+}
+
+type countingTokenProvider struct {
+	calls  int
+	expiry time.Duration
+}
+
+func (p *countingTokenProvider) Token(ctx context.Context) (string, time.Time, error) {
+	p.calls++
+	return fmt.Sprintf("broker-token-%d", p.calls), time.Now().Add(p.expiry), nil
+}
+
+func TestTokenProvider(t *testing.T) {
+	ctx := context.Background()
+	var gotHeader http.Header
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		fmt.Fprint(w, `{}`)
+	}
+	noEnv := func() map[string]string { return map[string]string{} }
+
+	for _, backend := range []Backend{BackendGeminiAPI, BackendVertexAI} {
+		t.Run(backend.String(), func(t *testing.T) {
+			provider := &countingTokenProvider{expiry: time.Hour}
+			client := newTestClient(t, &ClientConfig{
+				Backend:       backend,
+				Project:       "quota-project",
+				Location:      "us-central1",
+				TokenProvider: provider,
+			}, handler)
+			for i := 0; i < 2; i++ {
+				if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("hello"), nil); err != nil {
+					t.Fatalf("GenerateContent() failed: %v", err)
+				}
+				if got := gotHeader.Get("Authorization"); got != "Bearer broker-token-1" {
+					t.Errorf("Authorization = %q, want the cached token of the provider", got)
+				}
+				if got := gotHeader.Get("X-Goog-User-Project"); got != "quota-project" {
+					t.Errorf("X-Goog-User-Project = %q, want quota-project", got)
+				}
+			}
+			if provider.calls != 1 {
+				t.Errorf("provider called %d times, want the token to be cached", provider.calls)
+			}
+		})
+	}
+
+	t.Run("Expired", func(t *testing.T) {
+		provider := &countingTokenProvider{expiry: -time.Second}
+		client := newTestClient(t, &ClientConfig{TokenProvider: provider}, handler)
+		for i := 0; i < 2; i++ {
+			if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("hello"), nil); err != nil {
+				t.Fatalf("GenerateContent() failed: %v", err)
+			}
+		}
+		if provider.calls != 2 {
+			t.Errorf("provider called %d times, want expired tokens to be refreshed", provider.calls)
+		}
+	})
+
+	t.Run("MutuallyExclusive", func(t *testing.T) {
+		if _, err := NewClient(ctx, &ClientConfig{Backend: BackendGeminiAPI, APIKey: "key", TokenProvider: &countingTokenProvider{}, envVarProvider: noEnv}); err == nil {
+			t.Error("NewClient() with an API key and a token provider succeeded, want error")
+		}
+		creds := auth.NewCredentials(&auth.CredentialsOptions{TokenProvider: mockCredentials{MockToken: &auth.Token{Value: "t"}}})
+		if _, err := NewClient(ctx, &ClientConfig{Backend: BackendGeminiAPI, Credentials: creds, TokenProvider: &countingTokenProvider{}, envVarProvider: noEnv}); err == nil {
+			t.Error("NewClient() with credentials and a token provider succeeded, want error")
+		}
+	})
+}
@@ -17,7 +17,9 @@ package genai
 import (
 	"context"
 	"errors"
+	"fmt"
 	"iter"
+	"reflect"
 )
 
 // ErrPageDone is the error returned by an iterator's Next method when no more pages are available.
@@ -95,3 +97,93 @@ func (p Page[T]) Next(ctx context.Context) (Page[T], error) {
 
 	return newPage[T](ctx, p.Name, c, p.listFunc)
 }
+
+// Pager fetches the results of a List method one page at a time, with explicit
+// control over the page size and page token.
+//
+// Unlike the All iterators, a Pager never fetches more than the requested page,
+// which makes it suitable for serving classic paginated endpoints: pass the
+// page token received from the caller to [NewPager], call [Pager.NextPage]
+// once, and return the items together with the next page token.
+//
+//	pager := genai.NewPager(client.Models.List, &genai.ListModelsConfig{}, 20, token)
+//	models, nextToken, err := pager.NextPage(ctx)
+type Pager[T any] struct {
+	list      func(ctx context.Context, pageSize int32, pageToken string) (Page[T], error)
+	pageSize  int32
+	pageToken string
+	done      bool
+}
+
+// NewPager returns a Pager over a List method such as [Models.List],
+// [Files.List] or [Batches.List].
+//
+// The PageSize and PageToken fields of config are overridden by pageSize and
+// pageToken. A pageSize of zero uses the server default, and an empty pageToken
+// starts from the first page. config is not modified.
+//
+// List methods with additional arguments, such as [Documents.List], can be
+// adapted with a closure.
+func NewPager[T, C any](list func(context.Context, *C) (Page[T], error), config *C, pageSize int32, pageToken string) *Pager[T] {
+	return &Pager[T]{
+		list: func(ctx context.Context, pageSize int32, pageToken string) (Page[T], error) {
+			c := new(C)
+			if config != nil {
+				*c = *config
+			}
+			if err := setPagination(c, pageSize, pageToken); err != nil {
+				return Page[T]{}, err
+			}
+			return list(ctx, c)
+		},
+		pageSize:  pageSize,
+		pageToken: pageToken,
+	}
+}
+
+// setPagination sets the PageSize and PageToken fields of a List config.
+func setPagination(config any, pageSize int32, pageToken string) error {
+	v := reflect.ValueOf(config).Elem()
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("setPagination: config must be a struct, got %s", v.Type())
+	}
+	sizeField := v.FieldByName("PageSize")
+	tokenField := v.FieldByName("PageToken")
+	if !sizeField.IsValid() || sizeField.Kind() != reflect.Int32 || !tokenField.IsValid() || tokenField.Kind() != reflect.String {
+		return fmt.Errorf("setPagination: %s has no PageSize and PageToken fields", v.Type())
+	}
+	sizeField.SetInt(int64(pageSize))
+	tokenField.SetString(pageToken)
+	return nil
+}
+
+// PageSize returns the maximum number of items requested per page.
+func (p *Pager[T]) PageSize() int32 {
+	return p.pageSize
+}
+
+// PageToken returns the token of the page that the next call to NextPage
+// fetches. It is empty before the first page and after the last page.
+func (p *Pager[T]) PageToken() string {
+	return p.pageToken
+}
+
+// NextPage fetches the next page of results and returns its items together
+// with the token of the following page.
+//
+// An empty nextPageToken means that the returned page is the last one. Calling
+// NextPage after the last page returns [ErrPageDone].
+func (p *Pager[T]) NextPage(ctx context.Context) (items []*T, nextPageToken string, err error) {
+	if p.done {
+		return nil, "", ErrPageDone
+	}
+	page, err := p.list(ctx, p.pageSize, p.pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	p.pageToken = page.NextPageToken
+	if p.pageToken == "" {
+		p.done = true
+	}
+	return page.Items, page.NextPageToken, nil
+}
@@ -141,3 +141,86 @@ func TestPageAll(t *testing.T) {
 	}
 
 }
+
+func TestPager(t *testing.T) {
+	ctx := context.Background()
+	pages := map[string]Page[string]{
+		"":       {Items: []*string{Ptr("item1"), Ptr("item2")}, NextPageToken: "token2"},
+		"token2": {Items: []*string{Ptr("item3"), Ptr("item4")}, NextPageToken: "token3"},
+		"token3": {Items: []*string{Ptr("item5")}},
+	}
+	var gotConfigs []ListFilesConfig
+	list := func(ctx context.Context, config *ListFilesConfig) (Page[string], error) {
+		gotConfigs = append(gotConfigs, *config)
+		page, ok := pages[config.PageToken]
+		if !ok {
+			return Page[string]{}, errors.New("invalid page token")
+		}
+		return page, nil
+	}
+
+	t.Run("FromFirstPage", func(t *testing.T) {
+		gotConfigs = nil
+		config := &ListFilesConfig{PageSize: 100}
+		pager := NewPager(list, config, 2, "")
+		if pager.PageSize() != 2 {
+			t.Errorf("PageSize() = %d, want 2", pager.PageSize())
+		}
+
+		var got []*string
+		var tokens []string
+		for {
+			items, next, err := pager.NextPage(ctx)
+			if err != nil {
+				t.Fatalf("NextPage() failed: %v", err)
+			}
+			got = append(got, items...)
+			tokens = append(tokens, next)
+			if next == "" {
+				break
+			}
+			if pager.PageToken() != next {
+				t.Errorf("PageToken() = %q, want %q", pager.PageToken(), next)
+			}
+		}
+		want := []*string{Ptr("item1"), Ptr("item2"), Ptr("item3"), Ptr("item4"), Ptr("item5")}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("items mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]string{"token2", "token3", ""}, tokens); diff != "" {
+			t.Errorf("tokens mismatch (-want +got):\n%s", diff)
+		}
+		for _, c := range gotConfigs {
+			if c.PageSize != 2 {
+				t.Errorf("list called with PageSize %d, want 2", c.PageSize)
+			}
+		}
+		if config.PageSize != 100 || config.PageToken != "" {
+			t.Errorf("NewPager modified config: %+v", config)
+		}
+		if _, _, err := pager.NextPage(ctx); err != ErrPageDone {
+			t.Errorf("NextPage() after last page error = %v, want ErrPageDone", err)
+		}
+	})
+
+	t.Run("FromPageToken", func(t *testing.T) {
+		pager := NewPager(list, nil, 0, "token2")
+		items, next, err := pager.NextPage(ctx)
+		if err != nil {
+			t.Fatalf("NextPage() failed: %v", err)
+		}
+		if diff := cmp.Diff([]*string{Ptr("item3"), Ptr("item4")}, items); diff != "" {
+			t.Errorf("items mismatch (-want +got):\n%s", diff)
+		}
+		if next != "token3" {
+			t.Errorf("next page token = %q, want token3", next)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		pager := NewPager(list, nil, 0, "bogus")
+		if _, _, err := pager.NextPage(ctx); err == nil {
+			t.Error("NextPage() with invalid token should return an error")
+		}
+	})
+}
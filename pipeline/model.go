@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/plar/genai"
+)
+
+// Generate returns a named step that sends its input as a user prompt to
+// model with client.Models.GenerateContent and returns the response.
+func Generate(name string, client *genai.Client, model string, config *genai.GenerateContentConfig) Step[string, *genai.GenerateContentResponse] {
+	return Func(name, func(ctx context.Context, prompt string) (*genai.GenerateContentResponse, error) {
+		return generate(ctx, client, model, prompt, config)
+	})
+}
+
+// GenerateText is like [Generate] but returns the text of the response.
+func GenerateText(name string, client *genai.Client, model string, config *genai.GenerateContentConfig) Step[string, string] {
+	return Func(name, func(ctx context.Context, prompt string) (string, error) {
+		resp, err := generate(ctx, client, model, prompt, config)
+		if err != nil {
+			return "", err
+		}
+		return resp.Text(), nil
+	})
+}
+
+// Extract returns a named step that asks model for a JSON response to its
+// input and decodes it into a T. Set config.ResponseSchema or
+// config.ResponseJsonSchema to constrain the response to the shape of T; the
+// response MIME type is set to application/json unless config sets another
+// one.
+func Extract[T any](name string, client *genai.Client, model string, config *genai.GenerateContentConfig) Step[string, T] {
+	cfg := &genai.GenerateContentConfig{}
+	if config != nil {
+		*cfg = *config
+	}
+	if cfg.ResponseMIMEType == "" {
+		cfg.ResponseMIMEType = "application/json"
+	}
+	return Func(name, func(ctx context.Context, prompt string) (T, error) {
+		var out T
+		resp, err := generate(ctx, client, model, prompt, cfg)
+		if err != nil {
+			return out, err
+		}
+		if err := json.Unmarshal([]byte(resp.Text()), &out); err != nil {
+			return out, fmt.Errorf("failed to decode the response into %T: %w", out, err)
+		}
+		return out, nil
+	})
+}
+
+// Embed returns a named step that embeds its input with
+// client.Models.EmbedContent and returns the embedding values.
+func Embed(name string, client *genai.Client, model string, config *genai.EmbedContentConfig) Step[string, []float32] {
+	return Func(name, func(ctx context.Context, text string) ([]float32, error) {
+		resp, err := client.Models.EmbedContent(ctx, model, genai.Text(text), config)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Embeddings) == 0 || resp.Embeddings[0] == nil {
+			return nil, errors.New("the response has no embedding")
+		}
+		return resp.Embeddings[0].Values, nil
+	})
+}
+
+func generate(ctx context.Context, client *genai.Client, model, prompt string, config *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error) {
+	resp, err := client.Models.GenerateContent(ctx, model, genai.Text(prompt), config)
+	if err != nil {
+		return nil, err
+	}
+	if resp.UsageMetadata != nil {
+		addTokens(ctx, int(resp.UsageMetadata.TotalTokenCount))
+	}
+	return resp, nil
+}
@@ -0,0 +1,343 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pipeline composes model calls and plain functions into typed,
+// multi-step workflows.
+//
+// A [Step] turns an input of one type into an output of another. Steps are
+// built with [Func] or the model steps [Generate], [GenerateText], [Extract]
+// and [Embed], and combined with [Then], [Branch], [Both] and [ForEach]:
+//
+//	summarize := pipeline.GenerateText("summarize", client, "gemini-2.5-flash", nil)
+//	extract := pipeline.Extract[Invoice]("extract", client, "gemini-2.5-flash", nil)
+//	p := pipeline.Then(summarize, extract)
+//	invoice, trace, err := pipeline.Run(ctx, p, document)
+//
+// [Run] records a [Span] for every named step, including the steps that run
+// concurrently, in a single [Trace].
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Step is a unit of work that turns an In into an Out. Steps are immutable
+// and can be reused by several pipelines and concurrent runs.
+type Step[In, Out any] struct {
+	name string
+	run  func(ctx context.Context, in In) (Out, error)
+}
+
+// Name returns the name of the step, or an empty string for an unnamed
+// composition.
+func (s Step[In, Out]) Name() string {
+	return s.name
+}
+
+// Func returns a step that calls fn. Calls of a step with an empty name are
+// not recorded in the trace.
+func Func[In, Out any](name string, fn func(ctx context.Context, in In) (Out, error)) Step[In, Out] {
+	return Step[In, Out]{name: name, run: traced(name, fn)}
+}
+
+// Then returns a step that runs first and passes its output to second.
+func Then[A, B, C any](first Step[A, B], second Step[B, C]) Step[A, C] {
+	return Step[A, C]{run: func(ctx context.Context, in A) (C, error) {
+		mid, err := first.run(ctx, in)
+		if err != nil {
+			var zero C
+			return zero, err
+		}
+		return second.run(ctx, mid)
+	}}
+}
+
+// Branch returns a named step that runs ifTrue if cond returns true for the
+// input and ifFalse otherwise. The span of the step records the branch taken
+// in its "branch" attribute.
+func Branch[In, Out any](name string, cond func(in In) bool, ifTrue, ifFalse Step[In, Out]) Step[In, Out] {
+	return Func(name, func(ctx context.Context, in In) (Out, error) {
+		if cond(in) {
+			setAttribute(ctx, "branch", "true")
+			return ifTrue.run(ctx, in)
+		}
+		setAttribute(ctx, "branch", "false")
+		return ifFalse.run(ctx, in)
+	})
+}
+
+// Pair holds the outputs of the two steps of [Both].
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Both returns a named step that runs a and b concurrently with the same
+// input. If either fails, the context of the other is cancelled and the first
+// error is returned.
+func Both[In, A, B any](name string, a Step[In, A], b Step[In, B]) Step[In, Pair[A, B]] {
+	return Func(name, func(ctx context.Context, in In) (Pair[A, B], error) {
+		var out Pair[A, B]
+		g := newGroup(ctx)
+		g.do(func(ctx context.Context) (err error) {
+			out.First, err = a.run(ctx, in)
+			return err
+		})
+		g.do(func(ctx context.Context) (err error) {
+			out.Second, err = b.run(ctx, in)
+			return err
+		})
+		if err := g.wait(); err != nil {
+			return Pair[A, B]{}, err
+		}
+		return out, nil
+	})
+}
+
+// ForEach returns a named step that runs step for every element of its input
+// and returns the outputs in the same order. At most concurrency elements are
+// processed at once; zero or less means no limit. If any element fails, the
+// remaining ones are cancelled and the first error is returned.
+func ForEach[In, Out any](name string, step Step[In, Out], concurrency int) Step[[]In, []Out] {
+	return Func(name, func(ctx context.Context, in []In) ([]Out, error) {
+		out := make([]Out, len(in))
+		g := newGroup(ctx)
+		var sem chan struct{}
+		if concurrency > 0 {
+			sem = make(chan struct{}, concurrency)
+		}
+	loop:
+		for i, item := range in {
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+				case <-g.ctx.Done():
+					g.fail(g.ctx.Err())
+					break loop
+				}
+			}
+			g.do(func(ctx context.Context) (err error) {
+				if sem != nil {
+					defer func() { <-sem }()
+				}
+				out[i], err = step.run(ctx, item)
+				if err != nil {
+					return fmt.Errorf("item %d: %w", i, err)
+				}
+				return nil
+			})
+		}
+		if err := g.wait(); err != nil {
+			return nil, err
+		}
+		return out, nil
+	})
+}
+
+// group runs functions concurrently and keeps the first error.
+type group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+}
+
+func newGroup(ctx context.Context) *group {
+	ctx, cancel := context.WithCancel(ctx)
+	return &group{ctx: ctx, cancel: cancel}
+}
+
+func (g *group) do(fn func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := g.ctx.Err(); err != nil {
+			g.fail(err)
+			return
+		}
+		if err := fn(g.ctx); err != nil {
+			g.fail(err)
+		}
+	}()
+}
+
+func (g *group) fail(err error) {
+	g.once.Do(func() {
+		g.err = err
+		g.cancel()
+	})
+}
+
+func (g *group) wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
+
+// Span is the record of one named step of a run.
+type Span struct {
+	// ID identifies the span within the trace, starting at 1.
+	ID int `json:"id"`
+	// Parent is the ID of the enclosing span, or 0 for a top-level step.
+	Parent int `json:"parent,omitempty"`
+	// Name is the name of the step.
+	Name string `json:"name"`
+	// Start is when the step started.
+	Start time.Time `json:"start"`
+	// Duration is how long the step took.
+	Duration time.Duration `json:"duration"`
+	// TotalTokens is the token usage of the model calls of the step, excluding
+	// nested steps.
+	TotalTokens int `json:"totalTokens,omitempty"`
+	// Attributes are step-specific details, e.g. the branch taken by [Branch].
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Error is the error returned by the step, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Trace is the record of a run.
+type Trace struct {
+	// Spans are the spans of the named steps, in the order they started.
+	Spans []*Span `json:"spans"`
+	// TotalTokens is the token usage summed over all spans.
+	TotalTokens int `json:"totalTokens,omitempty"`
+	// Duration is how long the run took.
+	Duration time.Duration `json:"duration"`
+}
+
+// Span returns the first span with the given name, or nil.
+func (t *Trace) Span(name string) *Span {
+	for _, s := range t.Spans {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// recorder collects the spans of a run. It is safe for concurrent use.
+type recorder struct {
+	mu    sync.Mutex
+	trace *Trace
+}
+
+type recorderKey struct{}
+
+type spanKey struct{}
+
+// Run runs the pipeline step with input in and returns its output and the
+// trace of the run. The trace is returned even if the run fails.
+func Run[In, Out any](ctx context.Context, step Step[In, Out], in In) (Out, *Trace, error) {
+	start := time.Now()
+	rec := &recorder{trace: &Trace{}}
+	ctx = context.WithValue(ctx, recorderKey{}, rec)
+	out, err := step.run(ctx, in)
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.trace.Duration = time.Since(start)
+	if err != nil {
+		return out, rec.trace, fmt.Errorf("pipeline: %w", err)
+	}
+	return out, rec.trace, nil
+}
+
+// traced wraps fn so that each call records a span in the trace of the run.
+func traced[In, Out any](name string, fn func(ctx context.Context, in In) (Out, error)) func(context.Context, In) (Out, error) {
+	return func(ctx context.Context, in In) (Out, error) {
+		rec, _ := ctx.Value(recorderKey{}).(*recorder)
+		if rec == nil || name == "" {
+			return fn(ctx, in)
+		}
+		span := &Span{Name: name, Start: time.Now()}
+		if parent, ok := ctx.Value(spanKey{}).(*Span); ok {
+			span.Parent = parent.ID
+		}
+		rec.mu.Lock()
+		span.ID = len(rec.trace.Spans) + 1
+		rec.trace.Spans = append(rec.trace.Spans, span)
+		rec.mu.Unlock()
+
+		out, err := fn(context.WithValue(ctx, spanKey{}, span), in)
+
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		span.Duration = time.Since(span.Start)
+		if err != nil {
+			span.Error = err.Error()
+			// Name the failing step once, at the innermost span.
+			var se *StepError
+			if !errors.As(err, &se) {
+				err = &StepError{Step: name, Err: err}
+			}
+		}
+		return out, err
+	}
+}
+
+// StepError is returned by Run when a named step fails.
+type StepError struct {
+	// Step is the name of the innermost failing step.
+	Step string
+	// Err is the error returned by the step.
+	Err error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("step %q: %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// currentSpan returns the span of the running step and its recorder, or nil
+// if the step runs outside of Run.
+func currentSpan(ctx context.Context) (*Span, *recorder) {
+	rec, _ := ctx.Value(recorderKey{}).(*recorder)
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	if rec == nil || span == nil {
+		return nil, nil
+	}
+	return span, rec
+}
+
+func setAttribute(ctx context.Context, key, value string) {
+	span, rec := currentSpan(ctx)
+	if span == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if span.Attributes == nil {
+		span.Attributes = map[string]string{}
+	}
+	span.Attributes[key] = value
+}
+
+// addTokens adds the token usage of a model call to the running step.
+func addTokens(ctx context.Context, tokens int) {
+	span, rec := currentSpan(ctx)
+	if span == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	span.TotalTokens += tokens
+	rec.trace.TotalTokens += tokens
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/plar/genai"
+)
+
+// newTestClient returns a client whose GenerateContent calls answer with
+// reply(prompt) and whose embeddings have the length of the text.
+func newTestClient(t *testing.T, reply func(prompt string) string) *genai.Client {
+	t.Helper()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ":batchEmbedContents") {
+			var req struct {
+				Requests []struct {
+					Content *genai.Content `json:"content"`
+				} `json:"requests"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			text := req.Requests[0].Content.Parts[0].Text
+			fmt.Fprintf(w, `{"embeddings": [{"values": [%d]}]}`, len(text))
+			return
+		}
+		var req struct {
+			Contents []*genai.Content `json:"contents"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid request: %v", err)
+		}
+		resp := &genai.GenerateContentResponse{
+			Candidates: []*genai.Candidate{{
+				Content:      genai.NewContentFromText(reply(req.Contents[0].Parts[0].Text), genai.RoleModel),
+				FinishReason: genai.FinishReasonStop,
+			}},
+			UsageMetadata: &genai.GenerateContentResponseUsageMetadata{TotalTokenCount: 10},
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(ts.Close)
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:      "test-api-key",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: ts.URL, APIVersion: "v1beta"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+type ticket struct {
+	Category string `json:"category"`
+	Urgent   bool   `json:"urgent"`
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, func(prompt string) string {
+		switch {
+		case strings.HasPrefix(prompt, "Classify"):
+			return `{"category": "billing", "urgent": true}`
+		case strings.HasPrefix(prompt, "Escalate"):
+			return "Escalated."
+		}
+		return "Queued."
+	})
+
+	classify := Then(
+		Func("prompt", func(ctx context.Context, text string) (string, error) { return "Classify: " + text, nil }),
+		Extract[ticket]("classify", client, "gemini-2.5-flash", nil),
+	)
+	route := Branch("route", func(t ticket) bool { return t.Urgent },
+		Then(
+			Func("escalation prompt", func(ctx context.Context, t ticket) (string, error) { return "Escalate " + t.Category, nil }),
+			GenerateText("escalate", client, "gemini-2.5-flash", nil),
+		),
+		Func("queue", func(ctx context.Context, t ticket) (string, error) { return "Queued.", nil }),
+	)
+	p := Both("analyze", Then(classify, route), Embed("embed", client, "gemini-embedding-001", nil))
+
+	out, trace, err := Run(ctx, p, "I was charged twice!")
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if out.First != "Escalated." || len(out.Second) != 1 || out.Second[0] != 20 {
+		t.Errorf("Run() = %+v, want the escalation and the embedding", out)
+	}
+	if trace.TotalTokens != 20 {
+		t.Errorf("TotalTokens = %d, want 20", trace.TotalTokens)
+	}
+	if len(trace.Spans) != 7 {
+		t.Fatalf("got %d spans, want 7", len(trace.Spans))
+	}
+	analyze := trace.Span("analyze")
+	for _, name := range []string{"prompt", "classify", "route", "embed"} {
+		if s := trace.Span(name); s.Parent != analyze.ID {
+			t.Errorf("span %q has parent %d, want analyze", name, s.Parent)
+		}
+	}
+	if s := trace.Span("escalate"); s.Parent != trace.Span("route").ID || s.TotalTokens != 10 {
+		t.Errorf("escalate span = %+v, want a child of route with 10 tokens", s)
+	}
+	if diff := cmp.Diff(map[string]string{"branch": "true"}, trace.Span("route").Attributes); diff != "" {
+		t.Errorf("route attributes mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestForEach(t *testing.T) {
+	ctx := context.Background()
+	var running, maxRunning atomic.Int32
+	double := Func("double", func(ctx context.Context, n int) (int, error) {
+		cur := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if cur <= m || maxRunning.CompareAndSwap(m, cur) {
+				break
+			}
+		}
+		if n < 0 {
+			return 0, errors.New("negative")
+		}
+		return 2 * n, nil
+	})
+
+	out, trace, err := Run(ctx, ForEach("all", double, 2), []int{1, 2, 3, 4, 5})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	if diff := cmp.Diff([]int{2, 4, 6, 8, 10}, out); diff != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+	}
+	if maxRunning.Load() > 2 {
+		t.Errorf("%d items ran concurrently, want at most 2", maxRunning.Load())
+	}
+	if len(trace.Spans) != 6 {
+		t.Errorf("got %d spans, want 6", len(trace.Spans))
+	}
+
+	_, trace, err = Run(ctx, ForEach("all", double, 0), []int{1, -1})
+	var se *StepError
+	if !errors.As(err, &se) || se.Step != "double" || !strings.Contains(err.Error(), "item 1") {
+		t.Fatalf("Run() error = %v, want a double step error for item 1", err)
+	}
+	if s := trace.Span("all"); s.Error == "" {
+		t.Errorf("all span = %+v, want an error", s)
+	}
+}
@@ -0,0 +1,544 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RAGCorpora provides access to the Vertex AI RAG Engine corpora, the
+// retrieval sources used by the VertexRAGStore tool. It is only supported in
+// the Vertex AI client.
+type RAGCorpora struct {
+	apiClient *apiClient
+}
+
+// RAGCorpus is a Vertex AI RAG Engine corpus, a collection of indexed files.
+type RAGCorpus struct {
+	// Output only. The resource name of the corpus, e.g.
+	// "projects/my-project/locations/us-central1/ragCorpora/123".
+	Name string `json:"name,omitempty"`
+	// Required. The display name of the corpus.
+	DisplayName string `json:"displayName,omitempty"`
+	// Optional. The description of the corpus.
+	Description string `json:"description,omitempty"`
+	// Optional. The vector database and embedding model of the corpus. The
+	// service defaults are used if nil.
+	VectorDBConfig *RAGVectorDBConfig `json:"vectorDbConfig,omitempty"`
+	// Output only. The state of the corpus.
+	CorpusStatus *RAGCorpusStatus `json:"corpusStatus,omitempty"`
+	// Output only. When the corpus was created.
+	CreateTime time.Time `json:"createTime,omitempty"`
+	// Output only. When the corpus was last updated.
+	UpdateTime time.Time `json:"updateTime,omitempty"`
+}
+
+// RAGVectorDBConfig configures the vector database of a RAG corpus.
+type RAGVectorDBConfig struct {
+	// Optional. The embedding model used to index the files.
+	RAGEmbeddingModelConfig *RAGEmbeddingModelConfig `json:"ragEmbeddingModelConfig,omitempty"`
+	// Optional. Uses the managed RagManagedDb vector database.
+	RAGManagedDB map[string]any `json:"ragManagedDb,omitempty"`
+}
+
+// RAGEmbeddingModelConfig configures the embedding model of a RAG corpus.
+type RAGEmbeddingModelConfig struct {
+	// Optional. The Vertex AI prediction endpoint of the model, e.g.
+	// "projects/my-project/locations/us-central1/publishers/google/models/text-embedding-005".
+	VertexPredictionEndpoint *RAGVertexPredictionEndpoint `json:"vertexPredictionEndpoint,omitempty"`
+}
+
+// RAGVertexPredictionEndpoint is a Vertex AI prediction endpoint.
+type RAGVertexPredictionEndpoint struct {
+	// Required. The resource name of the endpoint or publisher model.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Output only. The resource name of the model deployed to the endpoint.
+	Model string `json:"model,omitempty"`
+}
+
+// RAGCorpusStatus is the state of a RAG corpus.
+type RAGCorpusStatus struct {
+	// Output only. The state, e.g. "ACTIVE" or "ERROR".
+	State string `json:"state,omitempty"`
+	// Output only. The error details if the state is "ERROR".
+	ErrorStatus string `json:"errorStatus,omitempty"`
+}
+
+// RAGFile is a file imported into a RAG corpus.
+type RAGFile struct {
+	// Output only. The resource name of the file.
+	Name string `json:"name,omitempty"`
+	// Output only. The display name of the file.
+	DisplayName string `json:"displayName,omitempty"`
+	// Output only. The description of the file.
+	Description string `json:"description,omitempty"`
+	// Output only. The Cloud Storage source of the file, if any.
+	GCSSource *RAGGCSSource `json:"gcsSource,omitempty"`
+	// Output only. The Google Drive source of the file, if any.
+	GoogleDriveSource *RAGGoogleDriveSource `json:"googleDriveSource,omitempty"`
+	// Output only. The state of the file.
+	FileStatus *RAGFileStatus `json:"fileStatus,omitempty"`
+	// Output only. When the file was created.
+	CreateTime time.Time `json:"createTime,omitempty"`
+	// Output only. When the file was last updated.
+	UpdateTime time.Time `json:"updateTime,omitempty"`
+}
+
+// RAGFileStatus is the state of a RAG file.
+type RAGFileStatus struct {
+	// Output only. The state, e.g. "ACTIVE" or "ERROR".
+	State string `json:"state,omitempty"`
+	// Output only. The error details if the state is "ERROR".
+	ErrorStatus string `json:"errorStatus,omitempty"`
+}
+
+// RAGGCSSource selects Cloud Storage files to import.
+type RAGGCSSource struct {
+	// Required. File or directory URIs, e.g. "gs://bucket/docs/" or
+	// "gs://bucket/docs/*.pdf".
+	URIs []string `json:"uris,omitempty"`
+}
+
+// RAGGoogleDriveSource selects Google Drive files and folders to import.
+type RAGGoogleDriveSource struct {
+	// Required. The files and folders to import.
+	ResourceIDs []*RAGGoogleDriveResourceID `json:"resourceIds,omitempty"`
+}
+
+// RAGGoogleDriveResourceType is the type of a Google Drive resource.
+type RAGGoogleDriveResourceType string
+
+const (
+	// RAGGoogleDriveResourceTypeFile is a Google Drive file.
+	RAGGoogleDriveResourceTypeFile RAGGoogleDriveResourceType = "RESOURCE_TYPE_FILE"
+	// RAGGoogleDriveResourceTypeFolder is a Google Drive folder.
+	RAGGoogleDriveResourceTypeFolder RAGGoogleDriveResourceType = "RESOURCE_TYPE_FOLDER"
+)
+
+// RAGGoogleDriveResourceID identifies a Google Drive file or folder.
+type RAGGoogleDriveResourceID struct {
+	// Required. The type of the resource.
+	ResourceType RAGGoogleDriveResourceType `json:"resourceType,omitempty"`
+	// Required. The ID of the resource, as found in its URL.
+	ResourceID string `json:"resourceId,omitempty"`
+}
+
+// RAGChunkingConfig configures how imported files are split into chunks.
+type RAGChunkingConfig struct {
+	// Optional. The size of each chunk, in tokens.
+	ChunkSize int32 `json:"chunkSize,omitempty"`
+	// Optional. The number of tokens that consecutive chunks share.
+	ChunkOverlap int32 `json:"chunkOverlap,omitempty"`
+}
+
+// CreateRAGCorpusConfig configures RAGCorpora.Create.
+type CreateRAGCorpusConfig struct {
+	// Optional. Used to override HTTP request options.
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+	// Optional. The description of the corpus.
+	Description string `json:"description,omitempty"`
+	// Optional. The vector database and embedding model of the corpus.
+	VectorDBConfig *RAGVectorDBConfig `json:"vectorDbConfig,omitempty"`
+}
+
+// GetRAGCorpusConfig configures RAGCorpora.Get.
+type GetRAGCorpusConfig struct {
+	// Optional. Used to override HTTP request options.
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+}
+
+// DeleteRAGCorpusConfig configures RAGCorpora.Delete.
+type DeleteRAGCorpusConfig struct {
+	// Optional. Used to override HTTP request options.
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+	// Optional. Also deletes the files of the corpus. Without it, deleting a
+	// corpus that has files fails.
+	Force bool `json:"force,omitempty"`
+}
+
+// ListRAGCorporaConfig configures RAGCorpora.List.
+type ListRAGCorporaConfig struct {
+	// Optional. Used to override HTTP request options.
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+	// Optional. The maximum number of corpora per page.
+	PageSize int32 `json:"pageSize,omitempty"`
+	// Optional. The token of the page to retrieve.
+	PageToken string `json:"pageToken,omitempty"`
+}
+
+// ListRAGFilesConfig configures RAGCorpora.ListFiles.
+type ListRAGFilesConfig struct {
+	// Optional. Used to override HTTP request options.
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+	// Optional. The maximum number of files per page.
+	PageSize int32 `json:"pageSize,omitempty"`
+	// Optional. The token of the page to retrieve.
+	PageToken string `json:"pageToken,omitempty"`
+}
+
+// DeleteRAGFileConfig configures RAGCorpora.DeleteFile.
+type DeleteRAGFileConfig struct {
+	// Optional. Used to override HTTP request options.
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+}
+
+// ImportRAGFilesConfig configures RAGCorpora.ImportFiles. Exactly one of
+// GCSSource and GoogleDriveSource must be set.
+type ImportRAGFilesConfig struct {
+	// Optional. Used to override HTTP request options.
+	HTTPOptions *HTTPOptions `json:"httpOptions,omitempty"`
+	// Optional. The Cloud Storage files to import.
+	GCSSource *RAGGCSSource `json:"gcsSource,omitempty"`
+	// Optional. The Google Drive files to import.
+	GoogleDriveSource *RAGGoogleDriveSource `json:"googleDriveSource,omitempty"`
+	// Optional. How files are split into chunks. The service default is used
+	// if nil.
+	Chunking *RAGChunkingConfig `json:"chunking,omitempty"`
+	// Optional. The maximum number of embedding requests per minute, to stay
+	// within the quota of the embedding model.
+	MaxEmbeddingRequestsPerMin int32 `json:"maxEmbeddingRequestsPerMin,omitempty"`
+}
+
+// RAGCorpusOperation is the long-running operation of RAGCorpora.Create.
+type RAGCorpusOperation struct {
+	// The server-assigned name of the operation.
+	Name string `json:"name,omitempty"`
+	// Optional. Service-specific metadata associated with the operation.
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// If true, the operation is completed, and either Error or Response is
+	// available.
+	Done bool `json:"done,omitempty"`
+	// Optional. The error of the operation in case of failure or cancellation.
+	Error map[string]any `json:"error,omitempty"`
+	// Optional. The created corpus, available when the operation is done.
+	Response *RAGCorpus `json:"response,omitempty"`
+}
+
+// ImportRAGFilesResponse is the result of an import of RAG files.
+type ImportRAGFilesResponse struct {
+	// The number of files that were imported.
+	ImportedRAGFilesCount int64 `json:"importedRagFilesCount,omitempty,string"`
+	// The number of files that failed to import.
+	FailedRAGFilesCount int64 `json:"failedRagFilesCount,omitempty,string"`
+	// The number of files that were skipped because they were already
+	// imported.
+	SkippedRAGFilesCount int64 `json:"skippedRagFilesCount,omitempty,string"`
+}
+
+// ImportRAGFilesOperation is the long-running operation of
+// RAGCorpora.ImportFiles.
+type ImportRAGFilesOperation struct {
+	// The server-assigned name of the operation.
+	Name string `json:"name,omitempty"`
+	// Optional. Service-specific metadata associated with the operation.
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// If true, the operation is completed, and either Error or Response is
+	// available.
+	Done bool `json:"done,omitempty"`
+	// Optional. The error of the operation in case of failure or cancellation.
+	Error map[string]any `json:"error,omitempty"`
+	// Optional. The result of the import, available when the operation is
+	// done.
+	Response *ImportRAGFilesResponse `json:"response,omitempty"`
+}
+
+type listRAGCorporaResponse struct {
+	RAGCorpora      []*RAGCorpus  `json:"ragCorpora,omitempty"`
+	NextPageToken   string        `json:"nextPageToken,omitempty"`
+	SDKHTTPResponse *HTTPResponse `json:"sdkHttpResponse,omitempty"`
+}
+
+type listRAGFilesResponse struct {
+	RAGFiles        []*RAGFile    `json:"ragFiles,omitempty"`
+	NextPageToken   string        `json:"nextPageToken,omitempty"`
+	SDKHTTPResponse *HTTPResponse `json:"sdkHttpResponse,omitempty"`
+}
+
+// ragCorpusName returns the resource name of a corpus given its name or ID.
+func ragCorpusName(name string) string {
+	if strings.HasPrefix(name, "projects/") || strings.HasPrefix(name, "ragCorpora/") {
+		return name
+	}
+	return "ragCorpora/" + name
+}
+
+func (r *RAGCorpora) checkBackend(method string) error {
+	if r.apiClient.clientConfig.Backend != BackendVertexAI {
+		return fmt.Errorf("method %s is only supported in the Vertex AI client. You can choose to use Vertex AI by setting ClientConfig.Backend to BackendVertexAI.", method)
+	}
+	return nil
+}
+
+// sendRAGRequest sends a RAG request and decodes the response into out, if
+// out is not nil.
+func sendRAGRequest[R any](ctx context.Context, ac *apiClient, method, path string, body any, httpOptions *HTTPOptions, out *R) error {
+	if httpOptions == nil {
+		httpOptions = &HTTPOptions{}
+	}
+	responseMap, err := sendRequest(ctx, ac, path, method, body, httpOptions)
+	if err != nil || out == nil {
+		return err
+	}
+	return mapToStruct(responseMap, out)
+}
+
+// Create starts the creation of a RAG corpus and returns its long-running
+// operation. Use [Operations.GetRAGCorpusOperation] to wait for the corpus.
+func (r *RAGCorpora) Create(ctx context.Context, displayName string, config *CreateRAGCorpusConfig) (*RAGCorpusOperation, error) {
+	if err := r.checkBackend("Create"); err != nil {
+		return nil, err
+	}
+	if displayName == "" {
+		return nil, fmt.Errorf("displayName is required")
+	}
+	if config == nil {
+		config = &CreateRAGCorpusConfig{}
+	}
+	body := map[string]any{"displayName": displayName}
+	if config.Description != "" {
+		body["description"] = config.Description
+	}
+	if config.VectorDBConfig != nil {
+		body["vectorDbConfig"] = config.VectorDBConfig
+	}
+	op := new(RAGCorpusOperation)
+	if err := sendRAGRequest(ctx, r.apiClient, http.MethodPost, "ragCorpora", body, config.HTTPOptions, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// Get returns the RAG corpus with the given resource name or ID.
+func (r *RAGCorpora) Get(ctx context.Context, name string, config *GetRAGCorpusConfig) (*RAGCorpus, error) {
+	if err := r.checkBackend("Get"); err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &GetRAGCorpusConfig{}
+	}
+	corpus := new(RAGCorpus)
+	if err := sendRAGRequest(ctx, r.apiClient, http.MethodGet, ragCorpusName(name), nil, config.HTTPOptions, corpus); err != nil {
+		return nil, err
+	}
+	return corpus, nil
+}
+
+// Delete deletes the RAG corpus with the given resource name or ID. The
+// corpus is removed in the background.
+func (r *RAGCorpora) Delete(ctx context.Context, name string, config *DeleteRAGCorpusConfig) error {
+	if err := r.checkBackend("Delete"); err != nil {
+		return err
+	}
+	if config == nil {
+		config = &DeleteRAGCorpusConfig{}
+	}
+	path := ragCorpusName(name)
+	if config.Force {
+		path += "?force=true"
+	}
+	return sendRAGRequest[struct{}](ctx, r.apiClient, http.MethodDelete, path, nil, config.HTTPOptions, nil)
+}
+
+func (r *RAGCorpora) list(ctx context.Context, config *ListRAGCorporaConfig) (*listRAGCorporaResponse, error) {
+	if err := r.checkBackend("List"); err != nil {
+		return nil, err
+	}
+	path, err := pagedPath("ragCorpora", config.PageSize, config.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(listRAGCorporaResponse)
+	if err := sendRAGRequest(ctx, r.apiClient, http.MethodGet, path, nil, config.HTTPOptions, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// List retrieves a paginated list of RAG corpora.
+func (r *RAGCorpora) List(ctx context.Context, config *ListRAGCorporaConfig) (Page[RAGCorpus], error) {
+	listFunc := func(ctx context.Context, config map[string]any) ([]*RAGCorpus, string, *HTTPResponse, error) {
+		var c ListRAGCorporaConfig
+		if err := mapToStruct(config, &c); err != nil {
+			return nil, "", nil, err
+		}
+		resp, err := r.list(ctx, &c)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return resp.RAGCorpora, resp.NextPageToken, resp.SDKHTTPResponse, nil
+	}
+	c := make(map[string]any)
+	deepMarshal(config, &c)
+	return newPage(ctx, "ragCorpora", c, listFunc)
+}
+
+// All retrieves all RAG corpora, handling pagination internally.
+func (r *RAGCorpora) All(ctx context.Context) iter.Seq2[*RAGCorpus, error] {
+	p, err := r.List(ctx, nil)
+	if err != nil {
+		return yieldErrorAndEndIterator[RAGCorpus](err)
+	}
+	return p.all(ctx)
+}
+
+// ImportFiles starts importing files from Cloud Storage or Google Drive into
+// the corpus with the given resource name or ID, and returns the long-running
+// operation. Use [Operations.GetImportRAGFilesOperation] to wait for the
+// import.
+func (r *RAGCorpora) ImportFiles(ctx context.Context, corpus string, config *ImportRAGFilesConfig) (*ImportRAGFilesOperation, error) {
+	if err := r.checkBackend("ImportFiles"); err != nil {
+		return nil, err
+	}
+	if config == nil || (config.GCSSource == nil) == (config.GoogleDriveSource == nil) {
+		return nil, fmt.Errorf("exactly one of GCSSource and GoogleDriveSource must be set")
+	}
+	importConfig := map[string]any{}
+	if config.GCSSource != nil {
+		importConfig["gcsSource"] = config.GCSSource
+	}
+	if config.GoogleDriveSource != nil {
+		importConfig["googleDriveSource"] = config.GoogleDriveSource
+	}
+	if config.Chunking != nil {
+		importConfig["ragFileTransformationConfig"] = map[string]any{
+			"ragFileChunkingConfig": map[string]any{"fixedLengthChunking": config.Chunking},
+		}
+	}
+	if config.MaxEmbeddingRequestsPerMin > 0 {
+		importConfig["maxEmbeddingRequestsPerMin"] = config.MaxEmbeddingRequestsPerMin
+	}
+	body := map[string]any{"importRagFilesConfig": importConfig}
+	op := new(ImportRAGFilesOperation)
+	if err := sendRAGRequest(ctx, r.apiClient, http.MethodPost, ragCorpusName(corpus)+"/ragFiles:import", body, config.HTTPOptions, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (r *RAGCorpora) listFiles(ctx context.Context, corpus string, config *ListRAGFilesConfig) (*listRAGFilesResponse, error) {
+	if err := r.checkBackend("ListFiles"); err != nil {
+		return nil, err
+	}
+	path, err := pagedPath(ragCorpusName(corpus)+"/ragFiles", config.PageSize, config.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(listRAGFilesResponse)
+	if err := sendRAGRequest(ctx, r.apiClient, http.MethodGet, path, nil, config.HTTPOptions, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListFiles retrieves a paginated list of the files of the corpus with the
+// given resource name or ID.
+func (r *RAGCorpora) ListFiles(ctx context.Context, corpus string, config *ListRAGFilesConfig) (Page[RAGFile], error) {
+	listFunc := func(ctx context.Context, config map[string]any) ([]*RAGFile, string, *HTTPResponse, error) {
+		var c ListRAGFilesConfig
+		if err := mapToStruct(config, &c); err != nil {
+			return nil, "", nil, err
+		}
+		resp, err := r.listFiles(ctx, corpus, &c)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return resp.RAGFiles, resp.NextPageToken, resp.SDKHTTPResponse, nil
+	}
+	c := make(map[string]any)
+	deepMarshal(config, &c)
+	return newPage(ctx, "ragFiles", c, listFunc)
+}
+
+// DeleteFile deletes a file from its corpus, given the resource name of the
+// file.
+func (r *RAGCorpora) DeleteFile(ctx context.Context, name string, config *DeleteRAGFileConfig) error {
+	if err := r.checkBackend("DeleteFile"); err != nil {
+		return err
+	}
+	if !strings.Contains(name, "/ragFiles/") {
+		return fmt.Errorf("invalid RAG file name %q", name)
+	}
+	if config == nil {
+		config = &DeleteRAGFileConfig{}
+	}
+	return sendRAGRequest[struct{}](ctx, r.apiClient, http.MethodDelete, name, nil, config.HTTPOptions, nil)
+}
+
+// pagedPath adds the page size and page token query parameters to path.
+func pagedPath(path string, pageSize int32, pageToken string) (string, error) {
+	query := map[string]any{}
+	if pageSize > 0 {
+		query["pageSize"] = int(pageSize)
+	}
+	if pageToken != "" {
+		query["pageToken"] = pageToken
+	}
+	if len(query) == 0 {
+		return path, nil
+	}
+	q, err := createURLQuery(query)
+	if err != nil {
+		return "", err
+	}
+	return path + "?" + q, nil
+}
+
+// GetRAGCorpusOperation retrieves the status and result of a long-running
+// RAG corpus creation.
+//
+// If the operation is still in progress, the returned operation has Done set
+// to false. Once it completes, either Response holds the corpus or Error is
+// populated.
+func (m Operations) GetRAGCorpusOperation(ctx context.Context, operation *RAGCorpusOperation, config *GetOperationConfig) (*RAGCorpusOperation, error) {
+	op := new(RAGCorpusOperation)
+	if err := getRAGOperation(ctx, m.apiClient, "GetRAGCorpusOperation", operation.Name, config, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// GetImportRAGFilesOperation retrieves the status and result of a long-running
+// import of RAG files.
+//
+// If the operation is still in progress, the returned operation has Done set
+// to false. Once it completes, either Response holds the import counts or
+// Error is populated.
+func (m Operations) GetImportRAGFilesOperation(ctx context.Context, operation *ImportRAGFilesOperation, config *GetOperationConfig) (*ImportRAGFilesOperation, error) {
+	op := new(ImportRAGFilesOperation)
+	if err := getRAGOperation(ctx, m.apiClient, "GetImportRAGFilesOperation", operation.Name, config, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func getRAGOperation[R any](ctx context.Context, ac *apiClient, method, name string, config *GetOperationConfig, out *R) error {
+	if name == "" {
+		return fmt.Errorf("Operation name is empty")
+	}
+	r := &RAGCorpora{apiClient: ac}
+	if err := r.checkBackend(method); err != nil {
+		return err
+	}
+	var httpOptions *HTTPOptions
+	if config != nil {
+		httpOptions = config.HTTPOptions
+	}
+	return sendRAGRequest(ctx, ac, http.MethodGet, name, nil, httpOptions, out)
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRAGCorpora(t *testing.T) {
+	ctx := context.Background()
+	const prefix = "/v1beta1/projects/test-project/locations/us-central1/"
+	var requests []string
+	var bodies []map[string]any
+	client := newTestClient(t, &ClientConfig{
+		Backend:     BackendVertexAI,
+		HTTPOptions: HTTPOptions{APIVersion: "v1beta1"},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.RequestURI(), prefix))
+		var body map[string]any
+		if r.ContentLength > 0 {
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("invalid request body: %v", err)
+			}
+		}
+		bodies = append(bodies, body)
+		path := strings.TrimPrefix(r.URL.Path, prefix)
+		switch {
+		case r.Method == http.MethodPost && path == "ragCorpora":
+			w.Write([]byte(`{"name": "projects/test-project/locations/us-central1/operations/1"}`))
+		case r.Method == http.MethodGet && path == "operations/1":
+			w.Write([]byte(`{"name": "projects/test-project/locations/us-central1/operations/1", "done": true,
+				"response": {"name": "projects/test-project/locations/us-central1/ragCorpora/123", "displayName": "docs", "corpusStatus": {"state": "ACTIVE"}}}`))
+		case r.Method == http.MethodGet && path == "ragCorpora" && r.URL.Query().Get("pageToken") == "":
+			w.Write([]byte(`{"ragCorpora": [{"name": "a"}], "nextPageToken": "next"}`))
+		case r.Method == http.MethodGet && path == "ragCorpora":
+			w.Write([]byte(`{"ragCorpora": [{"name": "b"}]}`))
+		case r.Method == http.MethodPost && path == "ragCorpora/123/ragFiles:import":
+			w.Write([]byte(`{"name": "projects/test-project/locations/us-central1/operations/2"}`))
+		case r.Method == http.MethodGet && path == "operations/2":
+			w.Write([]byte(`{"name": "projects/test-project/locations/us-central1/operations/2", "done": true,
+				"response": {"importedRagFilesCount": "3", "skippedRagFilesCount": "1"}}`))
+		case r.Method == http.MethodGet && path == "ragCorpora/123/ragFiles":
+			w.Write([]byte(`{"ragFiles": [{"name": "projects/test-project/locations/us-central1/ragCorpora/123/ragFiles/9", "displayName": "a.pdf"}]}`))
+		case r.Method == http.MethodDelete:
+			w.Write([]byte(`{"name": "projects/test-project/locations/us-central1/operations/3"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	})
+
+	op, err := client.RAGCorpora.Create(ctx, "docs", &CreateRAGCorpusConfig{
+		VectorDBConfig: &RAGVectorDBConfig{RAGEmbeddingModelConfig: &RAGEmbeddingModelConfig{
+			VertexPredictionEndpoint: &RAGVertexPredictionEndpoint{Endpoint: "publishers/google/models/text-embedding-005"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	op, err = client.Operations.GetRAGCorpusOperation(ctx, op, nil)
+	if err != nil {
+		t.Fatalf("GetRAGCorpusOperation() failed: %v", err)
+	}
+	if !op.Done || op.Response.DisplayName != "docs" || op.Response.CorpusStatus.State != "ACTIVE" {
+		t.Errorf("GetRAGCorpusOperation() = %+v, want the created corpus", op)
+	}
+
+	var names []string
+	for corpus, err := range client.RAGCorpora.All(ctx) {
+		if err != nil {
+			t.Fatalf("All() failed: %v", err)
+		}
+		names = append(names, corpus.Name)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, names); diff != "" {
+		t.Errorf("All() mismatch (-want +got):\n%s", diff)
+	}
+
+	importOp, err := client.RAGCorpora.ImportFiles(ctx, "123", &ImportRAGFilesConfig{
+		GCSSource: &RAGGCSSource{URIs: []string{"gs://bucket/docs/"}},
+		Chunking:  &RAGChunkingConfig{ChunkSize: 512, ChunkOverlap: 100},
+	})
+	if err != nil {
+		t.Fatalf("ImportFiles() failed: %v", err)
+	}
+	importOp, err = client.Operations.GetImportRAGFilesOperation(ctx, importOp, nil)
+	if err != nil {
+		t.Fatalf("GetImportRAGFilesOperation() failed: %v", err)
+	}
+	if want := (&ImportRAGFilesResponse{ImportedRAGFilesCount: 3, SkippedRAGFilesCount: 1}); !cmp.Equal(want, importOp.Response) {
+		t.Errorf("import response = %+v, want %+v", importOp.Response, want)
+	}
+
+	files, err := client.RAGCorpora.ListFiles(ctx, "123", &ListRAGFilesConfig{PageSize: 10})
+	if err != nil {
+		t.Fatalf("ListFiles() failed: %v", err)
+	}
+	if len(files.Items) != 1 || files.Items[0].DisplayName != "a.pdf" {
+		t.Errorf("ListFiles() = %+v, want a.pdf", files.Items)
+	}
+	if err := client.RAGCorpora.DeleteFile(ctx, files.Items[0].Name, nil); err != nil {
+		t.Errorf("DeleteFile() failed: %v", err)
+	}
+	if err := client.RAGCorpora.Delete(ctx, op.Response.Name, &DeleteRAGCorpusConfig{Force: true}); err != nil {
+		t.Errorf("Delete() failed: %v", err)
+	}
+
+	wantRequests := []string{
+		"POST ragCorpora",
+		"GET operations/1",
+		"GET ragCorpora",
+		"GET ragCorpora?pageToken=next",
+		"POST ragCorpora/123/ragFiles:import",
+		"GET operations/2",
+		"GET ragCorpora/123/ragFiles?pageSize=10",
+		"DELETE ragCorpora/123/ragFiles/9",
+		"DELETE ragCorpora/123?force=true",
+	}
+	if diff := cmp.Diff(wantRequests, requests); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+	wantCreate := map[string]any{
+		"displayName": "docs",
+		"vectorDbConfig": map[string]any{"ragEmbeddingModelConfig": map[string]any{
+			"vertexPredictionEndpoint": map[string]any{"endpoint": "publishers/google/models/text-embedding-005"},
+		}},
+	}
+	if diff := cmp.Diff(wantCreate, bodies[0]); diff != "" {
+		t.Errorf("create body mismatch (-want +got):\n%s", diff)
+	}
+	wantImport := map[string]any{"importRagFilesConfig": map[string]any{
+		"gcsSource": map[string]any{"uris": []any{"gs://bucket/docs/"}},
+		"ragFileTransformationConfig": map[string]any{"ragFileChunkingConfig": map[string]any{
+			"fixedLengthChunking": map[string]any{"chunkSize": float64(512), "chunkOverlap": float64(100)},
+		}},
+	}}
+	if diff := cmp.Diff(wantImport, bodies[4]); diff != "" {
+		t.Errorf("import body mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := client.RAGCorpora.ImportFiles(ctx, "123", &ImportRAGFilesConfig{}); err == nil {
+		t.Error("ImportFiles() without a source succeeded, want an error")
+	}
+	gemini, err := NewClient(ctx, &ClientConfig{APIKey: "test-api-key", Backend: BackendGeminiAPI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gemini.RAGCorpora.Get(ctx, "123", nil); err == nil || !strings.Contains(err.Error(), "only supported in the Vertex AI client") {
+		t.Errorf("Get() with the Gemini API = %v, want an unsupported error", err)
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rateLimitWindow = time.Minute
+
+// RateLimit limits the API calls of a client to stay within quotas. The
+// limits are shared by all the services of the client and apply to every
+// attempt of a call, including retries. A call waits until it is within the
+// limits or its context is done.
+type RateLimit struct {
+	// Optional. The maximum number of requests sent in any minute. Zero means
+	// no limit.
+	RequestsPerMinute int
+	// Optional. The maximum number of tokens used by the requests sent in any
+	// minute. A request reserves its estimated tokens before it is sent, and
+	// the reservation is corrected with the total token count of the usage
+	// metadata of its response. Zero means no limit.
+	TokensPerMinute int
+	// Optional. The maximum number of requests in flight at once, including
+	// streams that have not been read to the end or stopped. Zero means no
+	// limit.
+	MaxConcurrentRequests int
+	// Optional. Estimates the tokens of a call before it is sent, e.g. with
+	// a local tokenizer or the TotalTokens of a CountTokens response. If nil,
+	// the estimate is a quarter of the number of characters of the text in
+	// the request body. Token counting calls are not counted.
+	EstimateTokens func(call *InterceptedCall) int
+}
+
+// rateLimiter enforces a RateLimit with sliding windows of a minute.
+type rateLimiter struct {
+	limit RateLimit
+	slots chan struct{}
+
+	mu       sync.Mutex
+	requests []time.Time
+	tokens   []*rateReservation
+	now      func() time.Time
+}
+
+// rateReservation is the share of the token limit taken by a request.
+type rateReservation struct {
+	limiter *rateLimiter
+	at      time.Time
+	tokens  int
+}
+
+func newRateLimiter(limit *RateLimit) *rateLimiter {
+	if limit == nil {
+		return nil
+	}
+	l := &rateLimiter{limit: *limit, now: time.Now}
+	if limit.MaxConcurrentRequests > 0 {
+		l.slots = make(chan struct{}, limit.MaxConcurrentRequests)
+	}
+	return l
+}
+
+// acquire waits until call can be sent within the limits. It returns the
+// token reservation of the call and a function that releases its
+// concurrency slot.
+func (l *rateLimiter) acquire(ctx context.Context, call *InterceptedCall, body any) (*rateReservation, func(), error) {
+	if l == nil {
+		return nil, func() {}, nil
+	}
+	release := func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		var once sync.Once
+		release = func() { once.Do(func() { <-l.slots }) }
+	}
+	estimate := 0
+	if l.limit.TokensPerMinute > 0 && !strings.Contains(call.Path, ":countTokens") {
+		if l.limit.EstimateTokens != nil {
+			estimate = l.limit.EstimateTokens(call)
+		} else {
+			estimate = estimateBodyTokens(body)
+		}
+	}
+	for {
+		wait, reservation := l.reserve(estimate)
+		if reservation != nil {
+			return reservation, release, nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			release()
+			return nil, nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve records a request of estimate tokens if it is within the limits.
+// Otherwise it returns how long to wait before trying again.
+func (l *rateLimiter) reserve(estimate int) (time.Duration, *rateReservation) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	cutoff := now.Add(-rateLimitWindow)
+	for len(l.requests) > 0 && !l.requests[0].After(cutoff) {
+		l.requests = l.requests[1:]
+	}
+	for len(l.tokens) > 0 && !l.tokens[0].at.After(cutoff) {
+		l.tokens = l.tokens[1:]
+	}
+	if rpm := l.limit.RequestsPerMinute; rpm > 0 && len(l.requests) >= rpm {
+		return l.requests[0].Sub(cutoff), nil
+	}
+	if tpm := l.limit.TokensPerMinute; tpm > 0 && len(l.tokens) > 0 {
+		used := 0
+		for _, r := range l.tokens {
+			used += r.tokens
+		}
+		// A request larger than the limit is sent alone.
+		if used+estimate > tpm {
+			return l.tokens[0].at.Sub(cutoff), nil
+		}
+	}
+	l.requests = append(l.requests, now)
+	reservation := &rateReservation{limiter: l, at: now, tokens: estimate}
+	if l.limit.TokensPerMinute > 0 {
+		l.tokens = append(l.tokens, reservation)
+	}
+	return 0, reservation
+}
+
+// settle replaces the estimated tokens of the reservation with the actual
+// count.
+func (r *rateReservation) settle(tokens int) {
+	if r == nil || tokens <= 0 {
+		return
+	}
+	r.limiter.mu.Lock()
+	r.tokens = tokens
+	r.limiter.mu.Unlock()
+}
+
+// rateLimitedBody releases the concurrency slot of a request when its
+// response body is closed, and keeps the token reservation of the request
+// until its usage is known.
+type rateLimitedBody struct {
+	io.ReadCloser
+	reservation *rateReservation
+	release     func()
+}
+
+func (b *rateLimitedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// limitResponse ties the slot and the reservation of a request to resp.
+func limitResponse(resp *http.Response, reservation *rateReservation, release func()) {
+	if resp == nil || resp.Body == nil {
+		release()
+		return
+	}
+	resp.Body = &rateLimitedBody{ReadCloser: resp.Body, reservation: reservation, release: release}
+}
+
+// settleUsage corrects the token reservation of the request of resp with the
+// usage metadata of response, a decoded response body or stream chunk.
+func settleUsage(resp *http.Response, response map[string]any) {
+	body, ok := resp.Body.(*rateLimitedBody)
+	if !ok || body.reservation == nil {
+		return
+	}
+	body.reservation.settle(usageTokens(response))
+}
+
+// settleStreamUsage returns onEvent wrapped to correct the token reservation
+// of the request of resp with the usage metadata of the chunks of the stream.
+func settleStreamUsage(resp *http.Response, onEvent func(*SSEEvent)) func(*SSEEvent) {
+	body, ok := resp.Body.(*rateLimitedBody)
+	if !ok || body.reservation == nil || body.reservation.limiter.limit.TokensPerMinute == 0 {
+		return onEvent
+	}
+	return func(event *SSEEvent) {
+		if strings.Contains(event.Data, "usage") {
+			var chunk map[string]any
+			if json.Unmarshal([]byte(event.Data), &chunk) == nil {
+				body.reservation.settle(usageTokens(chunk))
+			}
+		}
+		if onEvent != nil {
+			onEvent(event)
+		}
+	}
+}
+
+// usageTokens returns the total token count of the usage metadata of a
+// generate content response or of an interaction, or 0 if it has none.
+func usageTokens(response map[string]any) int {
+	if usage, ok := response["usageMetadata"].(map[string]any); ok {
+		if n, ok := usage["totalTokenCount"].(float64); ok {
+			return int(n)
+		}
+	}
+	if interaction, ok := response["interaction"].(map[string]any); ok {
+		response = interaction
+	}
+	if usage, ok := response["usage"].(map[string]any); ok {
+		if n, ok := usage["totalTokens"].(float64); ok {
+			return int(n)
+		}
+	}
+	return 0
+}
+
+// estimateBodyTokens estimates the tokens of a request body as a quarter of
+// the number of characters of its strings, leaving out inline media.
+func estimateBodyTokens(body any) int {
+	bodyMap, err := interceptedBody(body)
+	if err != nil {
+		return 0
+	}
+	chars := 0
+	var walk func(v any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case string:
+			chars += len(v)
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]any:
+			for key, item := range v {
+				if !inlineDataKeys[key] {
+					walk(item)
+				}
+			}
+		}
+	}
+	walk(bodyMap)
+	return (chars + 3) / 4
+}
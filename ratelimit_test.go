@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	t.Run("RequestsPerMinute", func(t *testing.T) {
+		l := newRateLimiter(&RateLimit{RequestsPerMinute: 2})
+		l.now = func() time.Time { return now }
+		for i := 0; i < 2; i++ {
+			if _, r := l.reserve(0); r == nil {
+				t.Fatalf("request %d was limited", i)
+			}
+		}
+		wait, r := l.reserve(0)
+		if r != nil || wait != time.Minute {
+			t.Fatalf("reserve() = %v, %v, want to wait a minute", wait, r)
+		}
+		l.now = func() time.Time { return now.Add(time.Minute) }
+		if _, r := l.reserve(0); r == nil {
+			t.Error("request was limited after the window")
+		}
+	})
+	t.Run("TokensPerMinute", func(t *testing.T) {
+		l := newRateLimiter(&RateLimit{TokensPerMinute: 100})
+		l.now = func() time.Time { return now }
+		_, first := l.reserve(60)
+		if first == nil {
+			t.Fatal("first request was limited")
+		}
+		if _, r := l.reserve(60); r != nil {
+			t.Fatal("request over the token limit was not limited")
+		}
+		first.settle(30)
+		if _, r := l.reserve(60); r == nil {
+			t.Error("request was limited after the usage was settled below the estimate")
+		}
+	})
+	t.Run("OversizedRequestAlone", func(t *testing.T) {
+		l := newRateLimiter(&RateLimit{TokensPerMinute: 10})
+		l.now = func() time.Time { return now }
+		if _, r := l.reserve(50); r == nil {
+			t.Error("oversized request was limited although no other request was sent")
+		}
+	})
+}
+
+func TestRateLimitConcurrency(t *testing.T) {
+	ctx := context.Background()
+	var inFlight, maxInFlight atomic.Int32
+	client := newTestClient(t, &ClientConfig{RateLimit: &RateLimit{MaxConcurrentRequests: 2, TokensPerMinute: 1000}}, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, `{"candidates":[{"content":{"parts":[{"text":"ok"}]}}],"usageMetadata":{"totalTokenCount":5}}`)
+	})
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Hi"), nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("max in-flight requests = %d, want at most 2", got)
+	}
+	used := 0
+	for _, r := range client.Models.apiClient.limiter.tokens {
+		used += r.tokens
+	}
+	if used != 30 {
+		t.Errorf("tokens used = %d, want the 30 tokens of the usage metadata", used)
+	}
+}
+
+func TestRateLimitContextDone(t *testing.T) {
+	l := newRateLimiter(&RateLimit{RequestsPerMinute: 1})
+	call := &InterceptedCall{Path: "models/m:generateContent"}
+	if _, _, err := l.acquire(context.Background(), call, nil); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := l.acquire(ctx, call, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("acquire() = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestEstimateBodyTokens(t *testing.T) {
+	body := map[string]any{"contents": []any{map[string]any{"parts": []any{
+		map[string]any{"text": "12345678"},
+		map[string]any{"inlineData": map[string]any{"mimeType": "", "data": "aGVsbG8gd29ybGQ="}},
+	}}}}
+	if got := estimateBodyTokens(body); got != 2 {
+		t.Errorf("estimateBodyTokens() = %d, want 2", got)
+	}
+}
+
+func TestRateLimitWaitBeforeTimeouts(t *testing.T) {
+	ctx := context.Background()
+	var requests atomic.Int32
+	client := newTestClient(t, &ClientConfig{
+		RateLimit:      &RateLimit{RequestsPerMinute: 1},
+		RequestOptions: RequestOptions{ConnectTimeout: Ptr(50 * time.Millisecond)},
+	}, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `{"candidates":[{"content":{"parts":[{"text":"ok"}]}}]}`)
+	})
+	// After the first request, the clock of the limiter is moved forward so
+	// that the second request waits for about 200ms, longer than the connect
+	// timeout.
+	var skew atomic.Int64
+	client.Models.apiClient.limiter.now = func() time.Time { return time.Now().Add(time.Duration(skew.Load())) }
+	if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Hi"), nil); err != nil {
+		t.Fatal(err)
+	}
+	skew.Store(int64(rateLimitWindow - 200*time.Millisecond))
+	start := time.Now()
+	if _, err := client.Models.GenerateContent(ctx, "gemini-2.5-flash", Text("Hi"), nil); err != nil {
+		t.Fatalf("GenerateContent() of a throttled request error = %v, want nil", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("GenerateContent() took %v, want it to wait for the rate limiter", elapsed)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2 without retries", got)
+	}
+}
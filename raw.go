@@ -0,0 +1,264 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import "encoding/json"
+
+// rawBody returns the raw response JSON retained in r, or nil if
+// [RequestOptions.IncludeRawResponse] was not set for the call.
+func rawBody(r *HTTPResponse) json.RawMessage {
+	if r == nil || r.Body == "" {
+		return nil
+	}
+	return json.RawMessage(r.Body)
+}
+
+// Raw returns the raw JSON of the API response the page was decoded from,
+// or nil if [RequestOptions.IncludeRawResponse] was not set.
+func (p Page[T]) Raw() json.RawMessage { return rawBody(p.SDKHTTPResponse) }
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *GenerateContentResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *EmbedContentResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *GenerateImagesResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *EditImageResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *UpscaleImageResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *ListModelsResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *DeleteModelResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *CountTokensResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *ComputeTokensResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *TuningJob) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *ListTuningJobsResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *CancelTuningJobResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *TuningOperation) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *DeleteCachedContentResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *ListCachedContentsResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *ListDocumentsResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *ListFileSearchStoresResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *UploadToFileSearchStoreResumableResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *ImportFileResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *ListFilesResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *CreateFileResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *DeleteFileResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *ListBatchJobsResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *DeleteResourceJob) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *UploadToFileSearchStoreResponse) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
+
+// Raw returns the raw response JSON, or nil if [RequestOptions.IncludeRawResponse]
+// was not set.
+func (r *Interaction) Raw() json.RawMessage {
+	if r == nil {
+		return nil
+	}
+	return rawBody(r.SDKHTTPResponse)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRawResponse(t *testing.T) {
+	ctx := context.Background()
+	const body = `{"candidates": [{"content": {"role": "model", "parts": [{"text": "hi"}]}}], "futureField": 1}`
+	newClient := func(includeRaw bool) *Client {
+		return newTestClient(t, &ClientConfig{RequestOptions: RequestOptions{IncludeRawResponse: includeRaw}}, func(w http.ResponseWriter, r *http.Request) {
+			if strings.Contains(r.URL.Path, "streamGenerateContent") {
+				fmt.Fprintf(w, "data: %s\n\ndata: %s\n\n", body, body)
+				return
+			}
+			fmt.Fprint(w, body)
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		resp, err := newClient(false).Models.GenerateContent(ctx, "gemini-2.0-flash", Text("hello"), nil)
+		if err != nil {
+			t.Fatalf("GenerateContent() failed: %v", err)
+		}
+		if got := resp.Raw(); got != nil {
+			t.Errorf("Raw() = %s, want nil", got)
+		}
+	})
+
+	t.Run("ClientOption", func(t *testing.T) {
+		resp, err := newClient(true).Models.GenerateContent(ctx, "gemini-2.0-flash", Text("hello"), nil)
+		if err != nil {
+			t.Fatalf("GenerateContent() failed: %v", err)
+		}
+		if got := string(resp.Raw()); got != body {
+			t.Errorf("Raw() = %s, want %s", got, body)
+		}
+	})
+
+	t.Run("RequestOption", func(t *testing.T) {
+		ctx := WithRequestOptions(ctx, &RequestOptions{IncludeRawResponse: true})
+		resp, err := newClient(false).Models.GenerateContent(ctx, "gemini-2.0-flash", Text("hello"), nil)
+		if err != nil {
+			t.Fatalf("GenerateContent() failed: %v", err)
+		}
+		if got := string(resp.Raw()); got != body {
+			t.Errorf("Raw() = %s, want %s", got, body)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		chunks := 0
+		for resp, err := range newClient(true).Models.GenerateContentStream(ctx, "gemini-2.0-flash", Text("hello"), nil) {
+			if err != nil {
+				t.Fatalf("GenerateContentStream() failed: %v", err)
+			}
+			chunks++
+			if got := string(resp.Raw()); got != body {
+				t.Errorf("Raw() = %s, want %s", got, body)
+			}
+		}
+		if chunks != 2 {
+			t.Errorf("got %d chunks, want 2", chunks)
+		}
+	})
+}
@@ -0,0 +1,282 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"encoding"
+	"fmt"
+	"log/slog"
+	"net/textproto"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// redactMaxStringLen is the number of characters of a string value kept when
+	// printing debug representations.
+	redactMaxStringLen = 256
+	// redactMaxSliceLen is the number of slice elements kept when printing debug
+	// representations.
+	redactMaxSliceLen = 8
+	// redactMaxDepth limits the nesting of debug representations.
+	redactMaxDepth = 12
+)
+
+const redactedPlaceholder = "<redacted>"
+
+// sensitiveFields are struct fields whose values are never printed.
+var sensitiveFields = map[string]bool{
+	"APIKey":      true,
+	"Credentials": true,
+}
+
+// sensitiveHeaders are HTTP headers whose values are never printed.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"X-Goog-Api-Key":      true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+}
+
+// inlineDataKeys are the keys of base64-encoded media in JSON request and
+// response bodies, whose values are replaced by their length.
+var inlineDataKeys = map[string]bool{
+	"data":               true,
+	"bytesBase64Encoded": true,
+	"imageBytes":         true,
+	"videoBytes":         true,
+}
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// redactedLogValue returns a slog.Value for v in which byte slices are replaced
+// by their length, long strings and slices are truncated, and credentials are
+// elided.
+func redactedLogValue(v any) slog.Value {
+	return redactValue(reflect.ValueOf(v), 0)
+}
+
+// redactedString returns a compact, human-readable representation of v with the
+// same redactions as redactedLogValue.
+func redactedString(v any) string {
+	var sb strings.Builder
+	writeLogValue(&sb, redactedLogValue(v))
+	return sb.String()
+}
+
+func redactValue(v reflect.Value, depth int) slog.Value {
+	if !v.IsValid() {
+		return slog.StringValue("<nil>")
+	}
+	if depth > redactMaxDepth {
+		return slog.StringValue("...")
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return slog.StringValue("<nil>")
+		}
+		return redactValue(v.Elem(), depth)
+	case reflect.String:
+		return slog.StringValue(truncateString(v.String()))
+	case reflect.Bool:
+		return slog.BoolValue(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type().Implements(stringerType) {
+			return slog.StringValue(v.Interface().(fmt.Stringer).String())
+		}
+		return slog.Int64Value(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return slog.Uint64Value(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return slog.Float64Value(v.Float())
+	case reflect.Func, reflect.Chan:
+		if v.IsNil() {
+			return slog.StringValue("<nil>")
+		}
+		return slog.StringValue("<" + v.Kind().String() + ">")
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return slog.StringValue(fmt.Sprintf("<%d bytes>", v.Len()))
+		}
+		n := min(v.Len(), redactMaxSliceLen)
+		attrs := make([]slog.Attr, 0, n+1)
+		for i := 0; i < n; i++ {
+			attrs = append(attrs, slog.Attr{Key: strconv.Itoa(i), Value: redactValue(v.Index(i), depth+1)})
+		}
+		if v.Len() > n {
+			attrs = append(attrs, slog.String("...", fmt.Sprintf("<%d more>", v.Len()-n)))
+		}
+		return slog.GroupValue(attrs...)
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		isHeader := v.Type().Key().Kind() == reflect.String && v.Type().Elem() == reflect.TypeOf([]string{})
+		attrs := make([]slog.Attr, 0, len(keys))
+		for _, k := range keys {
+			key := fmt.Sprint(k.Interface())
+			if isHeader && sensitiveHeaders[textproto.CanonicalMIMEHeaderKey(key)] {
+				attrs = append(attrs, slog.String(key, redactedPlaceholder))
+				continue
+			}
+			if value := v.MapIndex(k); inlineDataKeys[key] && value.Kind() == reflect.Interface && value.Elem().Kind() == reflect.String {
+				attrs = append(attrs, slog.String(key, fmt.Sprintf("<%d base64 chars>", value.Elem().Len())))
+				continue
+			}
+			attrs = append(attrs, slog.Attr{Key: key, Value: redactValue(v.MapIndex(k), depth+1)})
+		}
+		return slog.GroupValue(attrs...)
+	case reflect.Struct:
+		t := v.Type()
+		// Types from other packages, such as time.Time or civil.Date, are printed
+		// through their text form and otherwise summarized by type name.
+		if t.PkgPath() != sdkPackagePath {
+			if t.Implements(textMarshalerType) {
+				if b, err := v.Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+					return slog.StringValue(string(b))
+				}
+			}
+			return slog.StringValue("<" + t.String() + ">")
+		}
+		var attrs []slog.Attr
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fv := v.Field(i)
+			if !f.IsExported() || fv.IsZero() {
+				continue
+			}
+			key := f.Name
+			if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" && name != "-" {
+				key = name
+			}
+			if sensitiveFields[f.Name] {
+				attrs = append(attrs, slog.String(key, redactedPlaceholder))
+				continue
+			}
+			attrs = append(attrs, slog.Attr{Key: key, Value: redactValue(fv, depth+1)})
+		}
+		return slog.GroupValue(attrs...)
+	}
+	return slog.StringValue("<" + v.Type().String() + ">")
+}
+
+func truncateString(s string) string {
+	if utf8.RuneCountInString(s) <= redactMaxStringLen {
+		return s
+	}
+	runes := []rune(s)
+	return fmt.Sprintf("%s...(%d chars)", string(runes[:redactMaxStringLen]), len(runes))
+}
+
+func writeLogValue(sb *strings.Builder, v slog.Value) {
+	switch v.Kind() {
+	case slog.KindGroup:
+		sb.WriteString("{")
+		for i, attr := range v.Group() {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(attr.Key)
+			sb.WriteString(": ")
+			writeLogValue(sb, attr.Value)
+		}
+		sb.WriteString("}")
+	case slog.KindString:
+		s := v.String()
+		if strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">") {
+			sb.WriteString(s)
+		} else {
+			sb.WriteString(strconv.Quote(s))
+		}
+	default:
+		sb.WriteString(v.String())
+	}
+}
+
+// String returns a debug representation of the ClientConfig with the API key
+// and credentials elided.
+func (cc ClientConfig) String() string { return redactedString(cc) }
+
+// LogValue implements [slog.LogValuer] with the same redactions as String.
+func (cc ClientConfig) LogValue() slog.Value { return redactedLogValue(cc) }
+
+// String returns a debug representation of the HTTPOptions with credential
+// headers elided.
+func (o HTTPOptions) String() string { return redactedString(o) }
+
+// LogValue implements [slog.LogValuer] with the same redactions as String.
+func (o HTTPOptions) LogValue() slog.Value { return redactedLogValue(o) }
+
+// String returns a debug representation of the Blob with the data replaced by
+// its length.
+func (b Blob) String() string { return redactedString(b) }
+
+// LogValue implements [slog.LogValuer] with the same redactions as String.
+func (b Blob) LogValue() slog.Value { return redactedLogValue(b) }
+
+// String returns a debug representation of the Part with inline data replaced
+// by its length and long text truncated.
+func (p Part) String() string { return redactedString(p) }
+
+// LogValue implements [slog.LogValuer] with the same redactions as String.
+func (p Part) LogValue() slog.Value { return redactedLogValue(p) }
+
+// String returns a debug representation of the Content with inline data
+// replaced by its length and long text or part lists truncated.
+func (c Content) String() string { return redactedString(c) }
+
+// LogValue implements [slog.LogValuer] with the same redactions as String.
+func (c Content) LogValue() slog.Value { return redactedLogValue(c) }
+
+// String returns a debug representation of the GenerateContentConfig with
+// inline data, long text and credential headers summarized.
+func (c GenerateContentConfig) String() string { return redactedString(c) }
+
+// LogValue implements [slog.LogValuer] with the same redactions as String.
+func (c GenerateContentConfig) LogValue() slog.Value { return redactedLogValue(c) }
+
+// String returns a debug representation of the GenerateContentResponse with
+// inline data, long text and large candidate lists summarized.
+func (r GenerateContentResponse) String() string { return redactedString(r) }
+
+// LogValue implements [slog.LogValuer] with the same redactions as String.
+func (r GenerateContentResponse) LogValue() slog.Value { return redactedLogValue(r) }
+
+// String returns a debug representation of the Interaction with inline data,
+// long text and large input or output lists summarized.
+func (i Interaction) String() string { return redactedString(i) }
+
+// LogValue implements [slog.LogValuer] with the same redactions as String.
+func (i Interaction) LogValue() slog.Value { return redactedLogValue(i) }
+
+// String returns a debug representation of the InteractionContent with inline
+// data replaced by its length and long text truncated.
+func (c InteractionContent) String() string { return redactedString(c) }
+
+// LogValue implements [slog.LogValuer] with the same redactions as String.
+func (c InteractionContent) LogValue() slog.Value { return redactedLogValue(c) }
+
+// String returns a debug representation of the InteractionEvent with inline
+// data replaced by its length and long text truncated.
+func (e InteractionEvent) String() string { return redactedString(e) }
+
+// LogValue implements [slog.LogValuer] with the same redactions as String.
+func (e InteractionEvent) LogValue() slog.Value { return redactedLogValue(e) }
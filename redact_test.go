@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactedStringers(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     any
+		want      []string
+		forbidden []string
+	}{
+		{
+			name:  "Part",
+			value: &Part{Text: "hello", InlineData: &Blob{MIMEType: "image/png", Data: make([]byte, 2048)}},
+			want:  []string{`{inlineData: {data: <2048 bytes>, mimeType: "image/png"}, text: "hello"}`},
+		},
+		{
+			name:  "Content with many parts and long text",
+			value: &Content{Role: RoleUser, Parts: append([]*Part{{Text: strings.Repeat("a", 300)}}, make([]*Part, 10)...)},
+			want:  []string{`...(300 chars)`, `...: <3 more>`, `role: "user"`},
+		},
+		{
+			name: "ClientConfig",
+			value: &ClientConfig{
+				APIKey:      "secret-key",
+				Backend:     BackendGeminiAPI,
+				HTTPOptions: HTTPOptions{Headers: http.Header{"Authorization": []string{"Bearer token"}, "X-Custom": []string{"v"}}},
+			},
+			want:      []string{`APIKey: <redacted>`, `Backend: "BackendGeminiAPI"`, `Authorization: <redacted>`, `X-Custom: {0: "v"}`},
+			forbidden: []string{"secret-key", "Bearer token"},
+		},
+		{
+			name:      "GenerateContentConfig",
+			value:     &GenerateContentConfig{HTTPOptions: &HTTPOptions{Headers: http.Header{"X-Goog-Api-Key": []string{"secret"}}}, Temperature: Float32(0.5)},
+			want:      []string{`X-Goog-Api-Key: <redacted>`, `temperature: 0.5`},
+			forbidden: []string{"secret"},
+		},
+		{
+			name:  "InteractionContent",
+			value: &InteractionContent{Type: "image", Data: []byte("abc"), MIMEType: "image/png"},
+			want:  []string{`data: <3 bytes>`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fmt.Sprint(tt.value)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("String() = %s, want it to contain %q", got, w)
+				}
+			}
+			for _, f := range tt.forbidden {
+				if strings.Contains(got, f) {
+					t.Errorf("String() = %s, must not contain %q", got, f)
+				}
+			}
+		})
+	}
+}
+
+func TestRedactedLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("request",
+		"config", &ClientConfig{APIKey: "secret-key", Project: "p"},
+		"content", NewContentFromBytes(make([]byte, 100), "audio/wav", RoleUser),
+	)
+	got := buf.String()
+	if strings.Contains(got, "secret-key") {
+		t.Errorf("log output contains the API key: %s", got)
+	}
+	for _, w := range []string{`"APIKey":"<redacted>"`, `"Project":"p"`, `"data":"<100 bytes>"`} {
+		if !strings.Contains(got, w) {
+			t.Errorf("log output = %s, want it to contain %s", got, w)
+		}
+	}
+	if got := fmt.Sprint((*Part)(nil)); got != "<nil>" {
+		t.Errorf("nil Part = %q, want <nil>", got)
+	}
+}
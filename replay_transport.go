@@ -0,0 +1,356 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ReplayMode is the mode of a record/replay transport, see [ReplayOptions].
+type ReplayMode string
+
+const (
+	// ReplayModeOff sends requests to the API without recording them.
+	ReplayModeOff ReplayMode = ""
+	// ReplayModeRecord sends requests to the API and records them with their
+	// responses in the cassette, replacing its previous content.
+	ReplayModeRecord ReplayMode = "record"
+	// ReplayModeReplay answers requests with the responses recorded in the
+	// cassette, without sending them. Requests that were not recorded fail.
+	ReplayModeReplay ReplayMode = "replay"
+	// ReplayModeAuto replays the cassette if it exists, and records it
+	// otherwise.
+	ReplayModeAuto ReplayMode = "auto"
+)
+
+// cassetteVersion is the version of the cassette format.
+const cassetteVersion = 1
+
+// defaultScrubbedHeaders are the headers whose values are never recorded.
+var defaultScrubbedHeaders = []string{"Authorization", "Proxy-Authorization", "X-Goog-Api-Key", "Cookie", "Set-Cookie"}
+
+// ReplayOptions records the HTTP traffic of a client to a cassette file, and
+// replays it in hermetic tests:
+//
+//	mode := genai.ReplayModeReplay
+//	if os.Getenv("RECORD") != "" {
+//		mode = genai.ReplayModeRecord
+//	}
+//	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+//		ReplayOptions: &genai.ReplayOptions{Mode: mode, CassettePath: "testdata/generate.json"},
+//	})
+//
+// Streamed responses and file uploads are recorded as they are sent. API keys
+// and authorization headers are scrubbed from the cassette. In replay mode
+// the client needs no credentials, and requests are matched to the recorded
+// ones by method, URL and body, each recorded interaction answering one
+// request. Live sessions are not recorded.
+type ReplayOptions struct {
+	// Optional. The mode of the transport. Defaults to ReplayModeOff.
+	Mode ReplayMode
+	// Required unless Mode is ReplayModeOff. The path of the cassette, a JSON
+	// file.
+	CassettePath string
+	// Optional. Headers whose values are scrubbed from the cassette in
+	// addition to authorization headers, cookies and API keys.
+	ScrubHeaders []string
+}
+
+// replaying reports whether the client answers requests from the cassette.
+func (o *ReplayOptions) replaying() bool {
+	if o == nil {
+		return false
+	}
+	switch o.Mode {
+	case ReplayModeReplay:
+		return true
+	case ReplayModeAuto:
+		_, err := os.Stat(o.CassettePath)
+		return err == nil
+	}
+	return false
+}
+
+// cassette is the file of a record/replay transport.
+type cassette struct {
+	Version      int                    `json:"version"`
+	Interactions []*cassetteInteraction `json:"interactions"`
+}
+
+type cassetteInteraction struct {
+	Request  cassetteRequest  `json:"request"`
+	Response cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	cassetteBody
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	cassetteBody
+}
+
+// cassetteBody is a body recorded as text if it is valid UTF-8, or in base64
+// otherwise, e.g. for images or gzipped responses.
+type cassetteBody struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+}
+
+func newCassetteBody(data []byte) cassetteBody {
+	if utf8.Valid(data) {
+		return cassetteBody{Body: string(data)}
+	}
+	return cassetteBody{BodyBase64: base64.StdEncoding.EncodeToString(data)}
+}
+
+func (b cassetteBody) bytes() ([]byte, error) {
+	if b.BodyBase64 != "" {
+		return base64.StdEncoding.DecodeString(b.BodyBase64)
+	}
+	return []byte(b.Body), nil
+}
+
+// replayTransport is the http.RoundTripper of ReplayOptions.
+type replayTransport struct {
+	path    string
+	replay  bool
+	base    http.RoundTripper
+	scrub   map[string]bool
+	mu      sync.Mutex
+	tape    cassette
+	used    []bool
+	matches []string
+}
+
+func newReplayTransport(opts *ReplayOptions, base http.RoundTripper) (*replayTransport, error) {
+	if opts.CassettePath == "" {
+		return nil, errors.New("ReplayOptions.CassettePath is required")
+	}
+	switch opts.Mode {
+	case ReplayModeRecord, ReplayModeReplay, ReplayModeAuto:
+	default:
+		return nil, fmt.Errorf("invalid replay mode %q", opts.Mode)
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &replayTransport{path: opts.CassettePath, replay: opts.replaying(), base: base, scrub: map[string]bool{}, tape: cassette{Version: cassetteVersion}}
+	for _, h := range append(defaultScrubbedHeaders, opts.ScrubHeaders...) {
+		t.scrub[http.CanonicalHeaderKey(h)] = true
+	}
+	if !t.replay {
+		return t, nil
+	}
+	data, err := os.ReadFile(opts.CassettePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &t.tape); err != nil {
+		return nil, fmt.Errorf("failed to decode the cassette %s: %w", opts.CassettePath, err)
+	}
+	if t.tape.Version != cassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d, want %d", t.tape.Version, cassetteVersion)
+	}
+	t.used = make([]bool, len(t.tape.Interactions))
+	for _, interaction := range t.tape.Interactions {
+		body, err := interaction.Request.bytes()
+		if err != nil {
+			return nil, fmt.Errorf("invalid request body in the cassette %s: %w", opts.CassettePath, err)
+		}
+		t.matches = append(t.matches, matchKey(interaction.Request.Method, interaction.Request.URL, interaction.Request.Header, body))
+	}
+	return t, nil
+}
+
+// RoundTrip replays or records req.
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	recorded := cassetteRequest{Method: req.Method, URL: t.scrubURL(req.URL), Header: t.scrubHeader(req.Header), cassetteBody: newCassetteBody(body)}
+	if t.replay {
+		return t.replayRequest(req, recorded, body)
+	}
+
+	sent := req.Clone(req.Context())
+	sent.Body = io.NopCloser(bytes.NewReader(body))
+	sent.ContentLength = int64(len(body))
+	resp, err := t.base.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+	interaction := &cassetteInteraction{
+		Request:  recorded,
+		Response: cassetteResponse{StatusCode: resp.StatusCode, Header: t.scrubHeader(resp.Header)},
+	}
+	resp.Body = &recordingBody{rc: resp.Body, done: func(data []byte) error {
+		interaction.Response.cassetteBody = newCassetteBody(data)
+		return t.record(interaction)
+	}}
+	return resp, nil
+}
+
+func (t *replayTransport) replayRequest(req *http.Request, recorded cassetteRequest, body []byte) (*http.Response, error) {
+	key := matchKey(recorded.Method, recorded.URL, recorded.Header, body)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, interaction := range t.tape.Interactions {
+		if t.used[i] || t.matches[i] != key {
+			continue
+		}
+		t.used[i] = true
+		data, err := interaction.Response.bytes()
+		if err != nil {
+			return nil, fmt.Errorf("invalid response body in the cassette %s: %w", t.path, err)
+		}
+		status := interaction.Response.StatusCode
+		return &http.Response{
+			StatusCode:    status,
+			Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(data)),
+			ContentLength: int64(len(data)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no interaction recorded in the cassette %s matches %s %s", t.path, recorded.Method, recorded.URL)
+}
+
+// record appends interaction to the cassette and saves it.
+func (t *replayTransport) record(interaction *cassetteInteraction) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tape.Interactions = append(t.tape.Interactions, interaction)
+	data, err := json.MarshalIndent(t.tape, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return fmt.Errorf("failed to save the cassette: %w", err)
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to save the cassette: %w", err)
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		return fmt.Errorf("failed to save the cassette: %w", err)
+	}
+	return nil
+}
+
+func (t *replayTransport) scrubHeader(header http.Header) http.Header {
+	scrubbed := header.Clone()
+	for key := range scrubbed {
+		if t.scrub[http.CanonicalHeaderKey(key)] {
+			scrubbed[key] = []string{redactedPlaceholder}
+		}
+	}
+	return scrubbed
+}
+
+func (t *replayTransport) scrubURL(u *url.URL) string {
+	return redactURL(u)
+}
+
+// recordingBody records a response body as it is read, e.g. a stream of
+// events, and calls done with it once it is read to the end or closed.
+type recordingBody struct {
+	rc   io.ReadCloser
+	buf  bytes.Buffer
+	once sync.Once
+	done func([]byte) error
+	err  error
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.rc.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		if doneErr := b.finish(); doneErr != nil {
+			return n, doneErr
+		}
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.rc.Close()
+	if doneErr := b.finish(); doneErr != nil {
+		return doneErr
+	}
+	return err
+}
+
+func (b *recordingBody) finish() error {
+	b.once.Do(func() { b.err = b.done(b.buf.Bytes()) })
+	return b.err
+}
+
+// matchKey returns the key by which a request is matched to the recorded
+// ones: its method, URL and a digest of its body. JSON bodies are compared
+// regardless of the order of their keys, and multipart bodies regardless of
+// their boundary.
+func matchKey(method, rawURL string, header http.Header, body []byte) string {
+	if strings.EqualFold(header.Get("Content-Encoding"), "gzip") {
+		if r, err := gzip.NewReader(bytes.NewReader(body)); err == nil {
+			if decompressed, err := io.ReadAll(r); err == nil {
+				body = decompressed
+			}
+		}
+	}
+	if mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type")); err == nil && strings.HasPrefix(mediaType, "multipart/") && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), []byte("boundary"))
+	} else {
+		var v any
+		if json.Unmarshal(body, &v) == nil {
+			if normalized, err := json.Marshal(v); err == nil {
+				body = normalized
+			}
+		}
+	}
+	sum := sha256.Sum256(body)
+	return method + " " + rawURL + " " + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReplayOptions(t *testing.T) {
+	ctx := context.Background()
+	newClient := func(t *testing.T, baseURL, apiKey string, opts *ReplayOptions) *Client {
+		t.Helper()
+		client, err := NewClient(ctx, &ClientConfig{
+			APIKey:         apiKey,
+			Backend:        BackendGeminiAPI,
+			HTTPOptions:    HTTPOptions{BaseURL: baseURL, APIVersion: "v1beta"},
+			ReplayOptions:  opts,
+			envVarProvider: func() map[string]string { return map[string]string{} },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return client
+	}
+	generate := func(client *Client, prompt string) (string, error) {
+		resp, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash", Text(prompt), nil)
+		if err != nil {
+			return "", err
+		}
+		return resp.Text(), nil
+	}
+	stream := func(client *Client) (string, error) {
+		var sb strings.Builder
+		for resp, err := range client.Models.GenerateContentStream(ctx, "gemini-2.0-flash", Text("stream"), nil) {
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(resp.Text())
+		}
+		return sb.String(), nil
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ":streamGenerateContent") {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for _, text := range []string{"Hello", ", world"} {
+				fmt.Fprintf(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":%q}]}}]}\n\n", text)
+			}
+			return
+		}
+		var prompt struct {
+			Contents []*Content `json:"contents"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&prompt); err != nil {
+			t.Errorf("failed to decode the request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"candidates":[{"content":{"parts":[{"text":"answer to %s"}]}}]}`, prompt.Contents[0].Parts[0].Text)
+	})
+
+	t.Run("Record and replay", func(t *testing.T) {
+		ts := httptest.NewServer(handler)
+		path := filepath.Join(t.TempDir(), "cassettes", "generate.json")
+
+		recording := newClient(t, ts.URL, "secret-api-key", &ReplayOptions{Mode: ReplayModeRecord, CassettePath: path})
+		for _, prompt := range []string{"one", "two"} {
+			if _, err := generate(recording, prompt); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got, err := stream(recording); err != nil || got != "Hello, world" {
+			t.Fatalf("stream() while recording = (%q, %v), want Hello, world", got, err)
+		}
+		ts.Close()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "secret-api-key") {
+			t.Errorf("cassette contains the API key:\n%s", data)
+		}
+
+		// The server is closed and the replaying client has no API key.
+		replaying := newClient(t, ts.URL, "", &ReplayOptions{Mode: ReplayModeReplay, CassettePath: path})
+		for _, prompt := range []string{"two", "one"} {
+			got, err := generate(replaying, prompt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := "answer to " + prompt; got != want {
+				t.Errorf("replayed GenerateContent(%q) = %q, want %q", prompt, got, want)
+			}
+		}
+		if got, err := stream(replaying); err != nil || got != "Hello, world" {
+			t.Errorf("replayed stream() = (%q, %v), want Hello, world", got, err)
+		}
+		if _, err := generate(replaying, "one"); err == nil || !strings.Contains(err.Error(), "no interaction recorded") {
+			t.Errorf("GenerateContent() of a request replayed already error = %v, want an unrecorded request error", err)
+		}
+	})
+
+	t.Run("File upload", func(t *testing.T) {
+		mockServer := NewMockUploadServer(t)
+		ts := httptest.NewServer(mockServer)
+		mockServer.baseURL = ts.URL
+		path := filepath.Join(t.TempDir(), "upload.json")
+		content := bytes.Repeat([]byte{0xff, 0x00, 0x7f}, 100)
+		config := &UploadFileConfig{MIMEType: "application/octet-stream", Name: "blob"}
+
+		recording := newClient(t, ts.URL, "test-api-key", &ReplayOptions{Mode: ReplayModeRecord, CassettePath: path})
+		want, err := recording.Files.Upload(ctx, bytes.NewReader(content), config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ts.Close()
+
+		replaying := newClient(t, ts.URL, "test-api-key", &ReplayOptions{Mode: ReplayModeReplay, CassettePath: path})
+		got, err := replaying.Files.Upload(ctx, bytes.NewReader(content), config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("replayed Upload() mismatch (-want +got):\n%s", diff)
+		}
+		if _, err := replaying.Files.Upload(ctx, bytes.NewReader([]byte("other content")), config); err == nil {
+			t.Error("replayed Upload() of other content succeeded, want an unrecorded request error")
+		}
+	})
+
+	t.Run("Auto", func(t *testing.T) {
+		ts := httptest.NewServer(handler)
+		path := filepath.Join(t.TempDir(), "auto.json")
+		opts := &ReplayOptions{Mode: ReplayModeAuto, CassettePath: path}
+		if _, err := generate(newClient(t, ts.URL, "test-api-key", opts), "auto"); err != nil {
+			t.Fatal(err)
+		}
+		ts.Close()
+		if got, err := generate(newClient(t, ts.URL, "test-api-key", opts), "auto"); err != nil || got != "answer to auto" {
+			t.Errorf("GenerateContent() with an existing cassette = (%q, %v), want it replayed", got, err)
+		}
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		for name, opts := range map[string]*ReplayOptions{
+			"no cassette path": {Mode: ReplayModeRecord},
+			"unknown mode":     {Mode: "rewind", CassettePath: "cassette.json"},
+			"missing cassette": {Mode: ReplayModeReplay, CassettePath: filepath.Join(t.TempDir(), "missing.json")},
+		} {
+			_, err := NewClient(ctx, &ClientConfig{APIKey: "test-api-key", Backend: BackendGeminiAPI, ReplayOptions: opts})
+			if err == nil {
+				t.Errorf("NewClient() with %s succeeded, want an error", name)
+			}
+		}
+	})
+}
+
+func TestReplayMatchKey(t *testing.T) {
+	multipart := func(boundary string) (http.Header, []byte) {
+		header := http.Header{"Content-Type": {"multipart/related; boundary=" + boundary}}
+		body := fmt.Sprintf("--%s\r\nContent-Type: application/json\r\n\r\n{}\r\n--%s--\r\n", boundary, boundary)
+		return header, []byte(body)
+	}
+	h1, b1 := multipart("aaaa")
+	h2, b2 := multipart("bbbb")
+	if matchKey("POST", "u", h1, b1) != matchKey("POST", "u", h2, b2) {
+		t.Error("matchKey() of multipart bodies differs by boundary, want equal")
+	}
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+	if matchKey("POST", "u", jsonHeader, []byte(`{"a":1,"b":2}`)) != matchKey("POST", "u", jsonHeader, []byte(`{"b": 2, "a": 1}`)) {
+		t.Error("matchKey() of JSON bodies differs by key order, want equal")
+	}
+	if matchKey("POST", "u", jsonHeader, []byte(`{"a":1}`)) == matchKey("POST", "u", jsonHeader, []byte(`{"a":2}`)) {
+		t.Error("matchKey() of different JSON bodies is equal, want different")
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RequestBuilder builds the arguments of Models.GenerateContent with chained
+// calls, e.g.
+//
+//	resp, err := genai.NewRequest("gemini-2.5-flash").
+//		System("Describe images in one sentence.").
+//		Text("What is in this image?").
+//		ImageFile("cat.png").
+//		Temperature(0.2).
+//		Do(ctx, client)
+//
+// The parts added with Text, Bytes, ImageFile and URI form the user turn of
+// the request, sent after the contents added with Contents. An error of a
+// call, e.g. a file that cannot be read, is returned by Build, Do and Stream.
+//
+// A RequestBuilder is not safe for concurrent use.
+type RequestBuilder struct {
+	model    string
+	contents []*Content
+	parts    []*Part
+	config   *GenerateContentConfig
+	err      error
+}
+
+// NewRequest returns a RequestBuilder of a request to model.
+func NewRequest(model string) *RequestBuilder {
+	return &RequestBuilder{model: model}
+}
+
+// Contents adds contents to the request, e.g. the history of a conversation.
+// The parts added before are sent in a user turn before contents.
+func (b *RequestBuilder) Contents(contents ...*Content) *RequestBuilder {
+	b.flush()
+	b.contents = append(b.contents, contents...)
+	return b
+}
+
+// Text adds a text part to the user turn.
+func (b *RequestBuilder) Text(text string) *RequestBuilder {
+	b.parts = append(b.parts, NewPartFromText(text))
+	return b
+}
+
+// Bytes adds a part with inline data of mimeType to the user turn.
+func (b *RequestBuilder) Bytes(data []byte, mimeType string) *RequestBuilder {
+	b.parts = append(b.parts, NewPartFromBytes(data, mimeType))
+	return b
+}
+
+// ImageFile adds the image at path to the user turn as inline data. Its MIME
+// type is detected from the extension of path, e.g. image/png for ".png".
+func (b *RequestBuilder) ImageFile(path string) *RequestBuilder {
+	mimeType, _, _ := strings.Cut(mime.TypeByExtension(filepath.Ext(path)), ";")
+	if !strings.HasPrefix(mimeType, "image/") {
+		b.setErr(fmt.Errorf("failed to detect the image type of %s", path))
+		return b
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		b.setErr(fmt.Errorf("failed to read image: %w", err))
+		return b
+	}
+	return b.Bytes(data, mimeType)
+}
+
+// URI adds a part that refers to the file at uri, e.g. a file uploaded with
+// the Files API or an object in Cloud Storage, to the user turn.
+func (b *RequestBuilder) URI(uri, mimeType string) *RequestBuilder {
+	b.parts = append(b.parts, NewPartFromURI(uri, mimeType))
+	return b
+}
+
+// Config sets the config of the request, on which the other config calls
+// apply. config is not modified.
+func (b *RequestBuilder) Config(config *GenerateContentConfig) *RequestBuilder {
+	b.config = config.Clone()
+	return b
+}
+
+// System sets the system instruction of the request.
+func (b *RequestBuilder) System(text string) *RequestBuilder {
+	b.configure().SystemInstruction = NewContentFromText(text, RoleUser)
+	return b
+}
+
+// Temperature sets the temperature of the request.
+func (b *RequestBuilder) Temperature(temperature float32) *RequestBuilder {
+	b.configure().Temperature = &temperature
+	return b
+}
+
+// TopP sets the nucleus sampling probability of the request.
+func (b *RequestBuilder) TopP(topP float32) *RequestBuilder {
+	b.configure().TopP = &topP
+	return b
+}
+
+// MaxOutputTokens sets the maximum number of tokens of the response.
+func (b *RequestBuilder) MaxOutputTokens(n int32) *RequestBuilder {
+	b.configure().MaxOutputTokens = n
+	return b
+}
+
+// Thinking sets the thinking config of the request, e.g. its thinking budget
+// or whether to include thoughts in the response.
+func (b *RequestBuilder) Thinking(config *ThinkingConfig) *RequestBuilder {
+	b.configure().ThinkingConfig = cloneOf(config)
+	return b
+}
+
+// Tools adds tools to the request.
+func (b *RequestBuilder) Tools(tools ...*Tool) *RequestBuilder {
+	config := b.configure()
+	config.Tools = append(config.Tools, tools...)
+	return b
+}
+
+// Build returns the model, contents and config of the request, or the first
+// error of the calls of b. The result does not change when b is used again.
+func (b *RequestBuilder) Build() (string, []*Content, *GenerateContentConfig, error) {
+	if b.err != nil {
+		return "", nil, nil, b.err
+	}
+	contents := append([]*Content(nil), b.contents...)
+	if len(b.parts) > 0 {
+		contents = append(contents, NewContentFromParts(append([]*Part(nil), b.parts...), RoleUser))
+	}
+	if len(contents) == 0 {
+		return "", nil, nil, errors.New("the request has no contents")
+	}
+	return b.model, contents, b.config.Clone(), nil
+}
+
+// Do sends the request with client.Models.GenerateContent.
+func (b *RequestBuilder) Do(ctx context.Context, client *Client) (*GenerateContentResponse, error) {
+	model, contents, config, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return client.Models.GenerateContent(ctx, model, contents, config)
+}
+
+// Stream sends the request with client.Models.GenerateContentStream.
+func (b *RequestBuilder) Stream(ctx context.Context, client *Client) iter.Seq2[*GenerateContentResponse, error] {
+	model, contents, config, err := b.Build()
+	if err != nil {
+		return yieldErrorAndEndIterator[GenerateContentResponse](err)
+	}
+	return client.Models.GenerateContentStream(ctx, model, contents, config)
+}
+
+// flush moves the parts of the user turn to the contents.
+func (b *RequestBuilder) flush() {
+	if len(b.parts) > 0 {
+		b.contents = append(b.contents, NewContentFromParts(b.parts, RoleUser))
+		b.parts = nil
+	}
+}
+
+func (b *RequestBuilder) configure() *GenerateContentConfig {
+	if b.config == nil {
+		b.config = &GenerateContentConfig{}
+	}
+	return b.config
+}
+
+func (b *RequestBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
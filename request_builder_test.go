@@ -0,0 +1,121 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRequestBuilder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cat.png")
+	if err := os.WriteFile(path, []byte{1, 2, 3}, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tool := &Tool{GoogleSearch: &GoogleSearch{}}
+	base := &GenerateContentConfig{CandidateCount: 1}
+	history := NewContentFromText("Hi", RoleModel)
+	b := NewRequest("gemini-2.5-flash").
+		Config(base).
+		Text("Say hello").
+		Contents(history).
+		System("Be brief.").
+		Text("What is in this image?").
+		ImageFile(path).
+		Temperature(0.2).
+		MaxOutputTokens(100).
+		Thinking(&ThinkingConfig{IncludeThoughts: true, ThinkingBudget: Ptr(ThinkingBudgetDynamic)}).
+		Tools(tool)
+	model, contents, config, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	wantContents := []*Content{
+		NewContentFromText("Say hello", RoleUser),
+		history,
+		NewContentFromParts([]*Part{NewPartFromText("What is in this image?"), NewPartFromBytes([]byte{1, 2, 3}, "image/png")}, RoleUser),
+	}
+	wantConfig := &GenerateContentConfig{
+		CandidateCount:    1,
+		SystemInstruction: NewContentFromText("Be brief.", RoleUser),
+		Temperature:       Float32(0.2),
+		MaxOutputTokens:   100,
+		ThinkingConfig:    &ThinkingConfig{IncludeThoughts: true, ThinkingBudget: Ptr[int32](-1)},
+		Tools:             []*Tool{tool},
+	}
+	if model != "gemini-2.5-flash" {
+		t.Errorf("Build() model = %q, want gemini-2.5-flash", model)
+	}
+	if diff := cmp.Diff(wantContents, contents); diff != "" {
+		t.Errorf("Build() contents mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantConfig, config); diff != "" {
+		t.Errorf("Build() config mismatch (-want +got):\n%s", diff)
+	}
+	if base.Temperature != nil {
+		t.Errorf("Build() modified the base config: %+v", base)
+	}
+
+	if _, _, _, err := NewRequest("m").Text("a").ImageFile(filepath.Join(t.TempDir(), "missing.jpg")).Build(); err == nil {
+		t.Error("Build() with a missing image succeeded, want an error")
+	}
+	if _, _, _, err := NewRequest("m").ImageFile(path + ".txt").Build(); err == nil || !strings.Contains(err.Error(), "image type") {
+		t.Errorf("Build() with a text file = %v, want an image type error", err)
+	}
+	if _, _, _, err := NewRequest("m").System("no contents").Build(); err == nil {
+		t.Error("Build() without contents succeeded, want an error")
+	}
+}
+
+func TestRequestBuilderDo(t *testing.T) {
+	ctx := context.Background()
+	var requests []map[string]any
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+		response := `{"candidates": [{"content": {"role": "model", "parts": [{"text": "hello"}]}}]}`
+		if strings.Contains(r.URL.Path, ":streamGenerateContent") {
+			response = "data: " + response + "\n\n"
+		}
+		fmt.Fprint(w, response)
+	})
+	b := NewRequest("gemini-2.5-flash").Text("Say hello").Temperature(0)
+	resp, err := b.Do(ctx, client)
+	if err != nil || resp.Text() != "hello" {
+		t.Fatalf("Do() = %v, %v, want hello", resp, err)
+	}
+	for resp, err := range b.Stream(ctx, client) {
+		if err != nil || resp.Text() != "hello" {
+			t.Fatalf("Stream() = %v, %v, want hello", resp, err)
+		}
+	}
+	if len(requests) != 2 || requests[1]["generationConfig"].(map[string]any)["temperature"] != 0.0 {
+		t.Errorf("requests = %v, want 2 requests with temperature 0", requests)
+	}
+	for _, err := range NewRequest("m").Stream(ctx, client) {
+		if err == nil {
+			t.Error("Stream() without contents succeeded, want an error")
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// RequestOptions are the options of the SDK for the API calls of a client
+// that have no counterpart in HTTPOptions. Set them for all the calls of a
+// client with ClientConfig.RequestOptions, and for the calls made with a
+// context with [WithRequestOptions].
+type RequestOptions struct {
+	// Optional. If true, the raw JSON body of each response is retained in
+	// [HTTPResponse.Body] and can be read with the Raw method of the response,
+	// e.g. [GenerateContentResponse.Raw]. For streaming calls, each chunk
+	// retains its own JSON payload.
+	IncludeRawResponse bool
+	// Optional. Controls what happens when the caller stops iterating a
+	// streaming response early. See [StreamOptions].
+	StreamOptions *StreamOptions
+	// Optional. The Google Cloud project of the request. If empty, defaults to
+	// ClientConfig.Project. Only supported by Vertex AI clients that use
+	// Google Cloud credentials.
+	Project string
+	// Optional. The Google Cloud location of the request, e.g. "europe-west4".
+	// If empty, defaults to ClientConfig.Location. Unless HTTPOptions.BaseURL
+	// is set, the request is sent to the endpoint of the location. Only
+	// supported by Vertex AI clients that use Google Cloud credentials.
+	Location string
+	// Optional. Labels attached to the Vertex AI requests that support labels,
+	// e.g. to break down the spend of a batch job per feature in billing
+	// exports. They take precedence over ClientConfig.Labels, and the labels
+	// set in the config of a request take precedence over them.
+	Labels map[string]string
+	// Optional. Called with every raw server-sent event of a streaming
+	// response before it is decoded, including events that cannot be decoded
+	// and are skipped. See [SSEEvent].
+	OnSSEEvent func(*SSEEvent)
+	// Optional. Retries the request if it fails with a retryable status code
+	// or a network error. Overrides ClientConfig.RetryOptions. See
+	// [RetryOptions].
+	RetryOptions *RetryOptions
+	// Optional. Compresses request bodies of at least 1 KiB, e.g. with
+	// CompressionGzip, which saves bandwidth for large inline media and long
+	// contexts. This includes the request that starts a file upload, but not
+	// the content of the file: the upload protocol stores the chunks as they
+	// are sent and counts their offsets in bytes of the file, so they are
+	// sent uncompressed. To save bandwidth on an upload, upload the file
+	// compressed, e.g. as a gzip file. Compressed responses, including those
+	// of uploads, are decompressed regardless of this option.
+	Compression Compression
+	// Optional. The maximum time until the response headers are received,
+	// after which the request fails with a *TimeoutError and is retried
+	// according to RetryOptions. Zero means no connect timeout.
+	ConnectTimeout *time.Duration
+	// Optional. The deadline of unary calls, including reading the response,
+	// in place of HTTPOptions.Timeout. Streaming calls ignore it; bound them
+	// with StreamIdleTimeout instead. Zero means no deadline.
+	RequestTimeout *time.Duration
+	// Optional. The maximum time between the events of a streaming response,
+	// reset on every event, after which the stream fails with a
+	// *TimeoutError. Stalled streams thus fail fast while long generations
+	// that make progress are not cut off. Zero means no idle timeout.
+	StreamIdleTimeout *time.Duration
+	// Optional. Uploads the large inline data parts of the contents of
+	// GenerateContent calls before they are sent. See [AutoUploadOptions].
+	AutoUpload *AutoUploadOptions
+}
+
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a copy of ctx with which API calls use options.
+// The set fields of options take precedence over those of
+// ClientConfig.RequestOptions and of any options already in ctx.
+func WithRequestOptions(ctx context.Context, options *RequestOptions) context.Context {
+	if options == nil {
+		return ctx
+	}
+	merged := mergeRequestOptions(requestOptionsFrom(ctx), *options)
+	return context.WithValue(ctx, requestOptionsKey{}, &merged)
+}
+
+// requestOptionsFrom returns the RequestOptions of ctx, which are empty if
+// WithRequestOptions was not used.
+func requestOptionsFrom(ctx context.Context) RequestOptions {
+	if options, ok := ctx.Value(requestOptionsKey{}).(*RequestOptions); ok {
+		return *options
+	}
+	return RequestOptions{}
+}
+
+// requestOptions returns the RequestOptions of a call made with ctx: those of
+// ctx on top of those of the client.
+func (ac *apiClient) requestOptions(ctx context.Context) RequestOptions {
+	return mergeRequestOptions(ac.clientConfig.RequestOptions, requestOptionsFrom(ctx))
+}
+
+// mergeRequestOptions returns the set fields of patch on top of options.
+func mergeRequestOptions(options, patch RequestOptions) RequestOptions {
+	if patch.IncludeRawResponse {
+		options.IncludeRawResponse = true
+	}
+	if patch.StreamOptions != nil {
+		options.StreamOptions = patch.StreamOptions
+	}
+	if patch.Project != "" {
+		options.Project = patch.Project
+	}
+	if patch.Location != "" {
+		options.Location = patch.Location
+	}
+	if patch.OnSSEEvent != nil {
+		options.OnSSEEvent = patch.OnSSEEvent
+	}
+	if patch.RetryOptions != nil {
+		options.RetryOptions = patch.RetryOptions
+	}
+	if patch.Compression != CompressionNone {
+		options.Compression = patch.Compression
+	}
+	if patch.ConnectTimeout != nil {
+		options.ConnectTimeout = patch.ConnectTimeout
+	}
+	if patch.RequestTimeout != nil {
+		options.RequestTimeout = patch.RequestTimeout
+	}
+	if patch.StreamIdleTimeout != nil {
+		options.StreamIdleTimeout = patch.StreamIdleTimeout
+	}
+	if patch.AutoUpload != nil {
+		options.AutoUpload = patch.AutoUpload
+	}
+	if patch.Labels != nil {
+		labels := maps.Clone(options.Labels)
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		maps.Copy(labels, patch.Labels)
+		options.Labels = labels
+	}
+	return options
+}
@@ -0,0 +1,256 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultResearchAgent is the agent used by Interactions.Research when
+	// ResearchConfig.Agent is empty.
+	DefaultResearchAgent = "deep-research-pro-preview-12-2025"
+	// defaultResearchPollInterval is the polling interval used when
+	// ResearchConfig.PollInterval is zero.
+	defaultResearchPollInterval = 10 * time.Second
+)
+
+// ResearchConfig configures Interactions.Research.
+type ResearchConfig struct {
+	// Optional. The research agent. Defaults to DefaultResearchAgent.
+	Agent string
+	// Optional. The agent configuration, sent as the agentConfig of the
+	// interaction.
+	AgentConfig any
+	// Optional. Tools available to the agent, in addition to web search.
+	Tools []*InteractionTool
+	// Optional. Adds the Google Search tool to Tools.
+	WebSearch bool
+	// Optional. How often the interaction is polled until it finishes.
+	// Defaults to 10 seconds.
+	PollInterval time.Duration
+	// Optional. Streams the progress of the agent instead of only polling its
+	// status. Streamed text is reported to OnProgress as it arrives.
+	Stream bool
+	// Optional. Called with the progress of the research: every streamed delta
+	// and every polled status.
+	OnProgress func(*ResearchProgress)
+	// Optional. Used for every call to the Interactions service.
+	HTTPOptions *HTTPOptions
+}
+
+// ResearchProgress is an update of a running research interaction.
+type ResearchProgress struct {
+	// Status is the last known status of the interaction.
+	Status InteractionStatus
+	// Text is the streamed text of a delta event, if any.
+	Text string
+	// Thought reports whether Text is a thought summary, i.e. an intermediate
+	// finding rather than a part of the report.
+	Thought bool
+	// Interaction is the polled interaction, or nil for streamed deltas.
+	Interaction *Interaction
+}
+
+// ResearchCitation is a source that supports a span of a research report.
+type ResearchCitation struct {
+	// Source is the cited source, usually a URL.
+	Source string
+	// Text is the cited span of the report.
+	Text string
+	// StartIndex is the byte offset of the span in the report.
+	StartIndex int
+	// EndIndex is the byte offset of the end of the span in the report.
+	EndIndex int
+}
+
+// ResearchReport is the result of Interactions.Research.
+type ResearchReport struct {
+	// Interaction is the finished interaction.
+	Interaction *Interaction
+	// Report is the text of the final report.
+	Report string
+	// Citations are the sources of the report, in order of appearance.
+	Citations []*ResearchCitation
+	// Findings are the thought summaries the agent produced while it worked.
+	Findings []string
+}
+
+// Research runs a deep research agent on query in the background and waits
+// for it to finish. It creates the interaction, polls it, or streams it when
+// config.Stream is set, reports progress to config.OnProgress and returns
+// the final report with its citations and the intermediate findings.
+//
+// If ctx is cancelled before the interaction finishes, the interaction is
+// cancelled too. An interaction that fails or is cancelled is returned as an
+// error.
+func (i *Interactions) Research(ctx context.Context, query string, config *ResearchConfig) (*ResearchReport, error) {
+	if config == nil {
+		config = &ResearchConfig{}
+	}
+	interaction := &Interaction{
+		Agent:       config.Agent,
+		AgentConfig: config.AgentConfig,
+		Input:       query,
+		Tools:       config.Tools,
+		Background:  true,
+	}
+	if interaction.Agent == "" {
+		interaction.Agent = DefaultResearchAgent
+	}
+	if config.WebSearch {
+		interaction.Tools = append(interaction.Tools[:len(interaction.Tools):len(interaction.Tools)], &InteractionTool{Type: "google_search"})
+	}
+	progress := func(p *ResearchProgress) {
+		if config.OnProgress != nil {
+			config.OnProgress(p)
+		}
+	}
+
+	var id string
+	if config.Stream {
+		var err error
+		id, err = i.streamResearch(ctx, interaction, config.HTTPOptions, progress)
+		if err != nil {
+			return nil, i.cancelResearch(ctx, id, err)
+		}
+	} else {
+		created, err := i.Create(ctx, interaction, &CreateInteractionConfig{HTTPOptions: config.HTTPOptions})
+		if err != nil {
+			return nil, err
+		}
+		id = created.ID
+		progress(&ResearchProgress{Status: created.Status, Interaction: created})
+	}
+	if id == "" {
+		return nil, fmt.Errorf("research interaction has no ID")
+	}
+
+	interval := config.PollInterval
+	if interval <= 0 {
+		interval = defaultResearchPollInterval
+	}
+	final, err := i.Wait(ctx, id, &WaitConfig{
+		InitialInterval: interval,
+		MaxInterval:     interval,
+		Multiplier:      1,
+		HTTPOptions:     config.HTTPOptions,
+		OnUpdate: func(got *Interaction) {
+			progress(&ResearchProgress{Status: got.Status, Interaction: got})
+		},
+	})
+	if err != nil {
+		return nil, i.cancelResearch(ctx, id, err)
+	}
+	switch final.Status {
+	case InteractionStatusFailed, InteractionStatusCancelled, InteractionStatusIncomplete:
+		return nil, fmt.Errorf("research interaction %s ended with status %q", id, final.Status)
+	}
+	return newResearchReport(final), nil
+}
+
+// streamResearch creates interaction as a stream and reports its deltas until
+// the stream ends. It returns the ID of the interaction.
+func (i *Interactions) streamResearch(ctx context.Context, interaction *Interaction, httpOptions *HTTPOptions, progress func(*ResearchProgress)) (string, error) {
+	var id string
+	var status InteractionStatus
+	for event, err := range i.CreateStream(ctx, interaction, &CreateInteractionConfig{HTTPOptions: httpOptions}) {
+		if err != nil {
+			return id, err
+		}
+		if event.Interaction != nil {
+			if event.Interaction.ID != "" {
+				id = event.Interaction.ID
+			}
+			if event.Interaction.Status != "" {
+				status = event.Interaction.Status
+			}
+		}
+		if d := event.Delta; d != nil {
+			switch {
+			case d.Text != "":
+				progress(&ResearchProgress{Status: status, Text: d.Text})
+			case len(d.Summary) > 0:
+				progress(&ResearchProgress{Status: status, Text: interactionText(d.Summary), Thought: true})
+			}
+		}
+	}
+	return id, nil
+}
+
+// cancelResearch cancels the interaction id if ctx is done and returns err.
+func (i *Interactions) cancelResearch(ctx context.Context, id string, err error) error {
+	if id == "" || ctx.Err() == nil {
+		return err
+	}
+	cancelCtx, cancel := context.WithTimeout(context.Background(), defaultStreamCancelTimeout)
+	defer cancel()
+	if _, cerr := i.Cancel(cancelCtx, id, nil); cerr != nil {
+		log.Printf("Warning: failed to cancel interaction %s: %v", id, cerr)
+	}
+	return err
+}
+
+func newResearchReport(interaction *Interaction) *ResearchReport {
+	report := &ResearchReport{Interaction: interaction}
+	var sb strings.Builder
+	for _, out := range interaction.Outputs {
+		if out == nil {
+			continue
+		}
+		switch out.Type {
+		case "text":
+			offset := sb.Len()
+			sb.WriteString(out.Text)
+			for _, a := range out.Annotations {
+				if a == nil || a.Source == "" {
+					continue
+				}
+				// An annotation out of range keeps its source but cites no text.
+				start, end := a.StartIndex, a.EndIndex
+				if start < 0 || end > len(out.Text) || start > end {
+					start, end = 0, 0
+				}
+				report.Citations = append(report.Citations, &ResearchCitation{
+					Source:     a.Source,
+					Text:       out.Text[start:end],
+					StartIndex: offset + start,
+					EndIndex:   offset + end,
+				})
+			}
+		case "thought":
+			if text := interactionText(out.Summary); text != "" {
+				report.Findings = append(report.Findings, text)
+			}
+		}
+	}
+	report.Report = sb.String()
+	return report
+}
+
+// interactionText concatenates the text of contents.
+func interactionText(contents []*InteractionContent) string {
+	var sb strings.Builder
+	for _, c := range contents {
+		if c != nil {
+			sb.WriteString(c.Text)
+		}
+	}
+	return sb.String()
+}
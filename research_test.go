@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func researchServer(t *testing.T, statuses []InteractionStatus, final *Interaction) (*Client, *map[string]any, *[]string) {
+	t.Helper()
+	var created map[string]any
+	var calls []string
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/v1beta/"))
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1beta/interactions":
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Errorf("invalid request body: %v", err)
+			}
+			if r.URL.Query().Get("alt") == "sse" {
+				w.Header().Set("Content-Type", "text/event-stream")
+				for _, event := range []InteractionEvent{
+					{EventType: "interaction.start", Interaction: &Interaction{ID: "research-1", Status: "in_progress"}},
+					{EventType: "content.delta", Delta: &InteractionContent{Type: "thought", Summary: []*InteractionContent{{Type: "text", Text: "Searching"}}}},
+					{EventType: "content.delta", Delta: &InteractionContent{Type: "text", Text: "Draft"}},
+				} {
+					data, _ := json.Marshal(event)
+					fmt.Fprintf(w, "data: %s\n\n", data)
+				}
+				return
+			}
+			json.NewEncoder(w).Encode(Interaction{ID: "research-1", Status: "in_progress"})
+		case r.Method == http.MethodGet && r.URL.Path == "/v1beta/interactions/research-1":
+			if len(statuses) > 0 {
+				json.NewEncoder(w).Encode(Interaction{ID: "research-1", Status: statuses[0]})
+				statuses = statuses[1:]
+				return
+			}
+			json.NewEncoder(w).Encode(final)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1beta/interactions/research-1/cancel":
+			json.NewEncoder(w).Encode(Interaction{ID: "research-1", Status: "cancelled"})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	})
+	return client, &created, &calls
+}
+
+func TestResearch(t *testing.T) {
+	ctx := context.Background()
+	final := &Interaction{
+		ID:     "research-1",
+		Status: "completed",
+		Outputs: []*InteractionContent{
+			{Type: "thought", Summary: []*InteractionContent{{Type: "text", Text: "Found two sources."}}},
+			{Type: "text", Text: "Go is fast. ", Annotations: []*InteractionAnnotation{{StartIndex: 0, EndIndex: 11, Source: "https://go.dev"}}},
+			{Type: "text", Text: "It is simple.", Annotations: []*InteractionAnnotation{{StartIndex: 6, EndIndex: 12, Source: "https://example.com"}, {StartIndex: 5, EndIndex: 99, Source: "https://bad.example.com"}}},
+		},
+	}
+
+	t.Run("Poll", func(t *testing.T) {
+		client, created, calls := researchServer(t, []InteractionStatus{"in_progress"}, final)
+		var statuses []InteractionStatus
+		report, err := client.Interactions.Research(ctx, "Why Go?", &ResearchConfig{
+			WebSearch:    true,
+			AgentConfig:  map[string]any{"type": "deep-research"},
+			PollInterval: time.Millisecond,
+			OnProgress:   func(p *ResearchProgress) { statuses = append(statuses, p.Status) },
+		})
+		if err != nil {
+			t.Fatalf("Research() failed: %v", err)
+		}
+		wantRequest := map[string]any{
+			"agent":       DefaultResearchAgent,
+			"agentConfig": map[string]any{"type": "deep-research"},
+			"input":       "Why Go?",
+			"tools":       []any{map[string]any{"type": "google_search"}},
+			"background":  true,
+		}
+		if diff := cmp.Diff(wantRequest, *created); diff != "" {
+			t.Errorf("request mismatch (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff([]InteractionStatus{"in_progress", "in_progress", "completed"}, statuses); diff != "" {
+			t.Errorf("progress statuses mismatch (-want +got):\n%s", diff)
+		}
+		if len(*calls) != 3 {
+			t.Errorf("calls = %q, want a create and two polls", *calls)
+		}
+		want := &ResearchReport{
+			Interaction: report.Interaction,
+			Report:      "Go is fast. It is simple.",
+			Citations: []*ResearchCitation{
+				{Source: "https://go.dev", Text: "Go is fast.", StartIndex: 0, EndIndex: 11},
+				{Source: "https://example.com", Text: "simple", StartIndex: 18, EndIndex: 24},
+				{Source: "https://bad.example.com", StartIndex: 12, EndIndex: 12},
+			},
+			Findings: []string{"Found two sources."},
+		}
+		if diff := cmp.Diff(want, report); diff != "" {
+			t.Errorf("Research() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		client, created, _ := researchServer(t, nil, final)
+		var progress []ResearchProgress
+		report, err := client.Interactions.Research(ctx, "Why Go?", &ResearchConfig{
+			Stream:       true,
+			PollInterval: time.Millisecond,
+			OnProgress: func(p *ResearchProgress) {
+				progress = append(progress, ResearchProgress{Status: p.Status, Text: p.Text, Thought: p.Thought})
+			},
+		})
+		if err != nil {
+			t.Fatalf("Research() failed: %v", err)
+		}
+		if (*created)["stream"] != true || (*created)["background"] != true {
+			t.Errorf("request = %v, want a background stream", *created)
+		}
+		want := []ResearchProgress{
+			{Status: "in_progress", Text: "Searching", Thought: true},
+			{Status: "in_progress", Text: "Draft"},
+			{Status: "completed"},
+		}
+		if diff := cmp.Diff(want, progress); diff != "" {
+			t.Errorf("progress mismatch (-want +got):\n%s", diff)
+		}
+		if report.Report != "Go is fast. It is simple." {
+			t.Errorf("Report = %q", report.Report)
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		client, _, _ := researchServer(t, []InteractionStatus{"failed"}, final)
+		_, err := client.Interactions.Research(ctx, "Why Go?", &ResearchConfig{PollInterval: time.Millisecond})
+		if err == nil || !strings.Contains(err.Error(), `status "failed"`) {
+			t.Errorf("Research() error = %v, want a failed status", err)
+		}
+	})
+
+	t.Run("CancelledContext", func(t *testing.T) {
+		client, _, calls := researchServer(t, []InteractionStatus{"in_progress", "in_progress", "in_progress"}, final)
+		ctx, cancel := context.WithCancel(ctx)
+		_, err := client.Interactions.Research(ctx, "Why Go?", &ResearchConfig{
+			PollInterval: time.Hour,
+			OnProgress: func(p *ResearchProgress) {
+				if p.Interaction != nil && p.Status == "in_progress" && len(*calls) > 1 {
+					cancel()
+				}
+			},
+		})
+		if err != context.Canceled {
+			t.Errorf("Research() error = %v, want context.Canceled", err)
+		}
+		if last := (*calls)[len(*calls)-1]; last != "POST interactions/research-1/cancel" {
+			t.Errorf("last call = %q, want the interaction to be cancelled", last)
+		}
+	})
+}
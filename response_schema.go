@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ResponseSchemaFor returns the Schema of the JSON encoding of T as returned by
+// SchemaOf, to be used as the ResponseSchema of a GenerateContentConfig
+// together with the "application/json" ResponseMIMEType.
+//
+//	schema, err := genai.ResponseSchemaFor[Recipe]()
+//	config := &genai.GenerateContentConfig{
+//		ResponseMIMEType: "application/json",
+//		ResponseSchema:   schema,
+//	}
+func ResponseSchemaFor[T any]() (*Schema, error) {
+	return SchemaOf[T]()
+}
+
+// Into unmarshals the text of the first candidate of the response into v,
+// which must be a non-nil pointer. Like Interaction.OutputInto, the text,
+// optionally wrapped in a Markdown code block, is first validated against the
+// type of v, so that a missing required field or a value of the wrong type is
+// reported as a *ValidationError rather than decoded into a zero value.
+func (r *GenerateContentResponse) Into(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("Into requires a non-nil pointer, got %T", v)
+	}
+	text := ""
+	if r != nil {
+		text = r.Text()
+	}
+	if text == "" {
+		return errors.New("the response has no text")
+	}
+	return unmarshalValidated(text, v)
+}
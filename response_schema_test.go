@@ -0,0 +1,119 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testReview struct {
+	Summary   string    `json:"summary"`
+	Rating    int       `json:"rating"`
+	Sentiment string    `json:"sentiment" jsonschema:"enum=positive|neutral|negative"`
+	Topics    []string  `json:"topics,omitempty" jsonschema:"enum=price|quality|service"`
+	Author    *string   `json:"author"`
+	Posted    time.Time `json:"posted,omitzero"`
+	Replies   []struct {
+		Text string `json:"text"`
+	} `json:"replies"`
+	internal string
+}
+
+func TestResponseSchemaFor(t *testing.T) {
+	got, err := ResponseSchemaFor[testReview]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Schema{
+		Type: TypeObject,
+		Properties: map[string]*Schema{
+			"summary":   {Type: TypeString},
+			"rating":    {Type: TypeInteger},
+			"sentiment": {Type: TypeString, Enum: []string{"positive", "neutral", "negative"}},
+			"topics":    {Type: TypeArray, Items: &Schema{Type: TypeString, Enum: []string{"price", "quality", "service"}}},
+			"author":    {Type: TypeString, Nullable: Ptr(true)},
+			"posted":    {Type: TypeString, Format: "date-time"},
+			"replies": {Type: TypeArray, Items: &Schema{
+				Type:             TypeObject,
+				Properties:       map[string]*Schema{"text": {Type: TypeString}},
+				Required:         []string{"text"},
+				PropertyOrdering: []string{"text"},
+			}},
+		},
+		Required:         []string{"summary", "rating", "sentiment", "replies"},
+		PropertyOrdering: []string{"summary", "rating", "sentiment", "topics", "author", "posted", "replies"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ResponseSchemaFor() mismatch (-want +got):\n%s", diff)
+	}
+
+	type node struct {
+		Children []node `json:"children"`
+	}
+	if _, err := ResponseSchemaFor[node](); err == nil {
+		t.Errorf("ResponseSchemaFor() of a recursive type succeeded, want error")
+	}
+	if _, err := ResponseSchemaFor[map[string]string](); err == nil {
+		t.Errorf("ResponseSchemaFor() of a map succeeded, want error")
+	}
+	type badEnum struct {
+		Level int `json:"level" jsonschema:"enum=1|2"`
+	}
+	if _, err := ResponseSchemaFor[badEnum](); err == nil {
+		t.Errorf("ResponseSchemaFor() with an enum of integers succeeded, want error")
+	}
+}
+
+func TestGenerateContentResponseInto(t *testing.T) {
+	resp := &GenerateContentResponse{Candidates: []*Candidate{{Content: &Content{Parts: []*Part{
+		{Text: "Reading the review.", Thought: true},
+		{Text: "```json\n{\"summary\": \"Great\", \"rating\": 5, \"sentiment\": \"positive\", \"replies\": []}\n```"},
+	}}}}}
+	var got testReview
+	if err := resp.Into(&got); err != nil {
+		t.Fatalf("Into() failed: %v", err)
+	}
+	want := testReview{Summary: "Great", Rating: 5, Sentiment: "positive", Replies: []struct {
+		Text string `json:"text"`
+	}{}}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(testReview{})); diff != "" {
+		t.Errorf("Into() mismatch (-want +got):\n%s", diff)
+	}
+
+	invalid := &GenerateContentResponse{Candidates: []*Candidate{{Content: &Content{Parts: []*Part{{Text: `{"summary": "Bad", "rating": "1"}`}}}}}}
+	var verr *ValidationError
+	if err := invalid.Into(&got); !errors.As(err, &verr) {
+		t.Fatalf("Into() = %v, want a *ValidationError", err)
+	}
+	wantViolations := []FieldViolation{
+		{Field: "sentiment", Description: "is required"},
+		{Field: "replies", Description: "is required"},
+		{Field: "rating", Description: "must be an integer, got a string"},
+	}
+	if diff := cmp.Diff(wantViolations, verr.Violations); diff != "" {
+		t.Errorf("violations mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := (&GenerateContentResponse{}).Into(&got); err == nil {
+		t.Errorf("Into() of a response without text succeeded, want error")
+	}
+	if err := resp.Into(got); err == nil {
+		t.Errorf("Into() with a non-pointer succeeded, want error")
+	}
+}
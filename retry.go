@@ -0,0 +1,198 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts     = 5
+	defaultRetryInitialInterval = time.Second
+	defaultRetryMaxInterval     = time.Minute
+	defaultRetryMultiplier      = 2.0
+	defaultRetryJitter          = 0.5
+	retryDrainLimit             = 64 << 10
+)
+
+// defaultRetryStatusCodes are the HTTP status codes retried by default:
+// request timeouts, rate limits and server errors.
+var defaultRetryStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryOptions configures how API calls that fail with a retryable status
+// code or a network error are retried with exponential backoff. Streaming
+// calls are retried until the stream is established; errors while reading the
+// stream are returned to the caller.
+type RetryOptions struct {
+	// Optional. The maximum number of attempts, including the first one.
+	// Defaults to 5. Set it to 1 to disable retries.
+	MaxAttempts int
+	// Optional. The interval before the first retry. Defaults to 1s.
+	InitialInterval time.Duration
+	// Optional. The maximum interval between attempts. Defaults to 1m.
+	MaxInterval time.Duration
+	// Optional. The factor the interval is multiplied by after each retry.
+	// Defaults to 2.
+	Multiplier float64
+	// Optional. The fraction of the interval that is randomized, between 0
+	// and 1, so that clients do not retry in lockstep. With 0.5, an interval
+	// of 2s becomes a random duration between 1s and 3s. Defaults to 0.5.
+	Jitter *float64
+	// Optional. The HTTP status codes that are retried. Defaults to 408, 429,
+	// 500, 502, 503 and 504.
+	HTTPStatusCodes []int
+	// Optional. If true, the Retry-After header of a response is ignored.
+	// Otherwise the retry waits as long as the header requests, if it is
+	// longer than the backoff interval.
+	IgnoreRetryAfter bool
+}
+
+// retryOptions returns the retry options of a call made with ctx, falling
+// back to the client's options, or nil if calls are not retried.
+func (ac *apiClient) retryOptions(ctx context.Context) *RetryOptions {
+	if o := ac.requestOptions(ctx).RetryOptions; o != nil {
+		return o
+	}
+	return ac.clientConfig.RetryOptions
+}
+
+func (r *RetryOptions) maxAttempts() int {
+	if r == nil {
+		return 1
+	}
+	if r.MaxAttempts <= 0 {
+		return defaultRetryMaxAttempts
+	}
+	return r.MaxAttempts
+}
+
+// shouldRetry reports whether an attempt that returned resp and err is retried.
+func (r *RetryOptions) shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		var timeoutErr *TimeoutError
+		if errors.As(err, &timeoutErr) {
+			return timeoutErr.Option == "ConnectTimeout"
+		}
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	codes := r.HTTPStatusCodes
+	if len(codes) == 0 {
+		codes = defaultRetryStatusCodes
+	}
+	return slices.Contains(codes, resp.StatusCode)
+}
+
+// delay returns the interval before the attempt that follows attempt, the
+// first attempt being 1.
+func (r *RetryOptions) delay(attempt int, resp *http.Response) time.Duration {
+	d := r.backoff(attempt)
+	if resp != nil && !r.IgnoreRetryAfter {
+		if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok && after > d {
+			d = after
+		}
+	}
+	return d
+}
+
+// errorDelay is like delay for an operation that failed with err, such as an
+// upload made of several requests. The Retry-After of an APIError is honored.
+func (r *RetryOptions) errorDelay(attempt int, err error) time.Duration {
+	d := r.backoff(attempt)
+	var apiErr APIError
+	if !r.IgnoreRetryAfter && errors.As(err, &apiErr) && apiErr.RetryAfter > d {
+		d = apiErr.RetryAfter
+	}
+	return d
+}
+
+// backoff returns the jittered exponential backoff interval after attempt.
+func (r *RetryOptions) backoff(attempt int) time.Duration {
+	initial, maxInterval, multiplier, jitter := r.InitialInterval, r.MaxInterval, r.Multiplier, defaultRetryJitter
+	if initial <= 0 {
+		initial = defaultRetryInitialInterval
+	}
+	if maxInterval <= 0 {
+		maxInterval = defaultRetryMaxInterval
+	}
+	if multiplier < 1 {
+		multiplier = defaultRetryMultiplier
+	}
+	if r.Jitter != nil {
+		jitter = min(max(*r.Jitter, 0), 1)
+	}
+	interval := min(float64(initial)*math.Pow(multiplier, float64(attempt-1)), float64(maxInterval))
+	interval *= 1 + jitter*(2*rand.Float64()-1)
+	return time.Duration(interval)
+}
+
+// uploadRetryOptions returns the retry options of an upload: those of the
+// call or the client, or three attempts 1s apart by default, with the
+// MaxAttempts and RetryDelay of the upload config taking precedence.
+func (ac *apiClient) uploadRetryOptions(ctx context.Context, maxAttempts int, retryDelay time.Duration) *RetryOptions {
+	r := RetryOptions{MaxAttempts: defaultUploadMaxAttempts, InitialInterval: defaultUploadRetryDelay}
+	if o := ac.retryOptions(ctx); o != nil {
+		r = *o
+	}
+	if maxAttempts > 0 {
+		r.MaxAttempts = maxAttempts
+	}
+	if retryDelay > 0 {
+		r.InitialInterval = retryDelay
+	}
+	return &r
+}
+
+// retryAfter parses the value of a Retry-After header, either a number of
+// seconds or an HTTP date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// discardResponse drains and closes the body of a response that is retried,
+// so that its connection can be reused.
+func discardResponse(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	_, _ = io.CopyN(io.Discard, resp.Body, retryDrainLimit)
+	resp.Body.Close()
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryOptions(t *testing.T) {
+	ctx := context.Background()
+	noJitter := 0.0
+	tests := []struct {
+		name         string
+		clientRetry  *RetryOptions
+		callRetry    *RetryOptions
+		failures     int
+		failStatus   int
+		stream       bool
+		wantAttempts int
+		wantErr      bool
+	}{
+		{name: "NoRetryByDefault", failures: 1, failStatus: http.StatusServiceUnavailable, wantAttempts: 1, wantErr: true},
+		{name: "RetriedUntilSuccess", clientRetry: &RetryOptions{InitialInterval: time.Millisecond, Jitter: &noJitter}, failures: 2, failStatus: http.StatusTooManyRequests, wantAttempts: 3},
+		{name: "MaxAttempts", clientRetry: &RetryOptions{MaxAttempts: 2, InitialInterval: time.Millisecond}, failures: 5, failStatus: http.StatusInternalServerError, wantAttempts: 2, wantErr: true},
+		{name: "NotRetryable", clientRetry: &RetryOptions{InitialInterval: time.Millisecond}, failures: 1, failStatus: http.StatusBadRequest, wantAttempts: 1, wantErr: true},
+		{name: "CustomStatusCodes", clientRetry: &RetryOptions{InitialInterval: time.Millisecond, HTTPStatusCodes: []int{http.StatusConflict}}, failures: 1, failStatus: http.StatusConflict, wantAttempts: 2},
+		{name: "CallOverride", clientRetry: &RetryOptions{InitialInterval: time.Millisecond}, callRetry: &RetryOptions{MaxAttempts: 1}, failures: 1, failStatus: http.StatusServiceUnavailable, wantAttempts: 1, wantErr: true},
+		{name: "StreamEstablishment", clientRetry: &RetryOptions{InitialInterval: time.Millisecond}, failures: 2, failStatus: http.StatusServiceUnavailable, stream: true, wantAttempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			client := newTestClient(t, &ClientConfig{RetryOptions: tt.clientRetry}, func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				if attempts <= tt.failures {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(tt.failStatus)
+					fmt.Fprintf(w, `{"error":{"code":%d,"message":"try again"}}`, tt.failStatus)
+					return
+				}
+				if tt.stream {
+					w.Header().Set("Content-Type", "text/event-stream")
+					fmt.Fprint(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"ok\"}]}}]}\n\n")
+					return
+				}
+				fmt.Fprint(w, `{"candidates":[{"content":{"parts":[{"text":"ok"}]}}]}`)
+			})
+			callCtx := WithRequestOptions(ctx, &RequestOptions{RetryOptions: tt.callRetry})
+			var gotErr error
+			if tt.stream {
+				for _, err := range client.Models.GenerateContentStream(callCtx, "gemini-2.5-flash", Text("Hi"), nil) {
+					if err != nil {
+						gotErr = err
+					}
+				}
+			} else {
+				_, gotErr = client.Models.GenerateContent(callCtx, "gemini-2.5-flash", Text("Hi"), nil)
+			}
+			if (gotErr != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", gotErr, tt.wantErr)
+			}
+			var apiErr APIError
+			if tt.wantErr && !errors.As(gotErr, &apiErr) {
+				t.Errorf("err = %v, want an APIError", gotErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	noJitter := 0.0
+	r := &RetryOptions{InitialInterval: time.Second, MaxInterval: 5 * time.Second, Jitter: &noJitter}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second} {
+		if got := r.delay(attempt, nil); got != want {
+			t.Errorf("delay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"30"}}}
+	if got := r.delay(1, resp); got != 30*time.Second {
+		t.Errorf("delay with Retry-After = %v, want 30s", got)
+	}
+	r.IgnoreRetryAfter = true
+	if got := r.delay(1, resp); got != time.Second {
+		t.Errorf("delay ignoring Retry-After = %v, want 1s", got)
+	}
+
+	r.IgnoreRetryAfter = false
+	wrapped := fmt.Errorf("failed to upload: %w", APIError{Code: http.StatusTooManyRequests, RetryAfter: 20 * time.Second})
+	if got := r.errorDelay(1, wrapped); got != 20*time.Second {
+		t.Errorf("errorDelay with RetryAfter = %v, want 20s", got)
+	}
+	if got := r.errorDelay(2, errors.New("connection reset")); got != 2*time.Second {
+		t.Errorf("errorDelay(2) = %v, want 2s", got)
+	}
+
+	upload := (&apiClient{clientConfig: &ClientConfig{RetryOptions: &RetryOptions{MaxAttempts: 7, Multiplier: 3}}}).uploadRetryOptions(context.Background(), 0, time.Millisecond)
+	if upload.MaxAttempts != 7 || upload.Multiplier != 3 || upload.InitialInterval != time.Millisecond {
+		t.Errorf("uploadRetryOptions() = %+v, want the client options with a 1ms initial interval", upload)
+	}
+
+	jittered := &RetryOptions{InitialInterval: time.Second}
+	for range 100 {
+		if got := jittered.delay(1, nil); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("jittered delay = %v, want between 500ms and 1.5s", got)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"7", 7 * time.Second, true},
+		{now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
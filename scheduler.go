@@ -0,0 +1,467 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Schedule computes when a scheduled job runs.
+type Schedule interface {
+	// Next returns the first run time after t, or the zero time if the job
+	// never runs again.
+	Next(t time.Time) time.Time
+}
+
+// Every returns a Schedule that runs every d, starting d after the scheduler
+// starts.
+func Every(d time.Duration) Schedule {
+	return everySchedule(d)
+}
+
+type everySchedule time.Duration
+
+func (s everySchedule) Next(t time.Time) time.Time {
+	if s <= 0 {
+		return time.Time{}
+	}
+	return t.Add(time.Duration(s))
+}
+
+// cronSchedule is a parsed cron expression. Each field is a bit set of the
+// values that match.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny report whether the day fields are "*". If both day
+	// fields are restricted, a day matches if either of them matches.
+	domAny, dowAny bool
+}
+
+// ParseSchedule parses a cron expression with the five standard fields
+// "minute hour day-of-month month day-of-week". Fields accept "*", values,
+// ranges "a-b", lists "a,b" and steps "*/n" or "a-b/n". Day-of-week is 0-6,
+// with 0 or 7 for Sunday. The descriptors "@hourly", "@daily", "@weekly",
+// "@monthly", "@yearly" and "@every <duration>" are also accepted.
+//
+// Times are evaluated in the location of the time passed to Next.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := strings.CutPrefix(spec, "@every "); ok {
+		duration, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid schedule %q: the interval must be a positive duration", spec)
+		}
+		return Every(duration), nil
+	}
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	case "@yearly", "@annually":
+		spec = "0 0 1 1 *"
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: want 5 fields, got %d", spec, len(fields))
+	}
+	s := &cronSchedule{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	for i, f := range []struct {
+		bits     *uint64
+		min, max int
+	}{{&s.minute, 0, 59}, {&s.hour, 0, 23}, {&s.dom, 1, 31}, {&s.month, 1, 12}, {&s.dow, 0, 7}} {
+		bits, err := parseCronField(fields[i], f.min, f.max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		*f.bits = bits
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			step = n
+		}
+		lo, hi := min, max
+		if rangePart != "*" {
+			first, last, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(first); err != nil {
+				return 0, fmt.Errorf("invalid value in %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(last); err != nil {
+					return 0, fmt.Errorf("invalid range in %q", part)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Every cron expression that matches at all matches within a few years,
+	// e.g. February 29 on a Monday.
+	limit := t.AddDate(30, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<int(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<t.Hour()) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<t.Minute()) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	if !s.domAny && !s.dowAny {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// ScheduledJob is an interaction that a Scheduler runs on a schedule.
+type ScheduledJob struct {
+	// Required. The unique name of the job.
+	Name string
+	// Required. When the job runs.
+	Schedule Schedule
+	// Required. The interaction to create on each run. It is copied for every
+	// run, so the same value can be reused.
+	Interaction *Interaction
+	// Optional. A text/template for the input of the interaction, executed
+	// with a ScheduledPromptData. If set, it replaces Interaction.Input.
+	Prompt string
+	// Optional. Continues the conversation of the previous run of the job by
+	// setting PreviousInteractionID.
+	Chain bool
+	// Optional. The config used to create the interaction.
+	Config *CreateInteractionConfig
+
+	prompt *template.Template
+}
+
+// ScheduledPromptData is the data of the prompt template of a ScheduledJob.
+type ScheduledPromptData struct {
+	// Job is the name of the job.
+	Job string
+	// Time is the scheduled time of the run.
+	Time time.Time
+	// Run is the number of the run, starting at 1.
+	Run int
+	// Previous is the output text of the previous successful run, if any.
+	Previous string
+}
+
+// ScheduledRun is the result of a run of a ScheduledJob.
+type ScheduledRun struct {
+	// Job is the name of the job.
+	Job string `json:"job"`
+	// Time is the scheduled time of the run.
+	Time time.Time `json:"time"`
+	// Run is the number of the run, starting at 1.
+	Run int `json:"run"`
+	// Interaction is the created interaction, or nil if the run failed.
+	Interaction *Interaction `json:"interaction,omitempty"`
+	// Text is the output text of the interaction.
+	Text string `json:"text,omitempty"`
+	// Err is the error of the run, if any.
+	Err error `json:"-"`
+}
+
+// SchedulerConfig configures a Scheduler.
+type SchedulerConfig struct {
+	// Optional. Persists the output of every successful run as a MemoryRecord
+	// of kind MemoryKindNote with role "model" and the ID "<job>/<time>", so
+	// that it can be listed later or recalled through a Memory that uses the
+	// same store.
+	Store MemoryStore
+	// Optional. Called after every run, including failed ones, once the result
+	// has been stored and the webhook notified.
+	OnComplete func(ctx context.Context, run *ScheduledRun)
+	// Optional. If set, every run is POSTed to this URL as a JSON document with
+	// the fields of ScheduledRun and an "error" field for failed runs.
+	WebhookURL string
+	// Optional. If set, webhook requests are signed with this secret like
+	// interaction notifications, so that the receiver can verify them with
+	// VerifyInteractionNotification.
+	WebhookSecret string
+	// Optional. The HTTP client used for the webhook. Defaults to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// Scheduler runs interactions on a schedule, e.g. for agents that generate
+// periodic reports. Runs of the same job never overlap: a run that is still
+// in progress when the next one is due delays it.
+type Scheduler struct {
+	interactions *Interactions
+	config       SchedulerConfig
+
+	mu   sync.Mutex
+	jobs []*scheduledJobState
+}
+
+type scheduledJobState struct {
+	job *ScheduledJob
+
+	mu       sync.Mutex
+	runs     int
+	previous *ScheduledRun
+}
+
+// NewScheduler returns a Scheduler that creates interactions with client.
+func NewScheduler(client *Client, config *SchedulerConfig) *Scheduler {
+	s := &Scheduler{interactions: client.Interactions}
+	if config != nil {
+		s.config = *config
+	}
+	return s
+}
+
+// Add adds a job to the scheduler. Jobs must be added before Run is called.
+func (s *Scheduler) Add(job *ScheduledJob) error {
+	switch {
+	case job == nil || job.Name == "":
+		return fmt.Errorf("scheduled job must have a name")
+	case job.Schedule == nil:
+		return fmt.Errorf("scheduled job %q has no schedule", job.Name)
+	case job.Interaction == nil:
+		return fmt.Errorf("scheduled job %q has no interaction", job.Name)
+	}
+	j := *job
+	if j.Prompt != "" {
+		tmpl, err := template.New(j.Name).Option("missingkey=error").Parse(j.Prompt)
+		if err != nil {
+			return fmt.Errorf("invalid prompt of scheduled job %q: %w", j.Name, err)
+		}
+		j.prompt = tmpl
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.jobs {
+		if existing.job.Name == j.Name {
+			return fmt.Errorf("duplicate scheduled job %q", j.Name)
+		}
+	}
+	s.jobs = append(s.jobs, &scheduledJobState{job: &j})
+	return nil
+}
+
+// Run runs the jobs on their schedules until ctx is done, then waits for the
+// runs in progress to stop and returns ctx.Err().
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	jobs := s.jobs
+	s.mu.Unlock()
+	if len(jobs) == 0 {
+		return fmt.Errorf("scheduler has no jobs")
+	}
+	var wg sync.WaitGroup
+	for _, state := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			next := state.job.Schedule.Next(time.Now())
+			for !next.IsZero() {
+				timer := time.NewTimer(time.Until(next))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+				s.run(ctx, state, next)
+				now := time.Now()
+				if next = state.job.Schedule.Next(next); !next.IsZero() && next.Before(now) {
+					// Skip the runs that were missed while this one was running.
+					next = state.job.Schedule.Next(now)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// RunNow runs the job with the given name once, immediately, and returns its
+// result. The run is persisted and reported like a scheduled run.
+func (s *Scheduler) RunNow(ctx context.Context, name string) (*ScheduledRun, error) {
+	s.mu.Lock()
+	var state *scheduledJobState
+	for _, js := range s.jobs {
+		if js.job.Name == name {
+			state = js
+		}
+	}
+	s.mu.Unlock()
+	if state == nil {
+		return nil, fmt.Errorf("unknown scheduled job %q", name)
+	}
+	run := s.run(ctx, state, time.Now())
+	return run, run.Err
+}
+
+func (s *Scheduler) run(ctx context.Context, state *scheduledJobState, at time.Time) *ScheduledRun {
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.runs++
+	run := &ScheduledRun{Job: state.job.Name, Time: at, Run: state.runs}
+	run.Interaction, run.Err = s.create(ctx, state, run)
+	if run.Err == nil {
+		run.Text = interactionOutputText(run.Interaction)
+		state.previous = run
+		if s.config.Store != nil {
+			record := &MemoryRecord{
+				ID:         fmt.Sprintf("%s/%s", run.Job, at.UTC().Format(time.RFC3339Nano)),
+				Kind:       MemoryKindNote,
+				Role:       string(RoleModel),
+				Text:       run.Text,
+				CreateTime: at,
+			}
+			if err := s.config.Store.Put(ctx, record); err != nil {
+				run.Err = fmt.Errorf("failed to store the result of scheduled job %q: %w", run.Job, err)
+			}
+		}
+	}
+	if s.config.WebhookURL != "" {
+		if err := s.notify(ctx, run); err != nil {
+			log.Printf("Warning: failed to notify the webhook of scheduled job %q: %v", run.Job, err)
+		}
+	}
+	if s.config.OnComplete != nil {
+		s.config.OnComplete(ctx, run)
+	}
+	return run
+}
+
+func (s *Scheduler) create(ctx context.Context, state *scheduledJobState, run *ScheduledRun) (*Interaction, error) {
+	interaction := *state.job.Interaction
+	if state.job.prompt != nil {
+		data := &ScheduledPromptData{Job: run.Job, Time: run.Time, Run: run.Run}
+		if state.previous != nil {
+			data.Previous = state.previous.Text
+		}
+		var sb strings.Builder
+		if err := state.job.prompt.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("failed to render the prompt of scheduled job %q: %w", run.Job, err)
+		}
+		interaction.Input = sb.String()
+	}
+	if state.job.Chain && state.previous != nil {
+		interaction.PreviousInteractionID = state.previous.Interaction.ID
+	}
+	return s.interactions.Create(ctx, &interaction, state.job.Config)
+}
+
+func (s *Scheduler) notify(ctx context.Context, run *ScheduledRun) error {
+	payload := struct {
+		*ScheduledRun
+		Error string `json:"error,omitempty"`
+	}{ScheduledRun: run}
+	if run.Err != nil {
+		payload.Error = run.Err.Error()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.config.WebhookSecret != "" {
+		id := fmt.Sprintf("%s/%s", run.Job, run.Time.UTC().Format(time.RFC3339Nano))
+		if err := signWebhook(req.Header, s.config.WebhookSecret, id, time.Now(), body); err != nil {
+			return err
+		}
+	}
+	httpClient := s.config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return errors.New(resp.Status)
+	}
+	return nil
+}
+
+// interactionOutputText concatenates the text outputs of interaction.
+func interactionOutputText(interaction *Interaction) string {
+	if interaction == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, out := range interaction.Outputs {
+		if out != nil && out.Type == "text" {
+			sb.WriteString(out.Text)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	start := time.Date(2025, time.March, 14, 10, 30, 15, 0, time.UTC) // A Friday.
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.March, 14, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.March, 14, 10, 45, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2025, time.March, 15, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2025, time.March, 17, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"30 8 1,15 * *", time.Date(2025, time.March, 15, 8, 30, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2025, time.March, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2025, time.March, 14, 12, 0, 15, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("ParseSchedule(%q) failed: %v", tt.spec, err)
+			continue
+		}
+		if got := s.Next(start); !got.Equal(tt.want) {
+			t.Errorf("ParseSchedule(%q).Next() = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	// Truncating to the hour works in UTC, so zones with a half-hour offset
+	// must still land on the local hour.
+	ist := time.FixedZone("IST", 5*3600+1800)
+	s, err := ParseSchedule("0 11 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2025, time.March, 14, 11, 0, 0, 0, ist)
+	if got := s.Next(time.Date(2025, time.March, 14, 10, 45, 0, 0, ist)); !got.Equal(want) {
+		t.Errorf("ParseSchedule(\"0 11 * * *\").Next() in IST = %v, want %v", got, want)
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@every -1m"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var requests []map[string]any
+	var webhooks []map[string]any
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Path == "/webhook" {
+			if r.Header.Get(WebhookSignatureHeader) == "" {
+				t.Errorf("webhook request without a %s header", WebhookSignatureHeader)
+			}
+			webhooks = append(webhooks, body)
+			return
+		}
+		requests = append(requests, body)
+		n := len(requests)
+		json.NewEncoder(w).Encode(Interaction{
+			ID:      fmt.Sprintf("run-%d", n),
+			Status:  "completed",
+			Outputs: []*InteractionContent{{Type: "text", Text: fmt.Sprintf("report %d", n)}},
+		})
+	})
+
+	store := NewInMemoryStore()
+	runs := make(chan *ScheduledRun, 10)
+	scheduler := NewScheduler(client, &SchedulerConfig{
+		Store:         store,
+		WebhookURL:    client.clientConfig.HTTPOptions.BaseURL + "/webhook",
+		WebhookSecret: "raw-secret",
+		OnComplete:    func(ctx context.Context, run *ScheduledRun) { runs <- run },
+	})
+	job := &ScheduledJob{
+		Name:        "daily-report",
+		Schedule:    Every(10 * time.Millisecond),
+		Interaction: &Interaction{Model: "gemini-2.5-flash"},
+		Prompt:      "Run {{.Run}}. Previous: {{.Previous}}",
+		Chain:       true,
+	}
+	if err := scheduler.Add(job); err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.Add(job); err == nil {
+		t.Error("Add() of a duplicate job succeeded, want an error")
+	}
+	if err := scheduler.Add(&ScheduledJob{Name: "bad", Schedule: Every(time.Hour), Interaction: &Interaction{}, Prompt: "{{.Run"}); err == nil {
+		t.Error("Add() of an invalid prompt succeeded, want an error")
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error)
+	go func() { done <- scheduler.Run(runCtx) }()
+	for i := 1; i <= 2; i++ {
+		run := <-runs
+		if run.Err != nil || run.Run != i || run.Text != fmt.Sprintf("report %d", i) {
+			t.Errorf("run %d = %+v, want report %d", i, run, i)
+		}
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := requests[0]["input"]; got != "Run 1. Previous: " {
+		t.Errorf("first input = %q", got)
+	}
+	if got := requests[1]["input"]; got != "Run 2. Previous: report 1" {
+		t.Errorf("second input = %q", got)
+	}
+	if got := requests[1]["previousInteractionId"]; got != "run-1" {
+		t.Errorf("second previousInteractionId = %v, want run-1", got)
+	}
+	if _, ok := requests[0]["previousInteractionId"]; ok {
+		t.Errorf("first request = %v, want no previousInteractionId", requests[0])
+	}
+	if len(webhooks) < 2 || webhooks[0]["job"] != "daily-report" || webhooks[0]["text"] != "report 1" {
+		t.Errorf("webhooks = %v, want the runs", webhooks)
+	}
+	records, err := store.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) < 2 || records[0].Text != "report 1" || records[0].Kind != MemoryKindNote {
+		t.Errorf("stored records = %+v, want the reports", records)
+	}
+
+	if _, err := scheduler.RunNow(ctx, "missing"); err == nil {
+		t.Error("RunNow() of an unknown job succeeded, want an error")
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SchemaOf returns the Schema of the JSON encoding of T as returned by
+// SchemaFromType, e.g. for the Parameters of a FunctionDeclaration or the
+// ResponseSchema of a GenerateContentConfig.
+//
+//	type WeatherArgs struct {
+//		City string `json:"city" jsonschema:"description=The city, e.g. Paris"`
+//		Unit string `json:"unit,omitempty" jsonschema:"enum=celsius|fahrenheit"`
+//	}
+//
+//	params, err := genai.SchemaOf[WeatherArgs]()
+//	tool := &genai.Tool{FunctionDeclarations: []*genai.FunctionDeclaration{{
+//		Name:        "get_weather",
+//		Description: "Returns the current weather of a city.",
+//		Parameters:  params,
+//	}}}
+func SchemaOf[T any]() (*Schema, error) {
+	return SchemaFromType(reflect.TypeFor[T]())
+}
+
+// SchemaFromType returns the Schema of the JSON encoding of values of type t,
+// as encoded by encoding/json.
+//
+// Struct fields are named by their json tags and ordered as declared. Fields
+// without omitempty or omitzero that are not pointers are required, and
+// pointer fields are nullable. The fields of embedded structs without a json
+// name are promoted. A jsonschema tag sets the comma-separated options of the
+// Schema of a field, in any order:
+//
+//   - description=text sets the Description. The text may contain commas,
+//     unless the text after a comma starts like another option, name=.
+//   - enum=a|b|c restricts a string field, or the items of a string slice
+//     field, to the given values.
+//
+// Interface fields accept any JSON scalar, as an AnyOf of the string, number
+// and boolean types. Maps, which have no fixed properties, and recursive types,
+// which the Schema cannot refer back to, are an error.
+//
+// The same rules give the JSON schema returned by InteractionResponseFormatFor,
+// which also supports maps.
+func SchemaFromType(t reflect.Type) (*Schema, error) {
+	if t == nil {
+		return nil, errors.New("type is required")
+	}
+	jsonSchema, err := jsonSchemaOf(t)
+	if err != nil {
+		return nil, err
+	}
+	return schemaFromJSONSchema(jsonSchema)
+}
+
+// schemaFromJSONSchema returns the Schema of a JSON schema returned by
+// jsonSchemaOf.
+func schemaFromJSONSchema(jsonSchema map[string]any) (*Schema, error) {
+	if _, ok := jsonSchema["additionalProperties"]; ok {
+		return nil, errors.New("maps have no Schema, use a struct instead")
+	}
+	schema := &Schema{}
+	typ, nullable := jsonSchemaType(jsonSchema)
+	if typ == "" {
+		// Any JSON value, which the Schema can only express for scalars.
+		schema.AnyOf = []*Schema{{Type: TypeString}, {Type: TypeNumber}, {Type: TypeBoolean}}
+	} else {
+		schema.Type = Type(strings.ToUpper(typ))
+	}
+	if nullable {
+		schema.Nullable = Ptr(true)
+	}
+	schema.Description, _ = jsonSchema["description"].(string)
+	schema.Format, _ = jsonSchema["format"].(string)
+	schema.Enum, _ = jsonSchema["enum"].([]string)
+	if items, ok := jsonSchema["items"].(map[string]any); ok {
+		var err error
+		if schema.Items, err = schemaFromJSONSchema(items); err != nil {
+			return nil, err
+		}
+	}
+	if properties, ok := jsonSchema["properties"].(map[string]any); ok {
+		schema.Properties = map[string]*Schema{}
+		schema.Required, _ = jsonSchema["required"].([]string)
+		schema.PropertyOrdering, _ = jsonSchema["propertyOrdering"].([]string)
+		for _, name := range schema.PropertyOrdering {
+			property, err := schemaFromJSONSchema(properties[name].(map[string]any))
+			if err != nil {
+				return nil, fmt.Errorf("property %s: %w", name, err)
+			}
+			schema.Properties[name] = property
+		}
+	}
+	return schema, nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type testLocation struct {
+	City    string `json:"city" jsonschema:"description=The city, e.g. Paris"`
+	Country string `json:"country,omitempty" jsonschema:"enum=FR|DE|IT,description=The ISO code of the country"`
+}
+
+type testWeatherArgs struct {
+	testLocation
+	Unit   string          `json:"unit,omitempty" jsonschema:"description=The unit, metric or not,enum=celsius|fahrenheit"`
+	Days   *int            `json:"days" jsonschema:"description=The number of days to forecast"`
+	Extra  any             `json:"extra,omitempty"`
+	Nearby []*testLocation `json:"nearby,omitempty"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	got, err := SchemaOf[testWeatherArgs]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	location := &Schema{
+		Type: TypeObject,
+		Properties: map[string]*Schema{
+			"city":    {Type: TypeString, Description: "The city, e.g. Paris"},
+			"country": {Type: TypeString, Enum: []string{"FR", "DE", "IT"}, Description: "The ISO code of the country"},
+		},
+		Required:         []string{"city"},
+		PropertyOrdering: []string{"city", "country"},
+	}
+	want := &Schema{
+		Type: TypeObject,
+		Properties: map[string]*Schema{
+			"city":    location.Properties["city"],
+			"country": location.Properties["country"],
+			"unit":    {Type: TypeString, Enum: []string{"celsius", "fahrenheit"}, Description: "The unit, metric or not"},
+			"days":    {Type: TypeInteger, Description: "The number of days to forecast", Nullable: Ptr(true)},
+			"extra":   {AnyOf: []*Schema{{Type: TypeString}, {Type: TypeNumber}, {Type: TypeBoolean}}},
+			"nearby":  {Type: TypeArray, Items: location},
+		},
+		Required:         []string{"city"},
+		PropertyOrdering: []string{"city", "country", "unit", "days", "extra", "nearby"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SchemaOf() mismatch (-want +got):\n%s", diff)
+	}
+
+	fromType, err := SchemaFromType(reflect.TypeOf(&testWeatherArgs{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, fromType); diff != "" {
+		t.Errorf("SchemaFromType() of a pointer mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSchemaFromTypeErrors(t *testing.T) {
+	type tree struct {
+		Value int `json:"value"`
+		Left  *struct {
+			Right *tree `json:"right"`
+		} `json:"left"`
+	}
+	type unknownOption struct {
+		Name string `json:"name" jsonschema:"title=Name"`
+	}
+	type optionAfterDescription struct {
+		Name string `json:"name" jsonschema:"description=The name, in full,title=Name"`
+	}
+	type enumOfBooleans struct {
+		Flag bool `json:"flag" jsonschema:"enum=true|false"`
+	}
+	tests := []struct {
+		name string
+		t    reflect.Type
+	}{
+		{name: "nil", t: nil},
+		{name: "recursive", t: reflect.TypeFor[tree]()},
+		{name: "map", t: reflect.TypeFor[struct {
+			Labels map[string]string `json:"labels"`
+		}]()},
+		{name: "channel", t: reflect.TypeFor[chan int]()},
+		{name: "unknown option", t: reflect.TypeFor[unknownOption]()},
+		{name: "unknown option after description", t: reflect.TypeFor[optionAfterDescription]()},
+		{name: "enum of booleans", t: reflect.TypeFor[enumOfBooleans]()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := SchemaFromType(tt.t); err == nil {
+				t.Errorf("SchemaFromType() = %+v, want error", got)
+			}
+		})
+	}
+}
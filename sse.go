@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SSEEvent is a raw server-sent event of a streaming response, e.g. to
+// implement custom resumption with the ID of the last event, or to debug
+// events that cannot be decoded. See [RequestOptions.OnSSEEvent].
+type SSEEvent struct {
+	// Event is the event type, or empty for the default "message" type.
+	Event string
+	// ID is the event ID, or empty if the event has none.
+	ID string
+	// Data is the data of the event, with the lines of multi-line data
+	// joined by "\n".
+	Data string
+	// Retry is the reconnection time requested by the server, or zero.
+	Retry time.Duration
+	// Raw is the unparsed event, without its terminating blank line.
+	Raw []byte
+	// Err is the error decoding the data of the event, if any. Events with
+	// an error are skipped by the stream.
+	Err error
+}
+
+// parseSSEEvent parses an event block of a text/event-stream. Comment lines
+// and unknown fields are ignored.
+func parseSSEEvent(block []byte) *SSEEvent {
+	event := &SSEEvent{Raw: block}
+	var data []string
+	for _, line := range bytes.Split(block, []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+		if len(line) == 0 || line[0] == ':' {
+			continue
+		}
+		field, value, _ := strings.Cut(string(line), ":")
+		value = strings.TrimPrefix(value, " ")
+		switch strings.TrimSpace(field) {
+		case "event":
+			event.Event = value
+		case "id":
+			event.ID = value
+		case "data":
+			data = append(data, strings.TrimSpace(value))
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	event.Data = strings.Join(data, "\n")
+	return event
+}
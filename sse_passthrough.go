@@ -0,0 +1,176 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+)
+
+// GenerateContentStreamRaw sends the request of GenerateContentStream and
+// returns an iterator over the server-sent events of the response, which are
+// not decoded, e.g. to relay them to a browser with WriteSSE. The data of the
+// events is the JSON of the API of the backend, in the format of the Gemini
+// API or of Vertex AI, not the JSON encoding of GenerateContentResponse.
+//
+// The Raw bytes of an event are only valid until the next event is read; copy
+// them to keep them. Error events of the stream are yielded as errors. The
+// output guardrails of the client are not applied since the responses are not
+// decoded.
+func (m Models) GenerateContentStreamRaw(ctx context.Context, model string, contents []*Content, config *GenerateContentConfig) iter.Seq2[*SSEEvent, error] {
+	model = m.apiClient.resolveModel(model)
+	if config != nil {
+		config.setDefaults()
+	}
+	parameterMap := make(map[string]any)
+	deepMarshal(map[string]any{"model": model, "contents": contents, "config": config}, &parameterMap)
+	httpOptions := &HTTPOptions{}
+	if config != nil && config.HTTPOptions != nil {
+		httpOptions = config.HTTPOptions
+	}
+	if httpOptions.Headers == nil {
+		httpOptions.Headers = http.Header{}
+	}
+	toConverter := generateContentParametersToMldev
+	if m.apiClient.clientConfig.Backend == BackendVertexAI {
+		toConverter = generateContentParametersToVertex
+	}
+	body, err := toConverter(m.apiClient, parameterMap, nil, parameterMap)
+	if err != nil {
+		return yieldErrorAndEndIterator[SSEEvent](err)
+	}
+	urlParams, _ := body["_url"].(map[string]any)
+	path, err := formatMap("{model}:streamGenerateContent?alt=sse", urlParams)
+	if err != nil {
+		return yieldErrorAndEndIterator[SSEEvent](fmt.Errorf("invalid url params: %#v.\n%w", urlParams, err))
+	}
+	delete(body, "_url")
+	delete(body, "config")
+	var rs responseStream[SSEEvent]
+	if err := sendStreamRequest(ctx, m.apiClient, path, http.MethodPost, body, httpOptions, &rs); err != nil {
+		return yieldErrorAndEndIterator[SSEEvent](err)
+	}
+	return iterateRawStream(&rs)
+}
+
+// iterateRawStream returns an iterator over the events of rs with data. It
+// ends after the [DONE] event, which is yielded.
+func iterateRawStream[R any](rs *responseStream[R]) iter.Seq2[*SSEEvent, error] {
+	return func(yield func(*SSEEvent, error) bool) {
+		stopped := false
+		defer func() {
+			closeStream(rs.rc, stopped, rs.opts)
+			rs.timeouts.release()
+			rs.call.done()
+		}()
+		for rs.r.Scan() {
+			rs.timeouts.resetIdle()
+			block := rs.r.Bytes()
+			if len(block) == 0 {
+				continue
+			}
+			event := parseSSEEvent(block)
+			rs.notifyEvent(event)
+			if event.Data == "" {
+				if err := rs.blockError(block); err != nil && !yield(nil, err) {
+					stopped = true
+					return
+				}
+				continue
+			}
+			if !yield(event, nil) {
+				stopped = true
+				return
+			}
+			if event.Data == "[DONE]" {
+				return
+			}
+		}
+		if err := rs.r.Err(); err != nil {
+			yield(nil, fmt.Errorf("stream interrupted: %w", rs.timeouts.cause(err)))
+		}
+	}
+}
+
+// WriteSSE writes the events of stream to w as a text/event-stream, flushing
+// w after every event, and returns the error of the stream, if any. Each
+// event is written verbatim from its Raw bytes.
+//
+// If the stream fails before its first event, the error is written as a JSON
+// error response with the status code of the APIError, or 502 Bad Gateway.
+// If it fails later, the headers have been sent, so the error is written as
+// an event of type "error" whose data is the JSON error.
+func WriteSSE(w http.ResponseWriter, stream iter.Seq2[*SSEEvent, error]) error {
+	flusher, _ := w.(http.Flusher)
+	started := false
+	for event, err := range stream {
+		if err != nil {
+			writeSSEError(w, err, started)
+			return err
+		}
+		if !started {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+		if _, err := w.Write(event.Raw); err != nil {
+			return fmt.Errorf("failed to write event: %w", err)
+		}
+		if _, err := w.Write([]byte("\n\n")); err != nil {
+			return fmt.Errorf("failed to write event: %w", err)
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	return nil
+}
+
+func writeSSEError(w http.ResponseWriter, err error, started bool) {
+	apiErr, ok := AsAPIError(err)
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		apiErr = APIError{Code: http.StatusGatewayTimeout, Message: err.Error(), Status: "DEADLINE_EXCEEDED"}
+	case !ok || apiErr.Code == 0:
+		apiErr = APIError{Code: http.StatusBadGateway, Message: err.Error(), Status: "UNAVAILABLE"}
+	}
+	data, _ := json.Marshal(map[string]any{"error": apiErr})
+	if !started {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(apiErr.Code)
+		w.Write(data)
+		return
+	}
+	fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// NewSSEHandler returns an http.Handler that relays the raw stream returned
+// by open for each request to the client with WriteSSE, e.g. a stream of
+// GenerateContentStreamRaw for the prompt of the request. The stream should
+// be opened with the context of the request, so that it is cancelled when
+// the client disconnects.
+func NewSSEHandler(open func(r *http.Request) iter.Seq2[*SSEEvent, error]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		WriteSSE(w, open(r))
+	})
+}
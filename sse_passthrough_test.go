@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSSEPassthrough(t *testing.T) {
+	upstream := `data: {"candidates": [{"content": {"role": "model", "parts": [{"text": "Hello"}]}}]}
+
+event: message
+id: 2
+data: {"candidates": [{"content": {"role": "model", "parts": [{"text": " world"}]}, "finishReason": "STOP"}]}
+
+`
+	mode := "ok"
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ":streamGenerateContent") || r.URL.Query().Get("alt") != "sse" {
+			t.Errorf("request path = %s, want a streamGenerateContent request", r.URL)
+		}
+		switch mode {
+		case "rate limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"error": {"code": 429, "status": "RESOURCE_EXHAUSTED", "message": "quota"}}`)
+		case "broken":
+			fmt.Fprint(w, "data: {\"candidates\": []}\n\n"+`{"error": {"code": 500, "status": "INTERNAL", "message": "boom"}}`+"\n\n")
+		default:
+			fmt.Fprint(w, upstream)
+		}
+	})
+	proxy := httptest.NewServer(NewSSEHandler(func(r *http.Request) iter.Seq2[*SSEEvent, error] {
+		return client.Models.GenerateContentStreamRaw(r.Context(), "gemini-2.5-flash", Text(r.URL.Query().Get("q")), nil)
+	}))
+	defer proxy.Close()
+	get := func() (*http.Response, string) {
+		t.Helper()
+		resp, err := http.Get(proxy.URL + "?q=hi")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	resp, body := get()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("response = %d %s, want 200 text/event-stream", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if body != upstream {
+		t.Errorf("relayed body =\n%s\nwant the upstream events verbatim\n%s", body, upstream)
+	}
+
+	mode = "rate limited"
+	resp, body = get()
+	var apiErr struct{ Error APIError }
+	if err := json.Unmarshal([]byte(body), &apiErr); err != nil || resp.StatusCode != http.StatusTooManyRequests || apiErr.Error.Status != "RESOURCE_EXHAUSTED" {
+		t.Errorf("response = %d %s, want the 429 error", resp.StatusCode, body)
+	}
+
+	mode = "broken"
+	_, body = get()
+	want := "data: {\"candidates\": []}\n\n" + `event: error` + "\n" + `data: {"error":{"code":500,"message":"boom","status":"INTERNAL"}}` + "\n\n"
+	if body != want {
+		t.Errorf("relayed body =\n%q\nwant\n%q", body, want)
+	}
+}
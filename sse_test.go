@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseSSEEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		block string
+		want  *SSEEvent
+	}{
+		{"Data", `data: {"a": 1}`, &SSEEvent{Data: `{"a": 1}`}},
+		{"AllFields", "event: content.delta\nid: evt-1\nretry: 1500\ndata: {}", &SSEEvent{Event: "content.delta", ID: "evt-1", Retry: 1500 * time.Millisecond, Data: "{}"}},
+		{"MultiLineData", "data: {\"a\":\r\ndata: 1}", &SSEEvent{Data: "{\"a\":\n1}"}},
+		{"Comment", ": keep-alive", &SSEEvent{}},
+		{"NoSpace", "data:[DONE]", &SSEEvent{Data: "[DONE]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSSEEvent([]byte(tt.block))
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(SSEEvent{}, "Raw")); diff != "" {
+				t.Errorf("parseSSEEvent() mismatch (-want +got):\n%s", diff)
+			}
+			if string(got.Raw) != tt.block {
+				t.Errorf("Raw = %q, want %q", got.Raw, tt.block)
+			}
+		})
+	}
+}
+
+func TestOnSSEEvent(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("id: 1\ndata: {\"candidates\": [{\"content\": {\"parts\": [{\"text\": \"a\"}]}}]}\n\n"))
+		w.Write([]byte("id: 2\ndata: {\"candidates\": [\n\n"))
+		w.Write([]byte("id: 3\ndata: {\"candidates\": [{\"content\": {\"parts\": [{\"text\": \"b\"}]}}]}\n\n"))
+	})
+
+	var events []*SSEEvent
+	ctx = WithRequestOptions(ctx, &RequestOptions{
+		OnSSEEvent: func(e *SSEEvent) { events = append(events, e) },
+	})
+	var ids []string
+	for _, err := range client.Models.GenerateContentStream(ctx, "gemini-2.5-flash", Text("Hi"), nil) {
+		if err != nil {
+			t.Fatalf("GenerateContentStream() failed: %v", err)
+		}
+		ids = append(ids, events[len(events)-1].ID)
+	}
+	if diff := cmp.Diff([]string{"1", "3"}, ids); diff != "" {
+		t.Errorf("event IDs of the responses mismatch (-want +got):\n%s", diff)
+	}
+	if len(events) != 3 {
+		t.Fatalf("OnSSEEvent was called %d times, want 3", len(events))
+	}
+	if events[0].Err != nil || events[1].Err == nil || events[1].ID != "2" {
+		t.Errorf("events = %+v, want an error for the malformed event 2", events)
+	}
+}
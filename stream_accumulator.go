@@ -0,0 +1,167 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"iter"
+	"maps"
+	"slices"
+)
+
+// StreamAccumulator merges the chunks of a response streamed by
+// Models.GenerateContentStream into the response that Models.GenerateContent
+// would have returned.
+//
+//	var acc genai.StreamAccumulator
+//	for chunk, err := range client.Models.GenerateContentStream(ctx, model, contents, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Print(chunk.Text())
+//		acc.Add(chunk)
+//	}
+//	resp := acc.Response()
+//
+// The contents of the candidates are merged as Chats merge them for their
+// history: consecutive text parts are joined and function calls streamed over
+// several chunks are assembled. The citations and log probabilities of the
+// chunks are concatenated. For the other fields, such as the finish reason and
+// the usage metadata, which the last chunk has in full, the last set value is
+// kept.
+//
+// The zero value is ready to use. A StreamAccumulator is not safe for
+// concurrent use.
+type StreamAccumulator struct {
+	response   *GenerateContentResponse
+	candidates map[int32]*accumulatedCandidate
+}
+
+// accumulatedCandidate is a candidate merged from the chunks of a stream.
+type accumulatedCandidate struct {
+	// candidate has the merged fields of the candidate, except its content.
+	candidate Candidate
+	contents  streamContentMerger
+}
+
+// Add merges chunk into the response.
+func (a *StreamAccumulator) Add(chunk *GenerateContentResponse) {
+	if chunk == nil {
+		return
+	}
+	if a.response == nil {
+		a.response = &GenerateContentResponse{}
+		a.candidates = map[int32]*accumulatedCandidate{}
+	}
+	r := a.response
+	if chunk.SDKHTTPResponse != nil {
+		r.SDKHTTPResponse = chunk.SDKHTTPResponse
+	}
+	if r.CreateTime.IsZero() {
+		r.CreateTime = chunk.CreateTime
+	}
+	if chunk.ModelVersion != "" {
+		r.ModelVersion = chunk.ModelVersion
+	}
+	if chunk.ResponseID != "" {
+		r.ResponseID = chunk.ResponseID
+	}
+	if chunk.PromptFeedback != nil {
+		r.PromptFeedback = cloneOf(chunk.PromptFeedback)
+	}
+	if chunk.UsageMetadata != nil {
+		r.UsageMetadata = cloneOf(chunk.UsageMetadata)
+	}
+	for _, candidate := range chunk.Candidates {
+		if candidate != nil {
+			a.addCandidate(candidate)
+		}
+	}
+}
+
+func (a *StreamAccumulator) addCandidate(chunk *Candidate) {
+	acc, ok := a.candidates[chunk.Index]
+	if !ok {
+		acc = &accumulatedCandidate{candidate: Candidate{Index: chunk.Index}}
+		a.candidates[chunk.Index] = acc
+	}
+	acc.contents.add(chunk.Content)
+	c := &acc.candidate
+	if chunk.FinishReason != "" {
+		c.FinishReason = chunk.FinishReason
+	}
+	if chunk.FinishMessage != "" {
+		c.FinishMessage = chunk.FinishMessage
+	}
+	if chunk.TokenCount != 0 {
+		c.TokenCount = chunk.TokenCount
+	}
+	if chunk.AvgLogprobs != 0 {
+		c.AvgLogprobs = chunk.AvgLogprobs
+	}
+	if len(chunk.SafetyRatings) > 0 {
+		c.SafetyRatings = *cloneOf(&chunk.SafetyRatings)
+	}
+	if chunk.GroundingMetadata != nil {
+		c.GroundingMetadata = cloneOf(chunk.GroundingMetadata)
+	}
+	if chunk.URLContextMetadata != nil {
+		c.URLContextMetadata = cloneOf(chunk.URLContextMetadata)
+	}
+	if chunk.CitationMetadata != nil {
+		if c.CitationMetadata == nil {
+			c.CitationMetadata = &CitationMetadata{}
+		}
+		c.CitationMetadata.Citations = append(c.CitationMetadata.Citations, *cloneOf(&chunk.CitationMetadata.Citations)...)
+	}
+	if chunk.LogprobsResult != nil {
+		if c.LogprobsResult == nil {
+			c.LogprobsResult = &LogprobsResult{}
+		}
+		logprobs := cloneOf(chunk.LogprobsResult)
+		c.LogprobsResult.ChosenCandidates = append(c.LogprobsResult.ChosenCandidates, logprobs.ChosenCandidates...)
+		c.LogprobsResult.TopCandidates = append(c.LogprobsResult.TopCandidates, logprobs.TopCandidates...)
+	}
+}
+
+// Response returns the response merged from the chunks so far, with its
+// candidates in the order of their index, or nil if no chunk was added. The
+// result is a copy, later chunks do not modify it.
+func (a *StreamAccumulator) Response() *GenerateContentResponse {
+	if a.response == nil {
+		return nil
+	}
+	r := cloneOf(a.response)
+	for _, i := range slices.Sorted(maps.Keys(a.candidates)) {
+		acc := a.candidates[i]
+		candidate := cloneOf(&acc.candidate)
+		candidate.Content = cloneOf(acc.contents.content)
+		r.Candidates = append(r.Candidates, candidate)
+	}
+	return r
+}
+
+// Collect consumes the chunks of a stream of Models.GenerateContentStream and
+// returns the response merged by a StreamAccumulator. If the stream fails, the
+// response merged so far, which may be nil, is returned with the error.
+func Collect(stream iter.Seq2[*GenerateContentResponse, error]) (*GenerateContentResponse, error) {
+	var acc StreamAccumulator
+	for chunk, err := range stream {
+		if err != nil {
+			return acc.Response(), err
+		}
+		acc.Add(chunk)
+	}
+	return acc.Response(), nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func chunkStream(chunks []*GenerateContentResponse, err error) iter.Seq2[*GenerateContentResponse, error] {
+	return func(yield func(*GenerateContentResponse, error) bool) {
+		for _, chunk := range chunks {
+			if !yield(chunk, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func TestCollect(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	chunks := []*GenerateContentResponse{
+		{
+			CreateTime:   created,
+			ResponseID:   "r1",
+			ModelVersion: "gemini-2.5-flash",
+			Candidates:   []*Candidate{{Content: &Content{Role: RoleModel, Parts: []*Part{{Text: "Thinking", Thought: true}}}}},
+		},
+		{Candidates: []*Candidate{{
+			Content:          &Content{Role: RoleModel, Parts: []*Part{{Text: "The weather "}}},
+			CitationMetadata: &CitationMetadata{Citations: []*Citation{{URI: "https://a"}}},
+		}}},
+		{Candidates: []*Candidate{{Content: &Content{Role: RoleModel, Parts: []*Part{
+			{Text: "is "},
+			{FunctionCall: &FunctionCall{Name: "weather", Args: map[string]any{"city": "Paris"}}},
+		}}}}},
+		{
+			Candidates: []*Candidate{{
+				Content:          &Content{Role: RoleModel, Parts: []*Part{{Text: "unknown."}}},
+				CitationMetadata: &CitationMetadata{Citations: []*Citation{{URI: "https://b"}}},
+				FinishReason:     FinishReasonStop,
+			}},
+			UsageMetadata: &GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, TotalTokenCount: 15},
+		},
+	}
+	got, err := Collect(chunkStream(chunks, nil))
+	if err != nil {
+		t.Fatalf("Collect() failed: %v", err)
+	}
+	want := &GenerateContentResponse{
+		CreateTime:   created,
+		ResponseID:   "r1",
+		ModelVersion: "gemini-2.5-flash",
+		Candidates: []*Candidate{{
+			Content: &Content{Role: RoleModel, Parts: []*Part{
+				{Text: "Thinking", Thought: true},
+				{Text: "The weather is "},
+				{FunctionCall: &FunctionCall{Name: "weather", Args: map[string]any{"city": "Paris"}}},
+				{Text: "unknown."},
+			}},
+			CitationMetadata: &CitationMetadata{Citations: []*Citation{{URI: "https://a"}, {URI: "https://b"}}},
+			FinishReason:     FinishReasonStop,
+		}},
+		UsageMetadata: &GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, TotalTokenCount: 15},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Collect() mismatch (-want +got):\n%s", diff)
+	}
+	if chunks[1].Candidates[0].Content.Parts[0].Text != "The weather " {
+		t.Error("Collect() modified the chunks")
+	}
+
+	streamErr := errors.New("stream failed")
+	got, err = Collect(chunkStream(chunks[:2], streamErr))
+	if !errors.Is(err, streamErr) || got.Text() != "The weather " {
+		t.Errorf("Collect() = %v, %v, want the partial response and the stream error", got, err)
+	}
+	if got, err := Collect(chunkStream(nil, nil)); got != nil || err != nil {
+		t.Errorf("Collect() of an empty stream = %v, %v, want nil", got, err)
+	}
+}
+
+func TestStreamAccumulatorCandidates(t *testing.T) {
+	var acc StreamAccumulator
+	acc.Add(&GenerateContentResponse{Candidates: []*Candidate{
+		{Index: 1, Content: NewContentFromText("b", RoleModel)},
+		{Index: 0, Content: NewContentFromText("a", RoleModel)},
+	}})
+	first := acc.Response()
+	acc.Add(&GenerateContentResponse{Candidates: []*Candidate{
+		{Index: 0, Content: NewContentFromText("a", RoleModel), FinishReason: FinishReasonStop},
+		{Index: 1, Content: NewContentFromText("b", RoleModel), FinishReason: FinishReasonMaxTokens},
+	}})
+	var got []string
+	for _, c := range acc.Response().Candidates {
+		got = append(got, c.Content.Parts[0].Text+" "+string(c.FinishReason))
+	}
+	if diff := cmp.Diff([]string{"aa STOP", "bb MAX_TOKENS"}, got); diff != "" {
+		t.Errorf("Response() candidates mismatch (-want +got):\n%s", diff)
+	}
+	if first.Candidates[0].Content.Parts[0].Text != "a" {
+		t.Error("Add() modified an earlier Response()")
+	}
+}
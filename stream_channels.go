@@ -0,0 +1,80 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"iter"
+	"sync"
+)
+
+// StreamChannelsConfig configures StreamToChannels.
+type StreamChannelsConfig struct {
+	// Optional. The number of items buffered in the item channel while the
+	// receiver is busy. The stream is not read further while the buffer is
+	// full. Defaults to 0, an unbuffered channel.
+	BufferSize int
+}
+
+// StreamToChannels reads seq in a goroutine and sends its items on the first
+// returned channel, so that a stream can be received in a select statement
+// alongside other channels. It works with the streams of
+// Models.GenerateContentStream, Chat.SendMessageStream and
+// Interactions.CreateStream alike, e.g.
+//
+//	chunks, errs, cancel := genai.StreamToChannels(client.Models.GenerateContentStream(ctx, model, contents, nil), nil)
+//	defer cancel()
+//	for chunk := range chunks {
+//		fmt.Print(chunk.Text())
+//	}
+//	if err := <-errs; err != nil {
+//		return err
+//	}
+//
+// The error of seq, if any, is sent on the error channel, which is buffered;
+// the stream ends at the first error. Both channels are closed once the
+// stream has ended, the item channel first.
+//
+// cancel stops reading seq, as breaking out of a range loop over it does,
+// and may be called more than once. The items that were not received are
+// dropped. A read of seq that is blocked waiting for the server is only
+// interrupted by cancelling the context of the stream. cancel must be called
+// if the channels are not drained, so that the goroutine does not leak.
+func StreamToChannels[T any](seq iter.Seq2[T, error], config *StreamChannelsConfig) (<-chan T, <-chan error, func()) {
+	buffer := 0
+	if config != nil && config.BufferSize > 0 {
+		buffer = config.BufferSize
+	}
+	items := make(chan T, buffer)
+	errs := make(chan error, 1)
+	done := make(chan struct{})
+	var once sync.Once
+	cancel := func() { once.Do(func() { close(done) }) }
+	go func() {
+		defer close(errs)
+		defer close(items)
+		for item, err := range seq {
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case items <- item:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return items, errs, cancel
+}
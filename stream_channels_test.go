@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"errors"
+	"iter"
+	"testing"
+	"time"
+)
+
+func TestStreamToChannels(t *testing.T) {
+	streamErr := errors.New("stream failed")
+	chunks := []*GenerateContentResponse{{ResponseID: "1"}, {ResponseID: "2"}}
+	items, errs, cancel := StreamToChannels(chunkStream(chunks, streamErr), nil)
+	defer cancel()
+	var got []string
+	for chunk := range items {
+		got = append(got, chunk.ResponseID)
+	}
+	if len(got) != 2 || got[0] != "1" || got[1] != "2" {
+		t.Errorf("received %v, want the 2 chunks", got)
+	}
+	if err := <-errs; !errors.Is(err, streamErr) {
+		t.Errorf("error = %v, want the stream error", err)
+	}
+	if _, ok := <-errs; ok {
+		t.Error("the error channel is not closed")
+	}
+
+	events := func(yield func(*InteractionEvent, error) bool) {
+		yield(&InteractionEvent{Index: 1}, nil)
+	}
+	eventItems, eventErrs, cancel := StreamToChannels(iter.Seq2[*InteractionEvent, error](events), &StreamChannelsConfig{BufferSize: 1})
+	defer cancel()
+	if event := <-eventItems; event == nil || event.Index != 1 {
+		t.Errorf("received %+v, want the event", event)
+	}
+	if err := <-eventErrs; err != nil {
+		t.Errorf("error = %v, want nil", err)
+	}
+}
+
+func TestStreamToChannelsCancel(t *testing.T) {
+	stopped := make(chan int)
+	infinite := func(yield func(*GenerateContentResponse, error) bool) {
+		n := 0
+		for yield(&GenerateContentResponse{}, nil) {
+			n++
+		}
+		stopped <- n
+	}
+	items, errs, cancel := StreamToChannels(infinite, &StreamChannelsConfig{BufferSize: 2})
+	<-items
+	cancel()
+	cancel()
+	select {
+	case n := <-stopped:
+		if n > 4 {
+			t.Errorf("read %d chunks, want at most the received chunk and the buffered ones", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the stream was not stopped by cancel")
+	}
+	for range items {
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("error = %v, want nil", err)
+	}
+}
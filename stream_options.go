@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"io"
+	"iter"
+	"log"
+	"time"
+)
+
+// defaultStreamDrainLimit is the number of bytes drained from a stream that the
+// caller stopped early when StreamOptions.DrainBody is set without a limit.
+const defaultStreamDrainLimit = 64 * 1024
+
+// defaultStreamCancelTimeout bounds the Cancel call issued for an interaction
+// stream that the caller stopped early.
+const defaultStreamCancelTimeout = 10 * time.Second
+
+// StreamOptions controls what happens when the caller stops iterating a
+// streaming response early, e.g. by breaking out of the range loop.
+//
+// By default the response body is closed without reading the rest of it. This
+// closes the HTTP connection, which stops a GenerateContentStream generation on
+// the server, but the connection cannot be reused. Interactions continue to run
+// on the server unless CancelOnBreak is set.
+type StreamOptions struct {
+	// Optional. If true, up to DrainLimit bytes of the remaining response body
+	// are read and discarded before the body is closed, so that the HTTP
+	// connection can be reused for later requests. If the stream has more data
+	// than that, the body is closed as if DrainBody were false. Note that the
+	// server keeps generating while the body is drained.
+	DrainBody bool
+	// Optional. The maximum number of bytes read when DrainBody is true.
+	// Defaults to 64 KiB.
+	DrainLimit int64
+	// Optional. If true, an interaction stream that is stopped early cancels the
+	// interaction on the server with [Interactions.Cancel], so that it does not
+	// keep running in the background. Only used by Interactions streams.
+	CancelOnBreak bool
+}
+
+// closeStream closes the response body of a stream. If the caller stopped the
+// stream early and draining is enabled, the rest of the body is discarded
+// first so that the connection can be reused.
+func closeStream(rc io.ReadCloser, stopped bool, opts *StreamOptions) {
+	if stopped && opts != nil && opts.DrainBody {
+		limit := opts.DrainLimit
+		if limit <= 0 {
+			limit = defaultStreamDrainLimit
+		}
+		_, _ = io.CopyN(io.Discard, rc, limit)
+	}
+	if err := rc.Close(); err != nil {
+		log.Printf("Error closing response body: %v", err)
+	}
+}
+
+// cancelInteractionOnBreak wraps an interaction event stream of a call made
+// with ctx so that the interaction is cancelled on the server if the caller
+// stops iterating before the stream ends. knownID is the interaction ID if it
+// is already known; otherwise it is taken from the stream events. The Cancel
+// call is made with the request options of ctx, even if ctx is done.
+func (i *Interactions) cancelInteractionOnBreak(ctx context.Context, seq iter.Seq2[*InteractionEvent, error], knownID string) iter.Seq2[*InteractionEvent, error] {
+	opts := i.apiClient.requestOptions(ctx).StreamOptions
+	if opts == nil || !opts.CancelOnBreak {
+		return seq
+	}
+	return func(yield func(*InteractionEvent, error) bool) {
+		id := knownID
+		stopped := false
+		for event, err := range seq {
+			if id == "" && event != nil && event.Interaction != nil {
+				id = event.Interaction.ID
+			}
+			if !yield(event, err) {
+				stopped = true
+				break
+			}
+		}
+		if !stopped || id == "" {
+			return
+		}
+		cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), defaultStreamCancelTimeout)
+		defer cancel()
+		if _, err := i.Cancel(cancelCtx, id, nil); err != nil {
+			log.Printf("Warning: failed to cancel interaction %s after the stream was stopped: %v", id, err)
+		}
+	}
+}
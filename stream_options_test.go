@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// trackingBody is a response body that records how much of it was read.
+type trackingBody struct {
+	*strings.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestStreamOptionsDrainBody(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(&sb, "data: {\"candidates\": [{\"content\": {\"parts\": [{\"text\": \"chunk %d\"}]}}]}\n\n", i)
+	}
+	stream := sb.String()
+
+	tests := []struct {
+		name          string
+		opts          *StreamOptions
+		stop          bool
+		wantRemaining func(remaining int) bool
+	}{
+		{name: "stopped without options", stop: true, wantRemaining: func(r int) bool { return r > 0 }},
+		{name: "stopped and drained", opts: &StreamOptions{DrainBody: true}, stop: true, wantRemaining: func(r int) bool { return r == 0 }},
+		{name: "drain limit exceeded", opts: &StreamOptions{DrainBody: true, DrainLimit: 16}, stop: true, wantRemaining: func(r int) bool { return r > 0 }},
+		{name: "read to the end", opts: &StreamOptions{DrainBody: true}, wantRemaining: func(r int) bool { return r == 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &trackingBody{Reader: strings.NewReader(stream)}
+			rs := &responseStream[GenerateContentResponse]{opts: tt.opts}
+			if err := deserializeStreamResponse(&http.Response{StatusCode: http.StatusOK, Body: body}, rs); err != nil {
+				t.Fatal(err)
+			}
+			chunks := 0
+			for _, err := range iterateResponseStream(rs, func(responseMap map[string]any) (*GenerateContentResponse, error) {
+				response := new(GenerateContentResponse)
+				return response, mapToStruct(responseMap, response)
+			}) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				chunks++
+				if tt.stop {
+					break
+				}
+			}
+			if !body.closed {
+				t.Errorf("body was not closed")
+			}
+			if !tt.wantRemaining(body.Len()) {
+				t.Errorf("%d unread bytes left after %d chunks", body.Len(), chunks)
+			}
+		})
+	}
+}
+
+func TestStreamOptionsCancelOnBreak(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var cancelled []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/cancel") {
+			mu.Lock()
+			cancelled = append(cancelled, r.URL.Path)
+			mu.Unlock()
+			fmt.Fprint(w, `{"id": "int-1", "status": "cancelled"}`)
+			return
+		}
+		fmt.Fprint(w, "data: {\"event_type\": \"interaction.start\", \"interaction\": {\"id\": \"int-1\"}}\n\n")
+		fmt.Fprint(w, "data: {\"event_type\": \"content.delta\", \"delta\": {\"type\": \"text\", \"text\": \"a\"}}\n\n")
+		fmt.Fprint(w, "data: {\"event_type\": \"content.delta\", \"delta\": {\"type\": \"text\", \"text\": \"b\"}}\n\n")
+	}
+	client := newTestClient(t, nil, handler)
+	cancelCtx := WithRequestOptions(ctx, &RequestOptions{StreamOptions: &StreamOptions{CancelOnBreak: true}})
+
+	t.Run("stopped early", func(t *testing.T) {
+		cancelled = nil
+		for event, err := range client.Interactions.CreateStream(cancelCtx, &Interaction{Model: "m", Input: "hi"}, nil) {
+			if err != nil {
+				t.Fatalf("CreateStream() failed: %v", err)
+			}
+			if event.Delta != nil {
+				break
+			}
+		}
+		if want := []string{"/v1beta/interactions/int-1/cancel"}; len(cancelled) != 1 || cancelled[0] != want[0] {
+			t.Errorf("cancel requests = %v, want %v", cancelled, want)
+		}
+	})
+
+	t.Run("read to the end", func(t *testing.T) {
+		cancelled = nil
+		for _, err := range client.Interactions.CreateStream(cancelCtx, &Interaction{Model: "m", Input: "hi"}, nil) {
+			if err != nil {
+				t.Fatalf("CreateStream() failed: %v", err)
+			}
+		}
+		if len(cancelled) != 0 {
+			t.Errorf("cancel requests = %v, want none", cancelled)
+		}
+	})
+
+	t.Run("request options of the call", func(t *testing.T) {
+		cancelled = nil
+		var labels []map[string]string
+		client := newTestClient(t, &ClientConfig{Interceptors: []Interceptor{
+			func(ctx context.Context, call *InterceptedCall, next Invoker) (*http.Response, error) {
+				if strings.HasSuffix(call.Path, "/cancel") {
+					labels = append(labels, requestOptionsFrom(ctx).Labels)
+				}
+				return next(ctx, call)
+			},
+		}}, handler)
+		callCtx := WithRequestOptions(cancelCtx, &RequestOptions{Labels: map[string]string{"team": "search"}})
+		for range client.Interactions.CreateStream(callCtx, &Interaction{Model: "m", Input: "hi"}, nil) {
+			break
+		}
+		if len(labels) != 1 || labels[0]["team"] != "search" {
+			t.Errorf("labels of the cancel requests = %v, want the labels of the call", labels)
+		}
+	})
+
+	t.Run("option not set", func(t *testing.T) {
+		cancelled = nil
+		for range client.Interactions.CreateStream(ctx, &Interaction{Model: "m", Input: "hi"}, nil) {
+			break
+		}
+		if len(cancelled) != 0 {
+			t.Errorf("cancel requests = %v, want none", cancelled)
+		}
+	})
+}
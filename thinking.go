@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"slices"
+	"strings"
+)
+
+// Values of ThinkingConfig.ThinkingBudget with a special meaning.
+const (
+	// ThinkingBudgetDynamic lets the model decide how many tokens to think.
+	ThinkingBudgetDynamic int32 = -1
+	// ThinkingBudgetOff disables thinking, on the models that support it.
+	ThinkingBudgetOff int32 = 0
+)
+
+// SkipThoughtSignatureValidation is a thought signature that the API accepts
+// for the function calls of a model turn that was not generated by the model
+// it is sent to, e.g. a turn written by hand or generated by another model,
+// see EnsureThoughtSignatures.
+var SkipThoughtSignatureValidation = []byte("skip_thought_signature_validator")
+
+// Thoughts concatenates the thought parts of the first candidate, which are
+// only returned if ThinkingConfig.IncludeThoughts is set.
+func (r *GenerateContentResponse) Thoughts() string {
+	var sb strings.Builder
+	for _, part := range r.ThoughtParts() {
+		sb.WriteString(part.Text)
+	}
+	return sb.String()
+}
+
+// ThoughtParts returns the thought parts of the first candidate.
+func (r *GenerateContentResponse) ThoughtParts() []*Part {
+	var parts []*Part
+	for _, part := range r.firstCandidateParts() {
+		if part != nil && part.Thought {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// ThoughtSignatures returns the thought signatures of the parts of the first
+// candidate, in the order of the parts. They must be sent back unchanged with
+// their parts in the next turns of the conversation, which the first
+// candidate does as a whole: send ModelContent, as Chats do.
+func (r *GenerateContentResponse) ThoughtSignatures() [][]byte {
+	var signatures [][]byte
+	for _, part := range r.firstCandidateParts() {
+		if part != nil && part.ThoughtSignature != nil {
+			signatures = append(signatures, part.ThoughtSignature)
+		}
+	}
+	return signatures
+}
+
+// ModelContent returns the content of the first candidate, with its thought
+// parts and signatures, to add to the contents of the next request. Its role
+// defaults to RoleModel. It returns nil if the response has no content.
+func (r *GenerateContentResponse) ModelContent() *Content {
+	if r == nil || len(r.Candidates) == 0 || r.Candidates[0].Content == nil {
+		return nil
+	}
+	content := r.Candidates[0].Content
+	if content.Role == "" {
+		clone := *content
+		clone.Role = RoleModel
+		content = &clone
+	}
+	return content
+}
+
+// AppendFunctionResponses returns contents followed by the model turn of
+// resp, with its function calls and thought signatures, and a user turn with
+// responses. It is the contents of the request that answers the function calls
+// of resp. contents is not modified.
+func AppendFunctionResponses(contents []*Content, resp *GenerateContentResponse, responses ...*FunctionResponse) []*Content {
+	result := slices.Clone(contents)
+	if content := resp.ModelContent(); content != nil {
+		result = append(result, content)
+	}
+	parts := make([]*Part, len(responses))
+	for i, response := range responses {
+		parts[i] = &Part{FunctionResponse: response}
+	}
+	return append(result, &Content{Role: RoleUser, Parts: parts})
+}
+
+// EnsureThoughtSignatures returns contents with SkipThoughtSignatureValidation
+// as the thought signature of the first function call of every model turn
+// without a signature. Thinking models require the signatures they returned
+// with function calls; this lets a conversation continue with turns that were
+// not generated by the model, e.g. after switching models. contents is not
+// modified.
+func EnsureThoughtSignatures(contents []*Content) []*Content {
+	result := slices.Clone(contents)
+	for i, content := range contents {
+		if content == nil || content.Role != RoleModel {
+			continue
+		}
+		j := slices.IndexFunc(content.Parts, func(p *Part) bool { return p != nil && p.FunctionCall != nil })
+		if j < 0 || slices.ContainsFunc(content.Parts, func(p *Part) bool { return p != nil && p.ThoughtSignature != nil }) {
+			continue
+		}
+		clone := *content
+		clone.Parts = slices.Clone(content.Parts)
+		part := *clone.Parts[j]
+		part.ThoughtSignature = SkipThoughtSignatureValidation
+		clone.Parts[j] = &part
+		result[i] = &clone
+	}
+	return result
+}
+
+func (r *GenerateContentResponse) firstCandidateParts() []*Part {
+	if r == nil || len(r.Candidates) == 0 || r.Candidates[0].Content == nil {
+		return nil
+	}
+	return r.Candidates[0].Content.Parts
+}
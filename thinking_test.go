@@ -0,0 +1,91 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func thinkingResponse() *GenerateContentResponse {
+	return &GenerateContentResponse{Candidates: []*Candidate{{Content: &Content{Parts: []*Part{
+		{Text: "Let me think. ", Thought: true},
+		{Text: "The user wants the weather.", Thought: true},
+		{FunctionCall: &FunctionCall{Name: "weather", Args: map[string]any{"city": "Paris"}}, ThoughtSignature: []byte("sig1")},
+		{Text: "Checking.", ThoughtSignature: []byte("sig2")},
+	}}}}}
+}
+
+func TestResponseThoughts(t *testing.T) {
+	resp := thinkingResponse()
+	if got, want := resp.Thoughts(), "Let me think. The user wants the weather."; got != want {
+		t.Errorf("Thoughts() = %q, want %q", got, want)
+	}
+	if got := len(resp.ThoughtParts()); got != 2 {
+		t.Errorf("ThoughtParts() returned %d parts, want 2", got)
+	}
+	if diff := cmp.Diff([][]byte{[]byte("sig1"), []byte("sig2")}, resp.ThoughtSignatures()); diff != "" {
+		t.Errorf("ThoughtSignatures() mismatch (-want +got):\n%s", diff)
+	}
+	if got := resp.Text(); got != "Checking." {
+		t.Errorf("Text() = %q, want the text without the thoughts", got)
+	}
+	if content := resp.ModelContent(); content.Role != RoleModel || resp.Candidates[0].Content.Role != "" {
+		t.Errorf("ModelContent() role = %q, want model without modifying the response", content.Role)
+	}
+	if got := (&GenerateContentResponse{}).Thoughts(); got != "" {
+		t.Errorf("Thoughts() of an empty response = %q, want empty", got)
+	}
+}
+
+func TestAppendFunctionResponses(t *testing.T) {
+	history := Text("What is the weather in Paris?")
+	resp := thinkingResponse()
+	contents := AppendFunctionResponses(history, resp, &FunctionResponse{Name: "weather", Response: map[string]any{"output": "sunny"}})
+	if len(contents) != 3 || len(history) != 1 {
+		t.Fatalf("AppendFunctionResponses() = %d contents from %d, want 3 from 1", len(contents), len(history))
+	}
+	if diff := cmp.Diff([]byte("sig1"), contents[1].Parts[2].ThoughtSignature); diff != "" {
+		t.Errorf("model turn signature mismatch (-want +got):\n%s", diff)
+	}
+	if got := contents[2]; got.Role != RoleUser || got.Parts[0].FunctionResponse.Name != "weather" {
+		t.Errorf("user turn = %+v, want the function response", got)
+	}
+}
+
+func TestEnsureThoughtSignatures(t *testing.T) {
+	call := &Part{FunctionCall: &FunctionCall{Name: "weather"}}
+	signed := &Content{Role: RoleModel, Parts: []*Part{{FunctionCall: &FunctionCall{Name: "a"}, ThoughtSignature: []byte("sig")}, {FunctionCall: &FunctionCall{Name: "b"}}}}
+	contents := []*Content{
+		NewContentFromText("Hi", RoleUser),
+		{Role: RoleModel, Parts: []*Part{NewPartFromText("Checking."), call, {FunctionCall: &FunctionCall{Name: "time"}}}},
+		signed,
+		NewContentFromText("Plain answer.", RoleModel),
+	}
+	got := EnsureThoughtSignatures(contents)
+	if diff := cmp.Diff(SkipThoughtSignatureValidation, got[1].Parts[1].ThoughtSignature); diff != "" {
+		t.Errorf("signature of the first call mismatch (-want +got):\n%s", diff)
+	}
+	if got[1].Parts[2].ThoughtSignature != nil || got[1].Parts[0].ThoughtSignature != nil {
+		t.Errorf("EnsureThoughtSignatures() signed other parts: %+v", got[1].Parts)
+	}
+	if got[2] != signed || got[3] != contents[3] {
+		t.Error("EnsureThoughtSignatures() changed turns that need no signature")
+	}
+	if call.ThoughtSignature != nil {
+		t.Error("EnsureThoughtSignatures() modified contents")
+	}
+}
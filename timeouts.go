@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeoutError is returned when a request exceeds RequestOptions.ConnectTimeout
+// or a stream exceeds RequestOptions.StreamIdleTimeout. It matches
+// context.DeadlineExceeded with errors.Is. Requests that exceed the connect
+// timeout are retried like network errors, and interaction streams that
+// exceed the idle timeout are resumed, see [StreamResumePolicy].
+type TimeoutError struct {
+	// Option is the name of the exceeded option, "ConnectTimeout" or
+	// "StreamIdleTimeout".
+	Option string
+	// Timeout is the value of the option.
+	Timeout time.Duration
+}
+
+// Error returns a string representation of the TimeoutError.
+func (e *TimeoutError) Error() string {
+	if e.Option == "StreamIdleTimeout" {
+		return fmt.Sprintf("no stream event within RequestOptions.StreamIdleTimeout of %v", e.Timeout)
+	}
+	return fmt.Sprintf("no response within RequestOptions.ConnectTimeout of %v", e.Timeout)
+}
+
+// Is reports whether target is context.DeadlineExceeded.
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// callTimeouts enforces the timeouts of an attempt of a request. A nil
+// *callTimeouts enforces none.
+type callTimeouts struct {
+	ctx          context.Context
+	cancel       context.CancelCauseFunc
+	stopDeadline context.CancelFunc
+	connectTimer *time.Timer
+	idle         time.Duration
+	idleTimer    *time.Timer
+}
+
+// newCallTimeouts returns the context of an attempt of a request with the
+// timeouts of httpOptions and options. The deadline of unary calls is
+// RequestTimeout, or Timeout if it is not set, and the deadline of streams is Timeout, so that
+// long streams are only bounded by StreamIdleTimeout. ConnectTimeout bounds
+// the time until the response headers are received.
+func newCallTimeouts(ctx context.Context, httpOptions *HTTPOptions, options RequestOptions, stream bool) (context.Context, *callTimeouts) {
+	t := &callTimeouts{stopDeadline: func() {}}
+	timeout := httpOptions.Timeout
+	if !stream && options.RequestTimeout != nil {
+		timeout = options.RequestTimeout
+	}
+	if timeout != nil && *timeout > 0 && isTimeoutBeforeDeadline(ctx, *timeout) {
+		ctx, t.stopDeadline = context.WithTimeout(ctx, *timeout)
+	}
+	t.ctx, t.cancel = context.WithCancelCause(ctx)
+	if d := options.ConnectTimeout; d != nil && *d > 0 {
+		err := &TimeoutError{Option: "ConnectTimeout", Timeout: *d}
+		t.connectTimer = time.AfterFunc(*d, func() { t.cancel(err) })
+	}
+	if d := options.StreamIdleTimeout; stream && d != nil && *d > 0 {
+		t.idle = *d
+	}
+	return t.ctx, t
+}
+
+// responded stops the connect timeout once the response headers are received
+// and starts the idle timeout of a stream.
+func (t *callTimeouts) responded() {
+	if t == nil {
+		return
+	}
+	if t.connectTimer != nil {
+		t.connectTimer.Stop()
+	}
+	if t.idle > 0 {
+		err := &TimeoutError{Option: "StreamIdleTimeout", Timeout: t.idle}
+		t.idleTimer = time.AfterFunc(t.idle, func() { t.cancel(err) })
+	}
+}
+
+// resetIdle restarts the idle timeout of a stream after an event.
+func (t *callTimeouts) resetIdle() {
+	if t != nil && t.idleTimer != nil {
+		t.idleTimer.Reset(t.idle)
+	}
+}
+
+// release stops the timeouts and releases the context of the attempt.
+func (t *callTimeouts) release() {
+	if t == nil {
+		return
+	}
+	if t.connectTimer != nil {
+		t.connectTimer.Stop()
+	}
+	if t.idleTimer != nil {
+		t.idleTimer.Stop()
+	}
+	t.cancel(nil)
+	t.stopDeadline()
+}
+
+// cause returns the *TimeoutError that cancelled the attempt if err is due to
+// it, or err otherwise.
+func (t *callTimeouts) cause(err error) error {
+	if t == nil || err == nil {
+		return err
+	}
+	var timeoutErr *TimeoutError
+	if errors.As(context.Cause(t.ctx), &timeoutErr) {
+		return timeoutErr
+	}
+	return err
+}
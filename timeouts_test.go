@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTimeouts(t *testing.T) {
+	ctx := context.Background()
+	// streamHandler sends events chunks, waiting for the delays before them.
+	streamHandler := func(delays ...time.Duration) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for i, delay := range delays {
+				select {
+				case <-r.Context().Done():
+					return
+				case <-time.After(delay):
+				}
+				fmt.Fprintf(w, "data: {\"candidates\":[{\"content\":{\"parts\":[{\"text\":\"%d\"}]}}]}\n\n", i)
+				w.(http.Flusher).Flush()
+			}
+		}
+	}
+	stream := func(client *Client) (int, error) {
+		chunks := 0
+		for _, err := range client.Models.GenerateContentStream(ctx, "gemini-2.0-flash", Text("hi"), nil) {
+			if err != nil {
+				return chunks, err
+			}
+			chunks++
+		}
+		return chunks, nil
+	}
+	// slowHandler responds after latency, or gives up when the test ends.
+	slowHandler := func(t *testing.T, latency time.Duration) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-t.Context().Done():
+				return
+			case <-time.After(latency):
+			}
+			w.Write([]byte(`{"candidates":[{"content":{"parts":[{"text":"ok"}]}}]}`))
+		}
+	}
+
+	t.Run("ConnectTimeout", func(t *testing.T) {
+		client := newTestClient(t, &ClientConfig{RequestOptions: RequestOptions{ConnectTimeout: Ptr(50 * time.Millisecond)}}, slowHandler(t, time.Second))
+		start := time.Now()
+		_, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash", Text("hi"), nil)
+		var timeoutErr *TimeoutError
+		if !errors.As(err, &timeoutErr) || timeoutErr.Option != "ConnectTimeout" {
+			t.Fatalf("GenerateContent() error = %v, want a ConnectTimeout *TimeoutError", err)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("errors.Is(%v, context.DeadlineExceeded) = false, want true", err)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("GenerateContent() took %v, want it to fail after the connect timeout", elapsed)
+		}
+	})
+
+	t.Run("ConnectTimeout is retried", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, &ClientConfig{RequestOptions: RequestOptions{
+			ConnectTimeout: Ptr(50 * time.Millisecond),
+			RetryOptions:   &RetryOptions{MaxAttempts: 2, InitialInterval: time.Millisecond, Jitter: Ptr(0.0)},
+		}}, func(w http.ResponseWriter, r *http.Request) {
+			latency := time.Duration(0)
+			if requests.Add(1) == 1 {
+				latency = time.Second
+			}
+			slowHandler(t, latency)(w, r)
+		})
+		if _, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash", Text("hi"), nil); err != nil {
+			t.Fatalf("GenerateContent() failed: %v", err)
+		}
+		if got := requests.Load(); got != 2 {
+			t.Errorf("server got %d requests, want 2", got)
+		}
+	})
+
+	t.Run("ConnectTimeout does not cut off the body", func(t *testing.T) {
+		client := newTestClient(t, &ClientConfig{RequestOptions: RequestOptions{ConnectTimeout: Ptr(50 * time.Millisecond)}}, streamHandler(0, 60*time.Millisecond, 60*time.Millisecond))
+		if chunks, err := stream(client); err != nil || chunks != 3 {
+			t.Errorf("stream() = (%d, %v), want (3, nil)", chunks, err)
+		}
+	})
+
+	t.Run("RequestTimeout", func(t *testing.T) {
+		client := newTestClient(t, &ClientConfig{RequestOptions: RequestOptions{RequestTimeout: Ptr(50 * time.Millisecond)}}, slowHandler(t, time.Second))
+		_, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash", Text("hi"), nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("GenerateContent() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+
+	t.Run("RequestTimeout does not apply to streams", func(t *testing.T) {
+		client := newTestClient(t, &ClientConfig{RequestOptions: RequestOptions{RequestTimeout: Ptr(50 * time.Millisecond)}}, streamHandler(0, 40*time.Millisecond, 40*time.Millisecond))
+		if chunks, err := stream(client); err != nil || chunks != 3 {
+			t.Errorf("stream() = (%d, %v), want (3, nil)", chunks, err)
+		}
+	})
+
+	t.Run("Timeout bounds the whole stream", func(t *testing.T) {
+		client := newTestClient(t, &ClientConfig{HTTPOptions: HTTPOptions{Timeout: Ptr(200 * time.Millisecond)}}, streamHandler(0, 30*time.Millisecond, time.Second))
+		chunks, err := stream(client)
+		if chunks != 2 || !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("stream() = (%d, %v), want (2, context.DeadlineExceeded)", chunks, err)
+		}
+	})
+
+	t.Run("StreamIdleTimeout resets on every event", func(t *testing.T) {
+		client := newTestClient(t, &ClientConfig{RequestOptions: RequestOptions{StreamIdleTimeout: Ptr(100 * time.Millisecond)}}, streamHandler(0, 40*time.Millisecond, 40*time.Millisecond, 40*time.Millisecond))
+		if chunks, err := stream(client); err != nil || chunks != 4 {
+			t.Errorf("stream() = (%d, %v), want (4, nil)", chunks, err)
+		}
+	})
+
+	t.Run("StreamIdleTimeout fails stalled streams", func(t *testing.T) {
+		client := newTestClient(t, &ClientConfig{RequestOptions: RequestOptions{StreamIdleTimeout: Ptr(50 * time.Millisecond)}}, streamHandler(0, 5*time.Second))
+		start := time.Now()
+		chunks, err := stream(client)
+		var timeoutErr *TimeoutError
+		if chunks != 1 || !errors.As(err, &timeoutErr) || timeoutErr.Option != "StreamIdleTimeout" {
+			t.Fatalf("stream() = (%d, %v), want (1, a StreamIdleTimeout *TimeoutError)", chunks, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("stream() took %v, want it to fail after the idle timeout", elapsed)
+		}
+		if !isTransientError(err) {
+			t.Errorf("isTransientError(%v) = false, want true", err)
+		}
+	})
+
+	t.Run("Request options override client options", func(t *testing.T) {
+		client := newTestClient(t, &ClientConfig{RequestOptions: RequestOptions{RequestTimeout: Ptr(20 * time.Millisecond)}}, slowHandler(t, 100*time.Millisecond))
+		callCtx := WithRequestOptions(ctx, &RequestOptions{RequestTimeout: Ptr(time.Duration(0))})
+		if _, err := client.Models.GenerateContent(callCtx, "gemini-2.0-flash", Text("hi"), nil); err != nil {
+			t.Errorf("GenerateContent() with a zero RequestTimeout failed: %v", err)
+		}
+	})
+}
@@ -26,6 +26,8 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 
 	sentencepiece "github.com/eliben/go-sentencepiece"
@@ -82,8 +84,13 @@ var tokenizers = map[string]tokenizerConfig{
 	},
 }
 
-// getLocalTokenizerName returns the tokenizer name for the given model name
+// getLocalTokenizerName returns the tokenizer name for the given model name.
+// Resource names such as "models/gemini-2.5-flash" or
+// "publishers/google/models/gemini-2.5-flash" are resolved to the model ID.
 func getLocalTokenizerName(modelName string) (string, error) {
+	if i := strings.LastIndex(modelName, "models/"); i >= 0 {
+		modelName = modelName[i+len("models/"):]
+	}
 	if tokenizerName, ok := geminiModelsToLocalTokenizerNames[modelName]; ok {
 		return tokenizerName, nil
 	}
@@ -100,6 +107,8 @@ func getLocalTokenizerName(modelName string) (string, error) {
 		supportedModels = append(supportedModels, model)
 	}
 
+	sort.Strings(supportedModels)
+
 	return "", fmt.Errorf("model %s is not supported. Supported models: %v", modelName, supportedModels)
 }
 
@@ -119,7 +128,7 @@ func NewLocalTokenizer(modelName string) (*LocalTokenizer, error) {
 
 	tokenizerName, err := getLocalTokenizerName(modelName)
 	if err != nil {
-		return nil, fmt.Errorf("model %s is not supported", modelName)
+		return nil, err
 	}
 
 	config, ok := tokenizers[tokenizerName]
@@ -366,6 +375,9 @@ func downloadModelFile(url string) ([]byte, error) {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
 
 	return io.ReadAll(resp.Body)
 }
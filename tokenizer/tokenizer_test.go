@@ -15,6 +15,8 @@ package tokenizer
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
@@ -431,3 +433,35 @@ func TestComputeTokensEmptyContent(t *testing.T) {
 		t.Errorf("expected empty TokensInfo for nil content, got %v entries", len(got.TokensInfo))
 	}
 }
+
+func TestLocalTokenizerName(t *testing.T) {
+	tests := []struct {
+		model string
+		want  string
+	}{
+		{model: "gemini-1.5-flash", want: "gemma2"},
+		{model: "gemini-2.0-flash-001", want: "gemma3"},
+		{model: "models/gemini-2.5-flash", want: "gemma3"},
+		{model: "publishers/google/models/gemini-2.5-pro", want: "gemma3"},
+		{model: "projects/p/locations/us-central1/publishers/google/models/gemini-2.0-flash", want: "gemma3"},
+	}
+	for _, tt := range tests {
+		got, err := getLocalTokenizerName(tt.model)
+		if err != nil {
+			t.Errorf("getLocalTokenizerName(%q) failed: %v", tt.model, err)
+		} else if got != tt.want {
+			t.Errorf("getLocalTokenizerName(%q) = %q, want %q", tt.model, got, tt.want)
+		}
+	}
+	if _, err := getLocalTokenizerName("models/gemini-0.92"); err == nil {
+		t.Errorf("getLocalTokenizerName() of an unsupported model succeeded, want error")
+	}
+}
+
+func TestModelFileDownloadStatus(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	if _, err := downloadModelFile(server.URL + "/tokenizer.model"); err == nil {
+		t.Errorf("downloadModelFile() of a missing file succeeded, want error")
+	}
+}
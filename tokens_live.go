@@ -0,0 +1,33 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+// ClientConfig returns the config of a client that authenticates with the
+// ephemeral token t instead of an API key, e.g. for a front end written in Go
+// that connects to the Live API with a token created by its backend with
+// client.AuthTokens.Create:
+//
+//	client, err := genai.NewClient(ctx, token.ClientConfig())
+//	session, err := client.Live.Connect(ctx, model, config)
+//
+// Ephemeral tokens are only supported by the v1alpha version of the Gemini
+// API, which the config selects.
+func (t *AuthToken) ClientConfig() *ClientConfig {
+	return &ClientConfig{
+		Backend:     BackendGeminiAPI,
+		APIKey:      t.Name,
+		HTTPOptions: HTTPOptions{APIVersion: "v1alpha"},
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/gorilla/websocket"
+)
+
+func TestAuthTokenLiveConnect(t *testing.T) {
+	ctx := context.Background()
+	var body map[string]any
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1beta/auth_tokens" {
+			t.Errorf("path = %s, want /v1beta/auth_tokens", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"name": "auth_tokens/abc"}`)
+	})
+	uses := int32(1)
+	expireTime := time.Date(2025, 1, 1, 0, 30, 0, 0, time.UTC)
+	token, err := client.AuthTokens.Create(ctx, &CreateAuthTokenConfig{
+		Uses:       &uses,
+		ExpireTime: expireTime,
+		LiveConnectConstraints: &LiveConnectConstraints{
+			Model:  "gemini-live-2.5-flash",
+			Config: &LiveConnectConfig{ResponseModalities: []Modality{ModalityAudio}},
+		},
+		LockAdditionalFields: []string{},
+	})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	wantBody := map[string]any{
+		"uses":       float64(1),
+		"expireTime": "2025-01-01T00:30:00Z",
+		"bidiGenerateContentSetup": map[string]any{
+			"model":            "models/gemini-live-2.5-flash",
+			"generationConfig": map[string]any{"responseModalities": []any{"AUDIO"}},
+		},
+	}
+	// The order of the field mask follows the order of a map.
+	fieldMask, _ := body["fieldMask"].(string)
+	delete(body, "fieldMask")
+	if diff := cmp.Diff([]string{"generationConfig.responseModalities", "model"}, strings.Split(fieldMask, ","), cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("field mask mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantBody, body); diff != "" {
+		t.Errorf("request body mismatch (-want +got):\n%s", diff)
+	}
+
+	var authorization, path string
+	var upgrader websocket.Upgrader
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization, path = r.Header.Get("Authorization"), r.URL.Path
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.ReadMessage()
+	}))
+	defer ts.Close()
+	config := token.ClientConfig()
+	config.HTTPOptions.BaseURL = strings.Replace(ts.URL, "http", "ws", 1)
+	config.HTTPClient = ts.Client()
+	liveClient, err := NewClient(ctx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := liveClient.Live.Connect(ctx, "gemini-live-2.5-flash", nil)
+	if err != nil {
+		t.Fatalf("Connect() failed: %v", err)
+	}
+	defer session.Close()
+	if want := "Token auth_tokens/abc"; authorization != want {
+		t.Errorf("Authorization = %q, want %q", authorization, want)
+	}
+	if want := "/ws/google.ai.generativelanguage.v1alpha.GenerativeService.BidiGenerateContentConstrained"; path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ToolPolicy restricts what a tool may do. The executors of function calls,
+// FunctionRegistry with its Policy and the agent package, check the arguments
+// chosen by the model before the tool runs and the result after it returns; a
+// violation is reported to the model as a failed tool call with a
+// *ToolPolicyError.
+//
+// Argument checks only see the paths and URLs that the model passes. Tools
+// that derive paths or open connections themselves should also call
+// [ToolPolicy.CheckPath] and [ToolPolicy.CheckURL], or use
+// [ToolPolicy.Transport], with the policy returned by [ToolPolicyFromContext].
+type ToolPolicy struct {
+	// Optional. Paths that tools must not access. A string argument that
+	// looks like a file path (it starts with "/", "./", "../" or "~/") is
+	// rejected if it is one of these paths or inside one of them.
+	DeniedPaths []string
+	// Optional. If set, the hosts that tools may connect to. A string argument
+	// that is an absolute URL is rejected unless its host is listed. An entry
+	// "*.example.com" allows the subdomains of example.com.
+	AllowedHosts []string
+	// Optional. The maximum time a tool may run. The context of the tool is
+	// cancelled when it expires, and the call fails even if the tool ignores
+	// the context.
+	MaxDuration time.Duration
+	// Optional. The maximum size of the JSON encoding of a tool result.
+	MaxOutputBytes int
+	// Optional. A custom check of the call, run after the built-in argument
+	// checks.
+	Check func(ctx context.Context, name string, args map[string]any) error
+}
+
+// ToolPolicyError is the error of a tool call that violated a ToolPolicy.
+type ToolPolicyError struct {
+	// Tool is the name of the tool.
+	Tool string
+	// Reason describes the violation.
+	Reason string
+}
+
+func (e *ToolPolicyError) Error() string {
+	return fmt.Sprintf("tool %q violates the policy: %s", e.Tool, e.Reason)
+}
+
+type toolPolicyKey struct{}
+
+// ToolPolicyFromContext returns the policy of the running tool, or nil if the
+// tool has no policy.
+func ToolPolicyFromContext(ctx context.Context) *ToolPolicy {
+	p, _ := ctx.Value(toolPolicyKey{}).(*ToolPolicy)
+	return p
+}
+
+// CheckPath returns an error if path is one of the DeniedPaths or inside one
+// of them. Relative paths are resolved against the working directory.
+func (p *ToolPolicy) CheckPath(path string) error {
+	if p == nil || len(p.DeniedPaths) == 0 {
+		return nil
+	}
+	abs, err := absPath(path)
+	if err != nil {
+		return fmt.Errorf("invalid path %q: %w", path, err)
+	}
+	for _, denied := range p.DeniedPaths {
+		d, err := absPath(denied)
+		if err != nil {
+			continue
+		}
+		prefix := d
+		if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+			prefix += string(filepath.Separator)
+		}
+		if abs == d || strings.HasPrefix(abs, prefix) {
+			return fmt.Errorf("access to %s is denied", path)
+		}
+	}
+	return nil
+}
+
+// CheckURL returns an error if AllowedHosts is set and does not include the
+// host of rawURL.
+func (p *ToolPolicy) CheckURL(rawURL string) error {
+	if p == nil || len(p.AllowedHosts) == 0 {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	if !p.hostAllowed(u.Hostname()) {
+		return fmt.Errorf("network access to %s is not allowed", u.Hostname())
+	}
+	return nil
+}
+
+func (p *ToolPolicy) hostAllowed(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range p.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// Transport returns an http.RoundTripper that rejects requests to hosts that
+// the policy does not allow and sends the others with base, or
+// http.DefaultTransport if base is nil.
+func (p *ToolPolicy) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if err := p.CheckURL(req.URL.String()); err != nil {
+			return nil, err
+		}
+		return base.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func absPath(path string) (string, error) {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	}
+	return filepath.Abs(path)
+}
+
+// checkArguments applies the path and URL checks to every string in args.
+func (p *ToolPolicy) checkArguments(args any) error {
+	switch v := args.(type) {
+	case string:
+		if looksLikePath(v) {
+			if err := p.CheckPath(v); err != nil {
+				return err
+			}
+		}
+		if looksLikeURL(v) {
+			return p.CheckURL(v)
+		}
+	case map[string]any:
+		for _, key := range sortedKeys(v) {
+			if err := p.checkArguments(v[key]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := p.checkArguments(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func looksLikePath(s string) bool {
+	for _, prefix := range []string{"/", "./", "../", "~/"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return s == "." || s == ".."
+}
+
+func looksLikeURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// Run calls fn, the function of the tool name, with args under the policy. The
+// arguments are checked before fn is called, with the policy in the context of
+// fn, and its result after it returns. A nil policy just calls fn.
+func (p *ToolPolicy) Run(ctx context.Context, name string, args map[string]any, fn func(ctx context.Context, args map[string]any) (any, error)) (any, error) {
+	if p == nil {
+		return fn(ctx, args)
+	}
+	if err := p.checkArguments(args); err != nil {
+		return nil, &ToolPolicyError{Tool: name, Reason: err.Error()}
+	}
+	if p.Check != nil {
+		if err := p.Check(ctx, name, args); err != nil {
+			return nil, &ToolPolicyError{Tool: name, Reason: err.Error()}
+		}
+	}
+	ctx = context.WithValue(ctx, toolPolicyKey{}, p)
+
+	var result any
+	var err error
+	if p.MaxDuration > 0 {
+		result, err = runWithTimeout(ctx, name, p.MaxDuration, args, fn)
+	} else {
+		result, err = fn(ctx, args)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.MaxOutputBytes > 0 {
+		b, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool result: %w", err)
+		}
+		if len(b) > p.MaxOutputBytes {
+			return nil, &ToolPolicyError{Tool: name, Reason: fmt.Sprintf("the result has %d bytes, more than the limit of %d", len(b), p.MaxOutputBytes)}
+		}
+	}
+	return result, nil
+}
+
+func runWithTimeout(parent context.Context, name string, timeout time.Duration, args map[string]any, fn func(ctx context.Context, args map[string]any) (any, error)) (any, error) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	type outcome struct {
+		result any
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := fn(ctx, args)
+		done <- outcome{result, err}
+	}()
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		if err := parent.Err(); err != nil {
+			return nil, err
+		}
+		return nil, &ToolPolicyError{Tool: name, Reason: fmt.Sprintf("did not finish within %v", timeout)}
+	}
+}
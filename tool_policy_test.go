@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToolPolicy(t *testing.T) {
+	ctx := context.Background()
+	echo := func(ctx context.Context, args map[string]any) (any, error) { return args, nil }
+	policy := &ToolPolicy{
+		DeniedPaths:    []string{"/etc"},
+		AllowedHosts:   []string{"*.example.com"},
+		MaxDuration:    50 * time.Millisecond,
+		MaxOutputBytes: 80,
+	}
+	tests := []struct {
+		name       string
+		args       map[string]any
+		fn         func(ctx context.Context, args map[string]any) (any, error)
+		wantReason string
+	}{
+		{name: "allowed", args: map[string]any{"path": "/tmp/a", "url": "https://api.example.com/x"}},
+		{name: "denied path", args: map[string]any{"files": []any{"/tmp/a", "/etc/passwd"}}, wantReason: "access to /etc/passwd is denied"},
+		{name: "denied path traversal", args: map[string]any{"path": "/tmp/../etc/shadow"}, wantReason: "is denied"},
+		{name: "denied host", args: map[string]any{"url": "https://evil.test/"}, wantReason: "network access to evil.test is not allowed"},
+		{name: "output too large", args: map[string]any{"text": strings.Repeat("x", 100)}, wantReason: "more than the limit of 80"},
+		{
+			name: "timeout",
+			fn: func(ctx context.Context, args map[string]any) (any, error) {
+				time.Sleep(time.Second) // Ignores the context.
+				return nil, nil
+			},
+			wantReason: "did not finish within 50ms",
+		},
+		{
+			name: "policy in context",
+			fn: func(ctx context.Context, args map[string]any) (any, error) {
+				return nil, ToolPolicyFromContext(ctx).CheckPath("/etc/hosts")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := tt.fn
+			if fn == nil {
+				fn = echo
+			}
+			_, err := policy.Run(ctx, "t", tt.args, fn)
+			var pe *ToolPolicyError
+			switch {
+			case tt.name == "policy in context":
+				if err == nil || !strings.Contains(err.Error(), "denied") {
+					t.Errorf("Run() error = %v, want the tool's own path check to fail", err)
+				}
+			case tt.wantReason == "":
+				if err != nil {
+					t.Errorf("Run() failed: %v", err)
+				}
+			case !errors.As(err, &pe) || !strings.Contains(pe.Reason, tt.wantReason):
+				t.Errorf("Run() error = %v, want a policy error with %q", err, tt.wantReason)
+			}
+		})
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://evil.test/", nil)
+	if _, err := policy.Transport(nil).RoundTrip(req); err == nil {
+		t.Error("Transport() sent a request to a host that is not allowed")
+	}
+}
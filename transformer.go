@@ -26,8 +26,14 @@ func tResourceName(ac *apiClient, resourceName string, collectionIdentifier stri
 	shouldPrependCollectionIdentifier := !strings.HasPrefix(resourceName, collectionIdentifier+"/") &&
 		strings.Count(collectionIdentifier+"/"+resourceName, "/")+1 == collectionHierarchyDepth
 
-	switch ac.clientConfig.Backend {
-	case BackendVertexAI:
+	switch {
+	case ac.expressMode():
+		// Express mode resources are addressed without a project.
+		if shouldPrependCollectionIdentifier {
+			return fmt.Sprintf("%s/%s", collectionIdentifier, resourceName)
+		}
+		return resourceName
+	case ac.clientConfig.Backend == BackendVertexAI:
 		if strings.HasPrefix(resourceName, "projects/") {
 			return resourceName
 		} else if strings.HasPrefix(resourceName, "locations/") {
@@ -55,6 +61,7 @@ func tCachedContentName(ac *apiClient, name any) (string, error) {
 func tModel(ac *apiClient, origin any) (string, error) {
 	switch model := origin.(type) {
 	case string:
+		model = ac.resolveModel(model)
 		if model == "" {
 			return "", fmt.Errorf("tModel: model is empty")
 		}
@@ -62,7 +69,12 @@ func tModel(ac *apiClient, origin any) (string, error) {
 			return "", fmt.Errorf("tModel: invalid model parameter")
 		}
 		if ac.clientConfig.Backend == BackendVertexAI {
-			if strings.HasPrefix(model, "projects/") || strings.HasPrefix(model, "models/") || strings.HasPrefix(model, "publishers/") {
+			if rest, ok := strings.CutPrefix(model, "models/"); ok && ac.expressMode() {
+				// Express mode has no tuned models, so "models/" names a
+				// Google model.
+				return fmt.Sprintf("publishers/google/models/%s", rest), nil
+			}
+			if strings.HasPrefix(model, "projects/") || strings.HasPrefix(model, "models/") || strings.HasPrefix(model, "publishers/") || strings.HasPrefix(model, "endpoints/") {
 				return model, nil
 			} else if strings.Contains(model, "/") {
 				parts := strings.SplitN(model, "/", 2)
@@ -89,7 +101,9 @@ func tModelFullName(ac *apiClient, origin any) (string, error) {
 		if err != nil {
 			return "", fmt.Errorf("tModelFullName: %w", err)
 		}
-		if strings.HasPrefix(name, "publishers/") && ac.clientConfig.Backend == BackendVertexAI {
+		if ac.expressMode() {
+			return name, nil
+		} else if (strings.HasPrefix(name, "publishers/") || strings.HasPrefix(name, "endpoints/")) && ac.clientConfig.Backend == BackendVertexAI {
 			return fmt.Sprintf("projects/%s/locations/%s/%s", ac.clientConfig.Project, ac.clientConfig.Location, name), nil
 		} else if strings.HasPrefix(name, "models/") && ac.clientConfig.Backend == BackendVertexAI {
 			return fmt.Sprintf("projects/%s/locations/%s/publishers/google/%s", ac.clientConfig.Project, ac.clientConfig.Location, name), nil
@@ -102,7 +116,7 @@ func tModelFullName(ac *apiClient, origin any) (string, error) {
 }
 
 func tCachesModel(ac *apiClient, origin any) (string, error) {
-	return tModelFullName(ac, origin)
+	return tModelFullName(ac, cacheModelName(ac, origin))
 }
 
 func tContent(content any) (any, error) {
@@ -65,6 +65,27 @@ func TestModelTransformer(t *testing.T) {
 			wantFullName: "projects/test-project/locations/test-location/publishers/google/models/gemini-2.5-flash",
 		},
 
+		{
+			name:         "VertexAI_Endpoint",
+			backend:      BackendVertexAI,
+			input:        "endpoints/123",
+			want:         "endpoints/123",
+			wantFullName: "projects/test-project/locations/test-location/endpoints/123",
+		},
+		{
+			name:         "VertexAI_Endpoint_Project_Prefix",
+			backend:      BackendVertexAI,
+			input:        "projects/test-project/locations/europe-west4/endpoints/123",
+			want:         "projects/test-project/locations/europe-west4/endpoints/123",
+			wantFullName: "projects/test-project/locations/europe-west4/endpoints/123",
+		},
+		{
+			name:         "VertexAI_TunedModel",
+			backend:      BackendVertexAI,
+			input:        "projects/test-project/locations/test-location/models/456@1",
+			want:         "projects/test-project/locations/test-location/models/456@1",
+			wantFullName: "projects/test-project/locations/test-location/models/456@1",
+		},
 		{
 			name:         "GoogleAI_Model_Short",
 			backend:      BackendGeminiAPI,
@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// TuningDatasetConfig configures a TuningDatasetBuilder.
+type TuningDatasetConfig struct {
+	// Optional. The minimum number of examples. Defaults to 1.
+	MinExamples int
+	// Optional. The maximum number of examples. If zero, the number is not
+	// limited.
+	MaxExamples int
+	// Optional. The maximum number of contents of a conversation, including
+	// the responses of the model. If zero, the number is not limited.
+	MaxTurns int
+	// Optional. The maximum number of tokens of an example, counted with
+	// Models.CountTokens for Model. If zero, the tokens are not counted.
+	MaxTokensPerExample int32
+	// Optional. The model whose tokenizer counts the tokens of the examples,
+	// usually the base model of the tuning job. Required if
+	// MaxTokensPerExample is set.
+	Model string
+	// Optional. Stages the JSONL file of the dataset on Vertex AI, which reads
+	// tuning datasets from Cloud Storage. Its Stage method receives the file as
+	// a Blob of MIME type "application/jsonl" and must return its "gs://" URI.
+	// Required on Vertex AI.
+	Stager MediaStager
+}
+
+// TuningDatasetBuilder builds a supervised tuning dataset from examples,
+// validates it and prepares it for Tunings.Tune: on the Gemini API the
+// examples are inlined in the dataset, on Vertex AI they are written as a
+// JSONL file that is staged in Cloud Storage.
+type TuningDatasetBuilder struct {
+	models   Models
+	backend  Backend
+	config   TuningDatasetConfig
+	examples []*tuningConversation
+}
+
+// tuningConversation is an example of a TuningDatasetBuilder.
+type tuningConversation struct {
+	SystemInstruction *Content   `json:"systemInstruction,omitempty"`
+	Contents          []*Content `json:"contents"`
+	// The example, if it was added with AddExample.
+	example *TuningExample
+}
+
+// NewTuningDatasetBuilder returns an empty TuningDatasetBuilder for the
+// backend of client. config may be nil.
+func NewTuningDatasetBuilder(client *Client, config *TuningDatasetConfig) *TuningDatasetBuilder {
+	b := &TuningDatasetBuilder{models: *client.Models, backend: client.clientConfig.Backend}
+	if config != nil {
+		b.config = *config
+	}
+	if b.config.MinExamples <= 0 {
+		b.config.MinExamples = 1
+	}
+	return b
+}
+
+// AddExample adds an example of a single-turn text input and its expected
+// output, which is supported by both backends.
+func (b *TuningDatasetBuilder) AddExample(example *TuningExample) {
+	b.examples = append(b.examples, &tuningConversation{
+		Contents: []*Content{NewContentFromText(example.TextInput, RoleUser), NewContentFromText(example.Output, RoleModel)},
+		example:  example,
+	})
+}
+
+// AddConversation adds an example of a conversation that alternates between
+// the user and the model, from the first message of the user to the last
+// response of the model, which is the expected output. systemInstruction may
+// be nil. Conversations are only supported by Vertex AI.
+func (b *TuningDatasetBuilder) AddConversation(systemInstruction *Content, contents ...*Content) {
+	b.examples = append(b.examples, &tuningConversation{SystemInstruction: systemInstruction, Contents: contents})
+}
+
+// Len returns the number of added examples.
+func (b *TuningDatasetBuilder) Len() int {
+	return len(b.examples)
+}
+
+// Validate checks the number of examples, the roles and the number of turns
+// of every conversation and, if config.MaxTokensPerExample is set, the number
+// of tokens of every example. It returns all the problems found, joined in a
+// single error.
+func (b *TuningDatasetBuilder) Validate(ctx context.Context) error {
+	var errs []error
+	switch n := len(b.examples); {
+	case n < b.config.MinExamples:
+		errs = append(errs, fmt.Errorf("the dataset has %d examples, at least %d are required", n, b.config.MinExamples))
+	case b.config.MaxExamples > 0 && n > b.config.MaxExamples:
+		errs = append(errs, fmt.Errorf("the dataset has %d examples, at most %d are allowed", n, b.config.MaxExamples))
+	}
+	if b.config.MaxTokensPerExample > 0 && b.config.Model == "" {
+		errs = append(errs, errors.New("TuningDatasetConfig.Model is required to count the tokens of the examples"))
+	}
+	for i, example := range b.examples {
+		if err := b.validateExample(ctx, example); err != nil {
+			errs = append(errs, fmt.Errorf("example %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *TuningDatasetBuilder) validateExample(ctx context.Context, example *tuningConversation) error {
+	if b.backend != BackendVertexAI && example.example == nil {
+		return errors.New("conversations are not supported by the Gemini API, add examples with AddExample")
+	}
+	contents := example.Contents
+	switch {
+	case len(contents) < 2:
+		return fmt.Errorf("a conversation needs a message of the user and a response of the model, got %d contents", len(contents))
+	case b.config.MaxTurns > 0 && len(contents) > b.config.MaxTurns:
+		return fmt.Errorf("the conversation has %d contents, at most %d are allowed", len(contents), b.config.MaxTurns)
+	}
+	for i, content := range contents {
+		want := RoleUser
+		if i%2 == 1 {
+			want = RoleModel
+		}
+		switch {
+		case content == nil || len(content.Parts) == 0:
+			return fmt.Errorf("content %d is empty", i)
+		case content.Role != string(want):
+			return fmt.Errorf("content %d has role %q, want %q: the conversation must alternate between the user and the model", i, content.Role, want)
+		case i == len(contents)-1 && want != RoleModel:
+			return errors.New("the conversation must end with the expected response of the model")
+		}
+		if example.example != nil && content.Parts[0].Text == "" {
+			return fmt.Errorf("content %d has no text", i)
+		}
+	}
+	if b.config.MaxTokensPerExample <= 0 || b.config.Model == "" {
+		return nil
+	}
+	resp, err := b.models.CountTokens(ctx, b.config.Model, contents, &CountTokensConfig{SystemInstruction: example.SystemInstruction})
+	if err != nil {
+		return fmt.Errorf("failed to count tokens: %w", err)
+	}
+	if resp.TotalTokens > b.config.MaxTokensPerExample {
+		return fmt.Errorf("the example has %d tokens, at most %d are allowed", resp.TotalTokens, b.config.MaxTokensPerExample)
+	}
+	return nil
+}
+
+// WriteJSONL writes the examples to w in the JSONL format of the tuning
+// datasets of Vertex AI, a conversation with its system instruction per
+// line.
+func (b *TuningDatasetBuilder) WriteJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for i, example := range b.examples {
+		if err := enc.Encode(example); err != nil {
+			return fmt.Errorf("failed to write example %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Build validates the examples and returns the dataset to pass to
+// Tunings.Tune. On the Gemini API the examples are inlined in the dataset. On
+// Vertex AI they are written as JSONL and staged with config.Stager, and the
+// dataset refers to the staged file.
+func (b *TuningDatasetBuilder) Build(ctx context.Context) (*TuningDataset, error) {
+	if err := b.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid tuning dataset: %w", err)
+	}
+	if b.backend != BackendVertexAI {
+		dataset := &TuningDataset{}
+		for _, example := range b.examples {
+			dataset.Examples = append(dataset.Examples, example.example)
+		}
+		return dataset, nil
+	}
+	if b.config.Stager == nil {
+		return nil, errors.New("TuningDatasetConfig.Stager is required on Vertex AI to stage the dataset in Cloud Storage")
+	}
+	var buf bytes.Buffer
+	if err := b.WriteJSONL(&buf); err != nil {
+		return nil, err
+	}
+	data, err := b.config.Stager.Stage(ctx, &Blob{Data: buf.Bytes(), MIMEType: "application/jsonl"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage the tuning dataset: %w", err)
+	}
+	return &TuningDataset{GCSURI: data.FileURI}, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTuningDatasetBuilderGeminiAPI(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Contents []*Content `json:"contents"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		// One token per character of the input.
+		fmt.Fprintf(w, `{"totalTokens": %d}`, len(body.Contents[0].Parts[0].Text))
+	})
+	builder := NewTuningDatasetBuilder(client, &TuningDatasetConfig{MinExamples: 2, MaxTokensPerExample: 10, Model: "gemini-2.5-flash"})
+	builder.AddExample(&TuningExample{TextInput: "1+1", Output: "2"})
+	if _, err := builder.Build(ctx); err == nil || !strings.Contains(err.Error(), "at least 2") {
+		t.Errorf("Build() of 1 example = %v, want an error about the minimum", err)
+	}
+	builder.AddExample(&TuningExample{TextInput: "2+2", Output: "4"})
+	dataset, err := builder.Build(ctx)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	want := &TuningDataset{Examples: []*TuningExample{{TextInput: "1+1", Output: "2"}, {TextInput: "2+2", Output: "4"}}}
+	if diff := cmp.Diff(want, dataset); diff != "" {
+		t.Errorf("Build() mismatch (-want +got):\n%s", diff)
+	}
+
+	builder.AddExample(&TuningExample{TextInput: "a very long input", Output: "x"})
+	builder.AddConversation(nil, NewContentFromText("Hi", RoleUser), NewContentFromText("Hello", RoleModel))
+	err = builder.Validate(ctx)
+	for _, problem := range []string{"example 2: the example has 17 tokens", "example 3: conversations are not supported"} {
+		if err == nil || !strings.Contains(err.Error(), problem) {
+			t.Errorf("Validate() = %v, want %q", err, problem)
+		}
+	}
+}
+
+func TestTuningDatasetBuilderVertexAI(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, &ClientConfig{Backend: BackendVertexAI}, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s", r.URL)
+	})
+	stager := &recordingStager{}
+	builder := NewTuningDatasetBuilder(client, &TuningDatasetConfig{MaxTurns: 4, Stager: stager})
+	builder.AddExample(&TuningExample{TextInput: "1+1", Output: "2"})
+	builder.AddConversation(NewContentFromText("Be brief.", RoleUser),
+		NewContentFromText("Hi", RoleUser), NewContentFromText("Hello", RoleModel),
+		NewContentFromText("1+1?", RoleUser), NewContentFromText("2", RoleModel))
+	dataset, err := builder.Build(ctx)
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+	if dataset.GCSURI != "gs://bucket/1" || len(stager.staged) != 1 || stager.staged[0].MIMEType != "application/jsonl" {
+		t.Fatalf("Build() = %+v after staging %v, want the staged JSONL", dataset, stager.staged)
+	}
+	lines := strings.Split(strings.TrimSpace(string(stager.staged[0].Data)), "\n")
+	want := []string{
+		`{"contents":[{"parts":[{"text":"1+1"}],"role":"user"},{"parts":[{"text":"2"}],"role":"model"}]}`,
+		`{"systemInstruction":{"parts":[{"text":"Be brief."}],"role":"user"},"contents":[{"parts":[{"text":"Hi"}],"role":"user"},{"parts":[{"text":"Hello"}],"role":"model"},{"parts":[{"text":"1+1?"}],"role":"user"},{"parts":[{"text":"2"}],"role":"model"}]}`,
+	}
+	if diff := cmp.Diff(want, lines); diff != "" {
+		t.Errorf("staged JSONL mismatch (-want +got):\n%s", diff)
+	}
+
+	tests := map[string][]*Content{
+		"must alternate": {NewContentFromText("Hi", RoleUser), NewContentFromText("Hi", RoleUser)},
+		"must end with":  {NewContentFromText("Hi", RoleUser), NewContentFromText("Hello", RoleModel), NewContentFromText("Bye", RoleUser)},
+		"at most 4":      {NewContentFromText("1", RoleUser), NewContentFromText("2", RoleModel), NewContentFromText("3", RoleUser), NewContentFromText("4", RoleModel), NewContentFromText("5", RoleUser), NewContentFromText("6", RoleModel)},
+	}
+	for problem, contents := range tests {
+		b := NewTuningDatasetBuilder(client, &TuningDatasetConfig{MaxTurns: 4})
+		b.AddConversation(nil, contents...)
+		if err := b.Validate(ctx); err == nil || !strings.Contains(err.Error(), problem) {
+			t.Errorf("Validate() = %v, want %q", err, problem)
+		}
+	}
+}
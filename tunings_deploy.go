@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+)
+
+// A tuning job of Vertex AI creates a model with a checkpoint per epoch if
+// intermediate checkpoints are enabled, and deploys every checkpoint and the
+// default checkpoint to endpoints. The helpers in this file find the
+// checkpoints and endpoints of the tuned model of a job.
+
+// Checkpoints returns the checkpoints of the tuned model of the tuning job
+// name, one per epoch. It is empty for jobs that did not
+// enable intermediate checkpoints, and on the Gemini API.
+func (t Tunings) Checkpoints(ctx context.Context, name string) ([]*TunedModelCheckpoint, error) {
+	job, err := t.tunedJob(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if job.TunedModel == nil {
+		return nil, nil
+	}
+	return job.TunedModel.Checkpoints, nil
+}
+
+// SetDefaultCheckpoint sets checkpointID as the default checkpoint of the
+// tuned model of the tuning job name, which is the checkpoint used when the
+// model is called without selecting a checkpoint. It is only supported on
+// Vertex AI.
+func (t Tunings) SetDefaultCheckpoint(ctx context.Context, name, checkpointID string) (*Model, error) {
+	if t.apiClient.clientConfig.Backend != BackendVertexAI {
+		return nil, fmt.Errorf("method SetDefaultCheckpoint is only supported in the Vertex AI client")
+	}
+	job, err := t.tunedJob(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := findCheckpoint(job, checkpointID); err != nil {
+		return nil, err
+	}
+	return Models{apiClient: t.apiClient}.Update(ctx, job.TunedModel.Model, &UpdateModelConfig{DefaultCheckpointID: checkpointID})
+}
+
+// ServingModel returns the model to pass to Models.GenerateContent to call
+// the tuned model of the tuning job name. On Vertex AI, it is the endpoint of
+// checkpointID, or of the default checkpoint if checkpointID is empty. On the
+// Gemini API, it is the name of the tuned model, and checkpointID must be
+// empty.
+func (t Tunings) ServingModel(ctx context.Context, name, checkpointID string) (string, error) {
+	job, err := t.tunedJob(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if t.apiClient.clientConfig.Backend != BackendVertexAI {
+		if checkpointID != "" {
+			return "", fmt.Errorf("checkpoints are not supported by the Gemini API")
+		}
+		if job.TunedModel != nil && job.TunedModel.Model != "" {
+			return job.TunedModel.Model, nil
+		}
+		return job.Name, nil
+	}
+	if checkpointID == "" {
+		if job.TunedModel.Endpoint == "" {
+			return "", fmt.Errorf("the tuned model of tuning job %s is not deployed to an endpoint", name)
+		}
+		return job.TunedModel.Endpoint, nil
+	}
+	checkpoint, err := findCheckpoint(job, checkpointID)
+	if err != nil {
+		return "", err
+	}
+	if checkpoint.Endpoint == "" {
+		return "", fmt.Errorf("checkpoint %s of tuning job %s is not deployed to an endpoint", checkpointID, name)
+	}
+	return checkpoint.Endpoint, nil
+}
+
+// tunedJob returns the tuning job name if it succeeded.
+func (t Tunings) tunedJob(ctx context.Context, name string) (*TuningJob, error) {
+	job, err := t.Get(ctx, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	if job.State != JobStateSucceeded {
+		return nil, fmt.Errorf("tuning job %s has not succeeded, its state is %s", name, job.State)
+	}
+	if t.apiClient.clientConfig.Backend == BackendVertexAI && (job.TunedModel == nil || job.TunedModel.Model == "") {
+		return nil, fmt.Errorf("tuning job %s has no tuned model", name)
+	}
+	return job, nil
+}
+
+func findCheckpoint(job *TuningJob, checkpointID string) (*TunedModelCheckpoint, error) {
+	for _, checkpoint := range job.TunedModel.Checkpoints {
+		if checkpoint.CheckpointID == checkpointID {
+			return checkpoint, nil
+		}
+	}
+	return nil, fmt.Errorf("tuning job %s has no checkpoint %s", job.Name, checkpointID)
+}
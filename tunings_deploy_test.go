@@ -0,0 +1,98 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTuningsServingModel(t *testing.T) {
+	ctx := context.Background()
+	var updates []map[string]any
+	client := newTestClient(t, &ClientConfig{Backend: BackendVertexAI}, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPatch:
+			var body map[string]any
+			json.NewDecoder(r.Body).Decode(&body)
+			updates = append(updates, body)
+			fmt.Fprintf(w, `{"name": "projects/p/locations/l/models/1", "defaultCheckpointId": "%v"}`, body["defaultCheckpointId"])
+		case strings.HasSuffix(r.URL.Path, "/running"):
+			fmt.Fprint(w, `{"name": "projects/p/locations/l/tuningJobs/running", "state": "JOB_STATE_RUNNING"}`)
+		default:
+			fmt.Fprint(w, `{"name": "projects/p/locations/l/tuningJobs/1", "state": "JOB_STATE_SUCCEEDED", "tunedModel": {
+				"model": "projects/p/locations/l/models/1",
+				"endpoint": "projects/p/locations/l/endpoints/default",
+				"checkpoints": [
+					{"checkpointId": "1", "epoch": "1", "step": "10", "endpoint": "projects/p/locations/l/endpoints/1"},
+					{"checkpointId": "2", "epoch": "2", "step": "20", "endpoint": "projects/p/locations/l/endpoints/2"}
+				]}}`)
+		}
+	})
+	name := "projects/p/locations/l/tuningJobs/1"
+
+	checkpoints, err := client.Tunings.Checkpoints(ctx, name)
+	if err != nil {
+		t.Fatalf("Checkpoints() failed: %v", err)
+	}
+	want := []*TunedModelCheckpoint{
+		{CheckpointID: "1", Epoch: 1, Step: 10, Endpoint: "projects/p/locations/l/endpoints/1"},
+		{CheckpointID: "2", Epoch: 2, Step: 20, Endpoint: "projects/p/locations/l/endpoints/2"},
+	}
+	if diff := cmp.Diff(want, checkpoints); diff != "" {
+		t.Errorf("Checkpoints() mismatch (-want +got):\n%s", diff)
+	}
+
+	for checkpointID, want := range map[string]string{"": "projects/p/locations/l/endpoints/default", "1": "projects/p/locations/l/endpoints/1"} {
+		got, err := client.Tunings.ServingModel(ctx, name, checkpointID)
+		if err != nil || got != want {
+			t.Errorf("ServingModel(%q) = %q, %v, want %q", checkpointID, got, err, want)
+		}
+	}
+	if _, err := client.Tunings.ServingModel(ctx, name, "3"); err == nil {
+		t.Error("ServingModel() of an unknown checkpoint succeeded, want an error")
+	}
+	if _, err := client.Tunings.ServingModel(ctx, "projects/p/locations/l/tuningJobs/running", ""); err == nil {
+		t.Error("ServingModel() of a running job succeeded, want an error")
+	}
+
+	model, err := client.Tunings.SetDefaultCheckpoint(ctx, name, "2")
+	if err != nil {
+		t.Fatalf("SetDefaultCheckpoint() failed: %v", err)
+	}
+	if model.DefaultCheckpointID != "2" || len(updates) != 1 || updates[0]["defaultCheckpointId"] != "2" {
+		t.Errorf("SetDefaultCheckpoint() = %+v after updates %v, want checkpoint 2", model, updates)
+	}
+}
+
+func TestTuningsServingModelGemini(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "tunedModels/m", "state": "ACTIVE"}`)
+	})
+	got, err := client.Tunings.ServingModel(ctx, "tunedModels/m", "")
+	if err != nil || got != "tunedModels/m" {
+		t.Errorf("ServingModel() = %q, %v, want tunedModels/m", got, err)
+	}
+	if _, err := client.Tunings.SetDefaultCheckpoint(ctx, "tunedModels/m", "1"); err == nil {
+		t.Error("SetDefaultCheckpoint() on the Gemini API succeeded, want an error")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// TuningSnapshot is the metrics of a step of a tuning job of the Gemini API.
+type TuningSnapshot struct {
+	// The tuning step.
+	Step int32 `json:"step,omitempty"`
+	// The epoch this step was part of.
+	Epoch int32 `json:"epoch,omitempty"`
+	// The mean loss of the training examples for this step.
+	MeanLoss float32 `json:"meanLoss,omitempty"`
+	// The time when this metric was computed.
+	ComputeTime time.Time `json:"computeTime,omitempty"`
+}
+
+// TuningEvent is a change of a tuning job observed by Tunings.Watch.
+type TuningEvent struct {
+	// The polled tuning job.
+	Job *TuningJob
+	// The state of the job at the previous event, or empty for the first
+	// event.
+	PreviousState JobState
+	// The metrics recorded since the previous event. Only the Gemini API
+	// reports metrics.
+	Snapshots []*TuningSnapshot
+	// The checkpoints of the tuned model created since the previous event.
+	// Only Vertex AI reports checkpoints.
+	Checkpoints []*TunedModelCheckpoint
+}
+
+// StateChanged reports whether the state of the job changed at the event,
+// which is true for the first event.
+func (e *TuningEvent) StateChanged() bool {
+	return e.PreviousState != e.Job.State
+}
+
+// Watch polls the tuning job name and returns an iterator over its changes:
+// the first event has the job as first polled, and later events are yielded
+// when the state changes or new metrics or checkpoints are reported. The
+// iteration ends after the event of a finished job, i.e. one that succeeded,
+// failed, was cancelled or expired.
+//
+// The delays between polls are those of config, as for Interactions.Wait. If
+// a poll fails, ctx is done or config.Timeout is exceeded, the error is
+// yielded and the iteration ends; the job keeps running.
+func (t Tunings) Watch(ctx context.Context, name string, config *WaitConfig) iter.Seq2[*TuningEvent, error] {
+	if config == nil {
+		config = &WaitConfig{}
+	}
+	return func(yield func(*TuningEvent, error) bool) {
+		var last *TuningJob
+		var lastSnapshots []*TuningSnapshot
+		stopped := false
+		get := func(ctx context.Context) (*TuningJob, error) {
+			var snapshots []*TuningSnapshot
+			job, err := t.Get(context.WithValue(ctx, tuningSnapshotsKey{}, &snapshots), name, &GetTuningJobConfig{HTTPOptions: config.HTTPOptions})
+			if err != nil {
+				return nil, err
+			}
+			if event := newTuningEvent(last, job, lastSnapshots, snapshots); event != nil {
+				stopped = !yield(event, nil)
+			}
+			last, lastSnapshots = job, snapshots
+			return job, nil
+		}
+		done := func(job *TuningJob) bool {
+			return stopped || job.State.isTerminal()
+		}
+		_, timedOut, err := poll(ctx, config, get, done)
+		switch {
+		case timedOut:
+			yield(nil, fmt.Errorf("tuning job %s did not finish within %v: %w", name, config.Timeout, err))
+		case err != nil:
+			yield(nil, err)
+		}
+	}
+}
+
+type tuningSnapshotsKey struct{}
+
+// tuningSnapshotsHook decodes the snapshots of the tuning job got by a call
+// made with a context of Tunings.Watch into the *[]*TuningSnapshot of the
+// context. The Gemini API reports them in the tuning task of the job.
+func (ac *apiClient) tuningSnapshotsHook(ctx context.Context, call *hookedCall) error {
+	snapshots, ok := ctx.Value(tuningSnapshotsKey{}).(*[]*TuningSnapshot)
+	if !ok || call.method != http.MethodGet {
+		return nil
+	}
+	call.onResponse = append(call.onResponse, func(response map[string]any) error {
+		var task struct {
+			Snapshots []*TuningSnapshot `json:"snapshots,omitempty"`
+		}
+		if t, ok := response["tuningTask"].(map[string]any); ok {
+			if err := mapToStruct(t, &task); err != nil {
+				return err
+			}
+		}
+		*snapshots = task.Snapshots
+		return nil
+	})
+	return nil
+}
+
+// newTuningEvent returns the event of job after last, the job at the previous
+// event, or nil if nothing changed. snapshots and lastSnapshots are the
+// snapshots of job and last.
+func newTuningEvent(last, job *TuningJob, lastSnapshots, snapshots []*TuningSnapshot) *TuningEvent {
+	event := &TuningEvent{Job: job}
+	var lastCheckpoints []*TunedModelCheckpoint
+	if last != nil {
+		event.PreviousState = last.State
+		lastCheckpoints = tunedModelCheckpoints(last)
+		if len(snapshots) > len(lastSnapshots) {
+			event.Snapshots = snapshots[len(lastSnapshots):]
+		}
+	} else {
+		event.Snapshots = snapshots
+	}
+	seen := map[string]bool{}
+	for _, checkpoint := range lastCheckpoints {
+		seen[checkpoint.CheckpointID] = true
+	}
+	for _, checkpoint := range tunedModelCheckpoints(job) {
+		if !seen[checkpoint.CheckpointID] {
+			event.Checkpoints = append(event.Checkpoints, checkpoint)
+		}
+	}
+	if last != nil && !event.StateChanged() && len(event.Snapshots) == 0 && len(event.Checkpoints) == 0 {
+		return nil
+	}
+	return event
+}
+
+func tunedModelCheckpoints(job *TuningJob) []*TunedModelCheckpoint {
+	if job.TunedModel == nil {
+		return nil
+	}
+	return job.TunedModel.Checkpoints
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTuningsWatch(t *testing.T) {
+	ctx := context.Background()
+	polls := 0
+	// The job is polled 5 times: twice without changes, then with a new
+	// snapshot, a new snapshot and finally as a succeeded job.
+	responses := []string{
+		`{"name": "tunedModels/m", "state": "CREATING"}`,
+		`{"name": "tunedModels/m", "state": "CREATING"}`,
+		`{"name": "tunedModels/m", "state": "CREATING", "tuningTask": {"snapshots": [{"step": 1, "epoch": 1, "meanLoss": 0.9}]}}`,
+		`{"name": "tunedModels/m", "state": "CREATING", "tuningTask": {"snapshots": [{"step": 1, "epoch": 1, "meanLoss": 0.9}, {"step": 2, "epoch": 1, "meanLoss": 0.5}]}}`,
+		`{"name": "tunedModels/m", "state": "ACTIVE", "tuningTask": {"snapshots": [{"step": 1, "epoch": 1, "meanLoss": 0.9}, {"step": 2, "epoch": 1, "meanLoss": 0.5}]}}`,
+	}
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "slow") {
+			fmt.Fprint(w, `{"name": "tunedModels/slow", "state": "CREATING"}`)
+			return
+		}
+		fmt.Fprint(w, responses[min(polls, len(responses)-1)])
+		polls++
+	})
+
+	var events []string
+	for event, err := range client.Tunings.Watch(ctx, "tunedModels/m", &WaitConfig{InitialInterval: time.Millisecond, Multiplier: 1}) {
+		if err != nil {
+			t.Fatalf("Watch() failed: %v", err)
+		}
+		desc := string(event.Job.State)
+		if event.StateChanged() {
+			desc += " (changed)"
+		}
+		for _, s := range event.Snapshots {
+			desc += fmt.Sprintf(" step %d loss %.1f", s.Step, s.MeanLoss)
+		}
+		events = append(events, desc)
+	}
+	want := []string{
+		"JOB_STATE_RUNNING (changed)",
+		"JOB_STATE_RUNNING step 1 loss 0.9",
+		"JOB_STATE_RUNNING step 2 loss 0.5",
+		"JOB_STATE_SUCCEEDED (changed)",
+	}
+	if strings.Join(events, "\n") != strings.Join(want, "\n") || polls != 5 {
+		t.Errorf("Watch() events after %d polls =\n%s\nwant\n%s", polls, strings.Join(events, "\n"), strings.Join(want, "\n"))
+	}
+
+	var err error
+	for _, err = range client.Tunings.Watch(ctx, "tunedModels/slow", &WaitConfig{InitialInterval: time.Millisecond, Timeout: 20 * time.Millisecond}) {
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Watch() = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestNewTuningEventCheckpoints(t *testing.T) {
+	first := &TuningJob{State: JobStateRunning, TunedModel: &TunedModel{Checkpoints: []*TunedModelCheckpoint{{CheckpointID: "1", Epoch: 1}}}}
+	second := &TuningJob{State: JobStateRunning, TunedModel: &TunedModel{Checkpoints: []*TunedModelCheckpoint{{CheckpointID: "1", Epoch: 1}, {CheckpointID: "2", Epoch: 2}}}}
+	event := newTuningEvent(first, second, nil, nil)
+	if event == nil || len(event.Checkpoints) != 1 || event.Checkpoints[0].CheckpointID != "2" {
+		t.Errorf("newTuningEvent() = %+v, want the new checkpoint", event)
+	}
+	if event := newTuningEvent(second, second, nil, nil); event != nil {
+		t.Errorf("newTuningEvent() of an unchanged job = %+v, want nil", event)
+	}
+}
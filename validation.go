@@ -0,0 +1,314 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// FieldViolation describes a single invalid field of a request.
+type FieldViolation struct {
+	// Field is the JSON path of the invalid field, e.g. "generationConfig.topP".
+	Field string
+	// Description explains why the field is invalid.
+	Description string
+}
+
+// ValidationError is returned by the Validate methods of request types when a
+// request is invalid. It lists every violation found, so that all problems can
+// be fixed at once before the request is sent.
+type ValidationError struct {
+	// TypeName is the name of the validated type.
+	TypeName string
+	// Violations are the invalid fields, in the order they were checked.
+	Violations []FieldViolation
+}
+
+// Error returns a string representation of the ValidationError.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Field + ": " + v.Description
+	}
+	return fmt.Sprintf("invalid %s: %s", e.TypeName, strings.Join(msgs, "; "))
+}
+
+// validator collects the field violations of a request.
+type validator struct {
+	prefix     string
+	violations []FieldViolation
+}
+
+func (v *validator) addf(field, format string, args ...any) {
+	v.violations = append(v.violations, FieldViolation{Field: v.prefix + field, Description: fmt.Sprintf(format, args...)})
+}
+
+// nested returns a validator for the fields under field whose violations are
+// later merged back with merge.
+func (v *validator) nested(field string) *validator {
+	return &validator{prefix: v.prefix + field + "."}
+}
+
+func (v *validator) merge(n *validator) {
+	v.violations = append(v.violations, n.violations...)
+}
+
+// err returns a *ValidationError for typeName, or nil if there are no violations.
+func (v *validator) err(typeName string) error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{TypeName: typeName, Violations: v.violations}
+}
+
+func (v *validator) checkRange(field string, value *float32, lo, hi float32) {
+	if value != nil && (*value < lo || *value > hi) {
+		v.addf(field, "must be between %g and %g, got %g", lo, hi, *value)
+	}
+}
+
+// checkUnsupported reports each set field that the backend does not support.
+func (v *validator) checkUnsupported(backend Backend, fields map[string]bool) {
+	for _, field := range sortedKeys(fields) {
+		if fields[field] {
+			v.addf(field, "not supported in %s", backendName(backend))
+		}
+	}
+}
+
+func backendName(backend Backend) string {
+	if backend == BackendVertexAI {
+		return "Vertex AI"
+	}
+	return "Gemini API"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Validate checks the GenerateContentConfig for out-of-range values, mutually
+// exclusive fields and fields that the given backend does not support. It
+// returns a *ValidationError listing all violations, or nil if the config is
+// valid. Backend-specific checks are skipped for BackendUnspecified. A nil
+// config is valid.
+func (c *GenerateContentConfig) Validate(backend Backend) error {
+	v := &validator{}
+	c.validate(v, backend)
+	return v.err("GenerateContentConfig")
+}
+
+func (c *GenerateContentConfig) validate(v *validator, backend Backend) {
+	if c == nil {
+		return
+	}
+	v.checkRange("temperature", c.Temperature, 0, 2)
+	v.checkRange("topP", c.TopP, 0, 1)
+	if c.TopK != nil && *c.TopK < 0 {
+		v.addf("topK", "must not be negative, got %g", *c.TopK)
+	}
+	if c.CandidateCount < 0 {
+		v.addf("candidateCount", "must not be negative, got %d", c.CandidateCount)
+	}
+	if c.MaxOutputTokens < 0 {
+		v.addf("maxOutputTokens", "must not be negative, got %d", c.MaxOutputTokens)
+	}
+	if c.Logprobs != nil && !c.ResponseLogprobs {
+		v.addf("logprobs", "requires responseLogprobs to be true")
+	}
+	if c.ResponseSchema != nil && c.ResponseJsonSchema != nil {
+		v.addf("responseJsonSchema", "mutually exclusive with responseSchema")
+	}
+	if (c.ResponseSchema != nil || c.ResponseJsonSchema != nil) && c.ResponseMIMEType != "application/json" && c.ResponseMIMEType != "text/x.enum" {
+		v.addf("responseMimeType", "must be application/json or text/x.enum when a response schema is set, got %q", c.ResponseMIMEType)
+	}
+	if c.ThinkingConfig != nil && c.ThinkingConfig.ThinkingBudget != nil && c.ThinkingConfig.ThinkingLevel != "" {
+		v.addf("thinkingConfig.thinkingLevel", "mutually exclusive with thinkingConfig.thinkingBudget")
+	}
+	if c.CachedContent != "" {
+		if c.SystemInstruction != nil {
+			v.addf("systemInstruction", "cannot be set together with cachedContent; put it in the cached content instead")
+		}
+		if len(c.Tools) > 0 {
+			v.addf("tools", "cannot be set together with cachedContent; put them in the cached content instead")
+		}
+		if c.ToolConfig != nil {
+			v.addf("toolConfig", "cannot be set together with cachedContent; put it in the cached content instead")
+		}
+	}
+	switch backend {
+	case BackendGeminiAPI:
+		v.checkUnsupported(backend, map[string]bool{
+			"routingConfig":        c.RoutingConfig != nil,
+			"modelSelectionConfig": c.ModelSelectionConfig != nil,
+			"labels":               c.Labels != nil,
+			"audioTimestamp":       c.AudioTimestamp,
+			"modelArmorConfig":     c.ModelArmorConfig != nil,
+		})
+	case BackendVertexAI:
+		v.checkUnsupported(backend, map[string]bool{
+			"enableEnhancedCivicAnswers": c.EnableEnhancedCivicAnswers != nil,
+		})
+	}
+}
+
+// Validate checks that exactly one input of the BatchJobSource is set, that the
+// format matches the input, and that the input is supported by the given
+// backend. The configs of inlined requests are validated as well. It returns a
+// *ValidationError listing all violations, or nil if the source is valid.
+// Backend-specific checks are skipped for BackendUnspecified.
+func (s *BatchJobSource) Validate(backend Backend) error {
+	v := &validator{}
+	if s == nil {
+		v.addf("src", "is required")
+		return v.err("BatchJobSource")
+	}
+	set := 0
+	for _, isSet := range []bool{len(s.GCSURI) > 0, s.BigqueryURI != "", s.FileName != "", len(s.InlinedRequests) > 0} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		v.addf("src", "exactly one of gcsUri, bigqueryUri, fileName or inlinedRequests must be set, got %d", set)
+	}
+	switch s.Format {
+	case "":
+	case "jsonl":
+		if len(s.GCSURI) == 0 {
+			v.addf("format", "jsonl requires gcsUri")
+		}
+	case "bigquery":
+		if s.BigqueryURI == "" {
+			v.addf("format", "bigquery requires bigqueryUri")
+		}
+	default:
+		v.addf("format", "must be jsonl or bigquery, got %q", s.Format)
+	}
+	switch backend {
+	case BackendGeminiAPI:
+		v.checkUnsupported(backend, map[string]bool{
+			"format":      s.Format != "",
+			"gcsUri":      len(s.GCSURI) > 0,
+			"bigqueryUri": s.BigqueryURI != "",
+		})
+	case BackendVertexAI:
+		v.checkUnsupported(backend, map[string]bool{
+			"fileName":        s.FileName != "",
+			"inlinedRequests": len(s.InlinedRequests) > 0,
+		})
+	}
+	for i, r := range s.InlinedRequests {
+		field := fmt.Sprintf("inlinedRequests[%d]", i)
+		if r == nil {
+			v.addf(field, "must not be nil")
+			continue
+		}
+		if len(r.Contents) == 0 {
+			v.addf(field+".contents", "is required")
+		}
+		n := v.nested(field + ".config")
+		r.Config.validate(n, backend)
+		v.merge(n)
+	}
+	return v.err("BatchJobSource")
+}
+
+// Validate checks the Interaction for mutually exclusive and missing fields,
+// incomplete tools and out-of-range generation parameters. It returns a
+// *ValidationError listing all violations, or nil if the interaction is valid.
+// Interactions.Create and Interactions.CreateStream validate interactions
+// before sending them unless CreateInteractionConfig.SkipValidation is set.
+func (i *Interaction) Validate() error {
+	return i.validate(i != nil && i.Stream)
+}
+
+// validate is Validate for an interaction created with CreateStream if stream
+// is set, or with Create otherwise.
+func (i *Interaction) validate(stream bool) error {
+	v := &validator{}
+	if i == nil {
+		v.addf("interaction", "is required")
+		return v.err("Interaction")
+	}
+	if i.Stream && !stream {
+		v.addf("stream", "use CreateStream to stream an interaction")
+	}
+	switch {
+	case i.Model != "" && i.Agent != "":
+		v.addf("agent", "mutually exclusive with model")
+	case i.Model == "" && i.Agent == "":
+		v.addf("model", "either model or agent is required")
+	}
+	if i.AgentConfig != nil && i.Agent == "" {
+		v.addf("agentConfig", "requires agent")
+	}
+	if isEmptyInput(i.Input) {
+		v.addf("input", "is required")
+	}
+	if w := i.Webhook; w != nil {
+		if !i.Background {
+			v.addf("webhook", "requires background")
+		}
+		if w.URL == "" {
+			v.addf("webhook.url", "is required")
+		}
+	}
+	for n, t := range i.Tools {
+		field := fmt.Sprintf("tools[%d]", n)
+		switch {
+		case t == nil:
+			v.addf(field, "must not be nil")
+		case t.Type == "":
+			v.addf(field+".type", "is required")
+		case t.Type == "function" && t.Name == "":
+			v.addf(field+".name", "is required for function tools")
+		case t.Type == InteractionToolTypeMCPServer && t.URL == "":
+			v.addf(field+".url", "is required for MCP server tools")
+		}
+	}
+	if c := i.GenerationConfig; c != nil {
+		v.checkRange("generationConfig.temperature", c.Temperature, 0, 2)
+		v.checkRange("generationConfig.topP", c.TopP, 0, 1)
+		if c.MaxOutputTokens < 0 {
+			v.addf("generationConfig.maxOutputTokens", "must not be negative, got %d", c.MaxOutputTokens)
+		}
+	}
+	return v.err("Interaction")
+}
+
+// isEmptyInput reports whether an Interaction input is unset, an empty string
+// or an empty list.
+func isEmptyInput(input any) bool {
+	if input == nil {
+		return true
+	}
+	rv := reflect.ValueOf(input)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice:
+		return rv.Len() == 0
+	case reflect.Pointer:
+		return rv.IsNil()
+	}
+	return false
+}
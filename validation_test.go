@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// violationFields returns the fields of a *ValidationError, or nil if err is nil.
+func violationFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("error = %v, want *ValidationError", err)
+	}
+	var fields []string
+	for _, v := range vErr.Violations {
+		fields = append(fields, v.Field)
+	}
+	return fields
+}
+
+func TestGenerateContentConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *GenerateContentConfig
+		backend Backend
+		want    []string
+	}{
+		{name: "nil", config: nil, backend: BackendGeminiAPI},
+		{
+			name:    "valid",
+			config:  &GenerateContentConfig{Temperature: Float32(1), TopP: Float32(0.9), ResponseMIMEType: "application/json", ResponseSchema: &Schema{Type: TypeString}},
+			backend: BackendGeminiAPI,
+		},
+		{
+			name: "all violations at once",
+			config: &GenerateContentConfig{
+				Temperature:        Float32(3),
+				TopP:               Float32(-1),
+				Logprobs:           Int32(2),
+				ResponseSchema:     &Schema{Type: TypeString},
+				ResponseJsonSchema: map[string]any{"type": "string"},
+				ThinkingConfig:     &ThinkingConfig{ThinkingBudget: Int32(0), ThinkingLevel: ThinkingLevelLow},
+				CachedContent:      "cachedContents/123",
+				SystemInstruction:  &Content{Parts: []*Part{{Text: "hi"}}},
+				Labels:             map[string]string{"a": "b"},
+			},
+			backend: BackendGeminiAPI,
+			want:    []string{"temperature", "topP", "logprobs", "responseJsonSchema", "responseMimeType", "thinkingConfig.thinkingLevel", "systemInstruction", "labels"},
+		},
+		{
+			name:    "backend specific",
+			config:  &GenerateContentConfig{Labels: map[string]string{"a": "b"}, EnableEnhancedCivicAnswers: Ptr(true)},
+			backend: BackendVertexAI,
+			want:    []string{"enableEnhancedCivicAnswers"},
+		},
+		{
+			name:    "backend unspecified",
+			config:  &GenerateContentConfig{Labels: map[string]string{"a": "b"}, EnableEnhancedCivicAnswers: Ptr(true)},
+			backend: BackendUnspecified,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := violationFields(t, tt.config.Validate(tt.backend))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Validate() violations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBatchJobSourceValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     *BatchJobSource
+		backend Backend
+		want    []string
+	}{
+		{name: "nil", src: nil, backend: BackendGeminiAPI, want: []string{"src"}},
+		{name: "file", src: &BatchJobSource{FileName: "files/123"}, backend: BackendGeminiAPI},
+		{name: "gcs", src: &BatchJobSource{Format: "jsonl", GCSURI: []string{"gs://b/f.jsonl"}}, backend: BackendVertexAI},
+		{name: "none set", src: &BatchJobSource{}, backend: BackendGeminiAPI, want: []string{"src"}},
+		{
+			name:    "wrong backend",
+			src:     &BatchJobSource{Format: "bigquery", BigqueryURI: "bq://p.d.t"},
+			backend: BackendGeminiAPI,
+			want:    []string{"bigqueryUri", "format"},
+		},
+		{
+			name:    "format mismatch",
+			src:     &BatchJobSource{Format: "jsonl", BigqueryURI: "bq://p.d.t"},
+			backend: BackendVertexAI,
+			want:    []string{"format"},
+		},
+		{
+			name: "inlined requests",
+			src: &BatchJobSource{InlinedRequests: []*InlinedRequest{
+				{Contents: Text("hi")},
+				{Config: &GenerateContentConfig{Temperature: Float32(5)}},
+			}},
+			backend: BackendGeminiAPI,
+			want:    []string{"inlinedRequests[1].contents", "inlinedRequests[1].config.temperature"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := violationFields(t, tt.src.Validate(tt.backend))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Validate() violations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInteractionValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		interaction *Interaction
+		want        []string
+	}{
+		{name: "valid", interaction: &Interaction{Model: "gemini-2.5-flash", Input: "hello"}},
+		{name: "agent", interaction: &Interaction{Agent: "deep-research", AgentConfig: map[string]any{}, Input: "hello"}},
+		{name: "missing model and input", interaction: &Interaction{}, want: []string{"model", "input"}},
+		{
+			name: "model and agent",
+			interaction: &Interaction{
+				Model:            "gemini-2.5-flash",
+				Agent:            "deep-research",
+				Input:            []*InteractionContent{},
+				GenerationConfig: &InteractionGenerationConfig{TopP: Float32(2)},
+			},
+			want: []string{"agent", "input", "generationConfig.topP"},
+		},
+		{name: "agent config without agent", interaction: &Interaction{Model: "m", AgentConfig: map[string]any{}, Input: "hi"}, want: []string{"agentConfig"}},
+		{
+			name: "incomplete tools",
+			interaction: &Interaction{Model: "m", Input: "hi", Tools: []*InteractionTool{
+				{Type: "function", Name: "f"}, nil, {}, {Type: "function"}, {Type: "mcp_server", Name: "s"},
+			}},
+			want: []string{"tools[1]", "tools[2].type", "tools[3].name", "tools[4].url"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := violationFields(t, tt.interaction.Validate())
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Validate() violations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	err := (&Interaction{Model: "m", Agent: "a", Input: "hi"}).Validate()
+	if want := "invalid Interaction: agent: mutually exclusive with model"; err == nil || err.Error() != want {
+		t.Errorf("Error() = %v, want %q", err, want)
+	}
+}
+
+func TestInteractionsCreateValidation(t *testing.T) {
+	ctx := context.Background()
+	var requests int
+	client := newTestClient(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"id": "abc"}`)
+	})
+
+	streaming := &Interaction{Model: "m", Agent: "a", Input: "hi", Stream: true}
+	if got := violationFields(t, func() error { _, err := client.Interactions.Create(ctx, streaming, nil); return err }()); !cmp.Equal(got, []string{"stream", "agent"}) {
+		t.Errorf("Create() violations = %v, want [stream agent]", got)
+	}
+	for _, err := range client.Interactions.CreateStream(ctx, &Interaction{Model: "m"}, nil) {
+		if got := violationFields(t, err); !cmp.Equal(got, []string{"input"}) {
+			t.Errorf("CreateStream() violations = %v, want [input]", got)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("invalid interactions sent %d requests, want none", requests)
+	}
+
+	if _, err := client.Interactions.Create(ctx, &Interaction{Model: "m"}, &CreateInteractionConfig{SkipValidation: true}); err != nil {
+		t.Errorf("Create() with SkipValidation failed: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Create() with SkipValidation sent %d requests, want 1", requests)
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/plar/genai/embeddings"
+)
+
+// VectorDocument is a document with its embedding, stored in a vector store.
+type VectorDocument struct {
+	// Optional. The unique ID of the document. Models.EmbedDocuments generates
+	// one if it is empty.
+	ID string `json:"id"`
+	// Required. The text of the document, which is embedded.
+	Text string `json:"text"`
+	// Optional. The metadata of the document, e.g. its source.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// The embedding of Text, set by Models.EmbedDocuments.
+	Embedding []float32 `json:"embedding,omitempty"`
+}
+
+// ScoredDocument is a document returned by a query of a vector store.
+type ScoredDocument struct {
+	// The document. Stores that only keep the embeddings, such as Vertex AI
+	// Vector Search, only set its ID.
+	Document *VectorDocument
+	// The score of the document for the query. It is the cosine similarity
+	// for the in-memory and pgvector stores, and the distance of the distance
+	// measure of the index for Vertex AI Vector Search, where higher is more
+	// similar for the dot product and cosine measures.
+	Score float64
+}
+
+// EmbeddingSink receives embedded documents, e.g. to store them in a vector
+// database.
+//
+// Implementations must be safe for concurrent use.
+type EmbeddingSink interface {
+	// Upsert stores docs, replacing the documents with the same IDs.
+	Upsert(ctx context.Context, docs ...*VectorDocument) error
+}
+
+// VectorStore is an EmbeddingSink that can be queried for the documents
+// nearest to an embedding.
+type VectorStore interface {
+	EmbeddingSink
+	// Query returns up to k documents nearest to embedding, nearest first.
+	Query(ctx context.Context, embedding []float32, k int) ([]*ScoredDocument, error)
+}
+
+// EmbedDocuments embeds the text of docs with EmbedContentBatched, sets their
+// Embedding and ID if it is empty, and upserts them in sink. The task type of
+// the embeddings defaults to RETRIEVAL_DOCUMENT.
+func (m Models) EmbedDocuments(ctx context.Context, model string, docs []*VectorDocument, sink EmbeddingSink, config *EmbedContentConfig) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	contents := make([]*Content, len(docs))
+	for i, doc := range docs {
+		if doc.Text == "" {
+			return fmt.Errorf("document %d has no text", i)
+		}
+		contents[i] = NewContentFromText(doc.Text, RoleUser)
+	}
+	resp, err := m.EmbedContentBatched(ctx, model, contents, &EmbedContentBatchedConfig{EmbedContentConfig: *embedConfig(config, "RETRIEVAL_DOCUMENT")})
+	if err != nil {
+		return err
+	}
+	for i, doc := range docs {
+		if doc.ID == "" {
+			doc.ID = newRecordID()
+		}
+		doc.Embedding = resp.Embeddings[i].Values
+	}
+	return sink.Upsert(ctx, docs...)
+}
+
+// embedConfig returns a copy of config with taskType as the default task
+// type.
+func embedConfig(config *EmbedContentConfig, taskType string) *EmbedContentConfig {
+	result := &EmbedContentConfig{}
+	if config != nil {
+		*result = *config
+	}
+	if result.TaskType == "" {
+		result.TaskType = taskType
+	}
+	return result
+}
+
+// SemanticRetriever embeds queries and returns the nearest documents of a
+// VectorStore, e.g. to add them to the prompt of a RAG pipeline.
+type SemanticRetriever struct {
+	models Models
+	model  string
+	store  VectorStore
+	config *EmbedContentConfig
+}
+
+// NewSemanticRetriever returns a SemanticRetriever that embeds queries and
+// documents with model and the Models of client. config may be nil; its task
+// type defaults to RETRIEVAL_QUERY for queries and RETRIEVAL_DOCUMENT for
+// documents.
+func NewSemanticRetriever(client *Client, model string, store VectorStore, config *EmbedContentConfig) *SemanticRetriever {
+	return &SemanticRetriever{models: *client.Models, model: model, store: store, config: config}
+}
+
+// Add embeds docs and adds them to the store, see Models.EmbedDocuments.
+func (r *SemanticRetriever) Add(ctx context.Context, docs ...*VectorDocument) error {
+	return r.models.EmbedDocuments(ctx, r.model, docs, r.store, r.config)
+}
+
+// Retrieve embeds query and returns up to k documents of the store by
+// decreasing similarity.
+func (r *SemanticRetriever) Retrieve(ctx context.Context, query string, k int) ([]*ScoredDocument, error) {
+	resp, err := r.models.EmbedContent(ctx, r.model, Text(query), embedConfig(r.config, "RETRIEVAL_QUERY"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed the query: %w", err)
+	}
+	if len(resp.Embeddings) == 0 || resp.Embeddings[0] == nil {
+		return nil, fmt.Errorf("failed to embed the query: the response has no embedding")
+	}
+	return r.store.Query(ctx, resp.Embeddings[0].Values, k)
+}
+
+type inMemoryVectorStore struct {
+	mu   sync.Mutex
+	docs []*VectorDocument
+}
+
+// NewInMemoryVectorStore returns a VectorStore that keeps the documents in
+// memory and compares the query with every document, which suits small
+// corpora and tests.
+func NewInMemoryVectorStore() VectorStore {
+	return &inMemoryVectorStore{}
+}
+
+func (s *inMemoryVectorStore) Upsert(ctx context.Context, docs ...*VectorDocument) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("document %q has no ID", doc.Text)
+		}
+		doc := *doc
+		if i := slices.IndexFunc(s.docs, func(d *VectorDocument) bool { return d.ID == doc.ID }); i >= 0 {
+			s.docs[i] = &doc
+		} else {
+			s.docs = append(s.docs, &doc)
+		}
+	}
+	return nil
+}
+
+func (s *inMemoryVectorStore) Query(ctx context.Context, embedding []float32, k int) ([]*ScoredDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	corpus := make([][]float32, len(s.docs))
+	for i, doc := range s.docs {
+		corpus[i] = doc.Embedding
+	}
+	var results []*ScoredDocument
+	for _, match := range embeddings.TopK(embedding, corpus, k) {
+		doc := *s.docs[match.Index]
+		results = append(results, &ScoredDocument{Document: &doc, Score: match.Score})
+	}
+	return results, nil
+}
+
+// pgIdentifier matches the table names accepted by NewPgVectorStore.
+var pgIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+type pgVectorStore struct {
+	db    *sql.DB
+	table string
+}
+
+// NewPgVectorStore returns a VectorStore that keeps the documents in table of
+// db, a PostgreSQL database with the pgvector extension, opened with any
+// PostgreSQL driver. The table must have the columns
+//
+//	id TEXT PRIMARY KEY, text TEXT, metadata JSONB, embedding VECTOR(n)
+//
+// where n is the dimensionality of the embeddings. Queries are ordered by
+// cosine distance, so an index on the embeddings should use the
+// vector_cosine_ops operator class.
+func NewPgVectorStore(db *sql.DB, table string) (VectorStore, error) {
+	if !pgIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	return &pgVectorStore{db: db, table: table}, nil
+}
+
+func (s *pgVectorStore) Upsert(ctx context.Context, docs ...*VectorDocument) error {
+	query := fmt.Sprintf(`INSERT INTO %s (id, text, metadata, embedding) VALUES ($1, $2, $3, $4::vector)
+ON CONFLICT (id) DO UPDATE SET text = EXCLUDED.text, metadata = EXCLUDED.metadata, embedding = EXCLUDED.embedding`, s.table)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to upsert documents: %w", err)
+	}
+	defer tx.Rollback()
+	for _, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("document %q has no ID", doc.Text)
+		}
+		metadata, err := json.Marshal(doc.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal the metadata of document %s: %w", doc.ID, err)
+		}
+		if _, err := tx.ExecContext(ctx, query, doc.ID, doc.Text, string(metadata), pgVector(doc.Embedding)); err != nil {
+			return fmt.Errorf("failed to upsert document %s: %w", doc.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to upsert documents: %w", err)
+	}
+	return nil
+}
+
+func (s *pgVectorStore) Query(ctx context.Context, embedding []float32, k int) ([]*ScoredDocument, error) {
+	query := fmt.Sprintf(`SELECT id, text, metadata, 1 - (embedding <=> $1::vector) FROM %s ORDER BY embedding <=> $1::vector`, s.table)
+	args := []any{pgVector(embedding)}
+	if k > 0 {
+		query += " LIMIT $2"
+		args = append(args, k)
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query documents: %w", err)
+	}
+	defer rows.Close()
+	var results []*ScoredDocument
+	for rows.Next() {
+		doc := &VectorDocument{}
+		var metadata sql.NullString
+		var score float64
+		if err := rows.Scan(&doc.ID, &doc.Text, &metadata, &score); err != nil {
+			return nil, fmt.Errorf("failed to read the documents: %w", err)
+		}
+		if metadata.Valid && metadata.String != "" {
+			if err := json.Unmarshal([]byte(metadata.String), &doc.Metadata); err != nil {
+				return nil, fmt.Errorf("failed to parse the metadata of document %s: %w", doc.ID, err)
+			}
+		}
+		results = append(results, &ScoredDocument{Document: doc, Score: score})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the documents: %w", err)
+	}
+	return results, nil
+}
+
+// pgVector returns the text representation of v in pgvector, e.g. "[1,2,3]".
+func pgVector(v []float32) string {
+	var sb strings.Builder
+	sb.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatFloat(float64(x), 'g', -1, 32))
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// VertexVectorSearchConfig configures a VectorStore of Vertex AI Vector
+// Search.
+type VertexVectorSearchConfig struct {
+	// Required. The resource name of the index, with streaming updates
+	// enabled, e.g. "projects/p/locations/l/indexes/123".
+	Index string
+	// Required. The resource name of the index endpoint, e.g.
+	// "projects/p/locations/l/indexEndpoints/456".
+	IndexEndpoint string
+	// Required. The ID of the index deployed to IndexEndpoint.
+	DeployedIndexID string
+	// Optional. The public endpoint domain of IndexEndpoint, e.g.
+	// "123.us-central1-456.vdb.vertexai.goog". Defaults to the host of the
+	// client.
+	PublicEndpointDomain string
+}
+
+type vertexVectorSearch struct {
+	ac     *apiClient
+	config VertexVectorSearchConfig
+}
+
+// NewVertexVectorSearch returns a VectorStore that upserts the embeddings to
+// an index of Vertex AI Vector Search and queries the index deployed to an
+// index endpoint, with the credentials of client, which must be a Vertex AI
+// client.
+//
+// Vector Search only keeps the embeddings, so the documents returned by Query
+// only have their ID; their text and metadata are not stored.
+func NewVertexVectorSearch(client *Client, config VertexVectorSearchConfig) (VectorStore, error) {
+	if client.clientConfig.Backend != BackendVertexAI {
+		return nil, fmt.Errorf("Vertex AI Vector Search is only supported in the Vertex AI client")
+	}
+	if config.Index == "" || config.IndexEndpoint == "" || config.DeployedIndexID == "" {
+		return nil, fmt.Errorf("Index, IndexEndpoint and DeployedIndexID are required")
+	}
+	return &vertexVectorSearch{ac: client.Models.apiClient, config: config}, nil
+}
+
+func (s *vertexVectorSearch) Upsert(ctx context.Context, docs ...*VectorDocument) error {
+	datapoints := make([]any, len(docs))
+	for i, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("document %q has no ID", doc.Text)
+		}
+		datapoints[i] = map[string]any{"datapointId": doc.ID, "featureVector": doc.Embedding}
+	}
+	_, err := sendRequest(ctx, s.ac, s.config.Index+":upsertDatapoints", http.MethodPost, map[string]any{"datapoints": datapoints}, &HTTPOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to upsert datapoints: %w", err)
+	}
+	return nil
+}
+
+func (s *vertexVectorSearch) Query(ctx context.Context, embedding []float32, k int) ([]*ScoredDocument, error) {
+	query := map[string]any{"datapoint": map[string]any{"featureVector": embedding}}
+	if k > 0 {
+		query["neighborCount"] = k
+	}
+	body := map[string]any{"deployedIndexId": s.config.DeployedIndexID, "queries": []any{query}}
+	httpOptions := &HTTPOptions{}
+	if domain := s.config.PublicEndpointDomain; domain != "" {
+		if !strings.Contains(domain, "://") {
+			domain = "https://" + domain
+		}
+		httpOptions.BaseURL = domain
+	}
+	response, err := sendRequest(ctx, s.ac, s.config.IndexEndpoint+":findNeighbors", http.MethodPost, body, httpOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find neighbors: %w", err)
+	}
+	var result struct {
+		NearestNeighbors []struct {
+			Neighbors []struct {
+				Datapoint struct {
+					DatapointID string `json:"datapointId"`
+				} `json:"datapoint"`
+				Distance float64 `json:"distance"`
+			} `json:"neighbors"`
+		} `json:"nearestNeighbors"`
+	}
+	if err := mapToStruct(response, &result); err != nil {
+		return nil, err
+	}
+	var results []*ScoredDocument
+	for _, nearest := range result.NearestNeighbors {
+		for _, neighbor := range nearest.Neighbors {
+			results = append(results, &ScoredDocument{Document: &VectorDocument{ID: neighbor.Datapoint.DatapointID}, Score: neighbor.Distance})
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSemanticRetriever(t *testing.T) {
+	ctx := context.Background()
+	var taskTypes []string
+	client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Requests []struct {
+				Content  *Content `json:"content"`
+				TaskType string   `json:"taskType"`
+			} `json:"requests"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		var embeddings []any
+		for _, request := range body.Requests {
+			taskTypes = append(taskTypes, request.TaskType)
+			text := request.Content.Parts[0].Text
+			values := []float32{0, 0}
+			if strings.Contains(text, "cat") {
+				values[0] = 1
+			}
+			if strings.Contains(text, "dog") {
+				values[1] = 1
+			}
+			embeddings = append(embeddings, map[string]any{"values": values})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings})
+	})
+	retriever := NewSemanticRetriever(client, "gemini-embedding-001", NewInMemoryVectorStore(), nil)
+	docs := []*VectorDocument{
+		{ID: "1", Text: "The cat sleeps."},
+		{ID: "2", Text: "The dog barks."},
+		{Text: "The cat chases the dog.", Metadata: map[string]string{"source": "news"}},
+	}
+	if err := retriever.Add(ctx, docs...); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if docs[2].ID == "" || len(docs[2].Embedding) != 2 {
+		t.Errorf("Add() set %+v, want an ID and an embedding", docs[2])
+	}
+	results, err := retriever.Retrieve(ctx, "Where is the cat?", 2)
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+	var got []string
+	for _, result := range results {
+		got = append(got, fmt.Sprintf("%s %.2f", result.Document.Text, result.Score))
+	}
+	want := []string{"The cat sleeps. 1.00", "The cat chases the dog. 0.71"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Retrieve() mismatch (-want +got):\n%s", diff)
+	}
+	wantTaskTypes := []string{"RETRIEVAL_DOCUMENT", "RETRIEVAL_DOCUMENT", "RETRIEVAL_DOCUMENT", "RETRIEVAL_QUERY"}
+	if diff := cmp.Diff(wantTaskTypes, taskTypes); diff != "" {
+		t.Errorf("task types mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVertexVectorSearch(t *testing.T) {
+	ctx := context.Background()
+	var requests []string
+	client := newTestClient(t, &ClientConfig{Backend: BackendVertexAI}, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, r.URL.Path+" "+string(body))
+		if strings.HasSuffix(r.URL.Path, ":findNeighbors") {
+			fmt.Fprint(w, `{"nearestNeighbors": [{"neighbors": [{"datapoint": {"datapointId": "a"}, "distance": 0.9}]}]}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+	})
+	store, err := NewVertexVectorSearch(client, VertexVectorSearchConfig{
+		Index:           "projects/test-project/locations/us-central1/indexes/1",
+		IndexEndpoint:   "projects/test-project/locations/us-central1/indexEndpoints/2",
+		DeployedIndexID: "deployed",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Upsert(ctx, &VectorDocument{ID: "a", Text: "a", Embedding: []float32{1, 0}}); err != nil {
+		t.Fatalf("Upsert() failed: %v", err)
+	}
+	results, err := store.Query(ctx, []float32{1, 0}, 1)
+	if err != nil {
+		t.Fatalf("Query() failed: %v", err)
+	}
+	if diff := cmp.Diff([]*ScoredDocument{{Document: &VectorDocument{ID: "a"}, Score: 0.9}}, results); diff != "" {
+		t.Errorf("Query() mismatch (-want +got):\n%s", diff)
+	}
+	want := []string{
+		`/v1beta1/projects/test-project/locations/us-central1/indexes/1:upsertDatapoints {"datapoints":[{"datapointId":"a","featureVector":[1,0]}]}`,
+		`/v1beta1/projects/test-project/locations/us-central1/indexEndpoints/2:findNeighbors {"deployedIndexId":"deployed","queries":[{"datapoint":{"featureVector":[1,0]},"neighborCount":1}]}`,
+	}
+	if diff := cmp.Diff(want, requests); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+
+	gemini := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {})
+	if _, err := NewVertexVectorSearch(gemini, VertexVectorSearchConfig{Index: "i", IndexEndpoint: "e", DeployedIndexID: "d"}); err == nil {
+		t.Error("NewVertexVectorSearch() with a Gemini API client succeeded, want an error")
+	}
+}
+
+// recordingDriver is a database/sql driver that records the statements and
+// returns rows for queries.
+type recordingDriver struct {
+	statements []string
+	rows       [][]driver.Value
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) { return recordingConn{d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return recordingStmt{d: c.d, query: query}, nil
+}
+func (c recordingConn) Close() error              { return nil }
+func (c recordingConn) Begin() (driver.Tx, error) { return recordingTx{c.d}, nil }
+
+type recordingTx struct{ d *recordingDriver }
+
+func (tx recordingTx) Commit() error {
+	tx.d.statements = append(tx.d.statements, "COMMIT")
+	return nil
+}
+func (tx recordingTx) Rollback() error { return nil }
+
+type recordingStmt struct {
+	d     *recordingDriver
+	query string
+}
+
+func (s recordingStmt) Close() error  { return nil }
+func (s recordingStmt) NumInput() int { return -1 }
+func (s recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.statements = append(s.d.statements, fmt.Sprint(strings.Fields(s.query)[0], args))
+	return driver.RowsAffected(1), nil
+}
+func (s recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.statements = append(s.d.statements, fmt.Sprint(s.query, args))
+	return &recordingRows{rows: s.d.rows}, nil
+}
+
+type recordingRows struct{ rows [][]driver.Value }
+
+func (r *recordingRows) Columns() []string { return []string{"id", "text", "metadata", "score"} }
+func (r *recordingRows) Close() error      { return nil }
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestPgVectorStore(t *testing.T) {
+	ctx := context.Background()
+	d := &recordingDriver{rows: [][]driver.Value{{"a", "The cat sleeps.", `{"source":"news"}`, 0.75}, {"b", "The dog barks.", nil, 0.5}}}
+	sql.Register("recording", d)
+	db, err := sql.Open("recording", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := NewPgVectorStore(db, "docs; DROP TABLE docs"); err == nil {
+		t.Error("NewPgVectorStore() with an invalid table name succeeded, want an error")
+	}
+	store, err := NewPgVectorStore(db, "public.docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Upsert(ctx, &VectorDocument{ID: "a", Text: "The cat sleeps.", Metadata: map[string]string{"source": "news"}, Embedding: []float32{0.5, -1}}); err != nil {
+		t.Fatalf("Upsert() failed: %v", err)
+	}
+	results, err := store.Query(ctx, []float32{1, 0}, 2)
+	if err != nil {
+		t.Fatalf("Query() failed: %v", err)
+	}
+	want := []*ScoredDocument{
+		{Document: &VectorDocument{ID: "a", Text: "The cat sleeps.", Metadata: map[string]string{"source": "news"}}, Score: 0.75},
+		{Document: &VectorDocument{ID: "b", Text: "The dog barks."}, Score: 0.5},
+	}
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Errorf("Query() mismatch (-want +got):\n%s", diff)
+	}
+	wantStatements := []string{
+		`INSERT[a The cat sleeps. {"source":"news"} [0.5,-1]]`,
+		"COMMIT",
+		"SELECT id, text, metadata, 1 - (embedding <=> $1::vector) FROM public.docs ORDER BY embedding <=> $1::vector LIMIT $2[[1,0] 2]",
+	}
+	if diff := cmp.Diff(wantStatements, d.statements); diff != "" {
+		t.Errorf("statements mismatch (-want +got):\n%s", diff)
+	}
+}
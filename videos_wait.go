@@ -0,0 +1,117 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// OperationError is returned by Operations.WaitVideos for an operation that
+// finished with an error.
+type OperationError struct {
+	// Name is the name of the operation.
+	Name string
+	// Code is the status code of the error, e.g. 3 for INVALID_ARGUMENT.
+	Code int
+	// Message is the message of the error.
+	Message string
+}
+
+// Error returns a string representation of the OperationError.
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("operation %s failed with code %d: %s", e.Name, e.Code, e.Message)
+}
+
+func newOperationError(name string, status map[string]any) *OperationError {
+	code, _ := status["code"].(float64)
+	message, _ := status["message"].(string)
+	return &OperationError{Name: name, Code: int(code), Message: message}
+}
+
+// WaitVideos polls the video generation operation until it is done and
+// returns it with its response. The delays between polls are those of
+// Interactions.Wait. An operation that failed is returned with an
+// *OperationError.
+//
+// If ctx is done first, WaitVideos returns ctx.Err(). If config.Timeout is
+// exceeded first, it returns an error that wraps context.DeadlineExceeded;
+// the operation keeps running.
+func (m Operations) WaitVideos(ctx context.Context, operation *GenerateVideosOperation, config *WaitConfig) (*GenerateVideosOperation, error) {
+	if operation == nil || operation.Name == "" {
+		return nil, errors.New("WaitVideos requires an operation with a name")
+	}
+	if config == nil {
+		config = &WaitConfig{}
+	}
+	if !operation.Done {
+		get := func(ctx context.Context) (*GenerateVideosOperation, error) {
+			got, err := m.GetVideosOperation(ctx, operation, &GetOperationConfig{HTTPOptions: config.HTTPOptions})
+			if err == nil && config.OnVideosOperationUpdate != nil {
+				config.OnVideosOperationUpdate(got)
+			}
+			return got, err
+		}
+		got, timedOut, err := poll(ctx, config, get, func(got *GenerateVideosOperation) bool { return got.Done })
+		if timedOut {
+			return nil, fmt.Errorf("operation %s did not finish within %v: %w", operation.Name, config.Timeout, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		operation = got
+	}
+	if operation.Error != nil {
+		return operation, newOperationError(operation.Name, operation.Error)
+	}
+	return operation, nil
+}
+
+// GenerateVideosAndWait starts the generation of videos from source with
+// GenerateVideosFromSource, waits until the operation is done with
+// Operations.WaitVideos and returns its response.
+//
+// The videos of the response are ready to download. On the Gemini API, they
+// refer to files that Files.Download or Files.DownloadToFile download. On
+// Vertex AI, they have VideoBytes, which Files.DownloadToFile writes to a
+// file, or, if GenerateVideosConfig.OutputGCSURI is set, a gs:// URI of the
+// object they were written to, to read with Cloud Storage.
+func (m Models) GenerateVideosAndWait(ctx context.Context, model string, source *GenerateVideosSource, config *GenerateVideosConfig, wait *WaitConfig) (*GenerateVideosResponse, error) {
+	operation, err := m.GenerateVideosFromSource(ctx, model, source, config)
+	if err != nil {
+		return nil, err
+	}
+	operation, err = Operations{apiClient: m.apiClient}.WaitVideos(ctx, operation, wait)
+	if err != nil {
+		return nil, err
+	}
+	if operation.Response == nil {
+		return &GenerateVideosResponse{}, nil
+	}
+	return operation.Response, nil
+}
+
+// Videos returns the generated videos of the response. Videos that were
+// filtered out are counted in RAIMediaFilteredCount instead.
+func (r *GenerateVideosResponse) Videos() []*Video {
+	var videos []*Video
+	for _, video := range r.GeneratedVideos {
+		if video != nil && video.Video != nil {
+			videos = append(videos, video.Video)
+		}
+	}
+	return videos
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerateVideosAndWait(t *testing.T) {
+	ctx := context.Background()
+	wait := &WaitConfig{InitialInterval: time.Millisecond, Multiplier: 1}
+	tests := []struct {
+		name     string
+		backend  Backend
+		done     string
+		wantPath string
+		want     []*Video
+	}{
+		{
+			name:     "Gemini API",
+			backend:  BackendGeminiAPI,
+			done:     `{"name": "models/veo-3.0-generate-001/operations/1", "done": true, "response": {"generateVideoResponse": {"generatedSamples": [{"video": {"uri": "https://generativelanguage.googleapis.com/v1beta/files/abc:download?alt=media"}}]}}}`,
+			wantPath: "/v1beta/models/veo-3.0-generate-001/operations/1",
+			want:     []*Video{{URI: "https://generativelanguage.googleapis.com/v1beta/files/abc:download?alt=media"}},
+		},
+		{
+			name:     "Vertex AI",
+			backend:  BackendVertexAI,
+			done:     `{"name": "projects/test-project/locations/us-central1/publishers/google/models/veo-3.0-generate-001/operations/1", "done": true, "response": {"videos": [{"gcsUri": "gs://bucket/video.mp4", "mimeType": "video/mp4"}]}}`,
+			wantPath: "/v1beta1/projects/test-project/locations/us-central1/publishers/google/models/veo-3.0-generate-001:fetchPredictOperation",
+			want:     []*Video{{URI: "gs://bucket/video.mp4", MIMEType: "video/mp4"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := 0
+			client := newTestClient(t, &ClientConfig{Backend: tt.backend}, func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, ":predictLongRunning") {
+					name := "models/veo-3.0-generate-001/operations/1"
+					if tt.backend == BackendVertexAI {
+						name = "projects/test-project/locations/us-central1/publishers/google/models/veo-3.0-generate-001/operations/1"
+					}
+					fmt.Fprintf(w, `{"name": %q}`, name)
+					return
+				}
+				if r.URL.Path != tt.wantPath {
+					t.Errorf("path = %s, want %s", r.URL.Path, tt.wantPath)
+				}
+				polls++
+				if polls < 2 {
+					fmt.Fprint(w, `{"name": "operations/1"}`)
+					return
+				}
+				fmt.Fprint(w, tt.done)
+			})
+			var updates int
+			wait := *wait
+			wait.OnVideosOperationUpdate = func(*GenerateVideosOperation) { updates++ }
+			resp, err := client.Models.GenerateVideosAndWait(ctx, "veo-3.0-generate-001", &GenerateVideosSource{Prompt: "a cat"}, nil, &wait)
+			if err != nil {
+				t.Fatalf("GenerateVideosAndWait() failed: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, resp.Videos()); diff != "" {
+				t.Errorf("Videos() mismatch (-want +got):\n%s", diff)
+			}
+			if updates != 2 {
+				t.Errorf("OnVideosOperationUpdate called %d times, want 2", updates)
+			}
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"name": "models/veo/operations/2", "done": true, "error": {"code": 3, "message": "bad prompt"}}`)
+		})
+		_, err := client.Operations.WaitVideos(ctx, &GenerateVideosOperation{Name: "models/veo/operations/2"}, wait)
+		var opErr *OperationError
+		if !errors.As(err, &opErr) || opErr.Code != 3 || opErr.Message != "bad prompt" {
+			t.Errorf("WaitVideos() error = %v, want an *OperationError", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		client := newTestClient(t, &ClientConfig{Backend: BackendGeminiAPI}, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"name": "models/veo/operations/3"}`)
+		})
+		_, err := client.Operations.WaitVideos(ctx, &GenerateVideosOperation{Name: "models/veo/operations/3"}, &WaitConfig{InitialInterval: time.Millisecond, Timeout: 20 * time.Millisecond})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("WaitVideos() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
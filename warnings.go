@@ -0,0 +1,228 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServerWarning is a warning or deprecation notice sent by the server in the
+// response headers of an API call, e.g. because the requested model or a
+// request field is being sunset.
+//
+// Warnings are parsed from the Warning header (RFC 7234) and from the
+// Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers.
+type ServerWarning struct {
+	// Code is the warn-code of a Warning header, e.g. 299 for a persistent
+	// warning. It is zero for deprecation notices.
+	Code int
+	// Message is the human-readable warning text. For deprecation notices
+	// without a Warning header, it is generated from the other fields.
+	Message string
+	// Deprecated is true if the response carried a deprecation notice.
+	Deprecated bool
+	// DeprecationDate is the date the resource was or will be deprecated, if
+	// the server provided one.
+	DeprecationDate time.Time
+	// SunsetDate is the date after which the resource is expected to stop
+	// working, if the server provided one.
+	SunsetDate time.Time
+	// Link points to documentation of the deprecation, if the server
+	// provided one.
+	Link string
+	// Endpoint is the HTTP method and URL, without query, of the request that
+	// produced the warning. It is only set for warnings passed to
+	// [ClientConfig.OnServerWarning].
+	Endpoint string
+}
+
+// String returns a one-line description of the warning.
+func (w *ServerWarning) String() string {
+	var sb strings.Builder
+	if w.Endpoint != "" {
+		fmt.Fprintf(&sb, "%s: ", w.Endpoint)
+	}
+	sb.WriteString(w.Message)
+	if !w.SunsetDate.IsZero() {
+		fmt.Fprintf(&sb, " (sunset %s)", w.SunsetDate.Format(time.DateOnly))
+	}
+	if w.Link != "" {
+		fmt.Fprintf(&sb, " See %s", w.Link)
+	}
+	return sb.String()
+}
+
+// Warnings returns the server warnings and deprecation notices carried by the
+// response headers, or nil if there are none.
+func (r *HTTPResponse) Warnings() []*ServerWarning {
+	if r == nil {
+		return nil
+	}
+	return parseServerWarnings(r.Headers)
+}
+
+// reportServerWarnings passes the warnings in resp to the client's
+// OnServerWarning callback, or logs them if no callback is set.
+func (ac *apiClient) reportServerWarnings(resp *http.Response) {
+	warnings := parseServerWarnings(resp.Header)
+	if len(warnings) == 0 {
+		return
+	}
+	endpoint := requestEndpoint(resp)
+	for _, w := range warnings {
+		w.Endpoint = endpoint
+		if ac.clientConfig.OnServerWarning != nil {
+			ac.clientConfig.OnServerWarning(w)
+		} else {
+			log.Printf("Warning: server warning: %s", w)
+		}
+	}
+}
+
+// parseServerWarnings parses the Warning, Deprecation, Sunset and Link headers.
+// A deprecation notice is merged into the first Warning if there is one.
+func parseServerWarnings(h http.Header) []*ServerWarning {
+	if h == nil {
+		return nil
+	}
+	var warnings []*ServerWarning
+	for _, v := range h.Values("Warning") {
+		warnings = append(warnings, parseWarningHeader(v)...)
+	}
+
+	deprecation := h.Get("Deprecation")
+	sunset := h.Get("Sunset")
+	if deprecation == "" && sunset == "" {
+		return warnings
+	}
+	notice := &ServerWarning{Deprecated: deprecation != "" && deprecation != "false"}
+	notice.DeprecationDate = parseDeprecationDate(deprecation)
+	if t, err := http.ParseTime(sunset); err == nil {
+		notice.SunsetDate = t
+	}
+	notice.Link = deprecationLink(h.Values("Link"))
+	if len(warnings) > 0 {
+		w := warnings[0]
+		w.Deprecated, w.DeprecationDate, w.SunsetDate, w.Link = notice.Deprecated, notice.DeprecationDate, notice.SunsetDate, notice.Link
+		return warnings
+	}
+	switch {
+	case !notice.DeprecationDate.IsZero():
+		notice.Message = fmt.Sprintf("resource is deprecated as of %s", notice.DeprecationDate.Format(time.DateOnly))
+	case notice.Deprecated:
+		notice.Message = "resource is deprecated"
+	default:
+		notice.Message = "resource is scheduled to be sunset"
+	}
+	return []*ServerWarning{notice}
+}
+
+// parseWarningHeader parses a Warning header value of the form
+// `299 agent "text" ["date"], ...`. Malformed entries are skipped.
+func parseWarningHeader(v string) []*ServerWarning {
+	var warnings []*ServerWarning
+	for v = strings.TrimSpace(v); v != ""; v = strings.TrimLeft(v, ", ") {
+		code, rest, ok := strings.Cut(v, " ")
+		if !ok {
+			return warnings
+		}
+		n, err := strconv.Atoi(code)
+		if err != nil {
+			return warnings
+		}
+		// Skip the warn-agent.
+		_, rest, ok = strings.Cut(strings.TrimLeft(rest, " "), " ")
+		if !ok {
+			return warnings
+		}
+		text, rest, ok := cutQuoted(strings.TrimLeft(rest, " "))
+		if !ok {
+			return warnings
+		}
+		warnings = append(warnings, &ServerWarning{Code: n, Message: text})
+		// Skip the optional warn-date.
+		if rest = strings.TrimLeft(rest, " "); strings.HasPrefix(rest, `"`) {
+			if _, rest, ok = cutQuoted(rest); !ok {
+				return warnings
+			}
+		}
+		v = rest
+	}
+	return warnings
+}
+
+// cutQuoted returns the unescaped content of the quoted string at the start of
+// s and the remainder of s.
+func cutQuoted(s string) (text, rest string, ok bool) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", s, false
+	}
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				sb.WriteByte(s[i])
+			}
+		case '"':
+			return sb.String(), s[i+1:], true
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return "", s, false
+}
+
+// parseDeprecationDate parses a Deprecation header value, which is either a
+// structured date such as "@1688169599" or, in older drafts, an HTTP date or
+// "true".
+func parseDeprecationDate(v string) time.Time {
+	if secs, ok := strings.CutPrefix(v, "@"); ok {
+		if n, err := strconv.ParseInt(secs, 10, 64); err == nil {
+			return time.Unix(n, 0).UTC()
+		}
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// deprecationLink returns the target of the first Link header entry with a
+// "deprecation" or "sunset" relation.
+func deprecationLink(links []string) string {
+	for _, header := range links {
+		for _, link := range strings.Split(header, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok {
+				continue
+			}
+			for _, p := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(p), "=")
+				value = strings.Trim(value, `"`)
+				if strings.EqualFold(key, "rel") && (value == "deprecation" || value == "sunset") {
+					return strings.Trim(strings.TrimSpace(target), "<>")
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseServerWarnings(t *testing.T) {
+	sunset := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   []*ServerWarning
+	}{
+		{
+			name:   "no warnings",
+			header: http.Header{"Content-Type": []string{"application/json"}},
+		},
+		{
+			name: "multiple warning values",
+			header: http.Header{"Warning": []string{
+				`299 - "Model gemini-1.0-pro is deprecated" "Wed, 21 Oct 2015 07:28:00 GMT", 199 proxy "a \"quoted\" note"`,
+			}},
+			want: []*ServerWarning{
+				{Code: 299, Message: "Model gemini-1.0-pro is deprecated"},
+				{Code: 199, Message: `a "quoted" note`},
+			},
+		},
+		{
+			name: "deprecation headers only",
+			header: http.Header{
+				"Deprecation": []string{"@1688169599"},
+				"Sunset":      []string{"Mon, 01 Jun 2026 00:00:00 GMT"},
+				"Link":        []string{`<https://example.com/docs>; rel="alternate", <https://example.com/deprecations>; rel="deprecation"`},
+			},
+			want: []*ServerWarning{{
+				Message:         "resource is deprecated as of 2023-06-30",
+				Deprecated:      true,
+				DeprecationDate: time.Unix(1688169599, 0).UTC(),
+				SunsetDate:      sunset,
+				Link:            "https://example.com/deprecations",
+			}},
+		},
+		{
+			name: "deprecation merged into warning",
+			header: http.Header{
+				"Warning":     []string{`299 - "Field foo is deprecated"`},
+				"Deprecation": []string{"true"},
+			},
+			want: []*ServerWarning{{Code: 299, Message: "Field foo is deprecated", Deprecated: true}},
+		},
+		{
+			name:   "malformed warning",
+			header: http.Header{"Warning": []string{`not a warning`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := (&HTTPResponse{Headers: tt.header}).Warnings()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Warnings() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOnServerWarning(t *testing.T) {
+	ctx := context.Background()
+	var got []*ServerWarning
+	client := newTestClient(t, &ClientConfig{OnServerWarning: func(w *ServerWarning) { got = append(got, w) }}, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Warning", `299 - "Model is deprecated"`)
+		w.Header().Set("Sunset", "Mon, 01 Jun 2026 00:00:00 GMT")
+		fmt.Fprint(w, `{}`)
+	})
+	resp, err := client.Models.GenerateContent(ctx, "gemini-old", Text("hello"), nil)
+	if err != nil {
+		t.Fatalf("GenerateContent() failed: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("OnServerWarning called %d times, want 1", len(got))
+	}
+	want := "POST " + client.clientConfig.HTTPOptions.BaseURL + "/v1beta/models/gemini-old:generateContent: Model is deprecated (sunset 2026-06-01)"
+	if got[0].String() != want {
+		t.Errorf("String() = %q, want %q", got[0].String(), want)
+	}
+	if warnings := resp.SDKHTTPResponse.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0].Message, "deprecated") {
+		t.Errorf("SDKHTTPResponse.Warnings() = %v, want the deprecation warning", warnings)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Webhook notifications follow the Standard Webhooks specification
+// (https://www.standardwebhooks.com): every notification carries the
+// webhook-id, webhook-timestamp and webhook-signature headers, and the
+// signature is an HMAC-SHA256 of "<id>.<timestamp>.<body>" with the secret of
+// the webhook.
+
+const (
+	// WebhookIDHeader is the header with the unique ID of a notification.
+	WebhookIDHeader = "Webhook-Id"
+	// WebhookTimestampHeader is the header with the Unix time at which a
+	// notification was sent.
+	WebhookTimestampHeader = "Webhook-Timestamp"
+	// WebhookSignatureHeader is the header with the space separated
+	// "v1,<base64 signature>" signatures of a notification.
+	WebhookSignatureHeader = "Webhook-Signature"
+)
+
+// DefaultWebhookTolerance is the maximum age of a notification accepted by
+// VerifyInteractionNotification, which protects against replayed
+// notifications.
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// ErrInvalidWebhookSignature is returned by VerifyInteractionNotification
+// for notifications that are not signed with the secret of the webhook, or
+// that are too old.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// InteractionWebhook asks the service to notify a URL when a background
+// interaction completes or requires action, instead of polling it with Get.
+type InteractionWebhook struct {
+	// Required. The HTTPS URL that notifications are POSTed to.
+	URL string `json:"url"`
+	// Optional. The secret the notifications are signed with, either raw or
+	// base64 encoded with the "whsec_" prefix. Use the same secret with
+	// VerifyInteractionNotification.
+	Secret string `json:"secret,omitempty"`
+	// Optional. The statuses that are notified, e.g. "completed" and
+	// "requires_action". If empty, every terminal status is notified.
+	Statuses []InteractionStatus `json:"statuses,omitempty"`
+}
+
+// InteractionNotification is the payload of a webhook notification about an
+// interaction.
+type InteractionNotification struct {
+	// The ID of the notification, from the webhook-id header.
+	ID string `json:"-"`
+	// The time at which the notification was sent.
+	Time time.Time `json:"-"`
+	// The ID of the interaction.
+	InteractionID string `json:"interactionId,omitempty"`
+	// The status of the interaction, e.g. "completed" or "requires_action".
+	Status InteractionStatus `json:"status,omitempty"`
+	// Optional. The interaction, including its outputs.
+	Interaction *Interaction `json:"interaction,omitempty"`
+}
+
+// VerifyInteractionNotification verifies the signature of a webhook
+// notification with the secret of the webhook and returns its payload. It
+// returns an error wrapping ErrInvalidWebhookSignature if the signature is
+// invalid or the notification is older than DefaultWebhookTolerance.
+func VerifyInteractionNotification(secret string, header http.Header, body []byte) (*InteractionNotification, error) {
+	return verifyInteractionNotification(secret, header, body, time.Now())
+}
+
+func verifyInteractionNotification(secret string, header http.Header, body []byte, now time.Time) (*InteractionNotification, error) {
+	id := header.Get(WebhookIDHeader)
+	timestamp := header.Get(WebhookTimestampHeader)
+	if id == "" || timestamp == "" {
+		return nil, fmt.Errorf("%w: missing %s or %s header", ErrInvalidWebhookSignature, WebhookIDHeader, WebhookTimestampHeader)
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid timestamp %q", ErrInvalidWebhookSignature, timestamp)
+	}
+	sent := time.Unix(seconds, 0)
+	if age := now.Sub(sent); age > DefaultWebhookTolerance || age < -DefaultWebhookTolerance {
+		return nil, fmt.Errorf("%w: timestamp %v is outside the tolerance of %v", ErrInvalidWebhookSignature, sent, DefaultWebhookTolerance)
+	}
+	key, err := webhookKey(secret)
+	if err != nil {
+		return nil, err
+	}
+	want := webhookSignature(key, id, timestamp, body)
+	valid := false
+	for _, signature := range strings.Fields(header.Get(WebhookSignatureHeader)) {
+		version, sig, _ := strings.Cut(signature, ",")
+		if version == "v1" && hmac.Equal([]byte(sig), []byte(want)) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, ErrInvalidWebhookSignature
+	}
+	notification := new(InteractionNotification)
+	if err := json.Unmarshal(body, notification); err != nil {
+		return nil, fmt.Errorf("invalid notification payload: %w", err)
+	}
+	notification.ID, notification.Time = id, sent
+	return notification, nil
+}
+
+// NewInteractionWebhookHandler returns an http.Handler that verifies the
+// notifications POSTed to it with secret and calls handle with them, e.g. to
+// serve the URL of an InteractionWebhook. Notifications with an invalid
+// signature are rejected with 401 Unauthorized. If handle returns an error,
+// the handler responds with 500 Internal Server Error so that the
+// notification is delivered again.
+func NewInteractionWebhookHandler(secret string, handle func(ctx context.Context, n *InteractionNotification) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read the notification", http.StatusBadRequest)
+			return
+		}
+		notification, err := VerifyInteractionNotification(secret, r.Header, body)
+		if errors.Is(err, ErrInvalidWebhookSignature) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := handle(r.Context(), notification); err != nil {
+			log.Printf("Warning: failed to handle the notification %s of interaction %s: %v", notification.ID, notification.InteractionID, err)
+			http.Error(w, "failed to handle the notification", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// signWebhook sets the Standard Webhooks headers of a notification with body
+// on header.
+func signWebhook(header http.Header, secret, id string, t time.Time, body []byte) error {
+	key, err := webhookKey(secret)
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	header.Set(WebhookIDHeader, id)
+	header.Set(WebhookTimestampHeader, timestamp)
+	header.Set(WebhookSignatureHeader, "v1,"+webhookSignature(key, id, timestamp, body))
+	return nil
+}
+
+// webhookKey returns the signing key of secret, which is either raw or base64
+// encoded with the "whsec_" prefix.
+func webhookKey(secret string) ([]byte, error) {
+	if secret == "" {
+		return nil, errors.New("a webhook secret is required")
+	}
+	if encoded, ok := strings.CutPrefix(secret, "whsec_"); ok {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook secret: %w", err)
+		}
+		return key, nil
+	}
+	return []byte(secret), nil
+}
+
+func webhookSignature(key []byte, id, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerifyInteractionNotification(t *testing.T) {
+	body := []byte(`{"interactionId": "abc", "status": "completed", "interaction": {"id": "abc", "status": "completed"}}`)
+	now := time.Unix(1760000000, 0)
+	for _, secret := range []string{"raw-secret", "whsec_" + base64.StdEncoding.EncodeToString([]byte("encoded-secret"))} {
+		header := http.Header{}
+		if err := signWebhook(header, secret, "msg_1", now, body); err != nil {
+			t.Fatal(err)
+		}
+		n, err := verifyInteractionNotification(secret, header, body, now.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("verifyInteractionNotification() failed: %v", err)
+		}
+		if n.ID != "msg_1" || n.InteractionID != "abc" || n.Status != "completed" || n.Interaction.ID != "abc" || !n.Time.Equal(now) {
+			t.Errorf("verifyInteractionNotification() = %+v, want the notification of abc", n)
+		}
+	}
+
+	header := http.Header{}
+	if err := signWebhook(header, "raw-secret", "msg_1", now, body); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		secret string
+		body   []byte
+		now    time.Time
+	}{
+		{"WrongSecret", "other-secret", body, now},
+		{"ModifiedBody", "raw-secret", append([]byte(" "), body...), now},
+		{"Expired", "raw-secret", body, now.Add(DefaultWebhookTolerance + time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := verifyInteractionNotification(tt.secret, header, tt.body, tt.now); !errors.Is(err, ErrInvalidWebhookSignature) {
+				t.Errorf("verifyInteractionNotification() = %v, want ErrInvalidWebhookSignature", err)
+			}
+		})
+	}
+}
+
+func TestInteractionWebhookHandler(t *testing.T) {
+	var got []*InteractionNotification
+	handler := NewInteractionWebhookHandler("raw-secret", func(ctx context.Context, n *InteractionNotification) error {
+		got = append(got, n)
+		return nil
+	})
+	body := []byte(`{"interactionId": "abc", "status": "requires_action"}`)
+
+	signed := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	if err := signWebhook(signed.Header, "raw-secret", "msg_1", time.Now(), body); err != nil {
+		t.Fatal(err)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signed)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if len(got) != 1 || got[0].Status != "requires_action" {
+		t.Errorf("handled notifications = %+v, want the requires_action notification", got)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body)))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status of an unsigned notification = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if len(got) != 1 {
+		t.Errorf("an unsigned notification was handled")
+	}
+}
+
+func TestInteractionWebhookValidation(t *testing.T) {
+	interaction := &Interaction{Model: "gemini-2.5-flash", Input: "Hi", Webhook: &InteractionWebhook{}}
+	var verr *ValidationError
+	if err := interaction.Validate(); !errors.As(err, &verr) || len(verr.Violations) != 2 {
+		t.Errorf("Validate() = %v, want violations for background and url", err)
+	}
+	interaction.Background, interaction.Webhook.URL = true, "https://example.com/hook"
+	if err := interaction.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}